@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -29,6 +30,10 @@ type options struct {
 }
 
 func mainErr() error {
+	dataDirectory := flag.String("data-dir", "", "directory in which to persist data (in-memory if empty)")
+	bufferPoolPages := flag.Int("buffer-pool-pages", 0, "store tables in heap files cached by a buffer pool of this many pages (requires -data-dir)")
+	flag.Parse()
+
 	l, err := readline.NewEx(&readline.Config{
 		Prompt:            "gostgres \033[32m❯\033[0m ",
 		HistoryFile:       "/tmp/gostgres.tmp",
@@ -42,7 +47,14 @@ func mainErr() error {
 	log.SetOutput(l.Stderr())
 
 	opts := options{}
-	engine := engine.NewDefaultEngine()
+	engine, err := newEngine(*dataDirectory, *bufferPoolPages)
+	if err != nil {
+		return err
+	}
+	defer engine.Close()
+
+	session := engine.NewSession()
+	defer session.Close()
 	buffer := ""
 
 loop:
@@ -96,7 +108,7 @@ loop:
 			for len(parts) > 0 && parts[0][len(parts[0])-1] == ';' {
 				line := parts[0]
 				parts = parts[1:]
-				if err := handleQuery(engine, opts, line); err != nil {
+				if err := handleQuery(session, opts, line); err != nil {
 					fmt.Printf("error: %s\n", err)
 				}
 			}
@@ -108,7 +120,24 @@ loop:
 	return nil
 }
 
-func handleQuery(engine *engine.Engine, opts options, input string) (err error) {
+func newEngine(dataDirectory string, bufferPoolPages int) (*engine.Engine, error) {
+	if dataDirectory == "" {
+		if bufferPoolPages > 0 {
+			return nil, fmt.Errorf("heap tables require a data directory")
+		}
+
+		return engine.NewDefaultEngine(), nil
+	}
+
+	var options []engine.Option
+	if bufferPoolPages > 0 {
+		options = append(options, engine.WithHeapTables(bufferPoolPages))
+	}
+
+	return engine.OpenDefaultEngine(dataDirectory, options...)
+}
+
+func handleQuery(session *engine.Session, opts options, input string) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("query execution panic: %v\n%s", r, string(debug.Stack()))
@@ -122,7 +151,7 @@ func handleQuery(engine *engine.Engine, opts options, input string) (err error)
 		}
 	}()
 
-	rows, err := engine.QueryRows(protocol.Request{
+	rows, err := session.QueryRows(protocol.Request{
 		Query: input,
 		Debug: opts.debug,
 	})
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+
+	"github.com/efritz/gostgres/internal/execution/engine"
+	"github.com/efritz/gostgres/internal/sample"
+	"github.com/efritz/gostgres/internal/server"
+)
+
+func main() {
+	if err := mainErr(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+type options struct {
+	addr            string
+	dataDirectory   string
+	bufferPoolPages int
+	loadSchema      bool
+	loadData        bool
+}
+
+func mainErr() error {
+	opts := options{}
+	flag.StringVar(&opts.addr, "addr", "localhost:5432", "address to listen on")
+	flag.StringVar(&opts.dataDirectory, "data-dir", "", "directory in which to persist data (in-memory if empty)")
+	flag.IntVar(&opts.bufferPoolPages, "buffer-pool-pages", 0, "store tables in heap files cached by a buffer pool of this many pages (requires -data-dir)")
+	flag.BoolVar(&opts.loadSchema, "load-sample-schema", false, "load the sample schema on startup")
+	flag.BoolVar(&opts.loadData, "load-sample", false, "load the sample schema and data on startup")
+	flag.Parse()
+
+	engine, err := newEngine(opts.dataDirectory, opts.bufferPoolPages)
+	if err != nil {
+		return err
+	}
+	defer engine.Close()
+
+	if opts.loadData {
+		if err := sample.LoadPagilaSampleSchemaAndData(engine); err != nil {
+			return err
+		}
+	} else if opts.loadSchema {
+		if err := sample.LoadPagilaSampleSchema(engine); err != nil {
+			return err
+		}
+	}
+
+	listener, err := net.Listen("tcp", opts.addr)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	log.Printf("listening on %s", listener.Addr())
+	return server.NewServer(engine).Serve(listener)
+}
+
+func newEngine(dataDirectory string, bufferPoolPages int) (*engine.Engine, error) {
+	if dataDirectory == "" {
+		if bufferPoolPages > 0 {
+			return nil, fmt.Errorf("heap tables require a data directory")
+		}
+
+		return engine.NewDefaultEngine(), nil
+	}
+
+	var options []engine.Option
+	if bufferPoolPages > 0 {
+		options = append(options, engine.WithHeapTables(bufferPoolPages))
+	}
+
+	return engine.OpenDefaultEngine(dataDirectory, options...)
+}
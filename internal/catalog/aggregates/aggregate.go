@@ -7,8 +7,9 @@ import (
 
 type aggregateImpl struct {
 	impls.Callable
-	step stepFunc
-	done doneFunc
+	strict bool
+	step   stepFunc
+	done   doneFunc
 }
 
 type stepFunc func(ctx impls.ExecutionContext, state any, args []any) (any, error)
@@ -30,6 +31,24 @@ func newAggregateImpl(
 	}
 }
 
+// newStrictAggregateImpl creates an aggregate that ignores rows with a NULL argument.
+// The step function still receives such rows, and must skip them itself.
+func newStrictAggregateImpl(
+	name string,
+	paramTypes []types.Type,
+	returnType types.Type,
+	step stepFunc,
+	done doneFunc,
+) impls.Aggregate {
+	a := newAggregateImpl(name, paramTypes, returnType, step, done).(aggregateImpl)
+	a.strict = true
+	return a
+}
+
+func (a aggregateImpl) Strict() bool {
+	return a.strict
+}
+
 func (a aggregateImpl) Step(ctx impls.ExecutionContext, state any, args []any) (any, error) {
 	refinedArgs, err := a.Callable.RefineArgValues(args)
 	if err != nil {
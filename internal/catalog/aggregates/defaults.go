@@ -16,6 +16,8 @@ func DefaultAggregates() map[string]impls.Aggregate {
 		sum,
 		min,
 		max,
+		arrayAgg,
+		jsonbAgg,
 	} {
 		m[a.Name()] = a
 	}
@@ -23,7 +25,7 @@ func DefaultAggregates() map[string]impls.Aggregate {
 	return m
 }
 
-var count = newAggregateImpl(
+var count = newStrictAggregateImpl(
 	"count",
 	[]types.Type{types.TypeAny},
 	types.TypeBigInteger,
@@ -57,11 +59,15 @@ var count = newAggregateImpl(
 	},
 )
 
-var sum = newAggregateImpl(
+var sum = newStrictAggregateImpl(
 	"sum",
 	[]types.Type{types.TypeBigInteger},
 	types.TypeInteger,
 	func(ctx impls.ExecutionContext, state any, args []any) (any, error) {
+		if args[0] == nil {
+			return state, nil
+		}
+
 		if state == nil {
 			return args[0], nil
 		}
@@ -94,12 +100,16 @@ var sum = newAggregateImpl(
 	},
 )
 
-var min = newAggregateImpl(
+var min = newStrictAggregateImpl(
 	"min",
 	[]types.Type{types.TypeAny},
 	types.TypeAny,
 	func(ctx impls.ExecutionContext, state any, args []any) (any, error) {
 		// TODO - test OrderTypeIncomparable
+		if args[0] == nil {
+			return state, nil
+		}
+
 		if state == nil || ordering.CompareValues(args[0], state) == ordering.OrderTypeBefore {
 			state = args[0]
 		}
@@ -111,12 +121,16 @@ var min = newAggregateImpl(
 	},
 )
 
-var max = newAggregateImpl(
+var max = newStrictAggregateImpl(
 	"max",
 	[]types.Type{types.TypeAny},
 	types.TypeAny,
 	func(ctx impls.ExecutionContext, state any, args []any) (any, error) {
 		// TODO - test OrderTypeIncomparable
+		if args[0] == nil {
+			return state, nil
+		}
+
 		if state == nil || ordering.CompareValues(args[0], state) == ordering.OrderTypeAfter {
 			state = args[0]
 		}
@@ -128,6 +142,69 @@ var max = newAggregateImpl(
 	},
 )
 
+var arrayAgg = newAggregateImpl(
+	"array_agg",
+	[]types.Type{types.TypeAny},
+	types.NewArrayType(types.TypeAny),
+	func(ctx impls.ExecutionContext, state any, args []any) (any, error) {
+		switch acc := state.(type) {
+		case nil:
+			return []any{args[0]}, nil
+		case []any:
+			// Arrays returned for earlier rows share the accumulated elements, so
+			// elements are only ever appended
+			return append(acc, args[0]), nil
+		}
+
+		panic("invalid state for array_agg")
+	},
+	func(ctx impls.ExecutionContext, state any) (any, error) {
+		elements, ok := state.([]any)
+		if !ok {
+			return nil, nil
+		}
+
+		elementType := types.TypeAny
+		for _, element := range elements {
+			if element != nil {
+				elementType = types.TypeKindFromValue(element)
+				break
+			}
+		}
+
+		return types.NewArray(elementType, elements), nil
+	},
+)
+
+var jsonbAgg = newAggregateImpl(
+	"jsonb_agg",
+	[]types.Type{types.TypeAny},
+	types.TypeJSONB,
+	func(ctx impls.ExecutionContext, state any, args []any) (any, error) {
+		switch acc := state.(type) {
+		case nil:
+			return []types.JSONB{types.ToJSONB(args[0])}, nil
+		case []types.JSONB:
+			return append(acc, types.ToJSONB(args[0])), nil
+		}
+
+		panic("invalid state for jsonb_agg")
+	},
+	func(ctx impls.ExecutionContext, state any) (any, error) {
+		values, ok := state.([]types.JSONB)
+		if !ok {
+			return nil, nil
+		}
+
+		elements := make([]any, 0, len(values))
+		for _, value := range values {
+			elements = append(elements, value.Value())
+		}
+
+		return types.NewJSONB(elements), nil
+	},
+)
+
 func addNumbers[T constraints.Integer | constraints.Float](a, b T) (T, error) {
 	return a + b, nil
 }
@@ -1,6 +1,18 @@
 package catalog
 
+import (
+	"sort"
+	"sync"
+)
+
+// Catalog is a named collection of entries that may be read and modified by
+// concurrent sessions. Changes are not transactional: an entry set within a
+// transaction is visible to other sessions immediately, and is reverted by the
+// transaction's rollback. Callers making changes that other sessions should not
+// observe before commit must hold a lock that excludes them (e.g., the exclusive
+// lock CREATE TABLE takes on the table it creates).
 type Catalog[T any] struct {
+	mu      sync.RWMutex
 	entries map[string]T
 }
 
@@ -18,11 +30,50 @@ func NewCatalogWithEntries[T any](entries map[string]T) *Catalog[T] {
 	}
 }
 
-func (c Catalog[T]) Get(name string) (T, bool) {
+func (c *Catalog[T]) Get(name string) (T, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	entry, ok := c.entries[name]
 	return entry, ok
 }
 
-func (c Catalog[T]) Set(name string, entry T) {
+func (c *Catalog[T]) Set(name string, entry T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.entries[name] = entry
 }
+
+func (c *Catalog[T]) Delete(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[name]; !ok {
+		return false
+	}
+
+	delete(c.entries, name)
+	return true
+}
+
+func (c *Catalog[T]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+}
+
+// Names returns the names of all entries, in sorted order.
+func (c *Catalog[T]) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.entries))
+	for name := range c.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/ordering"
 	"github.com/efritz/gostgres/internal/shared/types"
 )
 
@@ -16,6 +17,9 @@ func DefaultFunctions() map[string]impls.Function {
 		currval,
 		nextval,
 		setval,
+		arrayLength,
+		arrayPosition,
+		jsonbBuildObject,
 	} {
 		m[f.Name()] = f
 	}
@@ -68,7 +72,7 @@ var nextval = newFunctionImpl(
 			return nil, fmt.Errorf("sequence %s does not exist", name)
 		}
 
-		return sequence.Next()
+		return sequence.Next(ctx)
 	},
 )
 
@@ -84,6 +88,36 @@ var setval = newFunctionImpl(
 		}
 
 		value := args[1].(int64)
-		return nil, sequence.Set(value)
+		return nil, sequence.Set(ctx, value)
+	},
+)
+
+var arrayLength = newFunctionImpl(
+	"array_length",
+	[]types.Type{types.NewArrayType(types.TypeAny), types.TypeInteger},
+	types.TypeInteger,
+	func(ctx impls.ExecutionContext, args []any) (any, error) {
+		array := args[0].(types.Array)
+		if dimension := args[1].(int32); dimension != 1 || array.Len() == 0 {
+			return nil, nil
+		}
+
+		return int32(array.Len()), nil
+	},
+)
+
+var arrayPosition = newFunctionImpl(
+	"array_position",
+	[]types.Type{types.NewArrayType(types.TypeAny), types.TypeAny},
+	types.TypeInteger,
+	func(ctx impls.ExecutionContext, args []any) (any, error) {
+		array := args[0].(types.Array)
+		for i, element := range array.Elements() {
+			if element != nil && ordering.CompareValues(element, args[1]) == ordering.OrderTypeEqual {
+				return int32(i + 1), nil
+			}
+		}
+
+		return nil, nil
 	},
 )
@@ -30,5 +30,12 @@ func (f functionImpl) Invoke(ctx impls.ExecutionContext, args []any) (any, error
 		return nil, err
 	}
 
+	// Functions are strict: their value is NULL when any argument is NULL
+	for _, arg := range refinedArgs {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+
 	return f.invoke(ctx, refinedArgs)
 }
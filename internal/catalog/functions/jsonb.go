@@ -0,0 +1,49 @@
+package functions
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+// jsonbBuildObject builds a jsonb object from alternating keys and values. Unlike other
+// functions, it accepts any (even) number of arguments and NULL values.
+var jsonbBuildObject impls.Function = jsonbBuildObjectFunction{}
+
+type jsonbBuildObjectFunction struct{}
+
+func (jsonbBuildObjectFunction) Name() string             { return "jsonb_build_object" }
+func (jsonbBuildObjectFunction) ParamTypes() []types.Type { return nil }
+func (jsonbBuildObjectFunction) ReturnType() types.Type   { return types.TypeJSONB }
+
+func (f jsonbBuildObjectFunction) ValidateArgTypes(argTypes []types.Type) error {
+	if len(argTypes)%2 != 0 {
+		return fmt.Errorf("%s expects an even number of arguments, got %d", f.Name(), len(argTypes))
+	}
+
+	for i := 0; i < len(argTypes); i += 2 {
+		if argTypes[i].IsArray() || argTypes[i] == types.TypeJSONB {
+			return fmt.Errorf("argument %d to %s must be a scalar key, got %s", i+1, f.Name(), argTypes[i])
+		}
+	}
+
+	return nil
+}
+
+func (f jsonbBuildObjectFunction) RefineArgValues(args []any) ([]any, error) {
+	return args, nil
+}
+
+func (f jsonbBuildObjectFunction) Invoke(ctx impls.ExecutionContext, args []any) (any, error) {
+	object := make(map[string]any, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		if args[i] == nil {
+			return nil, fmt.Errorf("argument %d to %s must not be NULL", i+1, f.Name())
+		}
+
+		object[types.FormatText(args[i])] = types.ToJSONB(args[i+1]).Value()
+	}
+
+	return types.NewJSONB(object), nil
+}
@@ -1,11 +1,15 @@
 package sequence
 
 import (
+	"sync"
+
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/efritz/gostgres/internal/storage/wal"
 )
 
 type sequence struct {
+	mu    sync.Mutex
 	name  string
 	typ   types.Type // TODO - actually store as this type
 	value int64
@@ -22,16 +26,30 @@ func (s *sequence) Name() string {
 	return s.name
 }
 
-func (s *sequence) Next() (int64, error) {
+// Next advances the sequence. Sequences are not transactional: a value obtained by a
+// transaction that rolls back is not returned again. Only the values obtained by
+// committed transactions are logged, so such values may be reused after recovery.
+func (s *sequence) Next(ctx impls.ExecutionContext) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.value++
+	ctx.RecordChange(wal.NewSequenceAdvanceRecord(s.name, s.value))
 	return s.value, nil
 }
 
-func (s *sequence) Set(value int64) error {
+func (s *sequence) Set(ctx impls.ExecutionContext, value int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.value = value
+	ctx.RecordChange(wal.NewSequenceSetRecord(s.name, value))
 	return nil
 }
 
 func (s *sequence) Value() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	return s.value
 }
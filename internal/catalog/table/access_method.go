@@ -0,0 +1,106 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/storage/buffer"
+	"github.com/efritz/gostgres/internal/storage/heap"
+)
+
+type memoryAccessMethod struct{}
+
+var _ impls.TableAccessMethod = memoryAccessMethod{}
+
+// NewMemoryAccessMethod returns an access method creating tables whose rows are
+// stored in memory.
+func NewMemoryAccessMethod() impls.TableAccessMethod {
+	return memoryAccessMethod{}
+}
+
+func (memoryAccessMethod) Name() string {
+	return "memory"
+}
+
+func (memoryAccessMethod) NewTable(ctx impls.ExecutionContext, name string, fields []impls.TableField) (impls.Table, error) {
+	return NewTable(name, fields), nil
+}
+
+//
+//
+
+// heapAccessMethod creates tables whose rows are stored in heap files within a
+// directory. The pages of all heap files are cached by a shared buffer pool.
+type heapAccessMethod struct {
+	pool      *buffer.Pool
+	directory string
+
+	mu     sync.Mutex
+	nextID int
+	heaps  map[*heap.Heap]struct{}
+}
+
+var _ impls.TableAccessMethod = &heapAccessMethod{}
+
+func NewHeapAccessMethod(pool *buffer.Pool, directory string) *heapAccessMethod {
+	return &heapAccessMethod{
+		pool:      pool,
+		directory: directory,
+		heaps:     map[*heap.Heap]struct{}{},
+	}
+}
+
+func (m *heapAccessMethod) Name() string {
+	return "heap"
+}
+
+func (m *heapAccessMethod) NewTable(ctx impls.ExecutionContext, name string, fields []impls.TableField) (impls.Table, error) {
+	m.mu.Lock()
+	m.nextID++
+	path := filepath.Join(m.directory, fmt.Sprintf("%d.%s", m.nextID, name))
+	m.mu.Unlock()
+
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("heap file %s already exists", path)
+	}
+
+	h, err := heap.Open(m.pool, path)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.heaps[h] = struct{}{}
+	m.mu.Unlock()
+
+	ctx.OnRollback(func() {
+		m.mu.Lock()
+		delete(m.heaps, h)
+		m.mu.Unlock()
+
+		_ = h.Close()
+		_ = os.Remove(path)
+	})
+
+	return NewHeapTable(name, fields, h), nil
+}
+
+// Close closes the heap files of all tables created by this access method.
+func (m *heapAccessMethod) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errs []error
+	for h := range m.heaps {
+		if err := h.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	clear(m.heaps)
+
+	return errors.Join(errs...)
+}
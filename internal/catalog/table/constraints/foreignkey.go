@@ -12,15 +12,17 @@ import (
 type foreignKeyConstraint struct {
 	name        string
 	expressions []impls.Expression
+	refTable    impls.Table
 	refIndex    impls.Index[indexes.BtreeIndexScanOptions]
 }
 
 var _ impls.Constraint = &foreignKeyConstraint{}
 
-func NewForeignKeyConstraint(name string, expressions []impls.Expression, refIndex impls.Index[indexes.BtreeIndexScanOptions]) impls.Constraint {
+func NewForeignKeyConstraint(name string, expressions []impls.Expression, refTable impls.Table, refIndex impls.Index[indexes.BtreeIndexScanOptions]) impls.Constraint {
 	return &foreignKeyConstraint{
 		name:        name,
 		expressions: expressions,
+		refTable:    refTable,
 		refIndex:    refIndex,
 	}
 }
@@ -50,13 +52,25 @@ func (c *foreignKeyConstraint) Check(ctx impls.ExecutionContext, row rows.Row) e
 		return err
 	}
 
-	if _, err := scanner.Scan(); err != nil {
-		if err == scan.ErrNoRows {
-			return fmt.Errorf("foreign key constraint %q failed", c.name)
+	for {
+		tid, err := scanner.Scan()
+		if err != nil {
+			if err == scan.ErrNoRows {
+				return fmt.Errorf("foreign key constraint %q failed", c.name)
+			}
+
+			return err
 		}
 
-		return err
-	}
+		// The index may reference row versions not visible to this transaction
+		refRow, ok, err := c.refTable.Row(ctx, tid)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		if ok {
+			// Prevent the referenced row from being deleted before this transaction ends
+			return c.refTable.LockRow(ctx, refRow, impls.ForShareLock)
+		}
+	}
 }
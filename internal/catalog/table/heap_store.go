@@ -0,0 +1,181 @@
+package table
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/storage/encoding"
+	"github.com/efritz/gostgres/internal/storage/heap"
+)
+
+// heapStore holds row versions in a heap file. Each version is encoded as a fixed-size
+// header holding its transaction and command identifiers followed by its values. The
+// header is rewritten in place when the version is deleted.
+type heapStore struct {
+	name   string
+	heap   *heap.Heap
+	newRow newRowFunc
+	count  int
+}
+
+var _ tupleStore = &heapStore{}
+
+// tupleHeaderSize is the size of the encoded xmin, cmin, xmax, and cmax of a version.
+const tupleHeaderSize = 24
+
+func newHeapStore(name string, heap *heap.Heap, newRow newRowFunc) *heapStore {
+	return &heapStore{
+		name:   name,
+		heap:   heap,
+		newRow: newRow,
+	}
+}
+
+func (s *heapStore) insert(values []any, xmin int64, cmin int) (tuple, error) {
+	data, err := encodeTuple(values, xmin, cmin)
+	if err != nil {
+		return tuple{}, err
+	}
+
+	tid, err := s.heap.Insert(data)
+	if err != nil {
+		return tuple{}, s.wrap(err)
+	}
+
+	row, err := s.newRow(tid, values)
+	if err != nil {
+		return tuple{}, errors.Join(err, s.wrap(s.heap.Delete(tid)))
+	}
+
+	s.count++
+	return tuple{tid: tid, row: row, xmin: xmin, cmin: cmin}, nil
+}
+
+func (s *heapStore) restore(tid int64, values []any) (tuple, error) {
+	row, err := s.newRow(tid, values)
+	if err != nil {
+		return tuple{}, err
+	}
+
+	if _, ok, err := s.get(tid); err != nil {
+		return tuple{}, err
+	} else if ok {
+		return tuple{}, errDuplicateTID(tid)
+	}
+
+	data, err := encodeTuple(values, 0, 0)
+	if err != nil {
+		return tuple{}, err
+	}
+
+	if err := s.heap.InsertAt(tid, data); err != nil {
+		return tuple{}, s.wrap(err)
+	}
+
+	s.count++
+	return tuple{tid: tid, row: row}, nil
+}
+
+func (s *heapStore) get(tid int64) (tuple, bool, error) {
+	data, ok, err := s.heap.Read(tid)
+	if err != nil || !ok {
+		return tuple{}, false, s.wrap(err)
+	}
+
+	tuple, err := s.decode(tid, data)
+	if err != nil {
+		return tuple, false, s.wrap(err)
+	}
+
+	return tuple, true, nil
+}
+
+func (s *heapStore) setDeleted(tid int64, xmax int64, cmax int) error {
+	_, err := s.heap.Update(tid, func(data []byte) {
+		binary.BigEndian.PutUint64(data[12:], uint64(xmax))
+		binary.BigEndian.PutUint32(data[20:], uint32(cmax))
+	})
+
+	return s.wrap(err)
+}
+
+func (s *heapStore) remove(tid int64) error {
+	if err := s.heap.Delete(tid); err != nil {
+		return s.wrap(err)
+	}
+
+	s.count--
+	return nil
+}
+
+func (s *heapStore) forEach(f func(tuple tuple) error) error {
+	var callbackErr error
+	err := s.heap.Scan(func(tid int64, data []byte) error {
+		tuple, err := s.decode(tid, data)
+		if err != nil {
+			return err
+		}
+
+		callbackErr = f(tuple)
+		return callbackErr
+	})
+	if callbackErr != nil {
+		return callbackErr
+	}
+
+	return s.wrap(err)
+}
+
+func (s *heapStore) size() int {
+	return s.count
+}
+
+func encodeTuple(values []any, xmin int64, cmin int) ([]byte, error) {
+	buf := make([]byte, tupleHeaderSize)
+	binary.BigEndian.PutUint64(buf, uint64(xmin))
+	binary.BigEndian.PutUint32(buf[8:], uint32(cmin))
+
+	return encoding.AppendValues(buf, values)
+}
+
+func (s *heapStore) decode(tid int64, data []byte) (tuple, error) {
+	d := encoding.NewDecoder(data[tupleHeaderSize:])
+	values := d.Values()
+	if err := d.Err(); err != nil {
+		return tuple{}, fmt.Errorf("malformed row %d: %s", tid, err)
+	}
+
+	row, err := s.newRow(tid, values)
+	if err != nil {
+		return tuple{}, err
+	}
+
+	return tuple{
+		tid:  tid,
+		row:  row,
+		xmin: int64(binary.BigEndian.Uint64(data)),
+		cmin: int(binary.BigEndian.Uint32(data[8:])),
+		xmax: int64(binary.BigEndian.Uint64(data[12:])),
+		cmax: int(binary.BigEndian.Uint32(data[20:])),
+	}, nil
+}
+
+// heapStoreError describes a failure to read or write the heap file of a table.
+type heapStoreError struct {
+	name string
+	err  error
+}
+
+func (e heapStoreError) Error() string {
+	return fmt.Sprintf("storage failure in table %q: %s", e.name, e.err)
+}
+
+// wrap identifies the table whose heap file could not be read or written.
+func (s *heapStore) wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return heapStoreError{name: s.name, err: err}
+}
@@ -2,6 +2,7 @@ package indexes
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/efritz/gostgres/internal/execution/expressions"
 	"github.com/efritz/gostgres/internal/shared/fields"
@@ -11,18 +12,16 @@ import (
 )
 
 type btreeIndex struct {
+	latch       sync.RWMutex
 	name        string
 	tableName   string
 	unique      bool
 	expressions []impls.ExpressionWithDirection
 	root        *btreeNode
-}
 
-type btreeNode struct {
-	tid    int64
-	values []any
-	left   *btreeNode // TODO - make a proper BTree (not BST)
-	right  *btreeNode
+	// version is incremented on each modification of the tree. Scanners resume from
+	// their last position within a leaf only if the tree has not since changed.
+	version uint64
 }
 
 type BtreeIndexScanOptions struct {
@@ -64,6 +63,7 @@ func NewBTreeIndex(name, tableName string, unique bool, expressions []impls.Expr
 		tableName:   tableName,
 		unique:      unique,
 		expressions: expressions,
+		root:        &btreeNode{},
 	}
 }
 
@@ -203,44 +203,61 @@ func (i *btreeIndex) Ordering(opts BtreeIndexScanOptions) impls.OrderExpression
 	return expressions.NewOrderExpression(i.expressions)
 }
 
-func (i *btreeIndex) Insert(row rows.Row) error {
+func (i *btreeIndex) Insert(row rows.Row, isLive func(tid int64) (bool, error)) error {
 	tid, values, err := i.extractTIDAndValuesFromRow(row)
 	if err != nil {
 		return err
 	}
 
-	i.root, err = i.root.insert(values, tid, i.unique)
-	return err
-}
+	i.latch.Lock()
+	defer i.latch.Unlock()
 
-func (n *btreeNode) insert(values []any, tid int64, unique bool) (*btreeNode, error) {
-	if n == nil {
-		return &btreeNode{tid: tid, values: values}, nil
+	if i.unique {
+		if conflicts, err := i.conflicts(values, isLive); err != nil {
+			return err
+		} else if conflicts {
+			return fmt.Errorf("unique constraint violation")
+		}
 	}
 
-	switch ordering.CompareValueSlices(values, n.values) {
-	case ordering.OrderTypeEqual:
-		if unique {
-			return nil, fmt.Errorf("unique constraint violation")
+	if separator, right, ok := i.root.insert(btreeKey{values: values, tid: tid}); ok {
+		i.root = &btreeNode{
+			keys:     []btreeKey{separator},
+			children: []*btreeNode{i.root, right},
 		}
-		fallthrough
+	}
 
-	case ordering.OrderTypeBefore:
-		newLeft, err := n.left.insert(values, tid, unique)
-		if err != nil {
-			return nil, err
+	i.version++
+	return nil
+}
+
+// conflicts returns true if the index contains a live entry whose values equal the
+// given values. NULL values are not equal to one another, and never conflict.
+func (i *btreeIndex) conflicts(values []any, isLive func(tid int64) (bool, error)) (bool, error) {
+	leaf, j := i.root.first(func(key btreeKey) bool {
+		return compareIndexValues(key.values, values) < 0
+	})
+
+	for ; leaf != nil; leaf, j = forward(leaf, j+1) {
+		key := leaf.keys[j]
+		if compareIndexValues(key.values, values) != 0 {
+			break
 		}
-		n.left = newLeft
 
-	case ordering.OrderTypeAfter:
-		newRight, err := n.right.insert(values, tid, unique)
-		if err != nil {
-			return nil, err
+		if ordering.CompareValueSlices(key.values, values) != ordering.OrderTypeEqual {
+			continue
+		}
+
+		if isLive == nil {
+			return true, nil
+		}
+
+		if live, err := isLive(key.tid); err != nil || live {
+			return live, err
 		}
-		n.right = newRight
 	}
 
-	return n, nil
+	return false, nil
 }
 
 func (i *btreeIndex) Delete(row rows.Row) error {
@@ -249,39 +266,20 @@ func (i *btreeIndex) Delete(row rows.Row) error {
 		return err
 	}
 
-	i.root = i.root.delete(values, tid)
-	return nil
-}
+	i.latch.Lock()
+	defer i.latch.Unlock()
 
-func (n *btreeNode) delete(values []any, tid int64) *btreeNode {
-	if n == nil {
+	if !i.root.delete(btreeKey{values: values, tid: tid}) {
 		return nil
 	}
 
-	if n.tid == tid {
-		if n.right == nil {
-			return n.left
-		}
-
-		min := n.right
-		for min.left != nil {
-			min = min.left
-		}
-
-		n.values = min.values
-		n.tid = min.tid
-		n.right = n.right.delete(min.values, min.tid)
-		return n
+	if !i.root.isLeaf() && len(i.root.keys) == 0 {
+		// Collapse a root with a single child
+		i.root = i.root.children[0]
 	}
 
-	switch ordering.CompareValueSlices(values, n.values) {
-	case ordering.OrderTypeBefore, ordering.OrderTypeEqual:
-		n.left = n.left.delete(values, tid)
-	case ordering.OrderTypeAfter:
-		n.right = n.right.delete(values, tid)
-	}
-
-	return n
+	i.version++
+	return nil
 }
 
 func (i *btreeIndex) extractTIDAndValuesFromRow(row rows.Row) (int64, []any, error) {
@@ -2,6 +2,7 @@ package indexes
 
 import (
 	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/efritz/gostgres/internal/execution/expressions"
@@ -40,7 +41,7 @@ func TestBTreeIndex(t *testing.T) {
 	})
 
 	for _, row := range allRows {
-		require.NoError(t, index.Insert(row))
+		require.NoError(t, index.Insert(row, nil))
 	}
 
 	ids := func(opts BtreeIndexScanOptions) []int32 {
@@ -138,3 +139,245 @@ func TestBTreeIndex(t *testing.T) {
 		assert.Equal(t, expectedIDs, ids(opts))
 	})
 }
+
+func TestBTreeIndexBalanced(t *testing.T) {
+	tid := fields.NewField("scores", "tid", types.TypeBigInteger, fields.InternalFieldTid)
+	score := fields.NewField("scores", "score", types.TypeInteger, fields.NonInternalField)
+	fields := []fields.Field{tid, score}
+
+	// Keys are inserted in order, with three rows per score
+	var allRows []rows.Row
+	for i := 1; i <= 30000; i++ {
+		row, err := rows.NewRow(fields, []any{int64(i), int32((i + 2) / 3)})
+		require.NoError(t, err)
+		allRows = append(allRows, row)
+	}
+
+	index := NewBTreeIndex("scores_score", "scores", false, []impls.ExpressionWithDirection{
+		{Expression: expressions.NewNamed(score)},
+	}).(*btreeIndex)
+
+	for _, row := range allRows {
+		require.NoError(t, index.Insert(row, nil))
+	}
+	assertBTreeInvariants(t, index, len(allRows))
+
+	scores := func(direction ScanDirection, lower, upper int32) []int32 {
+		opts := BtreeIndexScanOptions{
+			scanDirection: direction,
+			lowerBounds:   [][]scanBound{{{expression: expressions.NewConstant(lower), inclusive: true}}},
+			upperBounds:   [][]scanBound{{{expression: expressions.NewConstant(upper), inclusive: false}}},
+		}
+
+		scanner, err := index.Scanner(impls.EmptyExecutionContext, opts)
+		require.NoError(t, err)
+
+		var scores []int32
+		for {
+			tid, err := scanner.Scan()
+			if err == scan.ErrNoRows {
+				break
+			}
+			require.NoError(t, err)
+
+			scores = append(scores, allRows[tid-1].Values[1].(int32))
+		}
+
+		return scores
+	}
+
+	t.Run("forward scan with duplicates", func(t *testing.T) {
+		assert.Equal(t, []int32{4999, 4999, 4999, 5000, 5000, 5000}, scores(ScanDirectionForward, 4999, 5001))
+	})
+
+	t.Run("backward scan with duplicates", func(t *testing.T) {
+		assert.Equal(t, []int32{5000, 5000, 5000, 4999, 4999, 4999}, scores(ScanDirectionBackward, 4999, 5001))
+	})
+
+	t.Run("empty range", func(t *testing.T) {
+		assert.Empty(t, scores(ScanDirectionForward, 20000, 30000))
+		assert.Empty(t, scores(ScanDirectionBackward, 20000, 30000))
+	})
+
+	t.Run("unique violation", func(t *testing.T) {
+		unique := NewBTreeIndex("scores_score_key", "scores", true, []impls.ExpressionWithDirection{
+			{Expression: expressions.NewNamed(score)},
+		})
+		require.NoError(t, unique.Insert(allRows[0], nil))
+		require.NoError(t, unique.Insert(allRows[3], nil))
+		require.Error(t, unique.Insert(allRows[1], nil))
+
+		// Entries of dead row versions do not conflict
+		require.NoError(t, unique.Insert(allRows[2], func(tid int64) (bool, error) { return false, nil }))
+	})
+
+	t.Run("mark and restore", func(t *testing.T) {
+		for _, direction := range []ScanDirection{ScanDirectionForward, ScanDirectionBackward} {
+			scanner, err := index.Scanner(impls.EmptyExecutionContext, BtreeIndexScanOptions{scanDirection: direction})
+			require.NoError(t, err)
+			markRestorer := scanner.(scan.MarkRestorer)
+
+			scanN := func(n int) (tids []int64) {
+				for j := 0; j < n; j++ {
+					tid, err := scanner.Scan()
+					require.NoError(t, err)
+					tids = append(tids, tid)
+				}
+
+				return tids
+			}
+
+			marked := scanN(100)[99]
+			markRestorer.Mark()
+			expected := append([]int64{marked}, scanN(200)...)
+
+			// Restore after the leaves have been restructured
+			for _, row := range allRows[:500] {
+				require.NoError(t, index.Delete(row))
+				require.NoError(t, index.Insert(row, nil))
+			}
+			markRestorer.Restore()
+			assert.Equal(t, expected, scanN(201))
+		}
+	})
+
+	t.Run("scan during modification", func(t *testing.T) {
+		scanner, err := index.Scanner(impls.EmptyExecutionContext, BtreeIndexScanOptions{scanDirection: ScanDirectionForward})
+		require.NoError(t, err)
+
+		var tids []int64
+		for {
+			tid, err := scanner.Scan()
+			if err == scan.ErrNoRows {
+				break
+			}
+			require.NoError(t, err)
+			tids = append(tids, tid)
+
+			// Restructure the leaves around the scanner's position
+			if tid%100 == 0 && tid+150 <= int64(len(allRows)) {
+				for _, row := range allRows[tid : tid+150] {
+					require.NoError(t, index.Delete(row))
+				}
+				for _, row := range allRows[tid : tid+150] {
+					require.NoError(t, index.Insert(row, nil))
+				}
+			}
+		}
+
+		require.Len(t, tids, len(allRows))
+		for i, tid := range tids {
+			assert.Equal(t, int64(i+1), tid)
+		}
+	})
+
+	t.Run("delete with rebalancing", func(t *testing.T) {
+		rand.New(rand.NewSource(0)).Shuffle(len(allRows), func(i, j int) {
+			allRows[i], allRows[j] = allRows[j], allRows[i]
+		})
+
+		for i, row := range allRows {
+			require.NoError(t, index.Delete(row))
+
+			if i%5000 == 0 {
+				assertBTreeInvariants(t, index, len(allRows)-i-1)
+			}
+		}
+		assertBTreeInvariants(t, index, 0)
+	})
+}
+
+// assertBTreeInvariants asserts that the index holds the given number of entries in
+// order, that all leaves are at the same depth, and that each node other than the
+// root holds between the minimum and maximum number of keys.
+func assertBTreeInvariants(t *testing.T, index *btreeIndex, expectedSize int) {
+	leafDepth := -1
+	var leaves []*btreeNode
+
+	var visit func(n *btreeNode, depth int, lower, upper *btreeKey)
+	visit = func(n *btreeNode, depth int, lower, upper *btreeKey) {
+		if n != index.root {
+			require.GreaterOrEqual(t, len(n.keys), btreeMinKeys)
+		}
+		require.LessOrEqual(t, len(n.keys), btreeMaxKeys)
+
+		for _, key := range n.keys {
+			if lower != nil {
+				require.GreaterOrEqual(t, compareKeys(key, *lower), 0)
+			}
+			if upper != nil {
+				require.Less(t, compareKeys(key, *upper), 0)
+			}
+		}
+
+		if n.isLeaf() {
+			if leafDepth < 0 {
+				leafDepth = depth
+			}
+			require.Equal(t, leafDepth, depth)
+			leaves = append(leaves, n)
+			return
+		}
+
+		require.Len(t, n.children, len(n.keys)+1)
+		for i, child := range n.children {
+			childLower, childUpper := lower, upper
+			if i > 0 {
+				childLower = &n.keys[i-1]
+			}
+			if i < len(n.keys) {
+				childUpper = &n.keys[i]
+			}
+
+			visit(child, depth+1, childLower, childUpper)
+		}
+	}
+	visit(index.root, 0, nil, nil)
+
+	size := 0
+	for i, leaf := range leaves {
+		if i > 0 {
+			require.Same(t, leaves[i-1], leaf.prev)
+			require.Same(t, leaf, leaves[i-1].next)
+		}
+		for j := 1; j < len(leaf.keys); j++ {
+			require.Less(t, compareKeys(leaf.keys[j-1], leaf.keys[j]), 0)
+		}
+
+		size += len(leaf.keys)
+	}
+	require.Nil(t, leaves[0].prev)
+	require.Nil(t, leaves[len(leaves)-1].next)
+	require.Equal(t, expectedSize, size)
+}
+
+func BenchmarkBTreeIndexSequentialInsert(b *testing.B) {
+	tid := fields.NewField("t", "tid", types.TypeBigInteger, fields.InternalFieldTid)
+	id := fields.NewField("t", "id", types.TypeBigInteger, fields.NonInternalField)
+	fields := []fields.Field{tid, id}
+
+	for _, n := range []int{10000, 100000, 1000000} {
+		allRows := make([]rows.Row, 0, n)
+		for i := 1; i <= n; i++ {
+			row, err := rows.NewRow(fields, []any{int64(i), int64(i)})
+			require.NoError(b, err)
+			allRows = append(allRows, row)
+		}
+
+		b.Run(fmt.Sprintf("%d keys", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				index := NewBTreeIndex("t_id", "t", true, []impls.ExpressionWithDirection{
+					{Expression: expressions.NewNamed(id)},
+				})
+
+				for _, row := range allRows {
+					if err := index.Insert(row, nil); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/key")
+		})
+	}
+}
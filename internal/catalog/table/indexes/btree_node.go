@@ -0,0 +1,271 @@
+package indexes
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+
+	"github.com/efritz/gostgres/internal/shared/ordering"
+)
+
+const (
+	// btreeMaxKeys is the maximum number of keys held by a node. A node that would
+	// exceed it is split in two.
+	btreeMaxKeys = 64
+
+	// btreeMinKeys is the minimum number of keys held by a node other than the root.
+	// A node that falls below it borrows keys from or is merged with a sibling.
+	btreeMinKeys = btreeMaxKeys / 2
+)
+
+// btreeKey is an entry of a B+tree index. Entries are ordered by their indexed
+// values, then by TID, so that duplicate values have a stable position and each
+// entry can be located precisely on deletion.
+type btreeKey struct {
+	values []any
+	tid    int64
+}
+
+// btreeNode is a node of a B+tree. Leaves hold the entries of the index and are
+// linked to their neighbors so that ranges can be scanned in either direction.
+// Internal nodes hold separators: each key of children[i] is less than keys[i],
+// and each key of children[i+1] is at least keys[i].
+type btreeNode struct {
+	keys     []btreeKey
+	children []*btreeNode // nil for leaves
+	prev     *btreeNode   // previous leaf
+	next     *btreeNode   // next leaf
+}
+
+func (n *btreeNode) isLeaf() bool {
+	return n.children == nil
+}
+
+// insert adds the given key to the subtree rooted at this node. If the node
+// overflows, it is split and the separator and new right sibling are returned.
+func (n *btreeNode) insert(key btreeKey) (btreeKey, *btreeNode, bool) {
+	i := n.search(key)
+
+	if n.isLeaf() {
+		n.keys = slices.Insert(n.keys, i, key)
+	} else if separator, right, ok := n.children[i].insert(key); ok {
+		n.keys = slices.Insert(n.keys, i, separator)
+		n.children = slices.Insert(n.children, i+1, right)
+	}
+
+	if len(n.keys) <= btreeMaxKeys {
+		return btreeKey{}, nil, false
+	}
+
+	return n.split()
+}
+
+func (n *btreeNode) split() (btreeKey, *btreeNode, bool) {
+	mid := len(n.keys) / 2
+
+	if n.isLeaf() {
+		right := &btreeNode{
+			keys: slices.Clone(n.keys[mid:]),
+			prev: n,
+			next: n.next,
+		}
+		if n.next != nil {
+			n.next.prev = right
+		}
+		n.next = right
+		n.keys = slices.Clip(n.keys[:mid])
+
+		return right.keys[0], right, true
+	}
+
+	separator := n.keys[mid]
+	right := &btreeNode{
+		keys:     slices.Clone(n.keys[mid+1:]),
+		children: slices.Clone(n.children[mid+1:]),
+	}
+	n.keys = slices.Clip(n.keys[:mid])
+	n.children = slices.Clip(n.children[:mid+1])
+
+	return separator, right, true
+}
+
+// delete removes the given key from the subtree rooted at this node, rebalancing
+// any child that underflows as a result. Returns false if the key is not present.
+func (n *btreeNode) delete(key btreeKey) bool {
+	if n.isLeaf() {
+		i, ok := slices.BinarySearchFunc(n.keys, key, compareKeys)
+		if !ok {
+			return false
+		}
+
+		n.keys = slices.Delete(n.keys, i, i+1)
+		return true
+	}
+
+	i := n.search(key)
+	if !n.children[i].delete(key) {
+		return false
+	}
+
+	if len(n.children[i].keys) < btreeMinKeys {
+		n.rebalance(i)
+	}
+
+	return true
+}
+
+// rebalance restores the minimum size of the child at the given index by moving
+// a key from an adjacent sibling or, if both siblings are minimal, by merging the
+// child with one of them.
+func (n *btreeNode) rebalance(i int) {
+	switch {
+	case i > 0 && len(n.children[i-1].keys) > btreeMinKeys:
+		n.borrowFromLeft(i)
+	case i < len(n.children)-1 && len(n.children[i+1].keys) > btreeMinKeys:
+		n.borrowFromRight(i)
+	case i > 0:
+		n.merge(i - 1)
+	case i < len(n.children)-1:
+		n.merge(i)
+	}
+}
+
+func (n *btreeNode) borrowFromLeft(i int) {
+	left, child := n.children[i-1], n.children[i]
+	last := len(left.keys) - 1
+
+	if child.isLeaf() {
+		child.keys = slices.Insert(child.keys, 0, left.keys[last])
+		n.keys[i-1] = child.keys[0]
+	} else {
+		child.keys = slices.Insert(child.keys, 0, n.keys[i-1])
+		child.children = slices.Insert(child.children, 0, left.children[last+1])
+		n.keys[i-1] = left.keys[last]
+		left.children = left.children[:last+1]
+	}
+
+	left.keys = left.keys[:last]
+}
+
+func (n *btreeNode) borrowFromRight(i int) {
+	child, right := n.children[i], n.children[i+1]
+
+	if child.isLeaf() {
+		child.keys = append(child.keys, right.keys[0])
+		right.keys = slices.Delete(right.keys, 0, 1)
+		n.keys[i] = right.keys[0]
+		return
+	}
+
+	child.keys = append(child.keys, n.keys[i])
+	child.children = append(child.children, right.children[0])
+	n.keys[i] = right.keys[0]
+	right.keys = slices.Delete(right.keys, 0, 1)
+	right.children = slices.Delete(right.children, 0, 1)
+}
+
+// merge combines the child at the given index with its right sibling.
+func (n *btreeNode) merge(i int) {
+	left, right := n.children[i], n.children[i+1]
+
+	if left.isLeaf() {
+		left.keys = append(left.keys, right.keys...)
+		left.next = right.next
+		if right.next != nil {
+			right.next.prev = left
+		}
+	} else {
+		left.keys = append(append(left.keys, n.keys[i]), right.keys...)
+		left.children = append(left.children, right.children...)
+	}
+
+	n.keys = slices.Delete(n.keys, i, i+1)
+	n.children = slices.Delete(n.children, i+1, i+2)
+}
+
+// search returns the number of keys of this node that are less than or equal to
+// the given key: the insertion point within a leaf, or the index of the child whose
+// subtree would contain the key.
+func (n *btreeNode) search(key btreeKey) int {
+	return sort.Search(len(n.keys), func(i int) bool {
+		return compareKeys(n.keys[i], key) > 0
+	})
+}
+
+// first returns the position of the first entry in the subtree rooted at this node
+// for which before returns false. The function must hold for a (possibly empty)
+// prefix of the entries in key order. A nil leaf is returned if there is no such
+// entry.
+func (n *btreeNode) first(before func(key btreeKey) bool) (*btreeNode, int) {
+	for !n.isLeaf() {
+		n = n.children[sort.Search(len(n.keys), func(i int) bool { return !before(n.keys[i]) })]
+	}
+
+	return forward(n, sort.Search(len(n.keys), func(i int) bool { return !before(n.keys[i]) }))
+}
+
+// last returns the position of the last entry in the subtree rooted at this node
+// for which after returns false. The function must hold for a (possibly empty)
+// suffix of the entries in key order. A nil leaf is returned if there is no such
+// entry.
+func (n *btreeNode) last(after func(key btreeKey) bool) (*btreeNode, int) {
+	for !n.isLeaf() {
+		n = n.children[sort.Search(len(n.keys), func(i int) bool { return after(n.keys[i]) })]
+	}
+
+	return backward(n, sort.Search(len(n.keys), func(i int) bool { return after(n.keys[i]) })-1)
+}
+
+// forward returns the position of the first entry at or following the given
+// position, moving to subsequent leaves as necessary.
+func forward(n *btreeNode, i int) (*btreeNode, int) {
+	for n != nil && i >= len(n.keys) {
+		n, i = n.next, 0
+	}
+
+	return n, i
+}
+
+// backward returns the position of the last entry at or preceding the given
+// position, moving to preceding leaves as necessary.
+func backward(n *btreeNode, i int) (*btreeNode, int) {
+	for n != nil && i < 0 {
+		if n = n.prev; n != nil {
+			i = len(n.keys) - 1
+		}
+	}
+
+	return n, i
+}
+
+func compareKeys(a, b btreeKey) int {
+	if c := compareIndexValues(a.values, b.values); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.tid, b.tid)
+}
+
+// compareIndexValues orders indexed values. Unlike ordering.CompareValueSlices, the
+// ordering is total: NULLs are equal to one another (and sort after other values),
+// and incomparable values are treated as equal.
+func compareIndexValues(a, b []any) int {
+	for i := range a[:min(len(a), len(b))] {
+		if c := compareIndexValue(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+func compareIndexValue(a, b any) int {
+	switch ordering.CompareValues(a, b) {
+	case ordering.OrderTypeBefore:
+		return -1
+	case ordering.OrderTypeAfter:
+		return 1
+	}
+
+	return 0
+}
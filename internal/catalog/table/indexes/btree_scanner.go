@@ -11,9 +11,6 @@ import (
 func (i *btreeIndex) Scanner(ctx impls.ExecutionContext, opts BtreeIndexScanOptions) (scan.TIDScanner, error) {
 	ctx.Log("Building BTree Index scanner")
 
-	stack := []*btreeNode{}
-	current := i.root
-
 	lowerBounds, err := resolveScanBounds(ctx, opts.lowerBounds)
 	if err != nil {
 		return nil, err
@@ -24,48 +21,134 @@ func (i *btreeIndex) Scanner(ctx impls.ExecutionContext, opts BtreeIndexScanOpti
 		return nil, err
 	}
 
-	return scan.TIDScannerFunc(func() (int64, error) {
-		ctx.Log("Scanning BTree Index")
+	// Entries are skipped until the range begins, and the scan stops once it ends
+	skip := func(key btreeKey) bool { return belowBounds(key.values, lowerBounds) }
+	stop := func(key btreeKey) bool { return aboveBounds(key.values, upperBounds) }
+	if opts.scanDirection == ScanDirectionBackward {
+		skip, stop = stop, skip
+	}
 
-		for current != nil || len(stack) > 0 {
-			for current != nil {
-				stack = append(stack, current)
+	return &btreeScanner{
+		ctx:         ctx,
+		index:       i,
+		direction:   opts.scanDirection,
+		lowerBounds: lowerBounds,
+		upperBounds: upperBounds,
+		skip:        skip,
+		stop:        stop,
+	}, nil
+}
 
-				if opts.scanDirection == ScanDirectionForward && checkBounds(current.values, lowerBounds, ordering.OrderTypeAfter) {
-					current = current.left
-				} else if opts.scanDirection == ScanDirectionBackward && checkBounds(current.values, upperBounds, ordering.OrderTypeBefore) {
-					current = current.right
-				} else {
-					current = nil
-				}
-			}
+type btreeScanner struct {
+	ctx         impls.ExecutionContext
+	index       *btreeIndex
+	direction   ScanDirection
+	lowerBounds [][]resolvedScanBound
+	upperBounds [][]resolvedScanBound
+	skip        func(key btreeKey) bool
+	stop        func(key btreeKey) bool
+
+	// state
+	leaf    *btreeNode
+	j       int
+	last    *btreeKey // entry most recently returned
+	version uint64    // version of the index when the position was determined
+	done    bool
+	mark    *btreeKey
+	resume  *btreeKey // entry to return next, set on restore
+}
 
-			if len(stack) == 0 {
-				break
-			}
+var _ scan.MarkRestorer = &btreeScanner{}
 
-			idx := len(stack) - 1
-			node := stack[idx]
-			stack = stack[:idx]
+func (s *btreeScanner) Scan() (int64, error) {
+	s.ctx.Log("Scanning BTree Index")
 
-			lowerOk := checkBounds(node.values, lowerBounds, ordering.OrderTypeAfter)
-			upperOk := checkBounds(node.values, upperBounds, ordering.OrderTypeBefore)
+	s.index.latch.RLock()
+	defer s.index.latch.RUnlock()
 
-			if opts.scanDirection == ScanDirectionForward && upperOk {
-				current = node.right
-			} else if opts.scanDirection == ScanDirectionBackward && lowerOk {
-				current = node.left
-			} else {
-				current = nil
-			}
+	switch {
+	case s.resume != nil:
+		s.leaf, s.j = s.seekFrom(*s.resume, true)
+		s.resume = nil
 
-			if upperOk && lowerOk {
-				return node.tid, nil
-			}
+	case s.done:
+		return 0, scan.ErrNoRows
+
+	case s.last == nil:
+		s.leaf, s.j = s.index.seek(s.direction, s.skip)
+
+	case s.version != s.index.version:
+		// The leaf may have been split or merged since the last entry was returned;
+		// resume from the entry following it in the current tree
+		s.leaf, s.j = s.seekFrom(*s.last, false)
+
+	default:
+		s.leaf, s.j = step(s.direction, s.leaf, s.j)
+	}
+	s.version = s.index.version
+
+	for ; s.leaf != nil; s.leaf, s.j = step(s.direction, s.leaf, s.j) {
+		key := s.leaf.keys[s.j]
+		if s.stop(key) {
+			break
 		}
 
-		return 0, scan.ErrNoRows
-	}), nil
+		// Entries within the range of the leading columns may still fall outside
+		// of the bounds of subsequent columns
+		if checkBounds(key.values, s.lowerBounds, ordering.OrderTypeAfter) && checkBounds(key.values, s.upperBounds, ordering.OrderTypeBefore) {
+			s.last = &key
+			return key.tid, nil
+		}
+	}
+
+	s.done = true
+	return 0, scan.ErrNoRows
+}
+
+// seekFrom returns the position of the first entry following (or, if inclusive, at)
+// the given entry in the scan direction.
+func (s *btreeScanner) seekFrom(from btreeKey, inclusive bool) (*btreeNode, int) {
+	return s.index.seek(s.direction, func(key btreeKey) bool {
+		c := compareKeys(key, from)
+		if s.direction == ScanDirectionBackward {
+			c = -c
+		}
+
+		return c < 0 || (c == 0 && !inclusive)
+	})
+}
+
+func (s *btreeScanner) Mark() {
+	s.mark = s.last
+}
+
+func (s *btreeScanner) Restore() {
+	if s.mark == nil {
+		panic("no mark to restore")
+	}
+
+	s.resume = s.mark
+	s.done = false
+}
+
+// seek returns the position of the first entry in the given scan direction for which
+// skip returns false.
+func (i *btreeIndex) seek(direction ScanDirection, skip func(key btreeKey) bool) (*btreeNode, int) {
+	if direction == ScanDirectionBackward {
+		return i.root.last(skip)
+	}
+
+	return i.root.first(skip)
+}
+
+// step returns the position of the entry following the given position in the given
+// scan direction.
+func step(direction ScanDirection, leaf *btreeNode, j int) (*btreeNode, int) {
+	if direction == ScanDirectionBackward {
+		return backward(leaf, j-1)
+	}
+
+	return forward(leaf, j+1)
 }
 
 type resolvedScanBound struct {
@@ -107,3 +190,39 @@ func checkBounds(nodeValues []any, bounds [][]resolvedScanBound, expected orderi
 
 	return true
 }
+
+// belowBounds returns true if the given values precede the range described by the
+// given lower bounds, compared lexicographically. The values for which it returns true
+// form a prefix of the index.
+func belowBounds(values []any, bounds [][]resolvedScanBound) bool {
+	return outsideBounds(values, bounds, -1)
+}
+
+// aboveBounds returns true if the given values follow the range described by the
+// given upper bounds, compared lexicographically. The values for which it returns true
+// form a suffix of the index.
+func aboveBounds(values []any, bounds [][]resolvedScanBound) bool {
+	return outsideBounds(values, bounds, 1)
+}
+
+func outsideBounds(values []any, bounds [][]resolvedScanBound, sign int) bool {
+	for j, bounds := range bounds[:min(len(bounds), len(values))] {
+		onBound := false
+		for _, bound := range bounds {
+			c := compareIndexValue(values[j], bound.value) * sign
+			if c > 0 || (c == 0 && !bound.inclusive) {
+				return true
+			}
+			if c == 0 {
+				onBound = true
+			}
+		}
+
+		if !onBound {
+			// Strictly within the bounds of this column
+			return false
+		}
+	}
+
+	return false
+}
@@ -115,6 +115,16 @@ func extractBounds(filter impls.Expression, indexedExprs []impls.ExpressionWithD
 					}
 				}
 			}
+
+			if left, prefix, ok := expressions.IsLikePrefix(conjunction); ok && prefix != "" && left.Equal(target.Expression) {
+				// Values matching a left-anchored pattern sort between the literal prefix
+				// (inclusive) and the next string of the same length (exclusive).
+				exprLowerBounds = append(exprLowerBounds, scanBound{expression: expressions.NewConstant(prefix), inclusive: true})
+
+				if upper, ok := prefixUpperBound(prefix); ok {
+					exprUpperBounds = append(exprUpperBounds, scanBound{expression: expressions.NewConstant(upper), inclusive: false})
+				}
+			}
 		}
 
 		lowerBounds = append(lowerBounds, exprLowerBounds)
@@ -141,3 +151,19 @@ func extractBounds(filter impls.Expression, indexedExprs []impls.ExpressionWithD
 
 	return prunedLowerBounds, prunedUpperBounds
 }
+
+// prefixUpperBound returns the smallest string that sorts after every string beginning
+// with the given prefix, if one exists.
+func prefixUpperBound(prefix string) (string, bool) {
+	bytes := []byte(prefix)
+	for len(bytes) > 0 && bytes[len(bytes)-1] == 0xff {
+		bytes = bytes[:len(bytes)-1]
+	}
+
+	if len(bytes) == 0 {
+		return "", false
+	}
+
+	bytes[len(bytes)-1]++
+	return string(bytes), true
+}
@@ -2,6 +2,7 @@ package indexes
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/efritz/gostgres/internal/execution/expressions"
 	"github.com/efritz/gostgres/internal/shared/fields"
@@ -11,6 +12,7 @@ import (
 )
 
 type hashIndex struct {
+	latch      sync.RWMutex
 	name       string
 	tableName  string
 	expression impls.Expression
@@ -69,12 +71,15 @@ func (i *hashIndex) Ordering(opts HashIndexScanOptions) impls.OrderExpression {
 	return nil
 }
 
-func (i *hashIndex) Insert(row rows.Row) error {
+func (i *hashIndex) Insert(row rows.Row, isLive func(tid int64) (bool, error)) error {
 	tid, value, err := i.extractTIDAndValueFromRow(row)
 	if err != nil {
 		return err
 	}
 
+	i.latch.Lock()
+	defer i.latch.Unlock()
+
 	hash := utils.Hash(value)
 	i.entries[hash] = append(i.entries[hash], hashItem{tid, value})
 	return nil
@@ -86,6 +91,9 @@ func (i *hashIndex) Delete(row rows.Row) error {
 		return err
 	}
 
+	i.latch.Lock()
+	defer i.latch.Unlock()
+
 	hash := utils.Hash(value)
 	items := i.entries[hash]
 
@@ -38,7 +38,7 @@ func TestHashIndex(t *testing.T) {
 	index := NewHashIndex("authors_name", "authors", expressions.NewNamed(name))
 
 	for _, row := range allRows {
-		require.NoError(t, index.Insert(row))
+		require.NoError(t, index.Insert(row, nil))
 	}
 
 	t.Run("find particular value", func(t *testing.T) {
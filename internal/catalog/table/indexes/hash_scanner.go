@@ -1,6 +1,8 @@
 package indexes
 
 import (
+	"slices"
+
 	"github.com/efritz/gostgres/internal/execution/queries"
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/shared/rows"
@@ -16,19 +18,47 @@ func (i *hashIndex) Scanner(ctx impls.ExecutionContext, opts HashIndexScanOption
 		return nil, err
 	}
 
-	items := i.entries[utils.Hash(value)]
+	// Copy the bucket; deletions reorder its entries in place
+	i.latch.RLock()
+	items := slices.Clone(i.entries[utils.Hash(value)])
+	i.latch.RUnlock()
+
+	return &hashScanner{
+		ctx:   ctx,
+		items: items,
+		mark:  -1,
+	}, nil
+}
+
+type hashScanner struct {
+	ctx   impls.ExecutionContext
+	items []hashItem
+	next  int
+	mark  int
+}
+
+var _ scan.MarkRestorer = &hashScanner{}
 
-	j := 0
+func (s *hashScanner) Scan() (int64, error) {
+	s.ctx.Log("Scanning Hash Index")
 
-	return scan.TIDScannerFunc(func() (int64, error) {
-		ctx.Log("Scanning Hash Index")
+	if s.next < len(s.items) {
+		tid := s.items[s.next].tid
+		s.next++
+		return tid, nil
+	}
+
+	return 0, scan.ErrNoRows
+}
 
-		if j < len(items) {
-			tid := items[j].tid
-			j++
-			return tid, nil
-		}
+func (s *hashScanner) Mark() {
+	s.mark = s.next - 1
+}
+
+func (s *hashScanner) Restore() {
+	if s.mark == -1 {
+		panic("no mark to restore")
+	}
 
-		return 0, scan.ErrNoRows
-	}), nil
+	s.next = s.mark
 }
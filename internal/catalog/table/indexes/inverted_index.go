@@ -0,0 +1,198 @@
+package indexes
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+// invertedIndex maps the keys and values occurring within jsonb values to the rows
+// containing them (like Postgres's GIN indexes). A lookup finds the rows containing
+// every key and value of the query, which are then rechecked against the indexed value.
+type invertedIndex struct {
+	latch      sync.RWMutex
+	name       string
+	tableName  string
+	expression impls.Expression
+	postings   map[string]map[int64]struct{}
+	values     map[int64]types.JSONB
+}
+
+type InvertedIndexScanOptions struct {
+	operator   invertedOperator
+	expression impls.Expression
+}
+
+type invertedOperator int
+
+const (
+	invertedOperatorContains invertedOperator = iota // indexed @> expression
+	invertedOperatorHasKey                           // indexed ? expression
+)
+
+var _ impls.Index[InvertedIndexScanOptions] = &invertedIndex{}
+
+func NewInvertedIndex(name, tableName string, expression impls.Expression) impls.Index[InvertedIndexScanOptions] {
+	return &invertedIndex{
+		name:       name,
+		tableName:  tableName,
+		expression: expression,
+		postings:   map[string]map[int64]struct{}{},
+		values:     map[int64]types.JSONB{},
+	}
+}
+
+func (i *invertedIndex) Unwrap() impls.BaseIndex {
+	return i
+}
+
+func (i *invertedIndex) UniqueOn() []fields.Field {
+	return nil
+}
+
+func (i *invertedIndex) Filter() impls.Expression {
+	return nil
+}
+
+func (i *invertedIndex) Name() string {
+	return i.name
+}
+
+func (i *invertedIndex) Description(opts InvertedIndexScanOptions) string {
+	return fmt.Sprintf("gin index scan of %s via %s", i.tableName, i.name)
+}
+
+func (i *invertedIndex) Condition(opts InvertedIndexScanOptions) impls.Expression {
+	if opts.operator == invertedOperatorHasKey {
+		return expressions.NewJSONBHasKey(i.expression, opts.expression)
+	}
+
+	return expressions.NewContains(i.expression, opts.expression)
+}
+
+func (i *invertedIndex) Ordering(opts InvertedIndexScanOptions) impls.OrderExpression {
+	return nil
+}
+
+func (i *invertedIndex) Insert(row rows.Row, isLive func(tid int64) (bool, error)) error {
+	tid, value, err := i.extractTIDAndValueFromRow(row)
+	if err != nil || value == nil {
+		return err
+	}
+
+	i.latch.Lock()
+	defer i.latch.Unlock()
+
+	for _, key := range invertedKeys(*value) {
+		if i.postings[key] == nil {
+			i.postings[key] = map[int64]struct{}{}
+		}
+
+		i.postings[key][tid] = struct{}{}
+	}
+
+	i.values[tid] = *value
+	return nil
+}
+
+func (i *invertedIndex) Delete(row rows.Row) error {
+	tid, value, err := i.extractTIDAndValueFromRow(row)
+	if err != nil || value == nil {
+		return err
+	}
+
+	i.latch.Lock()
+	defer i.latch.Unlock()
+
+	for _, key := range invertedKeys(*value) {
+		delete(i.postings[key], tid)
+
+		if len(i.postings[key]) == 0 {
+			delete(i.postings, key)
+		}
+	}
+
+	delete(i.values, tid)
+	return nil
+}
+
+func (i *invertedIndex) extractTIDAndValueFromRow(row rows.Row) (int64, *types.JSONB, error) {
+	tid, err := row.TID()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	value, err := i.expression.ValueFrom(impls.EmptyExecutionContext, row)
+	if err != nil || value == nil {
+		return 0, nil, err
+	}
+
+	jsonb, ok := value.(types.JSONB)
+	if !ok {
+		return 0, nil, fmt.Errorf("gin index %q requires values of type jsonb, got %s", i.name, types.TypeKindFromValue(value))
+	}
+
+	return tid, &jsonb, nil
+}
+
+// invertedKeys returns the index keys of the given value: the keys of every object and
+// every scalar within the value. Strings are also indexed as keys, as the ? operator
+// matches the string elements of arrays. Each key and value of a contained value is a key
+// or value of the containing value, so the keys of a query are a subset of the keys of
+// each value that contains it.
+func invertedKeys(value types.JSONB) []string {
+	keys := map[string]struct{}{}
+
+	var visit func(value any)
+	visit = func(value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			for key, element := range v {
+				keys[invertedKey(key)] = struct{}{}
+				visit(element)
+			}
+
+		case []any:
+			for _, element := range v {
+				visit(element)
+			}
+
+		case string:
+			keys[invertedKey(v)] = struct{}{}
+			keys[invertedScalar(v)] = struct{}{}
+
+		default:
+			keys[invertedScalar(v)] = struct{}{}
+		}
+	}
+	visit(value.Value())
+
+	flattened := make([]string, 0, len(keys))
+	for key := range keys {
+		flattened = append(flattened, key)
+	}
+
+	return flattened
+}
+
+func invertedKey(key string) string {
+	return "k" + key
+}
+
+func invertedScalar(value any) string {
+	if n, ok := value.(json.Number); ok {
+		// Numerically equal numbers (e.g., 1 and 1.0) share a key
+		if f, ok := new(big.Float).SetString(string(n)); ok {
+			return "n" + f.Text('e', -1)
+		}
+	}
+
+	return "v" + types.NewJSONB(value).String()
+}
@@ -0,0 +1,106 @@
+package indexes
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/scan"
+	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvertedIndex(t *testing.T) {
+	tid := fields.NewField("events", "tid", types.TypeBigInteger, fields.InternalFieldTid)
+	id := fields.NewField("events", "id", types.TypeInteger, fields.NonInternalField)
+	payload := fields.NewField("events", "payload", types.TypeJSONB, fields.NonInternalField)
+	fields := []fields.Field{tid, id, payload}
+
+	allRows := map[int64]rows.Row{}
+	for i := 1; i <= 100; i++ {
+		tid := int64(i)
+
+		values := []any{
+			tid,      // tid
+			int32(i), // id
+			types.JSONB(fmt.Sprintf(`{"kind": "k%d", "tags": ["t%d"], "nested": {"kind": "n%d"}}`, i%10, i%3, i%7)), // payload
+		}
+
+		row, err := rows.NewRow(fields, values)
+		require.NoError(t, err)
+		allRows[tid] = row
+	}
+
+	index := NewInvertedIndex("events_payload", "events", expressions.NewNamed(payload))
+
+	for _, row := range allRows {
+		require.NoError(t, index.Insert(row, nil))
+	}
+
+	scanIDs := func(opts InvertedIndexScanOptions) []int32 {
+		scanner, err := index.Scanner(impls.EmptyExecutionContext, opts)
+		require.NoError(t, err)
+
+		var ids []int32
+		for {
+			tid, err := scanner.Scan()
+			if err != nil {
+				if err == scan.ErrNoRows {
+					break
+				}
+
+				require.NoError(t, err)
+			}
+
+			ids = append(ids, allRows[tid].Values[1].(int32))
+		}
+
+		return ids
+	}
+
+	t.Run("containment", func(t *testing.T) {
+		opts := InvertedIndexScanOptions{
+			operator:   invertedOperatorContains,
+			expression: expressions.NewConstant(types.JSONB(`{"kind": "k4", "tags": ["t2"]}`)),
+		}
+
+		assert.Equal(t, []int32{14, 44, 74}, scanIDs(opts))
+	})
+
+	t.Run("containment rechecks depth", func(t *testing.T) {
+		// Every key and value occurs in rows where "n3" is nested, but not at the top level
+		opts := InvertedIndexScanOptions{
+			operator:   invertedOperatorContains,
+			expression: expressions.NewConstant(types.JSONB(`{"kind": "n3"}`)),
+		}
+
+		assert.Empty(t, scanIDs(opts))
+	})
+
+	t.Run("key existence", func(t *testing.T) {
+		opts := InvertedIndexScanOptions{
+			operator:   invertedOperatorHasKey,
+			expression: expressions.NewConstant("nested"),
+		}
+		assert.Len(t, scanIDs(opts), 100)
+
+		// Nested keys are not top-level keys
+		opts.expression = expressions.NewConstant("t1")
+		assert.Empty(t, scanIDs(opts))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, index.Delete(allRows[44]))
+
+		opts := InvertedIndexScanOptions{
+			operator:   invertedOperatorContains,
+			expression: expressions.NewConstant(types.JSONB(`{"kind": "k4", "tags": ["t2"]}`)),
+		}
+
+		assert.Equal(t, []int32{14, 74}, scanIDs(opts))
+	})
+}
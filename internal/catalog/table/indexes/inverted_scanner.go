@@ -0,0 +1,129 @@
+package indexes
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/scan"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+func (i *invertedIndex) Scanner(ctx impls.ExecutionContext, opts InvertedIndexScanOptions) (scan.TIDScanner, error) {
+	ctx.Log("Building Inverted Index scanner")
+
+	value, err := queries.Evaluate(ctx, opts.expression, rows.Row{})
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	var matches func(indexed types.JSONB) bool
+
+	switch v := value.(type) {
+	case nil:
+		// NULL operands match no rows
+		return &invertedScanner{ctx: ctx, mark: -1}, nil
+
+	case types.JSONB:
+		if opts.operator != invertedOperatorContains {
+			return nil, fmt.Errorf("unexpected jsonb operand for gin index %q", i.name)
+		}
+
+		keys = invertedKeys(v)
+		matches = func(indexed types.JSONB) bool { return indexed.Contains(v) }
+
+	case string:
+		if opts.operator != invertedOperatorHasKey {
+			return nil, fmt.Errorf("unexpected text operand for gin index %q", i.name)
+		}
+
+		keys = []string{invertedKey(v)}
+		matches = func(indexed types.JSONB) bool { return indexed.HasKey(v) }
+
+	default:
+		return nil, fmt.Errorf("unexpected %s operand for gin index %q", types.TypeKindFromValue(value), i.name)
+	}
+
+	i.latch.RLock()
+	tids := i.candidates(keys)
+
+	// Candidates contain every key of the query, but may not match it (e.g., a key may
+	// occur at a different depth); recheck each against its indexed value
+	tids = slices.DeleteFunc(tids, func(tid int64) bool { return !matches(i.values[tid]) })
+	i.latch.RUnlock()
+
+	slices.Sort(tids)
+
+	return &invertedScanner{
+		ctx:  ctx,
+		tids: tids,
+		mark: -1,
+	}, nil
+}
+
+// candidates returns the rows whose indexed values have each of the given keys. Every row
+// is a candidate when there are no keys (e.g., for the query `@> '{}'`). The caller must
+// hold the latch.
+func (i *invertedIndex) candidates(keys []string) []int64 {
+	if len(keys) == 0 {
+		tids := make([]int64, 0, len(i.values))
+		for tid := range i.values {
+			tids = append(tids, tid)
+		}
+
+		return tids
+	}
+
+	// Intersect starting from the smallest posting list
+	slices.SortFunc(keys, func(a, b string) int { return len(i.postings[a]) - len(i.postings[b]) })
+
+	var tids []int64
+outer:
+	for tid := range i.postings[keys[0]] {
+		for _, key := range keys[1:] {
+			if _, ok := i.postings[key][tid]; !ok {
+				continue outer
+			}
+		}
+
+		tids = append(tids, tid)
+	}
+
+	return tids
+}
+
+type invertedScanner struct {
+	ctx  impls.ExecutionContext
+	tids []int64
+	next int
+	mark int
+}
+
+var _ scan.MarkRestorer = &invertedScanner{}
+
+func (s *invertedScanner) Scan() (int64, error) {
+	s.ctx.Log("Scanning Inverted Index")
+
+	if s.next < len(s.tids) {
+		tid := s.tids[s.next]
+		s.next++
+		return tid, nil
+	}
+
+	return 0, scan.ErrNoRows
+}
+
+func (s *invertedScanner) Mark() {
+	s.mark = s.next - 1
+}
+
+func (s *invertedScanner) Restore() {
+	if s.mark == -1 {
+		panic("no mark to restore")
+	}
+
+	s.next = s.mark
+}
@@ -0,0 +1,49 @@
+package indexes
+
+import (
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type invertedExpressioner interface {
+	InvertedExpression() impls.Expression
+}
+
+func (i *invertedIndex) InvertedExpression() impls.Expression {
+	return i.expression
+}
+
+func CanSelectInvertedIndex(
+	index impls.BaseIndex,
+	filterExpression impls.Expression,
+) (_ impls.Index[InvertedIndexScanOptions], opts InvertedIndexScanOptions, _ bool) {
+	if !matchesPartial(index, filterExpression) {
+		return nil, opts, false
+	}
+
+	if filterExpression == nil {
+		return nil, opts, false
+	}
+
+	invertedIndex, ok := index.(impls.Index[InvertedIndexScanOptions])
+	if !ok {
+		return nil, opts, false
+	}
+	invertedExpressioner, ok := invertedIndex.Unwrap().(invertedExpressioner)
+	if !ok {
+		return nil, opts, false
+	}
+	invertedExpression := invertedExpressioner.InvertedExpression()
+
+	for _, conjunction := range expressions.Conjunctions(filterExpression) {
+		if left, right, ok := expressions.IsContainment(conjunction); ok && left.Equal(invertedExpression) {
+			return invertedIndex, InvertedIndexScanOptions{operator: invertedOperatorContains, expression: right}, true
+		}
+
+		if left, right, ok := expressions.IsJSONBKeyExistence(conjunction); ok && left.Equal(invertedExpression) {
+			return invertedIndex, InvertedIndexScanOptions{operator: invertedOperatorHasKey, expression: right}, true
+		}
+	}
+
+	return nil, opts, false
+}
@@ -29,7 +29,7 @@ func (i *partialIndex[O]) Filter() impls.Expression {
 	return i.condition
 }
 
-func (i *partialIndex[O]) Insert(row rows.Row) error {
+func (i *partialIndex[O]) Insert(row rows.Row, isLive func(tid int64) (bool, error)) error {
 	if i.condition != nil {
 		if ok, err := types.ValueAs[bool](i.condition.ValueFrom(impls.EmptyExecutionContext, row)); err != nil {
 			return err
@@ -38,7 +38,7 @@ func (i *partialIndex[O]) Insert(row rows.Row) error {
 		}
 	}
 
-	return i.Index.Insert(row)
+	return i.Index.Insert(row, isLive)
 }
 
 func (i *partialIndex[O]) Delete(row rows.Row) error {
@@ -0,0 +1,251 @@
+package table
+
+import (
+	"math/big"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/ordering"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+const (
+	// statisticsTarget bounds the number of most common values and histogram buckets
+	// collected for each column.
+	statisticsTarget = 100
+
+	// statisticsSampleSize is the maximum number of rows read when analyzing a table.
+	statisticsSampleSize = 300 * statisticsTarget
+)
+
+// Analyze collects statistics describing the rows of the given table visible to the
+// given context's transaction. Statistics of large tables are estimated from a random
+// sample of their rows.
+func Analyze(ctx impls.ExecutionContext, table impls.Table) (*impls.TableStatistics, error) {
+	tids, err := table.TIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sample, err := sampleRows(ctx, table, tids, statisticsSampleSize)
+	if err != nil {
+		return nil, err
+	}
+
+	statistics := &impls.TableStatistics{
+		RowCount: len(tids),
+		Columns:  map[string]impls.ColumnStatistics{},
+	}
+
+	for i, field := range table.Fields() {
+		if field.Internal() {
+			continue
+		}
+
+		values := make([]any, 0, len(sample))
+		for _, row := range sample {
+			values = append(values, row.Values[i])
+		}
+
+		statistics.Columns[field.Name()] = analyzeColumn(values, len(tids))
+	}
+
+	return statistics, nil
+}
+
+// sampleRows reads a uniformly random subset of at most sampleSize rows with the
+// given TIDs. The sample is deterministic for a given set of TIDs.
+func sampleRows(ctx impls.ExecutionContext, table impls.Table, tids []int64, sampleSize int) ([]rows.Row, error) {
+	if len(tids) > sampleSize {
+		tids = slices.Clone(tids)
+		random := rand.New(rand.NewSource(int64(len(tids))))
+
+		// Partial Fisher-Yates shuffle
+		for i := 0; i < sampleSize; i++ {
+			j := i + random.Intn(len(tids)-i)
+			tids[i], tids[j] = tids[j], tids[i]
+		}
+
+		tids = tids[:sampleSize]
+		slices.Sort(tids)
+	}
+
+	sample := make([]rows.Row, 0, len(tids))
+	for _, tid := range tids {
+		row, ok, err := table.Row(ctx, tid)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			sample = append(sample, row)
+		}
+	}
+
+	return sample, nil
+}
+
+type valueGroup struct {
+	value any
+	count int
+}
+
+// analyzeColumn computes the statistics of a column from the given sample of its
+// values, drawn from a table with the given number of rows.
+func analyzeColumn(values []any, rowCount int) impls.ColumnStatistics {
+	if len(values) == 0 {
+		return impls.ColumnStatistics{}
+	}
+
+	var groups []*valueGroup
+	groupsByKey := map[any]*valueGroup{}
+	nonNullCount := 0
+
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		nonNullCount++
+
+		key := groupKey(value)
+		group, ok := groupsByKey[key]
+		if !ok {
+			group = &valueGroup{value: value}
+			groupsByKey[key] = group
+			groups = append(groups, group)
+		}
+
+		group.count++
+	}
+
+	sampleCount := float64(len(values))
+	statistics := impls.ColumnStatistics{
+		NullFraction: float64(len(values)-nonNullCount) / sampleCount,
+	}
+	if nonNullCount == 0 {
+		return statistics
+	}
+
+	complete := len(values) >= rowCount
+	statistics.DistinctCount = estimateDistinctCount(groups, nonNullCount, rowCount, statistics.NullFraction, complete)
+
+	// Order groups by descending frequency; ties are broken by order of appearance
+	slices.SortStableFunc(groups, func(a, b *valueGroup) int { return b.count - a.count })
+
+	numCommon := 0
+	if len(groups) <= statisticsTarget && (complete || groups[len(groups)-1].count > 1) {
+		// Every value is common
+		numCommon = len(groups)
+	} else {
+		// Values are common if they occur notably more often than average
+		threshold := 1.25 * float64(nonNullCount) / float64(len(groups))
+
+		for numCommon < min(len(groups), statisticsTarget) {
+			if group := groups[numCommon]; group.count <= 1 || float64(group.count) < threshold {
+				break
+			}
+
+			numCommon++
+		}
+	}
+
+	for _, group := range groups[:numCommon] {
+		statistics.MostCommonValues = append(statistics.MostCommonValues, group.value)
+		statistics.MostCommonFrequencies = append(statistics.MostCommonFrequencies, float64(group.count)/sampleCount)
+	}
+
+	statistics.HistogramBounds = histogramBounds(groups[numCommon:])
+	return statistics
+}
+
+// estimateDistinctCount estimates the number of distinct non-NULL values of a column
+// from the groups of a sample of its values. Samples of an entire table are exact;
+// otherwise the estimate uses the Duj1 estimator of Haas and Stokes.
+func estimateDistinctCount(groups []*valueGroup, nonNullCount, rowCount int, nullFraction float64, complete bool) float64 {
+	distinct := float64(len(groups))
+	if complete {
+		return distinct
+	}
+
+	singletons := 0
+	for _, group := range groups {
+		if group.count == 1 {
+			singletons++
+		}
+	}
+
+	totalNonNull := float64(rowCount) * (1 - nullFraction)
+	if singletons == len(groups) {
+		// Every sampled value is unique; assume the column is as well
+		return totalNonNull
+	}
+
+	n := float64(nonNullCount)
+	f1 := float64(singletons)
+	estimate := n * distinct / (n - f1 + f1*n/float64(rowCount))
+
+	return min(max(estimate, distinct), totalNonNull)
+}
+
+// histogramBounds returns the bounds of an equi-depth histogram over the values of
+// the given groups. No histogram is built if there are fewer than two distinct values
+// or if the values cannot be ordered.
+func histogramBounds(groups []*valueGroup) []any {
+	if len(groups) < 2 {
+		return nil
+	}
+
+	comparable := true
+	slices.SortFunc(groups, func(a, b *valueGroup) int {
+		switch ordering.CompareValues(a.value, b.value) {
+		case ordering.OrderTypeBefore:
+			return -1
+		case ordering.OrderTypeAfter:
+			return 1
+		case ordering.OrderTypeEqual:
+			return 0
+		}
+
+		comparable = false
+		return 0
+	})
+	if !comparable {
+		return nil
+	}
+
+	var sorted []any
+	for _, group := range groups {
+		for i := 0; i < group.count; i++ {
+			sorted = append(sorted, group.value)
+		}
+	}
+
+	numBounds := min(len(groups), statisticsTarget+1)
+	bounds := make([]any, 0, numBounds)
+	for i := 0; i < numBounds; i++ {
+		bounds = append(bounds, sorted[i*(len(sorted)-1)/(numBounds-1)])
+	}
+
+	return bounds
+}
+
+type numericKey string
+type timestampKey int64
+type arrayKey string
+
+// groupKey returns a comparable key identifying equal values.
+func groupKey(value any) any {
+	switch v := value.(type) {
+	case *big.Float:
+		return numericKey(v.Text('g', -1))
+	case time.Time:
+		return timestampKey(v.UnixNano())
+	case types.Array:
+		return arrayKey(v.String())
+	}
+
+	return value
+}
@@ -0,0 +1,121 @@
+package table
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/efritz/gostgres/internal/shared/rows"
+)
+
+// tupleStore holds the row versions of a table, keyed by TID. Stores do not synchronize
+// access themselves; the table's latch must be held while a store is used.
+type tupleStore interface {
+	// insert stores a new row version with the given values and assigns it a TID.
+	insert(values []any, xmin int64, cmin int) (tuple, error)
+
+	// restore stores a row version with the given values and TID.
+	restore(tid int64, values []any) (tuple, error)
+
+	get(tid int64) (tuple, bool, error)
+	setDeleted(tid int64, xmax int64, cmax int) error
+	remove(tid int64) error
+	forEach(f func(tuple tuple) error) error
+	size() int
+}
+
+// newRowFunc creates the row stored with the given TID and values.
+type newRowFunc func(tid int64, values []any) (rows.Row, error)
+
+//
+//
+
+// memoryStore holds row versions in memory.
+type memoryStore struct {
+	newRow newRowFunc
+	tuples map[int64]*tuple
+}
+
+var _ tupleStore = &memoryStore{}
+
+// tid is the last TID assigned to a row of any in-memory table.
+var tid atomic.Int64
+
+func newMemoryStore(newRow newRowFunc) *memoryStore {
+	return &memoryStore{
+		newRow: newRow,
+		tuples: map[int64]*tuple{},
+	}
+}
+
+func (s *memoryStore) insert(values []any, xmin int64, cmin int) (tuple, error) {
+	id := tid.Add(1)
+
+	row, err := s.newRow(id, values)
+	if err != nil {
+		return tuple{}, err
+	}
+
+	s.tuples[id] = &tuple{tid: id, row: row, xmin: xmin, cmin: cmin}
+	return *s.tuples[id], nil
+}
+
+func (s *memoryStore) restore(id int64, values []any) (tuple, error) {
+	// Ensure TIDs assigned to new rows do not collide with restored ones
+	for {
+		current := tid.Load()
+		if current >= id || tid.CompareAndSwap(current, id) {
+			break
+		}
+	}
+
+	if _, ok := s.tuples[id]; ok {
+		return tuple{}, errDuplicateTID(id)
+	}
+
+	row, err := s.newRow(id, values)
+	if err != nil {
+		return tuple{}, err
+	}
+
+	s.tuples[id] = &tuple{tid: id, row: row}
+	return *s.tuples[id], nil
+}
+
+func (s *memoryStore) get(tid int64) (tuple, bool, error) {
+	if t, ok := s.tuples[tid]; ok {
+		return *t, true, nil
+	}
+
+	return tuple{}, false, nil
+}
+
+func (s *memoryStore) setDeleted(tid int64, xmax int64, cmax int) error {
+	if t, ok := s.tuples[tid]; ok {
+		t.xmax, t.cmax = xmax, cmax
+	}
+
+	return nil
+}
+
+func (s *memoryStore) remove(tid int64) error {
+	delete(s.tuples, tid)
+	return nil
+}
+
+func (s *memoryStore) forEach(f func(tuple tuple) error) error {
+	for _, t := range s.tuples {
+		if err := f(*t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *memoryStore) size() int {
+	return len(s.tuples)
+}
+
+func errDuplicateTID(tid int64) error {
+	return fmt.Errorf("duplicate TID %d", tid)
+}
@@ -1,26 +1,60 @@
 package table
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/efritz/gostgres/internal/shared/fields"
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/storage/heap"
+	"github.com/efritz/gostgres/internal/storage/wal"
 	"golang.org/x/exp/slices"
 )
 
+// table stores the row versions of a relation along with its indexes and constraints.
+// The latch guards these structures against concurrent access; it is held only for
+// the duration of a single operation and never while waiting on a transaction lock.
 type table struct {
+	latch       sync.RWMutex
 	name        string
 	fields      []impls.TableField
-	rows        map[int64]rows.Row
+	tuples      tupleStore
 	primaryKey  impls.BaseIndex
 	indexes     []impls.BaseIndex
 	constraints []impls.Constraint
+	statistics  *impls.TableStatistics
+	dead        int   // number of stored versions that have been deleted
+	horizon     int64 // horizon of the most recent vacuum
+	failure     error // failure to revert a change to the stored versions
 }
 
+// autoVacuumThreshold and autoVacuumScaleFactor determine when a table is vacuumed
+// as rows are modified: once the number of deleted versions exceeds the threshold
+// plus the given fraction of the live rows.
+const (
+	autoVacuumThreshold   = 50
+	autoVacuumScaleFactor = 0.2
+)
+
 var _ impls.Table = &table{}
 
+// NewTable creates a table whose rows are stored in memory.
 func NewTable(name string, nonInternalFields []impls.TableField) impls.Table {
+	t := newTable(name, nonInternalFields)
+	t.tuples = newMemoryStore(t.newRow)
+	return t
+}
+
+// NewHeapTable creates a table whose rows are stored in the given heap.
+func NewHeapTable(name string, nonInternalFields []impls.TableField, heap *heap.Heap) impls.Table {
+	t := newTable(name, nonInternalFields)
+	t.tuples = newHeapStore(name, heap, t.newRow)
+	return t
+}
+
+func newTable(name string, nonInternalFields []impls.TableField) *table {
 	tableFields := []impls.TableField{
 		impls.NewTableFieldFromField(fields.TIDField.WithRelationName(name)),
 	}
@@ -31,7 +65,6 @@ func NewTable(name string, nonInternalFields []impls.TableField) impls.Table {
 	return &table{
 		name:   name,
 		fields: tableFields,
-		rows:   map[int64]rows.Row{},
 	}
 }
 
@@ -40,122 +73,535 @@ func (t *table) Name() string {
 }
 
 func (t *table) Indexes() []impls.BaseIndex {
+	t.latch.RLock()
+	defer t.latch.RUnlock()
+
+	return t.indexesLocked()
+}
+
+func (t *table) indexesLocked() []impls.BaseIndex {
 	if t.primaryKey != nil {
 		return append([]impls.BaseIndex{t.primaryKey}, t.indexes...)
 	}
 
-	return t.indexes
+	return slices.Clone(t.indexes)
 }
 
 func (t *table) Fields() []impls.TableField {
 	return slices.Clone(t.fields)
 }
 
+// Size returns the number of live rows stored in the table: row versions that have
+// not been deleted, regardless of whether they are visible to any particular transaction.
 func (t *table) Size() int {
-	return len(t.rows)
+	t.latch.RLock()
+	defer t.latch.RUnlock()
+
+	return t.tuples.size() - t.dead
 }
 
-func (t *table) TIDs() []int64 {
-	tids := make([]int64, 0, len(t.rows))
-	for tid := range t.rows {
-		tids = append(tids, tid)
+func (t *table) TIDs(ctx impls.ExecutionContext) ([]int64, error) {
+	t.latch.RLock()
+	defer t.latch.RUnlock()
+
+	if t.failure != nil {
+		return nil, t.failure
+	}
+
+	tx := ctx.Transaction()
+
+	var tids []int64
+	if err := t.tuples.forEach(func(tuple tuple) error {
+		if tuple.visible(tx) {
+			tids = append(tids, tuple.tid)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 	slices.Sort(tids)
 
-	return tids
+	return tids, nil
 }
 
-func (t *table) Row(tid int64) (rows.Row, bool) {
-	row, ok := t.rows[tid]
-	return row, ok
+func (t *table) Row(ctx impls.ExecutionContext, tid int64) (rows.Row, bool, error) {
+	t.latch.RLock()
+	defer t.latch.RUnlock()
+
+	if t.failure != nil {
+		return rows.Row{}, false, t.failure
+	}
+
+	tuple, ok, err := t.tuples.get(tid)
+	if err != nil || !ok || !tuple.visible(ctx.Transaction()) {
+		return rows.Row{}, false, err
+	}
+
+	return tuple.row, true, nil
 }
 
-func (t *table) SetPrimaryKey(index impls.BaseIndex) error {
+func (t *table) SetPrimaryKey(ctx impls.ExecutionContext, index impls.BaseIndex) error {
+	t.latch.Lock()
+	defer t.latch.Unlock()
+
 	if t.primaryKey != nil {
 		return fmt.Errorf("primary key already set")
 	}
 
-	for _, row := range t.rows {
-		if err := index.Insert(row); err != nil {
-			return err
-		}
+	if err := t.populateIndex(ctx, index); err != nil {
+		return err
 	}
 
 	t.primaryKey = index
+	ctx.OnRollback(func() {
+		t.latch.Lock()
+		defer t.latch.Unlock()
+
+		t.primaryKey = nil
+	})
+
 	return nil
 }
 
-func (t *table) AddIndex(index impls.BaseIndex) error {
-	for _, row := range t.rows {
-		if err := index.Insert(row); err != nil {
-			return err
-		}
+func (t *table) AddIndex(ctx impls.ExecutionContext, index impls.BaseIndex) error {
+	t.latch.Lock()
+	defer t.latch.Unlock()
+
+	if err := t.populateIndex(ctx, index); err != nil {
+		return err
 	}
 
 	t.indexes = append(t.indexes, index)
+	ctx.OnRollback(func() {
+		t.latch.Lock()
+		defer t.latch.Unlock()
+
+		t.indexes = removeElement(t.indexes, index)
+	})
+
 	return nil
 }
 
-func (t *table) AddConstraint(ctx impls.ExecutionContext, constraint impls.Constraint) error {
-	for _, row := range t.rows {
-		if err := constraint.Check(ctx, row); err != nil {
+// populateIndex adds every version of every row to the given index. Index scans
+// filter out versions that are not visible to the scanning transaction. Deleted
+// versions are added first so that unique indexes do not reject them for conflicting
+// with the versions that replaced them.
+func (t *table) populateIndex(ctx impls.ExecutionContext, index impls.BaseIndex) error {
+	isLive := t.isLive(ctx)
+
+	for _, live := range []bool{false, true} {
+		if err := t.tuples.forEach(func(tuple tuple) error {
+			if tupleLive, err := isLive(tuple.tid); err != nil || tupleLive != live {
+				return err
+			}
+
+			return index.Insert(tuple.row, isLive)
+		}); err != nil {
 			return err
 		}
 	}
 
-	t.constraints = append(t.constraints, constraint)
 	return nil
 }
 
-var tid = int64(0)
+func (t *table) AddConstraint(ctx impls.ExecutionContext, constraint impls.Constraint) error {
+	// Constraints may read from this table (e.g., self-referential foreign keys);
+	// check existing rows without holding the latch
+	tids, err := t.TIDs(ctx)
+	if err != nil {
+		return err
+	}
 
-func (t *table) Insert(ctx impls.ExecutionContext, row rows.Row) (_ rows.Row, err error) {
-	tid++
-	id := tid
+	for _, tid := range tids {
+		row, ok, err := t.Row(ctx, tid)
+		if err != nil {
+			return err
+		}
 
-	var fields []fields.Field
-	for _, field := range t.fields {
-		fields = append(fields, field.Field)
+		if ok {
+			if err := constraint.Check(ctx, row); err != nil {
+				return err
+			}
+		}
 	}
 
-	newRow, err := rows.NewRow(fields, append([]any{id}, row.Values...))
+	t.latch.Lock()
+	defer t.latch.Unlock()
+
+	t.constraints = append(t.constraints, constraint)
+	ctx.OnRollback(func() {
+		t.latch.Lock()
+		defer t.latch.Unlock()
+
+		t.constraints = removeElement(t.constraints, constraint)
+	})
+
+	return nil
+}
+
+func (t *table) Insert(ctx impls.ExecutionContext, row rows.Row) (rows.Row, error) {
+	// The TID of the new row is not assigned until it is stored
+	candidate, err := t.newRow(0, row.Values)
 	if err != nil {
 		return rows.Row{}, err
 	}
 
-	for _, constraint := range t.constraints {
-		if err := constraint.Check(ctx, newRow); err != nil {
+	// Store the values as converted to the column types
+	values := candidate.Values[1:]
+
+	t.latch.RLock()
+	constraints := slices.Clone(t.constraints)
+	t.latch.RUnlock()
+
+	for _, constraint := range constraints {
+		if err := constraint.Check(ctx, candidate); err != nil {
 			return rows.Row{}, err
 		}
 	}
 
-	for _, index := range t.Indexes() {
-		if err := index.Insert(newRow); err != nil {
+	for {
+		row, err := t.insert(ctx, values)
+
+		var pending pendingError
+		if !errors.As(err, &pending) {
+			return row, err
+		}
+
+		// A unique index contains a version with the same values that was created (or
+		// deleted) by a transaction in progress; check again once that transaction ends
+		if err := ctx.WaitForTransaction(pending.transactionID); err != nil {
 			return rows.Row{}, err
 		}
 	}
+}
+
+// insert stores a new version of a row with the given values and adds it to the
+// indexes of the table.
+func (t *table) insert(ctx impls.ExecutionContext, values []any) (rows.Row, error) {
+	t.latch.Lock()
+	defer t.latch.Unlock()
+
+	if t.failure != nil {
+		return rows.Row{}, t.failure
+	}
+
+	if err := t.autoVacuum(ctx); err != nil {
+		return rows.Row{}, err
+	}
+
+	xmin, cmin := transactionIDs(ctx.Transaction())
+	tuple, err := t.tuples.insert(values, xmin, cmin)
+	if err != nil {
+		return rows.Row{}, err
+	}
+
+	if err := t.insertIntoIndexes(ctx, tuple.row); err != nil {
+		return rows.Row{}, errors.Join(err, t.fail(t.tuples.remove(tuple.tid)))
+	}
+
+	ctx.OnRollback(func() {
+		t.latch.Lock()
+		defer t.latch.Unlock()
+
+		t.deleteFromIndexes(tuple.row)
+		t.fail(t.tuples.remove(tuple.tid))
+	})
+	ctx.RecordChange(wal.NewInsertRecord(t.name, tuple.tid, values))
+
+	return tuple.row, nil
+}
+
+func (t *table) Restore(ctx impls.ExecutionContext, id int64, values []any) error {
+	t.latch.Lock()
+	defer t.latch.Unlock()
+
+	var tuple tuple
+	var err error
+	if id == 0 {
+		// Assign a new TID, visible to all transactions
+		tuple, err = t.tuples.insert(values, 0, 0)
+	} else {
+		tuple, err = t.tuples.restore(id, values)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore row of table %q: %s", t.name, err)
+	}
+
+	if err := t.insertIntoIndexes(ctx, tuple.row); err != nil {
+		return errors.Join(err, t.fail(t.tuples.remove(tuple.tid)))
+	}
 
-	t.rows[id] = newRow
-	return newRow, nil
+	return nil
 }
 
-func (t *table) Delete(row rows.Row) (rows.Row, bool, error) {
+func (t *table) newRow(id int64, values []any) (rows.Row, error) {
+	var fields []fields.Field
+	for _, field := range t.fields {
+		fields = append(fields, field.Field)
+	}
+
+	return rows.NewRow(fields, append([]any{id}, values...))
+}
+
+func (t *table) Delete(ctx impls.ExecutionContext, row rows.Row) (rows.Row, bool, error) {
 	tid, err := row.TID()
 	if err != nil {
 		return rows.Row{}, false, err
 	}
 
-	fullRow, ok := t.rows[tid]
-	if !ok {
-		return rows.Row{}, false, nil
+	if ok, err := t.lockRow(ctx, tid, impls.ForUpdateLock); err != nil || !ok {
+		return rows.Row{}, false, err
 	}
 
-	for _, index := range t.Indexes() {
-		if err := index.Delete(fullRow); err != nil {
+	t.latch.Lock()
+	defer t.latch.Unlock()
+
+	if t.failure != nil {
+		return rows.Row{}, false, t.failure
+	}
+
+	tuple, ok, err := t.tuples.get(tid)
+	if err != nil || !ok || tuple.xmax != 0 {
+		// Already deleted by the current command
+		return rows.Row{}, false, err
+	}
+
+	tx := ctx.Transaction()
+	if tx == nil {
+		// Changes made outside of a transaction cannot be undone; drop the row outright
+		if err := t.tuples.remove(tid); err != nil {
 			return rows.Row{}, false, err
 		}
+
+		t.deleteFromIndexes(tuple.row)
+		return tuple.row, true, nil
+	}
+
+	if err := t.tuples.setDeleted(tid, tx.ID(), tx.CommandID()); err != nil {
+		return rows.Row{}, false, err
+	}
+
+	t.dead++
+	ctx.OnRollback(func() {
+		t.latch.Lock()
+		defer t.latch.Unlock()
+
+		if t.fail(t.tuples.setDeleted(tid, 0, 0)) == nil {
+			t.dead--
+		}
+	})
+	ctx.RecordChange(wal.NewDeleteRecord(t.name, tid))
+
+	return tuple.row, true, nil
+}
+
+func (t *table) LockRow(ctx impls.ExecutionContext, row rows.Row, mode impls.LockMode) error {
+	tid, err := row.TID()
+	if err != nil {
+		return err
+	}
+
+	_, err = t.lockRow(ctx, tid, mode)
+	return err
+}
+
+// lockRow acquires a lock on the version of the row with the given TID visible to
+// the current transaction, waiting for any transaction holding a conflicting lock to
+// end. Once locked, the row version cannot be deleted by another transaction until
+// the current transaction ends. Rows that are not visible are not locked.
+func (t *table) lockRow(ctx impls.ExecutionContext, tid int64, mode impls.LockMode) (bool, error) {
+	tx := ctx.Transaction()
+
+	t.latch.RLock()
+	tuple, ok, err := t.tuples.get(tid)
+	visible := ok && tuple.visible(tx)
+	t.latch.RUnlock()
+
+	if err != nil || !visible {
+		return false, err
+	}
+
+	if err := ctx.LockRow(t.name, tid, mode); err != nil {
+		return false, err
+	}
+
+	t.latch.RLock()
+	defer t.latch.RUnlock()
+
+	// Versions are not removed while locked, but may have been deleted while waiting
+	tuple, _, err = t.tuples.get(tid)
+	if err != nil {
+		return false, err
+	}
+	if tuple.xmax != 0 && (tx == nil || tuple.xmax != tx.ID()) {
+		// Deleted by a transaction that committed after the current transaction's
+		// snapshot was taken. Deletions of transactions that roll back are reverted
+		// before their locks are released.
+		return false, fmt.Errorf("could not serialize access due to concurrent update")
+	}
+
+	return true, nil
+}
+
+func (t *table) Statistics() *impls.TableStatistics {
+	t.latch.RLock()
+	defer t.latch.RUnlock()
+
+	return t.statistics
+}
+
+func (t *table) SetStatistics(ctx impls.ExecutionContext, statistics *impls.TableStatistics) {
+	t.latch.Lock()
+	defer t.latch.Unlock()
+
+	previous := t.statistics
+	t.statistics = statistics
+	ctx.OnRollback(func() {
+		t.latch.Lock()
+		defer t.latch.Unlock()
+
+		t.statistics = previous
+	})
+}
+
+func (t *table) Vacuum(ctx impls.ExecutionContext) error {
+	tx := ctx.Transaction()
+	if tx == nil {
+		return nil
+	}
+
+	t.latch.Lock()
+	defer t.latch.Unlock()
+
+	if t.failure != nil {
+		return t.failure
+	}
+
+	return t.vacuum(tx.Horizon())
+}
+
+// autoVacuum vacuums the table if enough of its row versions have been deleted since
+// it was last vacuumed. Tables are not vacuumed again until the horizon advances, as
+// no additional versions could be removed. The caller must hold the latch.
+func (t *table) autoVacuum(ctx impls.ExecutionContext) error {
+	tx := ctx.Transaction()
+	if tx == nil {
+		return nil
+	}
+
+	live := t.tuples.size() - t.dead
+	if float64(t.dead) <= autoVacuumThreshold+autoVacuumScaleFactor*float64(live) {
+		return nil
+	}
+
+	horizon := tx.Horizon()
+	if horizon == t.horizon {
+		return nil
+	}
+
+	return t.vacuum(horizon)
+}
+
+// vacuum removes the row versions deleted by transactions older than the given horizon
+// from the table and its indexes. No current or future transaction can see these
+// versions. The caller must hold the latch.
+func (t *table) vacuum(horizon int64) error {
+	var obsolete []tuple
+	if err := t.tuples.forEach(func(tuple tuple) error {
+		if tuple.xmax != 0 && tuple.xmax < horizon {
+			obsolete = append(obsolete, tuple)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, tuple := range obsolete {
+		if err := t.tuples.remove(tuple.tid); err != nil {
+			return err
+		}
+
+		t.deleteFromIndexes(tuple.row)
+		t.dead--
+	}
+
+	t.horizon = horizon
+	return nil
+}
+
+// isLive returns a function that determines whether the row version with the given
+// TID has not been deleted from the perspective of the given context's transaction.
+// The function fails with a pendingError for versions created or deleted by another
+// transaction that has not yet ended.
+func (t *table) isLive(ctx impls.ExecutionContext) func(tid int64) (bool, error) {
+	tx := ctx.Transaction()
+
+	// Invoked by indexes while the latch is held
+	return func(tid int64) (bool, error) {
+		tuple, ok, err := t.tuples.get(tid)
+		if err != nil || !ok {
+			return false, err
+		}
+
+		if id := tuple.pending(tx); id != 0 {
+			return false, pendingError{transactionID: id}
+		}
+
+		return !tuple.dead(tx), nil
+	}
+}
+
+// pendingError is returned by isLive when whether a row version is live depends on
+// the outcome of a transaction that has not yet ended.
+type pendingError struct {
+	transactionID int64
+}
+
+func (e pendingError) Error() string {
+	return fmt.Sprintf("row version depends on transaction %d in progress", e.transactionID)
+}
+
+// fail records the given failure to revert a change to the stored row versions.
+// Versions that should not be visible may then remain in the table, so every
+// subsequent read or modification of the table fails. The caller must hold the latch.
+func (t *table) fail(err error) error {
+	if err != nil && t.failure == nil {
+		t.failure = fmt.Errorf("table %q cannot be used after failing to revert a change: %w", t.name, err)
+	}
+
+	return err
+}
+
+// insertIntoIndexes adds the given row to every index of the table. If any index
+// rejects the row, it is removed from the indexes to which it was already added.
+// The caller must hold the latch.
+func (t *table) insertIntoIndexes(ctx impls.ExecutionContext, row rows.Row) error {
+	indexes := t.indexesLocked()
+	for i, index := range indexes {
+		if err := index.Insert(row, t.isLive(ctx)); err != nil {
+			for _, inserted := range indexes[:i] {
+				_ = inserted.Delete(row)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *table) deleteFromIndexes(row rows.Row) {
+	for _, index := range t.indexesLocked() {
+		_ = index.Delete(row)
+	}
+}
+
+func removeElement[T comparable](elements []T, element T) []T {
+	if i := slices.Index(elements, element); i >= 0 {
+		return slices.Delete(slices.Clone(elements), i, i+1)
 	}
 
-	delete(t.rows, tid)
-	return fullRow, true, nil
+	return elements
 }
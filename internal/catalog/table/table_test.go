@@ -0,0 +1,127 @@
+package table
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/transaction"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/efritz/gostgres/internal/storage/buffer"
+	"github.com/efritz/gostgres/internal/storage/heap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVacuum(t *testing.T) {
+	coordinator := transaction.NewCoordinator()
+	writer := transaction.NewManager(coordinator)
+	reader := transaction.NewManager(coordinator)
+
+	tbl := NewTable("t", []impls.TableField{
+		impls.NewTableField("t", "id", types.TypeInteger, fields.NonInternalField),
+	}).(*table)
+
+	statement := func(m *transaction.Manager, f func(ctx impls.ExecutionContext)) {
+		f(impls.EmptyExecutionContext.WithTransaction(m.StartStatement()))
+		require.NoError(t, m.EndStatement(nil))
+	}
+
+	statement(writer, func(ctx impls.ExecutionContext) {
+		for i := 1; i <= 3; i++ {
+			_, err := tbl.Insert(ctx, rows.Row{Values: []any{int32(i)}})
+			require.NoError(t, err)
+		}
+	})
+
+	// Take a snapshot that sees every row
+	statement(reader, func(ctx impls.ExecutionContext) {
+		require.NoError(t, reader.Begin())
+	})
+
+	statement(writer, func(ctx impls.ExecutionContext) {
+		tids, err := tbl.TIDs(ctx)
+		require.NoError(t, err)
+
+		for _, tid := range tids[:2] {
+			row, ok, err := tbl.Row(ctx, tid)
+			require.NoError(t, err)
+			require.True(t, ok)
+			_, _, err = tbl.Delete(ctx, row)
+			require.NoError(t, err)
+		}
+	})
+	assert.Equal(t, 1, tbl.Size())
+
+	// Deleted versions remain while visible to the reader's snapshot
+	statement(writer, func(ctx impls.ExecutionContext) { require.NoError(t, tbl.Vacuum(ctx)) })
+	assert.Equal(t, 3, tbl.tuples.size())
+	statement(reader, func(ctx impls.ExecutionContext) {
+		tids, err := tbl.TIDs(ctx)
+		require.NoError(t, err)
+		assert.Len(t, tids, 3)
+	})
+
+	statement(reader, func(ctx impls.ExecutionContext) { require.NoError(t, reader.Commit()) })
+	statement(writer, func(ctx impls.ExecutionContext) { require.NoError(t, tbl.Vacuum(ctx)) })
+	assert.Equal(t, 1, tbl.tuples.size())
+	assert.Equal(t, 1, tbl.Size())
+}
+
+func TestAutoVacuum(t *testing.T) {
+	m := transaction.NewManager(transaction.NewCoordinator())
+
+	tbl := NewTable("t", []impls.TableField{
+		impls.NewTableField("t", "id", types.TypeInteger, fields.NonInternalField),
+	}).(*table)
+
+	// Repeatedly replace a single row; deleted versions are removed as they accumulate
+	var row rows.Row
+	for i := 0; i < 1000; i++ {
+		ctx := impls.EmptyExecutionContext.WithTransaction(m.StartStatement())
+		if i > 0 {
+			_, _, err := tbl.Delete(ctx, row)
+			require.NoError(t, err)
+		}
+
+		var err error
+		row, err = tbl.Insert(ctx, rows.Row{Values: []any{int32(i)}})
+		require.NoError(t, err)
+		require.NoError(t, m.EndStatement(nil))
+	}
+
+	assert.Equal(t, 1, tbl.Size())
+	assert.LessOrEqual(t, tbl.tuples.size(), autoVacuumThreshold+2)
+}
+
+func TestHeapStoreFailures(t *testing.T) {
+	h, err := heap.Open(buffer.NewPool(4), filepath.Join(t.TempDir(), "t"))
+	require.NoError(t, err)
+
+	tbl := NewHeapTable("t", []impls.TableField{
+		impls.NewTableField("t", "id", types.TypeInteger, fields.NonInternalField),
+	}, h)
+
+	ctx := impls.EmptyExecutionContext
+	row, err := tbl.Insert(ctx, rows.Row{Values: []any{int32(1)}})
+	require.NoError(t, err)
+	tid, err := row.TID()
+	require.NoError(t, err)
+
+	// Failures to read or write the heap file are returned rather than raised
+	require.NoError(t, h.Close())
+
+	_, err = tbl.TIDs(ctx)
+	assert.ErrorContains(t, err, `storage failure in table "t"`)
+
+	_, _, err = tbl.Row(ctx, tid)
+	assert.ErrorContains(t, err, `storage failure in table "t"`)
+
+	_, err = tbl.Insert(ctx, rows.Row{Values: []any{int32(2)}})
+	assert.ErrorContains(t, err, `storage failure in table "t"`)
+
+	_, _, err = tbl.Delete(ctx, row)
+	assert.ErrorContains(t, err, `storage failure in table "t"`)
+}
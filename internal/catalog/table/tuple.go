@@ -0,0 +1,79 @@
+package table
+
+import (
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+)
+
+// tuple is a single version of a row. Updating a row deletes its current version
+// and inserts a new one; versions are never modified in place so that transactions
+// reading from an older snapshot continue to see the version visible to them.
+//
+// A version is created by command cmin of transaction xmin and is deleted by command
+// cmax of transaction xmax (if xmax is non-zero). Versions created by transactions
+// that roll back are removed from the table, and deletions made by transactions that
+// roll back are cleared, so that only the changes of committed (or in-progress)
+// transactions are ever recorded here.
+type tuple struct {
+	tid  int64
+	row  rows.Row
+	xmin int64
+	cmin int
+	xmax int64
+	cmax int
+}
+
+// visible returns true if this version of the row is visible to the given transaction.
+// Outside of a transaction, only the latest version of a row is visible.
+func (t *tuple) visible(tx impls.Transaction) bool {
+	if tx == nil {
+		return t.xmax == 0
+	}
+
+	if !tx.Sees(t.xmin, t.cmin) {
+		return false
+	}
+
+	return t.xmax == 0 || !tx.Sees(t.xmax, t.cmax)
+}
+
+// dead returns true if this version of the row has been deleted by a committed
+// transaction or by the given transaction. Dead versions do not conflict with new
+// versions in unique indexes.
+func (t *tuple) dead(tx impls.Transaction) bool {
+	if t.xmax == 0 {
+		return false
+	}
+
+	if tx == nil {
+		return true
+	}
+
+	return t.xmax == tx.ID() || tx.Committed(t.xmax)
+}
+
+// pending returns the identifier of a transaction other than the given transaction
+// that created or deleted this version of the row and has not yet ended, or zero if
+// there is no such transaction. Whether the version is live depends on the outcome
+// of that transaction.
+func (t *tuple) pending(tx impls.Transaction) int64 {
+	if tx == nil {
+		return 0
+	}
+
+	for _, id := range []int64{t.xmin, t.xmax} {
+		if id != 0 && id != tx.ID() && !tx.Committed(id) {
+			return id
+		}
+	}
+
+	return 0
+}
+
+func transactionIDs(tx impls.Transaction) (int64, int) {
+	if tx == nil {
+		return 0, 0
+	}
+
+	return tx.ID(), tx.CommandID()
+}
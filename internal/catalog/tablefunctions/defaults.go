@@ -0,0 +1,79 @@
+package tablefunctions
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+func DefaultTableFunctions() map[string]impls.TableFunction {
+	m := map[string]impls.TableFunction{}
+	for _, f := range []impls.TableFunction{
+		unnest,
+		jsonbEach,
+		jsonbArrayElements,
+	} {
+		m[f.Name()] = f
+	}
+
+	return m
+}
+
+var unnest = newTableFunctionImpl(
+	"unnest",
+	[]types.Type{types.NewArrayType(types.TypeAny)},
+	func(argTypes []types.Type) []impls.TableFunctionColumn {
+		return []impls.TableFunctionColumn{{Name: "unnest", Type: argTypes[0].ElementType()}}
+	},
+	func(ctx impls.ExecutionContext, args []any) ([][]any, error) {
+		var rows [][]any
+		for _, element := range args[0].(types.Array).Elements() {
+			rows = append(rows, []any{element})
+		}
+
+		return rows, nil
+	},
+)
+
+var jsonbEach = newTableFunctionImpl(
+	"jsonb_each",
+	[]types.Type{types.TypeJSONB},
+	func(argTypes []types.Type) []impls.TableFunctionColumn {
+		return []impls.TableFunctionColumn{{Name: "key", Type: types.TypeText}, {Name: "value", Type: types.TypeJSONB}}
+	},
+	func(ctx impls.ExecutionContext, args []any) ([][]any, error) {
+		object, ok := args[0].(types.JSONB).Value().(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot call jsonb_each on a non-object")
+		}
+
+		var rows [][]any
+		for _, key := range types.JSONKeys(object) {
+			rows = append(rows, []any{key, types.NewJSONB(object[key])})
+		}
+
+		return rows, nil
+	},
+)
+
+var jsonbArrayElements = newTableFunctionImpl(
+	"jsonb_array_elements",
+	[]types.Type{types.TypeJSONB},
+	func(argTypes []types.Type) []impls.TableFunctionColumn {
+		return []impls.TableFunctionColumn{{Name: "value", Type: types.TypeJSONB}}
+	},
+	func(ctx impls.ExecutionContext, args []any) ([][]any, error) {
+		array, ok := args[0].(types.JSONB).Value().([]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot extract elements from a non-array")
+		}
+
+		var rows [][]any
+		for _, element := range array {
+			rows = append(rows, []any{types.NewJSONB(element)})
+		}
+
+		return rows, nil
+	},
+)
@@ -0,0 +1,55 @@
+package tablefunctions
+
+import (
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type tableFunctionImpl struct {
+	impls.Callable
+	columns columnsFunc
+	invoke  invokeFunc
+}
+
+type columnsFunc func(argTypes []types.Type) []impls.TableFunctionColumn
+type invokeFunc func(ctx impls.ExecutionContext, args []any) ([][]any, error)
+
+var _ impls.TableFunction = tableFunctionImpl{}
+
+func newTableFunctionImpl(
+	name string,
+	paramTypes []types.Type,
+	columns columnsFunc,
+	invoke invokeFunc,
+) impls.TableFunction {
+	return tableFunctionImpl{
+		Callable: impls.NewCallable(name, paramTypes, types.TypeUnknown),
+		columns:  columns,
+		invoke:   invoke,
+	}
+}
+
+func (f tableFunctionImpl) ResolveColumns(argTypes []types.Type) ([]impls.TableFunctionColumn, error) {
+	if err := f.Callable.ValidateArgTypes(argTypes); err != nil {
+		return nil, err
+	}
+
+	return f.columns(argTypes), nil
+}
+
+func (f tableFunctionImpl) Invoke(ctx impls.ExecutionContext, args []any) ([][]any, error) {
+	refinedArgs, err := f.Callable.RefineArgValues(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// Like other functions, table functions are strict: they return no rows when any
+	// argument is NULL
+	for _, arg := range refinedArgs {
+		if arg == nil {
+			return nil, nil
+		}
+	}
+
+	return f.invoke(ctx, refinedArgs)
+}
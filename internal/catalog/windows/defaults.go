@@ -0,0 +1,198 @@
+package windows
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+func DefaultWindowFunctions() map[string]impls.WindowFunction {
+	m := map[string]impls.WindowFunction{}
+	for _, f := range []impls.WindowFunction{
+		rowNumber,
+		rank,
+		denseRank,
+		percentRank,
+		cumeDist,
+		ntile,
+		lag,
+		lead,
+		firstValue,
+		lastValue,
+		nthValue,
+	} {
+		m[f.Name()] = f
+	}
+
+	return m
+}
+
+var rowNumber = newWindowFunctionImpl(
+	"row_number",
+	nil,
+	0,
+	types.TypeBigInteger,
+	func(ctx impls.ExecutionContext, row impls.WindowRow) (any, error) {
+		return int64(row.Index + 1), nil
+	},
+)
+
+var rank = newWindowFunctionImpl(
+	"rank",
+	nil,
+	0,
+	types.TypeBigInteger,
+	func(ctx impls.ExecutionContext, row impls.WindowRow) (any, error) {
+		return int64(row.PeersStart + 1), nil
+	},
+)
+
+var denseRank = newWindowFunctionImpl(
+	"dense_rank",
+	nil,
+	0,
+	types.TypeBigInteger,
+	func(ctx impls.ExecutionContext, row impls.WindowRow) (any, error) {
+		return int64(row.PeerGroup + 1), nil
+	},
+)
+
+var percentRank = newWindowFunctionImpl(
+	"percent_rank",
+	nil,
+	0,
+	types.TypeDoublePrecision,
+	func(ctx impls.ExecutionContext, row impls.WindowRow) (any, error) {
+		if len(row.Args) <= 1 {
+			return float64(0), nil
+		}
+
+		return float64(row.PeersStart) / float64(len(row.Args)-1), nil
+	},
+)
+
+var cumeDist = newWindowFunctionImpl(
+	"cume_dist",
+	nil,
+	0,
+	types.TypeDoublePrecision,
+	func(ctx impls.ExecutionContext, row impls.WindowRow) (any, error) {
+		return float64(row.PeersEnd) / float64(len(row.Args)), nil
+	},
+)
+
+var ntile = newWindowFunctionImpl(
+	"ntile",
+	[]types.Type{types.TypeInteger},
+	1,
+	types.TypeInteger,
+	func(ctx impls.ExecutionContext, row impls.WindowRow) (any, error) {
+		buckets, ok, err := integerArg(row, 0, "ntile", 0)
+		if err != nil || !ok {
+			return nil, err
+		}
+		if buckets <= 0 {
+			return nil, fmt.Errorf("argument of ntile must be greater than zero")
+		}
+
+		// The first (n % buckets) buckets hold one more row than the remaining buckets
+		n := int64(len(row.Args))
+		size, remainder := n/buckets, n%buckets
+		index := int64(row.Index)
+
+		if large := remainder * (size + 1); index >= large {
+			return int32(remainder + (index-large)/size + 1), nil
+		}
+
+		return int32(index/(size+1) + 1), nil
+	},
+)
+
+var lag = newWindowFunctionImpl(
+	"lag",
+	[]types.Type{types.TypeAny, types.TypeInteger, types.TypeAny},
+	1,
+	types.TypeAny,
+	func(ctx impls.ExecutionContext, row impls.WindowRow) (any, error) {
+		return offsetValue(row, "lag", -1)
+	},
+)
+
+var lead = newWindowFunctionImpl(
+	"lead",
+	[]types.Type{types.TypeAny, types.TypeInteger, types.TypeAny},
+	1,
+	types.TypeAny,
+	func(ctx impls.ExecutionContext, row impls.WindowRow) (any, error) {
+		return offsetValue(row, "lead", 1)
+	},
+)
+
+// offsetValue returns the value of the first argument for the row at the offset given
+// by the second argument (default one) in the given direction from the current row. If
+// there is no such row in the partition, the value of the third argument is returned.
+func offsetValue(row impls.WindowRow, name string, direction int) (any, error) {
+	offset, ok, err := integerArg(row, 1, name, 1)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	if index := row.Index + direction*int(offset); index >= 0 && index < len(row.Args) {
+		return arg(row, index, 0), nil
+	}
+
+	return arg(row, row.Index, 2), nil
+}
+
+var firstValue = newWindowFunctionImpl(
+	"first_value",
+	[]types.Type{types.TypeAny},
+	1,
+	types.TypeAny,
+	func(ctx impls.ExecutionContext, row impls.WindowRow) (any, error) {
+		frame := row.Frame()
+		if len(frame) == 0 {
+			return nil, nil
+		}
+
+		return arg(row, frame[0], 0), nil
+	},
+)
+
+var lastValue = newWindowFunctionImpl(
+	"last_value",
+	[]types.Type{types.TypeAny},
+	1,
+	types.TypeAny,
+	func(ctx impls.ExecutionContext, row impls.WindowRow) (any, error) {
+		frame := row.Frame()
+		if len(frame) == 0 {
+			return nil, nil
+		}
+
+		return arg(row, frame[len(frame)-1], 0), nil
+	},
+)
+
+var nthValue = newWindowFunctionImpl(
+	"nth_value",
+	[]types.Type{types.TypeAny, types.TypeInteger},
+	2,
+	types.TypeAny,
+	func(ctx impls.ExecutionContext, row impls.WindowRow) (any, error) {
+		n, ok, err := integerArg(row, 1, "nth_value", 0)
+		if err != nil || !ok {
+			return nil, err
+		}
+		if n <= 0 {
+			return nil, fmt.Errorf("argument of nth_value must be greater than zero")
+		}
+
+		if frame := row.Frame(); int(n) <= len(frame) {
+			return arg(row, frame[n-1], 0), nil
+		}
+
+		return nil, nil
+	},
+)
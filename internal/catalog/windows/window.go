@@ -0,0 +1,95 @@
+package windows
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type windowFunctionImpl struct {
+	name         string
+	paramTypes   []types.Type
+	requiredArgs int
+	returnType   types.Type // TypeAny to return the type of the first argument
+	invoke       invokeFunc
+}
+
+type invokeFunc func(ctx impls.ExecutionContext, row impls.WindowRow) (any, error)
+
+var _ impls.WindowFunction = windowFunctionImpl{}
+
+func newWindowFunctionImpl(
+	name string,
+	paramTypes []types.Type,
+	requiredArgs int,
+	returnType types.Type,
+	invoke invokeFunc,
+) impls.WindowFunction {
+	return windowFunctionImpl{
+		name:         name,
+		paramTypes:   paramTypes,
+		requiredArgs: requiredArgs,
+		returnType:   returnType,
+		invoke:       invoke,
+	}
+}
+
+func (f windowFunctionImpl) Name() string {
+	return f.name
+}
+
+func (f windowFunctionImpl) ResolveReturnType(argTypes []types.Type) (types.Type, error) {
+	if len(argTypes) < f.requiredArgs || len(argTypes) > len(f.paramTypes) {
+		if f.requiredArgs == len(f.paramTypes) {
+			return types.TypeUnknown, fmt.Errorf("%s expects %d arguments, got %d", f.name, len(f.paramTypes), len(argTypes))
+		}
+
+		return types.TypeUnknown, fmt.Errorf("%s expects %d to %d arguments, got %d", f.name, f.requiredArgs, len(f.paramTypes), len(argTypes))
+	}
+
+	if err := impls.NewCallable(f.name, f.paramTypes[:len(argTypes)], f.returnType).ValidateArgTypes(argTypes); err != nil {
+		return types.TypeUnknown, err
+	}
+
+	if f.returnType == types.TypeAny {
+		return argTypes[0], nil
+	}
+
+	return f.returnType, nil
+}
+
+func (f windowFunctionImpl) Invoke(ctx impls.ExecutionContext, row impls.WindowRow) (any, error) {
+	return f.invoke(ctx, row)
+}
+
+// arg returns the value of the given argument for the given row of the partition, or
+// nil if the argument was not supplied.
+func arg(row impls.WindowRow, index, i int) any {
+	if args := row.Args[index]; i < len(args) {
+		return args[i]
+	}
+
+	return nil
+}
+
+// integerArg returns the value of the given argument for the current row as an
+// integer, or the given default if the argument was not supplied. A NULL argument
+// yields false.
+func integerArg(row impls.WindowRow, i int, name string, defaultValue int64) (int64, bool, error) {
+	if i >= len(row.Args[row.Index]) {
+		return defaultValue, true, nil
+	}
+
+	value := row.Args[row.Index][i]
+	if value == nil {
+		return 0, false, nil
+	}
+
+	_, refined, ok := types.TypeBigInteger.Refine(value)
+	if !ok {
+		return 0, false, fmt.Errorf("argument of %s must be an integer", name)
+	}
+
+	return refined.(int64), true, nil
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	engine := NewDefaultEngine()
+	session := engine.NewSession()
+	exec(t, session, "CREATE TABLE events (id integer PRIMARY KEY, status text, score integer)")
+	exec(t, session, "CREATE INDEX events_status_idx ON events (status)")
+	exec(t, session, "CREATE INDEX events_score_idx ON events (score)")
+
+	var values []string
+	for i := 1; i <= 2000; i++ {
+		status := "'done'"
+		if i%100 == 0 {
+			status = "NULL"
+		} else if i%50 == 0 {
+			status = "'failed'"
+		} else if i%10 == 0 {
+			status = "'pending'"
+		}
+
+		values = append(values, fmt.Sprintf("(%d, %s, %d)", i, status, i))
+	}
+	exec(t, session, fmt.Sprintf("INSERT INTO events (id, status, score) VALUES %s", strings.Join(values, ", ")))
+
+	explain := func(query string) string {
+		plan, err := session.QueryRows(protocol.Request{Query: "EXPLAIN " + query})
+		require.NoError(t, err)
+		require.Len(t, plan.Values, 1)
+		return plan.Values[0][0].(string)
+	}
+
+	// Without statistics, equality and range conditions are assumed to be selective
+	assert.Contains(t, explain("SELECT * FROM events WHERE status = 'done'"), "index scan of events via events_status_idx")
+	assert.Contains(t, explain("SELECT * FROM events WHERE score > 100"), "index scan of events via events_score_idx")
+
+	// Statistics collected within a rolled back transaction are discarded
+	exec(t, session, "BEGIN")
+	exec(t, session, "ANALYZE events")
+	exec(t, session, "ROLLBACK")
+	tbl, ok := engine.catalog.Tables.Get("events")
+	require.True(t, ok)
+	require.Nil(t, tbl.Statistics())
+
+	exec(t, session, "ANALYZE")
+	statistics := tbl.Statistics()
+	require.NotNil(t, statistics)
+	assert.Equal(t, 2000, statistics.RowCount)
+
+	status := statistics.Columns["status"]
+	assert.InDelta(t, 0.01, status.NullFraction, 1e-9)
+	assert.Equal(t, 3.0, status.DistinctCount)
+	assert.Equal(t, []any{"done", "pending", "failed"}, status.MostCommonValues)
+	assert.InDeltaSlice(t, []float64{0.9, 0.08, 0.01}, status.MostCommonFrequencies, 1e-9)
+	assert.Empty(t, status.HistogramBounds)
+
+	score := statistics.Columns["score"]
+	assert.Equal(t, 2000.0, score.DistinctCount)
+	assert.Empty(t, score.MostCommonValues)
+	require.Len(t, score.HistogramBounds, 101)
+	assert.Equal(t, int32(1), score.HistogramBounds[0])
+	assert.Equal(t, int32(2000), score.HistogramBounds[100])
+
+	// Common values and wide ranges are read with a table scan
+	assert.Contains(t, explain("SELECT * FROM events WHERE status = 'done'"), "table scan of events")
+	assert.Contains(t, explain("SELECT * FROM events WHERE status = 'failed'"), "index scan of events via events_status_idx")
+	assert.Contains(t, explain("SELECT * FROM events WHERE score > 100"), "table scan of events")
+	assert.Contains(t, explain("SELECT * FROM events WHERE score > 1900"), "index scan of events via events_score_idx")
+	assert.Equal(t, [][]any{{int64(1800)}}, query(t, session, "SELECT count(*) FROM events WHERE status = 'done'"))
+
+	require.ErrorContains(t, session.QueryError(protocol.Request{Query: "ANALYZE missing"}), `unknown table "missing"`)
+}
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrays(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE posts (id integer PRIMARY KEY, tags text[], scores integer[], codes varchar(2)[])")
+	exec(t, session, `INSERT INTO posts (id, tags, scores, codes) VALUES
+		(1, '{go,sql}', ARRAY[3, 1, 2], '{ab}'),
+		(2, ARRAY['sql', NULL], '{}', NULL),
+		(3, NULL, NULL, ARRAY['x', 'y'])`)
+
+	t.Run("values", func(t *testing.T) {
+		assert.Equal(t, [][]any{{
+			types.NewArray(types.TypeText, []any{"sql", nil}),
+			types.NewArray(types.TypeInteger, nil),
+		}}, query(t, session, "SELECT tags, scores FROM posts WHERE id = 2"))
+	})
+
+	t.Run("untyped literals", func(t *testing.T) {
+		// Array literals adopt the type of the opposite operand or function parameter
+		assert.Equal(t, [][]any{{int32(1)}}, query(t, session, "SELECT id FROM posts WHERE tags @> '{go}'"))
+		assert.Equal(t, [][]any{{int32(1)}, {int32(2)}}, query(t, session, "SELECT id FROM posts WHERE scores <@ '{1,2,3}' ORDER BY id"))
+		assert.Equal(t, [][]any{{int32(2)}}, query(t, session, "SELECT array_length('{1,2}', 1)"))
+	})
+
+	t.Run("updates", func(t *testing.T) {
+		exec(t, session, "UPDATE posts SET scores = scores[2:], tags = ARRAY['c'] WHERE id = 1")
+		assert.Equal(t, [][]any{{"{1,2}", "{c}"}}, query(t, session, "SELECT scores::text, tags::text FROM posts WHERE id = 1"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query string
+			err   string
+		}{
+			{query: "INSERT INTO posts (id, codes) VALUES (4, ARRAY['abc'])", err: "value too long for type character varying(2)"},
+			{query: "INSERT INTO posts (id, scores) VALUES (4, '{1,x}')", err: "invalid input syntax for type integer: \"x\""},
+			{query: "SELECT '{1,2'::integer[]", err: `malformed array literal: "{1,2"`},
+			{query: "SELECT '{{1},{2}}'::integer[]", err: "multidimensional arrays are not supported"},
+			{query: "SELECT ARRAY[ARRAY[1]]", err: "multidimensional arrays are not supported"},
+			{query: "SELECT ARRAY[1, 'a'::text]", err: "ARRAY types integer and text cannot be matched"},
+			{query: "SELECT id[1] FROM posts", err: "cannot subscript type integer because it does not support subscripting"},
+			{query: "SELECT scores['a'::text] FROM posts", err: "array subscript must have type integer, got text"},
+			{query: "SELECT 1 = ANY(1)", err: "op ANY/ALL (array) requires array on right side"},
+			{query: "SELECT 1 = ANY(tags) FROM posts", err: "illegal operand types for comparison: integer and text"},
+			{query: "SELECT scores @> tags FROM posts", err: "illegal operand types for @>: integer[] and text[]"},
+			{query: "SELECT scores && 1 FROM posts", err: "illegal operand types for &&: integer[] and integer"},
+			{query: "SELECT * FROM unnest(1)", err: "argument 1 to unnest expects type any[], got integer"},
+			{query: "SELECT * FROM generate(1)", err: `unknown table function "generate"`},
+			{query: "SELECT array_agg(scores) FROM posts", err: "multidimensional arrays are not supported"},
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: testCase.query})
+			require.Error(t, err, testCase.query)
+			assert.Contains(t, err.Error(), testCase.err, testCase.query)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCasts(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE events (id integer PRIMARY KEY, label text, at timestamp with time zone)")
+	exec(t, session, "INSERT INTO events (id, label, at) VALUES (1, 'first', '2024-01-01'), (2, 'second', '2024-02-15 12:30:00+00')")
+	exec(t, session, "CREATE SEQUENCE counter")
+
+	t.Run("implicit function argument", func(t *testing.T) {
+		// Numbers are converted implicitly to wider function parameters
+		assert.Equal(t, [][]any{{nil, int64(5)}}, query(t, session, "SELECT setval('counter', 5::smallint), currval('counter')"))
+	})
+
+	t.Run("assignment", func(t *testing.T) {
+		exec(t, session, "CREATE TABLE targets (id integer PRIMARY KEY, label text, amount smallint)")
+		exec(t, session, "INSERT INTO targets (id, label, amount) VALUES (1, 42, 2.5), (2, 'two', 3.5::real)")
+
+		assert.Equal(t, [][]any{{int32(1), "42", int16(2)}, {int32(2), "two", int16(4)}}, query(t, session, "SELECT id, label, amount FROM targets ORDER BY id"))
+
+		for _, q := range []string{
+			"INSERT INTO targets (id, label, amount) VALUES (3, 'three', '3'::text)",
+			"INSERT INTO targets (id, label, amount) VALUES (3, 'three', 40000)",
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: q})
+			assert.Error(t, err, q)
+		}
+	})
+
+	t.Run("timestamp values", func(t *testing.T) {
+		values := query(t, session, "SELECT '2024-03-01 08:00:00+02'::timestamptz")
+		require.Len(t, values, 1)
+		assert.True(t, time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC).Equal(values[0][0].(time.Time)))
+	})
+
+	t.Run("untyped literals", func(t *testing.T) {
+		// String literals adopt the type of the opposite operand
+		assert.Equal(t, [][]any{{int32(2)}}, query(t, session, "SELECT id FROM events WHERE at > '2024-02-01'"))
+		assert.Equal(t, [][]any{{int32(1)}}, query(t, session, "SELECT id FROM events WHERE at::date = '2024-01-01'"))
+
+		// String literals adopt a type for which the operator is defined
+		assert.Equal(t, [][]any{{int32(1)}}, query(t, session, "SELECT id FROM events WHERE at + '1 day' = '2024-01-02'"))
+		assert.Equal(t, [][]any{{true}}, query(t, session, "SELECT now() + '1 day' > now()"))
+
+		// String literals without another type are text
+		assert.Equal(t, [][]any{{"ac", true}}, query(t, session, "SELECT COALESCE('a', 'b') || 'c', ARRAY['a', 'b'] @> ARRAY['a']"))
+
+		// String literals adopt the type of the parameter to which they are bound
+		exec(t, session, "PREPARE label_by_id (integer) AS SELECT label FROM events WHERE id = $1")
+		assert.Equal(t, [][]any{{"second"}}, query(t, session, "EXECUTE label_by_id ('2')"))
+	})
+
+	t.Run("plans", func(t *testing.T) {
+		plan, err := session.QueryRows(protocol.Request{Query: "EXPLAIN SELECT id FROM events WHERE id::text = '1' AND id = '1'::integer"})
+		require.NoError(t, err)
+		assert.Contains(t, plan.Values[0][0].(string), "events.id::text = 1")
+		assert.Contains(t, plan.Values[0][0].(string), "index scan of events via events_pkey")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query string
+			err   string
+		}{
+			{query: "SELECT 'abc'::integer", err: `invalid input syntax for type integer: "abc"`},
+			{query: "SELECT id FROM events WHERE at > 'soon'", err: `invalid input syntax for type timestamp with time zone: "soon"`},
+			{query: "EXECUTE label_by_id ('two')", err: `invalid input syntax for type integer: "two"`},
+			{query: "SELECT CASE WHEN true THEN 1 ELSE 'a' END", err: `invalid input syntax for type integer: "a"`},
+			{query: "SELECT coalesce(1, 'a')", err: `invalid input syntax for type integer: "a"`},
+			{query: "SELECT 'maybe'::boolean", err: `invalid input syntax for type bool: "maybe"`},
+			{query: "SELECT 40000::smallint", err: "value 40000 is out of range for small integer"},
+			{query: "SELECT 'NaN'::double precision::integer", err: "integer out of range"},
+			{query: "SELECT 'Infinity'::double precision::smallint", err: "small integer out of range"},
+			{query: "SELECT '-Infinity'::real::bigint", err: "bigint out of range"},
+			{query: "SELECT true::timestamptz", err: "cannot cast type bool to timestamp with time zone"},
+			{query: "SELECT 1.5::boolean", err: "cannot cast type double precision to bool"},
+			{query: "SELECT 1::money", err: `unknown type "money"`},
+			{query: "SELECT CAST(1 integer)", err: "expected"},
+			{query: "SELECT setval('counter', 1.5)", err: "argument 2 to setval expects type bigint, got double precision"},
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: testCase.query})
+			require.Error(t, err, testCase.query)
+			assert.Contains(t, err.Error(), testCase.err, testCase.query)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/efritz/gostgres/internal/storage/wal"
+)
+
+// changeLog receives the changes of committing transactions. Changes are appended to
+// the write-ahead log (if any), and the source text of committed DDL statements is
+// retained so that snapshots can recreate the schema.
+type changeLog struct {
+	log *wal.Log
+
+	mu         sync.Mutex
+	statements []string
+}
+
+func (l *changeLog) Append(records []wal.Record) error {
+	if l.log != nil {
+		if err := l.log.Append(records); err != nil {
+			return err
+		}
+	}
+
+	for _, record := range records {
+		if record.Type == wal.RecordTypeDDL {
+			l.addStatement(record.Statement)
+		}
+	}
+
+	return nil
+}
+
+func (l *changeLog) addStatement(statement string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.statements = append(l.statements, statement)
+}
+
+// schema returns the committed DDL statements, in commit order.
+func (l *changeLog) schema() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return slices.Clone(l.statements)
+}
+
+// nextLSN returns the LSN of the next entry of the write-ahead log (if any).
+func (l *changeLog) nextLSN() int64 {
+	if l.log == nil {
+		return 0
+	}
+
+	return l.log.NextLSN()
+}
@@ -0,0 +1,36 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalExpressions(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE scores (id integer PRIMARY KEY, name text, score smallint, bonus bigint)")
+	exec(t, session, "INSERT INTO scores (id, name, score, bonus) VALUES (1, 'alice', 90, 5), (2, 'bob', 60, NULL), (3, NULL, NULL, 7), (4, 'dave', 0, 0)")
+
+	t.Run("errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query string
+			err   string
+		}{
+			{query: "SELECT CASE WHEN id > 1 THEN name ELSE id END FROM scores", err: "CASE types text and integer cannot be matched"},
+			{query: "SELECT CASE WHEN id THEN 1 END FROM scores", err: "argument of CASE/WHEN must be type bool, not type integer"},
+			{query: "SELECT CASE id WHEN 'one'::text THEN 1 END FROM scores", err: "illegal operand types for comparison: integer and text"},
+			{query: "SELECT CASE id END FROM scores", err: "expected WHEN (near END)"},
+			{query: "SELECT coalesce(name, id) FROM scores", err: "COALESCE types text and integer cannot be matched"},
+			{query: "SELECT greatest(name, id) FROM scores", err: "GREATEST types text and integer cannot be matched"},
+			{query: "SELECT nullif(name, id) FROM scores", err: "illegal operand types for comparison: text and integer"},
+			{query: "SELECT nullif(name) FROM scores", err: "NULLIF expects 2 arguments, got 1"},
+			{query: "SELECT least() FROM scores", err: "LEAST expects at least 1 argument, got 0"},
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: testCase.query})
+			require.Error(t, err, testCase.query)
+			assert.Contains(t, err.Error(), testCase.err, testCase.query)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommonTableExpressions(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE employees (id integer PRIMARY KEY, name text, manager_id integer)")
+	exec(t, session, "INSERT INTO employees (id, name, manager_id) VALUES (1, 'ada', NULL), (2, 'bob', 1), (3, 'cat', 1), (4, 'dan', 2), (5, 'eve', 4)")
+
+	t.Run("mutations", func(t *testing.T) {
+		session := NewDefaultEngine().NewSession()
+		exec(t, session, "CREATE TABLE t (id integer PRIMARY KEY, v integer)")
+
+		exec(t, session, "WITH RECURSIVE s (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM s WHERE n < 4) INSERT INTO t (id, v) SELECT n, n * 10 FROM s")
+		exec(t, session, "WITH big AS (SELECT id FROM t WHERE v > 20) UPDATE t SET v = 0 WHERE id IN (SELECT id FROM big)")
+		exec(t, session, "WITH small AS (SELECT id FROM t WHERE v = 10) DELETE FROM t WHERE id IN (SELECT id FROM small)")
+		assert.Equal(t, [][]any{{int32(2), int32(20)}, {int32(3), int32(0)}, {int32(4), int32(0)}}, query(t, session, "SELECT id, v FROM t ORDER BY id"))
+	})
+
+	t.Run("materialized common tables are evaluated once", func(t *testing.T) {
+		exec(t, session, "CREATE SEQUENCE s")
+
+		assert.Equal(t, [][]any{{int64(1), int64(1)}}, query(t, session, "WITH n AS (SELECT nextval('s') AS v) SELECT * FROM n n1, n n2"))
+		assert.Equal(t, [][]any{{int64(2), int64(3)}}, query(t, session, "WITH n AS NOT MATERIALIZED (SELECT nextval('s') AS v) SELECT * FROM n n1, n n2"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query string
+			err   string
+		}{
+			{query: "WITH a AS (SELECT 1), a AS (SELECT 2) SELECT * FROM a", err: `WITH query name "a" specified more than once`},
+			{query: "WITH a (x, y) AS (SELECT 1) SELECT * FROM a", err: "has 1 columns available but 2 columns specified"},
+			{query: "WITH t AS (SELECT 1 UNION ALL SELECT n FROM t) SELECT * FROM t", err: `unknown table "t"`},
+			{query: "WITH RECURSIVE t (n) AS (SELECT 1 UNION ALL SELECT n, n FROM t) SELECT * FROM t", err: "each UNION query must have the same number of columns"},
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: testCase.query})
+			require.Error(t, err, testCase.query)
+			assert.Contains(t, err.Error(), testCase.err, testCase.query)
+		}
+	})
+}
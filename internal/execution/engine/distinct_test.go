@@ -0,0 +1,41 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHavingAndDistinct(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE employees (id integer PRIMARY KEY, dept text, salary integer)")
+	exec(t, session, "INSERT INTO employees (id, dept, salary) VALUES (1, 'eng', 100), (2, 'eng', 200), (3, 'eng', 200), (4, 'ops', 50), (5, 'ops', 80), (6, 'hr', 70)")
+
+	t.Run("plans", func(t *testing.T) {
+		// Rows of the primary key index are ordered by the distinct column
+		plan, err := session.QueryRows(protocol.Request{Query: "EXPLAIN SELECT DISTINCT id FROM employees ORDER BY id"})
+		require.NoError(t, err)
+		assert.Contains(t, plan.Values[0][0].(string), "unique on employees.id")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query string
+			err   string
+		}{
+			{query: "SELECT dept, id FROM employees GROUP BY dept HAVING count(*) > 1", err: "not in group by"},
+			{query: "SELECT count(*) FROM employees HAVING id > 1", err: "not in group by"},
+			{query: "SELECT DISTINCT dept FROM employees ORDER BY salary", err: "for SELECT DISTINCT, ORDER BY expressions must appear in select list"},
+			{query: "SELECT DISTINCT ON (dept) id FROM employees ORDER BY id", err: "SELECT DISTINCT ON expressions must match initial ORDER BY expressions"},
+			{query: "SELECT DISTINCT id FROM employees FOR UPDATE", err: "FOR UPDATE is not allowed with DISTINCT clause"},
+			{query: "SELECT length(DISTINCT dept) FROM employees", err: "DISTINCT specified, but length is not an aggregate function"},
+			{query: "SELECT count(DISTINCT id) OVER () FROM employees", err: "DISTINCT is not implemented for window functions"},
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: testCase.query})
+			require.Error(t, err, testCase.query)
+			assert.Contains(t, err.Error(), testCase.err, testCase.query)
+		}
+	})
+}
@@ -1,66 +1,185 @@
 package engine
 
 import (
-	"fmt"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
 
 	"github.com/efritz/gostgres/internal/catalog"
 	"github.com/efritz/gostgres/internal/catalog/aggregates"
 	"github.com/efritz/gostgres/internal/catalog/functions"
+	"github.com/efritz/gostgres/internal/catalog/table"
+	"github.com/efritz/gostgres/internal/catalog/tablefunctions"
+	"github.com/efritz/gostgres/internal/catalog/windows"
 	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/transaction"
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/shared/rows"
-	"github.com/efritz/gostgres/internal/syntax/lexing"
-	"github.com/efritz/gostgres/internal/syntax/parsing"
+	"github.com/efritz/gostgres/internal/storage/buffer"
 )
 
 type Engine struct {
-	catalog impls.CatalogSet
+	catalog       impls.CatalogSet
+	transactions  *transaction.Coordinator
+	changes       *changeLog
+	dataDirectory string
+	heapTables    io.Closer
+	checkpointMu  sync.Mutex
+
+	joinSearchLimit int
+}
+
+func NewDefaultEngine(options ...Option) *Engine {
+	return NewEngine(defaultCatalogSet(), options...)
+}
+
+func NewEngine(catalog impls.CatalogSet, options ...Option) *Engine {
+	return newEngine(catalog, "", nil, newEngineOptions(options))
+}
+
+func newEngine(catalog impls.CatalogSet, dataDirectory string, heapTables io.Closer, opts engineOptions) *Engine {
+	changes := &changeLog{}
+
+	return &Engine{
+		catalog:         catalog,
+		transactions:    transaction.NewCoordinatorWithLog(changes),
+		changes:         changes,
+		dataDirectory:   dataDirectory,
+		heapTables:      heapTables,
+		joinSearchLimit: opts.joinSearchLimit,
+	}
+}
+
+// OpenDefaultEngine creates an engine whose committed changes are written to a
+// write-ahead log in the given data directory. The state recorded by previous engines
+// using the same data directory (in its latest checkpoint and write-ahead log) is
+// recovered first.
+func OpenDefaultEngine(dataDirectory string, options ...Option) (*Engine, error) {
+	return OpenEngine(defaultCatalogSet(), dataDirectory, options...)
 }
 
-func NewDefaultEngine() *Engine {
-	return NewEngine(impls.NewCatalogSet(
+func OpenEngine(catalog impls.CatalogSet, dataDirectory string, options ...Option) (*Engine, error) {
+	opts := newEngineOptions(options)
+
+	if err := os.MkdirAll(dataDirectory, 0o755); err != nil {
+		return nil, err
+	}
+
+	var heapTables io.Closer
+	if opts.bufferPoolPages > 0 {
+		// Heap files are rebuilt during recovery, so any files left by a previous engine
+		// are discarded
+		heapDirectory := filepath.Join(dataDirectory, "heap")
+		if err := os.RemoveAll(heapDirectory); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(heapDirectory, 0o755); err != nil {
+			return nil, err
+		}
+
+		accessMethod := table.NewHeapAccessMethod(buffer.NewPool(opts.bufferPoolPages), heapDirectory)
+		catalog.TableAccessMethods.Set(accessMethod.Name(), accessMethod)
+		catalog.DefaultTableAccessMethod = accessMethod.Name()
+		heapTables = accessMethod
+	}
+
+	e := newEngine(catalog, dataDirectory, heapTables, opts)
+	if err := e.recover(); err != nil {
+		if heapTables != nil {
+			_ = heapTables.Close()
+		}
+
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Option configures an engine.
+type Option func(*engineOptions)
+
+type engineOptions struct {
+	bufferPoolPages int
+	joinSearchLimit int
+}
+
+func newEngineOptions(options []Option) engineOptions {
+	var opts engineOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return opts
+}
+
+// WithHeapTables stores the rows of tables in heap files within the data directory
+// (unless created with another access method), caching at most the given number of
+// pages in memory. It applies only to engines opened with OpenEngine.
+func WithHeapTables(bufferPoolPages int) Option {
+	return func(o *engineOptions) {
+		o.bufferPoolPages = bufferPoolPages
+	}
+}
+
+// WithJoinSearchLimit sets the number of relations above which queries order their
+// joins greedily rather than by an exhaustive search of the valid join orders.
+func WithJoinSearchLimit(limit int) Option {
+	return func(o *engineOptions) {
+		o.joinSearchLimit = limit
+	}
+}
+
+func defaultCatalogSet() impls.CatalogSet {
+	return impls.NewCatalogSet(
 		catalog.NewCatalog[impls.Table](),
 		catalog.NewCatalog[impls.Sequence](),
 		catalog.NewCatalogWithEntries[impls.Function](functions.DefaultFunctions()),
 		catalog.NewCatalogWithEntries[impls.Aggregate](aggregates.DefaultAggregates()),
-	))
+		catalog.NewCatalogWithEntries[impls.WindowFunction](windows.DefaultWindowFunctions()),
+		catalog.NewCatalogWithEntries[impls.TableFunction](tablefunctions.DefaultTableFunctions()),
+		catalog.NewCatalogWithEntries(map[string]impls.TableAccessMethod{
+			table.NewMemoryAccessMethod().Name(): table.NewMemoryAccessMethod(),
+		}),
+		table.NewMemoryAccessMethod().Name(),
+	)
 }
 
-func NewEngine(catalog impls.CatalogSet) *Engine {
-	return &Engine{
-		catalog: catalog,
+// Close releases the engine's write-ahead log and heap files (if any).
+func (e *Engine) Close() error {
+	if e.changes.log == nil {
+		return nil
 	}
-}
 
-func (e *Engine) Query(request protocol.Request, responseWriter protocol.ResponseWriter) {
-	query, err := parsing.Parse(e.catalog, lexing.Lex(request.Query))
-	if err != nil {
-		responseWriter.Error(fmt.Errorf("failed to parse query: %s", err))
-		return
+	err := e.changes.log.Close()
+	if e.heapTables != nil {
+		err = errors.Join(err, e.heapTables.Close())
 	}
 
-	executionContext := impls.NewExecutionContext(e.catalog)
-	if request.Debug {
-		executionContext = executionContext.WithDebug()
-	}
+	return err
+}
 
-	query.Execute(executionContext, responseWriter)
+// Query executes the given request in a new session that is closed once the request
+// completes. A transaction block opened by the request is rolled back when the session
+// is closed, releasing its locks.
+func (e *Engine) Query(request protocol.Request, responseWriter protocol.ResponseWriter) {
+	session := e.NewSession()
+	defer session.Close()
+
+	session.Query(request, responseWriter)
 }
 
 func (e *Engine) QueryRows(request protocol.Request) (rows.Rows, error) {
-	collector := protocol.NewRowCollector()
-	e.Query(request, collector)
-	collectedRows, err := collector.Rows()
-	if err != nil {
-		return rows.Rows{}, fmt.Errorf("failed to execute query %q: %s", request.Query, err)
-	}
+	session := e.NewSession()
+	defer session.Close()
 
-	return collectedRows, nil
+	return session.QueryRows(request)
 }
 
 func (e *Engine) QueryError(request protocol.Request) error {
-	collector := protocol.NewRowCollector()
-	e.Query(request, collector)
-	_, err := collector.Rows()
-	return err
+	session := e.NewSession()
+	defer session.Close()
+
+	return session.QueryError(request)
 }
@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeapTables(t *testing.T) {
+	dataDirectory := t.TempDir()
+
+	// A pool far smaller than the tables forces pages to be evicted and read back
+	engine, err := OpenDefaultEngine(dataDirectory, WithHeapTables(4))
+	require.NoError(t, err)
+
+	var values []string
+	for i := 0; i < 500; i++ {
+		values = append(values, fmt.Sprintf("(%d, '%s', %d)", i, strings.Repeat(fmt.Sprintf("%03d", i%1000), 50), i%7))
+	}
+
+	session := engine.NewSession()
+	for _, method := range []string{"memory", "heap", ""} {
+		name, using := "t_"+method, ""
+		if method != "" {
+			using = " USING " + method
+		}
+
+		for _, query := range []string{
+			fmt.Sprintf("CREATE TABLE %s (id integer PRIMARY KEY, name text, bucket integer)%s", name, using),
+			fmt.Sprintf("CREATE INDEX %s_bucket_idx ON %s (bucket)", name, name),
+			fmt.Sprintf("INSERT INTO %s (id, name, bucket) VALUES %s", name, strings.Join(values, ", ")),
+			fmt.Sprintf("UPDATE %s SET bucket = bucket + 10 WHERE bucket = 2 AND id > 250", name),
+			fmt.Sprintf("DELETE FROM %s WHERE bucket = 3", name),
+			"BEGIN",
+			fmt.Sprintf("DELETE FROM %s WHERE bucket = 4", name),
+			fmt.Sprintf("INSERT INTO %s (id, name, bucket) VALUES (1000, 'rolled back', 4)", name),
+			"ROLLBACK",
+		} {
+			require.NoError(t, session.QueryError(protocol.Request{Query: query}), query)
+		}
+	}
+
+	// Heap files are created only for tables using the heap access method
+	entries, err := os.ReadDir(filepath.Join(dataDirectory, "heap"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	queries := []string{
+		"SELECT id, bucket FROM %s ORDER BY id",
+		"SELECT id, name FROM %s WHERE id = 42",
+		"SELECT id FROM %s WHERE bucket = 12 ORDER BY id",
+		"SELECT count(*), sum(id) FROM %s WHERE bucket > 4",
+	}
+
+	assertSameResults := func(engine *Engine) {
+		for _, query := range queries {
+			expected, err := engine.QueryRows(protocol.Request{Query: fmt.Sprintf(query, "t_memory")})
+			require.NoError(t, err)
+
+			for _, name := range []string{"t_heap", "t_"} {
+				rows, err := engine.QueryRows(protocol.Request{Query: fmt.Sprintf(query, name)})
+				require.NoError(t, err)
+				assert.Equal(t, expected.Values, rows.Values, query)
+			}
+		}
+	}
+	assertSameResults(engine)
+
+	rows, err := engine.QueryRows(protocol.Request{Query: "SELECT count(*) FROM t_heap"})
+	require.NoError(t, err)
+	assert.Equal(t, "[[429]]", fmt.Sprintf("%v", rows.Values))
+
+	require.Error(t, engine.QueryError(protocol.Request{Query: "INSERT INTO t_heap (id) VALUES (1)"}))
+	require.Error(t, engine.QueryError(protocol.Request{Query: fmt.Sprintf("INSERT INTO t_heap (id, name) VALUES (2000, '%s')", strings.Repeat("x", 10000))}))
+	require.Error(t, engine.QueryError(protocol.Request{Query: "CREATE TABLE u (id integer) USING unknown"}))
+	require.NoError(t, engine.Close())
+
+	// Heap files are rebuilt from the write-ahead log
+	engine, err = OpenDefaultEngine(dataDirectory, WithHeapTables(4))
+	require.NoError(t, err)
+	defer engine.Close()
+	assertSameResults(engine)
+
+	entries, err = os.ReadDir(filepath.Join(dataDirectory, "heap"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
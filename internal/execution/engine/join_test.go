@@ -0,0 +1,179 @@
+package engine
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinStrategies(t *testing.T) {
+	session := newJoinTestSession(t)
+
+	for _, testCase := range []struct {
+		name     string
+		query    string
+		strategy string
+		expected int64
+	}{
+		{
+			name:     "large unfiltered relations",
+			query:    "SELECT count(*) FROM orders o JOIN items i ON i.order_id = o.id",
+			strategy: "hash",
+			expected: 1450,
+		},
+		{
+			name:     "non-equality condition",
+			query:    "SELECT count(*) FROM orders o JOIN customers c ON c.id = o.customer_id AND o.amount < c.id",
+			strategy: "hash",
+			expected: 750,
+		},
+		{
+			name:     "selective left relation",
+			query:    "SELECT count(*) FROM orders o JOIN customers c ON c.id = o.customer_id WHERE o.id = 5",
+			strategy: "nested loop",
+			expected: 1,
+		},
+		{
+			name:     "relations ordered by join keys",
+			query:    "SELECT count(*) FROM orders o JOIN items i ON i.order_id = o.id WHERE o.id < 250 AND i.order_id < 250",
+			strategy: "merge",
+			expected: 498,
+		},
+		{
+			name:     "no equality condition",
+			query:    "SELECT count(*) FROM customers c JOIN orders o ON o.amount < c.id",
+			strategy: "nested loop",
+			expected: 93994,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			plan, err := session.QueryRows(protocol.Request{Query: "EXPLAIN " + testCase.query})
+			require.NoError(t, err)
+			require.Len(t, plan.Values, 1)
+			assert.Contains(t, plan.Values[0][0], "join using "+testCase.strategy+" (")
+
+			assert.Equal(t, [][]any{{testCase.expected}}, query(t, session, testCase.query))
+		})
+	}
+}
+
+func TestHashJoinKeys(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE x (id integer PRIMARY KEY, t timestamp with time zone, f double precision)")
+	exec(t, session, "CREATE TABLE y (id integer PRIMARY KEY, t timestamp with time zone, f double precision)")
+
+	// Each instant is written with a different offset in each table, and each zero with
+	// a different sign
+	var xs, ys []string
+	for i := 0; i < 500; i++ {
+		xs = append(xs, fmt.Sprintf("(%d, '2024-01-01 %02d:%02d:00+00', 0)", i, i/60%22, i%60))
+		ys = append(ys, fmt.Sprintf("(%d, '2024-01-01 %02d:%02d:00+02', '-0')", i, i/60%22+2, i%60))
+	}
+	exec(t, session, "INSERT INTO x (id, t, f) VALUES "+strings.Join(xs, ", "))
+	exec(t, session, "INSERT INTO y (id, t, f) VALUES "+strings.Join(ys, ", "))
+
+	for _, q := range []string{
+		"SELECT count(*) FROM x JOIN y ON x.t = y.t",
+		"SELECT count(*) FROM x JOIN y ON x.id = y.id AND x.f = y.f",
+	} {
+		plan, err := session.QueryRows(protocol.Request{Query: "EXPLAIN " + q})
+		require.NoError(t, err)
+		assert.Contains(t, plan.Values[0][0], "join using hash (", q)
+		assert.Equal(t, [][]any{{int64(500)}}, query(t, session, q), q)
+	}
+
+	assert.Equal(t, [][]any{{int64(1)}}, query(t, session, "SELECT count(DISTINCT t) FROM (SELECT t FROM x WHERE id = 0 UNION ALL SELECT t FROM y WHERE id = 0) AS u"))
+	assert.Equal(t, [][]any{{int64(1)}}, query(t, session, "SELECT count(DISTINCT f) FROM (SELECT f FROM x UNION ALL SELECT f FROM y) AS u"))
+}
+
+func TestJoinOrdering(t *testing.T) {
+	for _, testCase := range []struct {
+		name    string
+		options []Option
+	}{
+		{name: "exhaustive"},
+		{name: "greedy", options: []Option{WithJoinSearchLimit(2)}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			session := newJoinTestSession(t, testCase.options...)
+
+			for _, q := range []string{
+				"SELECT count(*) FROM items i JOIN orders o ON i.order_id = o.id JOIN customers c ON o.customer_id = c.id WHERE c.id = 7",
+				"SELECT count(*) FROM items i, orders o, customers c WHERE i.order_id = o.id AND o.customer_id = c.id AND c.id = 7",
+			} {
+				plan, err := session.QueryRows(protocol.Request{Query: "EXPLAIN " + q})
+				require.NoError(t, err)
+				require.Len(t, plan.Values, 1)
+				explain := plan.Values[0][0].(string)
+
+				// The single selected customer is joined first, regardless of the
+				// order in which the relations are listed
+				assert.Less(t, strings.Index(explain, "scan of customers"), strings.Index(explain, "scan of orders"), explain)
+				assert.Less(t, strings.Index(explain, "scan of orders"), strings.Index(explain, "scan of items"), explain)
+				assert.Len(t, regexp.MustCompile(`join using [a-z ]+ \(rows=\d+ cost=\d+\.\d{2}\)\n`).FindAllString(explain, -1), 2, explain)
+
+				assert.Equal(t, [][]any{{int64(12)}}, query(t, session, q))
+			}
+		})
+	}
+}
+
+func TestOuterJoins(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE l (id integer PRIMARY KEY, k integer, x text)")
+	exec(t, session, "CREATE TABLE r (id integer PRIMARY KEY, k integer, y text)")
+	exec(t, session, "INSERT INTO l (id, k, x) VALUES (1, 1, 'l1'), (2, 2, 'l2'), (3, NULL, 'l3')")
+	exec(t, session, "INSERT INTO r (id, k, y) VALUES (1, 2, 'r2'), (2, 3, 'r3'), (3, NULL, 'r4')")
+
+	t.Run("errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query string
+			err   string
+		}{
+			{query: "SELECT * FROM l LEFT JOIN r", err: "expected ON or USING"},
+			{query: "SELECT * FROM l JOIN r USING (z)", err: `column "z" specified in USING clause does not exist in left table`},
+			{query: "SELECT * FROM l JOIN r USING (x)", err: `column "x" specified in USING clause does not exist in right table`},
+			{query: "SELECT * FROM l JOIN r ON l.k = r.k JOIN r r2 USING (k)", err: `common column name "k" appears more than once in left table`},
+			{query: "SELECT * FROM l JOIN r USING (k) WHERE r.k = 1", err: `unknown field "r"."k"`},
+			{query: "SELECT * FROM l LEFT JOIN r ON l.k = r.k FOR UPDATE", err: "FOR UPDATE cannot be applied to the nullable side of an outer join"},
+			{query: "SELECT * FROM l RIGHT JOIN r ON l.k = r.k FOR UPDATE OF l", err: "FOR UPDATE cannot be applied to the nullable side of an outer join"},
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: testCase.query})
+			require.Error(t, err, testCase.query)
+			assert.Contains(t, err.Error(), testCase.err, testCase.query)
+		}
+
+		// Rows of the preserved side may be locked
+		assert.Len(t, query(t, session, "SELECT x FROM l LEFT JOIN r ON l.k = r.k FOR UPDATE OF l"), 3)
+	})
+}
+
+// newJoinTestSession creates a session with customers, their orders, and the items
+// of each order.
+func newJoinTestSession(t *testing.T, options ...Option) *Session {
+	session := NewDefaultEngine(options...).NewSession()
+	exec(t, session, "CREATE TABLE customers (id integer PRIMARY KEY, name text)")
+	exec(t, session, "CREATE TABLE orders (id integer PRIMARY KEY, customer_id integer, amount integer)")
+	exec(t, session, "CREATE INDEX orders_customer_id_idx ON orders (customer_id)")
+	exec(t, session, "CREATE TABLE items (id integer PRIMARY KEY, order_id integer)")
+	exec(t, session, "CREATE INDEX items_order_id_idx ON items (order_id)")
+
+	insert := func(table string, n int, format func(i int) string) {
+		var values []string
+		for i := 1; i <= n; i++ {
+			values = append(values, format(i))
+		}
+
+		exec(t, session, fmt.Sprintf("INSERT INTO %s VALUES %s", table, strings.Join(values, ", ")))
+	}
+	insert("customers (id, name)", 100, func(i int) string { return fmt.Sprintf("(%d, 'c%d')", i, i) })
+	insert("orders (id, customer_id, amount)", 1000, func(i int) string { return fmt.Sprintf("(%d, %d, %d)", i, i%125+1, i%13) })
+	insert("items (id, order_id)", 1500, func(i int) string { return fmt.Sprintf("(%d, %d)", i, i%1050) })
+
+	return session
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONB(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE events (id integer PRIMARY KEY, payload jsonb)")
+	exec(t, session, `INSERT INTO events (id, payload) VALUES
+		(1, '{"type": "click", "user": {"id": 7, "name": "ada"}, "tags": ["a", "b"], "type": "view"}'),
+		(2, '[1, "two", null, {"k": 3}]'),
+		(3, NULL),
+		(4, '"a"')`)
+
+	t.Run("untyped literals", func(t *testing.T) {
+		// String literals adopt the type for which each operator is defined
+		for _, testCase := range []struct {
+			query    string
+			expected [][]any
+		}{
+			{query: `SELECT id FROM events WHERE payload @> '{"user": {"id": 7}}'`, expected: [][]any{{int32(1)}}},
+			{query: `SELECT id FROM events WHERE payload <@ '["a", "b"]'`, expected: [][]any{{int32(4)}}},
+			{query: "SELECT payload -> 'user' ->> 'name' FROM events WHERE id = 1", expected: [][]any{{"ada"}}},
+			{query: "SELECT payload #>> '{user,name}' FROM events WHERE id = 1", expected: [][]any{{"ada"}}},
+			{query: "SELECT (payload #> '{user,id}')::integer FROM events WHERE id = 1", expected: [][]any{{int32(7)}}},
+			{query: "SELECT id FROM events WHERE payload ? 'tags'", expected: [][]any{{int32(1)}}},
+			{query: "SELECT id FROM events WHERE payload ?| '{x,a}' ORDER BY id", expected: [][]any{{int32(4)}}},
+			{query: "SELECT id FROM events WHERE payload ?& '{tags,user}'", expected: [][]any{{int32(1)}}},
+			{query: `SELECT k, v::integer FROM jsonb_each('{"a": 1}') AS e (k, v)`, expected: [][]any{{"a", int32(1)}}},
+			{query: "SELECT count(*) FROM jsonb_array_elements('[1, 2]')", expected: [][]any{{int64(2)}}},
+		} {
+			assert.Equal(t, testCase.expected, query(t, session, testCase.query), testCase.query)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query string
+			err   string
+		}{
+			{query: `SELECT '{"a": }'::jsonb`, err: `invalid input syntax for type jsonb: "{\"a\": }"`},
+			{query: "INSERT INTO events (id, payload) VALUES (5, '[1')", err: "invalid input syntax for type jsonb: \"[1\""},
+			{query: "SELECT payload @> 1 FROM events", err: "illegal operand types for @>: jsonb and integer"},
+			{query: "SELECT payload -> 1.5 FROM events", err: "illegal operand types for ->: jsonb and double precision"},
+			{query: "SELECT payload #> 'a' FROM events", err: "illegal operand types for #>: jsonb and text"},
+			{query: "SELECT payload ? 1 FROM events", err: "illegal operand types for ?: jsonb and integer"},
+			{query: "SELECT id ? 'a' FROM events", err: "illegal operand types for ?: integer and text"},
+			{query: "SELECT (payload->'type')::integer FROM events WHERE id = 1", err: "cannot cast jsonb string to type integer"},
+			{query: "SELECT jsonb_build_object('a')", err: "jsonb_build_object expects an even number of arguments, got 1"},
+			{query: "SELECT jsonb_build_object(NULL, 1)", err: "argument 1 to jsonb_build_object must not be NULL"},
+			{query: "SELECT * FROM jsonb_each('[1]')", err: "cannot call jsonb_each on a non-object"},
+			{query: "SELECT * FROM jsonb_array_elements('{}')", err: "cannot extract elements from a non-array"},
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: testCase.query})
+			require.Error(t, err, testCase.query)
+			assert.Contains(t, err.Error(), testCase.err, testCase.query)
+		}
+	})
+}
+
+func TestJSONBInvertedIndex(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE docs (id integer PRIMARY KEY, body jsonb)")
+	for i := 1; i <= 200; i++ {
+		exec(t, session, fmt.Sprintf(`INSERT INTO docs (id, body) VALUES (%d, '{"kind": "k%d", "tags": ["t%d"], "meta": {"kind": "m%d"}}')`, i, i%10, i%3, i%7))
+	}
+	exec(t, session, "INSERT INTO docs (id, body) VALUES (201, NULL), (202, '[\"kind\"]')")
+	exec(t, session, "CREATE INDEX docs_body ON docs USING gin (body)")
+	exec(t, session, "ANALYZE docs")
+
+	explain := func(t *testing.T, query string) string {
+		rows, err := session.QueryRows(protocol.Request{Query: "EXPLAIN " + query})
+		require.NoError(t, err)
+		return rows.Values[0][0].(string)
+	}
+
+	t.Run("containment", func(t *testing.T) {
+		q := `SELECT id FROM docs WHERE body @> '{"kind": "k4", "tags": ["t2"]}' ORDER BY id`
+		assert.Contains(t, explain(t, q), "gin index scan of docs via docs_body")
+		assert.Equal(t, [][]any{{int32(14)}, {int32(44)}, {int32(74)}, {int32(104)}, {int32(134)}, {int32(164)}, {int32(194)}}, query(t, session, q))
+	})
+
+	t.Run("nested values are rechecked", func(t *testing.T) {
+		q := `SELECT id FROM docs WHERE body @> '{"kind": "m3"}'`
+		assert.Contains(t, explain(t, q), "gin index scan of docs via docs_body")
+		assert.Empty(t, query(t, session, q))
+	})
+
+	t.Run("key existence", func(t *testing.T) {
+		q := "SELECT id FROM docs WHERE body ? 'kind' AND body @> '[]'"
+		assert.Contains(t, explain(t, q), "gin index scan of docs via docs_body")
+		assert.Equal(t, [][]any{{int32(202)}}, query(t, session, q))
+	})
+
+	t.Run("updates", func(t *testing.T) {
+		exec(t, session, `UPDATE docs SET body = '{"kind": "moved"}' WHERE id = 44`)
+		exec(t, session, "DELETE FROM docs WHERE id = 74")
+
+		assert.Equal(t, [][]any{{int32(44)}}, query(t, session, `SELECT id FROM docs WHERE body @> '{"kind": "moved"}'`))
+		assert.Equal(t, [][]any{{int32(14)}, {int32(104)}, {int32(134)}, {int32(164)}, {int32(194)}}, query(t, session, `SELECT id FROM docs WHERE body @> '{"kind": "k4", "tags": ["t2"]}' ORDER BY id`))
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		exec(t, session, "CREATE INDEX docs_small ON docs USING gin (body) WHERE id < 10")
+		q := `SELECT id FROM docs WHERE body @> '{"tags": ["t1"]}' AND id < 10 ORDER BY id`
+		assert.Equal(t, [][]any{{int32(1)}, {int32(4)}, {int32(7)}}, query(t, session, q))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query string
+			err   string
+		}{
+			{query: "CREATE INDEX docs_id ON docs USING gin (id)", err: `gin index "docs_id" requires values of type jsonb, got integer`},
+			{query: "CREATE UNIQUE INDEX docs_unique ON docs USING gin (body)", err: "gin index do not support uniqueness"},
+			{query: "CREATE INDEX docs_multi ON docs USING gin (body, body)", err: "gin index must have exactly one column"},
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: testCase.query})
+			require.Error(t, err, testCase.query)
+			assert.Contains(t, err.Error(), testCase.err, testCase.query)
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternMatching(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE words (id integer PRIMARY KEY, word text)")
+	exec(t, session, "CREATE INDEX words_word_idx ON words (word)")
+	exec(t, session, `INSERT INTO words (id, word) VALUES (1, 'apple'), (2, 'Apricot'), (3, 'banana'), (4, '50%'), (5, 'a_b'), (6, 'abc'), (7, NULL)`)
+
+	t.Run("plans", func(t *testing.T) {
+		// Left-anchored patterns are bounded by the index, and re-checked afterwards
+		plan, err := session.QueryRows(protocol.Request{Query: "EXPLAIN SELECT id FROM words WHERE word LIKE 'ap%'"})
+		require.NoError(t, err)
+		assert.Contains(t, plan.Values[0][0].(string), "btree index scan of words via words_word_idx")
+		assert.Contains(t, plan.Values[0][0].(string), "index cond: words.word >= ap and words.word < aq")
+		assert.Contains(t, plan.Values[0][0].(string), "filter by words.word like ap%")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query string
+			err   string
+		}{
+			{query: `SELECT 'abc' LIKE 'ab\'`, err: "LIKE pattern must not end with escape character"},
+			{query: "SELECT 'abc' LIKE 'a%' ESCAPE '!!'", err: "invalid escape string"},
+			{query: "SELECT 'abc' ~ '('", err: "invalid regular expression"},
+			{query: "SELECT id FROM words WHERE id LIKE '1%'", err: "illegal operand types for like"},
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: testCase.query})
+			require.Error(t, err, testCase.query)
+			assert.Contains(t, err.Error(), testCase.err, testCase.query)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreparedStatementReplanning(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+
+	t.Run("recreated table", func(t *testing.T) {
+		// The table is removed when the transaction creating it rolls back
+		exec(t, session, "BEGIN")
+		exec(t, session, "CREATE TABLE t (id integer PRIMARY KEY, n integer)")
+		exec(t, session, "INSERT INTO t (id, n) VALUES (1, 10)")
+		exec(t, session, "PREPARE ns AS SELECT n FROM t ORDER BY id")
+		assert.Equal(t, [][]any{{int32(10)}}, query(t, session, "EXECUTE ns"))
+		exec(t, session, "ROLLBACK")
+
+		assert.ErrorContains(t, session.QueryError(protocol.Request{Query: "EXECUTE ns"}), `unknown table "t"`)
+
+		exec(t, session, "CREATE TABLE t (id integer PRIMARY KEY, n integer)")
+		exec(t, session, "INSERT INTO t (id, n) VALUES (1, 20), (2, 30)")
+		assert.Equal(t, [][]any{{int32(20)}, {int32(30)}}, query(t, session, "EXECUTE ns"))
+	})
+
+	t.Run("created index", func(t *testing.T) {
+		statement, err := session.Prepare("plan", "EXPLAIN SELECT id FROM t WHERE n = $1", nil)
+		require.NoError(t, err)
+
+		explain := func() string {
+			collector := protocol.NewRowCollector()
+			session.Execute(statement, []any{int32(30)}, collector)
+			plan, err := collector.Rows()
+			require.NoError(t, err)
+			return plan.Values[0][0].(string)
+		}
+		assert.Contains(t, explain(), "table scan of t")
+
+		exec(t, session, "CREATE INDEX t_n_idx ON t (n)")
+		assert.Contains(t, explain(), "index scan of t via t_n_idx")
+	})
+
+	t.Run("changed result type", func(t *testing.T) {
+		exec(t, session, "BEGIN")
+		exec(t, session, "CREATE TABLE u (id integer PRIMARY KEY)")
+		exec(t, session, "PREPARE ids AS SELECT * FROM u")
+		exec(t, session, "ROLLBACK")
+
+		exec(t, session, "CREATE TABLE u (id text PRIMARY KEY)")
+		assert.ErrorContains(t, session.QueryError(protocol.Request{Query: "EXECUTE ids"}), "cached plan must not change result type")
+	})
+}
@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/efritz/gostgres/internal/execution/queries/ddl"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/storage/wal"
+	"github.com/efritz/gostgres/internal/syntax/lexing"
+	"github.com/efritz/gostgres/internal/syntax/parsing"
+)
+
+// recover restores the state recorded in the data directory: the latest checkpoint (if
+// any) followed by the changes of the transactions logged after it, in commit order.
+// Changes are applied outside of any transaction, so they are visible to every
+// transaction and are not logged again.
+func (e *Engine) recover() error {
+	ctx := impls.NewExecutionContext(e.catalog)
+
+	var lsn int64
+	if file, err := os.Open(filepath.Join(e.dataDirectory, checkpointFilename)); err == nil {
+		lsn, err = wal.ReadSnapshot(file, func(record wal.Record) error {
+			return e.recoverRecord(ctx, record)
+		})
+		_ = file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to recover from checkpoint: %s", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	log, transactions, err := wal.Open(filepath.Join(e.dataDirectory, "wal"))
+	if err != nil {
+		return err
+	}
+
+	if base := log.Base(); base > lsn {
+		_ = log.Close()
+		return fmt.Errorf("write-ahead log begins at LSN %d after the checkpoint at LSN %d", base, lsn)
+	}
+
+	for i, records := range transactions {
+		if log.Base()+int64(i) < lsn {
+			// Reflected in the checkpoint
+			continue
+		}
+
+		for _, record := range records {
+			if err := e.recoverRecord(ctx, record); err != nil {
+				_ = log.Close()
+				return fmt.Errorf("failed to recover from write-ahead log: %s", err)
+			}
+		}
+	}
+
+	e.changes.log = log
+	return nil
+}
+
+// recoverRecord applies the change described by the given record. Committed DDL
+// statements are retained for future snapshots.
+func (e *Engine) recoverRecord(ctx impls.ExecutionContext, record wal.Record) error {
+	switch record.Type {
+	case wal.RecordTypeInsert:
+		table, err := recoveredTable(ctx, record.Name)
+		if err != nil {
+			return err
+		}
+
+		return table.Restore(ctx, record.TID, record.Values)
+
+	case wal.RecordTypeDelete:
+		table, err := recoveredTable(ctx, record.Name)
+		if err != nil {
+			return err
+		}
+
+		row, ok, err := table.Row(ctx, record.TID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("unknown row %d in table %q", record.TID, record.Name)
+		}
+
+		_, _, err = table.Delete(ctx, row)
+		return err
+
+	case wal.RecordTypeSequenceAdvance, wal.RecordTypeSequenceSet:
+		sequence, ok := ctx.Catalog().Sequences.Get(record.Name)
+		if !ok {
+			return fmt.Errorf("unknown sequence %q", record.Name)
+		}
+
+		if record.Type == wal.RecordTypeSequenceAdvance && sequence.Value() >= record.Value {
+			// Advanced further by a transaction that committed earlier
+			return nil
+		}
+
+		return sequence.Set(ctx, record.Value)
+
+	case wal.RecordTypeDDL:
+		if err := executeDDL(ctx, record.Statement); err != nil {
+			return err
+		}
+
+		e.changes.addStatement(record.Statement)
+		return nil
+	}
+
+	return fmt.Errorf("unknown record type %d", record.Type)
+}
+
+// executeDDL parses and executes the given logged DDL statement.
+func executeDDL(ctx impls.ExecutionContext, statement string) error {
+	query, err := parsing.Parse(ctx.Catalog(), lexing.Lex(statement))
+	if err != nil {
+		return fmt.Errorf("failed to parse %q: %s", statement, err)
+	}
+
+	ddlQuery, ok := query.(ddl.DDLQuery)
+	if !ok {
+		return fmt.Errorf("%q is not a DDL statement", statement)
+	}
+
+	return ddlQuery.ExecuteDDL(ctx)
+}
+
+func recoveredTable(ctx impls.ExecutionContext, name string) (impls.Table, error) {
+	table, ok := ctx.Catalog().Tables.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown table %q", name)
+	}
+
+	return table, nil
+}
@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecovery(t *testing.T) {
+	dataDirectory := t.TempDir()
+	engine, err := OpenDefaultEngine(dataDirectory)
+	require.NoError(t, err)
+
+	session := engine.NewSession()
+	type checkpoint struct {
+		size  int64
+		state string
+	}
+	checkpoints := []checkpoint{{state: recoveryState(engine)}}
+
+	for _, query := range []string{
+		"CREATE TABLE one (x integer)",
+		"INSERT INTO one (x) VALUES (1)",
+		"CREATE SEQUENCE t_id_seq",
+		"CREATE TABLE t (id bigint PRIMARY KEY DEFAULT nextval('t_id_seq'), name text NOT NULL, score numeric, active boolean)",
+		"INSERT INTO t (name, score, active) VALUES ('a', 1.5, true), ('b', NULL, false)",
+		"BEGIN",
+		"INSERT INTO t (name) VALUES ('c')",
+		"UPDATE t SET score = 2.5 WHERE name = 'a'",
+		"SAVEPOINT s",
+		"DELETE FROM t WHERE name = 'b'",
+		"ROLLBACK TO SAVEPOINT s",
+		"CREATE UNIQUE INDEX t_name_idx ON t (name)",
+		"COMMIT",
+		"BEGIN",
+		"INSERT INTO t (id, name) VALUES (100, 'rolled back')",
+		"ROLLBACK",
+		"ALTER TABLE t ADD CONSTRAINT t_id_check CHECK (id < 1000)",
+		"DELETE FROM t WHERE name = 'c'",
+		"SELECT setval('t_id_seq', 10) FROM one",
+		"INSERT INTO t (name, score) VALUES ('d', 3)",
+	} {
+		require.NoError(t, session.QueryError(protocol.Request{Query: query}), query)
+		if session.TransactionStatus() != transaction.StatusIdle {
+			// Sequences are not transactional, so the state within a transaction block is not
+			// necessarily the state of any committed transaction
+			continue
+		}
+
+		info, err := os.Stat(filepath.Join(dataDirectory, "wal"))
+		require.NoError(t, err)
+		checkpoints = append(checkpoints, checkpoint{size: info.Size(), state: recoveryState(engine)})
+	}
+	require.NoError(t, engine.Close())
+
+	data, err := os.ReadFile(filepath.Join(dataDirectory, "wal"))
+	require.NoError(t, err)
+
+	for size := 0; size <= len(data); size++ {
+		// The state of the last transaction committed in full is recovered
+		expected := checkpoints[0].state
+		for _, checkpoint := range checkpoints {
+			if checkpoint.size <= int64(size) {
+				expected = checkpoint.state
+			}
+		}
+
+		truncatedDirectory := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(truncatedDirectory, "wal"), data[:size], 0o644))
+
+		engine, err := OpenDefaultEngine(truncatedDirectory)
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, expected, recoveryState(engine), "size %d", size)
+		require.NoError(t, engine.Close())
+	}
+
+	// Rows recovered from the log can be changed and recovered again
+	for i := 0; i < 2; i++ {
+		engine, err := OpenDefaultEngine(dataDirectory)
+		require.NoError(t, err)
+		require.NoError(t, engine.QueryError(protocol.Request{Query: "UPDATE t SET score = score + 1 WHERE name = 'a'"}))
+		require.NoError(t, engine.QueryError(protocol.Request{Query: fmt.Sprintf("INSERT INTO t (name) VALUES ('e%d')", i)}))
+		require.Error(t, engine.QueryError(protocol.Request{Query: "INSERT INTO t (id, name) VALUES (2000, 'f')"}))
+		require.NoError(t, engine.Close())
+	}
+
+	engine, err = OpenDefaultEngine(dataDirectory)
+	require.NoError(t, err)
+	defer engine.Close()
+
+	rows, err := engine.QueryRows(protocol.Request{Query: "SELECT id, name, score FROM t ORDER BY id"})
+	require.NoError(t, err)
+	assert.Equal(t, "[[1 a 4.5] [2 b <nil>] [11 d 3] [12 e0 <nil>] [13 e1 <nil>]]", fmt.Sprintf("%v", rows.Values))
+}
+
+// recoveryState describes the committed contents of the database modified by TestRecovery.
+func recoveryState(engine *Engine) string {
+	rows, err := engine.QueryRows(protocol.Request{Query: "SELECT id, name, score, active FROM t ORDER BY id"})
+	if err != nil {
+		return err.Error()
+	}
+
+	sequence, err := engine.QueryRows(protocol.Request{Query: "SELECT currval('t_id_seq') FROM one"})
+	if err != nil {
+		return err.Error()
+	}
+
+	return fmt.Sprintf("%v %v", rows.Values, sequence.Values)
+}
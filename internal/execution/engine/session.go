@@ -0,0 +1,211 @@
+package engine
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"github.com/efritz/gostgres/internal/catalog"
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/execution/queries/ddl"
+	"github.com/efritz/gostgres/internal/execution/queries/prepared"
+	"github.com/efritz/gostgres/internal/execution/transaction"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/efritz/gostgres/internal/syntax/lexing"
+	"github.com/efritz/gostgres/internal/syntax/parsing"
+)
+
+// Session holds state that persists between queries issued over the same
+// connection, such as prepared statements and the open transaction block.
+type Session struct {
+	engine             *Engine
+	preparedStatements *catalog.Catalog[impls.PreparedStatement]
+	transactions       *transaction.Manager
+}
+
+func (e *Engine) NewSession() *Session {
+	return &Session{
+		engine:             e,
+		preparedStatements: catalog.NewCatalog[impls.PreparedStatement](),
+		transactions:       transaction.NewManager(e.transactions),
+	}
+}
+
+// Close ends the session, rolling back its open transaction block (if any) and
+// releasing the locks it holds. The session should not be used after it is closed.
+func (s *Session) Close() {
+	s.transactions.Close()
+}
+
+func (s *Session) TransactionStatus() transaction.Status {
+	return s.transactions.Status()
+}
+
+// BeginImplicitBlock groups the statements executed until EndImplicitBlock into a
+// single implicit transaction, as for the statements of a simple Query message.
+func (s *Session) BeginImplicitBlock() {
+	s.transactions.BeginImplicitBlock()
+}
+
+// EndImplicitBlock ends the implicit block, committing its implicit transaction unless
+// the given error (if any) stopped the execution of its statements.
+func (s *Session) EndImplicitBlock(err error) error {
+	return s.transactions.EndImplicitBlock(err)
+}
+
+func (s *Session) Query(request protocol.Request, responseWriter protocol.ResponseWriter) {
+	query, _, err := s.parse(request.Query, nil)
+	if err != nil {
+		s.execute(nil, request.Debug, responseWriter, func(_ impls.ExecutionContext, w protocol.ResponseWriter) {
+			w.Error(fmt.Errorf("failed to parse query: %s", err))
+		})
+
+		return
+	}
+
+	s.execute(query, request.Debug, responseWriter, query.Execute)
+}
+
+// parse parses the given query. DDL statements are wrapped so that their source text
+// is written to the write-ahead log when they execute.
+func (s *Session) parse(query string, parameterTypes []types.Type) (queries.Query, []types.Type, error) {
+	parsed, inferredTypes, err := parsing.ParseWithParameterTypes(s.engine.catalog, lexing.Lex(query), parameterTypes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ddlQuery, ok := parsed.(ddl.DDLQuery); ok {
+		parsed = ddl.NewLogged(ddlQuery, query)
+	}
+
+	return parsed, inferredTypes, nil
+}
+
+func (s *Session) QueryRows(request protocol.Request) (rows.Rows, error) {
+	collector := protocol.NewRowCollector()
+	s.Query(request, collector)
+	collectedRows, err := collector.Rows()
+	if err != nil {
+		return rows.Rows{}, fmt.Errorf("failed to execute query %q: %s", request.Query, err)
+	}
+
+	return collectedRows, nil
+}
+
+func (s *Session) QueryError(request protocol.Request) error {
+	collector := protocol.NewRowCollector()
+	s.Query(request, collector)
+	_, err := collector.Rows()
+	return err
+}
+
+// Prepare parses and registers a prepared statement with the given name. Types of
+// parameters not given in parameterTypes (or given as types.TypeUnknown) are inferred.
+// The unnamed statement may be replaced freely; named statements must be deallocated
+// before their name can be reused.
+func (s *Session) Prepare(name, query string, parameterTypes []types.Type) (impls.PreparedStatement, error) {
+	if _, ok := s.preparedStatements.Get(name); ok && name != "" {
+		return nil, fmt.Errorf("prepared statement %q already exists", name)
+	}
+
+	catalogVersion := s.engine.catalog.Version()
+	parsed, inferredTypes, err := s.parse(query, parameterTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse query: %s", err)
+	}
+
+	statement := prepared.NewPreparedStatement(parsed, inferredTypes, catalogVersion, func() (queries.Query, error) {
+		parsed, _, err := s.parse(query, inferredTypes)
+		return parsed, err
+	})
+	s.preparedStatements.Set(name, statement)
+	return statement, nil
+}
+
+func (s *Session) PreparedStatement(name string) (impls.PreparedStatement, bool) {
+	return s.preparedStatements.Get(name)
+}
+
+func (s *Session) Deallocate(name string) {
+	s.preparedStatements.Delete(name)
+}
+
+func (s *Session) Execute(statement impls.PreparedStatement, parameters []any, responseWriter protocol.ResponseWriter) {
+	s.execute(statement, false, responseWriter, func(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+		statement.Execute(ctx, w, parameters)
+	})
+}
+
+var errFailedTransaction = fmt.Errorf("current transaction is aborted, commands ignored until end of transaction block")
+
+// execute invokes f within the session's current transaction. If no transaction block
+// is open, the statement executes in an implicit transaction that is committed if the
+// statement succeeds and rolled back otherwise. A statement that panics fails with an
+// error describing the panic.
+func (s *Session) execute(query any, debug bool, responseWriter protocol.ResponseWriter, f func(ctx impls.ExecutionContext, w protocol.ResponseWriter)) {
+	tx := s.transactions.StartStatement()
+
+	if tx.Failed() && !queries.AllowedInFailedTransaction(query) {
+		s.transactions.EndStatement(errFailedTransaction)
+		responseWriter.Error(errFailedTransaction)
+		return
+	}
+
+	w := &errorRecorder{ResponseWriter: responseWriter}
+	protocol.Describe(w, queries.Command(query), queries.Fields(query))
+	executeStatement(s.executionContext(tx, debug), w, f)
+
+	if err := s.transactions.EndStatement(w.err); err != nil {
+		// The statement's response may have already completed, in which case the
+		// response writer may not deliver the error
+		responseWriter.Error(err)
+	}
+}
+
+// executeStatement invokes f, reporting a panic as an error of the statement so that
+// its changes are reverted before the transaction is used by another statement. The
+// stack of the panic is logged rather than reported.
+func executeStatement(ctx impls.ExecutionContext, w protocol.ResponseWriter, f func(ctx impls.ExecutionContext, w protocol.ResponseWriter)) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("query execution panic: %v\n%s", r, debug.Stack())
+			w.Error(fmt.Errorf("query execution panic: %v", r))
+		}
+	}()
+
+	f(ctx, w)
+}
+
+func (s *Session) executionContext(tx impls.Transaction, debug bool) impls.ExecutionContext {
+	ctx := impls.NewExecutionContext(s.engine.catalog).
+		WithPreparedStatements(s.preparedStatements).
+		WithTransaction(tx).
+		WithTransactions(s.transactions).
+		WithCheckpointer(s.engine).
+		WithJoinSearchLimit(s.engine.joinSearchLimit)
+
+	if debug {
+		ctx = ctx.WithDebug()
+	}
+
+	return ctx
+}
+
+// errorRecorder captures the error (if any) reported by a statement.
+type errorRecorder struct {
+	protocol.ResponseWriter
+	err error
+}
+
+func (w *errorRecorder) Describe(command string, fields []fields.Field) {
+	protocol.Describe(w.ResponseWriter, command, fields)
+}
+
+func (w *errorRecorder) Error(err error) {
+	w.err = err
+	w.ResponseWriter.Error(err)
+}
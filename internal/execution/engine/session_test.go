@@ -0,0 +1,354 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/transaction"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionTransactions(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		queries  []string
+		errors   []int // indexes of queries expected to fail
+		expected [][]any
+	}{
+		{
+			name: "failed statement rolls back implicit transaction",
+			queries: []string{
+				"INSERT INTO t (id) VALUES (3), (4), (1)",
+			},
+			errors:   []int{0},
+			expected: [][]any{{int32(1)}, {int32(2)}},
+		},
+		{
+			name: "commit",
+			queries: []string{
+				"BEGIN",
+				"INSERT INTO t (id) VALUES (3)",
+				"DELETE FROM t WHERE id = 1",
+				"COMMIT",
+			},
+			expected: [][]any{{int32(2)}, {int32(3)}},
+		},
+		{
+			name: "rollback",
+			queries: []string{
+				"BEGIN",
+				"INSERT INTO t (id) VALUES (3)",
+				"UPDATE t SET id = 10 WHERE id = 1",
+				"DELETE FROM t WHERE id = 2",
+				"ROLLBACK",
+			},
+			expected: [][]any{{int32(1)}, {int32(2)}},
+		},
+		{
+			name: "failed transaction block",
+			queries: []string{
+				"BEGIN",
+				"INSERT INTO t (id) VALUES (3)",
+				"INSERT INTO t (id) VALUES (1)",
+				"INSERT INTO t (id) VALUES (4)",
+				"COMMIT",
+			},
+			errors:   []int{2, 3},
+			expected: [][]any{{int32(1)}, {int32(2)}},
+		},
+		{
+			name: "savepoints",
+			queries: []string{
+				"START TRANSACTION",
+				"INSERT INTO t (id) VALUES (3)",
+				"SAVEPOINT s",
+				"INSERT INTO t (id) VALUES (4)",
+				"INSERT INTO t (id) VALUES (2)",
+				"ROLLBACK TO SAVEPOINT s",
+				"INSERT INTO t (id) VALUES (5)",
+				"RELEASE s",
+				"END",
+			},
+			errors:   []int{4},
+			expected: [][]any{{int32(1)}, {int32(2)}, {int32(3)}, {int32(5)}},
+		},
+		{
+			name: "transactional ddl",
+			queries: []string{
+				"BEGIN",
+				"CREATE TABLE u (id integer)",
+				"CREATE UNIQUE INDEX t_id_idx ON t (id)",
+				"ALTER TABLE t ADD CONSTRAINT t_id_check CHECK (id < 100)",
+				"ROLLBACK",
+				"INSERT INTO t (id) VALUES (200)",
+				"DELETE FROM t WHERE id = 200",
+			},
+			expected: [][]any{{int32(1)}, {int32(2)}},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			engine := NewDefaultEngine()
+			require.NoError(t, engine.QueryError(protocol.Request{Query: "CREATE TABLE t (id integer PRIMARY KEY)"}))
+			require.NoError(t, engine.QueryError(protocol.Request{Query: "INSERT INTO t (id) VALUES (1), (2)"}))
+
+			session := engine.NewSession()
+			for i, query := range testCase.queries {
+				err := session.QueryError(protocol.Request{Query: query})
+				if slices.Contains(testCase.errors, i) {
+					assert.Error(t, err, query)
+				} else {
+					assert.NoError(t, err, query)
+				}
+			}
+			assert.Equal(t, transaction.StatusIdle, session.TransactionStatus())
+
+			rows, err := engine.QueryRows(protocol.Request{Query: "SELECT id FROM t ORDER BY id"})
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, rows.Values)
+
+			_, ok := engine.catalog.Tables.Get("u")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestSessionIsolation(t *testing.T) {
+	engine := NewDefaultEngine()
+	require.NoError(t, engine.QueryError(protocol.Request{Query: "CREATE TABLE t (id integer PRIMARY KEY)"}))
+	require.NoError(t, engine.QueryError(protocol.Request{Query: "INSERT INTO t (id) VALUES (1), (2)"}))
+
+	a := engine.NewSession()
+	b := engine.NewSession()
+	c := engine.NewSession()
+
+	exec(t, a, "BEGIN")
+	exec(t, a, "INSERT INTO t (id) VALUES (3)")
+	exec(t, a, "UPDATE t SET id = 10 WHERE id = 1")
+	assert.Equal(t, [][]any{{int32(2)}, {int32(3)}, {int32(10)}}, query(t, a, "SELECT id FROM t ORDER BY id"))
+
+	// Uncommitted changes are not visible to other sessions
+	exec(t, b, "BEGIN")
+	assert.Equal(t, [][]any{{int32(1)}, {int32(2)}}, query(t, b, "SELECT id FROM t ORDER BY id"))
+	assert.Equal(t, [][]any{{int32(1)}}, query(t, b, "SELECT id FROM t WHERE id = 1"))
+
+	// Deleting a row deleted by a concurrent transaction waits for that transaction to end
+	result := make(chan error)
+	go func() { result <- b.QueryError(protocol.Request{Query: "DELETE FROM t WHERE id = 1"}) }()
+
+	exec(t, c, "BEGIN")
+	assert.Equal(t, [][]any{{int32(1)}, {int32(2)}}, query(t, c, "SELECT id FROM t ORDER BY id"))
+	exec(t, a, "COMMIT")
+	assert.ErrorContains(t, <-result, "could not serialize access due to concurrent update")
+	exec(t, b, "ROLLBACK")
+
+	// Transactions continue to read from the snapshot taken when they began
+	assert.Equal(t, [][]any{{int32(1)}, {int32(2)}}, query(t, c, "SELECT id FROM t ORDER BY id"))
+	exec(t, c, "COMMIT")
+	assert.Equal(t, [][]any{{int32(2)}, {int32(3)}, {int32(10)}}, query(t, c, "SELECT id FROM t ORDER BY id"))
+
+	// Versions deleted by committed transactions do not conflict with new ones
+	exec(t, b, "INSERT INTO t (id) VALUES (1)")
+	assert.Equal(t, [][]any{{int32(1)}, {int32(2)}, {int32(3)}, {int32(10)}}, query(t, a, "SELECT id FROM t ORDER BY id"))
+
+	// Versions no longer visible to any transaction are removed
+	exec(t, a, "VACUUM t")
+	exec(t, a, "VACUUM")
+	assert.Equal(t, [][]any{{int32(1)}, {int32(2)}, {int32(3)}, {int32(10)}}, query(t, a, "SELECT id FROM t ORDER BY id"))
+	assert.Equal(t, [][]any{{int32(10)}}, query(t, a, "SELECT id FROM t WHERE id = 10"))
+}
+
+func TestSessionLocking(t *testing.T) {
+	engine := NewDefaultEngine()
+	require.NoError(t, engine.QueryError(protocol.Request{Query: "CREATE TABLE t (id integer PRIMARY KEY, n integer)"}))
+	require.NoError(t, engine.QueryError(protocol.Request{Query: "INSERT INTO t (id, n) VALUES (1, 0), (2, 0)"}))
+
+	a := engine.NewSession()
+	b := engine.NewSession()
+
+	// Row locks taken by SELECT ... FOR UPDATE block concurrent writers
+	exec(t, a, "BEGIN")
+	assert.Equal(t, [][]any{{int32(0)}}, query(t, a, "SELECT n FROM t WHERE id = 1 FOR UPDATE"))
+
+	result := make(chan error)
+	go func() { result <- b.QueryError(protocol.Request{Query: "UPDATE t SET n = n + 1 WHERE id = 1"}) }()
+	assertBlocked(t, result)
+
+	exec(t, a, "COMMIT")
+	require.NoError(t, <-result)
+
+	// Explicit table locks block conflicting table locks of other sessions
+	exec(t, a, "BEGIN")
+	exec(t, a, "LOCK TABLE t IN ACCESS EXCLUSIVE MODE")
+
+	go func() { result <- b.QueryError(protocol.Request{Query: "SELECT * FROM t"}) }()
+	assertBlocked(t, result)
+
+	exec(t, a, "ROLLBACK")
+	require.NoError(t, <-result)
+
+	// Sessions waiting on one another's row locks are detected as a deadlock
+	exec(t, a, "BEGIN")
+	exec(t, b, "BEGIN")
+	exec(t, a, "UPDATE t SET n = 10 WHERE id = 1")
+	exec(t, b, "UPDATE t SET n = 20 WHERE id = 2")
+
+	go func() { result <- a.QueryError(protocol.Request{Query: "UPDATE t SET n = 10 WHERE id = 2"}) }()
+	assertBlocked(t, result)
+
+	assert.ErrorContains(t, b.QueryError(protocol.Request{Query: "UPDATE t SET n = 20 WHERE id = 1"}), "deadlock detected")
+	exec(t, b, "ROLLBACK")
+	require.NoError(t, <-result)
+	exec(t, a, "COMMIT")
+
+	assert.Equal(t, [][]any{{int32(1), int32(10)}, {int32(2), int32(10)}}, query(t, b, "SELECT id, n FROM t ORDER BY id"))
+}
+
+func TestSessionUniqueConflicts(t *testing.T) {
+	engine := NewDefaultEngine()
+	require.NoError(t, engine.QueryError(protocol.Request{Query: "CREATE TABLE t (id integer PRIMARY KEY)"}))
+	require.NoError(t, engine.QueryError(protocol.Request{Query: "INSERT INTO t (id) VALUES (1)"}))
+
+	a := engine.NewSession()
+	b := engine.NewSession()
+
+	// Inserting a row that conflicts with an uncommitted row waits for the inserting
+	// transaction to end, and succeeds if it rolls back
+	exec(t, a, "BEGIN")
+	exec(t, a, "INSERT INTO t (id) VALUES (2)")
+
+	result := make(chan error)
+	go func() { result <- b.QueryError(protocol.Request{Query: "INSERT INTO t (id) VALUES (2)"}) }()
+	assertBlocked(t, result)
+
+	exec(t, a, "ROLLBACK")
+	require.NoError(t, <-result)
+
+	// ... and fails if it commits
+	exec(t, a, "BEGIN")
+	exec(t, a, "INSERT INTO t (id) VALUES (3)")
+
+	go func() { result <- b.QueryError(protocol.Request{Query: "INSERT INTO t (id) VALUES (3)"}) }()
+	assertBlocked(t, result)
+
+	exec(t, a, "COMMIT")
+	assert.ErrorContains(t, <-result, "unique constraint violation")
+
+	// Inserting a row that conflicts with a row deleted by an uncommitted transaction
+	// succeeds once the deletion commits
+	exec(t, a, "BEGIN")
+	exec(t, a, "DELETE FROM t WHERE id = 1")
+
+	go func() { result <- b.QueryError(protocol.Request{Query: "INSERT INTO t (id) VALUES (1)"}) }()
+	assertBlocked(t, result)
+
+	exec(t, a, "COMMIT")
+	require.NoError(t, <-result)
+
+	assert.Equal(t, [][]any{{int32(1)}, {int32(2)}, {int32(3)}}, query(t, a, "SELECT id FROM t ORDER BY id"))
+}
+
+func TestSessionDDL(t *testing.T) {
+	engine := NewDefaultEngine()
+	a := engine.NewSession()
+	b := engine.NewSession()
+
+	// Statements of other sessions using a table created by an uncommitted transaction
+	// wait for the transaction to end, and fail if it rolls back
+	exec(t, a, "BEGIN")
+	exec(t, a, "CREATE TABLE t (id integer PRIMARY KEY)")
+	exec(t, a, "INSERT INTO t (id) VALUES (1)")
+
+	result := make(chan error)
+	go func() { result <- b.QueryError(protocol.Request{Query: "SELECT * FROM t"}) }()
+	assertBlocked(t, result)
+
+	exec(t, a, "ROLLBACK")
+	assert.ErrorContains(t, <-result, `unknown table "t"`)
+	assert.ErrorContains(t, b.QueryError(protocol.Request{Query: "SELECT * FROM t"}), `unknown table "t"`)
+
+	// ... and succeed once it commits
+	exec(t, a, "BEGIN")
+	exec(t, a, "CREATE TABLE t (id integer PRIMARY KEY)")
+	exec(t, a, "INSERT INTO t (id) VALUES (1)")
+
+	go func() { result <- b.QueryError(protocol.Request{Query: "INSERT INTO t (id) VALUES (2)"}) }()
+	assertBlocked(t, result)
+
+	exec(t, a, "COMMIT")
+	require.NoError(t, <-result)
+	assert.Equal(t, [][]any{{int32(1)}, {int32(2)}}, query(t, b, "SELECT id FROM t ORDER BY id"))
+}
+
+func TestSessionClose(t *testing.T) {
+	engine := NewDefaultEngine()
+	require.NoError(t, engine.QueryError(protocol.Request{Query: "CREATE TABLE t (id integer PRIMARY KEY, n integer)"}))
+	require.NoError(t, engine.QueryError(protocol.Request{Query: "INSERT INTO t (id, n) VALUES (1, 0)"}))
+
+	a := engine.NewSession()
+	b := engine.NewSession()
+
+	exec(t, a, "BEGIN")
+	exec(t, a, "INSERT INTO t (id, n) VALUES (2, 0)")
+	exec(t, a, "UPDATE t SET n = 10 WHERE id = 1")
+
+	result := make(chan error)
+	go func() { result <- b.QueryError(protocol.Request{Query: "UPDATE t SET n = n + 1 WHERE id = 1"}) }()
+	assertBlocked(t, result)
+
+	// Closing the session rolls back its transaction block and releases its locks
+	a.Close()
+	require.NoError(t, <-result)
+	assert.Equal(t, [][]any{{int32(1), int32(1)}}, query(t, b, "SELECT id, n FROM t ORDER BY id"))
+}
+
+func TestSessionPanics(t *testing.T) {
+	engine := NewDefaultEngine()
+	require.NoError(t, engine.QueryError(protocol.Request{Query: "CREATE TABLE t (id integer PRIMARY KEY)"}))
+	require.NoError(t, engine.QueryError(protocol.Request{Query: "INSERT INTO t (id) VALUES (1), (2)"}))
+
+	// The statement panics after its row is inserted
+	panicking := &panickingResponseWriter{}
+	session := engine.NewSession()
+	session.Query(protocol.Request{Query: "INSERT INTO t (id) VALUES (3) RETURNING id"}, panicking)
+	assert.ErrorContains(t, panicking.err, "query execution panic: unexpected row")
+	assert.Equal(t, transaction.StatusIdle, session.TransactionStatus())
+	exec(t, session, "INSERT INTO t (id) VALUES (4)")
+	assert.Equal(t, [][]any{{int32(1)}, {int32(2)}, {int32(4)}}, query(t, engine.NewSession(), "SELECT id FROM t ORDER BY id"))
+
+	// A statement panicking within a transaction block fails the block
+	exec(t, session, "BEGIN")
+	session.Query(protocol.Request{Query: "DELETE FROM t RETURNING id"}, panicking)
+	assert.Equal(t, transaction.StatusFailed, session.TransactionStatus())
+	exec(t, session, "ROLLBACK")
+	assert.Equal(t, [][]any{{int32(1)}, {int32(2)}, {int32(4)}}, query(t, engine.NewSession(), "SELECT id FROM t ORDER BY id"))
+}
+
+type panickingResponseWriter struct {
+	err error
+}
+
+func (w *panickingResponseWriter) SendRow(row rows.Row) { panic("unexpected row") }
+func (w *panickingResponseWriter) Done()                {}
+func (w *panickingResponseWriter) Error(err error)      { w.err = err }
+
+func assertBlocked(t *testing.T, result <-chan error) {
+	select {
+	case err := <-result:
+		t.Fatalf("expected query to block, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func query(t *testing.T, session *Session, query string) [][]any {
+	rows, err := session.QueryRows(protocol.Request{Query: query})
+	require.NoError(t, err, query)
+	return rows.Values
+}
+
+func exec(t *testing.T, session *Session, query string) {
+	require.NoError(t, session.QueryError(protocol.Request{Query: query}), query)
+}
@@ -0,0 +1,188 @@
+package engine
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/efritz/gostgres/internal/catalog"
+	"github.com/efritz/gostgres/internal/catalog/table"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/storage/wal"
+)
+
+const checkpointFilename = "checkpoint"
+
+// Snapshot writes the committed state of the engine's catalog (its schema, sequence
+// values, and the rows of every table) to the given writer. The snapshot is consistent:
+// it reflects exactly the transactions that committed before it was taken, and does not
+// block concurrent transactions.
+func (e *Engine) Snapshot(w io.Writer) error {
+	_, err := e.snapshot(w)
+	return err
+}
+
+// Restore reads a snapshot written by Snapshot into the engine, which must not yet
+// contain any tables or sequences. If the engine has a data directory, a checkpoint is
+// taken so that the restored state is durable. Restore must not be called concurrently
+// with other queries. If an error is returned, the snapshot may have been partially
+// restored.
+func (e *Engine) Restore(r io.Reader) error {
+	if len(e.catalog.Tables.Names()) > 0 || len(e.catalog.Sequences.Names()) > 0 {
+		return fmt.Errorf("cannot restore a snapshot into a non-empty database")
+	}
+
+	ctx := impls.NewExecutionContext(e.catalog)
+
+	if _, err := wal.ReadSnapshot(bufio.NewReader(r), func(record wal.Record) error {
+		if record.Type == wal.RecordTypeInsert {
+			// Rows are assigned new TIDs, as the TIDs of the snapshot may not be
+			// valid for the tables' access methods
+			record.TID = 0
+		}
+
+		return e.recoverRecord(ctx, record)
+	}); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %s", err)
+	}
+
+	return e.Checkpoint()
+}
+
+// Checkpoint writes a snapshot of the engine's committed state to its data directory
+// and discards the write-ahead log entries reflected in it, bounding the time required
+// to recover. Checkpoint has no effect if the engine has no data directory.
+func (e *Engine) Checkpoint() error {
+	if e.changes.log == nil {
+		return nil
+	}
+
+	e.checkpointMu.Lock()
+	defer e.checkpointMu.Unlock()
+
+	var lsn int64
+	if err := wal.WriteFileAtomic(
+		filepath.Join(e.dataDirectory, checkpointFilename+".tmp"),
+		filepath.Join(e.dataDirectory, checkpointFilename),
+		func(w io.Writer) (err error) {
+			bw := bufio.NewWriter(w)
+			if lsn, err = e.snapshot(bw); err != nil {
+				return err
+			}
+
+			return bw.Flush()
+		},
+	); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %s", err)
+	}
+
+	return e.changes.log.Discard(lsn)
+}
+
+// snapshot writes a snapshot of the engine's committed state to the given writer and
+// returns the LSN of the first write-ahead log entry not reflected in it.
+func (e *Engine) snapshot(w io.Writer) (int64, error) {
+	var (
+		lsn        int64
+		statements []string
+	)
+
+	err := e.transactions.ReadConsistent(
+		func() {
+			lsn = e.changes.nextLSN()
+			statements = e.changes.schema()
+		},
+		func(tx impls.Transaction) error {
+			// Objects created by transactions still in progress are present in the
+			// engine's catalog, so the names of committed objects are determined from
+			// the committed DDL statements instead
+			committed, err := e.committedSchema(statements)
+			if err != nil {
+				return err
+			}
+
+			sw, err := wal.NewSnapshotWriter(w, lsn)
+			if err != nil {
+				return err
+			}
+
+			for _, statement := range statements {
+				if err := sw.Write(wal.NewDDLRecord(statement)); err != nil {
+					return err
+				}
+			}
+
+			for _, name := range committed.Sequences.Names() {
+				sequence, ok := e.catalog.Sequences.Get(name)
+				if !ok {
+					return fmt.Errorf("sequence %q was concurrently dropped", name)
+				}
+
+				if err := sw.Write(wal.NewSequenceSetRecord(name, sequence.Value())); err != nil {
+					return err
+				}
+			}
+
+			ctx := impls.NewExecutionContext(e.catalog).WithTransaction(tx)
+			for _, name := range committed.Tables.Names() {
+				table, ok := e.catalog.Tables.Get(name)
+				if !ok {
+					return fmt.Errorf("table %q was concurrently dropped", name)
+				}
+
+				tids, err := table.TIDs(ctx)
+				if err != nil {
+					return err
+				}
+
+				for _, tid := range tids {
+					row, ok, err := table.Row(ctx, tid)
+					if err != nil {
+						return err
+					}
+					if !ok {
+						continue
+					}
+
+					if err := sw.Write(wal.NewInsertRecord(name, tid, row.Values[1:])); err != nil {
+						return err
+					}
+				}
+			}
+
+			return sw.Close()
+		},
+	)
+
+	return lsn, err
+}
+
+// committedSchema returns a scratch catalog containing the tables and sequences created
+// by the given DDL statements. Tables of the scratch catalog hold their rows in memory.
+func (e *Engine) committedSchema(statements []string) (impls.CatalogSet, error) {
+	accessMethods := map[string]impls.TableAccessMethod{}
+	for _, name := range e.catalog.TableAccessMethods.Names() {
+		accessMethods[name] = table.NewMemoryAccessMethod()
+	}
+
+	schema := impls.NewCatalogSet(
+		catalog.NewCatalog[impls.Table](),
+		catalog.NewCatalog[impls.Sequence](),
+		e.catalog.Functions,
+		e.catalog.Aggregates,
+		e.catalog.WindowFunctions,
+		e.catalog.TableFunctions,
+		catalog.NewCatalogWithEntries(accessMethods),
+		e.catalog.DefaultTableAccessMethod,
+	)
+
+	ctx := impls.NewExecutionContext(schema)
+	for _, statement := range statements {
+		if err := executeDDL(ctx, statement); err != nil {
+			return impls.CatalogSet{}, err
+		}
+	}
+
+	return schema, nil
+}
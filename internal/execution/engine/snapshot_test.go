@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint(t *testing.T) {
+	dataDirectory := t.TempDir()
+	engine, err := OpenDefaultEngine(dataDirectory)
+	require.NoError(t, err)
+
+	for _, query := range []string{
+		"CREATE TABLE one (x integer)",
+		"INSERT INTO one (x) VALUES (1)",
+		"CREATE SEQUENCE t_id_seq",
+		"CREATE TABLE t (id bigint PRIMARY KEY DEFAULT nextval('t_id_seq'), name text NOT NULL, score numeric, active boolean)",
+		"INSERT INTO t (name, score, active) VALUES ('a', 1.5, true), ('b', NULL, false), ('c', 2, NULL)",
+		"DELETE FROM t WHERE name = 'b'",
+		"checkpoint",
+		"INSERT INTO t (name) VALUES ('d')",
+		"CREATE UNIQUE INDEX t_name_idx ON t (name)",
+	} {
+		require.NoError(t, engine.QueryError(protocol.Request{Query: query}), query)
+	}
+
+	// Entries preceding the checkpoint are discarded
+	info, err := os.Stat(filepath.Join(dataDirectory, "wal"))
+	require.NoError(t, err)
+	walSize := info.Size()
+
+	expected := recoveryState(engine)
+	require.NoError(t, engine.Close())
+
+	engine, err = OpenDefaultEngine(dataDirectory)
+	require.NoError(t, err)
+	assert.Equal(t, expected, recoveryState(engine))
+	require.Error(t, engine.QueryError(protocol.Request{Query: "INSERT INTO t (name) VALUES ('d')"}))
+
+	// A checkpoint taken after recovery replaces the previous checkpoint
+	require.NoError(t, engine.Checkpoint())
+	info, err = os.Stat(filepath.Join(dataDirectory, "wal"))
+	require.NoError(t, err)
+	assert.Less(t, info.Size(), walSize)
+	require.NoError(t, engine.QueryError(protocol.Request{Query: "INSERT INTO t (name) VALUES ('e')"}))
+	expected = recoveryState(engine)
+	require.NoError(t, engine.Close())
+
+	engine, err = OpenDefaultEngine(dataDirectory)
+	require.NoError(t, err)
+	defer engine.Close()
+	assert.Equal(t, expected, recoveryState(engine))
+}
+
+func TestCheckpointWithoutDataDirectory(t *testing.T) {
+	engine := NewDefaultEngine()
+	require.NoError(t, engine.QueryError(protocol.Request{Query: "CHECKPOINT"}))
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	source := NewDefaultEngine()
+	for _, query := range []string{
+		"CREATE TABLE one (x integer)",
+		"INSERT INTO one (x) VALUES (1)",
+		"CREATE SEQUENCE t_id_seq",
+		"CREATE TABLE t (id bigint PRIMARY KEY DEFAULT nextval('t_id_seq'), name text NOT NULL, score numeric, active boolean)",
+		"INSERT INTO t (name, score, active) VALUES ('a', 1.5, true), ('b', NULL, false), ('c', 2, NULL)",
+		"UPDATE t SET score = 4 WHERE name = 'c'",
+		"DELETE FROM t WHERE name = 'b'",
+		"ALTER TABLE t ADD CONSTRAINT t_id_check CHECK (id < 1000)",
+	} {
+		require.NoError(t, source.QueryError(protocol.Request{Query: query}), query)
+	}
+
+	// Changes and objects of transactions in progress are not part of the snapshot
+	session := source.NewSession()
+	for _, query := range []string{
+		"BEGIN",
+		"INSERT INTO t (name) VALUES ('uncommitted')",
+		"CREATE TABLE uncommitted (x integer)",
+		"CREATE SEQUENCE uncommitted_seq",
+	} {
+		require.NoError(t, session.QueryError(protocol.Request{Query: query}), query)
+	}
+
+	expected := recoveryState(source)
+
+	var buf bytes.Buffer
+	require.NoError(t, source.Snapshot(&buf))
+	require.NoError(t, session.QueryError(protocol.Request{Query: "COMMIT"}))
+
+	// Non-empty databases cannot be restored into
+	require.Error(t, source.Restore(bytes.NewReader(buf.Bytes())))
+
+	dataDirectory := t.TempDir()
+	target, err := OpenDefaultEngine(dataDirectory, WithHeapTables(4))
+	require.NoError(t, err)
+	require.NoError(t, target.Restore(bytes.NewReader(buf.Bytes())))
+	assert.Equal(t, expected, recoveryState(target))
+	require.Error(t, target.QueryError(protocol.Request{Query: "SELECT * FROM uncommitted"}))
+	require.Error(t, target.QueryError(protocol.Request{Query: "INSERT INTO t (id, name) VALUES (2000, 'f')"}))
+	require.NoError(t, target.QueryError(protocol.Request{Query: "INSERT INTO t (name) VALUES ('f')"}))
+	expected = recoveryState(target)
+	require.NoError(t, target.Close())
+
+	// The restored state is durable
+	target, err = OpenDefaultEngine(dataDirectory, WithHeapTables(4))
+	require.NoError(t, err)
+	defer target.Close()
+	assert.Equal(t, expected, recoveryState(target))
+
+	// Truncated snapshots are rejected
+	require.Error(t, NewDefaultEngine().Restore(bytes.NewReader(buf.Bytes()[:buf.Len()-1])))
+}
@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubqueries(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE l (id integer PRIMARY KEY, k integer, x text)")
+	exec(t, session, "CREATE TABLE r (id integer PRIMARY KEY, k integer, y text)")
+	exec(t, session, "CREATE TABLE e (id integer PRIMARY KEY, k integer)")
+	exec(t, session, "INSERT INTO l (id, k, x) VALUES (1, 1, 'l1'), (2, 2, 'l2'), (3, NULL, 'l3'), (4, 3, 'l4')")
+	exec(t, session, "INSERT INTO r (id, k, y) VALUES (1, 2, 'r2'), (2, 3, 'r3'), (3, NULL, 'r4'), (4, 3, 'r5')")
+
+	t.Run("joins", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query    string
+			expected string
+		}{
+			{query: "SELECT x FROM l WHERE EXISTS (SELECT 1 FROM r WHERE r.k = l.k)", expected: "semi join using hash"},
+			{query: "SELECT x FROM l WHERE k IN (SELECT k FROM r)", expected: "semi join using hash"},
+			{query: "SELECT x FROM l WHERE NOT EXISTS (SELECT 1 FROM r WHERE r.k = l.k)", expected: "anti join using hash"},
+			{query: "SELECT x FROM l WHERE k NOT IN (SELECT k FROM r)", expected: "anti join using hash"},
+			{query: "SELECT x FROM l WHERE k < ALL (SELECT k FROM r)", expected: "anti join using nested loop"},
+		} {
+			plan, err := session.QueryRows(protocol.Request{Query: "EXPLAIN " + testCase.query})
+			require.NoError(t, err)
+			assert.Contains(t, plan.Values[0][0], testCase.expected, testCase.query)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := session.QueryRows(protocol.Request{Query: "SELECT x FROM l WHERE k IN (SELECT id, k FROM r)"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "subquery has too many columns")
+	})
+}
+
+func TestScalarSubqueries(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE customers (id integer PRIMARY KEY, name text)")
+	exec(t, session, "CREATE TABLE payments (id integer PRIMARY KEY, customer_id integer, amount integer)")
+	exec(t, session, "INSERT INTO customers (id, name) VALUES (1, 'c1'), (2, 'c2'), (3, 'c3')")
+	exec(t, session, "INSERT INTO payments (id, customer_id, amount) VALUES (1, 1, 10), (2, 1, 30), (3, 2, 20)")
+
+	t.Run("uncorrelated subqueries are evaluated once", func(t *testing.T) {
+		exec(t, session, "CREATE SEQUENCE s")
+
+		for i := 1; i <= 2; i++ {
+			rows := query(t, session, "SELECT (SELECT nextval('s') FROM customers WHERE id = 1) FROM customers")
+			assert.Equal(t, [][]any{{int64(i)}, {int64(i)}, {int64(i)}}, rows)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query string
+			err   string
+		}{
+			{query: "SELECT (SELECT amount FROM payments) FROM customers", err: "more than one row returned by a subquery used as an expression"},
+			{query: "SELECT (SELECT id, amount FROM payments) FROM customers", err: "subquery must return only one column"},
+			{query: "SELECT (SELECT amount FROM payments p WHERE p.id = c.missing) FROM customers c", err: `unknown field "c"."missing"`},
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: testCase.query})
+			require.Error(t, err, testCase.query)
+			assert.Contains(t, err.Error(), testCase.err, testCase.query)
+		}
+	})
+}
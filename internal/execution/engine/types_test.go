@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypes(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, `CREATE TABLE things (
+		id integer PRIMARY KEY,
+		code char(4),
+		name varchar(8),
+		price numeric(6, 2),
+		born date,
+		opens time,
+		seen timestamp,
+		seen_at timestamp with time zone,
+		lasts interval,
+		token uuid,
+		data bytea
+	)`)
+	exec(t, session, `INSERT INTO things (id, code, name, price, born, opens, seen, seen_at, lasts, token, data) VALUES
+		(1, 'ab', 'first', 12.345, '2024-01-31', '09:30', '2024-01-31 12:00:00', '2024-01-31 12:00:00+00', '1 day 02:00:00', 'A0EEBC99-9C0B-4EF8-BB6D-6BB9BD380A11', '\x00ff'),
+		(2, 'cd  ', 'second  ', 3, '2023-12-25', '17:45:30.5', '2023-12-25 08:15:00', '2023-12-25 08:15:00+02', '3 hours', '{a0eebc999c0b4ef8bb6d6bb9bd380a12}', 'abc'),
+		(3, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL)`)
+
+	t.Run("padded values", func(t *testing.T) {
+		// Trailing spaces beyond the length of the column are removed
+		assert.Equal(t, [][]any{{types.Character("cd  "), "second  "}}, query(t, session, "SELECT code, name FROM things WHERE id = 2"))
+	})
+
+	t.Run("values", func(t *testing.T) {
+		assert.Equal(t, [][]any{{
+			types.Interval{Microseconds: 3 * 60 * 60 * 1e6},
+			types.UUID{0xa0, 0xee, 0xbc, 0x99, 0x9c, 0x0b, 0x4e, 0xf8, 0xbb, 0x6d, 0x6b, 0xb9, 0xbd, 0x38, 0x0a, 0x12},
+		}}, query(t, session, "SELECT lasts, token FROM things WHERE id = 2"))
+	})
+
+	t.Run("updates are conformed to modifiers", func(t *testing.T) {
+		exec(t, session, "UPDATE things SET code = 'x', price = 1.999 WHERE id = 3")
+		assert.Equal(t, [][]any{{types.Character("x   "), "2"}}, query(t, session, "SELECT code, price::text FROM things WHERE id = 3"))
+	})
+
+	t.Run("indexes", func(t *testing.T) {
+		exec(t, session, "CREATE INDEX things_born_idx ON things (born)")
+		exec(t, session, "CREATE INDEX things_token_idx ON things USING hash (token)")
+
+		assert.Equal(t, [][]any{{int32(2)}}, query(t, session, "SELECT id FROM things WHERE born < '2024-01-01'::date"))
+		assert.Equal(t, [][]any{{int32(1)}}, query(t, session, "SELECT id FROM things WHERE token = 'a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11'::uuid"))
+
+		// Index lookups of char values ignore trailing spaces
+		exec(t, session, "CREATE INDEX things_code_idx ON things (code)")
+		exec(t, session, "CREATE INDEX things_code_hash_idx ON things USING hash (code)")
+		assert.Equal(t, [][]any{{int32(2)}}, query(t, session, "SELECT id FROM things WHERE code = 'cd'"))
+		assert.Equal(t, [][]any{{int32(2)}}, query(t, session, "SELECT id FROM things WHERE code = 'cd '::char(3)"))
+
+		plan, err := session.QueryRows(protocol.Request{Query: "EXPLAIN SELECT id FROM things WHERE token = 'a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11'::uuid"})
+		require.NoError(t, err)
+		assert.Contains(t, plan.Values[0][0].(string), "index scan of things via things_token_idx")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query string
+			err   string
+		}{
+			{query: "INSERT INTO things (id, name) VALUES (4, 'too long name')", err: "value too long for type character varying(8)"},
+			{query: "INSERT INTO things (id, code) VALUES (4, 'abcde')", err: "value too long for type character(4)"},
+			{query: "INSERT INTO things (id, price) VALUES (4, 12345.6)", err: "numeric field overflow"},
+			{query: "UPDATE things SET name = 'much too long' WHERE id = 1", err: "value too long for type character varying(8)"},
+			{query: "SELECT '2024-13-01'::date", err: `invalid input syntax for type date: "2024-13-01"`},
+			{query: "SELECT '25:00'::time", err: "invalid input syntax for type time without time zone"},
+			{query: "SELECT '1 fortnight'::interval", err: `invalid input syntax for type interval: "1 fortnight"`},
+			{query: "SELECT 'a0eebc99'::uuid", err: "invalid input syntax for type uuid"},
+			{query: `SELECT '\xabc'::bytea`, err: "invalid input syntax for type bytea"},
+			{query: "SELECT born + born FROM things", err: "illegal operand types for +: date and date"},
+			{query: "SELECT lasts / 0 FROM things WHERE id = 1", err: "division by zero"},
+			{query: "SELECT born = opens FROM things", err: "illegal operand types for comparison: date and time without time zone"},
+			{query: "SELECT 1::varchar(0)", err: "length for type varchar must be at least 1"},
+			{query: "SELECT 1::numeric(2, 3)", err: "NUMERIC scale 3 must be between 0 and precision 2"},
+			{query: "SELECT '10:00'::time with time zone", err: `unknown type "time with time zone"`},
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: testCase.query})
+			require.Error(t, err, testCase.query)
+			assert.Contains(t, err.Error(), testCase.err, testCase.query)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValues(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE targets (id integer PRIMARY KEY, label text)")
+
+	t.Run("column types", func(t *testing.T) {
+		// Values of each column are converted to their common type
+		rows, err := session.QueryRows(protocol.Request{Query: "SELECT * FROM (VALUES (1, NULL), (2.5, 'b')) AS t (n, s)"})
+		require.NoError(t, err)
+		assert.Equal(t, "double precision", rows.Fields[0].Type().String())
+		assert.Equal(t, "text", rows.Fields[1].Type().String())
+		assert.Equal(t, [][]any{{float64(1), nil}, {2.5, "b"}}, rows.Values)
+	})
+
+	t.Run("insert", func(t *testing.T) {
+		// Inserted values are converted to the type of their target column individually
+		exec(t, session, "INSERT INTO targets (id, label) VALUES (1, 'one'), (2, 2)")
+		assert.Equal(t, [][]any{{int32(1), "one"}, {int32(2), "2"}}, query(t, session, "SELECT id, label FROM targets ORDER BY id"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query string
+			err   string
+		}{
+			{query: "SELECT * FROM (VALUES (1), ('a'::text)) AS t", err: "VALUES types integer and text cannot be matched"},
+			{query: "SELECT * FROM (VALUES (1), (2, 3)) AS t", err: "VALUES lists must all be the same length"},
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: testCase.query})
+			require.Error(t, err, testCase.query)
+			assert.Contains(t, err.Error(), testCase.err, testCase.query)
+		}
+	})
+}
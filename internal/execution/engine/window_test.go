@@ -0,0 +1,76 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowFunctions(t *testing.T) {
+	session := NewDefaultEngine().NewSession()
+	exec(t, session, "CREATE TABLE employees (id integer PRIMARY KEY, dept text, salary integer)")
+	exec(t, session, "INSERT INTO employees (id, dept, salary) VALUES (1, 'eng', 100), (2, 'eng', 200), (3, 'eng', 200), (4, 'ops', 50), (5, 'ops', 80), (6, 'hr', 70)")
+
+	t.Run("errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query string
+			err   string
+		}{
+			{query: "SELECT id FROM employees WHERE row_number() OVER () > 1", err: "window functions are not allowed in this context"},
+			{query: "SELECT sum(row_number() OVER ()) OVER () FROM employees", err: "window functions are not allowed in this context"},
+			{query: "SELECT dept, rank() OVER (ORDER BY salary) FROM employees GROUP BY dept", err: `"employees.salary" not in group by`},
+			{query: "SELECT id, rank() OVER () FROM employees FOR UPDATE", err: "FOR UPDATE is not allowed with window functions"},
+			{query: "SELECT row_number() FROM employees", err: `window function "row_number" requires an OVER clause`},
+			{query: "SELECT now() OVER () FROM employees", err: "now is not a window function nor an aggregate function"},
+			{query: "SELECT rank() OVER w FROM employees", err: `window "w" does not exist`},
+			{query: "SELECT rank() OVER w FROM employees WINDOW w AS (), w AS ()", err: `window "w" is already defined`},
+			{query: "SELECT rank() OVER (w ORDER BY id) FROM employees WINDOW w AS (ORDER BY salary)", err: `cannot override ORDER BY clause of window "w"`},
+			{query: "SELECT rank() OVER (w PARTITION BY id) FROM employees WINDOW w AS ()", err: `cannot override PARTITION BY clause of window "w"`},
+			{query: "SELECT count(*) OVER (ORDER BY id ROWS BETWEEN UNBOUNDED FOLLOWING AND CURRENT ROW) FROM employees", err: "frame start cannot be UNBOUNDED FOLLOWING"},
+			{query: "SELECT count(*) OVER (ORDER BY id ROWS BETWEEN CURRENT ROW AND 1 PRECEDING) FROM employees", err: "frame starting from current row cannot have preceding rows"},
+			{query: "SELECT count(*) OVER (ORDER BY id, salary RANGE 1 PRECEDING) FROM employees", err: "RANGE with offset PRECEDING/FOLLOWING requires exactly one ORDER BY column"},
+			{query: "SELECT count(*) OVER (GROUPS CURRENT ROW) FROM employees", err: "GROUPS mode requires an ORDER BY clause"},
+			{query: "SELECT count(*) OVER (ORDER BY id ROWS id PRECEDING) FROM employees", err: "argument of ROWS must not contain variables"},
+			{query: "SELECT count(*) OVER (ORDER BY id ROWS 0 - 1 PRECEDING) FROM employees", err: "frame starting offset must not be negative"},
+			{query: "SELECT count(*) OVER (ORDER BY id ROWS CURRENT ROW EXCLUDE OTHERS) FROM employees", err: "expected CURRENT ROW, GROUP, TIES, or NO OTHERS"},
+			{query: "SELECT ntile(0) OVER () FROM employees", err: "argument of ntile must be greater than zero"},
+			{query: "SELECT lag() OVER () FROM employees", err: "lag expects 1 to 3 arguments, got 0"},
+		} {
+			_, err := session.QueryRows(protocol.Request{Query: testCase.query})
+			require.Error(t, err, testCase.query)
+			assert.Contains(t, err.Error(), testCase.err, testCase.query)
+		}
+	})
+
+	t.Run("grouped", func(t *testing.T) {
+		for _, testCase := range []struct {
+			query    string
+			expected [][]any
+		}{
+			{
+				query:    "SELECT dept, sum(salary), rank() OVER (ORDER BY sum(salary) DESC) FROM employees GROUP BY dept ORDER BY dept",
+				expected: [][]any{{"eng", int32(500), int64(1)}, {"hr", int32(70), int64(3)}, {"ops", int32(130), int64(2)}},
+			},
+			{
+				query:    "SELECT dept, row_number() OVER (ORDER BY dept) FROM employees GROUP BY dept HAVING count(*) > 1 ORDER BY dept",
+				expected: [][]any{{"eng", int64(1)}, {"ops", int64(2)}},
+			},
+			{
+				query:    "SELECT count(*), sum(count(*)) OVER () FROM employees",
+				expected: [][]any{{int64(6), int32(6)}},
+			},
+			{
+				query:    "SELECT salary / 100 AS bucket, count(*), lag(count(*)) OVER (ORDER BY salary / 100) FROM employees GROUP BY salary / 100 ORDER BY bucket",
+				expected: [][]any{{int32(0), int64(3), nil}, {int32(1), int64(1), int64(3)}, {int32(2), int64(2), int64(1)}},
+			},
+			{
+				query:    "SELECT DISTINCT count(*) OVER () FROM employees GROUP BY dept",
+				expected: [][]any{{int64(3)}},
+			},
+		} {
+			assert.Equal(t, testCase.expected, query(t, session, testCase.query), testCase.query)
+		}
+	})
+}
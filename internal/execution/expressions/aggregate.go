@@ -1,9 +1,12 @@
 package expressions
 
 import (
+	"slices"
+
 	"github.com/efritz/gostgres/internal/shared/fields"
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/utils"
 )
 
 func UnwrapAggregate(ctx impls.Cataloger, expr impls.Expression) (impls.Aggregate, []impls.Expression, bool) {
@@ -101,7 +104,7 @@ func AsAggregate(ctx impls.ExecutionContext, e impls.Expression) impls.Aggregate
 		if aggregate, args, ok := UnwrapAggregate(ctx, e); ok {
 			placeholder := NewMutableConstant()
 			results = append(results, placeholder)
-			subExpressions = append(subExpressions, &aggregateSubExpression{aggregate: aggregate, args: args})
+			subExpressions = append(subExpressions, &aggregateSubExpression{aggregate: aggregate, args: args, distinct: e.(*functionExpression).distinct})
 			return placeholder, nil
 		}
 
@@ -161,6 +164,8 @@ func (e *explodedAggregateExpression) Done(ctx impls.ExecutionContext) (any, err
 type aggregateSubExpression struct {
 	aggregate impls.Aggregate
 	args      []impls.Expression
+	distinct  bool
+	seen      map[string]struct{}
 	state     any
 }
 
@@ -177,6 +182,24 @@ func (e *aggregateSubExpression) Step(ctx impls.ExecutionContext, row rows.Row)
 		values = append(values, value)
 	}
 
+	if e.distinct {
+		// Rows ignored by the aggregate are not counted as a distinct value
+		if e.aggregate.Strict() && slices.Contains(values, nil) {
+			return nil
+		}
+
+		if e.seen == nil {
+			e.seen = map[string]struct{}{}
+		}
+
+		key := utils.HashSlice(values)
+		if _, ok := e.seen[key]; ok {
+			return nil
+		}
+
+		e.seen[key] = struct{}{}
+	}
+
 	newState, err := e.aggregate.Step(ctx, e.state, values)
 	if err != nil {
 		return err
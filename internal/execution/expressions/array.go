@@ -0,0 +1,327 @@
+package expressions
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type arrayConstructorExpression struct {
+	elements []impls.Expression
+	typ      types.Type
+}
+
+var _ impls.Expression = &arrayConstructorExpression{}
+
+// NewArrayConstructor creates an expression whose value is an array of the values of
+// the given expressions (i.e., ARRAY[...]). The elements of the array have the common
+// type of the expressions.
+func NewArrayConstructor(elements []impls.Expression) impls.Expression {
+	return &arrayConstructorExpression{
+		elements: elements,
+	}
+}
+
+func (e arrayConstructorExpression) String() string {
+	elements := make([]string, 0, len(e.elements))
+	for _, element := range e.elements {
+		elements = append(elements, element.String())
+	}
+
+	return fmt.Sprintf("ARRAY[%s]", strings.Join(elements, ", "))
+}
+
+func (e *arrayConstructorExpression) Resolve(ctx impls.ExpressionResolutionContext) error {
+	typ, err := resolveCommonType(ctx, "ARRAY", e.elements)
+	if err != nil {
+		return err
+	}
+
+	if typ.IsArray() {
+		return fmt.Errorf("multidimensional arrays are not supported")
+	}
+
+	e.typ = types.NewArrayType(typ)
+	return nil
+}
+
+func (e arrayConstructorExpression) Type() types.Type {
+	return e.typ
+}
+
+func (e arrayConstructorExpression) Equal(other impls.Expression) bool {
+	o, ok := other.(*arrayConstructorExpression)
+	if !ok || len(e.elements) != len(o.elements) {
+		return false
+	}
+
+	for i, element := range e.elements {
+		if !element.Equal(o.elements[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e arrayConstructorExpression) Children() []impls.Expression {
+	return slices.Clone(e.elements)
+}
+
+func (e arrayConstructorExpression) Fold() impls.Expression {
+	elements := make([]impls.Expression, 0, len(e.elements))
+	for _, element := range e.elements {
+		elements = append(elements, element.Fold())
+	}
+
+	return tryEvaluate(&arrayConstructorExpression{elements: elements, typ: e.typ})
+}
+
+func (e arrayConstructorExpression) Map(f func(impls.Expression) (impls.Expression, error)) (impls.Expression, error) {
+	elements := make([]impls.Expression, 0, len(e.elements))
+	for _, element := range e.elements {
+		mapped, err := element.Map(f)
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, mapped)
+	}
+
+	return f(&arrayConstructorExpression{elements: elements, typ: e.typ})
+}
+
+func (e arrayConstructorExpression) ValueFrom(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	elementType := e.typ.ElementType()
+
+	values := make([]any, 0, len(e.elements))
+	for _, element := range e.elements {
+		value, err := element.ValueFrom(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, refineValue(value, elementType))
+	}
+
+	return types.NewArray(elementType, values), nil
+}
+
+//
+//
+
+type subscriptExpression struct {
+	array impls.Expression
+	lower impls.Expression // the index of the element, or the optional lower bound of a slice
+	upper impls.Expression // the optional upper bound of a slice
+	slice bool
+}
+
+var _ impls.Expression = &subscriptExpression{}
+
+// NewSubscript creates an expression whose value is the element of the given array at
+// the given (one-based) index, or NULL if the index is out of bounds.
+func NewSubscript(array, index impls.Expression) impls.Expression {
+	return &subscriptExpression{
+		array: array,
+		lower: index,
+	}
+}
+
+// NewSlice creates an expression whose value is the array of elements of the given
+// array between the given (inclusive, one-based) bounds, either of which may be nil to
+// extend the slice to that end of the array. Bounds beyond the array are clamped.
+func NewSlice(array, lower, upper impls.Expression) impls.Expression {
+	return &subscriptExpression{
+		array: array,
+		lower: lower,
+		upper: upper,
+		slice: true,
+	}
+}
+
+func (e subscriptExpression) String() string {
+	if !e.slice {
+		return fmt.Sprintf("%s[%s]", e.array, e.lower)
+	}
+
+	var lower, upper string
+	if e.lower != nil {
+		lower = e.lower.String()
+	}
+	if e.upper != nil {
+		upper = e.upper.String()
+	}
+
+	return fmt.Sprintf("%s[%s:%s]", e.array, lower, upper)
+}
+
+func (e *subscriptExpression) Resolve(ctx impls.ExpressionResolutionContext) error {
+	if err := e.array.Resolve(ctx); err != nil {
+		return err
+	}
+
+	if typ := e.array.Type(); !typ.IsArray() && isTyped(typ) {
+		return fmt.Errorf("cannot subscript type %s because it does not support subscripting", typ)
+	}
+
+	for _, bound := range e.bounds() {
+		if err := bound.Resolve(ctx); err != nil {
+			return err
+		}
+
+		if err := InferType(bound, types.TypeInteger); err != nil {
+			return err
+		}
+
+		switch typ := bound.Type(); typ {
+		case types.TypeSmallInteger, types.TypeInteger, types.TypeBigInteger:
+		default:
+			if isTyped(typ) {
+				return fmt.Errorf("array subscript must have type integer, got %s", typ)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e subscriptExpression) bounds() []impls.Expression {
+	var bounds []impls.Expression
+	for _, bound := range []impls.Expression{e.lower, e.upper} {
+		if bound != nil {
+			bounds = append(bounds, bound)
+		}
+	}
+
+	return bounds
+}
+
+func (e subscriptExpression) Type() types.Type {
+	typ := e.array.Type()
+	if !typ.IsArray() {
+		return types.TypeAny
+	}
+
+	if e.slice {
+		return typ
+	}
+
+	return typ.ElementType()
+}
+
+func (e subscriptExpression) Equal(other impls.Expression) bool {
+	o, ok := other.(*subscriptExpression)
+	if !ok || e.slice != o.slice || !e.array.Equal(o.array) {
+		return false
+	}
+
+	return optionalExpressionsEqual(e.lower, o.lower) && optionalExpressionsEqual(e.upper, o.upper)
+}
+
+func (e subscriptExpression) Children() []impls.Expression {
+	return append([]impls.Expression{e.array}, e.bounds()...)
+}
+
+func (e subscriptExpression) Fold() impls.Expression {
+	e.array = e.array.Fold()
+	if e.lower != nil {
+		e.lower = e.lower.Fold()
+	}
+	if e.upper != nil {
+		e.upper = e.upper.Fold()
+	}
+
+	return tryEvaluate(&e)
+}
+
+func (e subscriptExpression) Map(f func(impls.Expression) (impls.Expression, error)) (impls.Expression, error) {
+	array, err := e.array.Map(f)
+	if err != nil {
+		return nil, err
+	}
+	e.array = array
+
+	if e.lower != nil {
+		lower, err := e.lower.Map(f)
+		if err != nil {
+			return nil, err
+		}
+		e.lower = lower
+	}
+
+	if e.upper != nil {
+		upper, err := e.upper.Map(f)
+		if err != nil {
+			return nil, err
+		}
+		e.upper = upper
+	}
+
+	return f(&e)
+}
+
+func (e subscriptExpression) ValueFrom(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	value, err := e.array.ValueFrom(ctx, row)
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	array, ok := value.(types.Array)
+	if !ok {
+		return nil, fmt.Errorf("cannot subscript type %s because it does not support subscripting", types.TypeKindFromValue(value))
+	}
+
+	lower, ok, err := e.boundValue(ctx, row, e.lower, 1)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	if !e.slice {
+		if lower < 1 || lower > int64(array.Len()) {
+			return nil, nil
+		}
+
+		return array.Elements()[lower-1], nil
+	}
+
+	upper, ok, err := e.boundValue(ctx, row, e.upper, int64(array.Len()))
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	lower, upper = max(lower, 1), min(upper, int64(array.Len()))
+	if lower > upper {
+		return types.NewArray(array.ElementType(), nil), nil
+	}
+
+	return types.NewArray(array.ElementType(), array.Elements()[lower-1:upper]), nil
+}
+
+// boundValue returns the value of the given subscript, or the given default if the
+// subscript is omitted. False is returned if the subscript is NULL.
+func (e subscriptExpression) boundValue(ctx impls.ExecutionContext, row rows.Row, bound impls.Expression, defaultValue int64) (int64, bool, error) {
+	if bound == nil {
+		return defaultValue, true, nil
+	}
+
+	value, err := bound.ValueFrom(ctx, row)
+	if err != nil || value == nil {
+		return 0, false, err
+	}
+
+	switch v := value.(type) {
+	case int16:
+		return int64(v), true, nil
+	case int32:
+		return int64(v), true, nil
+	case int64:
+		return v, true, nil
+	}
+
+	return 0, false, fmt.Errorf("array subscript must have type integer, got %s", types.TypeKindFromValue(value))
+}
@@ -0,0 +1,148 @@
+package expressions
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type arrayComparisonExpression struct {
+	left         impls.Expression
+	array        impls.Expression
+	operatorText string
+	compare      func(left, right impls.Expression) impls.Expression
+	all          bool
+}
+
+var _ impls.Expression = &arrayComparisonExpression{}
+
+// NewArrayComparison creates an expression comparing the given value with each element
+// of the given array (e.g., x = ANY (array) or x < ALL (array)). The comparison of two
+// expressions is created by the given function. An ANY comparison is true if any element
+// comparison is true, and false if every comparison (of which there may be none) is
+// false. An ALL comparison is true if every comparison is true, and false if any is
+// false. Otherwise, the result is NULL.
+func NewArrayComparison(left, array impls.Expression, operatorText string, compare func(left, right impls.Expression) impls.Expression, all bool) impls.Expression {
+	return &arrayComparisonExpression{
+		left:         left,
+		array:        array,
+		operatorText: operatorText,
+		compare:      compare,
+		all:          all,
+	}
+}
+
+func (e arrayComparisonExpression) String() string {
+	quantifier := "any"
+	if e.all {
+		quantifier = "all"
+	}
+
+	return fmt.Sprintf("%s %s %s(%s)", e.left, e.operatorText, quantifier, e.array)
+}
+
+func (e *arrayComparisonExpression) Resolve(ctx impls.ExpressionResolutionContext) error {
+	if err := e.left.Resolve(ctx); err != nil {
+		return err
+	}
+
+	if err := e.array.Resolve(ctx); err != nil {
+		return err
+	}
+
+	if typ := e.left.Type(); isTyped(typ) {
+		if err := InferType(e.array, types.NewArrayType(typ)); err != nil {
+			return err
+		}
+	}
+
+	typ := e.array.Type()
+	if !typ.IsArray() {
+		if isTyped(typ) {
+			return fmt.Errorf("op ANY/ALL (array) requires array on right side")
+		}
+
+		return nil
+	}
+
+	// Ensure the value can be compared with the elements of the array
+	return e.compare(e.left, NewSubscript(e.array, NewConstant(int32(1)))).Resolve(ctx)
+}
+
+func (e arrayComparisonExpression) Type() types.Type {
+	return types.TypeBool
+}
+
+func (e arrayComparisonExpression) Equal(other impls.Expression) bool {
+	o, ok := other.(*arrayComparisonExpression)
+	if !ok || e.operatorText != o.operatorText || e.all != o.all {
+		return false
+	}
+
+	return e.left.Equal(o.left) && e.array.Equal(o.array)
+}
+
+func (e arrayComparisonExpression) Children() []impls.Expression {
+	return []impls.Expression{e.left, e.array}
+}
+
+func (e arrayComparisonExpression) Fold() impls.Expression {
+	e.left = e.left.Fold()
+	e.array = e.array.Fold()
+	return tryEvaluate(&e)
+}
+
+func (e arrayComparisonExpression) Map(f func(impls.Expression) (impls.Expression, error)) (impls.Expression, error) {
+	left, err := e.left.Map(f)
+	if err != nil {
+		return nil, err
+	}
+
+	array, err := e.array.Map(f)
+	if err != nil {
+		return nil, err
+	}
+
+	e.left = left
+	e.array = array
+	return f(&e)
+}
+
+func (e arrayComparisonExpression) ValueFrom(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	left, err := e.left.ValueFrom(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	array, err := types.ValueAs[types.Array](e.array.ValueFrom(ctx, row))
+	if err != nil || array == nil {
+		return nil, err
+	}
+
+	// ANY is decided by a true comparison, and ALL by a false one
+	decisive := !e.all
+
+	// Without a decisive comparison, the result is NULL if any comparison is NULL
+	var result any = !decisive
+	for _, element := range array.Elements() {
+		comparison := e.compare(NewConstant(left), NewConstant(element))
+
+		value, err := types.ValueAs[bool](comparison.ValueFrom(ctx, rows.Row{}))
+		if err != nil {
+			return nil, err
+		}
+
+		if value == nil {
+			result = nil
+			continue
+		}
+
+		if *value == decisive {
+			return decisive, nil
+		}
+	}
+
+	return result, nil
+}
@@ -45,6 +45,24 @@ func (e *binaryExpression) Resolve(ctx impls.ExpressionResolutionContext) error
 		return err
 	}
 
+	// Untyped literals adopt a type for which the operator is defined
+	inferLiteralOperandType(e.left, e.right.Type(), func(typ types.Type) bool {
+		_, err := e.typeChecker(typ, e.right.Type())
+		return err == nil
+	})
+	inferLiteralOperandType(e.right, e.left.Type(), func(typ types.Type) bool {
+		_, err := e.typeChecker(e.left.Type(), typ)
+		return err == nil
+	})
+
+	// Untyped parameters adopt the type of the opposite operand
+	if err := InferType(e.left, e.right.Type()); err != nil {
+		return err
+	}
+	if err := InferType(e.right, e.left.Type()); err != nil {
+		return err
+	}
+
 	typ, err := e.typeChecker(e.left.Type(), e.right.Type())
 	e.typ = typ
 	return err
@@ -50,6 +50,10 @@ func newBinaryIntExpression(left, right impls.Expression, operatorText string, f
 			return typ, nil
 		}
 
+		if operator, ok := temporalOperators[temporalOperatorKey{operatorText, left, right}]; ok {
+			return operator.typ, nil
+		}
+
 		return types.TypeUnknown, fmt.Errorf("illegal operand types for %s: %s and %s", operatorText, left, right)
 	}
 
@@ -68,6 +72,10 @@ func newBinaryIntExpression(left, right impls.Expression, operatorText string, f
 			return nil, nil
 		}
 
+		if operator, ok := temporalOperators[temporalOperatorKey{operatorText, types.TypeKindFromValue(lVal), types.TypeKindFromValue(rVal)}]; ok {
+			return operator.apply(lVal, rVal)
+		}
+
 		lVal, rVal, err = types.PromoteToCommonNumericValues(lVal, rVal)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,124 @@
+package expressions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type temporalOperatorKey struct {
+	operatorText string
+	left         types.Type
+	right        types.Type
+}
+
+type temporalOperator struct {
+	typ   types.Type
+	apply func(a, b any) (any, error)
+}
+
+// temporalOperators are the arithmetic operators defined on dates, times, timestamps,
+// and intervals, keyed by the operator and the types of its operands.
+var temporalOperators = map[temporalOperatorKey]temporalOperator{}
+
+func registerTemporalOperator(operatorText string, left, right, typ types.Type, apply func(a, b any) (any, error)) {
+	temporalOperators[temporalOperatorKey{operatorText, left, right}] = temporalOperator{typ: typ, apply: apply}
+}
+
+// registerCommutativeTemporalOperator registers an operator along with the operator
+// that accepts the same operands in the opposite order.
+func registerCommutativeTemporalOperator(operatorText string, left, right, typ types.Type, apply func(a, b any) (any, error)) {
+	registerTemporalOperator(operatorText, left, right, typ, apply)
+	registerTemporalOperator(operatorText, right, left, typ, func(a, b any) (any, error) { return apply(b, a) })
+}
+
+func init() {
+	for _, typ := range []types.Type{types.TypeSmallInteger, types.TypeInteger} {
+		registerCommutativeTemporalOperator("+", types.TypeDate, typ, types.TypeDate, func(a, b any) (any, error) {
+			return a.(types.Date).AddDays(integerValue(b)), nil
+		})
+		registerTemporalOperator("-", types.TypeDate, typ, types.TypeDate, func(a, b any) (any, error) {
+			return a.(types.Date).AddDays(-integerValue(b)), nil
+		})
+	}
+
+	registerTemporalOperator("-", types.TypeDate, types.TypeDate, types.TypeInteger, func(a, b any) (any, error) {
+		return int32(a.(types.Date) - b.(types.Date)), nil
+	})
+
+	registerCommutativeTemporalOperator("+", types.TypeDate, types.TypeTime, types.TypeTimestamp, func(a, b any) (any, error) {
+		return types.NewTimestamp(a.(types.Date).Time()) + types.Timestamp(b.(types.Time)), nil
+	})
+
+	// Dates are treated as midnight of the date when combined with intervals
+	registerCommutativeTemporalOperator("+", types.TypeDate, types.TypeInterval, types.TypeTimestamp, func(a, b any) (any, error) {
+		return types.NewTimestamp(b.(types.Interval).AddTo(a.(types.Date).Time())), nil
+	})
+	registerTemporalOperator("-", types.TypeDate, types.TypeInterval, types.TypeTimestamp, func(a, b any) (any, error) {
+		return types.NewTimestamp(b.(types.Interval).Negate().AddTo(a.(types.Date).Time())), nil
+	})
+
+	registerCommutativeTemporalOperator("+", types.TypeTimestamp, types.TypeInterval, types.TypeTimestamp, func(a, b any) (any, error) {
+		return types.NewTimestamp(b.(types.Interval).AddTo(a.(types.Timestamp).Time())), nil
+	})
+	registerTemporalOperator("-", types.TypeTimestamp, types.TypeInterval, types.TypeTimestamp, func(a, b any) (any, error) {
+		return types.NewTimestamp(b.(types.Interval).Negate().AddTo(a.(types.Timestamp).Time())), nil
+	})
+	registerTemporalOperator("-", types.TypeTimestamp, types.TypeTimestamp, types.TypeInterval, func(a, b any) (any, error) {
+		return types.NewIntervalBetween(a.(types.Timestamp).Time(), b.(types.Timestamp).Time()), nil
+	})
+
+	registerCommutativeTemporalOperator("+", types.TypeTimestampTz, types.TypeInterval, types.TypeTimestampTz, func(a, b any) (any, error) {
+		return b.(types.Interval).AddTo(a.(time.Time)), nil
+	})
+	registerTemporalOperator("-", types.TypeTimestampTz, types.TypeInterval, types.TypeTimestampTz, func(a, b any) (any, error) {
+		return b.(types.Interval).Negate().AddTo(a.(time.Time)), nil
+	})
+	registerTemporalOperator("-", types.TypeTimestampTz, types.TypeTimestampTz, types.TypeInterval, func(a, b any) (any, error) {
+		return types.NewIntervalBetween(a.(time.Time), b.(time.Time)), nil
+	})
+
+	registerCommutativeTemporalOperator("+", types.TypeTime, types.TypeInterval, types.TypeTime, func(a, b any) (any, error) {
+		return a.(types.Time).AddInterval(b.(types.Interval)), nil
+	})
+	registerTemporalOperator("-", types.TypeTime, types.TypeInterval, types.TypeTime, func(a, b any) (any, error) {
+		return a.(types.Time).AddInterval(b.(types.Interval).Negate()), nil
+	})
+	registerTemporalOperator("-", types.TypeTime, types.TypeTime, types.TypeInterval, func(a, b any) (any, error) {
+		return types.Interval{Microseconds: int64(a.(types.Time) - b.(types.Time))}, nil
+	})
+
+	registerTemporalOperator("+", types.TypeInterval, types.TypeInterval, types.TypeInterval, func(a, b any) (any, error) {
+		return a.(types.Interval).Add(b.(types.Interval)), nil
+	})
+	registerTemporalOperator("-", types.TypeInterval, types.TypeInterval, types.TypeInterval, func(a, b any) (any, error) {
+		return a.(types.Interval).Add(b.(types.Interval).Negate()), nil
+	})
+
+	for _, typ := range []types.Type{types.TypeSmallInteger, types.TypeInteger, types.TypeBigInteger, types.TypeReal, types.TypeDoublePrecision, types.TypeNumeric} {
+		registerCommutativeTemporalOperator("*", types.TypeInterval, typ, types.TypeInterval, func(a, b any) (any, error) {
+			factor, _ := floatValue(b)
+			return a.(types.Interval).Scale(factor), nil
+		})
+		registerTemporalOperator("/", types.TypeInterval, typ, types.TypeInterval, func(a, b any) (any, error) {
+			divisor, _ := floatValue(b)
+			if divisor == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+
+			return a.(types.Interval).Scale(1 / divisor), nil
+		})
+	}
+}
+
+func integerValue(value any) int64 {
+	switch v := value.(type) {
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	}
+
+	panic("unreachable after type checking")
+}
@@ -0,0 +1,130 @@
+package expressions
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/ordering"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+// NewContains creates an expression that is true if the left operand contains the right
+// operand (i.e., left @> right). An array contains another if every element of the right
+// array is an element of the left array. Containment of jsonb values is defined by
+// types.JSONB.Contains.
+func NewContains(left, right impls.Expression) impls.Expression {
+	return newContainmentOperator(left, right, "@>", arrayContains, types.JSONB.Contains)
+}
+
+// NewContainedBy creates an expression that is true if the right operand contains the
+// left operand (i.e., left <@ right).
+func NewContainedBy(left, right impls.Expression) impls.Expression {
+	return newContainmentOperator(left, right, "<@", flip(arrayContains), flip(types.JSONB.Contains))
+}
+
+// NewArrayOverlaps creates an expression that is true if the arrays have any element in
+// common (i.e., left && right).
+func NewArrayOverlaps(left, right impls.Expression) impls.Expression {
+	return newContainmentOperator(left, right, "&&", func(a, b types.Array) bool {
+		for _, element := range b.Elements() {
+			if arrayHasElement(a, element) {
+				return true
+			}
+		}
+
+		return false
+	}, nil)
+}
+
+// IsContainment returns the operands of an expression of the form left @> right.
+func IsContainment(expr impls.Expression) (left, right impls.Expression, ok bool) {
+	if e, ok := expr.(*binaryExpression); ok && e.operatorText == "@>" {
+		return e.left, e.right, true
+	}
+
+	return nil, nil, false
+}
+
+// newContainmentOperator creates a boolean operator over two arrays or, if jsonbFunc is
+// non-nil, two jsonb values.
+func newContainmentOperator(
+	left, right impls.Expression,
+	operatorText string,
+	arrayFunc func(a, b types.Array) bool,
+	jsonbFunc func(a, b types.JSONB) bool,
+) impls.Expression {
+	typeChecker := func(left types.Type, right types.Type) (types.Type, error) {
+		if left.IsArray() && right.IsArray() && left.PromoteToCommonType(right) != types.TypeUnknown {
+			return types.TypeBool, nil
+		}
+
+		if jsonbFunc != nil && left == types.TypeJSONB && right == types.TypeJSONB {
+			return types.TypeBool, nil
+		}
+
+		return types.TypeUnknown, fmt.Errorf("illegal operand types for %s: %s and %s", operatorText, left, right)
+	}
+
+	valueFrom := func(ctx impls.ExecutionContext, left, right impls.Expression, row rows.Row) (any, error) {
+		lVal, err := left.ValueFrom(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		rVal, err := right.ValueFrom(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if lVal == nil || rVal == nil {
+			return nil, nil
+		}
+
+		switch l := lVal.(type) {
+		case types.Array:
+			if r, ok := rVal.(types.Array); ok {
+				return arrayFunc(l, r), nil
+			}
+
+		case types.JSONB:
+			if r, ok := rVal.(types.JSONB); ok && jsonbFunc != nil {
+				return jsonbFunc(l, r), nil
+			}
+		}
+
+		return nil, fmt.Errorf("illegal operand types for %s: %s and %s", operatorText, types.TypeKindFromValue(lVal), types.TypeKindFromValue(rVal))
+	}
+
+	return newBinaryExpression(left, right, operatorText, typeChecker, valueFrom)
+}
+
+func flip[T any](f func(a, b T) bool) func(a, b T) bool {
+	return func(a, b T) bool { return f(b, a) }
+}
+
+// arrayContains returns true if every element of b is an element of a. NULL elements
+// are not equal to any element.
+func arrayContains(a, b types.Array) bool {
+	for _, element := range b.Elements() {
+		if !arrayHasElement(a, element) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func arrayHasElement(array types.Array, value any) bool {
+	if value == nil {
+		return false
+	}
+
+	for _, element := range array.Elements() {
+		if element != nil && ordering.CompareValues(element, value) == ordering.OrderTypeEqual {
+			return true
+		}
+	}
+
+	return false
+}
@@ -18,6 +18,21 @@ func IsComparison(expr impls.Expression) (_ ComparisonType, left, right impls.Ex
 	return ComparisonTypeUnknown, nil, nil
 }
 
+// IsNullAwareEquality returns the operands of an expression of the form
+// NOT ((left = right) IS FALSE), which is true when the operands are equal or when
+// either operand is NULL.
+func IsNullAwareEquality(expr impls.Expression) (left, right impls.Expression, ok bool) {
+	if inner, ok := IsNegation(expr); ok {
+		if isFalse, ok := inner.(*unaryExpression); ok && isFalse.operatorText == "is false" {
+			if comparisonType, left, right := IsComparison(isFalse.expression); comparisonType == ComparisonTypeEquals {
+				return left, right, true
+			}
+		}
+	}
+
+	return nil, nil, false
+}
+
 func NewEquals(left, right impls.Expression) impls.Expression {
 	return newComparison(left, right, ComparisonTypeEquals)
 }
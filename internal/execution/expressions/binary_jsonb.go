@@ -0,0 +1,240 @@
+package expressions
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+// NewJSONBField creates an expression whose value is the field of a jsonb object with the
+// given key, or the element of a jsonb array at the given (zero-based) index (i.e.,
+// left -> right). The value is NULL if there is no such field or element.
+func NewJSONBField(left, right impls.Expression) impls.Expression {
+	return newJSONBField(left, right, "->", false)
+}
+
+// NewJSONBFieldText is like NewJSONBField, but the value is converted to text (i.e.,
+// left ->> right).
+func NewJSONBFieldText(left, right impls.Expression) impls.Expression {
+	return newJSONBField(left, right, "->>", true)
+}
+
+func newJSONBField(left, right impls.Expression, operatorText string, asText bool) impls.Expression {
+	typeChecker := func(left types.Type, right types.Type) (types.Type, error) {
+		if left == types.TypeJSONB {
+			switch right {
+			case types.TypeText, types.TypeSmallInteger, types.TypeInteger, types.TypeBigInteger:
+				return jsonbResultType(asText), nil
+			}
+		}
+
+		return types.TypeUnknown, fmt.Errorf("illegal operand types for %s: %s and %s", operatorText, left, right)
+	}
+
+	valueFrom := func(ctx impls.ExecutionContext, left, right impls.Expression, row rows.Row) (any, error) {
+		lVal, err := types.ValueAs[types.JSONB](left.ValueFrom(ctx, row))
+		if err != nil || lVal == nil {
+			return nil, err
+		}
+
+		rVal, err := right.ValueFrom(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		var value types.JSONB
+		var ok bool
+
+		switch r := rVal.(type) {
+		case nil:
+			return nil, nil
+		case string:
+			value, ok = lVal.Field(r)
+		case int16:
+			value, ok = lVal.Element(int(r))
+		case int32:
+			value, ok = lVal.Element(int(r))
+		case int64:
+			value, ok = lVal.Element(int(r))
+		default:
+			return nil, fmt.Errorf("illegal operand types for %s: jsonb and %s", operatorText, types.TypeKindFromValue(rVal))
+		}
+
+		return jsonbResult(value, ok, asText), nil
+	}
+
+	return newBinaryExpression(left, right, operatorText, typeChecker, valueFrom)
+}
+
+// NewJSONBPath creates an expression whose value is the jsonb value at the given path of
+// object keys and array indexes (i.e., left #> right). The value is NULL if there is no
+// value at the path.
+func NewJSONBPath(left, right impls.Expression) impls.Expression {
+	return newJSONBPath(left, right, "#>", false)
+}
+
+// NewJSONBPathText is like NewJSONBPath, but the value is converted to text (i.e.,
+// left #>> right).
+func NewJSONBPathText(left, right impls.Expression) impls.Expression {
+	return newJSONBPath(left, right, "#>>", true)
+}
+
+func newJSONBPath(left, right impls.Expression, operatorText string, asText bool) impls.Expression {
+	typeChecker := func(left types.Type, right types.Type) (types.Type, error) {
+		if left == types.TypeJSONB && right == types.NewArrayType(types.TypeText) {
+			return jsonbResultType(asText), nil
+		}
+
+		return types.TypeUnknown, fmt.Errorf("illegal operand types for %s: %s and %s", operatorText, left, right)
+	}
+
+	valueFrom := func(ctx impls.ExecutionContext, left, right impls.Expression, row rows.Row) (any, error) {
+		lVal, elements, err := jsonbAndTextArrayOperands(ctx, left, right, row)
+		if err != nil || lVal == nil {
+			return nil, err
+		}
+
+		// A NULL path element matches no value
+		path := make([]string, 0, len(elements))
+		for _, element := range elements {
+			if element == nil {
+				return nil, nil
+			}
+
+			path = append(path, element.(string))
+		}
+
+		value, ok := lVal.Path(path)
+		return jsonbResult(value, ok, asText), nil
+	}
+
+	return newBinaryExpression(left, right, operatorText, typeChecker, valueFrom)
+}
+
+// jsonbAndTextArrayOperands returns the value of the left jsonb operand and the elements
+// of the right text array operand. A nil jsonb value is returned if either is NULL.
+func jsonbAndTextArrayOperands(ctx impls.ExecutionContext, left, right impls.Expression, row rows.Row) (*types.JSONB, []any, error) {
+	lVal, err := types.ValueAs[types.JSONB](left.ValueFrom(ctx, row))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rVal, err := types.ValueAs[types.Array](right.ValueFrom(ctx, row))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if lVal == nil || rVal == nil {
+		return nil, nil, nil
+	}
+
+	return lVal, rVal.Elements(), nil
+}
+
+func jsonbResultType(asText bool) types.Type {
+	if asText {
+		return types.TypeText
+	}
+
+	return types.TypeJSONB
+}
+
+func jsonbResult(value types.JSONB, ok bool, asText bool) any {
+	if !ok {
+		return nil
+	}
+
+	if asText {
+		return value.Text()
+	}
+
+	return value
+}
+
+// NewJSONBHasKey creates an expression that is true if the given text is a top-level key
+// of a jsonb object or an element of a jsonb array (i.e., left ? right).
+func NewJSONBHasKey(left, right impls.Expression) impls.Expression {
+	typeChecker := func(left types.Type, right types.Type) (types.Type, error) {
+		if left == types.TypeJSONB && right == types.TypeText {
+			return types.TypeBool, nil
+		}
+
+		return types.TypeUnknown, fmt.Errorf("illegal operand types for ?: %s and %s", left, right)
+	}
+
+	valueFrom := func(ctx impls.ExecutionContext, left, right impls.Expression, row rows.Row) (any, error) {
+		lVal, err := types.ValueAs[types.JSONB](left.ValueFrom(ctx, row))
+		if err != nil {
+			return nil, err
+		}
+
+		rVal, err := types.ValueAs[string](right.ValueFrom(ctx, row))
+		if err != nil {
+			return nil, err
+		}
+
+		if lVal == nil || rVal == nil {
+			return nil, nil
+		}
+
+		return lVal.HasKey(*rVal), nil
+	}
+
+	return newBinaryExpression(left, right, "?", typeChecker, valueFrom)
+}
+
+// NewJSONBHasAnyKey creates an expression that is true if any of the texts in the given
+// array is a top-level key or element of a jsonb value (i.e., left ?| right).
+func NewJSONBHasAnyKey(left, right impls.Expression) impls.Expression {
+	return newJSONBHasKeys(left, right, "?|", false)
+}
+
+// NewJSONBHasAllKeys creates an expression that is true if all of the texts in the given
+// array are top-level keys or elements of a jsonb value (i.e., left ?& right).
+func NewJSONBHasAllKeys(left, right impls.Expression) impls.Expression {
+	return newJSONBHasKeys(left, right, "?&", true)
+}
+
+func newJSONBHasKeys(left, right impls.Expression, operatorText string, all bool) impls.Expression {
+	typeChecker := func(left types.Type, right types.Type) (types.Type, error) {
+		if left == types.TypeJSONB && right == types.NewArrayType(types.TypeText) {
+			return types.TypeBool, nil
+		}
+
+		return types.TypeUnknown, fmt.Errorf("illegal operand types for %s: %s and %s", operatorText, left, right)
+	}
+
+	valueFrom := func(ctx impls.ExecutionContext, left, right impls.Expression, row rows.Row) (any, error) {
+		lVal, keys, err := jsonbAndTextArrayOperands(ctx, left, right, row)
+		if err != nil || lVal == nil {
+			return nil, err
+		}
+
+		for _, key := range keys {
+			// NULL keys are ignored
+			if key == nil {
+				continue
+			}
+
+			// ?| is decided by a present key, and ?& by a missing one
+			if lVal.HasKey(key.(string)) != all {
+				return !all, nil
+			}
+		}
+
+		return all, nil
+	}
+
+	return newBinaryExpression(left, right, operatorText, typeChecker, valueFrom)
+}
+
+// IsJSONBKeyExistence returns the operands of an expression of the form left ? right.
+func IsJSONBKeyExistence(expr impls.Expression) (left, right impls.Expression, ok bool) {
+	if e, ok := expr.(*binaryExpression); ok && e.operatorText == "?" {
+		return e.left, e.right, true
+	}
+
+	return nil, nil, false
+}
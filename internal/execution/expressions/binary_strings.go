@@ -2,6 +2,9 @@ package expressions
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+	"sync"
 
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/shared/rows"
@@ -10,7 +13,7 @@ import (
 
 func NewConcat(left, right impls.Expression) impls.Expression {
 	typeChecker := func(left types.Type, right types.Type) (types.Type, error) {
-		if left == types.TypeText && right == types.TypeText {
+		if isTextType(left) && isTextType(right) {
 			return types.TypeText, nil
 		}
 
@@ -18,12 +21,12 @@ func NewConcat(left, right impls.Expression) impls.Expression {
 	}
 
 	valueFrom := func(ctx impls.ExecutionContext, left, right impls.Expression, row rows.Row) (any, error) {
-		lVal, err := types.ValueAs[string](left.ValueFrom(ctx, row))
+		lVal, err := textValueFrom(ctx, left, row)
 		if err != nil {
 			return nil, err
 		}
 
-		rVal, err := types.ValueAs[string](right.ValueFrom(ctx, row))
+		rVal, err := textValueFrom(ctx, right, row)
 		if err != nil {
 			return nil, err
 		}
@@ -38,10 +41,298 @@ func NewConcat(left, right impls.Expression) impls.Expression {
 	return newBinaryExpression(left, right, "||", typeChecker, valueFrom)
 }
 
+// isTextType returns true if values of the given type are accepted as text operands.
+// NULL operands have no type and are accepted, producing NULL.
+func isTextType(typ types.Type) bool {
+	return typ == types.TypeText || typ == types.TypeCharacter || typ == types.TypeUnknown
+}
+
+// textValueFrom evaluates a text operand. Blank-padded values are converted to text,
+// removing their trailing spaces.
+func textValueFrom(ctx impls.ExecutionContext, expr impls.Expression, row rows.Row) (*string, error) {
+	value, err := expr.ValueFrom(ctx, row)
+	if c, ok := value.(types.Character); ok && err == nil {
+		value = c.Text()
+	}
+
+	return types.ValueAs[string](value, err)
+}
+
 func NewLike(left, right impls.Expression) impls.Expression {
-	panic("NewLike unimplemented") // TODO
+	return newPatternMatch(left, right, "like", likePatternToRegexp, false)
 }
 
 func NewILike(left, right impls.Expression) impls.Expression {
-	panic("NewILike unimplemented") // TODO
+	return newPatternMatch(left, right, "ilike", likePatternToRegexp, true)
+}
+
+func NewSimilarTo(left, right impls.Expression) impls.Expression {
+	return newPatternMatch(left, right, "similar to", similarPatternToRegexp, false)
+}
+
+func NewRegexMatch(left, right impls.Expression) impls.Expression {
+	return newPatternMatch(left, right, "~", identityPattern, false)
+}
+
+func NewIRegexMatch(left, right impls.Expression) impls.Expression {
+	return newPatternMatch(left, right, "~*", identityPattern, true)
+}
+
+// NewPatternEscape creates an expression that rewrites a LIKE or SIMILAR TO pattern
+// using the given escape character into an equivalent pattern escaped by backslashes.
+// An empty escape string disables escaping.
+func NewPatternEscape(left, right impls.Expression) impls.Expression {
+	typeChecker := func(left types.Type, right types.Type) (types.Type, error) {
+		if isTextType(left) && isTextType(right) {
+			return types.TypeText, nil
+		}
+
+		return types.TypeUnknown, fmt.Errorf("illegal operand types for escape: %s and %s", left, right)
+	}
+
+	valueFrom := func(ctx impls.ExecutionContext, left, right impls.Expression, row rows.Row) (any, error) {
+		lVal, err := textValueFrom(ctx, left, row)
+		if err != nil {
+			return nil, err
+		}
+
+		rVal, err := textValueFrom(ctx, right, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if lVal == nil || rVal == nil {
+			return nil, nil
+		}
+
+		return escapePattern(*lVal, *rVal)
+	}
+
+	return newBinaryExpression(left, right, "escape", typeChecker, valueFrom)
+}
+
+// IsLikePrefix returns the operand of a case-sensitive LIKE expression with a constant
+// pattern along with the literal prefix that every matching value must begin with.
+func IsLikePrefix(expr impls.Expression) (left impls.Expression, prefix string, ok bool) {
+	e, ok := expr.(*binaryExpression)
+	if !ok || e.operatorText != "like" {
+		return nil, "", false
+	}
+
+	value, ok := constantValue(e.right)
+	if !ok {
+		return nil, "", false
+	}
+	pattern, ok := value.(string)
+	if !ok {
+		return nil, "", false
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '%', '_':
+			return e.left, sb.String(), true
+
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+		}
+
+		sb.WriteByte(pattern[i])
+	}
+
+	return e.left, sb.String(), true
+}
+
+type patternTranslator func(pattern string) (string, error)
+
+func newPatternMatch(left, right impls.Expression, operatorText string, translator patternTranslator, caseInsensitive bool) impls.Expression {
+	typeChecker := func(left types.Type, right types.Type) (types.Type, error) {
+		if isTextType(left) && isTextType(right) {
+			return types.TypeBool, nil
+		}
+
+		return types.TypeUnknown, fmt.Errorf("illegal operand types for %s: %s and %s", operatorText, left, right)
+	}
+
+	valueFrom := func(ctx impls.ExecutionContext, left, right impls.Expression, row rows.Row) (any, error) {
+		lVal, err := textValueFrom(ctx, left, row)
+		if err != nil {
+			return nil, err
+		}
+
+		rVal, err := textValueFrom(ctx, right, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if lVal == nil || rVal == nil {
+			return nil, nil
+		}
+
+		re, err := compilePattern(*rVal, translator, caseInsensitive)
+		if err != nil {
+			return nil, err
+		}
+
+		return re.MatchString(*lVal), nil
+	}
+
+	return newBinaryExpression(left, right, operatorText, typeChecker, valueFrom)
+}
+
+// compiledPatterns caches regular expressions compiled from (usually constant) patterns
+// so that they are not recompiled for every row.
+var compiledPatterns sync.Map
+
+func compilePattern(pattern string, translator patternTranslator, caseInsensitive bool) (*regexp.Regexp, error) {
+	expr, err := translator(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if caseInsensitive {
+		expr = "(?i)" + expr
+	}
+
+	if re, ok := compiledPatterns.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %s", err)
+	}
+
+	compiledPatterns.Store(expr, re)
+	return re, nil
+}
+
+func identityPattern(pattern string) (string, error) {
+	return pattern, nil
+}
+
+// likePatternToRegexp translates a LIKE pattern into an anchored regular expression.
+// A percent sign matches any sequence of characters, an underscore matches any single
+// character, and a backslash matches the following character literally.
+func likePatternToRegexp(pattern string) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("(?s)^")
+
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		case '\\':
+			if i+1 == len(runes) {
+				return "", fmt.Errorf("LIKE pattern must not end with escape character")
+			}
+
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(runes[i])))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	sb.WriteString("$")
+	return sb.String(), nil
+}
+
+// similarPatternToRegexp translates a SIMILAR TO pattern into an anchored regular
+// expression. Percent signs and underscores behave as in LIKE, the regular expression
+// metacharacters `. ^ $` are matched literally, and the remaining metacharacters
+// (alternation, repetition, grouping, and bracket expressions) are retained.
+func similarPatternToRegexp(pattern string) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("(?s)^(?:")
+
+	inBrackets := false
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if r == '\\' {
+			if i+1 == len(runes) {
+				return "", fmt.Errorf("SIMILAR TO pattern must not end with escape character")
+			}
+
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(runes[i])))
+			continue
+		}
+
+		if inBrackets {
+			if r == ']' {
+				inBrackets = false
+			}
+
+			sb.WriteRune(r)
+			continue
+		}
+
+		switch r {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		case '.', '^', '$':
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		case '[':
+			inBrackets = true
+			sb.WriteRune(r)
+
+			// A closing bracket at the start of a bracket expression is literal
+			if i+1 < len(runes) && runes[i+1] == '^' {
+				i++
+				sb.WriteRune(runes[i])
+			}
+			if i+1 < len(runes) && runes[i+1] == ']' {
+				i++
+				sb.WriteString(`\]`)
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	sb.WriteString(")$")
+	return sb.String(), nil
+}
+
+// escapePattern rewrites a pattern using the given escape character into a pattern
+// using backslash as its escape character.
+func escapePattern(pattern, escape string) (string, error) {
+	escapeRunes := []rune(escape)
+	if len(escapeRunes) > 1 {
+		return "", fmt.Errorf("invalid escape string: must be empty or one character")
+	}
+
+	if len(escapeRunes) == 1 && escapeRunes[0] == '\\' {
+		return pattern, nil
+	}
+
+	var sb strings.Builder
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case len(escapeRunes) == 1 && r == escapeRunes[0]:
+			sb.WriteRune('\\')
+			if i+1 < len(runes) {
+				i++
+				sb.WriteRune(runes[i])
+			}
+		case r == '\\':
+			// Backslashes are not special when another escape character is specified
+			sb.WriteString(`\\`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String(), nil
 }
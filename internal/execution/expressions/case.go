@@ -0,0 +1,330 @@
+package expressions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+// CaseWhen is a single `WHEN condition THEN result` branch of a CASE expression. In a
+// simple CASE expression, the condition is a value compared against the operand.
+type CaseWhen struct {
+	Condition impls.Expression
+	Result    impls.Expression
+}
+
+type caseExpression struct {
+	operand    impls.Expression
+	whens      []CaseWhen
+	elseResult impls.Expression
+	typ        types.Type
+}
+
+var _ impls.Expression = &caseExpression{}
+
+// NewCase creates an expression whose value is the result of the first branch whose
+// condition is true (or, when an operand is given, whose value equals the operand). The
+// else result, or NULL if none is given, is the value when no branch matches. Branches
+// after the first matching branch are not evaluated.
+func NewCase(operand impls.Expression, whens []CaseWhen, elseResult impls.Expression) impls.Expression {
+	return &caseExpression{
+		operand:    operand,
+		whens:      whens,
+		elseResult: elseResult,
+	}
+}
+
+func (e caseExpression) Name() string {
+	return "case"
+}
+
+func (e caseExpression) String() string {
+	parts := []string{"case"}
+	if e.operand != nil {
+		parts = append(parts, e.operand.String())
+	}
+
+	for _, when := range e.whens {
+		parts = append(parts, fmt.Sprintf("when %s then %s", when.Condition, when.Result))
+	}
+
+	if e.elseResult != nil {
+		parts = append(parts, fmt.Sprintf("else %s", e.elseResult))
+	}
+
+	return strings.Join(append(parts, "end"), " ")
+}
+
+func (e *caseExpression) Resolve(ctx impls.ExpressionResolutionContext) error {
+	if e.operand != nil {
+		if err := e.operand.Resolve(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, when := range e.whens {
+		if err := when.Condition.Resolve(ctx); err != nil {
+			return err
+		}
+
+		if e.operand != nil {
+			// Untyped parameters and literals adopt the type of the opposite operand
+			if err := InferType(e.operand, when.Condition.Type()); err != nil {
+				return err
+			}
+			if err := InferType(when.Condition, e.operand.Type()); err != nil {
+				return err
+			}
+
+			if isTyped(e.operand.Type()) && isTyped(when.Condition.Type()) && e.operand.Type().PromoteToCommonType(when.Condition.Type()) == types.TypeUnknown {
+				return fmt.Errorf("illegal operand types for comparison: %s and %s", e.operand.Type(), when.Condition.Type())
+			}
+		} else {
+			if err := InferType(when.Condition, types.TypeBool); err != nil {
+				return err
+			}
+
+			if typ := when.Condition.Type(); isTyped(typ) && typ != types.TypeBool {
+				return fmt.Errorf("argument of CASE/WHEN must be type bool, not type %s", typ)
+			}
+		}
+	}
+
+	results := make([]impls.Expression, 0, len(e.whens)+1)
+	for _, when := range e.whens {
+		results = append(results, when.Result)
+	}
+	if e.elseResult != nil {
+		results = append(results, e.elseResult)
+	}
+
+	typ, err := resolveCommonType(ctx, "CASE", results)
+	e.typ = typ
+	return err
+}
+
+func (e caseExpression) Type() types.Type {
+	return e.typ
+}
+
+func (e caseExpression) Equal(other impls.Expression) bool {
+	o, ok := other.(*caseExpression)
+	if !ok || len(e.whens) != len(o.whens) {
+		return false
+	}
+
+	if !optionalExpressionsEqual(e.operand, o.operand) || !optionalExpressionsEqual(e.elseResult, o.elseResult) {
+		return false
+	}
+
+	for i, when := range e.whens {
+		if !when.Condition.Equal(o.whens[i].Condition) || !when.Result.Equal(o.whens[i].Result) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e caseExpression) Children() []impls.Expression {
+	var children []impls.Expression
+	if e.operand != nil {
+		children = append(children, e.operand)
+	}
+
+	for _, when := range e.whens {
+		children = append(children, when.Condition, when.Result)
+	}
+
+	if e.elseResult != nil {
+		children = append(children, e.elseResult)
+	}
+
+	return children
+}
+
+func (e caseExpression) Fold() impls.Expression {
+	var operand impls.Expression
+	if e.operand != nil {
+		operand = e.operand.Fold()
+	}
+
+	var elseResult impls.Expression
+	if e.elseResult != nil {
+		elseResult = e.elseResult.Fold()
+	}
+
+	var whens []CaseWhen
+	for _, when := range e.whens {
+		when = CaseWhen{Condition: when.Condition.Fold(), Result: when.Result.Fold()}
+
+		if matches, ok := e.constantMatch(operand, when.Condition); ok {
+			if !matches {
+				// Never selected
+				continue
+			}
+
+			if len(whens) == 0 {
+				// Always selected; later branches are never evaluated
+				whens, elseResult = nil, when.Result
+				break
+			}
+
+			// Later branches are never evaluated
+			elseResult = when.Result
+			break
+		}
+
+		whens = append(whens, when)
+	}
+
+	if len(whens) == 0 {
+		if elseResult == nil {
+			return NewConstant(nil)
+		}
+
+		if value, ok := constantValue(elseResult); ok {
+			return NewConstant(refineValue(value, e.typ))
+		}
+
+		if elseResult.Type() == e.typ {
+			return elseResult
+		}
+	}
+
+	return tryEvaluate(&caseExpression{operand: operand, whens: whens, elseResult: elseResult, typ: e.typ})
+}
+
+// constantMatch determines if the branch with the given (folded) condition is selected
+// when the branch is reached. The second return value is false if this cannot be
+// determined without evaluating the expression.
+func (e caseExpression) constantMatch(operand, condition impls.Expression) (bool, bool) {
+	conditionValue, ok := constantValue(condition)
+	if !ok {
+		return false, false
+	}
+
+	if operand == nil {
+		value, ok := conditionValue.(bool)
+		return ok && value, true
+	}
+
+	operandValue, ok := constantValue(operand)
+	if !ok {
+		return false, false
+	}
+
+	if operandValue == nil || conditionValue == nil {
+		return false, true
+	}
+
+	matches, err := ComparisonTypeEquals.MatchesOrderType(operandValue, conditionValue)
+	return matches, err == nil
+}
+
+func (e caseExpression) Map(f func(impls.Expression) (impls.Expression, error)) (impls.Expression, error) {
+	var operand impls.Expression
+	if e.operand != nil {
+		o, err := e.operand.Map(f)
+		if err != nil {
+			return nil, err
+		}
+
+		operand = o
+	}
+
+	whens := make([]CaseWhen, 0, len(e.whens))
+	for _, when := range e.whens {
+		condition, err := when.Condition.Map(f)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := when.Result.Map(f)
+		if err != nil {
+			return nil, err
+		}
+
+		whens = append(whens, CaseWhen{Condition: condition, Result: result})
+	}
+
+	var elseResult impls.Expression
+	if e.elseResult != nil {
+		r, err := e.elseResult.Map(f)
+		if err != nil {
+			return nil, err
+		}
+
+		elseResult = r
+	}
+
+	return f(&caseExpression{operand: operand, whens: whens, elseResult: elseResult, typ: e.typ})
+}
+
+func (e caseExpression) ValueFrom(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	var operandValue any
+	if e.operand != nil {
+		value, err := e.operand.ValueFrom(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		operandValue = value
+	}
+
+	for _, when := range e.whens {
+		matches, err := e.matches(ctx, operandValue, when.Condition, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if matches {
+			value, err := when.Result.ValueFrom(ctx, row)
+			if err != nil {
+				return nil, err
+			}
+
+			return refineValue(value, e.typ), nil
+		}
+	}
+
+	if e.elseResult == nil {
+		return nil, nil
+	}
+
+	value, err := e.elseResult.ValueFrom(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	return refineValue(value, e.typ), nil
+}
+
+func (e caseExpression) matches(ctx impls.ExecutionContext, operandValue any, condition impls.Expression, row rows.Row) (bool, error) {
+	if e.operand == nil {
+		value, err := types.ValueAs[bool](condition.ValueFrom(ctx, row))
+		return value != nil && *value, err
+	}
+
+	if operandValue == nil {
+		return false, nil
+	}
+
+	conditionValue, err := condition.ValueFrom(ctx, row)
+	if err != nil || conditionValue == nil {
+		return false, err
+	}
+
+	return ComparisonTypeEquals.MatchesOrderType(operandValue, conditionValue)
+}
+
+func optionalExpressionsEqual(a, b impls.Expression) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return a.Equal(b)
+}
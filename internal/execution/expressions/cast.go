@@ -0,0 +1,99 @@
+package expressions
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type castExpression struct {
+	expression   impls.Expression
+	typ          types.Type
+	typeModifier types.TypeModifier
+}
+
+var _ impls.Expression = &castExpression{}
+
+// NewCast creates an expression that explicitly converts the value of the given
+// expression to the given type, conforming the result to the given type modifier.
+func NewCast(expression impls.Expression, typ types.Type, typeModifier types.TypeModifier) impls.Expression {
+	return &castExpression{
+		expression:   expression,
+		typ:          typ,
+		typeModifier: typeModifier,
+	}
+}
+
+func (e castExpression) Name() string {
+	if named, ok := e.expression.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+
+	return e.typeModifier.TypeName(e.typ)
+}
+
+func (e castExpression) String() string {
+	return fmt.Sprintf("%s::%s", e.expression, e.typeModifier.TypeName(e.typ))
+}
+
+func (e *castExpression) Resolve(ctx impls.ExpressionResolutionContext) error {
+	if err := e.expression.Resolve(ctx); err != nil {
+		return err
+	}
+
+	// Untyped parameters and literals adopt the target type
+	if err := InferType(e.expression, e.typ); err != nil {
+		return err
+	}
+
+	if typ := e.expression.Type(); isTyped(typ) && !types.CanCast(typ, e.typ, types.CastContextExplicit) {
+		return fmt.Errorf("cannot cast type %s to %s", typ, e.typ)
+	}
+
+	return nil
+}
+
+func (e castExpression) Type() types.Type {
+	return e.typ
+}
+
+func (e castExpression) Equal(other impls.Expression) bool {
+	if o, ok := other.(*castExpression); ok {
+		return e.typ == o.typ && e.typeModifier == o.typeModifier && e.expression.Equal(o.expression)
+	}
+
+	return false
+}
+
+func (e castExpression) Children() []impls.Expression {
+	return []impls.Expression{e.expression}
+}
+
+func (e castExpression) Fold() impls.Expression {
+	return tryEvaluate(&castExpression{expression: e.expression.Fold(), typ: e.typ, typeModifier: e.typeModifier})
+}
+
+func (e castExpression) Map(f func(impls.Expression) (impls.Expression, error)) (impls.Expression, error) {
+	expression, err := e.expression.Map(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(&castExpression{expression: expression, typ: e.typ, typeModifier: e.typeModifier})
+}
+
+func (e castExpression) ValueFrom(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	value, err := e.expression.ValueFrom(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err = types.Cast(value, e.typ, types.CastContextExplicit)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.typeModifier.Apply(value, types.CastContextExplicit)
+}
@@ -0,0 +1,128 @@
+package expressions
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type coalesceExpression struct {
+	args []impls.Expression
+	typ  types.Type
+}
+
+var _ impls.Expression = &coalesceExpression{}
+
+// NewCoalesce creates an expression whose value is that of the first of the given
+// expressions with a non-NULL value. Later expressions are not evaluated.
+func NewCoalesce(args []impls.Expression) impls.Expression {
+	return &coalesceExpression{
+		args: args,
+	}
+}
+
+func (e coalesceExpression) Name() string {
+	return "coalesce"
+}
+
+func (e coalesceExpression) String() string {
+	args := make([]string, 0, len(e.args))
+	for _, arg := range e.args {
+		args = append(args, arg.String())
+	}
+
+	return fmt.Sprintf("coalesce(%s)", strings.Join(args, ", "))
+}
+
+func (e *coalesceExpression) Resolve(ctx impls.ExpressionResolutionContext) error {
+	typ, err := resolveCommonType(ctx, "COALESCE", e.args)
+	e.typ = typ
+	return err
+}
+
+func (e coalesceExpression) Type() types.Type {
+	return e.typ
+}
+
+func (e coalesceExpression) Equal(other impls.Expression) bool {
+	o, ok := other.(*coalesceExpression)
+	if !ok || len(e.args) != len(o.args) {
+		return false
+	}
+
+	for i, arg := range e.args {
+		if !arg.Equal(o.args[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e coalesceExpression) Children() []impls.Expression {
+	return slices.Clone(e.args)
+}
+
+func (e coalesceExpression) Fold() impls.Expression {
+	var args []impls.Expression
+	for _, arg := range e.args {
+		arg = arg.Fold()
+
+		if constant, ok := arg.(*constantExpression); ok {
+			if constant.value == nil {
+				// Never selected
+				continue
+			}
+
+			if len(args) == 0 {
+				// Always selected
+				return NewConstant(refineValue(constant.value, e.typ))
+			}
+
+			// Later arguments are never evaluated
+			args = append(args, constant)
+			break
+		}
+
+		args = append(args, arg)
+	}
+
+	if len(args) == 0 {
+		return NewConstant(nil)
+	}
+
+	return &coalesceExpression{args: args, typ: e.typ}
+}
+
+func (e coalesceExpression) Map(f func(impls.Expression) (impls.Expression, error)) (impls.Expression, error) {
+	args := make([]impls.Expression, 0, len(e.args))
+	for _, arg := range e.args {
+		a, err := arg.Map(f)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, a)
+	}
+
+	return f(&coalesceExpression{args: args, typ: e.typ})
+}
+
+func (e coalesceExpression) ValueFrom(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	for _, arg := range e.args {
+		value, err := arg.ValueFrom(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if value != nil {
+			return refineValue(value, e.typ), nil
+		}
+	}
+
+	return nil, nil
+}
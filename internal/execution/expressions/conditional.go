@@ -82,6 +82,13 @@ func (e *conditionalExpression) Resolve(ctx impls.ExpressionResolutionContext) e
 		return err
 	}
 
+	if err := InferType(e.left, types.TypeBool); err != nil {
+		return err
+	}
+	if err := InferType(e.right, types.TypeBool); err != nil {
+		return err
+	}
+
 	if e.left.Type() == types.TypeBool && e.right.Type() == types.TypeBool {
 		return nil
 	}
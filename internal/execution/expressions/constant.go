@@ -2,6 +2,7 @@ package expressions
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/shared/rows"
@@ -25,12 +26,16 @@ func (e *constantExpression) Resolve(ctx impls.ExpressionResolutionContext) erro
 }
 
 func (e constantExpression) Type() types.Type {
-	// TODO - should consider explicit `::type` casts
 	return types.TypeKindFromValue(e.value)
 }
 
 func (e constantExpression) Equal(other impls.Expression) bool {
 	if o, ok := other.(*constantExpression); ok {
+		if _, ok := e.value.(types.Array); ok {
+			// Arrays are not comparable with ==
+			return reflect.DeepEqual(e.value, o.value)
+		}
+
 		return e.value == o.value
 	}
 
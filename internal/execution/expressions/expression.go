@@ -13,6 +13,32 @@ type CompositeExpression interface {
 	Children() []impls.Expression
 }
 
+// SubqueryExpression is an expression whose value is computed from the rows of a
+// subquery. The children of such an expression include references to the fields of
+// enclosing queries on which the subquery depends.
+type SubqueryExpression interface {
+	impls.Expression
+	Subquery()
+}
+
+// ContainsSubquery returns true if the given expression or any of its children is a
+// subquery expression.
+func ContainsSubquery(expr impls.Expression) bool {
+	if _, ok := expr.(SubqueryExpression); ok {
+		return true
+	}
+
+	if c, ok := expr.(CompositeExpression); ok {
+		for _, child := range c.Children() {
+			if ContainsSubquery(child) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func Fields(expr impls.Expression) []fields.Field {
 	return gatherFields(expr, nil)
 }
@@ -0,0 +1,145 @@
+package expressions
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/ordering"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type extremumExpression struct {
+	name      string
+	args      []impls.Expression
+	orderType ordering.OrderType
+	typ       types.Type
+}
+
+var _ impls.Expression = &extremumExpression{}
+
+// NewGreatest creates an expression whose value is the largest non-NULL value of the
+// given expressions, or NULL if all values are NULL.
+func NewGreatest(args []impls.Expression) impls.Expression {
+	return &extremumExpression{
+		name:      "greatest",
+		args:      args,
+		orderType: ordering.OrderTypeAfter,
+	}
+}
+
+// NewLeast creates an expression whose value is the smallest non-NULL value of the
+// given expressions, or NULL if all values are NULL.
+func NewLeast(args []impls.Expression) impls.Expression {
+	return &extremumExpression{
+		name:      "least",
+		args:      args,
+		orderType: ordering.OrderTypeBefore,
+	}
+}
+
+func (e extremumExpression) Name() string {
+	return e.name
+}
+
+func (e extremumExpression) String() string {
+	args := make([]string, 0, len(e.args))
+	for _, arg := range e.args {
+		args = append(args, arg.String())
+	}
+
+	return fmt.Sprintf("%s(%s)", e.name, strings.Join(args, ", "))
+}
+
+func (e *extremumExpression) Resolve(ctx impls.ExpressionResolutionContext) error {
+	typ, err := resolveCommonType(ctx, strings.ToUpper(e.name), e.args)
+	e.typ = typ
+	return err
+}
+
+func (e extremumExpression) Type() types.Type {
+	return e.typ
+}
+
+func (e extremumExpression) Equal(other impls.Expression) bool {
+	o, ok := other.(*extremumExpression)
+	if !ok || e.name != o.name || len(e.args) != len(o.args) {
+		return false
+	}
+
+	for i, arg := range e.args {
+		if !arg.Equal(o.args[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e extremumExpression) Children() []impls.Expression {
+	return slices.Clone(e.args)
+}
+
+func (e extremumExpression) Fold() impls.Expression {
+	args := make([]impls.Expression, 0, len(e.args))
+	for _, arg := range e.args {
+		arg = arg.Fold()
+
+		if value, ok := constantValue(arg); ok && value == nil {
+			// Never selected
+			continue
+		}
+
+		args = append(args, arg)
+	}
+
+	if len(args) == 0 {
+		return NewConstant(nil)
+	}
+
+	return tryEvaluate(&extremumExpression{name: e.name, args: args, orderType: e.orderType, typ: e.typ})
+}
+
+func (e extremumExpression) Map(f func(impls.Expression) (impls.Expression, error)) (impls.Expression, error) {
+	args := make([]impls.Expression, 0, len(e.args))
+	for _, arg := range e.args {
+		a, err := arg.Map(f)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, a)
+	}
+
+	return f(&extremumExpression{name: e.name, args: args, orderType: e.orderType, typ: e.typ})
+}
+
+func (e extremumExpression) ValueFrom(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	var result any
+	for _, arg := range e.args {
+		value, err := arg.ValueFrom(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if value == nil {
+			continue
+		}
+
+		if result == nil {
+			result = value
+			continue
+		}
+
+		switch ordering.CompareValues(value, result) {
+		case e.orderType:
+			result = value
+		case ordering.OrderTypeIncomparable:
+			return nil, fmt.Errorf("incomparable types")
+		}
+	}
+
+	return refineValue(result, e.typ), nil
+}
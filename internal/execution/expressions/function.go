@@ -11,17 +11,29 @@ import (
 )
 
 type functionExpression struct {
-	name string
-	args []impls.Expression
-	typ  types.Type
+	name     string
+	args     []impls.Expression
+	distinct bool // true if an aggregate function should ignore duplicate arguments
+	typ      types.Type
 }
 
 var _ impls.Expression = &functionExpression{}
 
 func NewFunction(name string, args []impls.Expression) impls.Expression {
+	return newFunction(name, args, false)
+}
+
+// NewDistinctFunction creates an invocation of the given aggregate function over
+// distinct values of its arguments.
+func NewDistinctFunction(name string, args []impls.Expression) impls.Expression {
+	return newFunction(name, args, true)
+}
+
+func newFunction(name string, args []impls.Expression, distinct bool) impls.Expression {
 	return &functionExpression{
-		name: name,
-		args: args,
+		name:     name,
+		args:     args,
+		distinct: distinct,
 	}
 }
 
@@ -31,24 +43,43 @@ func (e functionExpression) String() string {
 		args = append(args, arg.String())
 	}
 
+	if e.distinct {
+		return fmt.Sprintf("%s(distinct %s)", e.name, strings.Join(args, ", "))
+	}
+
 	return fmt.Sprintf("%s(%s)", e.name, strings.Join(args, ", "))
 }
 
 func (e *functionExpression) Resolve(ctx impls.ExpressionResolutionContext) error {
 	f, isAggregate, ok := lookupFunction(ctx, e.name)
 	if !ok {
+		if _, ok := ctx.Catalog().WindowFunctions.Get(e.name); ok {
+			return fmt.Errorf("window function %q requires an OVER clause", e.name)
+		}
+
 		return fmt.Errorf("unknown function %q", e.name)
 	}
+	if e.distinct && !isAggregate {
+		return fmt.Errorf("DISTINCT specified, but %s is not an aggregate function", e.name)
+	}
 	if isAggregate && !ctx.AllowAggregateFunctions() {
 		return fmt.Errorf("aggregate function %q not allowed in this context", e.name)
 	}
 
+	paramTypes := f.ParamTypes()
+
 	var argTypes []types.Type
-	for _, arg := range e.args {
+	for i, arg := range e.args {
 		if err := arg.Resolve(ctx); err != nil {
 			return err
 		}
 
+		if i < len(paramTypes) {
+			if err := InferType(arg, paramTypes[i]); err != nil {
+				return err
+			}
+		}
+
 		argTypes = append(argTypes, arg.Type())
 	}
 
@@ -56,8 +87,29 @@ func (e *functionExpression) Resolve(ctx impls.ExpressionResolutionContext) erro
 		return err
 	}
 
-	e.typ = f.ReturnType()
-	return nil
+	typ, err := resolveReturnType(f, argTypes)
+	e.typ = typ
+	return err
+}
+
+// resolveReturnType returns the type of the value of the given function when invoked
+// with arguments of the given types. Functions returning arrays of any type (such as
+// array_agg) return arrays of the type of their first argument.
+func resolveReturnType(f impls.Callable, argTypes []types.Type) (types.Type, error) {
+	typ := f.ReturnType()
+	if typ != types.NewArrayType(types.TypeAny) || len(argTypes) == 0 || f.ParamTypes()[0] != types.TypeAny {
+		return typ, nil
+	}
+
+	if argTypes[0].IsArray() {
+		return types.TypeUnknown, fmt.Errorf("multidimensional arrays are not supported")
+	}
+
+	if !isTyped(argTypes[0]) {
+		return typ, nil
+	}
+
+	return types.NewArrayType(argTypes[0]), nil
 }
 
 func lookupFunction(ctx impls.Cataloger, name string) (_ impls.Callable, isAggregate bool, ok bool) {
@@ -78,7 +130,7 @@ func (e functionExpression) Type() types.Type {
 
 func (e functionExpression) Equal(other impls.Expression) bool {
 	if o, ok := other.(*functionExpression); ok {
-		if e.name == o.name && len(e.args) == len(o.args) {
+		if e.name == o.name && e.distinct == o.distinct && len(e.args) == len(o.args) {
 			for i, arg := range e.args {
 				if !arg.Equal(o.args[i]) {
 					return false
@@ -106,7 +158,7 @@ func (e functionExpression) Fold() impls.Expression {
 		args = append(args, arg.Fold())
 	}
 
-	return NewFunction(e.name, args)
+	return newFunction(e.name, args, e.distinct)
 }
 
 func (e functionExpression) Map(f func(impls.Expression) (impls.Expression, error)) (impls.Expression, error) {
@@ -120,7 +172,7 @@ func (e functionExpression) Map(f func(impls.Expression) (impls.Expression, erro
 		args = append(args, a)
 	}
 
-	return f(NewFunction(e.name, args))
+	return f(newFunction(e.name, args, e.distinct))
 }
 
 func (e functionExpression) ValueFrom(ctx impls.ExecutionContext, row rows.Row) (any, error) {
@@ -1,8 +1,11 @@
 package expressions
 
 import (
+	"fmt"
+
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
 )
 
 // TODO - normalization into "Conjunctive Normal Form"
@@ -134,9 +137,86 @@ func SubsumesOrder(a, b impls.OrderExpression) bool {
 }
 
 func tryEvaluate(expression impls.Expression) impls.Expression {
+	if ContainsSubquery(expression) {
+		// Subqueries are evaluated only during execution
+		return expression
+	}
+
 	if value, err := expression.ValueFrom(impls.EmptyExecutionContext, rows.Row{}); err == nil {
 		return NewConstant(value)
 	}
 
 	return expression
 }
+
+// resolveCommonType resolves the given expressions and returns the type to which all of
+// their values can be converted. Expressions without a type (e.g., NULL) are ignored, and
+// untyped parameters and literals adopt the common type. The common type of untyped
+// literals alone is text.
+func resolveCommonType(ctx impls.ExpressionResolutionContext, name string, exprs []impls.Expression) (types.Type, error) {
+	typ := types.TypeAny
+	hasLiterals := false
+	for _, expr := range exprs {
+		if err := expr.Resolve(ctx); err != nil {
+			return types.TypeUnknown, err
+		}
+
+		if IsUntypedLiteral(expr) {
+			hasLiterals = true
+			continue
+		}
+
+		if !isTyped(expr.Type()) {
+			continue
+		}
+
+		common := typ.PromoteToCommonType(expr.Type())
+		if common == types.TypeUnknown {
+			return types.TypeUnknown, fmt.Errorf("%s types %s and %s cannot be matched", name, typ, expr.Type())
+		}
+
+		typ = common
+	}
+
+	if typ == types.TypeAny && hasLiterals {
+		// String literals are text unless another expression determines their type
+		typ = types.TypeText
+	}
+
+	for _, expr := range exprs {
+		if err := InferType(expr, typ); err != nil {
+			return types.TypeUnknown, err
+		}
+	}
+
+	return typ, nil
+}
+
+// InferType assigns the given type to the expression if it is a reference to a parameter
+// or a string literal whose type has not otherwise been determined. An error is returned
+// if a string literal is not valid input for the type it adopts.
+func InferType(expr impls.Expression, typ types.Type) error {
+	switch e := expr.(type) {
+	case *parameterExpression:
+		e.parameterTypes.Infer(e.index, typ)
+	case *literalExpression:
+		e.infer(typ)
+		return e.err
+	}
+
+	return nil
+}
+
+func isTyped(typ types.Type) bool {
+	return typ != types.TypeUnknown && typ != types.TypeAny
+}
+
+// refineValue converts the given value to the given type, returning the value unchanged
+// when the conversion is not possible.
+func refineValue(value any, typ types.Type) any {
+	if _, refined, ok := typ.Refine(value); ok {
+		return refined
+	}
+
+	return value
+}
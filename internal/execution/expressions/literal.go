@@ -0,0 +1,144 @@
+package expressions
+
+import (
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type literalExpression struct {
+	text  string
+	typ   types.Type // TypeUnknown until the type is inferred from the literal's context
+	value any
+	err   error
+}
+
+var _ impls.Expression = &literalExpression{}
+
+// NewLiteral creates an expression for a string literal. As in Postgres, the literal
+// has no type of its own: it adopts the type expected by the context in which it is
+// used (e.g., the type of the opposite operand or of a function parameter), and is
+// text otherwise.
+func NewLiteral(text string) impls.Expression {
+	return &literalExpression{
+		text: text,
+		typ:  types.TypeUnknown,
+	}
+}
+
+func (e literalExpression) String() string {
+	return e.text
+}
+
+func (e *literalExpression) Resolve(ctx impls.ExpressionResolutionContext) error {
+	return nil
+}
+
+func (e literalExpression) Type() types.Type {
+	if e.typ == types.TypeUnknown {
+		return types.TypeText
+	}
+
+	return e.typ
+}
+
+func (e literalExpression) Equal(other impls.Expression) bool {
+	if o, ok := other.(*literalExpression); ok {
+		return e.text == o.text && e.Type() == o.Type()
+	}
+
+	return false
+}
+
+func (e literalExpression) Children() []impls.Expression {
+	return nil
+}
+
+func (e literalExpression) Fold() impls.Expression {
+	if e.err != nil {
+		// Invalid input is reported when the expression is evaluated
+		return &e
+	}
+
+	return NewConstant(e.valueOrText())
+}
+
+func (e literalExpression) Map(f func(impls.Expression) (impls.Expression, error)) (impls.Expression, error) {
+	return f(&e)
+}
+
+func (e literalExpression) ValueFrom(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	return e.valueOrText(), e.err
+}
+
+func (e literalExpression) valueOrText() any {
+	if e.typ == types.TypeUnknown {
+		return e.text
+	}
+
+	return e.value
+}
+
+// infer assigns the given type to the literal if its type has not otherwise been
+// determined. Literals used where any type is accepted are text.
+func (e *literalExpression) infer(typ types.Type) {
+	if e.typ != types.TypeUnknown || typ == types.TypeUnknown {
+		return
+	}
+
+	if typ == types.TypeAny {
+		typ = types.TypeText
+	} else if typ == types.NewArrayType(types.TypeAny) {
+		typ = types.NewArrayType(types.TypeText)
+	}
+
+	e.typ = typ
+	e.value, e.err = types.ParseText(e.text, typ)
+}
+
+// IsUntypedLiteral returns true if the given expression is a string literal whose type
+// has not yet been determined.
+func IsUntypedLiteral(expr impls.Expression) bool {
+	e, ok := expr.(*literalExpression)
+	return ok && e.typ == types.TypeUnknown
+}
+
+// literalCandidateTypes are the types, after the type of the opposite operand, that an
+// untyped literal operand of a binary operator may adopt (in order of preference).
+var literalCandidateTypes = []types.Type{
+	types.TypeText,
+	types.NewArrayType(types.TypeText),
+	types.TypeInterval,
+	types.TypeJSONB,
+}
+
+// inferLiteralOperandType assigns a type to the given operand if it is a string literal
+// whose type has not otherwise been determined. The literal adopts the first candidate
+// type that it is valid input for and for which the operator is defined (as reported by
+// the given function), preferring the type of the opposite operand. Literals without a
+// matching candidate adopt the type of the opposite operand if the operator is defined
+// for it (so that the invalid input is reported), and are text otherwise.
+func inferLiteralOperandType(operand impls.Expression, opposite types.Type, defined func(typ types.Type) bool) {
+	e, ok := operand.(*literalExpression)
+	if !ok || e.typ != types.TypeUnknown {
+		return
+	}
+
+	fallback := types.TypeText
+	if isTyped(opposite) && defined(opposite) {
+		fallback = opposite
+	}
+
+	for _, typ := range append([]types.Type{opposite}, literalCandidateTypes...) {
+		if !isTyped(typ) || !defined(typ) {
+			continue
+		}
+
+		if _, err := types.ParseText(e.text, typ); err == nil {
+			e.infer(typ)
+			return
+		}
+	}
+
+	e.infer(fallback)
+}
@@ -0,0 +1,93 @@
+package expressions
+
+import (
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+// IsNullRejecting returns true if the given filter cannot be true for any row in which
+// each of the given fields is NULL. The analysis is conservative: a filter rejecting
+// such rows may still be reported as not doing so.
+func IsNullRejecting(filter impls.Expression, nullFields []fields.Field) bool {
+	switch e := filter.(type) {
+	case *conditionalExpression:
+		if e.conjunctions {
+			return IsNullRejecting(e.left, nullFields) || IsNullRejecting(e.right, nullFields)
+		}
+
+		return IsNullRejecting(e.left, nullFields) && IsNullRejecting(e.right, nullFields)
+
+	case *unaryExpression:
+		switch e.operatorText {
+		case "not":
+			if inner, ok := e.expression.(*unaryExpression); ok && inner.operatorText == "is null" {
+				// x IS NOT NULL
+				return isNullOnNulls(inner.expression, nullFields)
+			}
+
+		case "is true":
+			return IsNullRejecting(e.expression, nullFields)
+		}
+
+	case *constantExpression:
+		return e.value == nil || e.value == false
+	}
+
+	// Filters evaluating to NULL are not true
+	return isNullOnNulls(filter, nullFields)
+}
+
+// isNullOnNulls returns true if the given expression evaluates to NULL for any row in
+// which each of the given fields is NULL.
+func isNullOnNulls(expression impls.Expression, nullFields []fields.Field) bool {
+	switch e := expression.(type) {
+	case NamedExpression:
+		_, err := fields.FindMatchingFieldIndex(e.Field(), nullFields)
+		return err == nil
+
+	case *constantExpression:
+		return e.value == nil
+
+	case *binaryExpression:
+		if comparisonType, _, _ := IsComparison(e); comparisonType == ComparisonTypeDistinctFrom {
+			return false
+		}
+
+		// Remaining binary operators are NULL when either operand is NULL
+		return isNullOnNulls(e.left, nullFields) || isNullOnNulls(e.right, nullFields)
+
+	case *unaryExpression:
+		if e.operatorText == "not" {
+			return isNullOnNulls(e.expression, nullFields)
+		}
+
+	case *conditionalExpression:
+		return isNullOnNulls(e.left, nullFields) && isNullOnNulls(e.right, nullFields)
+
+	case *coalesceExpression:
+		for _, arg := range e.args {
+			if !isNullOnNulls(arg, nullFields) {
+				return false
+			}
+		}
+
+		return true
+
+	case *extremumExpression:
+		for _, arg := range e.args {
+			if !isNullOnNulls(arg, nullFields) {
+				return false
+			}
+		}
+
+		return true
+
+	case *nullIfExpression:
+		return isNullOnNulls(e.left, nullFields)
+
+	case *castExpression:
+		return isNullOnNulls(e.expression, nullFields)
+	}
+
+	return false
+}
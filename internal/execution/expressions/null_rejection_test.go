@@ -0,0 +1,46 @@
+package expressions
+
+import (
+	"testing"
+
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsNullRejecting(t *testing.T) {
+	aField := fields.NewField("a", "x", types.TypeInteger, fields.NonInternalField)
+	bField := fields.NewField("b", "y", types.TypeInteger, fields.NonInternalField)
+	a := NewNamed(aField)
+	b := NewNamed(bField)
+	nullFields := []fields.Field{bField}
+
+	for _, testCase := range []struct {
+		name     string
+		filter   impls.Expression
+		expected bool
+	}{
+		{name: "comparison", filter: NewEquals(a, b), expected: true},
+		{name: "comparison of other fields", filter: NewEquals(a, NewConstant(int32(1))), expected: false},
+		{name: "arithmetic", filter: NewGreaterThan(NewAddition(b, NewConstant(int32(1))), a), expected: true},
+		{name: "is null", filter: NewIsNull(b), expected: false},
+		{name: "is not null", filter: NewNot(NewIsNull(b)), expected: true},
+		{name: "is true", filter: NewIsTrue(NewEquals(b, a)), expected: true},
+		{name: "is distinct from", filter: NewIsDistinctFrom(b, a), expected: false},
+		{name: "not", filter: NewNot(NewEquals(b, a)), expected: true},
+		{name: "conjunction", filter: NewAnd(NewEquals(a, NewConstant(int32(1))), NewEquals(b, NewConstant(int32(2)))), expected: true},
+		{name: "disjunction", filter: NewOr(NewEquals(a, NewConstant(int32(1))), NewEquals(b, NewConstant(int32(2)))), expected: false},
+		{name: "disjunction of rejecting filters", filter: NewOr(NewEquals(b, NewConstant(int32(1))), NewLessThan(b, a)), expected: true},
+		{name: "coalesce", filter: NewEquals(NewCoalesce([]impls.Expression{b, a}), NewConstant(int32(1))), expected: false},
+		{name: "greatest", filter: NewEquals(NewGreatest([]impls.Expression{b, a}), NewConstant(int32(1))), expected: false},
+		{name: "greatest of null fields", filter: NewEquals(NewGreatest([]impls.Expression{b, NewConstant(nil)}), NewConstant(int32(1))), expected: true},
+		{name: "nullif", filter: NewEquals(NewNullIf(b, a), NewConstant(int32(1))), expected: true},
+		{name: "case", filter: NewEquals(NewCase(nil, []CaseWhen{{Condition: NewIsNull(b), Result: a}}, b), NewConstant(int32(1))), expected: false},
+		{name: "false", filter: NewConstant(false), expected: true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, IsNullRejecting(testCase.filter, nullFields))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package expressions
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type nullIfExpression struct {
+	left  impls.Expression
+	right impls.Expression
+}
+
+var _ impls.Expression = &nullIfExpression{}
+
+// NewNullIf creates an expression whose value is NULL if the given expressions are
+// equal, and the value of the left expression otherwise.
+func NewNullIf(left, right impls.Expression) impls.Expression {
+	return &nullIfExpression{
+		left:  left,
+		right: right,
+	}
+}
+
+func (e nullIfExpression) Name() string {
+	return "nullif"
+}
+
+func (e nullIfExpression) String() string {
+	return fmt.Sprintf("nullif(%s, %s)", e.left, e.right)
+}
+
+func (e *nullIfExpression) Resolve(ctx impls.ExpressionResolutionContext) error {
+	if err := e.left.Resolve(ctx); err != nil {
+		return err
+	}
+
+	if err := e.right.Resolve(ctx); err != nil {
+		return err
+	}
+
+	// Untyped parameters and literals adopt the type of the opposite operand
+	if err := InferType(e.left, e.right.Type()); err != nil {
+		return err
+	}
+	if err := InferType(e.right, e.left.Type()); err != nil {
+		return err
+	}
+
+	if isTyped(e.left.Type()) && isTyped(e.right.Type()) && e.left.Type().PromoteToCommonType(e.right.Type()) == types.TypeUnknown {
+		return fmt.Errorf("illegal operand types for comparison: %s and %s", e.left.Type(), e.right.Type())
+	}
+
+	return nil
+}
+
+func (e nullIfExpression) Type() types.Type {
+	return e.left.Type()
+}
+
+func (e nullIfExpression) Equal(other impls.Expression) bool {
+	if o, ok := other.(*nullIfExpression); ok {
+		return e.left.Equal(o.left) && e.right.Equal(o.right)
+	}
+
+	return false
+}
+
+func (e nullIfExpression) Children() []impls.Expression {
+	return []impls.Expression{e.left, e.right}
+}
+
+func (e nullIfExpression) Fold() impls.Expression {
+	return tryEvaluate(&nullIfExpression{left: e.left.Fold(), right: e.right.Fold()})
+}
+
+func (e nullIfExpression) Map(f func(impls.Expression) (impls.Expression, error)) (impls.Expression, error) {
+	left, err := e.left.Map(f)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := e.right.Map(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f(&nullIfExpression{left: left, right: right})
+}
+
+func (e nullIfExpression) ValueFrom(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	lVal, err := e.left.ValueFrom(ctx, row)
+	if err != nil || lVal == nil {
+		return nil, err
+	}
+
+	rVal, err := e.right.ValueFrom(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if rVal != nil {
+		equal, err := ComparisonTypeEquals.MatchesOrderType(lVal, rVal)
+		if err != nil {
+			return nil, err
+		}
+
+		if equal {
+			return nil, nil
+		}
+	}
+
+	return lVal, nil
+}
@@ -0,0 +1,62 @@
+package expressions
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type parameterExpression struct {
+	index          int
+	parameterTypes *impls.ParameterTypes
+}
+
+var _ impls.Expression = &parameterExpression{}
+
+func NewParameter(index int) impls.Expression {
+	return &parameterExpression{
+		index: index,
+	}
+}
+
+func (e parameterExpression) String() string {
+	return fmt.Sprintf("$%d", e.index)
+}
+
+func (e *parameterExpression) Resolve(ctx impls.ExpressionResolutionContext) error {
+	e.parameterTypes = ctx.ParameterTypes()
+	e.parameterTypes.Use(e.index)
+	return nil
+}
+
+func (e parameterExpression) Type() types.Type {
+	// Parameter types are shared by all references to the same parameter and
+	// may be refined after this expression has been resolved
+	return e.parameterTypes.Type(e.index)
+}
+
+func (e parameterExpression) Equal(other impls.Expression) bool {
+	if o, ok := other.(*parameterExpression); ok {
+		return e.index == o.index
+	}
+
+	return false
+}
+
+func (e parameterExpression) Children() []impls.Expression {
+	return nil
+}
+
+func (e parameterExpression) Fold() impls.Expression {
+	return &e
+}
+
+func (e parameterExpression) Map(f func(impls.Expression) (impls.Expression, error)) (impls.Expression, error) {
+	return f(&e)
+}
+
+func (e parameterExpression) ValueFrom(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	return ctx.Parameter(e.index)
+}
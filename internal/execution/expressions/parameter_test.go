@@ -0,0 +1,69 @@
+package expressions
+
+import (
+	"testing"
+
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameter_InferType(t *testing.T) {
+	a := NewNamed(fields.NewField("t", "a", types.TypeBigInteger, fields.NonInternalField))
+
+	for _, testCase := range []struct {
+		name     string
+		declared []types.Type
+		expr     func() impls.Expression
+		expected []types.Type
+	}{
+		{
+			name:     "comparison",
+			expr:     func() impls.Expression { return NewEquals(a, NewParameter(1)) },
+			expected: []types.Type{types.TypeBigInteger},
+		},
+		{
+			name:     "arithmetic",
+			expr:     func() impls.Expression { return NewAddition(NewParameter(2), NewConstant(int32(1))) },
+			expected: []types.Type{types.TypeText, types.TypeInteger},
+		},
+		{
+			name:     "conditional",
+			expr:     func() impls.Expression { return NewAnd(NewParameter(1), NewEquals(a, NewParameter(2))) },
+			expected: []types.Type{types.TypeBool, types.TypeBigInteger},
+		},
+		{
+			name:     "declared types take precedence",
+			declared: []types.Type{types.TypeNumeric},
+			expr:     func() impls.Expression { return NewEquals(a, NewParameter(1)) },
+			expected: []types.Type{types.TypeNumeric},
+		},
+		{
+			name:     "shared references",
+			expr:     func() impls.Expression { return NewOr(NewEquals(a, NewParameter(1)), NewEquals(NewParameter(1), a)) },
+			expected: []types.Type{types.TypeBigInteger},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			parameterTypes := impls.NewParameterTypes(testCase.declared)
+			ctx := impls.NewExpressionResolutionContext(impls.NewCatalogEmptySet(), false).WithParameterTypes(parameterTypes)
+
+			require.NoError(t, testCase.expr().Resolve(ctx))
+			assert.Equal(t, testCase.expected, parameterTypes.Finalize())
+		})
+	}
+}
+
+func TestParameter_ValueFrom(t *testing.T) {
+	ctx := impls.EmptyExecutionContext.WithParameters([]any{int32(3), "x"})
+
+	value, err := NewParameter(2).ValueFrom(ctx, rows.Row{})
+	require.NoError(t, err)
+	assert.Equal(t, "x", value)
+
+	_, err = NewParameter(3).ValueFrom(ctx, rows.Row{})
+	assert.ErrorContains(t, err, "there is no parameter $3")
+}
@@ -0,0 +1,280 @@
+package expressions
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/ordering"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+// Default selectivities, used in the absence of statistics.
+const (
+	DefaultEqualitySelectivity = 0.005
+	DefaultRangeSelectivity    = 1.0 / 3
+	DefaultNullSelectivity     = 0.005
+	DefaultSelectivity         = 0.5
+)
+
+// StatisticsLookup returns the statistics of the column referenced by the given
+// field, if any are known.
+type StatisticsLookup func(field fields.Field) (impls.ColumnStatistics, bool)
+
+// EstimateSelectivity returns the expected fraction of rows that satisfy the given
+// filter. Column statistics are retrieved via the given lookup, which may be nil.
+func EstimateSelectivity(filter impls.Expression, lookup StatisticsLookup) float64 {
+	if filter == nil {
+		return 1
+	}
+
+	return clampSelectivity(estimateSelectivity(filter, lookup))
+}
+
+func estimateSelectivity(expression impls.Expression, lookup StatisticsLookup) float64 {
+	switch e := expression.(type) {
+	case *conditionalExpression:
+		left := estimateSelectivity(e.left, lookup)
+		right := estimateSelectivity(e.right, lookup)
+
+		if e.conjunctions {
+			return left * right
+		}
+
+		return left + right - left*right
+
+	case *unaryExpression:
+		switch e.operatorText {
+		case "not":
+			return 1 - estimateSelectivity(e.expression, lookup)
+
+		case "is null":
+			if statistics, ok := columnStatistics(e.expression, lookup); ok {
+				return statistics.NullFraction
+			}
+
+			return DefaultNullSelectivity
+		}
+
+	case *constantExpression:
+		if value, ok := e.value.(bool); ok && value {
+			return 1
+		}
+
+		return 0
+	}
+
+	if comparisonType, left, right := IsComparison(expression); comparisonType != ComparisonTypeUnknown {
+		return estimateComparisonSelectivity(comparisonType, left, right, lookup)
+	}
+
+	return DefaultSelectivity
+}
+
+func estimateComparisonSelectivity(comparisonType ComparisonType, left, right impls.Expression, lookup StatisticsLookup) float64 {
+	if _, ok := columnStatistics(left, lookup); !ok {
+		if _, ok := columnStatistics(right, lookup); ok {
+			// Estimate with the column on the left-hand side
+			comparisonType, left, right = comparisonType.Flip(), right, left
+		}
+	}
+
+	switch comparisonType {
+	case ComparisonTypeEquals:
+		return estimateEqualitySelectivity(left, right, lookup)
+
+	case ComparisonTypeDistinctFrom:
+		return 1 - estimateEqualitySelectivity(left, right, lookup)
+
+	case
+		ComparisonTypeLessThan,
+		ComparisonTypeLessThanEquals,
+		ComparisonTypeGreaterThan,
+		ComparisonTypeGreaterThanEquals:
+		return estimateRangeSelectivity(comparisonType, left, right, lookup)
+	}
+
+	return DefaultSelectivity
+}
+
+// estimateEqualitySelectivity estimates the fraction of rows for which the given
+// expressions are equal. Comparison with a constant uses the most common values of
+// the column; comparison with another column assumes that the values of the column
+// with fewer distinct values all occur in the other column.
+func estimateEqualitySelectivity(left, right impls.Expression, lookup StatisticsLookup) float64 {
+	statistics, ok := columnStatistics(left, lookup)
+	if !ok {
+		return DefaultEqualitySelectivity
+	}
+
+	if value, ok := constantValue(right); ok {
+		if value == nil {
+			return 0
+		}
+
+		for i, commonValue := range statistics.MostCommonValues {
+			if ordering.CompareValues(commonValue, value) == ordering.OrderTypeEqual {
+				return statistics.MostCommonFrequencies[i]
+			}
+		}
+
+		// Assume the remaining values are uniformly distributed, and no more common
+		// than the least common of the most common values
+		selectivity := uncommonFraction(statistics) / max(statistics.DistinctCount-float64(len(statistics.MostCommonValues)), 1)
+		if n := len(statistics.MostCommonFrequencies); n > 0 {
+			selectivity = min(selectivity, statistics.MostCommonFrequencies[n-1])
+		}
+
+		return selectivity
+	}
+
+	selectivity := (1 - statistics.NullFraction) / max(statistics.DistinctCount, 1)
+	if otherStatistics, ok := columnStatistics(right, lookup); ok {
+		selectivity = (1 - statistics.NullFraction) * (1 - otherStatistics.NullFraction) / max(statistics.DistinctCount, otherStatistics.DistinctCount, 1)
+	}
+
+	return selectivity
+}
+
+// estimateRangeSelectivity estimates the fraction of rows for which the given
+// inequality holds. Comparison of a column with a constant uses the most common values
+// and histogram of the column.
+func estimateRangeSelectivity(comparisonType ComparisonType, left, right impls.Expression, lookup StatisticsLookup) float64 {
+	statistics, ok := columnStatistics(left, lookup)
+	if !ok {
+		return DefaultRangeSelectivity
+	}
+
+	value, ok := constantValue(right)
+	if !ok {
+		return DefaultRangeSelectivity
+	}
+	if value == nil {
+		return 0
+	}
+
+	commonSelectivity := 0.0
+	for i, commonValue := range statistics.MostCommonValues {
+		if matches, err := comparisonType.MatchesOrderType(commonValue, value); err == nil && matches {
+			commonSelectivity += statistics.MostCommonFrequencies[i]
+		}
+	}
+
+	fraction, ok := histogramFractionBelow(statistics.HistogramBounds, value)
+	if !ok {
+		fraction = DefaultRangeSelectivity
+	} else if comparisonType == ComparisonTypeGreaterThan || comparisonType == ComparisonTypeGreaterThanEquals {
+		fraction = 1 - fraction
+	}
+
+	return commonSelectivity + fraction*uncommonFraction(statistics)
+}
+
+// histogramFractionBelow returns the fraction of the values described by the given
+// histogram bounds that are less than the given value. Values within a bucket are
+// assumed to be uniformly distributed.
+func histogramFractionBelow(bounds []any, value any) (float64, bool) {
+	if len(bounds) < 2 {
+		return 0, false
+	}
+
+	numBuckets := len(bounds) - 1
+	for i := 0; i <= numBuckets; i++ {
+		switch ordering.CompareValues(value, bounds[i]) {
+		case ordering.OrderTypeAfter:
+			continue
+
+		case ordering.OrderTypeBefore, ordering.OrderTypeEqual:
+			if i == 0 {
+				return 0, true
+			}
+
+			return (float64(i-1) + interpolate(bounds[i-1], bounds[i], value)) / float64(numBuckets), true
+		}
+
+		return 0, false
+	}
+
+	return 1, true
+}
+
+// interpolate returns the relative position of the given value between the given
+// lower and upper bounds. Positions between bounds that are not numbers or points in
+// time are assumed to be in the middle.
+func interpolate(lower, upper, value any) float64 {
+	l, ok1 := floatValue(lower)
+	u, ok2 := floatValue(upper)
+	v, ok3 := floatValue(value)
+	if !ok1 || !ok2 || !ok3 || u <= l {
+		return 0.5
+	}
+
+	return min(max((v-l)/(u-l), 0), 1)
+}
+
+func floatValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case *big.Float:
+		f, _ := v.Float64()
+		return f, true
+	case types.Date:
+		return float64(v), true
+	case types.Time:
+		return float64(v), true
+	case types.Timestamp:
+		return float64(v), true
+	case time.Time:
+		return float64(v.UnixMicro()), true
+	}
+
+	return 0, false
+}
+
+// uncommonFraction returns the fraction of rows whose value is not NULL and is not one
+// of the most common values.
+func uncommonFraction(statistics impls.ColumnStatistics) float64 {
+	fraction := 1 - statistics.NullFraction
+	for _, frequency := range statistics.MostCommonFrequencies {
+		fraction -= frequency
+	}
+
+	return max(fraction, 0)
+}
+
+func columnStatistics(expression impls.Expression, lookup StatisticsLookup) (impls.ColumnStatistics, bool) {
+	if lookup == nil {
+		return impls.ColumnStatistics{}, false
+	}
+
+	named, ok := expression.(NamedExpression)
+	if !ok {
+		return impls.ColumnStatistics{}, false
+	}
+
+	return lookup(named.Field())
+}
+
+// constantValue returns the value of the given expression if it is a constant known
+// during planning.
+func constantValue(expression impls.Expression) (any, bool) {
+	if constant, ok := expression.(*constantExpression); ok {
+		return constant.value, true
+	}
+
+	return nil, false
+}
+
+func clampSelectivity(selectivity float64) float64 {
+	return min(max(selectivity, 0), 1)
+}
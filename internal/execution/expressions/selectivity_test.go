@@ -0,0 +1,68 @@
+package expressions
+
+import (
+	"testing"
+
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateSelectivity(t *testing.T) {
+	a := NewNamed(fields.NewField("t", "a", types.TypeInteger, fields.NonInternalField))
+	b := NewNamed(fields.NewField("t", "b", types.TypeInteger, fields.NonInternalField))
+	c := NewNamed(fields.NewField("t", "c", types.TypeInteger, fields.NonInternalField))
+
+	lookup := func(field fields.Field) (impls.ColumnStatistics, bool) {
+		switch field.Name() {
+		case "a":
+			// 10% NULL, 50% zero, 10% one, remainder uniform over [0, 100]
+			return impls.ColumnStatistics{
+				NullFraction:          0.1,
+				DistinctCount:         42,
+				MostCommonValues:      []any{int32(0), int32(1)},
+				MostCommonFrequencies: []float64{0.5, 0.1},
+				HistogramBounds:       []any{int32(0), int32(25), int32(50), int32(75), int32(100)},
+			}, true
+
+		case "b":
+			return impls.ColumnStatistics{DistinctCount: 200}, true
+		}
+
+		return impls.ColumnStatistics{}, false
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		filter   impls.Expression
+		expected float64
+	}{
+		{name: "no filter", filter: nil, expected: 1},
+		{name: "common value", filter: NewEquals(a, NewConstant(int32(0))), expected: 0.5},
+		{name: "common value (flipped)", filter: NewEquals(NewConstant(int32(1)), a), expected: 0.1},
+		{name: "uncommon value", filter: NewEquals(a, NewConstant(int32(17))), expected: 0.3 / 40},
+		{name: "null value", filter: NewEquals(a, NewConstant(nil)), expected: 0},
+		{name: "distinct from", filter: NewIsDistinctFrom(a, NewConstant(int32(0))), expected: 0.5},
+		{name: "is null", filter: NewIsNull(a), expected: 0.1},
+		{name: "is not null", filter: NewNot(NewIsNull(a)), expected: 0.9},
+		{name: "range below histogram", filter: NewLessThan(a, NewConstant(int32(-5))), expected: 0},
+		{name: "range within histogram", filter: NewLessThan(a, NewConstant(int32(30))), expected: 0.6 + 0.3*0.3},
+		{name: "range within histogram (flipped)", filter: NewGreaterThan(NewConstant(int32(30)), a), expected: 0.6 + 0.3*0.3},
+		{name: "range above value", filter: NewGreaterThanEquals(a, NewConstant(int32(50))), expected: 0.3 * 0.5},
+		{name: "range above histogram", filter: NewGreaterThan(a, NewConstant(int32(500))), expected: 0},
+		{name: "join columns", filter: NewEquals(a, b), expected: 0.9 / 200},
+		{name: "column and unknown value", filter: NewEquals(b, NewParameter(1)), expected: 1.0 / 200},
+		{name: "conjunction", filter: NewAnd(NewEquals(a, NewConstant(int32(0))), NewIsNull(a)), expected: 0.05},
+		{name: "disjunction", filter: NewOr(NewEquals(a, NewConstant(int32(0))), NewEquals(a, NewConstant(int32(1)))), expected: 0.55},
+		{name: "no statistics (equality)", filter: NewEquals(c, NewConstant(int32(0))), expected: DefaultEqualitySelectivity},
+		{name: "no statistics (range)", filter: NewLessThan(c, NewConstant(int32(0))), expected: DefaultRangeSelectivity},
+		{name: "no statistics (null)", filter: NewIsNull(c), expected: DefaultNullSelectivity},
+		{name: "unknown expression", filter: NewIsTrue(c), expected: DefaultSelectivity},
+		{name: "constant", filter: NewConstant(false), expected: 0},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.InDelta(t, testCase.expected, EstimateSelectivity(testCase.filter, lookup), 1e-9)
+		})
+	}
+}
@@ -8,6 +8,15 @@ import (
 	"github.com/efritz/gostgres/internal/shared/types"
 )
 
+// IsNegation returns the operand of the given expression if it is of the form NOT x.
+func IsNegation(expr impls.Expression) (impls.Expression, bool) {
+	if e, ok := expr.(*unaryExpression); ok && e.operatorText == "not" {
+		return e.expression, true
+	}
+
+	return nil, false
+}
+
 func NewNot(expression impls.Expression) impls.Expression {
 	typeChecker := func(expression types.Type) (types.Type, error) {
 		if expression == types.TypeBool {
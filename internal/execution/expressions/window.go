@@ -0,0 +1,435 @@
+package expressions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type windowFunctionExpression struct {
+	name   string
+	args   []impls.Expression
+	window impls.Window
+	typ    types.Type
+}
+
+var _ impls.Expression = &windowFunctionExpression{}
+
+// NewWindowFunction creates an invocation of a window function (or of an aggregate used
+// as a window function) over the given window. The value of the expression is computed
+// by a window node, which replaces the expression with a reference to its output.
+func NewWindowFunction(name string, args []impls.Expression, window impls.Window) impls.Expression {
+	return &windowFunctionExpression{
+		name:   name,
+		args:   args,
+		window: window,
+	}
+}
+
+// UnwrapWindowFunction returns the name, arguments, and window of the given expression
+// if it is a window function invocation.
+func UnwrapWindowFunction(expr impls.Expression) (name string, args []impls.Expression, window impls.Window, ok bool) {
+	if e, ok := expr.(*windowFunctionExpression); ok {
+		return e.name, e.args, e.window, true
+	}
+
+	return "", nil, impls.Window{}, false
+}
+
+// ContainsWindowFunction returns true if the given expression or any of its children is
+// a window function invocation.
+func ContainsWindowFunction(expr impls.Expression) bool {
+	if _, ok := expr.(*windowFunctionExpression); ok {
+		return true
+	}
+
+	for _, child := range expr.Children() {
+		if ContainsWindowFunction(child) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e windowFunctionExpression) String() string {
+	args := make([]string, 0, len(e.args))
+	for _, arg := range e.args {
+		args = append(args, arg.String())
+	}
+
+	return fmt.Sprintf("%s(%s) over %s", e.name, strings.Join(args, ", "), WindowString(e.window))
+}
+
+func (e *windowFunctionExpression) Resolve(ctx impls.ExpressionResolutionContext) error {
+	if !ctx.AllowWindowFunctions() {
+		return fmt.Errorf("window functions are not allowed in this context")
+	}
+
+	if e.window.Name != "" {
+		base, ok := ctx.Window(e.window.Name)
+		if !ok {
+			return fmt.Errorf("window %q does not exist", e.window.Name)
+		}
+
+		window, err := InheritWindow(e.window.Name, base, e.window)
+		if err != nil {
+			return err
+		}
+		e.window = window
+	}
+
+	// Window function invocations cannot be nested
+	ctx = ctx.WithoutWindowFunctions()
+
+	var argTypes []types.Type
+	for _, arg := range e.args {
+		if err := arg.Resolve(ctx); err != nil {
+			return err
+		}
+
+		argTypes = append(argTypes, arg.Type())
+	}
+
+	if err := ResolveWindow(ctx, e.window); err != nil {
+		return err
+	}
+
+	if f, ok := ctx.Catalog().WindowFunctions.Get(e.name); ok {
+		typ, err := f.ResolveReturnType(argTypes)
+		if err != nil {
+			return err
+		}
+
+		e.typ = typ
+		return nil
+	}
+
+	if a, ok := ctx.Catalog().Aggregates.Get(e.name); ok {
+		paramTypes := a.ParamTypes()
+		for i, arg := range e.args {
+			if i < len(paramTypes) {
+				if err := InferType(arg, paramTypes[i]); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := a.ValidateArgTypes(argTypes); err != nil {
+			return err
+		}
+
+		typ, err := resolveReturnType(a, argTypes)
+		e.typ = typ
+		return err
+	}
+
+	if _, ok := ctx.Catalog().Functions.Get(e.name); ok {
+		return fmt.Errorf("OVER specified, but %s is not a window function nor an aggregate function", e.name)
+	}
+
+	return fmt.Errorf("unknown function %q", e.name)
+}
+
+func (e windowFunctionExpression) Type() types.Type {
+	return e.typ
+}
+
+func (e windowFunctionExpression) Equal(other impls.Expression) bool {
+	o, ok := other.(*windowFunctionExpression)
+	if !ok || e.name != o.name || len(e.args) != len(o.args) {
+		return false
+	}
+
+	for i, arg := range e.args {
+		if !arg.Equal(o.args[i]) {
+			return false
+		}
+	}
+
+	return WindowsEqual(e.window, o.window)
+}
+
+func (e windowFunctionExpression) Name() string {
+	return e.name
+}
+
+func (e windowFunctionExpression) Children() []impls.Expression {
+	children := make([]impls.Expression, 0, len(e.args))
+	children = append(children, e.args...)
+	children = append(children, windowExpressions(e.window)...)
+	return children
+}
+
+func (e windowFunctionExpression) Fold() impls.Expression {
+	args := make([]impls.Expression, 0, len(e.args))
+	for _, arg := range e.args {
+		args = append(args, arg.Fold())
+	}
+
+	window, _ := MapWindow(e.window, func(expr impls.Expression) (impls.Expression, error) {
+		return expr.Fold(), nil
+	})
+
+	return &windowFunctionExpression{name: e.name, args: args, window: window, typ: e.typ}
+}
+
+func (e windowFunctionExpression) Map(f func(impls.Expression) (impls.Expression, error)) (impls.Expression, error) {
+	args := make([]impls.Expression, 0, len(e.args))
+	for _, arg := range e.args {
+		a, err := arg.Map(f)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, a)
+	}
+
+	window, err := MapWindow(e.window, func(expr impls.Expression) (impls.Expression, error) {
+		return expr.Map(f)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return f(&windowFunctionExpression{name: e.name, args: args, window: window, typ: e.typ})
+}
+
+func (e windowFunctionExpression) ValueFrom(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	return nil, fmt.Errorf("window function %q evaluated outside of a window", e.name)
+}
+
+//
+//
+
+// InheritWindow returns the given window with the partitioning, ordering, and framing
+// it does not specify copied from the given named window.
+func InheritWindow(name string, base, window impls.Window) (impls.Window, error) {
+	refined := window.Order != nil || window.Frame != nil
+
+	if len(window.Partitions) > 0 {
+		return impls.Window{}, fmt.Errorf("cannot override PARTITION BY clause of window %q", name)
+	}
+	if base.Order != nil && window.Order != nil {
+		return impls.Window{}, fmt.Errorf("cannot override ORDER BY clause of window %q", name)
+	}
+	if base.Frame != nil && refined {
+		return impls.Window{}, fmt.Errorf("cannot copy window %q because it has a frame clause", name)
+	}
+
+	inherited := base
+	if window.Order != nil {
+		inherited.Order = window.Order
+	}
+	if window.Frame != nil {
+		inherited.Frame = window.Frame
+	}
+
+	return inherited, nil
+}
+
+// ResolveWindow resolves the expressions of the given window and ensures that its frame
+// is well-formed.
+func ResolveWindow(ctx impls.ExpressionResolutionContext, window impls.Window) error {
+	for _, expr := range windowExpressions(window) {
+		if err := expr.Resolve(ctx); err != nil {
+			return err
+		}
+	}
+
+	frame := window.Frame
+	if frame == nil {
+		return nil
+	}
+
+	if frame.Start.Type == impls.WindowFrameBoundUnboundedFollowing {
+		return fmt.Errorf("frame start cannot be UNBOUNDED FOLLOWING")
+	}
+	if frame.End.Type == impls.WindowFrameBoundUnboundedPreceding {
+		return fmt.Errorf("frame end cannot be UNBOUNDED PRECEDING")
+	}
+	if frame.Start.Type == impls.WindowFrameBoundCurrentRow && frame.End.Type == impls.WindowFrameBoundPreceding {
+		return fmt.Errorf("frame starting from current row cannot have preceding rows")
+	}
+	if frame.Start.Type == impls.WindowFrameBoundFollowing && (frame.End.Type == impls.WindowFrameBoundPreceding || frame.End.Type == impls.WindowFrameBoundCurrentRow) {
+		return fmt.Errorf("frame starting from following row cannot have preceding rows")
+	}
+
+	hasOffset := false
+	for _, bound := range []impls.WindowFrameBound{frame.Start, frame.End} {
+		if bound.Offset == nil {
+			continue
+		}
+
+		if len(Fields(bound.Offset)) > 0 {
+			return fmt.Errorf("argument of %s must not contain variables", strings.ToUpper(frame.Mode.String()))
+		}
+
+		hasOffset = true
+	}
+
+	switch frame.Mode {
+	case impls.WindowFrameModeRange:
+		if hasOffset && (window.Order == nil || len(window.Order.Expressions()) != 1) {
+			return fmt.Errorf("RANGE with offset PRECEDING/FOLLOWING requires exactly one ORDER BY column")
+		}
+
+	case impls.WindowFrameModeGroups:
+		if window.Order == nil {
+			return fmt.Errorf("GROUPS mode requires an ORDER BY clause")
+		}
+	}
+
+	return nil
+}
+
+// MapWindow returns a copy of the given window with f applied to each of its expressions.
+func MapWindow(window impls.Window, f func(impls.Expression) (impls.Expression, error)) (impls.Window, error) {
+	mapped := impls.Window{Name: window.Name}
+
+	for _, expr := range window.Partitions {
+		partition, err := f(expr)
+		if err != nil {
+			return impls.Window{}, err
+		}
+
+		mapped.Partitions = append(mapped.Partitions, partition)
+	}
+
+	if window.Order != nil {
+		order, err := window.Order.Map(f)
+		if err != nil {
+			return impls.Window{}, err
+		}
+
+		mapped.Order = order
+	}
+
+	if window.Frame != nil {
+		frame := *window.Frame
+		for _, bound := range []*impls.WindowFrameBound{&frame.Start, &frame.End} {
+			if bound.Offset == nil {
+				continue
+			}
+
+			offset, err := f(bound.Offset)
+			if err != nil {
+				return impls.Window{}, err
+			}
+
+			bound.Offset = offset
+		}
+
+		mapped.Frame = &frame
+	}
+
+	return mapped, nil
+}
+
+// WindowsEqual returns true if the given windows are equivalent.
+func WindowsEqual(a, b impls.Window) bool {
+	if a.Name != b.Name || len(a.Partitions) != len(b.Partitions) {
+		return false
+	}
+
+	for i, partition := range a.Partitions {
+		if !partition.Equal(b.Partitions[i]) {
+			return false
+		}
+	}
+
+	if (a.Order == nil) != (b.Order == nil) {
+		return false
+	}
+	if a.Order != nil {
+		aExpressions, bExpressions := a.Order.Expressions(), b.Order.Expressions()
+		if len(aExpressions) != len(bExpressions) {
+			return false
+		}
+
+		for i, expr := range aExpressions {
+			if expr.Reverse != bExpressions[i].Reverse || !expr.Expression.Equal(bExpressions[i].Expression) {
+				return false
+			}
+		}
+	}
+
+	if (a.Frame == nil) != (b.Frame == nil) {
+		return false
+	}
+	if a.Frame != nil {
+		if a.Frame.Mode != b.Frame.Mode || a.Frame.Exclusion != b.Frame.Exclusion {
+			return false
+		}
+
+		for _, pair := range [][2]impls.WindowFrameBound{{a.Frame.Start, b.Frame.Start}, {a.Frame.End, b.Frame.End}} {
+			if pair[0].Type != pair[1].Type || (pair[0].Offset == nil) != (pair[1].Offset == nil) {
+				return false
+			}
+			if pair[0].Offset != nil && !pair[0].Offset.Equal(pair[1].Offset) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// WindowString returns the given window as it would appear after OVER.
+func WindowString(window impls.Window) string {
+	var parts []string
+	if window.Name != "" {
+		if len(window.Partitions) == 0 && window.Order == nil && window.Frame == nil {
+			return window.Name
+		}
+
+		parts = append(parts, window.Name)
+	}
+
+	if len(window.Partitions) > 0 {
+		partitions := make([]string, 0, len(window.Partitions))
+		for _, expr := range window.Partitions {
+			partitions = append(partitions, expr.String())
+		}
+
+		parts = append(parts, "partition by "+strings.Join(partitions, ", "))
+	}
+
+	if window.Order != nil {
+		parts = append(parts, fmt.Sprintf("order by %s", window.Order))
+	}
+
+	if window.Frame != nil {
+		parts = append(parts, fmt.Sprintf("%s between %s and %s", window.Frame.Mode, window.Frame.Start, window.Frame.End))
+
+		if window.Frame.Exclusion != impls.WindowFrameExcludeNoOthers {
+			parts = append(parts, fmt.Sprintf("exclude %s", window.Frame.Exclusion))
+		}
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(parts, " "))
+}
+
+func windowExpressions(window impls.Window) []impls.Expression {
+	var exprs []impls.Expression
+	exprs = append(exprs, window.Partitions...)
+
+	if window.Order != nil {
+		for _, expr := range window.Order.Expressions() {
+			exprs = append(exprs, expr.Expression)
+		}
+	}
+
+	if window.Frame != nil {
+		for _, bound := range []impls.WindowFrameBound{window.Frame.Start, window.Frame.End} {
+			if bound.Offset != nil {
+				exprs = append(exprs, bound.Offset)
+			}
+		}
+	}
+
+	return exprs
+}
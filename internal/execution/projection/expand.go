@@ -111,6 +111,10 @@ func NewAliasedExpressionFromField(field fields.Field) ProjectionExpression {
 	return NewAliasedExpression(expressions.NewNamed(field), field.Name(), field.IsTID())
 }
 
+func (p aliasedExpression) Alias() string {
+	return p.alias
+}
+
 func (p aliasedExpression) Expand(fields []fields.Field, aliasedTables []AliasedTable) ([]ProjectedExpression, error) {
 	expression := p.expression
 	for _, table := range aliasedTables {
@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"github.com/efritz/gostgres/internal/shared/fields"
 	"github.com/efritz/gostgres/internal/shared/rows"
 )
 
@@ -9,3 +10,19 @@ type ResponseWriter interface {
 	Done()
 	Error(err error)
 }
+
+// Describer is implemented by response writers that are told the command a statement
+// executes and the fields of its result before any of its rows are sent. A statement
+// may be described more than once (e.g., an EXECUTE statement is described again by
+// the prepared statement it executes); the latest description applies.
+type Describer interface {
+	Describe(command string, fields []fields.Field)
+}
+
+// Describe describes a statement to the given response writer if it supports
+// descriptions.
+func Describe(w ResponseWriter, command string, fields []fields.Field) {
+	if describer, ok := w.(Describer); ok {
+		describer.Describe(command, fields)
+	}
+}
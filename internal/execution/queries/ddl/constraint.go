@@ -29,6 +29,10 @@ func NewCreatePrimaryKeyConstraint(name, tableName string, columnNames []string)
 	}
 }
 
+func (q *createPrimaryKeyConstraint) Command() string {
+	return "ALTER TABLE"
+}
+
 func (q *createPrimaryKeyConstraint) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
 	if err := q.ExecuteDDL(ctx); err != nil {
 		w.Error(err)
@@ -39,9 +43,9 @@ func (q *createPrimaryKeyConstraint) Execute(ctx impls.ExecutionContext, w proto
 }
 
 func (q *createPrimaryKeyConstraint) ExecuteDDL(ctx impls.ExecutionContext) error {
-	t, ok := ctx.Catalog().Tables.Get(q.tableName)
-	if !ok {
-		return fmt.Errorf("unknown table %q", q.tableName)
+	t, err := lockTable(ctx, q.tableName, impls.AccessExclusiveLock)
+	if err != nil {
+		return err
 	}
 
 	fields := t.Fields()
@@ -70,7 +74,7 @@ func (q *createPrimaryKeyConstraint) ExecuteDDL(ctx impls.ExecutionContext) erro
 		columnExpressions,
 	)
 
-	return t.SetPrimaryKey(index)
+	return t.SetPrimaryKey(ctx, index)
 }
 
 type createCheckConstraint struct {
@@ -90,6 +94,10 @@ func NewCreateCheckConstraint(name, tableName string, expression impls.Expressio
 	}
 }
 
+func (q *createCheckConstraint) Command() string {
+	return "ALTER TABLE"
+}
+
 func (q *createCheckConstraint) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
 	if err := q.ExecuteDDL(ctx); err != nil {
 		w.Error(err)
@@ -100,9 +108,9 @@ func (q *createCheckConstraint) Execute(ctx impls.ExecutionContext, w protocol.R
 }
 
 func (q *createCheckConstraint) ExecuteDDL(ctx impls.ExecutionContext) error {
-	table, ok := ctx.Catalog().Tables.Get(q.tableName)
-	if !ok {
-		return fmt.Errorf("unknown table %q", q.tableName)
+	table, err := lockTable(ctx, q.tableName, impls.AccessExclusiveLock)
+	if err != nil {
+		return err
 	}
 
 	constraint := constraints.NewCheckConstraint(q.name, q.expression)
@@ -130,6 +138,10 @@ func NewCreateForeignKeyConstraint(name, tableName string, columnNames []string,
 	}
 }
 
+func (q *createForeignKeyConstraint) Command() string {
+	return "ALTER TABLE"
+}
+
 func (q *createForeignKeyConstraint) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
 	if err := q.ExecuteDDL(ctx); err != nil {
 		w.Error(err)
@@ -140,9 +152,9 @@ func (q *createForeignKeyConstraint) Execute(ctx impls.ExecutionContext, w proto
 }
 
 func (q *createForeignKeyConstraint) ExecuteDDL(ctx impls.ExecutionContext) error {
-	t, ok := ctx.Catalog().Tables.Get(q.tableName)
-	if !ok {
-		return fmt.Errorf("unknown table %q", q.tableName)
+	t, err := lockTable(ctx, q.tableName, impls.ShareRowExclusiveLock)
+	if err != nil {
+		return err
 	}
 
 	var exprs []impls.Expression
@@ -156,9 +168,9 @@ func (q *createForeignKeyConstraint) ExecuteDDL(ctx impls.ExecutionContext) erro
 		exprs = append(exprs, setRelationName(expressions.NewNamed(field), q.tableName))
 	}
 
-	refTable, ok := ctx.Catalog().Tables.Get(q.refTableName)
-	if !ok {
-		return fmt.Errorf("unknown table %q", q.refTableName)
+	refTable, err := lockTable(ctx, q.refTableName, impls.ShareRowExclusiveLock)
+	if err != nil {
+		return err
 	}
 
 	var refIndex impls.Index[indexes.BtreeIndexScanOptions]
@@ -182,6 +194,6 @@ func (q *createForeignKeyConstraint) ExecuteDDL(ctx impls.ExecutionContext) erro
 		return fmt.Errorf("there is no unique constraint matching given keys for referenced table")
 	}
 
-	constraint := constraints.NewForeignKeyConstraint(q.name, exprs, refIndex)
+	constraint := constraints.NewForeignKeyConstraint(q.name, exprs, refTable, refIndex)
 	return t.AddConstraint(ctx, constraint)
 }
@@ -33,6 +33,10 @@ func NewCreateIndex(name, tableName, method string, unique bool, columnExpressio
 	}
 }
 
+func (q *createIndex) Command() string {
+	return "CREATE INDEX"
+}
+
 func (q *createIndex) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
 	if err := q.ExecuteDDL(ctx); err != nil {
 		w.Error(err)
@@ -46,6 +50,7 @@ func (q *createIndex) ExecuteDDL(ctx impls.ExecutionContext) error {
 	factories := map[string]func(ctx impls.ExecutionContext) (impls.BaseIndex, error){
 		"btree": q.createBtreeIndex,
 		"hash":  q.createHashIndex,
+		"gin":   q.createInvertedIndex,
 	}
 
 	factory, ok := factories[q.method]
@@ -58,12 +63,12 @@ func (q *createIndex) ExecuteDDL(ctx impls.ExecutionContext) error {
 		return err
 	}
 
-	table, ok := ctx.Catalog().Tables.Get(q.tableName)
-	if !ok {
-		return fmt.Errorf("unknown table %q", q.tableName)
+	table, err := lockTable(ctx, q.tableName, impls.ShareLock)
+	if err != nil {
+		return err
 	}
 
-	if err := table.AddIndex(index); err != nil {
+	if err := table.AddIndex(ctx, index); err != nil {
 		return err
 	}
 
@@ -116,6 +121,30 @@ func (q *createIndex) createHashIndex(ctx impls.ExecutionContext) (impls.BaseInd
 	return index, nil
 }
 
+func (q *createIndex) createInvertedIndex(ctx impls.ExecutionContext) (impls.BaseIndex, error) {
+	if len(q.columnExpressions) != 1 {
+		return nil, fmt.Errorf("gin index must have exactly one column")
+	}
+	if q.columnExpressions[0].Reverse {
+		return nil, fmt.Errorf("gin index do not support ordering")
+	}
+	if q.unique {
+		return nil, fmt.Errorf("gin index do not support uniqueness")
+	}
+
+	var index impls.Index[indexes.InvertedIndexScanOptions] = indexes.NewInvertedIndex(
+		q.name,
+		q.tableName,
+		setRelationName(q.columnExpressions[0].Expression, q.tableName),
+	)
+
+	if q.where != nil {
+		index = indexes.NewPartialIndex(index, setRelationName(q.where, q.tableName))
+	}
+
+	return index, nil
+}
+
 func setRelationName(e impls.Expression, name string) impls.Expression {
 	mapped, _ := e.Map(func(e impls.Expression) (impls.Expression, error) {
 		if named, ok := e.(expressions.NamedExpression); ok {
@@ -0,0 +1,52 @@
+package ddl
+
+import (
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/storage/wal"
+)
+
+// loggedDDL records the source text of a DDL statement in the write-ahead log once
+// it has executed successfully. Schema changes are recovered by re-executing the
+// statement rather than by logging the catalog entries it creates. Plans built
+// against the catalog before the statement executed (or rolled back) are invalidated.
+type loggedDDL struct {
+	query     DDLQuery
+	statement string
+}
+
+var _ queries.Query = &loggedDDL{}
+var _ DDLQuery = &loggedDDL{}
+
+func NewLogged(query DDLQuery, statement string) DDLQuery {
+	return &loggedDDL{
+		query:     query,
+		statement: statement,
+	}
+}
+
+func (q *loggedDDL) Command() string {
+	return queries.Command(q.query)
+}
+
+func (q *loggedDDL) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	if err := q.ExecuteDDL(ctx); err != nil {
+		w.Error(err)
+		return
+	}
+
+	w.Done()
+}
+
+func (q *loggedDDL) ExecuteDDL(ctx impls.ExecutionContext) error {
+	ctx.OnRollback(ctx.Catalog().InvalidatePlans)
+	defer ctx.Catalog().InvalidatePlans()
+
+	if err := q.query.ExecuteDDL(ctx); err != nil {
+		return err
+	}
+
+	ctx.RecordChange(wal.NewDDLRecord(q.statement))
+	return nil
+}
@@ -23,6 +23,10 @@ func NewCreateSequence(name string, typ types.Type) *createSequence {
 	}
 }
 
+func (q *createSequence) Command() string {
+	return "CREATE SEQUENCE"
+}
+
 func (q *createSequence) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
 	if err := q.ExecuteDDL(ctx); err != nil {
 		w.Error(err)
@@ -33,6 +37,6 @@ func (q *createSequence) Execute(ctx impls.ExecutionContext, w protocol.Response
 }
 
 func (q *createSequence) ExecuteDDL(ctx impls.ExecutionContext) error {
-	ctx.Catalog().Sequences.Set(q.name, sequence.NewSequence(q.name, q.typ))
+	setCatalogEntry(ctx, ctx.Catalog().Sequences, q.name, sequence.NewSequence(q.name, q.typ))
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 )
 
 type ddlSet struct {
+	command string
 	queries []DDLQuery
 }
 
@@ -15,19 +16,36 @@ type DDLQuery interface {
 	ExecuteDDL(ctx impls.ExecutionContext) error
 }
 
-func NewSet(queries []DDLQuery) queries.Query {
+var _ DDLQuery = &ddlSet{}
+
+// NewSet creates a query executing the given queries in order, which together
+// implement a single statement executing the given command.
+func NewSet(command string, queries []DDLQuery) DDLQuery {
 	return &ddlSet{
+		command: command,
 		queries: queries,
 	}
 }
 
+func (q *ddlSet) Command() string {
+	return q.command
+}
+
 func (q *ddlSet) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	if err := q.ExecuteDDL(ctx); err != nil {
+		w.Error(err)
+		return
+	}
+
+	w.Done()
+}
+
+func (q *ddlSet) ExecuteDDL(ctx impls.ExecutionContext) error {
 	for _, query := range q.queries {
 		if err := query.ExecuteDDL(ctx); err != nil {
-			w.Error(err)
-			return
+			return err
 		}
 	}
 
-	w.Done()
+	return nil
 }
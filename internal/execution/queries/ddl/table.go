@@ -1,7 +1,9 @@
 package ddl
 
 import (
-	"github.com/efritz/gostgres/internal/catalog/table"
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/catalog"
 	"github.com/efritz/gostgres/internal/execution/protocol"
 	"github.com/efritz/gostgres/internal/execution/queries"
 	"github.com/efritz/gostgres/internal/shared/impls"
@@ -10,18 +12,26 @@ import (
 type createTable struct {
 	name   string
 	fields []impls.TableField
+	method string
 }
 
 var _ queries.Query = &createTable{}
 var _ DDLQuery = &createTable{}
 
-func NewCreateTable(name string, fields []impls.TableField) *createTable {
+// NewCreateTable creates a table with the given table access method. If no method is
+// given, the catalog's default table access method is used.
+func NewCreateTable(name string, fields []impls.TableField, method string) *createTable {
 	return &createTable{
 		name:   name,
 		fields: fields,
+		method: method,
 	}
 }
 
+func (q *createTable) Command() string {
+	return "CREATE TABLE"
+}
+
 func (q *createTable) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
 	if err := q.ExecuteDDL(ctx); err != nil {
 		w.Error(err)
@@ -32,6 +42,55 @@ func (q *createTable) Execute(ctx impls.ExecutionContext, w protocol.ResponseWri
 }
 
 func (q *createTable) ExecuteDDL(ctx impls.ExecutionContext) error {
-	ctx.Catalog().Tables.Set(q.name, table.NewTable(q.name, q.fields))
+	// Other sessions may not use the table until the creating transaction ends
+	if err := ctx.LockTable(q.name, impls.AccessExclusiveLock); err != nil {
+		return err
+	}
+
+	method := q.method
+	if method == "" {
+		method = ctx.Catalog().DefaultTableAccessMethod
+	}
+
+	accessMethod, ok := ctx.Catalog().TableAccessMethods.Get(method)
+	if !ok {
+		return fmt.Errorf("unknown table access method %q", method)
+	}
+
+	table, err := accessMethod.NewTable(ctx, q.name, q.fields)
+	if err != nil {
+		return err
+	}
+
+	setCatalogEntry(ctx, ctx.Catalog().Tables, q.name, table)
 	return nil
 }
+
+// setCatalogEntry adds the given entry to the catalog. The catalog is restored
+// to its previous state if the enclosing transaction rolls back.
+func setCatalogEntry[T any](ctx impls.ExecutionContext, c *catalog.Catalog[T], name string, entry T) {
+	previous, ok := c.Get(name)
+	c.Set(name, entry)
+
+	ctx.OnRollback(func() {
+		if ok {
+			c.Set(name, previous)
+		} else {
+			c.Delete(name)
+		}
+	})
+}
+
+// lockTable acquires a lock on the table with the given name and returns it.
+func lockTable(ctx impls.ExecutionContext, name string, mode impls.LockMode) (impls.Table, error) {
+	if err := ctx.LockTable(name, mode); err != nil {
+		return nil, err
+	}
+
+	table, ok := ctx.Catalog().Tables.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown table %q", name)
+	}
+
+	return table, nil
+}
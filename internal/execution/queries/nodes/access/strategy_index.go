@@ -1,8 +1,6 @@
 package access
 
 import (
-	"fmt"
-
 	"github.com/efritz/gostgres/internal/execution/expressions"
 	"github.com/efritz/gostgres/internal/execution/queries/nodes"
 	"github.com/efritz/gostgres/internal/execution/serialization"
@@ -47,24 +45,29 @@ func (s *indexAccessStrategy[ScanOptions]) Ordering() impls.OrderExpression {
 func (s *indexAccessStrategy[ScanOptions]) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
 	ctx.Log("Building Index Access scanner Strategy")
 
+	if err := ctx.LockCatalogTable(s.table, impls.AccessShareLock); err != nil {
+		return nil, err
+	}
+
 	tidScanner, err := s.index.Scanner(ctx, s.opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return scan.RowScannerFunc(func() (rows.Row, error) {
+	return scan.WithMarkRestore(scan.RowScannerFunc(func() (rows.Row, error) {
 		ctx.Log("Scanning Index Access Strategy")
 
-		tid, err := tidScanner.Scan()
-		if err != nil {
-			return rows.Row{}, err
-		}
+		for {
+			tid, err := tidScanner.Scan()
+			if err != nil {
+				return rows.Row{}, err
+			}
 
-		row, ok := s.table.Row(tid)
-		if !ok {
-			return rows.Row{}, fmt.Errorf("row not found for tid %d", tid)
+			// Indexes reference every version of a row; skip those that
+			// are not visible to the current transaction
+			if row, ok, err := s.table.Row(ctx, tid); err != nil || ok {
+				return row, err
+			}
 		}
-
-		return row, nil
-	}), nil
+	}), tidScanner), nil
 }
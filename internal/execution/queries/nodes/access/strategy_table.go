@@ -1,8 +1,6 @@
 package access
 
 import (
-	"fmt"
-
 	"github.com/efritz/gostgres/internal/execution/queries/nodes"
 	"github.com/efritz/gostgres/internal/execution/serialization"
 	"github.com/efritz/gostgres/internal/shared/impls"
@@ -33,25 +31,57 @@ func (s *tableAccessStrategy) Ordering() impls.OrderExpression {
 func (s *tableAccessStrategy) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
 	ctx.Log("Building Table Access Strategy scanner")
 
-	tids := s.table.TIDs()
+	if err := ctx.LockCatalogTable(s.table, impls.AccessShareLock); err != nil {
+		return nil, err
+	}
 
-	i := 0
+	tids, err := s.table.TIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	return scan.RowScannerFunc(func() (rows.Row, error) {
-		ctx.Log("Scanning Table Access Strategy")
+	return &tableScanner{
+		ctx:   ctx,
+		table: s.table,
+		tids:  tids,
+		mark:  -1,
+	}, nil
+}
 
-		if i >= len(tids) {
-			return rows.Row{}, scan.ErrNoRows
-		}
+type tableScanner struct {
+	ctx   impls.ExecutionContext
+	table impls.Table
+	tids  []int64
+	next  int
+	mark  int
+}
+
+var _ scan.MarkRestorer = &tableScanner{}
 
-		tid := tids[i]
-		i++
+func (s *tableScanner) Scan() (rows.Row, error) {
+	s.ctx.Log("Scanning Table Access Strategy")
 
-		row, ok := s.table.Row(tid)
-		if !ok {
-			return rows.Row{}, fmt.Errorf("missing row %d", tid)
+	for s.next < len(s.tids) {
+		tid := s.tids[s.next]
+		s.next++
+
+		// Skip rows removed since the scan began
+		if row, ok, err := s.table.Row(s.ctx, tid); err != nil || ok {
+			return row, err
 		}
+	}
+
+	return rows.Row{}, scan.ErrNoRows
+}
+
+func (s *tableScanner) Mark() {
+	s.mark = s.next - 1
+}
+
+func (s *tableScanner) Restore() {
+	if s.mark == -1 {
+		panic("no mark to restore")
+	}
 
-		return row, nil
-	}), nil
+	s.next = s.mark
 }
@@ -0,0 +1,114 @@
+package combination
+
+import (
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	"github.com/efritz/gostgres/internal/execution/serialization"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/scan"
+	"github.com/efritz/gostgres/internal/shared/utils"
+)
+
+type recursiveUnionNode struct {
+	base      nodes.Node
+	recursive nodes.Node
+	working   *nodes.WorkingTable
+	fields    []fields.Field
+	distinct  bool
+}
+
+// NewRecursiveUnion creates a node emitting the rows of the base node followed by the
+// rows of each evaluation of the recursive node. Each evaluation reads the rows emitted
+// by the previous evaluation (or by the base node) from the given working table, and
+// evaluation stops once no rows are emitted. Duplicate rows are discarded if distinct
+// is true.
+func NewRecursiveUnion(base, recursive nodes.Node, working *nodes.WorkingTable, fields []fields.Field, distinct bool) nodes.Node {
+	return &recursiveUnionNode{
+		base:      base,
+		recursive: recursive,
+		working:   working,
+		fields:    fields,
+		distinct:  distinct,
+	}
+}
+
+func (n *recursiveUnionNode) Serialize(w serialization.IndentWriter) {
+	if n.distinct {
+		w.WritefLine("recursive union")
+	} else {
+		w.WritefLine("recursive union all")
+	}
+	n.base.Serialize(w.Indent())
+	w.WritefLine("with")
+	n.recursive.Serialize(w.Indent())
+}
+
+func (n *recursiveUnionNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
+	ctx.Log("Building Recursive Union scanner")
+
+	hash := map[string]struct{}{}
+	mark := func(row rows.Row) bool {
+		if !n.distinct {
+			return true
+		}
+
+		key := utils.HashSlice(row.Values)
+		if _, ok := hash[key]; ok {
+			return false
+		}
+
+		hash[key] = struct{}{}
+		return true
+	}
+
+	scanner, err := n.base.Scanner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows emitted by the current evaluation, read by the next
+	var emitted []rows.Row
+
+	return scan.RowScannerFunc(func() (rows.Row, error) {
+		ctx.Log("Scanning Recursive Union")
+
+		for scanner != nil {
+			row, err := scanner.Scan()
+			if err != nil {
+				if err != scan.ErrNoRows {
+					return rows.Row{}, err
+				}
+
+				if len(emitted) == 0 {
+					scanner = nil
+					break
+				}
+
+				n.working.SetRows(emitted)
+				emitted = nil
+
+				scanner, err = n.recursive.Scanner(ctx)
+				if err != nil {
+					return rows.Row{}, err
+				}
+
+				continue
+			}
+
+			if !mark(row) {
+				continue
+			}
+
+			row, err = rows.NewRow(n.fields, row.Values)
+			if err != nil {
+				return rows.Row{}, err
+			}
+
+			emitted = append(emitted, row)
+			return row, nil
+		}
+
+		return rows.Row{}, scan.ErrNoRows
+	}), nil
+}
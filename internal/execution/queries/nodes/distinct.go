@@ -0,0 +1,132 @@
+package nodes
+
+import (
+	"strings"
+
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/execution/serialization"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/scan"
+	"github.com/efritz/gostgres/internal/shared/utils"
+)
+
+type distinctNode struct {
+	Node
+	expressions []impls.Expression
+}
+
+// NewDistinct creates a node emitting the first row of the given node for each distinct
+// set of values of the given expressions. Rows seen so far are tracked in a hash set.
+func NewDistinct(node Node, expressions []impls.Expression) Node {
+	return &distinctNode{
+		Node:        node,
+		expressions: expressions,
+	}
+}
+
+func (n *distinctNode) Serialize(w serialization.IndentWriter) {
+	w.WritefLine("hash distinct on %s", serializeExpressions(n.expressions))
+	n.Node.Serialize(w.Indent())
+}
+
+func (n *distinctNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
+	ctx.Log("Building Hash Distinct scanner")
+
+	scanner, err := n.Node.Scanner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]struct{}{}
+
+	return scan.RowScannerFunc(func() (rows.Row, error) {
+		ctx.Log("Scanning Hash Distinct")
+
+		for {
+			row, err := scanner.Scan()
+			if err != nil {
+				return rows.Row{}, err
+			}
+
+			values, err := queries.EvaluateExpressions(ctx, n.expressions, row)
+			if err != nil {
+				return rows.Row{}, err
+			}
+
+			key := utils.HashSlice(values)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+
+			seen[key] = struct{}{}
+			return row, nil
+		}
+	}), nil
+}
+
+//
+//
+
+type uniqueNode struct {
+	Node
+	expressions []impls.Expression
+}
+
+// NewUnique creates a node emitting the first row of the given node for each distinct
+// set of values of the given expressions. Rows with equal values must be adjacent in
+// the input.
+func NewUnique(node Node, expressions []impls.Expression) Node {
+	return &uniqueNode{
+		Node:        node,
+		expressions: expressions,
+	}
+}
+
+func (n *uniqueNode) Serialize(w serialization.IndentWriter) {
+	w.WritefLine("unique on %s", serializeExpressions(n.expressions))
+	n.Node.Serialize(w.Indent())
+}
+
+func (n *uniqueNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
+	ctx.Log("Building Unique scanner")
+
+	scanner, err := n.Node.Scanner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous []any
+
+	return scan.RowScannerFunc(func() (rows.Row, error) {
+		ctx.Log("Scanning Unique")
+
+		for {
+			row, err := scanner.Scan()
+			if err != nil {
+				return rows.Row{}, err
+			}
+
+			values, err := queries.EvaluateExpressions(ctx, n.expressions, row)
+			if err != nil {
+				return rows.Row{}, err
+			}
+
+			if previous != nil && valuesEqual(previous, values) {
+				continue
+			}
+
+			previous = values
+			return row, nil
+		}
+	}), nil
+}
+
+func serializeExpressions(expressions []impls.Expression) string {
+	strExpressions := make([]string, 0, len(expressions))
+	for _, expr := range expressions {
+		strExpressions = append(strExpressions, expr.String())
+	}
+
+	return strings.Join(strExpressions, ", ")
+}
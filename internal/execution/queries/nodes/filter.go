@@ -34,7 +34,7 @@ func (n *filterNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error
 		return nil, err
 	}
 
-	return scan.RowScannerFunc(func() (rows.Row, error) {
+	return scan.WithMarkRestore(scan.RowScannerFunc(func() (rows.Row, error) {
 		ctx.Log("Scanning Filter")
 
 		for {
@@ -51,5 +51,5 @@ func (n *filterNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error
 
 			return row, nil
 		}
-	}), nil
+	}), scanner), nil
 }
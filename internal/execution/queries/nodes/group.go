@@ -10,6 +10,7 @@ import (
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/shared/rows"
 	"github.com/efritz/gostgres/internal/shared/scan"
+	"github.com/efritz/gostgres/internal/shared/types"
 	"github.com/efritz/gostgres/internal/shared/utils"
 	"golang.org/x/exp/maps"
 )
@@ -17,13 +18,15 @@ import (
 type groupNode struct {
 	Node
 	groupExpressions []impls.Expression
+	having           impls.Expression
 	projection       *projection.Projection
 }
 
-func NewGroup(node Node, groupExpressions []impls.Expression, projection *projection.Projection) Node {
+func NewGroup(node Node, groupExpressions []impls.Expression, having impls.Expression, projection *projection.Projection) Node {
 	return &groupNode{
 		Node:             node,
 		groupExpressions: groupExpressions,
+		having:           having,
 		projection:       projection,
 	}
 }
@@ -34,7 +37,11 @@ func (n *groupNode) Serialize(w serialization.IndentWriter) {
 		strExpressions = append(strExpressions, expr.String())
 	}
 
-	w.WritefLine("group by %s, project %s", strings.Join(strExpressions, ", "), n.projection)
+	if n.having != nil {
+		w.WritefLine("group by %s, having %s, project %s", strings.Join(strExpressions, ", "), n.having, n.projection)
+	} else {
+		w.WritefLine("group by %s, project %s", strings.Join(strExpressions, ", "), n.projection)
+	}
 	n.Node.Serialize(w.Indent())
 }
 
@@ -52,6 +59,10 @@ func (n *groupNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error)
 		for _, projectedExpression := range n.projection.Aliases() {
 			aggregateExpressions = append(aggregateExpressions, expressions.AsAggregate(ctx, projectedExpression.Expression))
 		}
+		if n.having != nil {
+			// The having condition is evaluated after the projected expressions
+			aggregateExpressions = append(aggregateExpressions, expressions.AsAggregate(ctx, n.having))
+		}
 
 		buckets[key] = aggregateExpressions
 		return aggregateExpressions, nil
@@ -91,23 +102,34 @@ func (n *groupNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error)
 	return scan.RowScannerFunc(func() (rows.Row, error) {
 		ctx.Log("Scanning Hash Aggregate")
 
-		if i >= len(keys) {
-			return rows.Row{}, scan.ErrNoRows
-		}
+		for i < len(keys) {
+			aggregateExpressions := buckets[keys[i]]
+			i++
 
-		aggregateExpressions := buckets[keys[i]]
-		i++
+			var values []any
+			for _, aggregateExpression := range aggregateExpressions {
+				value, err := aggregateExpression.Done(ctx)
+				if err != nil {
+					return rows.Row{}, err
+				}
 
-		var values []any
-		for _, aggregateExpression := range aggregateExpressions {
-			value, err := aggregateExpression.Done(ctx)
-			if err != nil {
-				return rows.Row{}, err
+				values = append(values, value)
+			}
+
+			if n.having != nil {
+				condition := values[len(values)-1]
+				values = values[:len(values)-1]
+
+				if ok, err := types.ValueAs[bool](condition, nil); err != nil {
+					return rows.Row{}, err
+				} else if ok == nil || !*ok {
+					continue
+				}
 			}
 
-			values = append(values, value)
+			return rows.Row{Fields: n.projection.Fields(), Values: values}, nil
 		}
 
-		return rows.Row{Fields: n.projection.Fields(), Values: values}, nil
+		return rows.Row{}, scan.ErrNoRows
 	}), nil
 }
@@ -13,25 +13,34 @@ type JoinStrategy interface {
 }
 
 type joinNode struct {
-	left     Node
-	right    Node
-	filter   impls.Expression
-	fields   []fields.Field
-	strategy JoinStrategy
+	left          Node
+	right         Node
+	joinType      string
+	filter        impls.Expression
+	fields        []fields.Field
+	strategy      JoinStrategy
+	estimatedRows float64
+	estimatedCost float64
 }
 
-func NewJoin(left, right Node, filter impls.Expression, fields []fields.Field, strategy JoinStrategy) Node {
+// NewJoin creates a node joining the rows of the given nodes with the given strategy.
+// The type of the join (e.g., "left join") and its estimated rows and cost are reported
+// when the node is serialized.
+func NewJoin(left, right Node, joinType string, filter impls.Expression, fields []fields.Field, strategy JoinStrategy, estimatedRows, estimatedCost float64) Node {
 	return &joinNode{
-		left:     left,
-		right:    right,
-		filter:   filter,
-		fields:   fields,
-		strategy: strategy,
+		left:          left,
+		right:         right,
+		joinType:      joinType,
+		filter:        filter,
+		fields:        fields,
+		strategy:      strategy,
+		estimatedRows: estimatedRows,
+		estimatedCost: estimatedCost,
 	}
 }
 
 func (n *joinNode) Serialize(w serialization.IndentWriter) {
-	w.WritefLine("join using %s", n.strategy.Name())
+	w.WritefLine("%s using %s (rows=%.0f cost=%.2f)", n.joinType, n.strategy.Name(), n.estimatedRows, n.estimatedCost)
 	n.left.Serialize(w.Indent())
 	w.WritefLine("with")
 	n.right.Serialize(w.Indent())
@@ -0,0 +1,20 @@
+package join
+
+import (
+	"slices"
+
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/rows"
+)
+
+// extendLeftRow returns a joined row in which the values of the given row of the left
+// relation are followed by NULL values for each field of the right relation.
+func extendLeftRow(fields []fields.Field, leftRow rows.Row) (rows.Row, error) {
+	return rows.NewRow(fields, append(slices.Clone(leftRow.Values), make([]any, len(fields)-len(leftRow.Values))...))
+}
+
+// extendRightRow returns a joined row in which the values of the given row of the right
+// relation are preceded by NULL values for each field of the left relation.
+func extendRightRow(fields []fields.Field, rightRow rows.Row) (rows.Row, error) {
+	return rows.NewRow(fields, append(make([]any, len(fields)-len(rightRow.Values)), rightRow.Values...))
+}
@@ -3,12 +3,16 @@ package join
 import (
 	"github.com/efritz/gostgres/internal/execution/queries"
 	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/ordering"
 	"github.com/efritz/gostgres/internal/shared/rows"
 )
 
 type EqualityPair struct {
 	Left  impls.Expression
 	Right impls.Expression
+
+	// NullAware pairs also match when either key is NULL (e.g., the keys of NOT IN)
+	NullAware bool
 }
 
 var leftOfPair = func(pair EqualityPair) impls.Expression { return pair.Left }
@@ -26,3 +30,31 @@ func evaluatePair(ctx impls.ExecutionContext, pairs []EqualityPair, expression f
 
 	return values, nil
 }
+
+// keysMatch returns true if the given join keys of a row of the left relation match the
+// given join keys of a row of the right relation.
+func keysMatch(pairs []EqualityPair, leftKeys, rightKeys []any) bool {
+	for i, pair := range pairs {
+		if pair.NullAware && (leftKeys[i] == nil || rightKeys[i] == nil) {
+			continue
+		}
+
+		if ordering.CompareValues(leftKeys[i], rightKeys[i]) != ordering.OrderTypeEqual {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasNullAwareNull returns true if the given join keys have a NULL value for a
+// null-aware pair.
+func hasNullAwareNull(pairs []EqualityPair, keys []any) bool {
+	for i, pair := range pairs {
+		if pair.NullAware && keys[i] == nil {
+			return true
+		}
+	}
+
+	return false
+}
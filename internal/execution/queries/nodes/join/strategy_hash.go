@@ -6,25 +6,28 @@ import (
 	"github.com/efritz/gostgres/internal/execution/queries/nodes"
 	"github.com/efritz/gostgres/internal/shared/fields"
 	"github.com/efritz/gostgres/internal/shared/impls"
-	"github.com/efritz/gostgres/internal/shared/ordering"
 	"github.com/efritz/gostgres/internal/shared/rows"
 	"github.com/efritz/gostgres/internal/shared/scan"
 	"github.com/efritz/gostgres/internal/shared/utils"
 )
 
 type hashJoinStrategy struct {
-	left   nodes.Node
-	right  nodes.Node
-	pairs  []EqualityPair
-	fields []fields.Field
+	left     nodes.Node
+	right    nodes.Node
+	joinType JoinType
+	pairs    []EqualityPair
+	filter   impls.Expression
+	fields   []fields.Field
 }
 
-func NewHashJoinStrategy(left nodes.Node, right nodes.Node, pairs []EqualityPair, fields []fields.Field) nodes.JoinStrategy {
+func NewHashJoinStrategy(left nodes.Node, right nodes.Node, joinType JoinType, pairs []EqualityPair, filter impls.Expression, fields []fields.Field) nodes.JoinStrategy {
 	return &hashJoinStrategy{
-		left:   left,
-		right:  right,
-		pairs:  pairs,
-		fields: fields,
+		left:     left,
+		right:    right,
+		joinType: joinType,
+		pairs:    pairs,
+		filter:   filter,
+		fields:   fields,
 	}
 }
 
@@ -32,6 +35,12 @@ func (s *hashJoinStrategy) Name() string {
 	return "hash"
 }
 
+// hashEntry is a row of the right relation stored in the hash table.
+type hashEntry struct {
+	row     rows.Row
+	matched bool
+}
+
 func (s *hashJoinStrategy) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
 	ctx.Log("Building Hash Join Strategy scanner")
 
@@ -40,15 +49,25 @@ func (s *hashJoinStrategy) Scanner(ctx impls.ExecutionContext) (scan.RowScanner,
 		return nil, err
 	}
 
-	h := map[uint64][]rows.Row{}
+	h := map[uint64][]*hashEntry{}
+	var entries []*hashEntry     // in scan order
+	var nullEntries []*hashEntry // with a NULL null-aware key, matching every row
 	if err := scan.VisitRows(rightScanner, func(row rows.Row) (bool, error) {
 		keys, err := evaluatePair(ctx, s.pairs, rightOfPair, row)
 		if err != nil {
 			return false, err
 		}
 
-		key := utils.Hash(keys)
-		h[key] = append(h[key], row)
+		entry := &hashEntry{row: row}
+		entries = append(entries, entry)
+
+		if hasNullAwareNull(s.pairs, keys) {
+			nullEntries = append(nullEntries, entry)
+		} else {
+			key := utils.Hash(keys)
+			h[key] = append(h[key], entry)
+		}
+
 		return true, nil
 	}); err != nil {
 		return nil, err
@@ -59,44 +78,121 @@ func (s *hashJoinStrategy) Scanner(ctx impls.ExecutionContext) (scan.RowScanner,
 		return nil, err
 	}
 
-	var leftRow rows.Row
-	var rightRows []rows.Row
+	allEntries := entries
+
+	var (
+		leftRow       rows.Row
+		hasLeftRow    bool
+		leftMatched   bool
+		leftExhausted bool
+		rightEntries  []*hashEntry
+	)
 
 	return scan.RowScannerFunc(func() (rows.Row, error) {
 		ctx.Log("Scanning Hash Join Strategy")
 
 		for {
-			for len(rightRows) > 0 {
-				rightRow := rightRows[0]
-				rightRows = rightRows[1:]
+			for len(rightEntries) > 0 {
+				entry := rightEntries[0]
+				rightEntries = rightEntries[1:]
 
 				lKeys, err := evaluatePair(ctx, s.pairs, leftOfPair, leftRow)
 				if err != nil {
 					return rows.Row{}, err
 				}
 
-				rKeys, err := evaluatePair(ctx, s.pairs, rightOfPair, rightRow)
+				rKeys, err := evaluatePair(ctx, s.pairs, rightOfPair, entry.row)
+				if err != nil {
+					return rows.Row{}, err
+				}
+
+				if !keysMatch(s.pairs, lKeys, rKeys) {
+					continue
+				}
+
+				row, err := rows.NewRow(s.fields, append(slices.Clone(leftRow.Values), entry.row.Values...))
 				if err != nil {
 					return rows.Row{}, err
 				}
 
-				if ordering.CompareValueSlices(lKeys, rKeys) == ordering.OrderTypeEqual {
-					return rows.NewRow(s.fields, append(slices.Clone(leftRow.Values), rightRow.Values...))
+				if ok, err := evaluateFilter(ctx, s.filter, row); err != nil {
+					return rows.Row{}, err
+				} else if ok {
+					leftMatched = true
+					entry.matched = true
+
+					switch s.joinType {
+					case JoinTypeSemi:
+						// Each row of the left relation is emitted once, on its first match
+						rightEntries = nil
+						hasLeftRow = false
+						return leftRow, nil
+
+					case JoinTypeAnti:
+						// Rows of the left relation with a match are discarded
+						rightEntries = nil
+						continue
+					}
+
+					return row, nil
 				}
 			}
 
+			if hasLeftRow {
+				hasLeftRow = false
+
+				if !leftMatched {
+					switch s.joinType {
+					case JoinTypeLeftOuter, JoinTypeFullOuter:
+						return extendLeftRow(s.fields, leftRow)
+					case JoinTypeAnti:
+						return leftRow, nil
+					}
+				}
+			}
+
+			if leftExhausted {
+				// Emit the rows of the right relation that matched no row of the left relation
+				for len(entries) > 0 {
+					entry := entries[0]
+					entries = entries[1:]
+
+					if !entry.matched {
+						return extendRightRow(s.fields, entry.row)
+					}
+				}
+
+				return rows.Row{}, scan.ErrNoRows
+			}
+
 			leftRow, err = leftScanner.Scan()
 			if err != nil {
+				if err == scan.ErrNoRows && s.joinType == JoinTypeFullOuter {
+					leftExhausted = true
+					continue
+				}
+
 				return rows.Row{}, err
 			}
+			hasLeftRow = true
+			leftMatched = false
 
 			lKeys, err := evaluatePair(ctx, s.pairs, leftOfPair, leftRow)
 			if err != nil {
 				return rows.Row{}, err
 			}
 
+			if hasNullAwareNull(s.pairs, lKeys) {
+				// The row may match a row of the right relation with any key
+				rightEntries = allEntries
+				continue
+			}
+
 			// TODO - handle hash collision
-			rightRows = h[utils.Hash(lKeys)]
+			rightEntries = h[utils.Hash(lKeys)]
+			if len(nullEntries) > 0 {
+				rightEntries = append(slices.Clone(rightEntries), nullEntries...)
+			}
 		}
 	}), nil
 }
@@ -1,6 +1,7 @@
 package join
 
 import (
+	"fmt"
 	"slices"
 
 	"github.com/efritz/gostgres/internal/execution/queries/nodes"
@@ -12,18 +13,26 @@ import (
 )
 
 type mergeJoinStrategy struct {
-	left   nodes.Node
-	right  nodes.Node
-	pairs  []EqualityPair
-	fields []fields.Field
+	left     nodes.Node
+	right    nodes.Node
+	joinType JoinType
+	pairs    []EqualityPair
+	filter   impls.Expression
+	fields   []fields.Field
 }
 
-func NewMergeJoinStrategy(left nodes.Node, right nodes.Node, pairs []EqualityPair, fields []fields.Field) nodes.JoinStrategy {
+// NewMergeJoinStrategy creates a strategy joining relations ordered by the given pairs
+// of join keys. The residual filter of a full outer join must be nil: a row of the right
+// relation is considered matched when a row of the left relation shares its join keys.
+// Semi and anti joins, and null-aware pairs, are not supported.
+func NewMergeJoinStrategy(left nodes.Node, right nodes.Node, joinType JoinType, pairs []EqualityPair, filter impls.Expression, fields []fields.Field) nodes.JoinStrategy {
 	return &mergeJoinStrategy{
-		left:   left,
-		right:  right,
-		pairs:  pairs,
-		fields: fields,
+		left:     left,
+		right:    right,
+		joinType: joinType,
+		pairs:    pairs,
+		filter:   filter,
+		fields:   fields,
 	}
 }
 
@@ -46,7 +55,14 @@ func (s *mergeJoinStrategy) Scanner(ctx impls.ExecutionContext) (scan.RowScanner
 
 	markRestorer, ok := rightScanner.(scan.MarkRestorer)
 	if !ok {
-		panic("right scanner is not a MarkRestorer")
+		// The right relation must be revisited from the last mark when the left relation
+		// contains duplicate values; buffer it if the scanner cannot do so itself
+		materialized, err := newMaterializedScanner(rightScanner)
+		if err != nil {
+			return nil, err
+		}
+
+		rightScanner, markRestorer = materialized, materialized
 	}
 
 	return &mergeJoinScanner{
@@ -66,113 +82,213 @@ type mergeJoinScanner struct {
 	markRestorer scan.MarkRestorer
 
 	// state
-	leftRow  *rows.Row
-	rightRow *rows.Row
-	mark     *rows.Row
-	matching bool
+	started     bool
+	leftRow     *rows.Row // nil once the left relation is exhausted
+	rightRow    *rows.Row // nil once the right relation is exhausted
+	mark        *rows.Row // the first row of the right relation matching leftRow
+	matching    bool      // true while scanning the rows of the right relation matching leftRow
+	leftMatched bool      // true once leftRow has been emitted as part of a joined row
 }
 
 func (s *mergeJoinScanner) Scan() (rows.Row, error) {
 	s.ctx.Log("Scanning Merge Join Strategy")
-	if s.leftRow == nil {
-		// Get next row from left relation. This is necessary only on the first
-		// iteration of the scan. After that, the left row is always non-nil.
+
+	if !s.started {
+		s.started = true
+
 		if err := s.advanceLeft(); err != nil {
 			return rows.Row{}, err
 		}
+		if err := s.advanceRight(); err != nil {
+			return rows.Row{}, err
+		}
 	}
 
 	for {
-		if s.rightRow == nil {
-			// Get next row from right relation. This is necessary on the first
-			// iteration of the scan, as well as immediately after we have found
-			// a matching pair of rows and need to find the next one.
-			if err := s.advanceRight(); err != nil {
-				if err != scan.ErrNoRows {
-					return rows.Row{}, err
-				}
-
-				if !s.matching {
-					// When we exhaust the right relation, we only want to halt
-					// when we will no longer restore a previous mark position.
-					return rows.Row{}, err
-				}
+		if s.matching {
+			row, ok, err := s.nextMatch()
+			if err != nil || ok {
+				return row, err
 			}
+
+			continue
 		}
 
-		// If we are had not yet found a pair of matching rows, we need to continue
-		// to advance the smaller of the two relations until we find a pair that match.
-		// When we find a match, we'll set the mark to the current right row and drop
-		// to the next phase of the join logic.
-		if !s.matching {
-			if err := s.findNextMatch(); err != nil {
-				return rows.Row{}, err
-			}
+		row, ok, err := s.seekMatch()
+		if err != nil || ok {
+			return row, err
+		}
+	}
+}
 
-			s.matching = true
-			s.mark = s.rightRow
-			s.markRestorer.Mark()
+// nextMatch pairs the current row of the left relation with the next matching row of
+// the right relation. When the matching rows are exhausted the left relation is advanced
+// and the right relation is restored to the mark if the new left row shares the same join
+// keys. A row is returned when the pair satisfies the residual filter, or when the left
+// row of an outer join is left without a match.
+func (s *mergeJoinScanner) nextMatch() (rows.Row, bool, error) {
+	if s.rightRow != nil {
+		ot, err := s.compareRows(*s.leftRow, *s.rightRow)
+		if err != nil {
+			return rows.Row{}, false, err
 		}
 
-		if s.rightRow != nil {
-			// Check if the current pair of rows match. If they are, we'll emit the
-			// pair and note that we need to advance the right relation on the next
-			// iteration of the scan.
-			if ot, err := s.compareRows(*s.leftRow, *s.rightRow); err != nil {
-				return rows.Row{}, err
-			} else if ot == ordering.OrderTypeEqual {
-				row, err := rows.NewRow(s.strategy.fields, append(slices.Clone(s.leftRow.Values), s.rightRow.Values...))
-				if err != nil {
-					return rows.Row{}, err
-				}
-
-				s.rightRow = nil
-				return row, nil
+		if ot == ordering.OrderTypeEqual {
+			row, err := rows.NewRow(s.strategy.fields, append(slices.Clone(s.leftRow.Values), s.rightRow.Values...))
+			if err != nil {
+				return rows.Row{}, false, err
 			}
-		}
 
-		// The current pair of rows did not match. We need to advance the left relation
-		// to the next row. If the left relation matches our previous mark, we need to
-		// restore the right relation to the mark and begin scanning from there. This is
-		// necessary in the presence of duplicate values in the left relation.
+			if err := s.advanceRight(); err != nil {
+				return rows.Row{}, false, err
+			}
 
-		if err := s.advanceLeft(); err != nil {
-			return rows.Row{}, err
+			if ok, err := evaluateFilter(s.ctx, s.strategy.filter, row); err != nil || !ok {
+				return rows.Row{}, false, err
+			}
+
+			s.leftMatched = true
+			return row, true, nil
 		}
+	}
+
+	// The current row of the left relation has been compared with each matching row of
+	// the right relation. If the next row of the left relation shares the same join keys
+	// we need to restore the right relation to the mark and begin scanning from there.
+	// This is necessary in the presence of duplicate values in the left relation.
 
+	finishedRow, finishedMatched := *s.leftRow, s.leftMatched
+	if err := s.advanceLeft(); err != nil {
+		return rows.Row{}, false, err
+	}
+
+	s.matching = false
+	if s.leftRow != nil {
 		if ot, err := s.compareRows(*s.leftRow, *s.mark); err != nil {
-			return rows.Row{}, err
+			return rows.Row{}, false, err
 		} else if ot == ordering.OrderTypeEqual {
-			s.rightRow = nil
 			s.markRestorer.Restore()
-		} else {
-			s.matching = false
+			if err := s.advanceRight(); err != nil {
+				return rows.Row{}, false, err
+			}
+
+			s.matching = true
+			s.leftMatched = false
 		}
 	}
+
+	if !finishedMatched && s.preservesLeft() {
+		row, err := extendLeftRow(s.strategy.fields, finishedRow)
+		return row, err == nil, err
+	}
+
+	return rows.Row{}, false, nil
 }
 
-func (s *mergeJoinScanner) findNextMatch() error {
-	for {
-		ot, err := s.compareRows(*s.leftRow, *s.rightRow)
-		if err != nil {
-			return err
+// seekMatch advances the smaller of the current rows of the two relations until a pair
+// of rows with matching join keys is found, at which point the right relation is marked.
+// A row is returned when a row passed over must be extended with NULL values by an outer
+// join.
+func (s *mergeJoinScanner) seekMatch() (rows.Row, bool, error) {
+	if s.leftRow == nil {
+		if s.rightRow == nil || !s.preservesRight() {
+			return rows.Row{}, false, scan.ErrNoRows
 		}
 
-		switch ot {
-		case ordering.OrderTypeEqual:
-			return nil
+		return s.skipRight()
+	}
 
-		case ordering.OrderTypeBefore:
-			if err := s.advanceLeft(); err != nil {
-				return err
-			}
+	if s.rightRow == nil {
+		if !s.preservesLeft() {
+			return rows.Row{}, false, scan.ErrNoRows
+		}
 
-		case ordering.OrderTypeAfter:
-			if err := s.advanceRight(); err != nil {
-				return err
-			}
+		return s.skipLeft()
+	}
+
+	ot, err := s.compareRows(*s.leftRow, *s.rightRow)
+	if err != nil {
+		return rows.Row{}, false, err
+	}
+
+	switch ot {
+	case ordering.OrderTypeEqual:
+		s.matching = true
+		s.leftMatched = false
+		s.mark = s.rightRow
+		s.markRestorer.Mark()
+		return rows.Row{}, false, nil
+
+	case ordering.OrderTypeBefore:
+		return s.skipLeft()
+
+	case ordering.OrderTypeAfter:
+		return s.skipRight()
+
+	case ordering.OrderTypeNulls:
+		// NULL keys never match; skip the row(s) containing them
+		leftNull, err := s.hasNullKey(*s.leftRow, leftOfPair)
+		if err != nil {
+			return rows.Row{}, false, err
 		}
+
+		if leftNull {
+			return s.skipLeft()
+		}
+
+		return s.skipRight()
+	}
+
+	return rows.Row{}, false, fmt.Errorf("incomparable join keys")
+}
+
+// skipLeft advances past the current row of the left relation, which matches no row of
+// the right relation. The row is returned extended with NULL values for outer joins.
+func (s *mergeJoinScanner) skipLeft() (rows.Row, bool, error) {
+	skippedRow := *s.leftRow
+	if err := s.advanceLeft(); err != nil {
+		return rows.Row{}, false, err
+	}
+
+	if !s.preservesLeft() {
+		return rows.Row{}, false, nil
+	}
+
+	row, err := extendLeftRow(s.strategy.fields, skippedRow)
+	return row, err == nil, err
+}
+
+// skipRight advances past the current row of the right relation, which matches no row
+// of the left relation. The row is returned extended with NULL values for full joins.
+func (s *mergeJoinScanner) skipRight() (rows.Row, bool, error) {
+	skippedRow := *s.rightRow
+	if err := s.advanceRight(); err != nil {
+		return rows.Row{}, false, err
+	}
+
+	if !s.preservesRight() {
+		return rows.Row{}, false, nil
+	}
+
+	row, err := extendRightRow(s.strategy.fields, skippedRow)
+	return row, err == nil, err
+}
+
+func (s *mergeJoinScanner) preservesLeft() bool {
+	return s.strategy.joinType == JoinTypeLeftOuter || s.strategy.joinType == JoinTypeFullOuter
+}
+
+func (s *mergeJoinScanner) preservesRight() bool {
+	return s.strategy.joinType == JoinTypeFullOuter
+}
+
+func (s *mergeJoinScanner) hasNullKey(row rows.Row, expression func(EqualityPair) impls.Expression) (bool, error) {
+	values, err := evaluatePair(s.ctx, s.strategy.pairs, expression, row)
+	if err != nil {
+		return false, err
 	}
+
+	return slices.Contains(values, nil), nil
 }
 
 func (s *mergeJoinScanner) advanceLeft() error {
@@ -183,10 +299,17 @@ func (s *mergeJoinScanner) advanceRight() error {
 	return scanIntoTarget(s.rightScanner, &s.rightRow)
 }
 
+// scanIntoTarget sets the target to the next row of the given scanner, or to nil if the
+// scanner is exhausted.
 func scanIntoTarget(scanner scan.RowScanner, target **rows.Row) error {
 	row, err := scanner.Scan()
 	if err != nil {
 		*target = nil
+
+		if err == scan.ErrNoRows {
+			return nil
+		}
+
 		return err
 	}
 
@@ -207,3 +330,53 @@ func (s *mergeJoinScanner) compareRows(leftRow, rightRow rows.Row) (ordering.Ord
 
 	return ordering.CompareValueSlices(lValues, rValues), nil
 }
+
+//
+//
+
+// materializedScanner returns the rows of a fully consumed scanner, supporting mark and
+// restore.
+type materializedScanner struct {
+	rows []rows.Row
+	next int
+	mark int
+}
+
+var _ scan.MarkRestorer = &materializedScanner{}
+
+func newMaterializedScanner(scanner scan.RowScanner) (*materializedScanner, error) {
+	var materialized []rows.Row
+	if err := scan.VisitRows(scanner, func(row rows.Row) (bool, error) {
+		materialized = append(materialized, row)
+		return true, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &materializedScanner{
+		rows: materialized,
+		mark: -1,
+	}, nil
+}
+
+func (s *materializedScanner) Scan() (rows.Row, error) {
+	if s.next < len(s.rows) {
+		row := s.rows[s.next]
+		s.next++
+		return row, nil
+	}
+
+	return rows.Row{}, scan.ErrNoRows
+}
+
+func (s *materializedScanner) Mark() {
+	s.mark = s.next - 1
+}
+
+func (s *materializedScanner) Restore() {
+	if s.mark == -1 {
+		panic("no mark to restore")
+	}
+
+	s.next = s.mark
+}
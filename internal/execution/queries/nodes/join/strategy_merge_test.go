@@ -0,0 +1,120 @@
+package join
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/catalog/table"
+	"github.com/efritz/gostgres/internal/catalog/table/indexes"
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	"github.com/efritz/gostgres/internal/execution/queries/nodes/access"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/scan"
+	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeJoinStrategy(t *testing.T) {
+	ctx := impls.EmptyExecutionContext
+
+	// Keys are inserted out of order; both relations are read in order from their indexes
+	left, leftKey := newIndexedTable(t, "l", 3, 1, 6, 2, 2, 5, 4, 6)
+	right, rightKey := newIndexedTable(t, "r", 2, 4, 2, 0, 4, 6)
+
+	leftNode := newIndexScan(left)
+	rightNode := nodes.NewFilter(newIndexScan(right), expressions.NewGreaterThan(expressions.NewNamed(rightKey), expressions.NewConstant(int32(0))))
+
+	// The index scan (and the filter above it) can return to a marked row directly
+	rightScanner, err := rightNode.Scanner(ctx)
+	require.NoError(t, err)
+	_, ok := rightScanner.(scan.MarkRestorer)
+	require.True(t, ok)
+
+	// Each duplicate on the left is matched with each duplicate on the right
+	assert.Equal(t, []string{"2=2", "2=2", "2=2", "2=2", "4=4", "4=4", "6=6", "6=6"}, mergeJoin(t, leftNode, rightNode, leftKey, rightKey, nil))
+}
+
+func TestMergeJoinStrategyNullKeys(t *testing.T) {
+	left, leftKey := newIndexedTable(t, "l", nil, 1, 3, nil, 2)
+	right, rightKey := newIndexedTable(t, "r", 2, nil, 1, nil, 2)
+
+	// NULL keys never match one another
+	assert.Equal(t, []string{"1=1", "2=2", "2=2"}, mergeJoin(t, newIndexScan(left), newIndexScan(right), leftKey, rightKey, nil))
+}
+
+func TestMergeJoinStrategyFilter(t *testing.T) {
+	left, leftKey := newIndexedTable(t, "l", 1, 2, 2, 3)
+	right, rightKey := newIndexedTable(t, "r", 1, 2, 3, 3)
+
+	// Matching pairs not satisfying the residual filter are skipped
+	filter := expressions.NewLessThan(expressions.NewNamed(leftKey), expressions.NewConstant(int32(3)))
+	assert.Equal(t, []string{"1=1", "2=2", "2=2"}, mergeJoin(t, newIndexScan(left), newIndexScan(right), leftKey, rightKey, filter))
+}
+
+func newIndexedTable(t *testing.T, name string, keys ...any) (impls.Table, fields.Field) {
+	ctx := impls.EmptyExecutionContext
+	key := impls.NewTableField(name, "key", types.TypeInteger, fields.NonInternalField)
+	tbl := table.NewTable(name, []impls.TableField{key})
+	keyField := tbl.Fields()[1].Field
+
+	require.NoError(t, tbl.AddIndex(ctx, indexes.NewBTreeIndex(name+"_key", name, false, []impls.ExpressionWithDirection{
+		{Expression: expressions.NewNamed(keyField)},
+	})))
+
+	for _, k := range keys {
+		var value any
+		if k != nil {
+			value = int32(k.(int))
+		}
+
+		row, err := rows.NewRow([]fields.Field{keyField}, []any{value})
+		require.NoError(t, err)
+		_, err = tbl.Insert(ctx, row)
+		require.NoError(t, err)
+	}
+
+	return tbl, keyField
+}
+
+func newIndexScan(tbl impls.Table) nodes.Node {
+	index := tbl.Indexes()[0].(impls.Index[indexes.BtreeIndexScanOptions])
+	return nodes.NewAccess(access.NewIndexAccessStrategy(tbl, index, indexes.BtreeIndexScanOptions{}))
+}
+
+func mergeJoin(t *testing.T, left, right nodes.Node, leftKey, rightKey fields.Field, filter impls.Expression) []string {
+	strategy := NewMergeJoinStrategy(left, right, JoinTypeInner, []EqualityPair{{
+		Left:  expressions.NewNamed(leftKey),
+		Right: expressions.NewNamed(rightKey),
+	}}, filter, joinFields(leftKey, rightKey))
+
+	return joinedKeys(t, strategy)
+}
+
+// joinFields returns the fields of the join of two relations, each having an internal
+// TID field followed by the given key.
+func joinFields(leftKey, rightKey fields.Field) []fields.Field {
+	var joinFields []fields.Field
+	for _, key := range []fields.Field{leftKey, rightKey} {
+		joinFields = append(joinFields, fields.NewField(key.RelationName(), "tid", types.TypeBigInteger, fields.InternalFieldTid), key)
+	}
+
+	return joinFields
+}
+
+// joinedKeys returns the pair of keys of each row emitted by the given strategy.
+func joinedKeys(t *testing.T, strategy nodes.JoinStrategy) []string {
+	scanner, err := strategy.Scanner(impls.EmptyExecutionContext)
+	require.NoError(t, err)
+
+	var pairs []string
+	require.NoError(t, scan.VisitRows(scanner, func(row rows.Row) (bool, error) {
+		pairs = append(pairs, fmt.Sprintf("%v=%v", row.Values[1], row.Values[3]))
+		return true, nil
+	}))
+
+	return pairs
+}
@@ -13,17 +13,20 @@ import (
 )
 
 type nestedLoopJoinStrategy struct {
-	left   nodes.Node
-	right  nodes.Node
-	filter impls.Expression
-	fields []fields.Field
+	left     nodes.Node
+	right    nodes.Node
+	joinType JoinType
+	filter   impls.Expression
+	fields   []fields.Field
 }
 
-func NewNestedLoopJoinStrategy(left nodes.Node, right nodes.Node, filter impls.Expression, fields []fields.Field) nodes.JoinStrategy {
+func NewNestedLoopJoinStrategy(left nodes.Node, right nodes.Node, joinType JoinType, filter impls.Expression, fields []fields.Field) nodes.JoinStrategy {
 	return &nestedLoopJoinStrategy{
-		left:   left,
-		right:  right,
-		fields: fields,
+		left:     left,
+		right:    right,
+		joinType: joinType,
+		filter:   filter,
+		fields:   fields,
 	}
 }
 
@@ -39,8 +42,13 @@ func (s *nestedLoopJoinStrategy) Scanner(ctx impls.ExecutionContext) (scan.RowSc
 		return nil, err
 	}
 
+	if s.joinType == JoinTypeFullOuter {
+		return s.fullScanner(ctx, leftScanner)
+	}
+
 	var (
 		leftRow      *rows.Row
+		leftMatched  bool
 		rightScanner scan.RowScanner
 	)
 
@@ -54,6 +62,7 @@ func (s *nestedLoopJoinStrategy) Scanner(ctx impls.ExecutionContext) (scan.RowSc
 					return rows.Row{}, err
 				}
 				leftRow = &row
+				leftMatched = false
 
 				scanner, err := s.right.Scanner(ctx.AddOuterRow(row))
 				if err != nil {
@@ -65,8 +74,19 @@ func (s *nestedLoopJoinStrategy) Scanner(ctx impls.ExecutionContext) (scan.RowSc
 			rightRow, err := rightScanner.Scan()
 			if err != nil {
 				if err == scan.ErrNoRows {
+					unmatchedRow := *leftRow
 					leftRow = nil
 					rightScanner = nil
+
+					if !leftMatched {
+						switch s.joinType {
+						case JoinTypeLeftOuter:
+							return extendLeftRow(s.fields, unmatchedRow)
+						case JoinTypeAnti:
+							return unmatchedRow, nil
+						}
+					}
+
 					continue
 				}
 
@@ -78,15 +98,136 @@ func (s *nestedLoopJoinStrategy) Scanner(ctx impls.ExecutionContext) (scan.RowSc
 				return rows.Row{}, err
 			}
 
-			if s.filter != nil {
-				if ok, err := types.ValueAs[bool](queries.Evaluate(ctx, s.filter, row)); err != nil {
+			if ok, err := evaluateFilter(ctx, s.filter, row); err != nil {
+				return rows.Row{}, err
+			} else if !ok {
+				continue
+			}
+
+			switch s.joinType {
+			case JoinTypeSemi:
+				// Each row of the left relation is emitted once, on its first match
+				matchedRow := *leftRow
+				leftRow = nil
+				rightScanner = nil
+				return matchedRow, nil
+
+			case JoinTypeAnti:
+				// Rows of the left relation with a match are discarded
+				leftRow = nil
+				rightScanner = nil
+				continue
+			}
+
+			leftMatched = true
+			return row, nil
+		}
+	}), nil
+}
+
+// fullScanner returns a scanner for a full outer join. The right relation is read once
+// (it cannot reference the rows of the left relation) and is compared with each row of
+// the left relation. Rows of either relation without a match are extended with NULL
+// values; those of the right relation are emitted after the left relation is exhausted.
+func (s *nestedLoopJoinStrategy) fullScanner(ctx impls.ExecutionContext, leftScanner scan.RowScanner) (scan.RowScanner, error) {
+	rightScanner, err := s.right.Scanner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rightRows []rows.Row
+	if err := scan.VisitRows(rightScanner, func(row rows.Row) (bool, error) {
+		rightRows = append(rightRows, row)
+		return true, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var (
+		leftRow       *rows.Row
+		leftMatched   bool
+		leftExhausted bool
+		rightMatched  = make([]bool, len(rightRows))
+		next          int
+	)
+
+	return scan.RowScannerFunc(func() (rows.Row, error) {
+		ctx.Log("Scanning Nested Loop Join Strategy")
+
+		for {
+			if leftExhausted {
+				for next < len(rightRows) {
+					i := next
+					next++
+
+					if !rightMatched[i] {
+						return extendRightRow(s.fields, rightRows[i])
+					}
+				}
+
+				return rows.Row{}, scan.ErrNoRows
+			}
+
+			if leftRow == nil {
+				row, err := leftScanner.Scan()
+				if err != nil {
+					if err == scan.ErrNoRows {
+						leftExhausted = true
+						next = 0
+						continue
+					}
+
 					return rows.Row{}, err
-				} else if ok == nil || !*ok {
-					continue
 				}
+
+				leftRow = &row
+				leftMatched = false
+				next = 0
 			}
 
+			if next == len(rightRows) {
+				unmatchedRow := *leftRow
+				leftRow = nil
+
+				if !leftMatched {
+					return extendLeftRow(s.fields, unmatchedRow)
+				}
+
+				continue
+			}
+
+			i := next
+			next++
+
+			row, err := rows.NewRow(s.fields, append(slices.Clone(leftRow.Values), rightRows[i].Values...))
+			if err != nil {
+				return rows.Row{}, err
+			}
+
+			if ok, err := evaluateFilter(ctx, s.filter, row); err != nil {
+				return rows.Row{}, err
+			} else if !ok {
+				continue
+			}
+
+			leftMatched = true
+			rightMatched[i] = true
 			return row, nil
 		}
 	}), nil
 }
+
+// evaluateFilter returns true if the given filter, which may be nil, is true for the
+// given joined row.
+func evaluateFilter(ctx impls.ExecutionContext, filter impls.Expression, row rows.Row) (bool, error) {
+	if filter == nil {
+		return true, nil
+	}
+
+	ok, err := types.ValueAs[bool](queries.Evaluate(ctx, filter, row))
+	if err != nil {
+		return false, err
+	}
+
+	return ok != nil && *ok, nil
+}
@@ -0,0 +1,82 @@
+package join
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOuterJoinStrategies(t *testing.T) {
+	left, leftKey := newIndexedTable(t, "l", 3, nil, 2, 1, 2)
+	right, rightKey := newIndexedTable(t, "r", 4, 2, nil, 2)
+
+	keys := expressions.NewEquals(expressions.NewNamed(leftKey), expressions.NewNamed(rightKey))
+	rightKeyAboveTwo := expressions.NewGreaterThan(expressions.NewNamed(rightKey), expressions.NewConstant(int32(2)))
+
+	for _, testCase := range []struct {
+		name      string
+		joinType  JoinType
+		filter    impls.Expression
+		skipMerge bool
+		expected  []string
+	}{
+		{
+			name:     "inner",
+			joinType: JoinTypeInner,
+			expected: []string{"2=2", "2=2", "2=2", "2=2"},
+		},
+		{
+			name:     "left",
+			joinType: JoinTypeLeftOuter,
+			expected: []string{"1=<nil>", "2=2", "2=2", "2=2", "2=2", "3=<nil>", "<nil>=<nil>"},
+		},
+		{
+			name:     "full",
+			joinType: JoinTypeFullOuter,
+			expected: []string{"1=<nil>", "2=2", "2=2", "2=2", "2=2", "3=<nil>", "<nil>=4", "<nil>=<nil>", "<nil>=<nil>"},
+		},
+		{
+			// Left rows whose matches are all rejected by the residual filter are extended
+			name:     "left with residual filter",
+			joinType: JoinTypeLeftOuter,
+			filter:   rightKeyAboveTwo,
+			expected: []string{"1=<nil>", "2=<nil>", "2=<nil>", "3=<nil>", "<nil>=<nil>"},
+		},
+		{
+			name:      "full with residual filter",
+			joinType:  JoinTypeFullOuter,
+			filter:    rightKeyAboveTwo,
+			skipMerge: true,
+			expected:  []string{"1=<nil>", "2=<nil>", "2=<nil>", "3=<nil>", "<nil>=2", "<nil>=2", "<nil>=4", "<nil>=<nil>", "<nil>=<nil>"},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			pairs := []EqualityPair{{Left: expressions.NewNamed(leftKey), Right: expressions.NewNamed(rightKey)}}
+			joinFields := joinFields(leftKey, rightKey)
+
+			strategies := map[string]func(left, right nodes.Node) nodes.JoinStrategy{
+				"nested loop": func(left, right nodes.Node) nodes.JoinStrategy {
+					return NewNestedLoopJoinStrategy(left, right, testCase.joinType, expressions.UnionFilters(keys, testCase.filter), joinFields)
+				},
+				"hash": func(left, right nodes.Node) nodes.JoinStrategy {
+					return NewHashJoinStrategy(left, right, testCase.joinType, pairs, testCase.filter, joinFields)
+				},
+			}
+			if !testCase.skipMerge {
+				strategies["merge"] = func(left, right nodes.Node) nodes.JoinStrategy {
+					return NewMergeJoinStrategy(left, right, testCase.joinType, pairs, testCase.filter, joinFields)
+				}
+			}
+
+			for name, strategy := range strategies {
+				keys := joinedKeys(t, strategy(newIndexScan(left), newIndexScan(right)))
+				slices.Sort(keys)
+				assert.Equal(t, testCase.expected, keys, name)
+			}
+		})
+	}
+}
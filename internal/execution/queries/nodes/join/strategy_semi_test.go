@@ -0,0 +1,100 @@
+package join
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/scan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSemiJoinStrategies(t *testing.T) {
+	left, leftKey := newIndexedTable(t, "l", 3, nil, 2, 1, 2)
+
+	for _, testCase := range []struct {
+		name      string
+		joinType  JoinType
+		rightKeys []any
+		nullAware bool
+		expected  []string
+	}{
+		{
+			name:      "semi",
+			joinType:  JoinTypeSemi,
+			rightKeys: []any{4, 2, nil, 2},
+			expected:  []string{"2", "2"},
+		},
+		{
+			name:      "anti",
+			joinType:  JoinTypeAnti,
+			rightKeys: []any{4, 2, nil, 2},
+			expected:  []string{"1", "3", "<nil>"},
+		},
+		{
+			// A NULL key on the right may equal any key on the left
+			name:      "null-aware anti",
+			joinType:  JoinTypeAnti,
+			rightKeys: []any{4, 2, nil, 2},
+			nullAware: true,
+			expected:  nil,
+		},
+		{
+			// A NULL key on the left may equal any key on the right
+			name:      "null-aware anti without null keys",
+			joinType:  JoinTypeAnti,
+			rightKeys: []any{4, 2, 2},
+			nullAware: true,
+			expected:  []string{"1", "3"},
+		},
+		{
+			name:      "null-aware anti with empty relation",
+			joinType:  JoinTypeAnti,
+			rightKeys: nil,
+			nullAware: true,
+			expected:  []string{"1", "2", "2", "3", "<nil>"},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			right, rightKey := newIndexedTable(t, "r", testCase.rightKeys...)
+			pairs := []EqualityPair{{Left: expressions.NewNamed(leftKey), Right: expressions.NewNamed(rightKey), NullAware: testCase.nullAware}}
+			joinFields := joinFields(leftKey, rightKey)
+
+			condition := expressions.NewEquals(pairs[0].Left, pairs[0].Right)
+			if testCase.nullAware {
+				condition = expressions.NewNot(expressions.NewIsFalse(condition))
+			}
+
+			strategies := map[string]nodes.JoinStrategy{
+				"nested loop": NewNestedLoopJoinStrategy(newIndexScan(left), newIndexScan(right), testCase.joinType, condition, joinFields),
+				"hash":        NewHashJoinStrategy(newIndexScan(left), newIndexScan(right), testCase.joinType, pairs, nil, joinFields),
+			}
+
+			for name, strategy := range strategies {
+				keys := leftKeys(t, strategy)
+				slices.Sort(keys)
+				assert.Equal(t, testCase.expected, keys, name)
+			}
+		})
+	}
+}
+
+// leftKeys returns the key of each left row emitted by the given strategy.
+func leftKeys(t *testing.T, strategy nodes.JoinStrategy) []string {
+	scanner, err := strategy.Scanner(impls.EmptyExecutionContext)
+	require.NoError(t, err)
+
+	var keys []string
+	require.NoError(t, scan.VisitRows(scanner, func(row rows.Row) (bool, error) {
+		require.Len(t, row.Values, 2)
+		keys = append(keys, fmt.Sprintf("%v", row.Values[1]))
+		return true, nil
+	}))
+
+	return keys
+}
@@ -9,6 +9,8 @@ const (
 	JoinTypeRightOuter
 	JoinTypeFullOuter
 	JoinTypeCross
+	JoinTypeSemi
+	JoinTypeAnti
 )
 
 func (t JoinType) String() string {
@@ -23,6 +25,10 @@ func (t JoinType) String() string {
 		return "FULL OUTER JOIN"
 	case JoinTypeCross:
 		return "CROSS JOIN"
+	case JoinTypeSemi:
+		return "SEMI JOIN"
+	case JoinTypeAnti:
+		return "ANTI JOIN"
 	}
 
 	return "UNKNOWN JOIN"
@@ -0,0 +1,73 @@
+package nodes
+
+import (
+	"strings"
+
+	"github.com/efritz/gostgres/internal/execution/serialization"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/scan"
+)
+
+// LockedRelation is a table whose rows are locked by a SELECT ... FOR UPDATE/SHARE
+// query, along with the name by which the table is referenced in the query.
+type LockedRelation struct {
+	Name  string
+	Table impls.Table
+}
+
+type lockRowsNode struct {
+	Node
+	relations []LockedRelation
+	mode      impls.LockMode
+}
+
+func NewLockRows(node Node, relations []LockedRelation, mode impls.LockMode) Node {
+	return &lockRowsNode{
+		Node:      node,
+		relations: relations,
+		mode:      mode,
+	}
+}
+
+func (n *lockRowsNode) Serialize(w serialization.IndentWriter) {
+	w.WritefLine("lock rows %s", strings.ToLower(n.mode.String()))
+	n.Node.Serialize(w.Indent())
+}
+
+func (n *lockRowsNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
+	ctx.Log("Building Lock Rows scanner")
+
+	for _, relation := range n.relations {
+		if err := ctx.LockCatalogTable(relation.Table, impls.RowShareLock); err != nil {
+			return nil, err
+		}
+	}
+
+	scanner, err := n.Node.Scanner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return scan.RowScannerFunc(func() (rows.Row, error) {
+		ctx.Log("Scanning Lock Rows")
+
+		row, err := scanner.Scan()
+		if err != nil {
+			return rows.Row{}, err
+		}
+
+		for _, relation := range n.relations {
+			tidRow, err := row.IsolateTID(relation.Name)
+			if err != nil {
+				return rows.Row{}, err
+			}
+
+			if err := relation.Table.LockRow(ctx, tidRow, n.mode); err != nil {
+				return rows.Row{}, err
+			}
+		}
+
+		return row, nil
+	}), nil
+}
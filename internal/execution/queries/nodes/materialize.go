@@ -0,0 +1,93 @@
+package nodes
+
+import (
+	"github.com/efritz/gostgres/internal/execution/serialization"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/scan"
+)
+
+// MaterializedRelation is a relation whose rows are computed at most once for each
+// execution of a statement and shared by each of its scans.
+type MaterializedRelation struct {
+	name string
+	node Node
+}
+
+func NewMaterializedRelation(name string, node Node) *MaterializedRelation {
+	return &MaterializedRelation{
+		name: name,
+		node: node,
+	}
+}
+
+// rows returns the rows of the relation, computing them on first use.
+func (r *MaterializedRelation) rows(ctx impls.ExecutionContext) ([]rows.Row, error) {
+	value, err := ctx.Memoize(r, func() (any, error) {
+		scanner, err := r.node.Scanner(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var materialized []rows.Row
+		if err := scan.VisitRows(scanner, func(row rows.Row) (bool, error) {
+			materialized = append(materialized, row)
+			return true, nil
+		}); err != nil {
+			return nil, err
+		}
+
+		return materialized, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return value.([]rows.Row), nil
+}
+
+//
+//
+
+type materializedScanNode struct {
+	relation *MaterializedRelation
+}
+
+func NewMaterializedScan(relation *MaterializedRelation) Node {
+	return &materializedScanNode{
+		relation: relation,
+	}
+}
+
+func (n *materializedScanNode) Serialize(w serialization.IndentWriter) {
+	w.WritefLine("materialized scan of %s", n.relation.name)
+	n.relation.node.Serialize(w.Indent())
+}
+
+func (n *materializedScanNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
+	ctx.Log("Building Materialized Scan scanner")
+
+	materialized, err := n.relation.rows(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSliceScanner(ctx, "Materialized Scan", materialized), nil
+}
+
+// newSliceScanner returns a scanner over the given rows.
+func newSliceScanner(ctx impls.ExecutionContext, name string, rs []rows.Row) scan.RowScanner {
+	i := 0
+
+	return scan.RowScannerFunc(func() (rows.Row, error) {
+		ctx.Log("Scanning %s", name)
+
+		if i >= len(rs) {
+			return rows.Row{}, scan.ErrNoRows
+		}
+
+		row := rs[i]
+		i++
+		return row, nil
+	})
+}
@@ -30,6 +30,10 @@ func (n *deleteNode) Serialize(w serialization.IndentWriter) {
 func (n *deleteNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
 	ctx.Log("Building Delete scanner")
 
+	if err := ctx.LockCatalogTable(n.table, impls.RowExclusiveLock); err != nil {
+		return nil, err
+	}
+
 	scanner, err := n.Node.Scanner(ctx)
 	if err != nil {
 		return nil, err
@@ -53,7 +57,7 @@ func (n *deleteNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error
 			return rows.Row{}, err
 		}
 
-		deletedRow, ok, err := n.table.Delete(tidRow)
+		deletedRow, ok, err := n.table.Delete(ctx, tidRow)
 		if err != nil {
 			return rows.Row{}, err
 		}
@@ -33,6 +33,10 @@ func (n *insertNode) Serialize(w serialization.IndentWriter) {
 func (n *insertNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
 	ctx.Log("Building Insert scanner")
 
+	if err := ctx.LockCatalogTable(n.table, impls.RowExclusiveLock); err != nil {
+		return nil, err
+	}
+
 	scanner, err := n.Node.Scanner(ctx)
 	if err != nil {
 		return nil, err
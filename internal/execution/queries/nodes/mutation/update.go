@@ -2,6 +2,7 @@ package mutation
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/efritz/gostgres/internal/execution/queries"
 	"github.com/efritz/gostgres/internal/execution/queries/nodes"
@@ -45,6 +46,10 @@ func (n *updateNode) Serialize(w serialization.IndentWriter) {
 func (n *updateNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
 	ctx.Log("Building Update scanner")
 
+	if err := ctx.LockCatalogTable(n.table, impls.RowExclusiveLock); err != nil {
+		return nil, err
+	}
+
 	scanner, err := n.Node.Scanner(ctx)
 	if err != nil {
 		return nil, err
@@ -93,13 +98,15 @@ func (n *updateNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error
 			return rows.Row{}, err
 		}
 
-		baseRow, ok, err := n.table.Delete(tidRow)
+		baseRow, ok, err := n.table.Delete(ctx, tidRow)
 		if err != nil {
 			return rows.Row{}, err
 		} else if !ok {
 			return rows.Row{}, nil
 		}
 
+		// The deleted row is retained to be restored on rollback; don't modify it in place
+		baseRow.Values = slices.Clone(baseRow.Values)
 		for i, value := range updates {
 			baseRow.Values[i] = value
 		}
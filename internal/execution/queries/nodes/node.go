@@ -29,7 +29,7 @@ func NewQuery(n Node) queries.Query {
 }
 
 func (q *NodeQuery) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
-	scanner, err := q.Scanner(ctx)
+	scanner, err := q.Scanner(ctx.WithMemo())
 	if err != nil {
 		w.Error(err)
 		return
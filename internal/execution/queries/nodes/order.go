@@ -40,10 +40,9 @@ func (n *orderNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error)
 		return nil, err
 	}
 
-	// TODO - commented out to support mark-restore
-	// if n.order == nil {
-	// 	return scanner, nil
-	// }
+	if n.order == nil {
+		return scanner, nil
+	}
 
 	return newOrderScanner(ctx, scanner, n.fields, n.order)
 }
@@ -107,6 +106,22 @@ func (s *orderScanner) Restore() {
 }
 
 func findIndexIterationOrder(ctx impls.ExecutionContext, order impls.OrderExpression, rows rows.Rows) ([]int, error) {
+	indexValues, err := sortIndexValues(ctx, order, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]int, 0, len(indexValues))
+	for _, value := range indexValues {
+		indexes = append(indexes, value.index)
+	}
+
+	return indexes, nil
+}
+
+// sortIndexValues returns the index of each of the given rows along with the values of
+// the given order expressions for that row, sorted in order.
+func sortIndexValues(ctx impls.ExecutionContext, order impls.OrderExpression, rows rows.Rows) ([]indexValue, error) {
 	expressions := order.Expressions()
 
 	indexValues, err := makeIndexValues(ctx, expressions, rows)
@@ -136,12 +151,7 @@ func findIndexIterationOrder(ctx impls.ExecutionContext, order impls.OrderExpres
 		return nil, fmt.Errorf("incomparable types")
 	}
 
-	indexes := make([]int, 0, len(indexValues))
-	for _, value := range indexValues {
-		indexes = append(indexes, value.index)
-	}
-
-	return indexes, nil
+	return indexValues, nil
 }
 
 type indexValue struct {
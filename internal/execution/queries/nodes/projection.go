@@ -36,7 +36,7 @@ func (n *projectionNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, e
 
 	aliases := n.projection.Aliases()
 
-	return scan.RowScannerFunc(func() (rows.Row, error) {
+	return scan.WithMarkRestore(scan.RowScannerFunc(func() (rows.Row, error) {
 		ctx.Log("Scanning Projection")
 
 		row, err := scanner.Scan()
@@ -55,5 +55,5 @@ func (n *projectionNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, e
 		}
 
 		return rows.NewRow(n.projection.Fields(), values)
-	}), nil
+	}), scanner), nil
 }
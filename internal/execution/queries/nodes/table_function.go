@@ -0,0 +1,61 @@
+package nodes
+
+import (
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/execution/serialization"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/scan"
+)
+
+type tableFunctionNode struct {
+	name     string
+	fields   []fields.Field
+	function impls.TableFunction
+	args     []impls.Expression
+}
+
+func NewTableFunction(name string, fields []fields.Field, function impls.TableFunction, args []impls.Expression) Node {
+	return &tableFunctionNode{
+		name:     name,
+		fields:   fields,
+		function: function,
+		args:     args,
+	}
+}
+
+func (n *tableFunctionNode) Serialize(w serialization.IndentWriter) {
+	w.WritefLine("function scan of %s", n.name)
+}
+
+func (n *tableFunctionNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
+	ctx.Log("Building Function Scan scanner")
+
+	args := make([]any, 0, len(n.args))
+	for _, expr := range n.args {
+		value, err := queries.Evaluate(ctx, expr, rows.Row{})
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, value)
+	}
+
+	values, err := n.function.Invoke(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	rs := make([]rows.Row, 0, len(values))
+	for _, rowValues := range values {
+		row, err := rows.NewRow(n.fields, rowValues)
+		if err != nil {
+			return nil, err
+		}
+
+		rs = append(rs, row)
+	}
+
+	return newSliceScanner(ctx, "Function Scan", rs), nil
+}
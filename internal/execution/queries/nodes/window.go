@@ -0,0 +1,299 @@
+package nodes
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/execution/serialization"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/ordering"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/scan"
+)
+
+// WindowFunction is a window function invocation whose value is appended to each row
+// emitted by a window node.
+type WindowFunction struct {
+	Field      fields.Field
+	Expression impls.Expression
+}
+
+type windowNode struct {
+	Node
+	fields    []fields.Field
+	functions []WindowFunction
+}
+
+// NewWindow creates a node that emits the rows of the given node (having the given
+// fields) in their original order, each extended with the values of the given window
+// functions.
+func NewWindow(node Node, fields []fields.Field, functions []WindowFunction) Node {
+	return &windowNode{
+		Node:      node,
+		fields:    fields,
+		functions: functions,
+	}
+}
+
+func (n *windowNode) Serialize(w serialization.IndentWriter) {
+	strFunctions := make([]string, 0, len(n.functions))
+	for _, function := range n.functions {
+		strFunctions = append(strFunctions, function.Expression.String())
+	}
+
+	w.WritefLine("window %s", strings.Join(strFunctions, ", "))
+	n.Node.Serialize(w.Indent())
+}
+
+func (n *windowNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
+	ctx.Log("Building Window scanner")
+
+	scanner, err := n.Node.Scanner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := rows.NewRows(n.fields)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err = scan.ScanIntoRows(scanner, input)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([][]any, input.Size())
+	for i := range values {
+		values[i] = make([]any, len(n.functions))
+	}
+
+	// Functions computed over the same window share a single sort of the input
+	var windows []impls.Window
+	var windowFunctions [][]int
+	for i, function := range n.functions {
+		_, _, window, _ := expressions.UnwrapWindowFunction(function.Expression)
+
+		j := slices.IndexFunc(windows, func(w impls.Window) bool { return expressions.WindowsEqual(w, window) })
+		if j < 0 {
+			j = len(windows)
+			windows = append(windows, window)
+			windowFunctions = append(windowFunctions, nil)
+		}
+
+		windowFunctions[j] = append(windowFunctions[j], i)
+	}
+
+	for i, window := range windows {
+		if err := n.evaluateWindow(ctx, input, window, windowFunctions[i], values); err != nil {
+			return nil, err
+		}
+	}
+
+	outputFields := slices.Clone(input.Fields)
+	for _, function := range n.functions {
+		outputFields = append(outputFields, function.Field)
+	}
+
+	return &windowScanner{
+		ctx:    ctx,
+		fields: outputFields,
+		input:  input,
+		values: values,
+		mark:   -1,
+	}, nil
+}
+
+// evaluateWindow computes the values of the functions with the given indexes, which are
+// all computed over the given window, for each of the given input rows.
+func (n *windowNode) evaluateWindow(ctx impls.ExecutionContext, input rows.Rows, window impls.Window, functionIndexes []int, values [][]any) error {
+	var orderExpressions []impls.ExpressionWithDirection
+	for _, partition := range window.Partitions {
+		orderExpressions = append(orderExpressions, impls.ExpressionWithDirection{Expression: partition})
+	}
+	if window.Order != nil {
+		orderExpressions = append(orderExpressions, window.Order.Expressions()...)
+	}
+
+	sorted, err := sortIndexValues(ctx, expressions.NewOrderExpression(orderExpressions), input)
+	if err != nil {
+		return err
+	}
+
+	frame, err := newWindowFrame(ctx, window)
+	if err != nil {
+		return err
+	}
+
+	numPartitions := len(window.Partitions)
+	for start := 0; start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && valuesEqual(sorted[start].values[:numPartitions], sorted[end].values[:numPartitions]) {
+			end++
+		}
+
+		partition := newWindowPartition(sorted[start:end], numPartitions, frame)
+
+		for _, i := range functionIndexes {
+			partitionValues, err := n.evaluatePartition(ctx, input, partition, n.functions[i].Expression)
+			if err != nil {
+				return err
+			}
+
+			for j, value := range partitionValues {
+				values[partition.rows[j].index][i] = value
+			}
+		}
+
+		start = end
+	}
+
+	return nil
+}
+
+// evaluatePartition computes the value of the given window function for each row of
+// the given partition.
+func (n *windowNode) evaluatePartition(ctx impls.ExecutionContext, input rows.Rows, partition *windowPartition, expr impls.Expression) ([]any, error) {
+	name, args, _, _ := expressions.UnwrapWindowFunction(expr)
+
+	argValues := make([][]any, 0, len(partition.rows))
+	for _, row := range partition.rows {
+		values, err := queries.EvaluateExpressions(ctx, args, input.Row(row.index))
+		if err != nil {
+			return nil, err
+		}
+
+		argValues = append(argValues, values)
+	}
+
+	values := make([]any, 0, len(partition.rows))
+
+	if f, ok := ctx.Catalog().WindowFunctions.Get(name); ok {
+		for i := range partition.rows {
+			row, err := partition.windowRow(ctx, i)
+			if err != nil {
+				return nil, err
+			}
+			row.Args = argValues
+
+			value, err := f.Invoke(ctx, row)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, value)
+		}
+
+		return values, nil
+	}
+
+	if aggregate, ok := ctx.Catalog().Aggregates.Get(name); ok {
+		// The aggregate state is carried forward from the previous row as long as the
+		// frame of the current row extends the frame of the previous row (as with the
+		// default frame). Otherwise, the frame is re-aggregated from scratch. Frames with
+		// excluded rows are always re-aggregated.
+		var state any
+		frameStart, frameEnd := 0, 0
+
+		for i := range partition.rows {
+			row, err := partition.windowRow(ctx, i)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(row.Excluded) > 0 {
+				state = nil
+				for _, j := range row.Frame() {
+					if state, err = aggregate.Step(ctx, state, argValues[j]); err != nil {
+						return nil, err
+					}
+				}
+
+				value, err := aggregate.Done(ctx, state)
+				if err != nil {
+					return nil, err
+				}
+
+				values = append(values, value)
+				continue
+			}
+
+			if row.FrameStart != frameStart || row.FrameEnd < frameEnd {
+				state = nil
+				frameStart, frameEnd = row.FrameStart, row.FrameStart
+			}
+
+			for ; frameEnd < row.FrameEnd; frameEnd++ {
+				if state, err = aggregate.Step(ctx, state, argValues[frameEnd]); err != nil {
+					return nil, err
+				}
+			}
+
+			value, err := aggregate.Done(ctx, state)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, value)
+		}
+
+		return values, nil
+	}
+
+	return nil, fmt.Errorf("unknown window function %q", name)
+}
+
+// valuesEqual returns true if the given slices hold pairwise equal values. Unlike
+// comparisons in expressions, NULL values are considered equal to one another.
+func valuesEqual(a, b []any) bool {
+	for i, value := range a {
+		switch ordering.CompareValues(value, b[i]) {
+		case ordering.OrderTypeEqual, ordering.OrderTypeNulls:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+//
+
+type windowScanner struct {
+	ctx    impls.ExecutionContext
+	fields []fields.Field
+	input  rows.Rows
+	values [][]any
+	next   int
+	mark   int
+}
+
+func (s *windowScanner) Scan() (rows.Row, error) {
+	s.ctx.Log("Scanning Window")
+
+	if s.next >= s.input.Size() {
+		return rows.Row{}, scan.ErrNoRows
+	}
+
+	values := append(slices.Clone(s.input.Values[s.next]), s.values[s.next]...)
+	s.next++
+
+	return rows.NewRow(s.fields, values)
+}
+
+func (s *windowScanner) Mark() {
+	s.mark = s.next - 1
+}
+
+func (s *windowScanner) Restore() {
+	if s.mark == -1 {
+		panic("no mark to restore")
+	}
+
+	s.next = s.mark
+}
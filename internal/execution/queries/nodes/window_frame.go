@@ -0,0 +1,300 @@
+package nodes
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/ordering"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+// windowFrame is a window frame with evaluated offsets.
+type windowFrame struct {
+	mode        impls.WindowFrameMode
+	start       impls.WindowFrameBoundType
+	end         impls.WindowFrameBoundType
+	startOffset any
+	endOffset   any
+	exclusion   impls.WindowFrameExclusion
+	reverse     bool // true if the (single) order expression of a RANGE frame is descending
+}
+
+// newWindowFrame returns the frame of the given window. Windows without an explicit
+// frame include all rows from the start of the partition through the peers of the
+// current row.
+func newWindowFrame(ctx impls.ExecutionContext, window impls.Window) (*windowFrame, error) {
+	if window.Frame == nil {
+		return &windowFrame{
+			mode:  impls.WindowFrameModeRange,
+			start: impls.WindowFrameBoundUnboundedPreceding,
+			end:   impls.WindowFrameBoundCurrentRow,
+		}, nil
+	}
+
+	startOffset, err := evaluateFrameOffset(ctx, window.Frame.Mode, window.Frame.Start, "starting")
+	if err != nil {
+		return nil, err
+	}
+
+	endOffset, err := evaluateFrameOffset(ctx, window.Frame.Mode, window.Frame.End, "ending")
+	if err != nil {
+		return nil, err
+	}
+
+	reverse := false
+	if window.Order != nil && len(window.Order.Expressions()) > 0 {
+		reverse = window.Order.Expressions()[0].Reverse
+	}
+
+	return &windowFrame{
+		mode:        window.Frame.Mode,
+		start:       window.Frame.Start.Type,
+		end:         window.Frame.End.Type,
+		startOffset: startOffset,
+		endOffset:   endOffset,
+		exclusion:   window.Frame.Exclusion,
+		reverse:     reverse,
+	}, nil
+}
+
+func evaluateFrameOffset(ctx impls.ExecutionContext, mode impls.WindowFrameMode, bound impls.WindowFrameBound, which string) (any, error) {
+	if bound.Offset == nil {
+		return nil, nil
+	}
+
+	value, err := queries.Evaluate(ctx, bound.Offset, rows.Row{})
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, fmt.Errorf("frame %s offset must not be null", which)
+	}
+
+	if mode == impls.WindowFrameModeRange {
+		if !types.IsNumeric(value) {
+			return nil, fmt.Errorf("RANGE offsets are supported only for numeric ordering columns")
+		}
+		if ordering.CompareValues(value, int64(0)) == ordering.OrderTypeBefore {
+			return nil, fmt.Errorf("invalid preceding or following size in window function")
+		}
+
+		return value, nil
+	}
+
+	_, offset, ok := types.TypeBigInteger.Refine(value)
+	if !ok {
+		return nil, fmt.Errorf("frame %s offset must be an integer", which)
+	}
+	if offset.(int64) < 0 {
+		return nil, fmt.Errorf("frame %s offset must not be negative", which)
+	}
+
+	return offset, nil
+}
+
+//
+//
+
+// windowPartition is a sorted set of rows with equal partition keys.
+type windowPartition struct {
+	rows   []indexValue
+	frame  *windowFrame
+	keyIdx int // index of the first order value in each row's values
+
+	groups    [][2]int // half-open range of each peer group
+	rowGroups []int    // peer group of each row
+}
+
+func newWindowPartition(sorted []indexValue, numPartitions int, frame *windowFrame) *windowPartition {
+	p := &windowPartition{
+		rows:      sorted,
+		frame:     frame,
+		keyIdx:    numPartitions,
+		rowGroups: make([]int, len(sorted)),
+	}
+
+	for i := range sorted {
+		if i == 0 || !valuesEqual(sorted[i-1].values[numPartitions:], sorted[i].values[numPartitions:]) {
+			p.groups = append(p.groups, [2]int{i, i})
+		}
+
+		p.groups[len(p.groups)-1][1] = i + 1
+		p.rowGroups[i] = len(p.groups) - 1
+	}
+
+	return p
+}
+
+// windowRow returns the position of the given row within the partition and its frame.
+// The argument values of the returned row are unset.
+func (p *windowPartition) windowRow(ctx impls.ExecutionContext, i int) (impls.WindowRow, error) {
+	group := p.rowGroups[i]
+	peers := p.groups[group]
+
+	frameStart, err := p.bound(ctx, i, p.frame.start, p.frame.startOffset, true)
+	if err != nil {
+		return impls.WindowRow{}, err
+	}
+
+	frameEnd, err := p.bound(ctx, i, p.frame.end, p.frame.endOffset, false)
+	if err != nil {
+		return impls.WindowRow{}, err
+	}
+
+	n := len(p.rows)
+	frameStart = max(0, min(frameStart, n))
+	frameEnd = max(frameStart, min(frameEnd, n))
+
+	var excluded [][2]int
+	switch p.frame.exclusion {
+	case impls.WindowFrameExcludeCurrentRow:
+		excluded = [][2]int{{i, i + 1}}
+	case impls.WindowFrameExcludeGroup:
+		excluded = [][2]int{peers}
+	case impls.WindowFrameExcludeTies:
+		excluded = [][2]int{{peers[0], i}, {i + 1, peers[1]}}
+	}
+
+	return impls.WindowRow{
+		Index:      i,
+		PeerGroup:  group,
+		PeersStart: peers[0],
+		PeersEnd:   peers[1],
+		FrameStart: frameStart,
+		FrameEnd:   frameEnd,
+		Excluded:   excluded,
+	}, nil
+}
+
+// bound returns the index of the first row in the frame of the given row (when start is
+// true), or the index following the last row in the frame (when start is false). The
+// returned index may lie outside of the partition.
+func (p *windowPartition) bound(ctx impls.ExecutionContext, i int, typ impls.WindowFrameBoundType, offset any, start bool) (int, error) {
+	n := len(p.rows)
+	group := p.rowGroups[i]
+	peers := p.groups[group]
+
+	switch typ {
+	case impls.WindowFrameBoundUnboundedPreceding:
+		return 0, nil
+
+	case impls.WindowFrameBoundUnboundedFollowing:
+		return n, nil
+
+	case impls.WindowFrameBoundCurrentRow:
+		if p.frame.mode == impls.WindowFrameModeRows {
+			if start {
+				return i, nil
+			}
+
+			return i + 1, nil
+		}
+
+		if start {
+			return peers[0], nil
+		}
+
+		return peers[1], nil
+	}
+
+	preceding := typ == impls.WindowFrameBoundPreceding
+
+	switch p.frame.mode {
+	case impls.WindowFrameModeRows:
+		delta := int(min(offset.(int64), int64(n)))
+		if preceding {
+			delta = -delta
+		}
+
+		if start {
+			return i + delta, nil
+		}
+
+		return i + delta + 1, nil
+
+	case impls.WindowFrameModeGroups:
+		delta := int(min(offset.(int64), int64(len(p.groups))))
+		if preceding {
+			delta = -delta
+		}
+
+		target := group + delta
+		if target < 0 {
+			return 0, nil
+		}
+		if target >= len(p.groups) {
+			return n, nil
+		}
+
+		if start {
+			return p.groups[target][0], nil
+		}
+
+		return p.groups[target][1], nil
+	}
+
+	return p.rangeBound(ctx, i, offset, preceding, start)
+}
+
+// rangeBound returns the bound of the frame of the given row including rows whose order
+// value lies within the given offset of the order value of the current row.
+func (p *windowPartition) rangeBound(ctx impls.ExecutionContext, i int, offset any, preceding, start bool) (int, error) {
+	current := p.rows[i].values[p.keyIdx]
+	if current == nil {
+		// NULL order values are only within range of one another
+		peers := p.groups[p.rowGroups[i]]
+		if start {
+			return peers[0], nil
+		}
+
+		return peers[1], nil
+	}
+
+	// Preceding rows have smaller order values in ascending order
+	factory := expressions.NewAddition
+	if preceding != p.frame.reverse {
+		factory = expressions.NewSubtraction
+	}
+
+	target, err := queries.Evaluate(ctx, factory(expressions.NewConstant(current), expressions.NewConstant(offset)), rows.Row{})
+	if err != nil {
+		return 0, err
+	}
+
+	reverse := p.frame.reverse
+	return sort.Search(len(p.rows), func(j int) bool {
+		value := p.rows[j].values[p.keyIdx]
+		if value == nil {
+			// NULL values sort after non-NULL values in ascending order
+			return !reverse
+		}
+
+		cmp := ordering.CompareValues(value, target)
+		if reverse {
+			cmp = flipOrder(cmp)
+		}
+
+		if start {
+			// first row at or after the target
+			return cmp != ordering.OrderTypeBefore
+		}
+
+		// first row after the target
+		return cmp == ordering.OrderTypeAfter
+	}), nil
+}
+
+func flipOrder(cmp ordering.OrderType) ordering.OrderType {
+	switch cmp {
+	case ordering.OrderTypeBefore:
+		return ordering.OrderTypeAfter
+	case ordering.OrderTypeAfter:
+		return ordering.OrderTypeBefore
+	}
+
+	return cmp
+}
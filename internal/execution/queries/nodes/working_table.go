@@ -0,0 +1,47 @@
+package nodes
+
+import (
+	"github.com/efritz/gostgres/internal/execution/serialization"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/scan"
+)
+
+// WorkingTable holds the rows produced by one iteration of a recursive query, which are
+// read by the recursive term of the query during the next iteration.
+type WorkingTable struct {
+	rows []rows.Row
+}
+
+func NewWorkingTable() *WorkingTable {
+	return &WorkingTable{}
+}
+
+func (t *WorkingTable) SetRows(rows []rows.Row) {
+	t.rows = rows
+}
+
+//
+//
+
+type workingTableScanNode struct {
+	name  string
+	table *WorkingTable
+}
+
+func NewWorkingTableScan(name string, table *WorkingTable) Node {
+	return &workingTableScanNode{
+		name:  name,
+		table: table,
+	}
+}
+
+func (n *workingTableScanNode) Serialize(w serialization.IndentWriter) {
+	w.WritefLine("working table scan of %s", n.name)
+}
+
+func (n *workingTableScanNode) Scanner(ctx impls.ExecutionContext) (scan.RowScanner, error) {
+	ctx.Log("Building Working Table Scan scanner")
+
+	return newSliceScanner(ctx, "Working Table Scan", n.table.rows), nil
+}
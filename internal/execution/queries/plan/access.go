@@ -1,6 +1,8 @@
 package plan
 
 import (
+	"math"
+
 	"github.com/efritz/gostgres/internal/catalog/table/indexes"
 	"github.com/efritz/gostgres/internal/execution/expressions"
 	"github.com/efritz/gostgres/internal/execution/queries/nodes"
@@ -13,7 +15,9 @@ type logicalAccessNode struct {
 	table    impls.Table
 	filter   impls.Expression
 	order    impls.OrderExpression
+	rowGoal  float64
 	strategy nodes.AccessStrategy
+	estimate Estimate
 }
 
 func NewAccess(table impls.Table) LogicalNode {
@@ -45,6 +49,10 @@ func (n *logicalAccessNode) AddOrder(ctx impls.OptimizationContext, order impls.
 	n.order = order
 }
 
+func (n *logicalAccessNode) AddRowGoal(ctx impls.OptimizationContext, rows float64) {
+	n.rowGoal = rows
+}
+
 func (n *logicalAccessNode) Optimize(ctx impls.OptimizationContext) {
 	if n.filter != nil {
 		n.filter = n.filter.Fold()
@@ -54,7 +62,7 @@ func (n *logicalAccessNode) Optimize(ctx impls.OptimizationContext) {
 		n.order = n.order.Fold()
 	}
 
-	n.strategy = selectAccessStrategy(n.table, n.filter, n.order)
+	n.strategy, n.estimate = selectAccessStrategy(n.table, n.filter, n.order, n.rowGoal)
 	n.filter = expressions.FilterDifference(n.filter, n.strategy.Filter())
 	n.order = nil
 }
@@ -72,7 +80,34 @@ func (n *logicalAccessNode) Ordering() impls.OrderExpression {
 }
 
 func (n *logicalAccessNode) SupportsMarkRestore() bool {
-	return false
+	// Table and index scans can return to a marked row, and filters pass marks and
+	// restores through to the scan
+	return true
+}
+
+func (n *logicalAccessNode) Estimate() Estimate {
+	if n.strategy == nil {
+		// Estimate the strategy that optimization would select
+		_, estimate := selectAccessStrategy(n.table, n.filter, n.order, n.rowGoal)
+		return estimate
+	}
+
+	return n.estimate
+}
+
+func (n *logicalAccessNode) EstimateHypothetical(filter impls.Expression, order impls.OrderExpression) (Estimate, bool) {
+	baseFilter := n.filter
+	if n.strategy != nil {
+		baseFilter = n.Filter()
+	}
+
+	strategy, estimate := selectAccessStrategy(n.table, expressions.UnionFilters(baseFilter, filter), order, n.rowGoal)
+	if order != nil && !expressions.SubsumesOrder(order, strategy.Ordering()) {
+		estimate.Cost += EstimateSortCost(estimate.Rows)
+		estimate.StartupCost = estimate.Cost
+	}
+
+	return estimate, true
 }
 
 func (n *logicalAccessNode) Build() nodes.Node {
@@ -88,11 +123,15 @@ func (n *logicalAccessNode) Build() nodes.Node {
 //
 //
 
+// selectAccessStrategy returns the cheapest strategy to read the rows of the given table
+// matching the given filter in the given order. If rowGoal is non-zero, strategies are
+// compared by the cost of reading only that many rows.
 func selectAccessStrategy(
 	table impls.Table,
 	filterExpression impls.Expression,
 	orderExpression impls.OrderExpression,
-) nodes.AccessStrategy {
+	rowGoal float64,
+) (nodes.AccessStrategy, Estimate) {
 	var candidates []nodes.AccessStrategy
 	for _, index := range table.Indexes() {
 		if index, opts, ok := indexes.CanSelectHashIndex(index, filterExpression); ok {
@@ -102,32 +141,60 @@ func selectAccessStrategy(
 		if index, opts, ok := indexes.CanSelectBtreeIndex(index, filterExpression, orderExpression); ok {
 			candidates = append(candidates, access.NewIndexAccessStrategy(table, index, opts))
 		}
+
+		if index, opts, ok := indexes.CanSelectInvertedIndex(index, filterExpression); ok {
+			candidates = append(candidates, access.NewIndexAccessStrategy(table, index, opts))
+		}
 	}
 
-	maxScore := 0
 	bestStrategy := access.NewTableAccessStrategy(table)
+	bestEstimate := estimateTableAccess(table, filterExpression)
+	bestCost := estimateAccessCost(bestStrategy, bestEstimate, orderExpression, rowGoal)
 
 	for _, index := range candidates {
-		indexCost := 0
-		if expressions.SubsumesOrder(orderExpression, index.Ordering()) {
-			indexCost += 100
+		estimate := estimateIndexAccess(table, index, filterExpression)
+
+		if cost := estimateAccessCost(index, estimate, orderExpression, rowGoal); cost < bestCost {
+			bestStrategy = index
+			bestEstimate = estimate
+			bestCost = cost
 		}
+	}
 
-		if filterExpression != nil {
-			oldLen := len(expressions.Conjunctions(filterExpression))
-			newLen := 0
-			if remainingFilter := expressions.FilterDifference(filterExpression, index.Filter()); remainingFilter != nil {
-				newLen = len(expressions.Conjunctions(remainingFilter))
-			}
+	return bestStrategy, bestEstimate
+}
 
-			indexCost += (oldLen - newLen) * 10
-		}
+// estimateAccessCost returns the cost of reading the given number of rows (or all rows
+// if zero) via the given strategy in the given order. Strategies not producing rows in
+// the requested order must read and sort every row.
+func estimateAccessCost(strategy nodes.AccessStrategy, estimate Estimate, orderExpression impls.OrderExpression, rowGoal float64) float64 {
+	if orderExpression != nil && !expressions.SubsumesOrder(orderExpression, strategy.Ordering()) {
+		return estimate.Cost + EstimateSortCost(estimate.Rows)
+	}
 
-		if indexCost > maxScore {
-			bestStrategy = index
-			maxScore = indexCost
-		}
+	if rowGoal == 0 {
+		return estimate.Cost
 	}
 
-	return bestStrategy
+	return estimate.FractionalCost(rowGoal / estimate.Rows)
+}
+
+func estimateTableAccess(table impls.Table, filterExpression impls.Expression) Estimate {
+	size := float64(table.Size())
+
+	return Estimate{
+		Rows: ClampRows(size * estimateSelectivity(table, filterExpression)),
+		Cost: size*SeqRowCost + EstimateFilterCost(size, filterExpression),
+	}
+}
+
+func estimateIndexAccess(table impls.Table, index nodes.AccessStrategy, filterExpression impls.Expression) Estimate {
+	size := float64(table.Size())
+	matched := ClampRows(size * estimateSelectivity(table, index.Filter()))
+	remainingFilter := expressions.FilterDifference(filterExpression, index.Filter())
+
+	return Estimate{
+		Rows: ClampRows(matched * estimateSelectivity(table, remainingFilter)),
+		Cost: math.Log2(size+1)*CPUOperatorCost + matched*RandomRowCost + EstimateFilterCost(matched, remainingFilter),
+	}
 }
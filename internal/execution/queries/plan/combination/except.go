@@ -69,6 +69,18 @@ func (n *logicalExceptNode) Filter() impls.Expression {
 func (n *logicalExceptNode) Ordering() impls.OrderExpression { return nil }
 func (n *logicalExceptNode) SupportsMarkRestore() bool       { return false }
 
+func (n *logicalExceptNode) Estimate() plan.Estimate {
+	left, right := n.left.Estimate(), n.right.Estimate()
+
+	return plan.Estimate{
+		Rows: left.Rows,
+		Cost: left.Cost + right.Cost + (left.Rows+right.Rows)*plan.CPUOperatorCost,
+
+		// Rows of the right relation are hashed before the first row is emitted
+		StartupCost: left.StartupCost + right.Cost + right.Rows*plan.CPUOperatorCost,
+	}
+}
+
 func (n *logicalExceptNode) Build() nodes.Node {
 	return combination.NewExcept(n.left.Build(), n.right.Build(), n.fields, n.distinct)
 }
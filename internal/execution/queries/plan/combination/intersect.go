@@ -69,6 +69,18 @@ func (n *logiclIntersectNode) Filter() impls.Expression {
 func (n *logiclIntersectNode) Ordering() impls.OrderExpression { return nil }
 func (n *logiclIntersectNode) SupportsMarkRestore() bool       { return false }
 
+func (n *logiclIntersectNode) Estimate() plan.Estimate {
+	left, right := n.left.Estimate(), n.right.Estimate()
+
+	return plan.Estimate{
+		Rows: min(left.Rows, right.Rows),
+		Cost: left.Cost + right.Cost + (left.Rows+right.Rows)*plan.CPUOperatorCost,
+
+		// Rows of the right relation are hashed before the first row is emitted
+		StartupCost: left.StartupCost + right.Cost + right.Rows*plan.CPUOperatorCost,
+	}
+}
+
 func (n *logiclIntersectNode) Build() nodes.Node {
 	return combination.NewIntersect(n.left.Build(), n.right.Build(), n.fields, n.distinct)
 }
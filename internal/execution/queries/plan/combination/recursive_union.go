@@ -0,0 +1,76 @@
+package combination
+
+import (
+	"slices"
+
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	"github.com/efritz/gostgres/internal/execution/queries/nodes/combination"
+	"github.com/efritz/gostgres/internal/execution/queries/plan"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type logicalRecursiveUnionNode struct {
+	base      plan.LogicalNode
+	recursive plan.LogicalNode
+	working   *nodes.WorkingTable
+	fields    []fields.Field
+	distinct  bool
+}
+
+// NewRecursiveUnion creates a node emitting the rows of the base node followed by the
+// rows of each iteration of the recursive node, which reads the rows emitted by the
+// previous iteration from the given working table. Rows are emitted with the given
+// fields.
+func NewRecursiveUnion(base, recursive plan.LogicalNode, working *nodes.WorkingTable, fields []fields.Field, distinct bool) plan.LogicalNode {
+	return &logicalRecursiveUnionNode{
+		base:      base,
+		recursive: recursive,
+		working:   working,
+		fields:    fields,
+		distinct:  distinct,
+	}
+}
+
+func (n *logicalRecursiveUnionNode) Name() string {
+	return ""
+}
+
+func (n *logicalRecursiveUnionNode) Fields() []fields.Field {
+	return slices.Clone(n.fields)
+}
+
+func (n *logicalRecursiveUnionNode) AddFilter(ctx impls.OptimizationContext, filter impls.Expression) {
+	// Filtering the rows of an iteration would change the rows read by the next
+}
+
+func (n *logicalRecursiveUnionNode) AddOrder(ctx impls.OptimizationContext, order impls.OrderExpression) {
+	// Rows are emitted in the order they are produced by each iteration
+}
+
+func (n *logicalRecursiveUnionNode) Filter() impls.Expression        { return nil }
+func (n *logicalRecursiveUnionNode) Ordering() impls.OrderExpression { return nil }
+func (n *logicalRecursiveUnionNode) SupportsMarkRestore() bool       { return false }
+
+func (n *logicalRecursiveUnionNode) Optimize(ctx impls.OptimizationContext) {
+	n.base.Optimize(ctx)
+	n.recursive.Optimize(ctx)
+}
+
+// recursiveIterations is the expected number of iterations of a recursive query.
+const recursiveIterations = 10
+
+func (n *logicalRecursiveUnionNode) Estimate() plan.Estimate {
+	base, recursive := n.base.Estimate(), n.recursive.Estimate()
+	rows := base.Rows + recursive.Rows*recursiveIterations
+
+	return plan.Estimate{
+		Rows:        rows,
+		Cost:        base.Cost + recursive.Cost*recursiveIterations + rows*plan.CPUOperatorCost,
+		StartupCost: base.StartupCost,
+	}
+}
+
+func (n *logicalRecursiveUnionNode) Build() nodes.Node {
+	return combination.NewRecursiveUnion(n.base.Build(), n.recursive.Build(), n.working, n.fields, n.distinct)
+}
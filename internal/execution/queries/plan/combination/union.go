@@ -70,6 +70,16 @@ func (n *logicalUnionNode) Filter() impls.Expression {
 func (n *logicalUnionNode) Ordering() impls.OrderExpression { return nil }
 func (n *logicalUnionNode) SupportsMarkRestore() bool       { return false }
 
+func (n *logicalUnionNode) Estimate() plan.Estimate {
+	left, right := n.left.Estimate(), n.right.Estimate()
+
+	return plan.Estimate{
+		Rows:        left.Rows + right.Rows,
+		Cost:        left.Cost + right.Cost + (left.Rows+right.Rows)*plan.CPUOperatorCost,
+		StartupCost: left.StartupCost,
+	}
+}
+
 func (n *logicalUnionNode) Build() nodes.Node {
 	if !n.distinct {
 		return combination.NewAppend(n.left.Build(), n.right.Build(), n.fields)
@@ -0,0 +1,166 @@
+package plan
+
+import (
+	"math"
+
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+// Estimate describes the number of rows a node is expected to produce and the total
+// cost of producing them. Costs are relative to reading a single row during a scan of
+// a table. The startup cost is the part of the cost incurred before the first row is
+// produced (e.g., by sorting or hashing the input of the node).
+type Estimate struct {
+	Rows        float64
+	Cost        float64
+	StartupCost float64
+}
+
+// FractionalCost returns the expected cost of producing the given fraction of the rows
+// of the estimate. Rows following the first are assumed to be produced at an even rate.
+func (e Estimate) FractionalCost(fraction float64) float64 {
+	if fraction >= 1 {
+		return e.Cost
+	}
+
+	return e.StartupCost + (e.Cost-e.StartupCost)*fraction
+}
+
+// RowGoalSetter is implemented by nodes that can choose a plan that produces its first
+// rows more cheaply when only the given number of its rows are expected to be read
+// (e.g., by a parent with a LIMIT clause).
+type RowGoalSetter interface {
+	AddRowGoal(ctx impls.OptimizationContext, rows float64)
+}
+
+// HypotheticalEstimator is implemented by nodes that can estimate the rows and cost of
+// producing their rows under an additional filter, which may reference fields of an
+// outer relation, and in a given order. This is used to cost join strategies, which
+// constrain or order their relations differently.
+type HypotheticalEstimator interface {
+	EstimateHypothetical(filter impls.Expression, order impls.OrderExpression) (Estimate, bool)
+}
+
+// EstimateHypothetical returns the expected rows and cost of producing the rows of the
+// given node under the given additional filter and in the given order (either of which
+// may be nil). Nodes that cannot estimate this themselves are assumed to evaluate the
+// filter over and sort their current output.
+func EstimateHypothetical(node LogicalNode, filter impls.Expression, order impls.OrderExpression) Estimate {
+	if estimator, ok := node.(HypotheticalEstimator); ok {
+		if estimate, ok := estimator.EstimateHypothetical(filter, order); ok {
+			return estimate
+		}
+	}
+
+	estimate := node.Estimate()
+
+	if filter != nil {
+		estimate.Cost += EstimateFilterCost(estimate.Rows, filter)
+		estimate.Rows = ClampRows(estimate.Rows * EstimateSelectivity(filter))
+	}
+
+	if order != nil {
+		estimate.Cost += EstimateSortCost(estimate.Rows)
+		estimate.StartupCost = estimate.Cost
+	}
+
+	return estimate
+}
+
+const (
+	// SeqRowCost is the cost of reading a row during a table scan.
+	SeqRowCost = 1.0
+
+	// RandomRowCost is the cost of reading a row referenced by an index entry.
+	RandomRowCost = 2.0
+
+	// CPUOperatorCost is the cost of evaluating an expression for a single row, or of
+	// comparing or hashing a single value.
+	CPUOperatorCost = 0.25
+)
+
+// defaultGroupSelectivity is the expected ratio of groups to grouped rows.
+const defaultGroupSelectivity = 0.1
+
+// EstimateSelectivity returns the expected fraction of rows that satisfy the given
+// filter.
+func EstimateSelectivity(filter impls.Expression) float64 {
+	return estimateSelectivity(nil, filter)
+}
+
+// EstimateFilterCost returns the cost of evaluating the given filter against the
+// given number of rows.
+func EstimateFilterCost(rows float64, filter impls.Expression) float64 {
+	return rows * CPUOperatorCost * float64(len(expressions.Conjunctions(filter)))
+}
+
+// EstimateSortCost returns the cost of sorting the given number of rows.
+func EstimateSortCost(rows float64) float64 {
+	return rows * math.Log2(rows+1) * CPUOperatorCost
+}
+
+// EstimateMaterializeCost returns the cost of buffering the given number of rows so
+// that they can be read again.
+func EstimateMaterializeCost(rows float64) float64 {
+	return rows * CPUOperatorCost
+}
+
+// ClampRows rounds the given row estimate up to a single row. Estimates of zero rows
+// would make the cost of everything depending on them negligible.
+func ClampRows(rows float64) float64 {
+	return max(rows, 1)
+}
+
+// estimateSelectivity returns the expected fraction of rows of the given table (if
+// non-nil) that satisfy the given filter. The statistics collected by the most recent
+// analysis of the table are used when available. Columns with a unique index are
+// otherwise known to have a distinct value in each row.
+func estimateSelectivity(table impls.Table, filter impls.Expression) float64 {
+	if table == nil {
+		return expressions.EstimateSelectivity(filter, nil)
+	}
+
+	return expressions.EstimateSelectivity(filter, statisticsLookup(table))
+}
+
+func statisticsLookup(table impls.Table) expressions.StatisticsLookup {
+	statistics := table.Statistics()
+
+	var tableFields []fields.Field
+	for _, field := range table.Fields() {
+		tableFields = append(tableFields, field.Field)
+	}
+
+	return func(field fields.Field) (impls.ColumnStatistics, bool) {
+		index, err := fields.FindMatchingFieldIndex(field, tableFields)
+		if err != nil || tableFields[index].Internal() {
+			return impls.ColumnStatistics{}, false
+		}
+
+		if statistics != nil {
+			if columnStatistics, ok := statistics.Columns[field.Name()]; ok {
+				return columnStatistics, true
+			}
+		}
+
+		if isUniqueColumn(table, field) {
+			return impls.ColumnStatistics{DistinctCount: float64(table.Size())}, true
+		}
+
+		return impls.ColumnStatistics{}, false
+	}
+}
+
+func isUniqueColumn(table impls.Table, field fields.Field) bool {
+	for _, index := range table.Indexes() {
+		if uniqueOn := index.UniqueOn(); len(uniqueOn) == 1 && index.Filter() == nil {
+			if _, err := fields.FindMatchingFieldIndex(field, uniqueOn); err == nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,101 @@
+package plan
+
+import (
+	"slices"
+
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type logicalDistinctNode struct {
+	LogicalNode
+	expressions []impls.Expression
+	on          bool
+	sorted      bool
+}
+
+// NewDistinct creates a node emitting the first row of the given node for each distinct
+// set of values of the given expressions. When on is false, the expressions determine
+// every column of the rows that are eventually emitted (as in SELECT DISTINCT), so rows
+// with equal values are interchangeable.
+func NewDistinct(node LogicalNode, expressions []impls.Expression, on bool) LogicalNode {
+	return &logicalDistinctNode{
+		LogicalNode: node,
+		expressions: expressions,
+		on:          on,
+	}
+}
+
+func (n *logicalDistinctNode) AddFilter(ctx impls.OptimizationContext, filter impls.Expression) {
+	if n.on {
+		return // filtering the input would change the first row of each set
+	}
+
+	n.LogicalNode.AddFilter(ctx, filter)
+}
+
+func (n *logicalDistinctNode) AddOrder(ctx impls.OptimizationContext, order impls.OrderExpression) {
+	if n.on {
+		return // ordering the input would change the first row of each set
+	}
+
+	// Rows are emitted in the order of the input
+	n.LogicalNode.AddOrder(ctx, order)
+}
+
+func (n *logicalDistinctNode) Optimize(ctx impls.OptimizationContext) {
+	for i, expr := range n.expressions {
+		n.expressions[i] = expr.Fold()
+	}
+
+	n.LogicalNode.Optimize(ctx)
+	n.sorted = groupsAdjacentRows(n.LogicalNode.Ordering(), n.expressions)
+}
+
+// groupsAdjacentRows returns true if rows with equal values for the given expressions
+// are adjacent when emitted in the given order.
+func groupsAdjacentRows(order impls.OrderExpression, exprs []impls.Expression) bool {
+	if order == nil {
+		return false
+	}
+
+	orderExpressions := order.Expressions()
+	if len(orderExpressions) < len(exprs) {
+		return false
+	}
+
+	for _, orderExpression := range orderExpressions[:len(exprs)] {
+		if !slices.ContainsFunc(exprs, orderExpression.Expression.Equal) {
+			return false
+		}
+	}
+
+	for _, expr := range exprs {
+		if !slices.ContainsFunc(orderExpressions[:len(exprs)], func(orderExpression impls.ExpressionWithDirection) bool {
+			return orderExpression.Expression.Equal(expr)
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (n *logicalDistinctNode) SupportsMarkRestore() bool {
+	return false
+}
+
+func (n *logicalDistinctNode) Estimate() Estimate {
+	estimate := n.LogicalNode.Estimate()
+	estimate.Cost += estimate.Rows * CPUOperatorCost * float64(len(n.expressions))
+	estimate.Rows = ClampRows(estimate.Rows * defaultGroupSelectivity)
+	return estimate
+}
+
+func (n *logicalDistinctNode) Build() nodes.Node {
+	if n.sorted {
+		return nodes.NewUnique(n.LogicalNode.Build(), n.expressions)
+	}
+
+	return nodes.NewDistinct(n.LogicalNode.Build(), n.expressions)
+}
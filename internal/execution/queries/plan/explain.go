@@ -23,6 +23,10 @@ func (n *logicalExplain) Name() string {
 
 var queryPlanField = fields.NewField("", "query plan", types.TypeText, fields.NonInternalField)
 
+func (n *logicalExplain) Command() string {
+	return "EXPLAIN"
+}
+
 func (n *logicalExplain) Fields() []fields.Field {
 	return []fields.Field{queryPlanField}
 }
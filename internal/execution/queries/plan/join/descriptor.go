@@ -9,12 +9,12 @@ type JoinDescriptor struct {
 	originalRightRelations uint
 	operator               JoinOperator
 	referencedRelations    uint
-	isNullRejecting        bool // TODO
+	isNullRejecting        bool
 }
 
 func (n JoinDescriptor) Applicable(s1, s2 uint) bool {
 	switch n.operator.JoinType {
-	case join.JoinTypeInner, join.JoinTypeLeftOuter, join.JoinTypeFullOuter:
+	case join.JoinTypeInner, join.JoinTypeLeftOuter, join.JoinTypeFullOuter, join.JoinTypeSemi, join.JoinTypeAnti:
 		// For these joins, we need all tables referenced in the predicate that are actually in the subtrees being joined
 		tes := intersect(n.referencedRelations, union(n.originalLeftRelations, n.originalRightRelations))
 		lTes := intersect(tes, n.originalLeftRelations)
@@ -40,22 +40,18 @@ func (op1 JoinDescriptor) IsAssociative(op2 JoinDescriptor) bool {
 	}
 
 	if op1.operator.JoinType == join.JoinTypeLeftOuter {
-		if op2.operator.JoinType == join.JoinTypeFullOuter {
-			// TODO - footnote 1
-			return op1.isNullRejecting
-		}
-
-		return true
+		// (e1 ⟕ e2) ⟕ e3 = e1 ⟕ (e2 ⟕ e3) when the top predicate rejects nulls on e2
+		return op2.operator.JoinType == join.JoinTypeLeftOuter && op2.isNullRejecting
 	}
 
 	if op1.operator.JoinType == join.JoinTypeFullOuter {
 		if op2.operator.JoinType == join.JoinTypeLeftOuter {
-			// TODO - footnote 2
+			// (e1 ⟗ e2) ⟕ e3 = e1 ⟗ (e2 ⟕ e3) when the top predicate rejects nulls on e2
 			return op2.isNullRejecting
 		}
 
 		if op2.operator.JoinType == join.JoinTypeFullOuter {
-			// TODO - footnote 3
+			// (e1 ⟗ e2) ⟗ e3 = e1 ⟗ (e2 ⟗ e3) when both predicates reject nulls on e2
 			return op1.isNullRejecting && op2.isNullRejecting
 		}
 	}
@@ -77,6 +73,12 @@ func (op1 JoinDescriptor) IsLeftAsscom(op2 JoinDescriptor) bool {
 		return true
 	}
 
+	if op1.operator.JoinType == join.JoinTypeSemi || op1.operator.JoinType == join.JoinTypeAnti {
+		// (e1 ⋉ e2) op2 e3 = (e1 op2 e3) ⋉ e2, as rows of e1 are filtered independently
+		// of e3 (likewise for ▷)
+		return op2.operator.JoinType != join.JoinTypeFullOuter
+	}
+
 	if op1.operator.JoinType == join.JoinTypeFullOuter {
 		if op2.operator.JoinType == join.JoinTypeLeftOuter {
 			// TODO - footnote 2
@@ -19,6 +19,7 @@ type DescriptorSetBuilder interface {
 	AddRelation(relation plan.LogicalNode) uint
 	AddNode(node JoinDescriptor) uint
 	MaskReferencedTables(condition impls.Expression) uint
+	RejectsNulls(condition impls.Expression, mask uint) bool
 }
 
 type descriptorSet struct {
@@ -71,3 +72,19 @@ func (ds *descriptorSet) MaskReferencedTables(condition impls.Expression) uint {
 
 	return mask
 }
+
+// RejectsNulls returns true if the given condition rejects rows in which the fields of
+// any of the relations in the given (non-empty) mask are NULL.
+func (ds *descriptorSet) RejectsNulls(condition impls.Expression, mask uint) bool {
+	if mask == 0 {
+		return false
+	}
+
+	for i, r := range ds.relations {
+		if isSubset(bit(uint(i)), mask) && !expressions.IsNullRejecting(condition, r.Fields()) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,186 @@
+package join
+
+import (
+	"math/bits"
+)
+
+// joinGraph is the query hypergraph over the relations of a join tree. Each edge is a
+// set of relations which a join can connect once all of them are present on either
+// side of the join, and only if some are present on each side (e.g., the relations
+// referenced by a conjunction of an inner join condition). An edge over a set R stands
+// for every hyperedge (u, R \ u) of the hypergraph.
+type joinGraph struct {
+	numRelations uint
+	edges        []uint
+}
+
+// newJoinGraph creates a graph over the given number of relations with the given edges.
+// Edges are added between each pair of components of the graph connected by edges over
+// two relations, so that components are joined by a cross product only once each has
+// been joined. Relations connected only by larger edges may need such a cross product
+// before the edge can connect them.
+func newJoinGraph(numRelations uint, edges []uint) *joinGraph {
+	g := &joinGraph{numRelations: numRelations}
+	for _, edge := range edges {
+		if bits.OnesCount(edge) > 1 {
+			g.edges = append(g.edges, edge)
+		}
+	}
+
+	components := g.components()
+	for i, c1 := range components {
+		for _, c2 := range components[i+1:] {
+			g.edges = append(g.edges, union(c1, c2))
+		}
+	}
+
+	return g
+}
+
+// components returns the relations of each component of the graph connected by edges
+// over two relations.
+func (g *joinGraph) components() []uint {
+	var components []uint
+	for i := uint(0); i < g.numRelations; i++ {
+		component := bit(i)
+		for _, edge := range g.edges {
+			if bits.OnesCount(edge) == 2 && overlaps(edge, component) {
+				component = union(component, edge)
+			}
+		}
+
+		var merged []uint
+		for _, other := range components {
+			if overlaps(other, component) {
+				component = union(component, other)
+			} else {
+				merged = append(merged, other)
+			}
+		}
+
+		components = append(merged, component)
+	}
+
+	return components
+}
+
+// connected returns true if an edge of the graph connects the given disjoint sets.
+func (g *joinGraph) connected(s1, s2 uint) bool {
+	for _, edge := range g.edges {
+		if isSubset(edge, union(s1, s2)) && overlaps(edge, s1) && overlaps(edge, s2) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// neighborhood returns the neighborhood of the given set of relations, excluding the
+// relations in x. Each edge reaching relations outside of both sets contributes the
+// lowest such relation, unless the relations it reaches include those reached by
+// another edge.
+func (g *joinGraph) neighborhood(s, x uint) uint {
+	var reached []uint
+	for _, edge := range g.edges {
+		if v := difference(edge, s); overlaps(edge, s) && v != 0 && !overlaps(v, x) {
+			reached = append(reached, v)
+		}
+	}
+
+	neighbors := uint(0)
+	for _, v := range reached {
+		minimal := true
+		for _, w := range reached {
+			if w != v && isSubset(w, v) {
+				minimal = false
+				break
+			}
+		}
+
+		if minimal {
+			neighbors = union(neighbors, lowest(v))
+		}
+	}
+
+	return neighbors
+}
+
+// enumeratePairs calls emit for each pair of disjoint, connected sets of relations
+// which are connected to one another (csg-cmp-pairs) by the DPhyp algorithm described
+// in "Dynamic Programming Strikes Back" by Moerkotte and Neumann. Each pair is emitted
+// once, and only after every pair whose union is either set of the pair. The given
+// function reports whether a (connected) set of relations can be joined at all.
+func (g *joinGraph) enumeratePairs(exists func(s uint) bool, emit func(s1, s2 uint)) {
+	e := &pairEnumerator{graph: g, exists: exists, emit: emit}
+
+	for i := int(g.numRelations) - 1; i >= 0; i-- {
+		v := bit(uint(i))
+		e.emitCsg(v)
+		e.enumerateCsgRec(v, all(uint(i)+1))
+	}
+}
+
+type pairEnumerator struct {
+	graph  *joinGraph
+	exists func(s uint) bool
+	emit   func(s1, s2 uint)
+}
+
+// enumerateCsgRec emits each connected set extending s1 by relations not in x.
+func (e *pairEnumerator) enumerateCsgRec(s1, x uint) {
+	neighbors := e.graph.neighborhood(s1, x)
+	if neighbors == 0 {
+		return
+	}
+
+	subsets := generateSubsetMasksMatchingPattern(neighbors)
+	for _, n := range subsets {
+		if e.exists(union(s1, n)) {
+			e.emitCsg(union(s1, n))
+		}
+	}
+
+	for _, n := range subsets {
+		e.enumerateCsgRec(union(s1, n), union(x, neighbors))
+	}
+}
+
+// emitCsg emits each pair of the given connected set with a complement set containing
+// only relations after the lowest relation of s1.
+func (e *pairEnumerator) emitCsg(s1 uint) {
+	x := union(s1, all(uint(bits.TrailingZeros(s1))+1))
+	neighbors := e.graph.neighborhood(s1, x)
+
+	for i := bits.Len(neighbors) - 1; i >= 0; i-- {
+		if !has(neighbors, uint(i)) {
+			continue
+		}
+
+		s2 := bit(uint(i))
+		if e.graph.connected(s1, s2) {
+			e.emit(s1, s2)
+		}
+
+		e.enumerateCmpRec(s1, s2, union(x, intersect(all(uint(i)+1), neighbors)))
+	}
+}
+
+// enumerateCmpRec emits each pair of s1 with a connected set extending s2 by relations
+// not in x.
+func (e *pairEnumerator) enumerateCmpRec(s1, s2, x uint) {
+	neighbors := e.graph.neighborhood(s2, x)
+	if neighbors == 0 {
+		return
+	}
+
+	subsets := generateSubsetMasksMatchingPattern(neighbors)
+	for _, n := range subsets {
+		if e.exists(union(s2, n)) && e.graph.connected(s1, union(s2, n)) {
+			e.emit(s1, union(s2, n))
+		}
+	}
+
+	for _, n := range subsets {
+		e.enumerateCmpRec(s1, union(s2, n), union(x, neighbors))
+	}
+}
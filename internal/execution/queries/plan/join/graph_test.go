@@ -0,0 +1,70 @@
+package join
+
+import (
+	"math/bits"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinGraphEnumeratePairs(t *testing.T) {
+	for _, testCase := range []struct {
+		name         string
+		numRelations uint
+		edges        []uint
+	}{
+		{name: "chain", numRelations: 5, edges: []uint{0b00011, 0b00110, 0b01100, 0b11000}},
+		{name: "star", numRelations: 5, edges: []uint{0b00011, 0b00101, 0b01001, 0b10001}},
+		{name: "cycle", numRelations: 4, edges: []uint{0b0011, 0b0110, 0b1100, 0b1001}},
+		{name: "clique", numRelations: 4, edges: []uint{0b0011, 0b0101, 0b1001, 0b0110, 0b1010, 0b1100}},
+		{name: "hyperedge", numRelations: 5, edges: []uint{0b00011, 0b01100, 0b10111}},
+		{name: "disconnected", numRelations: 5, edges: []uint{0b00011, 0b01100}},
+		{name: "no edges", numRelations: 3},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			g := newJoinGraph(testCase.numRelations, testCase.edges)
+
+			var isConnected func(s uint) bool
+			isConnected = func(s uint) bool {
+				if bits.OnesCount(s) == 1 {
+					return true
+				}
+
+				for _, s1 := range generateSubsetMasksMatchingPattern(s) {
+					if s2 := difference(s, s1); s2 != 0 && isConnected(s1) && isConnected(s2) && g.connected(s1, s2) {
+						return true
+					}
+				}
+
+				return false
+			}
+
+			// Every pair of disjoint connected sets connected to one another, keyed by
+			// the union of the pair
+			expected := map[uint][][2]uint{}
+			for _, s := range generateSubsetMasks(testCase.numRelations) {
+				for _, s1 := range generateSubsetMasksMatchingPattern(s) {
+					if s2 := difference(s, s1); s2 != 0 && s1 < s2 && isConnected(s1) && isConnected(s2) && g.connected(s1, s2) {
+						expected[s] = append(expected[s], [2]uint{s1, s2})
+					}
+				}
+			}
+
+			emitted := map[uint][][2]uint{}
+			g.enumeratePairs(isConnected, func(s1, s2 uint) {
+				// Sets are complete before they are joined to another
+				for _, s := range []uint{s1, s2} {
+					assert.ElementsMatch(t, expected[s], emitted[s], "pairs of %b emitted after use", s)
+				}
+
+				emitted[union(s1, s2)] = append(emitted[union(s1, s2)], [2]uint{min(s1, s2), max(s1, s2)})
+			})
+
+			for s, pairs := range expected {
+				assert.ElementsMatch(t, pairs, emitted[s], "pairs of %b", s)
+			}
+			assert.Len(t, emitted, len(expected))
+			assert.Contains(t, emitted, all(testCase.numRelations))
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package join
 
 import (
+	"github.com/efritz/gostgres/internal/execution/expressions"
 	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	"github.com/efritz/gostgres/internal/execution/queries/plan"
 	"github.com/efritz/gostgres/internal/shared/fields"
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/shared/types"
@@ -9,7 +11,12 @@ import (
 
 // TODO - gomockgen
 
-type mockLogicalNode struct{ name string }
+type mockLogicalNode struct {
+	name        string
+	estimate    plan.Estimate
+	ordering    impls.OrderExpression
+	markRestore bool
+}
 
 func newMockLogicalNode(name string) *mockLogicalNode {
 	return &mockLogicalNode{name: name}
@@ -32,6 +39,23 @@ func (*mockLogicalNode) AddFilter(impls.OptimizationContext, impls.Expression)
 func (*mockLogicalNode) AddOrder(impls.OptimizationContext, impls.OrderExpression) {}
 func (*mockLogicalNode) Optimize(impls.OptimizationContext)                        {}
 func (*mockLogicalNode) Filter() impls.Expression                                  { return nil }
-func (*mockLogicalNode) Ordering() impls.OrderExpression                           { return nil }
-func (*mockLogicalNode) SupportsMarkRestore() bool                                 { return false }
+func (m *mockLogicalNode) Ordering() impls.OrderExpression                         { return m.ordering }
+func (m *mockLogicalNode) SupportsMarkRestore() bool                               { return m.markRestore }
+func (m *mockLogicalNode) Estimate() plan.Estimate                                 { return m.estimate }
 func (*mockLogicalNode) Build() nodes.Node                                         { return nil }
+
+func (m *mockLogicalNode) EstimateHypothetical(filter impls.Expression, order impls.OrderExpression) (plan.Estimate, bool) {
+	estimate := m.estimate
+
+	if filter != nil {
+		estimate.Cost += plan.EstimateFilterCost(estimate.Rows, filter)
+		estimate.Rows = plan.ClampRows(estimate.Rows * plan.EstimateSelectivity(filter))
+	}
+
+	if order != nil && !expressions.SubsumesOrder(order, m.ordering) {
+		estimate.Cost += plan.EstimateSortCost(estimate.Rows)
+		estimate.StartupCost = estimate.Cost
+	}
+
+	return estimate, true
+}
@@ -12,6 +12,7 @@ func intersect(a, b uint) uint  { return a & b }                     // a ∩ b
 func difference(a, b uint) uint { return a & ^b }                    // a \ b
 func isSubset(a, b uint) bool   { return intersect(a, b) == a }      // a ⊆ b
 func overlaps(a, b uint) bool   { return intersect(a, b) != 0 }      // a ∩ b ≠ ∅
+func lowest(m uint) uint        { return intersect(m, -m) }          // {min(m)}
 
 func coalesce(a, b uint) uint {
 	if a != 0 {
@@ -51,6 +51,28 @@ type joinNodeInternal struct {
 	right    JoinNode
 	operator JoinOperator
 	strategy logicalJoinStrategy
+
+	// filter holds filters added to an outer join, which cannot be evaluated as part
+	// of its condition
+	filter impls.Expression
+
+	// ordered is true once the order of the joins in this subtree has been chosen
+	ordered bool
+
+	// order is the order in which the parent of the join requested its rows, and
+	// rowGoal is the number of rows the parent is expected to read (zero if all)
+	order   impls.OrderExpression
+	rowGoal float64
+
+	// preserveOrder is true if the join must emit rows in the order of its left
+	// relation, as chosen by join ordering to provide the order requested of the tree.
+	// Strategies are chosen by the cost of producing the given fraction of the rows of
+	// the join (zero if all).
+	preserveOrder bool
+	rowFraction   float64
+
+	// estimate caches the estimate of the node prior to optimization
+	estimate *plan.Estimate
 }
 
 type JoinOperator struct {
@@ -82,12 +104,17 @@ func (n *joinNodeInternal) ConvertRightJoinsToLeftJoins() JoinNode {
 }
 
 func (n *joinNodeInternal) BuildDescriptor(b DescriptorSetBuilder) uint {
+	// Relations of the subtrees must be added before the condition can be masked
+	leftRelations := n.left.BuildDescriptor(b)
+	rightRelations := n.right.BuildDescriptor(b)
+	referencedRelations := b.MaskReferencedTables(n.operator.Condition)
+
 	return b.AddNode(JoinDescriptor{
-		originalLeftRelations:  n.left.BuildDescriptor(b),
-		originalRightRelations: n.right.BuildDescriptor(b),
+		originalLeftRelations:  leftRelations,
+		originalRightRelations: rightRelations,
 		operator:               n.operator,
-		referencedRelations:    b.MaskReferencedTables(n.operator.Condition),
-		isNullRejecting:        false, // TODO
+		referencedRelations:    referencedRelations,
+		isNullRejecting:        b.RejectsNulls(n.operator.Condition, referencedRelations),
 	})
 }
 
@@ -130,6 +157,14 @@ func (n *joinNodeLeaf) SupportsMarkRestore() bool {
 	return n.relation.SupportsMarkRestore()
 }
 
+func (n *joinNodeLeaf) Estimate() plan.Estimate {
+	return n.relation.Estimate()
+}
+
+func (n *joinNodeLeaf) EstimateHypothetical(filter impls.Expression, order impls.OrderExpression) (plan.Estimate, bool) {
+	return plan.EstimateHypothetical(n.relation, filter, order), true
+}
+
 func (n *joinNodeLeaf) Build() nodes.Node {
 	return n.relation.Build()
 }
@@ -142,49 +177,118 @@ func (n *joinNodeInternal) Name() string {
 }
 
 func (n *joinNodeInternal) Fields() []fields.Field {
+	if isSemiOrAnti(n.operator.JoinType) {
+		// Only rows of the left relation are emitted
+		return n.left.Fields()
+	}
+
+	return n.joinedFields()
+}
+
+// joinedFields returns the fields of the rows to which the join condition is applied.
+func (n *joinNodeInternal) joinedFields() []fields.Field {
 	return append(slices.Clone(n.left.Fields()), n.right.Fields()...)
 }
 
 func (n *joinNodeInternal) AddFilter(ctx impls.OptimizationContext, filterExpression impls.Expression) {
-	// TODO - determine when this might inappropriately alter query semantics
-	n.operator.Condition = expressions.UnionFilters(n.operator.Condition, filterExpression)
+	if isInner(n.operator.JoinType) {
+		n.operator.Condition = expressions.UnionFilters(n.operator.Condition, filterExpression)
+	} else {
+		// Filters on the result of an outer join reject rows that its condition would
+		// have extended with NULL values; they are applied during optimization
+		n.filter = expressions.UnionFilters(n.filter, filterExpression)
+	}
+
+	n.estimate = nil
 }
 
 func (n *joinNodeInternal) AddOrder(ctx impls.OptimizationContext, orderExpression impls.OrderExpression) {
+	n.order = orderExpression
 	util.LowerOrder(ctx, orderExpression, n.left, n.right)
+	n.estimate = nil
 }
 
-func (n *joinNodeInternal) Optimize(ctx impls.OptimizationContext) {
-	// NOTE: Outer fields depend on nested loop join strategy
-	// Merge and hash joins won't have have LHS rows available to RHS
+func (n *joinNodeInternal) AddRowGoal(ctx impls.OptimizationContext, rows float64) {
+	n.rowGoal = rows
+	n.estimate = nil
+}
 
+func (n *joinNodeInternal) Optimize(ctx impls.OptimizationContext) {
 	if n.operator.Condition != nil {
 		n.operator.Condition = n.operator.Condition.Fold()
-		// TODO - determine when this might inappropriately alter query semantics
-		util.LowerFilter(ctx, n.operator.Condition, n.left)
-		util.LowerFilter(ctx.AddOuterFields(n.left.Fields()), n.operator.Condition, n.right)
 	}
 
-	// TODO:
-	//   (1) Ensure we don't re-optimize for multiple levels of the same join tree;
-	//   disable optimization until we hit leaves.
-	//   (2) Select lowest cost alternative. Requires a system for estimating costs
-	//   which we don't currently have.
-	for _, v := range EnumerateValidJoinTrees(n) {
-		_ = v
-		// fmt.Printf("Considering alternative join: %s\n", v)
+	if !n.ordered || n.filter != nil {
+		reduceOuterJoins(n, n.filter)
 	}
 
-	n.left.Optimize(ctx)
-	n.right.Optimize(ctx.AddOuterFields(n.left.Fields()))
+	if n.filter != nil {
+		if isInner(n.operator.JoinType) {
+			n.operator.Condition = expressions.UnionFilters(n.operator.Condition, n.filter)
+		} else if n.operator.JoinType == join.JoinTypeLeftOuter || isSemiOrAnti(n.operator.JoinType) {
+			// Rows of the left relation are emitted with their original values
+			util.LowerFilter(ctx, n.filter, n.left)
+		}
 
-	// TODO - determine when this might inappropriately alter query semantics
+		// Remaining filters are applied by the parent of the join
+		n.filter = nil
+	}
+
+	if !n.ordered {
+		// Choose the order of every join in the tree rooted at this node; the joins
+		// below this node are already ordered when optimized
+		n.orderJoins(ctx)
+	}
+
+	if n.operator.Condition != nil {
+		util.LowerFilter(ctx, n.operator.Condition, n.filterableRelations()...)
+	}
+
+	n.strategy = selectJoinStrategy(n.candidateStrategies(), n.fraction())
+	n.strategy.optimize(ctx)
+
+	// Conjunctions satisfied by every row of either relation need not be re-evaluated
 	n.operator.Condition = expressions.FilterDifference(n.operator.Condition, expressions.UnionFilters(n.left.Filter(), n.right.Filter()))
-	n.strategy = &logicalNestedLoopJoinStrategy{n: n}
 }
 
 func (n *joinNodeInternal) Filter() impls.Expression {
-	return expressions.UnionFilters(n.operator.Condition, n.left.Filter(), n.right.Filter())
+	switch {
+	case isInner(n.operator.JoinType):
+		return expressions.UnionFilters(n.operator.Condition, n.left.Filter(), n.right.Filter())
+
+	case n.operator.JoinType == join.JoinTypeLeftOuter:
+		// Rows of the right relation may be replaced by NULL values
+		return n.left.Filter()
+
+	case isSemiOrAnti(n.operator.JoinType):
+		return n.left.Filter()
+	}
+
+	return nil
+}
+
+// filterableRelations returns the relations into which the join condition can be
+// lowered. Rows of a relation preserved by an outer join must not be filtered.
+func (n *joinNodeInternal) filterableRelations() []util.LogicalNode {
+	switch {
+	case isInner(n.operator.JoinType):
+		return []util.LogicalNode{n.left, n.right}
+
+	case n.operator.JoinType == join.JoinTypeSemi:
+		// A row of the left relation is emitted only if the whole condition holds
+		return []util.LogicalNode{n.left, n.right}
+
+	case n.operator.JoinType == join.JoinTypeLeftOuter, n.operator.JoinType == join.JoinTypeAnti:
+		return []util.LogicalNode{n.right}
+	}
+
+	return nil
+}
+
+// isSemiOrAnti returns true if the given join emits only rows of its left relation
+// (those with a match for semi joins, and those without a match for anti joins).
+func isSemiOrAnti(joinType join.JoinType) bool {
+	return joinType == join.JoinTypeSemi || joinType == join.JoinTypeAnti
 }
 
 func (n *joinNodeInternal) Ordering() impls.OrderExpression {
@@ -192,6 +296,16 @@ func (n *joinNodeInternal) Ordering() impls.OrderExpression {
 		panic("No strategy set - optimization required before ordering can be determined")
 	}
 
+	if n.operator.JoinType == join.JoinTypeFullOuter {
+		// Rows of the right relation without a match are emitted out of order
+		return nil
+	}
+
+	if isSemiOrAnti(n.operator.JoinType) {
+		// Rows of the left relation are emitted in the order they are scanned
+		return n.left.Ordering()
+	}
+
 	return n.strategy.Ordering()
 }
 
@@ -199,36 +313,297 @@ func (n *joinNodeInternal) SupportsMarkRestore() bool {
 	return false
 }
 
+func (n *joinNodeInternal) Estimate() plan.Estimate {
+	if n.strategy != nil {
+		return n.strategy.Estimate()
+	}
+
+	if n.estimate == nil {
+		// Estimate the strategy that optimization would select
+		estimate := selectJoinStrategy(n.candidateStrategies(), n.fraction()).Estimate()
+		n.estimate = &estimate
+	}
+
+	return *n.estimate
+}
+
+// EstimateHypothetical estimates the rows of the join under the given filter. Each
+// conjunction referencing fields of only one side of the join is estimated against
+// that side, whose relations may have collected statistics.
+func (n *joinNodeInternal) EstimateHypothetical(filter impls.Expression, order impls.OrderExpression) (plan.Estimate, bool) {
+	estimate := n.Estimate()
+
+	if filter != nil {
+		selectivity := 1.0
+		for _, expression := range expressions.Conjunctions(filter) {
+			selectivity *= n.selectivity(expression)
+		}
+
+		estimate.Cost += plan.EstimateFilterCost(estimate.Rows, filter)
+		estimate.Rows = plan.ClampRows(estimate.Rows * selectivity)
+	}
+
+	if order != nil {
+		estimate.Cost += plan.EstimateSortCost(estimate.Rows)
+		estimate.StartupCost = estimate.Cost
+	}
+
+	return estimate, true
+}
+
+// selectivity returns the expected fraction of rows of the join satisfying the given
+// conjunction. Fields not bound by the join (e.g., outer fields) are not considered.
+func (n *joinNodeInternal) selectivity(expression impls.Expression) float64 {
+	var referencesLeft, referencesRight bool
+	for _, field := range expressions.Fields(expression) {
+		if _, err := fields.FindMatchingFieldIndex(field, n.left.Fields()); err == nil {
+			referencesLeft = true
+		} else if _, err := fields.FindMatchingFieldIndex(field, n.right.Fields()); err == nil {
+			referencesRight = true
+		}
+	}
+
+	var side JoinNode
+	switch {
+	case referencesLeft && !referencesRight:
+		side = n.left
+	case referencesRight && !referencesLeft && !isSemiOrAnti(n.operator.JoinType):
+		side = n.right
+	default:
+		return plan.EstimateSelectivity(expression)
+	}
+
+	if rows := side.Estimate().Rows; rows > 0 {
+		return min(plan.EstimateHypothetical(side, expression, nil).Rows/rows, 1)
+	}
+
+	return plan.EstimateSelectivity(expression)
+}
+
+// fraction returns the fraction of the rows of the join expected to be read.
+func (n *joinNodeInternal) fraction() float64 {
+	if n.rowFraction == 0 {
+		return 1
+	}
+
+	return n.rowFraction
+}
+
 func (n *joinNodeInternal) Build() nodes.Node {
 	left := n.left.Build()
 	right := n.right.Build()
+	estimate := n.strategy.Estimate()
 
 	return nodes.NewJoin(
 		left,
 		right,
+		serializedJoinType(n.operator.JoinType),
 		n.operator.Condition,
 		n.Fields(),
-		n.strategy.Build(left, right, n.Fields()),
+		n.strategy.Build(left, right, n.joinedFields()),
+		estimate.Rows,
+		estimate.Cost,
 	)
 }
 
+func serializedJoinType(joinType join.JoinType) string {
+	switch joinType {
+	case join.JoinTypeLeftOuter:
+		return "left join"
+	case join.JoinTypeFullOuter:
+		return "full join"
+	case join.JoinTypeSemi:
+		return "semi join"
+	case join.JoinTypeAnti:
+		return "anti join"
+	}
+
+	return "join"
+}
+
+//
+//
+
+// candidateStrategies returns the strategies that can evaluate the join, along with
+// estimates of their cost. Nested loop joins scan the right relation once for each row
+// of the left relation, and can use the join condition to constrain each scan (e.g.,
+// as an index lookup). Hash and merge joins scan each relation once, and are possible
+// only when the join condition equates expressions over each relation.
+func (n *joinNodeInternal) candidateStrategies() []logicalJoinStrategy {
+	left := n.left.Estimate()
+	right := n.right.Estimate()
+	pairs, pairExpressions, rest := decomposeFilter(n)
+	joinFilter := expressions.UnionFilters(append(slices.Clone(pairExpressions), rest...)...)
+
+	// Estimate the rows of each relation matching a single row of the other. Matching
+	// rows are counted from both sides as either side may reveal the join keys to be
+	// unique (e.g., a foreign key joined to the primary key it references).
+	inner := plan.EstimateHypothetical(n.right, joinFilter, nil)
+	outer := plan.EstimateHypothetical(n.left, joinFilter, nil)
+	rows := plan.ClampRows(min(left.Rows*inner.Rows, right.Rows*outer.Rows))
+
+	switch n.operator.JoinType {
+	case join.JoinTypeLeftOuter:
+		// Every row of the left relation is emitted at least once
+		rows = max(rows, left.Rows)
+
+	case join.JoinTypeFullOuter:
+		// Rows of the right relation without a match are emitted once more
+		rows = max(rows, left.Rows) + max(right.Rows-rows, 0)
+
+	case join.JoinTypeSemi:
+		// Rows of the left relation are emitted at most once
+		rows = plan.ClampRows(left.Rows * min(inner.Rows, 1))
+
+	case join.JoinTypeAnti:
+		rows = plan.ClampRows(left.Rows - left.Rows*min(inner.Rows, 1))
+	}
+
+	outputCost := rows * plan.CPUOperatorCost
+
+	// The right relation is scanned once for each row of the left relation, except for
+	// full joins, which scan it once and compare it with each row of the left relation
+	nestedLoopCost := left.Cost + left.Rows*inner.Cost + outputCost
+	if n.operator.JoinType == join.JoinTypeFullOuter {
+		nestedLoopCost = left.Cost + right.Cost + plan.EstimateFilterCost(left.Rows*right.Rows, joinFilter) + outputCost
+	}
+
+	nestedLoopStartupCost := left.StartupCost + inner.StartupCost
+	if n.operator.JoinType == join.JoinTypeFullOuter {
+		nestedLoopStartupCost = left.StartupCost + right.StartupCost
+	}
+
+	strategies := []logicalJoinStrategy{
+		&logicalNestedLoopJoinStrategy{
+			n:        n,
+			estimate: plan.Estimate{Rows: rows, Cost: nestedLoopCost, StartupCost: nestedLoopStartupCost},
+		},
+	}
+
+	if len(pairs) == 0 {
+		return strategies
+	}
+
+	filter := expressions.UnionFilters(rest...)
+	outputCost += plan.EstimateFilterCost(rows, filter)
+	keyCost := float64(len(pairs)) * plan.CPUOperatorCost
+
+	// The right relation is hashed and the left relation probes the hash table
+	hashCost := left.Cost + right.Cost + right.Rows*2*keyCost + left.Rows*keyCost + outputCost
+	hashStartupCost := left.StartupCost + right.Cost + right.Rows*2*keyCost
+
+	var lefts, rights []impls.ExpressionWithDirection
+	for _, p := range pairs {
+		lefts = append(lefts, impls.ExpressionWithDirection{Expression: p.Left})
+		rights = append(rights, impls.ExpressionWithDirection{Expression: p.Right})
+	}
+	leftOrder := expressions.NewOrderExpression(lefts)
+	rightOrder := expressions.NewOrderExpression(rights)
+
+	// Both relations are read in order of the join keys, sorting them if necessary
+	sortedLeft := plan.EstimateHypothetical(n.left, nil, leftOrder)
+	sortedRight := plan.EstimateHypothetical(n.right, nil, rightOrder)
+	mergeCost := sortedLeft.Cost + sortedRight.Cost + (left.Rows+right.Rows)*keyCost + outputCost
+	mergeStartupCost := sortedLeft.StartupCost + sortedRight.StartupCost
+
+	// Rows of the right relation are revisited for each left row sharing their key; a
+	// relation that cannot return to an earlier row itself is buffered by the join
+	if !n.right.SupportsMarkRestore() {
+		mergeCost += plan.EstimateMaterializeCost(right.Rows)
+	}
+
+	strategies = append(strategies, &logicalHashJoinStrategy{
+		n:               n,
+		pairs:           pairs,
+		pairExpressions: pairExpressions,
+		estimate:        plan.Estimate{Rows: rows, Cost: hashCost, StartupCost: hashStartupCost},
+	})
+
+	if n.preserveOrder {
+		// Rows of the left relation are re-ordered by merge joins
+		return strategies
+	}
+
+	if n.operator.JoinType == join.JoinTypeFullOuter && len(rest) > 0 {
+		// Whether a row of the right relation matches any row of the left relation is
+		// not known until the rows sharing its key have been compared with it; a merge
+		// join moves past rows of the right relation before that is determined
+		return strategies
+	}
+
+	if isSemiOrAnti(n.operator.JoinType) || slices.ContainsFunc(pairs, func(pair join.EqualityPair) bool { return pair.NullAware }) {
+		// Not supported by merge joins
+		return strategies
+	}
+
+	return append(strategies, &logicalMergeJoinStrategy{
+		n:               n,
+		pairs:           pairs,
+		pairExpressions: pairExpressions,
+		leftOrder:       leftOrder,
+		rightOrder:      rightOrder,
+		estimate:        plan.Estimate{Rows: rows, Cost: mergeCost, StartupCost: mergeStartupCost},
+	})
+}
+
+// selectJoinStrategy returns the strategy with the lowest estimated cost of producing
+// the given fraction of its rows. Ties are broken in favor of earlier strategies.
+func selectJoinStrategy(strategies []logicalJoinStrategy, fraction float64) logicalJoinStrategy {
+	best := strategies[0]
+	for _, strategy := range strategies[1:] {
+		if strategy.Estimate().FractionalCost(fraction) < best.Estimate().FractionalCost(fraction) {
+			best = strategy
+		}
+	}
+
+	return best
+}
+
 //
 //
 
 type logicalJoinStrategy interface {
+	Estimate() plan.Estimate
 	Ordering() impls.OrderExpression
 	Build(left nodes.Node, right nodes.Node, fields []fields.Field) nodes.JoinStrategy
+
+	// optimize optimizes the relations of the join as required by the strategy.
+	optimize(ctx impls.OptimizationContext)
 }
 
 //
 //
 
 type logicalNestedLoopJoinStrategy struct {
-	n *joinNodeInternal
+	n        *joinNodeInternal
+	estimate plan.Estimate
 }
 
 var _ logicalJoinStrategy = &logicalNestedLoopJoinStrategy{}
 
+func (s *logicalNestedLoopJoinStrategy) Estimate() plan.Estimate {
+	return s.estimate
+}
+
+func (s *logicalNestedLoopJoinStrategy) optimize(ctx impls.OptimizationContext) {
+	s.n.left.Optimize(ctx)
+
+	if s.n.operator.JoinType == join.JoinTypeFullOuter {
+		// The right relation is scanned once so that its unmatched rows can be emitted
+		s.n.right.Optimize(ctx)
+		return
+	}
+
+	// Rows of the left relation are available while scanning the right relation
+	ctx = ctx.AddOuterFields(s.n.left.Fields())
+
+	if s.n.operator.Condition != nil {
+		util.LowerFilter(ctx, s.n.operator.Condition, s.n.right)
+	}
+
+	s.n.right.Optimize(ctx)
+}
+
 func (s *logicalNestedLoopJoinStrategy) Ordering() impls.OrderExpression {
 	leftOrdering := s.n.left.Ordering()
 	if leftOrdering == nil {
@@ -247,6 +622,7 @@ func (s *logicalNestedLoopJoinStrategy) Build(left nodes.Node, right nodes.Node,
 	return join.NewNestedLoopJoinStrategy(
 		left,
 		right,
+		s.n.operator.JoinType,
 		s.n.operator.Condition,
 		fields,
 	)
@@ -256,27 +632,51 @@ func (s *logicalNestedLoopJoinStrategy) Build(left nodes.Node, right nodes.Node,
 //
 
 type logicalMergeJoinStrategy struct {
-	n     *joinNodeInternal
-	pairs []join.EqualityPair
+	n               *joinNodeInternal
+	pairs           []join.EqualityPair
+	pairExpressions []impls.Expression
+	leftOrder       impls.OrderExpression
+	rightOrder      impls.OrderExpression
+	estimate        plan.Estimate
 }
 
 var _ logicalJoinStrategy = &logicalMergeJoinStrategy{}
 
+func (s *logicalMergeJoinStrategy) Estimate() plan.Estimate {
+	return s.estimate
+}
+
+func (s *logicalMergeJoinStrategy) optimize(ctx impls.OptimizationContext) {
+	s.n.left.AddOrder(ctx, s.leftOrder)
+	s.n.right.AddOrder(ctx, s.rightOrder)
+	s.n.left.Optimize(ctx)
+	s.n.right.Optimize(ctx)
+}
+
 func (s *logicalMergeJoinStrategy) Ordering() impls.OrderExpression {
 	// TODO - can add right fields as well?
-	return s.n.left.Ordering()
+	if ordering := s.n.left.Ordering(); expressions.SubsumesOrder(s.leftOrder, ordering) {
+		return ordering
+	}
+
+	return s.leftOrder
 }
 
 func (s *logicalMergeJoinStrategy) Build(left nodes.Node, right nodes.Node, fields []fields.Field) nodes.JoinStrategy {
-	// return &mergeJoinStrategy{
-	// 	n:      n,
-	// 	pairs:  s.pairs,
-	// 	fields: n.fields,
-	// }
+	// Relations not already ordered by the join keys are sorted
+	if !expressions.SubsumesOrder(s.leftOrder, s.n.left.Ordering()) {
+		left = nodes.NewOrder(left, s.leftOrder, s.n.left.Fields())
+	}
+	if !expressions.SubsumesOrder(s.rightOrder, s.n.right.Ordering()) {
+		right = nodes.NewOrder(right, s.rightOrder, s.n.right.Fields())
+	}
+
 	return join.NewMergeJoinStrategy(
 		left,
 		right,
+		s.n.operator.JoinType,
 		s.pairs,
+		expressions.FilterDifference(s.n.operator.Condition, expressions.UnionFilters(s.pairExpressions...)),
 		fields,
 	)
 }
@@ -285,12 +685,23 @@ func (s *logicalMergeJoinStrategy) Build(left nodes.Node, right nodes.Node, fiel
 //
 
 type logicalHashJoinStrategy struct {
-	n     *joinNodeInternal
-	pairs []join.EqualityPair
+	n               *joinNodeInternal
+	pairs           []join.EqualityPair
+	pairExpressions []impls.Expression
+	estimate        plan.Estimate
 }
 
 var _ logicalJoinStrategy = &logicalHashJoinStrategy{}
 
+func (s *logicalHashJoinStrategy) Estimate() plan.Estimate {
+	return s.estimate
+}
+
+func (s *logicalHashJoinStrategy) optimize(ctx impls.OptimizationContext) {
+	s.n.left.Optimize(ctx)
+	s.n.right.Optimize(ctx)
+}
+
 func (s *logicalHashJoinStrategy) Ordering() impls.OrderExpression {
 	return s.n.left.Ordering()
 }
@@ -299,7 +710,9 @@ func (s *logicalHashJoinStrategy) Build(left nodes.Node, right nodes.Node, field
 	return join.NewHashJoinStrategy(
 		left,
 		right,
+		s.n.operator.JoinType,
 		s.pairs,
+		expressions.FilterDifference(s.n.operator.Condition, expressions.UnionFilters(s.pairExpressions...)),
 		fields,
 	)
 }
@@ -307,76 +720,58 @@ func (s *logicalHashJoinStrategy) Build(left nodes.Node, right nodes.Node, field
 //
 //
 
-// const (
-// 	EnableHashJoins  = false
-// 	EnableMergeJoins = false
-// )
-
-// func selectJoinStrategy(ctx impls.OptimizationContext, n *joinNode) joinStrategy {
-// 	if pairs, ok := decomposeFilter(n); ok {
-// 		if EnableMergeJoins {
-// 			// if orderable?
-// 			// if n.right.SupportsMarkRestore()
-
-// 			// TODO - HACK!
-// 			var lefts, rights []impls.ExpressionWithDirection
-// 			for _, p := range pairs {
-// 				lefts = append(lefts, impls.ExpressionWithDirection{Expression: p.left})
-// 				rights = append(rights, impls.ExpressionWithDirection{Expression: p.right})
-// 			}
-// 			n.left = order.NewOrder(n.left, expressions.NewOrderExpression(lefts))
-// 			n.left.Optimize(ctx)
-// 			n.right = order.NewOrder(n.right, expressions.NewOrderExpression(rights))
-// 			n.right.Optimize(ctx)
-
-// 			return &mergeJoinStrategy{
-// 				n:     n,
-// 				pairs: pairs,
-// 			}
-// 		}
-
-// 		if EnableHashJoins {
-// 			return &hashJoinStrategy{
-// 				n:     n,
-// 				pairs: pairs,
-// 			}
-// 		}
-// 	}
-
-// 	// if n.filter != nil {
-// 	// filter.LowerFilter(n.filter, n.left.Fields(), n.right)
-// 	// n.right.AddFilter(n.filter)
-// 	// n.right.Optimize(ctx)
-// 	// }
-
-// 	n.left.Optimize(ctx)
-// 	n.right.Optimize(ctx)
-
-// 	return &nestedLoopJoinStrategy{n: n}
-// }
-
-func decomposeFilter(n *joinNodeInternal) (pairs []join.EqualityPair, _ bool) {
-	if n.operator.Condition == nil {
-		return nil, false
-	}
-
+// decomposeFilter partitions the conjunctions of the join condition that reference both
+// relations into equality pairs, which hash and merge joins evaluate by comparing join
+// keys, and the remaining conjunctions. Conjunctions referencing only one relation are
+// lowered into that relation and are omitted.
+func decomposeFilter(n *joinNodeInternal) (pairs []join.EqualityPair, pairExpressions, rest []impls.Expression) {
 	for _, expr := range expressions.Conjunctions(n.operator.Condition) {
-		if comparisonType, left, right := expressions.IsComparison(expr); comparisonType == expressions.ComparisonTypeEquals {
-			if bindsAllFields(n.left, left) && bindsAllFields(n.right, right) {
-				pairs = append(pairs, join.EqualityPair{Left: left, Right: right})
-				continue
-			}
+		referencesLeft := referencesAnyField(n.left, expr)
+		referencesRight := referencesAnyField(n.right, expr)
+		if referencesLeft != referencesRight {
+			continue
+		}
 
-			if bindsAllFields(n.left, right) && bindsAllFields(n.right, left) {
-				pairs = append(pairs, join.EqualityPair{Left: right, Right: left})
-				continue
+		if referencesLeft && referencesRight {
+			if left, right, nullAware, ok := equalityOperands(expr); ok {
+				if bindsRelation(n.left, left) && bindsRelation(n.right, right) {
+					pairs = append(pairs, join.EqualityPair{Left: left, Right: right, NullAware: nullAware})
+					pairExpressions = append(pairExpressions, expr)
+					continue
+				}
+
+				if bindsRelation(n.left, right) && bindsRelation(n.right, left) {
+					pairs = append(pairs, join.EqualityPair{Left: right, Right: left, NullAware: nullAware})
+					pairExpressions = append(pairExpressions, expr)
+					continue
+				}
 			}
 		}
 
-		return nil, false
+		rest = append(rest, expr)
+	}
+
+	return pairs, pairExpressions, rest
+}
+
+// equalityOperands returns the operands of the given conjunction if it is an equality
+// comparison or a null-aware equality (see expressions.IsNullAwareEquality).
+func equalityOperands(expr impls.Expression) (left, right impls.Expression, nullAware, ok bool) {
+	if comparisonType, left, right := expressions.IsComparison(expr); comparisonType == expressions.ComparisonTypeEquals {
+		return left, right, false, true
+	}
+
+	if left, right, ok := expressions.IsNullAwareEquality(expr); ok {
+		return left, right, true, true
 	}
 
-	return pairs, len(pairs) > 0
+	return nil, nil, false, false
+}
+
+// bindsRelation returns true if the given expression references only (and at least one)
+// field of the given relation.
+func bindsRelation(n JoinNode, expr impls.Expression) bool {
+	return len(expressions.Fields(expr)) > 0 && bindsAllFields(n, expr)
 }
 
 func bindsAllFields(n JoinNode, expr impls.Expression) bool {
@@ -388,3 +783,13 @@ func bindsAllFields(n JoinNode, expr impls.Expression) bool {
 
 	return true
 }
+
+func referencesAnyField(n JoinNode, expr impls.Expression) bool {
+	for _, field := range expressions.Fields(expr) {
+		if _, err := fields.FindMatchingFieldIndex(field, n.Fields()); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
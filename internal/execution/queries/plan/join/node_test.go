@@ -3,8 +3,14 @@ package join
 import (
 	"testing"
 
+	"github.com/efritz/gostgres/internal/execution/expressions"
 	"github.com/efritz/gostgres/internal/execution/queries/nodes/join"
+	"github.com/efritz/gostgres/internal/execution/queries/plan"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConvertRightJoinsToLeftJoins(t *testing.T) {
@@ -65,3 +71,200 @@ func TestConvertRightJoinsToLeftJoins(t *testing.T) {
 		})
 	}
 }
+
+func TestReduceOuterJoins(t *testing.T) {
+	a := NewJoinLeafNode(newMockLogicalNode("a"))
+	b := NewJoinLeafNode(newMockLogicalNode("b"))
+	c := NewJoinLeafNode(newMockLogicalNode("c"))
+
+	foo := func(relationName string) impls.Expression {
+		return expressions.NewNamed(fields.NewField(relationName, "foo", types.TypeBigInteger, fields.NonInternalField))
+	}
+	equals := func(left, right string) impls.Expression {
+		return expressions.NewEquals(foo(left), foo(right))
+	}
+	isNull := func(relationName string) impls.Expression {
+		return expressions.NewIsNull(foo(relationName))
+	}
+	makeJoin := func(left, right JoinNode, joinType join.JoinType, condition impls.Expression) JoinNode {
+		return NewJoinInternalNode(left, right, JoinOperator{JoinType: joinType, Condition: condition})
+	}
+
+	testCases := []struct {
+		name     string
+		input    JoinNode
+		filter   impls.Expression
+		expected JoinNode
+	}{
+		{
+			name:     "left join with null-rejecting filter",
+			input:    makeJoin(a, b, join.JoinTypeLeftOuter, equals("a", "b")),
+			filter:   equals("b", "b"),
+			expected: makeJoin(a, b, join.JoinTypeInner, equals("a", "b")),
+		},
+		{
+			name:     "left join with filter accepting nulls",
+			input:    makeJoin(a, b, join.JoinTypeLeftOuter, equals("a", "b")),
+			filter:   isNull("b"),
+			expected: makeJoin(a, b, join.JoinTypeLeftOuter, equals("a", "b")),
+		},
+		{
+			name:     "left join with filter on preserved relation",
+			input:    makeJoin(a, b, join.JoinTypeLeftOuter, equals("a", "b")),
+			filter:   equals("a", "a"),
+			expected: makeJoin(a, b, join.JoinTypeLeftOuter, equals("a", "b")),
+		},
+		{
+			name:     "full join rejecting nulls on left",
+			input:    makeJoin(a, b, join.JoinTypeFullOuter, equals("a", "b")),
+			filter:   equals("a", "a"),
+			expected: makeJoin(a, b, join.JoinTypeLeftOuter, equals("a", "b")),
+		},
+		{
+			name:     "full join rejecting nulls on right",
+			input:    makeJoin(a, b, join.JoinTypeFullOuter, equals("a", "b")),
+			filter:   equals("b", "b"),
+			expected: makeJoin(b, a, join.JoinTypeLeftOuter, equals("a", "b")),
+		},
+		{
+			name:     "full join rejecting nulls on both sides",
+			input:    makeJoin(a, b, join.JoinTypeFullOuter, equals("a", "b")),
+			filter:   expressions.NewAnd(equals("a", "a"), equals("b", "b")),
+			expected: makeJoin(a, b, join.JoinTypeInner, equals("a", "b")),
+		},
+		{
+			name:     "inner join condition above left join",
+			input:    makeJoin(makeJoin(a, b, join.JoinTypeLeftOuter, equals("a", "b")), c, join.JoinTypeInner, equals("b", "c")),
+			expected: makeJoin(makeJoin(a, b, join.JoinTypeInner, equals("a", "b")), c, join.JoinTypeInner, equals("b", "c")),
+		},
+		{
+			name:     "left join condition above left join",
+			input:    makeJoin(a, makeJoin(b, c, join.JoinTypeLeftOuter, equals("b", "c")), join.JoinTypeLeftOuter, equals("a", "c")),
+			expected: makeJoin(a, makeJoin(b, c, join.JoinTypeInner, equals("b", "c")), join.JoinTypeLeftOuter, equals("a", "c")),
+		},
+		{
+			name:     "full join condition above left join",
+			input:    makeJoin(a, makeJoin(b, c, join.JoinTypeLeftOuter, equals("b", "c")), join.JoinTypeFullOuter, equals("a", "c")),
+			expected: makeJoin(a, makeJoin(b, c, join.JoinTypeLeftOuter, equals("b", "c")), join.JoinTypeFullOuter, equals("a", "c")),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			reduceOuterJoins(testCase.input, testCase.filter)
+			assert.Equal(t, testCase.expected.String(), testCase.input.String())
+		})
+	}
+}
+
+func TestMergeJoinMaterializationCost(t *testing.T) {
+	foo := func(relationName string) impls.Expression {
+		return expressions.NewNamed(fields.NewField(relationName, "foo", types.TypeBigInteger, fields.NonInternalField))
+	}
+
+	mergeCost := func(markRestore bool) float64 {
+		left := newMockLogicalNode("a")
+		left.estimate = plan.Estimate{Rows: 100, Cost: 100}
+		right := newMockLogicalNode("b")
+		right.estimate = plan.Estimate{Rows: 50, Cost: 50}
+		right.markRestore = markRestore
+
+		n := NewJoinInternalNode(NewJoinLeafNode(left), NewJoinLeafNode(right), JoinOperator{
+			JoinType:  join.JoinTypeInner,
+			Condition: expressions.NewEquals(foo("a"), foo("b")),
+		}).(*joinNodeInternal)
+
+		for _, strategy := range n.candidateStrategies() {
+			if merge, ok := strategy.(*logicalMergeJoinStrategy); ok {
+				return merge.Estimate().Cost
+			}
+		}
+
+		require.Fail(t, "expected merge join strategy")
+		return 0
+	}
+
+	// A right relation that cannot return to a marked row is buffered by the join
+	assert.Equal(t, plan.EstimateMaterializeCost(50), mergeCost(false)-mergeCost(true))
+}
+
+func TestJoinOrderingWithRowGoal(t *testing.T) {
+	field := func(relationName, name string) impls.Expression {
+		return expressions.NewNamed(fields.NewField(relationName, name, types.TypeBigInteger, fields.NonInternalField))
+	}
+	order := expressions.NewOrderExpression([]impls.ExpressionWithDirection{{Expression: field("a", "id")}})
+
+	optimize := func(rowGoal float64) *joinNodeInternal {
+		// A small relation already ordered as requested, and a large relation
+		a := newMockLogicalNode("a")
+		a.estimate = plan.Estimate{Rows: 10, Cost: 10}
+		a.ordering = order
+		b := newMockLogicalNode("b")
+		b.estimate = plan.Estimate{Rows: 10000, Cost: 10000}
+
+		n := NewJoinInternalNode(NewJoinLeafNode(b), NewJoinLeafNode(a), JoinOperator{
+			JoinType:  join.JoinTypeInner,
+			Condition: expressions.NewEquals(field("a", "foo"), field("b", "foo")),
+		}).(*joinNodeInternal)
+
+		ctx := impls.OptimizationContext{}
+		n.AddOrder(ctx, order)
+		if rowGoal > 0 {
+			n.AddRowGoal(ctx, rowGoal)
+		}
+		n.Optimize(ctx)
+
+		return n
+	}
+
+	// Sorting the rows of the cheapest join is cheaper than preserving the order of a
+	// when every row is read
+	n := optimize(0)
+	assert.False(t, expressions.SubsumesOrder(order, n.Ordering()))
+
+	// Rows of a nested loop join driven by a are emitted in order without first
+	// reading every row of b
+	n = optimize(1)
+	assert.True(t, expressions.SubsumesOrder(order, n.Ordering()))
+	assert.IsType(t, &logicalNestedLoopJoinStrategy{}, n.strategy)
+}
+
+func TestJoinStrategySelection(t *testing.T) {
+	field := func(relationName, name string) impls.Expression {
+		return expressions.NewNamed(fields.NewField(relationName, name, types.TypeBigInteger, fields.NonInternalField))
+	}
+
+	relation := func(name string, rows float64) JoinNode {
+		r := newMockLogicalNode(name)
+		r.estimate = plan.Estimate{Rows: rows, Cost: rows}
+		return NewJoinLeafNode(r)
+	}
+
+	optimize := func(left, right JoinNode, condition impls.Expression) *joinNodeInternal {
+		n := NewJoinInternalNode(left, right, JoinOperator{
+			JoinType:  join.JoinTypeInner,
+			Condition: condition,
+		}).(*joinNodeInternal)
+		n.Optimize(impls.OptimizationContext{})
+
+		return n
+	}
+
+	t.Run("equality over large relations", func(t *testing.T) {
+		n := optimize(relation("a", 1000), relation("b", 5000), expressions.NewEquals(field("a", "foo"), field("b", "foo")))
+		assert.IsType(t, &logicalHashJoinStrategy{}, n.strategy)
+	})
+
+	t.Run("chained equalities over large relations", func(t *testing.T) {
+		ab := optimize(relation("a", 1000), relation("b", 5000), expressions.NewEquals(field("a", "foo"), field("b", "foo")))
+		n := optimize(ab, relation("c", 15000), expressions.NewEquals(field("b", "bar"), field("c", "bar")))
+		assert.IsType(t, &logicalHashJoinStrategy{}, n.strategy)
+		assert.IsType(t, &logicalHashJoinStrategy{}, ab.strategy)
+	})
+
+	t.Run("inequality", func(t *testing.T) {
+		// Only a nested loop join can evaluate a condition without equality pairs
+		n := optimize(relation("a", 1000), relation("b", 5000), expressions.NewLessThan(field("a", "foo"), field("b", "foo")))
+		assert.IsType(t, &logicalNestedLoopJoinStrategy{}, n.strategy)
+	})
+}
@@ -0,0 +1,588 @@
+package join
+
+import (
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/execution/queries/nodes/join"
+	"github.com/efritz/gostgres/internal/execution/queries/plan"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+// orderJoins replaces the join tree rooted at this node with the equivalent tree of
+// lowest estimated cost. Valid trees are those permitted by the conflict rules of the
+// joins in the original tree. Trees over up to the context's join search limit of
+// relations are searched exhaustively by dynamic programming over connected sets of
+// relations; larger trees are built greedily by repeatedly performing the cheapest
+// valid join. Both searches join relations by a cross product only if no join
+// condition connects them.
+//
+// The conditions of inner joins are pooled: each conjunction is evaluated by the lowest
+// inner join whose relations include all relations it references. The conditions of
+// other joins are evaluated by the join that originally declared them.
+//
+// When the rows of the join are requested in an order provided by one of its relations
+// (e.g., by an index scan), the exhaustive search also considers trees which preserve
+// that order by driving the join from that relation. When only some rows of the join
+// are expected to be read (e.g., due to a LIMIT clause), such a tree can be cheaper than
+// sorting the rows of the otherwise cheapest tree.
+func (n *joinNodeInternal) orderJoins(ctx impls.OptimizationContext) {
+	o := newJoinOrderer(ctx, n)
+
+	var best *joinPlan
+	if o.numRelations() <= ctx.JoinSearchLimit() {
+		best = o.searchExhaustively()
+	} else {
+		best = o.searchGreedily()
+		o.applyRowGoal(best)
+	}
+
+	if best == nil || !best.appliesAll() {
+		// Keep the original order
+		markOrdered(n)
+		return
+	}
+
+	root := best.node.(*joinNodeInternal)
+	n.left = root.left
+	n.right = root.right
+	n.operator = root.operator
+	n.ordered = true
+	n.preserveOrder = root.preserveOrder
+	n.rowFraction = root.rowFraction
+	n.estimate = nil
+}
+
+func markOrdered(node JoinNode) {
+	if n, ok := node.(*joinNodeInternal); ok {
+		n.ordered = true
+		markOrdered(n.left)
+		markOrdered(n.right)
+	}
+}
+
+//
+//
+
+type joinOrderer struct {
+	ctx           impls.OptimizationContext
+	descriptors   DescriptorSet
+	conflictRules ConflictRuleSet
+	conditions    []joinCondition
+	graph         *joinGraph
+
+	// order is the order requested of the rows of the join, and orderRelation is the
+	// relation binding every expression of that order (zero if there is none)
+	order         impls.OrderExpression
+	orderRelation uint
+
+	// rowGoal is the number of rows of the join expected to be read (zero if all), and
+	// rowFraction is the fraction of the rows of the join that this represents
+	rowGoal     float64
+	rowFraction float64
+}
+
+// joinCondition is a conjunction of the condition of an inner join.
+type joinCondition struct {
+	expression impls.Expression
+	relations  uint
+}
+
+func newJoinOrderer(ctx impls.OptimizationContext, n *joinNodeInternal) *joinOrderer {
+	var conjunctions []pooledConjunction
+	var leafFilters []leafFilter
+	tree := lowerInnerConditions(n, nil, &conjunctions, &leafFilters)
+
+	descriptors := NewDescriptorSet(tree)
+	builder := descriptors.(DescriptorSetBuilder)
+
+	// Filter each relation by the conditions referencing only that relation so that
+	// relation estimates reflect them
+	for _, f := range leafFilters {
+		f.leaf.AddFilter(ctx, f.expression)
+	}
+
+	var conditions []joinCondition
+	for _, conjunction := range conjunctions {
+		scope := relationMask(descriptors, conjunction.origin)
+
+		relations := builder.MaskReferencedTables(conjunction.expression)
+		if relations == 0 {
+			// References no relation (e.g., only outer fields); evaluate with the root
+			// of the join that declared it
+			relations = scope
+		}
+
+		conditions = append(conditions, joinCondition{
+			expression: conjunction.expression,
+			relations:  expandNullableRelations(descriptors, scope, relations),
+		})
+	}
+
+	// Each conjunction of an inner join condition connects the relations it references;
+	// other joins connect the relations their conditions reference on either side (or
+	// the whole side, if none)
+	var edges []uint
+	for _, condition := range conditions {
+		edges = append(edges, condition.relations)
+	}
+	descriptors.ForEachDescriptor(func(descriptor JoinDescriptor) {
+		if !isInner(descriptor.operator.JoinType) {
+			edges = append(edges, union(
+				coalesce(intersect(descriptor.referencedRelations, descriptor.originalLeftRelations), descriptor.originalLeftRelations),
+				coalesce(intersect(descriptor.referencedRelations, descriptor.originalRightRelations), descriptor.originalRightRelations),
+			))
+		}
+	})
+
+	return &joinOrderer{
+		ctx:           ctx,
+		descriptors:   descriptors,
+		conflictRules: NewConflictRuleSet(descriptors),
+		conditions:    conditions,
+		graph:         newJoinGraph(uint(descriptors.NumRelations()), edges),
+		order:         n.order,
+		orderRelation: orderRelation(descriptors, n.order),
+		rowGoal:       n.rowGoal,
+		rowFraction:   1,
+	}
+}
+
+// orderRelation returns the relation binding every expression of the given order, or
+// zero if there is no such relation.
+func orderRelation(descriptors DescriptorSet, order impls.OrderExpression) uint {
+	if order == nil {
+		return 0
+	}
+
+outer:
+	for i := 0; i < descriptors.NumRelations(); i++ {
+		leaf := NewJoinLeafNode(descriptors.Relation(bit(uint(i))))
+
+		for _, expression := range order.Expressions() {
+			if !bindsRelation(leaf, expression.Expression) {
+				continue outer
+			}
+		}
+
+		return bit(uint(i))
+	}
+
+	return 0
+}
+
+// expandNullableRelations returns the given relations, extended so that a conjunction
+// referencing them is evaluated only after every non-inner join (declared within the
+// given scope) that may produce NULL values for the referenced relations.
+func expandNullableRelations(descriptors DescriptorSet, scope, relations uint) uint {
+	for changed := true; changed; {
+		changed = false
+
+		descriptors.ForEachDescriptor(func(descriptor JoinDescriptor) {
+			joined := union(descriptor.originalLeftRelations, descriptor.originalRightRelations)
+			if isInner(descriptor.operator.JoinType) || !isSubset(joined, scope) || isSubset(joined, relations) {
+				return
+			}
+
+			nullable := descriptor.originalRightRelations
+			if descriptor.operator.JoinType == join.JoinTypeFullOuter {
+				nullable = joined
+			}
+
+			if overlaps(relations, nullable) {
+				relations = union(relations, joined)
+				changed = true
+			}
+		})
+	}
+
+	return relations
+}
+
+// relationMask returns the mask of the relations of the given join tree.
+func relationMask(descriptors DescriptorSet, node JoinNode) uint {
+	switch n := node.(type) {
+	case *joinNodeLeaf:
+		for i := 0; i < descriptors.NumRelations(); i++ {
+			if descriptors.Relation(bit(uint(i))) == n.relation {
+				return bit(uint(i))
+			}
+		}
+
+	case *joinNodeInternal:
+		return union(relationMask(descriptors, n.left), relationMask(descriptors, n.right))
+	}
+
+	return 0
+}
+
+// pooledConjunction is a conjunction of the condition of an inner join, which may be
+// evaluated by any inner join within the subtree of the join that declared it.
+type pooledConjunction struct {
+	expression impls.Expression
+	origin     JoinNode
+}
+
+type leafFilter struct {
+	leaf       *joinNodeLeaf
+	expression impls.Expression
+}
+
+// lowerInnerConditions returns a copy of the given join tree in which each conjunction
+// of the condition of an inner join is moved to the lowest inner join whose subtree
+// binds all the fields it references. This limits the relations considered to be
+// referenced by each join to those required to evaluate it. Conjunctions binding a
+// single relation (reached only through inner joins) are omitted from the copy and
+// added to the given leaf filters. The conjunctions of every inner join are added to
+// the given pool.
+func lowerInnerConditions(node JoinNode, lowered []impls.Expression, pool *[]pooledConjunction, leafFilters *[]leafFilter) JoinNode {
+	switch n := node.(type) {
+	case *joinNodeLeaf:
+		for _, expression := range lowered {
+			*leafFilters = append(*leafFilters, leafFilter{leaf: n, expression: expression})
+		}
+
+		return n
+
+	case *joinNodeInternal:
+		if !isInner(n.operator.JoinType) {
+			return NewJoinInternalNode(
+				lowerInnerConditions(n.left, nil, pool, leafFilters),
+				lowerInnerConditions(n.right, nil, pool, leafFilters),
+				n.operator,
+			)
+		}
+
+		conjunctions := expressions.Conjunctions(n.operator.Condition)
+		for _, expression := range conjunctions {
+			*pool = append(*pool, pooledConjunction{expression: expression, origin: n})
+		}
+
+		var kept, left, right []impls.Expression
+		for _, expression := range append(conjunctions, lowered...) {
+			if canLowerInto(n.left, expression) {
+				left = append(left, expression)
+			} else if canLowerInto(n.right, expression) {
+				right = append(right, expression)
+			} else {
+				kept = append(kept, expression)
+			}
+		}
+
+		return NewJoinInternalNode(
+			lowerInnerConditions(n.left, left, pool, leafFilters),
+			lowerInnerConditions(n.right, right, pool, leafFilters),
+			JoinOperator{JoinType: n.operator.JoinType, Condition: expressions.UnionFilters(kept...)},
+		)
+	}
+
+	return node
+}
+
+// canLowerInto returns true if the given conjunction of an inner join condition can be
+// evaluated by the given child of the join without altering the result of the join.
+func canLowerInto(node JoinNode, expression impls.Expression) bool {
+	if n, ok := node.(*joinNodeInternal); ok && !isInner(n.operator.JoinType) {
+		return false
+	}
+
+	return bindsRelation(node, expression)
+}
+
+func isInner(joinType join.JoinType) bool {
+	return joinType == join.JoinTypeInner || joinType == join.JoinTypeCross
+}
+
+func (o *joinOrderer) numRelations() int {
+	return o.descriptors.NumRelations()
+}
+
+//
+//
+
+// joinPlan is the cheapest known join tree over a set of relations.
+type joinPlan struct {
+	relations uint
+	node      JoinNode
+	estimate  plan.Estimate
+	applied   []bool // indexed by join condition
+}
+
+func (p *joinPlan) appliesAll() bool {
+	for _, applied := range p.applied {
+		if !applied {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (o *joinOrderer) leafPlan(relation uint) *joinPlan {
+	node := NewJoinLeafNode(o.descriptors.Relation(relation))
+
+	return &joinPlan{
+		relations: relation,
+		node:      node,
+		estimate:  node.Estimate(),
+		applied:   make([]bool, len(o.conditions)),
+	}
+}
+
+// orderedLeafPlan returns the plan of the given relation if it provides the requested
+// order without sorting its rows, and nil otherwise. Reading a fraction of the rows of
+// the join reads about the same fraction of the rows of the relation driving it, which
+// may favor an ordered index scan over sorting the result of a cheaper scan.
+func (o *joinOrderer) orderedLeafPlan(relation uint) *joinPlan {
+	o.addLeafRowGoal(relation, o.rowFraction)
+
+	leaf := o.leafPlan(relation)
+	if plan.EstimateHypothetical(leaf.node, nil, o.order).Cost > leaf.estimate.Cost {
+		o.addLeafRowGoal(relation, 1)
+		return nil
+	}
+
+	return leaf
+}
+
+// addLeafRowGoal sets the number of rows expected to be read from the given relation to
+// the given fraction of its rows.
+func (o *joinOrderer) addLeafRowGoal(relation uint, fraction float64) {
+	setter, ok := o.descriptors.Relation(relation).(plan.RowGoalSetter)
+	if !ok {
+		return
+	}
+
+	if fraction >= 1 {
+		setter.AddRowGoal(o.ctx, 0)
+	} else {
+		setter.AddRowGoal(o.ctx, fraction*o.descriptors.Relation(relation).Estimate().Rows)
+	}
+}
+
+// searchExhaustively returns the cheapest valid join tree over all relations. Trees are
+// built by dynamic programming over the pairs of connected sets of relations that are
+// connected to one another in the query graph, so that relations are joined by a cross
+// product only if no join condition connects them.
+func (o *joinOrderer) searchExhaustively() *joinPlan {
+	n := uint(o.numRelations())
+
+	best := map[uint]*joinPlan{}
+	for i := uint(0); i < n; i++ {
+		best[bit(i)] = o.leafPlan(bit(i))
+	}
+
+	// Pairs are emitted only after every pair forming either of their sets
+	var pairs [][2]uint
+	exists := func(s uint) bool { _, ok := best[s]; return ok }
+	o.graph.enumeratePairs(exists, func(s1, s2 uint) {
+		pairs = append(pairs, [2]uint{s1, s2})
+
+		for _, sides := range [][2]uint{{s1, s2}, {s2, s1}} {
+			for _, candidate := range o.candidatePlans(best[sides[0]], best[sides[1]], false) {
+				if current, ok := best[candidate.relations]; !ok || candidate.estimate.Cost < current.estimate.Cost {
+					best[candidate.relations] = candidate
+				}
+			}
+		}
+	})
+
+	cheapest, ok := best[all(n)]
+	if !ok || o.orderRelation == 0 {
+		o.applyRowGoal(cheapest)
+		return cheapest
+	}
+	o.rowFraction = o.fractionOf(cheapest)
+
+	// Trees preserving the requested order join the ordered tree over a set of relations
+	// (on the left) with the cheapest tree over another set
+	ordered := map[uint]*joinPlan{}
+	if leaf := o.orderedLeafPlan(o.orderRelation); leaf != nil {
+		ordered[o.orderRelation] = leaf
+	}
+
+	for _, pair := range pairs {
+		for _, sides := range [][2]uint{{pair[0], pair[1]}, {pair[1], pair[0]}} {
+			left, ok1 := ordered[sides[0]]
+			right, ok2 := best[sides[1]]
+			if !ok1 || !ok2 {
+				continue
+			}
+
+			for _, candidate := range o.candidatePlans(left, right, true) {
+				if current, ok := ordered[candidate.relations]; !ok || candidate.estimate.FractionalCost(o.rowFraction) < current.estimate.FractionalCost(o.rowFraction) {
+					ordered[candidate.relations] = candidate
+				}
+			}
+		}
+	}
+
+	// Rows of the cheapest tree must all be sorted before the first is emitted
+	if candidate, ok := ordered[all(n)]; ok && candidate.appliesAll() {
+		if candidate.estimate.FractionalCost(o.rowFraction) < cheapest.estimate.Cost+plan.EstimateSortCost(cheapest.estimate.Rows) {
+			return candidate
+		}
+	}
+
+	o.addLeafRowGoal(o.orderRelation, 1)
+	o.rowFraction = 1
+	return cheapest
+}
+
+// fractionOf returns the fraction of the rows of the given plan expected to be read.
+func (o *joinOrderer) fractionOf(p *joinPlan) float64 {
+	if o.rowGoal == 0 || p.estimate.Rows <= o.rowGoal {
+		return 1
+	}
+
+	return o.rowGoal / p.estimate.Rows
+}
+
+// applyRowGoal chooses the strategy of the root of the given plan by the cost of the
+// rows expected to be read. Rows requested in an order the plan does not provide are
+// all read by the sort above it.
+func (o *joinOrderer) applyRowGoal(p *joinPlan) {
+	if p == nil || o.order != nil {
+		return
+	}
+
+	if root, ok := p.node.(*joinNodeInternal); ok {
+		root.rowFraction = o.fractionOf(p)
+		root.estimate = nil
+	}
+}
+
+// searchGreedily returns a valid join tree over all relations, built by repeatedly
+// joining the pair of connected subtrees with the cheapest valid join.
+func (o *joinOrderer) searchGreedily() *joinPlan {
+	var plans []*joinPlan
+	for i := 0; i < o.numRelations(); i++ {
+		plans = append(plans, o.leafPlan(bit(uint(i))))
+	}
+
+	for len(plans) > 1 {
+		var best *joinPlan
+		var bestLeft, bestRight int
+
+		for i, left := range plans {
+			for j, right := range plans {
+				if i == j || !o.graph.connected(left.relations, right.relations) {
+					continue
+				}
+
+				for _, candidate := range o.candidatePlans(left, right, false) {
+					if best == nil || candidate.estimate.Cost < best.estimate.Cost {
+						best, bestLeft, bestRight = candidate, i, j
+					}
+				}
+			}
+		}
+
+		if best == nil {
+			// No valid join between the remaining subtrees
+			return nil
+		}
+
+		plans[min(bestLeft, bestRight)] = best
+		plans = append(plans[:max(bestLeft, bestRight)], plans[max(bestLeft, bestRight)+1:]...)
+	}
+
+	return plans[0]
+}
+
+// candidatePlans returns the valid plans joining the given plans, with the left plan
+// as the left relation of the join. If preserveOrder is true, the returned plans emit
+// rows in the order of the left plan.
+func (o *joinOrderer) candidatePlans(left, right *joinPlan, preserveOrder bool) []*joinPlan {
+	var candidates []*joinPlan
+	for _, operator := range o.operators(left, right) {
+		if preserveOrder && operator.JoinType == join.JoinTypeFullOuter {
+			// Unmatched rows of the right relation are emitted last
+			continue
+		}
+
+		var applied []bool
+		if isInner(operator.JoinType) {
+			applied, operator.Condition = o.innerCondition(left, right)
+		} else {
+			applied = combineApplied(left.applied, right.applied)
+		}
+
+		node := &joinNodeInternal{
+			left:          left.node,
+			right:         right.node,
+			operator:      operator,
+			ordered:       true,
+			preserveOrder: preserveOrder,
+		}
+		if preserveOrder {
+			node.rowFraction = o.rowFraction
+		}
+
+		candidates = append(candidates, &joinPlan{
+			relations: union(left.relations, right.relations),
+			node:      node,
+			estimate:  node.Estimate(),
+			applied:   applied,
+		})
+	}
+
+	return candidates
+}
+
+// operators returns the join operators that can validly join the given plans. All inner
+// joins share an operator, whose condition is determined by the relations being joined.
+func (o *joinOrderer) operators(left, right *joinPlan) []JoinOperator {
+	var operators []JoinOperator
+	hasInner := false
+
+	o.descriptors.ForEachDescriptor(func(descriptor JoinDescriptor) {
+		if !o.applicable(descriptor, left.relations, right.relations) {
+			if !descriptor.IsCommutative() || !o.applicable(descriptor, right.relations, left.relations) {
+				return
+			}
+		}
+
+		if isInner(descriptor.operator.JoinType) {
+			if !hasInner {
+				hasInner = true
+				operators = append(operators, JoinOperator{JoinType: join.JoinTypeInner})
+			}
+
+			return
+		}
+
+		operators = append(operators, descriptor.operator)
+	})
+
+	return operators
+}
+
+func (o *joinOrderer) applicable(descriptor JoinDescriptor, s1, s2 uint) bool {
+	return descriptor.Applicable(s1, s2) && o.conflictRules.Applicable(descriptor, s1, s2)
+}
+
+// innerCondition returns the condition of an inner join of the given plans, which
+// includes every pooled conjunction that references only the joined relations and is
+// not already evaluated by either plan.
+func (o *joinOrderer) innerCondition(left, right *joinPlan) ([]bool, impls.Expression) {
+	relations := union(left.relations, right.relations)
+	applied := combineApplied(left.applied, right.applied)
+
+	var conjunctions []impls.Expression
+	for i, condition := range o.conditions {
+		if !applied[i] && isSubset(condition.relations, relations) {
+			conjunctions = append(conjunctions, condition.expression)
+			applied[i] = true
+		}
+	}
+
+	return applied, expressions.UnionFilters(conjunctions...)
+}
+
+func combineApplied(left, right []bool) []bool {
+	applied := make([]bool, len(left))
+	for i := range applied {
+		applied[i] = left[i] || right[i]
+	}
+
+	return applied
+}
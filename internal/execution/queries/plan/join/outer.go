@@ -0,0 +1,68 @@
+package join
+
+import (
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/execution/queries/nodes/join"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+// reduceOuterJoins replaces the outer joins of the given join tree with inner (or left)
+// joins where the given filter, which is applied to the rows of the tree, rejects every
+// row that the outer join extends with NULL values. Conditions of inner joins in the
+// tree are applied to the rows of their subtrees and reduce the joins below them.
+func reduceOuterJoins(node JoinNode, filter impls.Expression) {
+	n, ok := node.(*joinNodeInternal)
+	if !ok {
+		return
+	}
+
+	switch n.operator.JoinType {
+	case join.JoinTypeLeftOuter:
+		if rejectsNulls(filter, n.right) {
+			n.operator.JoinType = join.JoinTypeInner
+		}
+
+	case join.JoinTypeFullOuter:
+		leftRejected := rejectsNulls(filter, n.left)
+		rightRejected := rejectsNulls(filter, n.right)
+
+		switch {
+		case leftRejected && rightRejected:
+			n.operator.JoinType = join.JoinTypeInner
+
+		case leftRejected:
+			// Only rows of the left relation may be extended with NULL values
+			n.operator.JoinType = join.JoinTypeLeftOuter
+
+		case rightRejected:
+			// Only rows of the right relation may be extended with NULL values
+			n.left, n.right = n.right, n.left
+			n.operator.JoinType = join.JoinTypeLeftOuter
+		}
+	}
+
+	switch {
+	case isInner(n.operator.JoinType):
+		filter = expressions.UnionFilters(filter, n.operator.Condition)
+		reduceOuterJoins(n.left, filter)
+		reduceOuterJoins(n.right, filter)
+
+	case n.operator.JoinType == join.JoinTypeLeftOuter, isSemiOrAnti(n.operator.JoinType):
+		// Rows of the right relation are discarded (not NULL-extended) when they do not
+		// match the join condition
+		reduceOuterJoins(n.left, filter)
+		reduceOuterJoins(n.right, n.operator.Condition)
+
+	default:
+		reduceOuterJoins(n.left, nil)
+		reduceOuterJoins(n.right, nil)
+	}
+
+	n.estimate = nil
+}
+
+// rejectsNulls returns true if the given filter rejects rows in which every field of
+// the given relation is NULL.
+func rejectsNulls(filter impls.Expression, node JoinNode) bool {
+	return filter != nil && expressions.IsNullRejecting(filter, node.Fields())
+}
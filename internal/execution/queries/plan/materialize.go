@@ -0,0 +1,89 @@
+package plan
+
+import (
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+// MaterializedRelation is a relation whose rows are computed at most once for each
+// execution of a statement and shared by each of its scans. The relation is optimized
+// and built once, regardless of the number of scans.
+type MaterializedRelation struct {
+	name      string
+	node      LogicalNode
+	optimized bool
+	built     *nodes.MaterializedRelation
+}
+
+func NewMaterializedRelation(name string, node LogicalNode) *MaterializedRelation {
+	return &MaterializedRelation{
+		name: name,
+		node: node,
+	}
+}
+
+func (r *MaterializedRelation) optimize(ctx impls.OptimizationContext) {
+	if !r.optimized {
+		r.optimized = true
+		r.node.Optimize(ctx)
+	}
+}
+
+func (r *MaterializedRelation) build() *nodes.MaterializedRelation {
+	if r.built == nil {
+		r.built = nodes.NewMaterializedRelation(r.name, r.node.Build())
+	}
+
+	return r.built
+}
+
+//
+//
+
+type logicalMaterializedScanNode struct {
+	relation *MaterializedRelation
+}
+
+func NewMaterializedScan(relation *MaterializedRelation) LogicalNode {
+	return &logicalMaterializedScanNode{
+		relation: relation,
+	}
+}
+
+func (n *logicalMaterializedScanNode) Name() string {
+	return n.relation.name
+}
+
+func (n *logicalMaterializedScanNode) Fields() []fields.Field {
+	return n.relation.node.Fields()
+}
+
+func (n *logicalMaterializedScanNode) AddFilter(ctx impls.OptimizationContext, filter impls.Expression) {
+	// The rows of the relation are shared by each scan and are filtered above it
+}
+
+func (n *logicalMaterializedScanNode) AddOrder(ctx impls.OptimizationContext, order impls.OrderExpression) {
+	// The rows of the relation are shared by each scan and are ordered above it
+}
+
+func (n *logicalMaterializedScanNode) Filter() impls.Expression  { return nil }
+func (n *logicalMaterializedScanNode) SupportsMarkRestore() bool { return false }
+
+func (n *logicalMaterializedScanNode) Optimize(ctx impls.OptimizationContext) {
+	n.relation.optimize(ctx)
+}
+
+func (n *logicalMaterializedScanNode) Ordering() impls.OrderExpression {
+	return n.relation.node.Ordering()
+}
+
+func (n *logicalMaterializedScanNode) Estimate() Estimate {
+	// The cost of computing the relation is shared by its scans
+	rows := n.relation.node.Estimate().Rows
+	return Estimate{Rows: rows, Cost: rows * CPUOperatorCost}
+}
+
+func (n *logicalMaterializedScanNode) Build() nodes.Node {
+	return nodes.NewMaterializedScan(n.relation.build())
+}
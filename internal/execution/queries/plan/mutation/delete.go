@@ -34,6 +34,7 @@ func NewDelete(
 	}, nil
 }
 
+func (n *logicalDeleteNode) Command() string                                                     { return "DELETE" }
 func (n *logicalDeleteNode) Fields() []fields.Field                                              { return n.returning.Fields() }
 func (n *logicalDeleteNode) AddFilter(ctx impls.OptimizationContext, filter impls.Expression)    {}
 func (n *logicalDeleteNode) AddOrder(ctx impls.OptimizationContext, order impls.OrderExpression) {}
@@ -30,6 +30,7 @@ func NewInsert(
 	}, nil
 }
 
+func (n *logicalInsertNode) Command() string                                                     { return "INSERT" }
 func (n *logicalInsertNode) Fields() []fields.Field                                              { return n.returning.Fields() }
 func (n *logicalInsertNode) AddFilter(ctx impls.OptimizationContext, filter impls.Expression)    {}
 func (n *logicalInsertNode) AddOrder(ctx impls.OptimizationContext, order impls.OrderExpression) {}
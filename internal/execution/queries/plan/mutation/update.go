@@ -37,6 +37,7 @@ func NewUpdate(
 	}, nil
 }
 
+func (n *logicalUpdateNode) Command() string                                                     { return "UPDATE" }
 func (n *logicalUpdateNode) Fields() []fields.Field                                              { return n.returning.Fields() }
 func (n *logicalUpdateNode) AddFilter(ctx impls.OptimizationContext, filter impls.Expression)    {}
 func (n *logicalUpdateNode) AddOrder(ctx impls.OptimizationContext, order impls.OrderExpression) {}
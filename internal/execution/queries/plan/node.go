@@ -21,6 +21,10 @@ type LogicalNode interface {
 	// https://sourcegraph.com/github.com/postgres/postgres@06286709ee0637ec7376329a5aa026b7682dcfe2/-/blob/src/backend/executor/execAmi.c?L439:59-439:79
 	SupportsMarkRestore() bool
 
+	// Estimate returns the expected rows and cost of the node. Before optimization,
+	// the estimate reflects the plan that optimization is expected to select.
+	Estimate() Estimate
+
 	Build() nodes.Node
 }
 
@@ -29,6 +33,7 @@ type LogicalNode interface {
 
 type NodeQuery struct {
 	LogicalNode
+	node nodes.Node
 }
 
 func NewQuery(n LogicalNode) queries.Query {
@@ -37,7 +42,23 @@ func NewQuery(n LogicalNode) queries.Query {
 	}
 }
 
+// Command returns INSERT, UPDATE, or DELETE for queries that mutate a table (including
+// those with a WITH clause), EXPLAIN for explained queries, and SELECT otherwise.
+func (q *NodeQuery) Command() string {
+	if n, ok := q.LogicalNode.(interface{ Command() string }); ok {
+		return n.Command()
+	}
+
+	return "SELECT"
+}
+
 func (q *NodeQuery) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
-	q.LogicalNode.Optimize(ctx.OptimizationContext())
-	nodes.NewQuery(q.LogicalNode.Build()).Execute(ctx, w)
+	if q.node == nil {
+		// Plan once; subsequent executions (e.g., of a prepared statement)
+		// re-use the same physical plan with different parameter values.
+		q.LogicalNode.Optimize(ctx.OptimizationContext())
+		q.node = q.LogicalNode.Build()
+	}
+
+	nodes.NewQuery(q.node).Execute(ctx, w)
 }
@@ -35,6 +35,12 @@ func (n *logicalProjectionNode) AddOrder(ctx impls.OptimizationContext, order im
 	n.LogicalNode.AddOrder(ctx, mapped)
 }
 
+func (n *logicalProjectionNode) AddRowGoal(ctx impls.OptimizationContext, rows float64) {
+	if setter, ok := n.LogicalNode.(RowGoalSetter); ok {
+		setter.AddRowGoal(ctx, rows)
+	}
+}
+
 func (n *logicalProjectionNode) Optimize(ctx impls.OptimizationContext) {
 	n.projection.Optimize(ctx)
 	n.LogicalNode.Optimize(ctx)
@@ -58,7 +64,21 @@ func (n *logicalProjectionNode) Ordering() impls.OrderExpression {
 }
 
 func (n *logicalProjectionNode) SupportsMarkRestore() bool {
-	return false
+	return n.LogicalNode.SupportsMarkRestore()
+}
+
+func (n *logicalProjectionNode) EstimateHypothetical(filter impls.Expression, order impls.OrderExpression) (Estimate, bool) {
+	if filter != nil {
+		filter = n.projection.DeprojectExpression(filter)
+	}
+
+	if order != nil {
+		order, _ = order.Map(func(expression impls.Expression) (impls.Expression, error) {
+			return n.projection.DeprojectExpression(expression), nil
+		})
+	}
+
+	return EstimateHypothetical(n.LogicalNode, filter, order), true
 }
 
 func (n *logicalProjectionNode) Build() nodes.Node {
@@ -12,29 +12,41 @@ type logicalSelectNode struct {
 	LogicalNode
 	projection       *projection.Projection
 	groupExpressions []impls.Expression
+	having           impls.Expression
 	filter           impls.Expression
 	order            impls.OrderExpression
 	limit            *int
 	offset           *int
+	locking          *RowLocking
+}
+
+// RowLocking describes the rows locked by a SELECT ... FOR UPDATE/SHARE query.
+type RowLocking struct {
+	Mode      impls.LockMode
+	Relations []nodes.LockedRelation
 }
 
 func NewSelect(
 	node LogicalNode,
 	projection *projection.Projection,
 	groupExpressions []impls.Expression,
+	having impls.Expression,
 	filter impls.Expression,
 	order impls.OrderExpression,
 	limit *int,
 	offset *int,
+	locking *RowLocking,
 ) LogicalNode {
 	return &logicalSelectNode{
 		LogicalNode:      node,
 		projection:       projection,
 		groupExpressions: groupExpressions,
+		having:           having,
 		filter:           filter,
 		order:            order,
 		limit:            limit,
 		offset:           offset,
+		locking:          locking,
 	}
 }
 
@@ -120,11 +132,26 @@ func (n *logicalSelectNode) Optimize(ctx impls.OptimizationContext) {
 		n.LogicalNode.AddFilter(ctx, n.filter)
 	}
 
+	if n.having != nil {
+		n.having = n.having.Fold()
+	}
+
 	if n.order != nil {
 		n.order = n.order.Fold()
 		n.LogicalNode.AddOrder(ctx, n.order)
 	}
 
+	if n.limit != nil && len(n.groupExpressions) == 0 {
+		if setter, ok := n.LogicalNode.(RowGoalSetter); ok {
+			rows := float64(*n.limit)
+			if n.offset != nil {
+				rows += float64(*n.offset)
+			}
+
+			setter.AddRowGoal(ctx, rows)
+		}
+	}
+
 	n.LogicalNode.Optimize(ctx)
 
 	n.filter = expressions.FilterDifference(n.filter, n.LogicalNode.Filter())
@@ -163,7 +190,80 @@ func (n *logicalSelectNode) Ordering() impls.OrderExpression {
 }
 
 func (n *logicalSelectNode) SupportsMarkRestore() bool {
-	return false // TODO
+	if n.offset != nil || n.limit != nil || n.locking != nil {
+		return false
+	}
+
+	if n.order != nil {
+		// Sorted rows are materialized
+		return true
+	}
+
+	if len(n.groupExpressions) > 0 {
+		return false
+	}
+
+	return n.LogicalNode.SupportsMarkRestore()
+}
+
+func (n *logicalSelectNode) Estimate() Estimate {
+	estimate := n.LogicalNode.Estimate()
+
+	if n.filter != nil {
+		estimate.Cost += EstimateFilterCost(estimate.Rows, n.filter)
+		estimate.Rows = ClampRows(estimate.Rows * EstimateSelectivity(n.filter))
+	}
+
+	if len(n.groupExpressions) > 0 {
+		estimate.Cost += estimate.Rows * CPUOperatorCost * float64(len(n.groupExpressions))
+		estimate.Rows = ClampRows(estimate.Rows * defaultGroupSelectivity)
+
+		if n.having != nil {
+			estimate.Cost += EstimateFilterCost(estimate.Rows, n.having)
+			estimate.Rows = ClampRows(estimate.Rows * EstimateSelectivity(n.having))
+		}
+
+		// Groups are emitted once every row has been read
+		estimate.StartupCost = estimate.Cost
+	}
+
+	if n.order != nil {
+		estimate.Cost += EstimateSortCost(estimate.Rows)
+		estimate.StartupCost = estimate.Cost
+	}
+
+	if n.offset != nil {
+		estimate.Rows = ClampRows(estimate.Rows - float64(*n.offset))
+	}
+
+	if n.limit != nil {
+		estimate.Rows = min(estimate.Rows, float64(*n.limit))
+	}
+
+	return estimate
+}
+
+func (n *logicalSelectNode) EstimateHypothetical(filter impls.Expression, order impls.OrderExpression) (Estimate, bool) {
+	if n.limit != nil || n.offset != nil || len(n.groupExpressions) > 0 {
+		// Filters and orders are not pushed beyond these boundaries
+		return Estimate{}, false
+	}
+
+	if n.projection != nil {
+		filter = n.projection.DeprojectExpression(filter)
+
+		if order != nil {
+			order, _ = order.Map(func(expression impls.Expression) (impls.Expression, error) {
+				return n.projection.DeprojectExpression(expression), nil
+			})
+		}
+	}
+
+	if order == nil {
+		order = n.order
+	}
+
+	return EstimateHypothetical(n.LogicalNode, expressions.UnionFilters(n.filter, filter), order), true
 }
 
 func (n *logicalSelectNode) Build() nodes.Node {
@@ -174,7 +274,7 @@ func (n *logicalSelectNode) Build() nodes.Node {
 	}
 
 	if len(n.groupExpressions) > 0 {
-		node = nodes.NewGroup(node, n.groupExpressions, n.projection)
+		node = nodes.NewGroup(node, n.groupExpressions, n.having, n.projection)
 	}
 
 	if n.order != nil {
@@ -194,6 +294,10 @@ func (n *logicalSelectNode) Build() nodes.Node {
 		node = nodes.NewLimit(node, *n.limit)
 	}
 
+	if n.locking != nil {
+		node = nodes.NewLockRows(node, n.locking.Relations, n.locking.Mode)
+	}
+
 	if n.projection != nil && len(n.groupExpressions) == 0 {
 		node = nodes.NewProjection(node, n.projection)
 	}
@@ -0,0 +1,63 @@
+package plan
+
+import (
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type logicalTableFunctionNode struct {
+	name     string
+	fields   []fields.Field
+	function impls.TableFunction
+	args     []impls.Expression
+}
+
+// NewTableFunction creates a node emitting the rows returned by the given table function
+// invoked with the values of the given expressions.
+func NewTableFunction(name string, fields []fields.Field, function impls.TableFunction, args []impls.Expression) LogicalNode {
+	return &logicalTableFunctionNode{
+		name:     name,
+		fields:   fields,
+		function: function,
+		args:     args,
+	}
+}
+
+func (n *logicalTableFunctionNode) Name() string {
+	return n.name
+}
+
+func (n *logicalTableFunctionNode) Fields() []fields.Field {
+	return n.fields
+}
+
+func (n *logicalTableFunctionNode) AddFilter(ctx impls.OptimizationContext, filter impls.Expression) {
+	// The rows of the function are filtered above the scan
+}
+
+func (n *logicalTableFunctionNode) AddOrder(ctx impls.OptimizationContext, order impls.OrderExpression) {
+	// The rows of the function are ordered above the scan
+}
+
+func (n *logicalTableFunctionNode) Optimize(ctx impls.OptimizationContext) {
+	for i, arg := range n.args {
+		n.args[i] = arg.Fold()
+	}
+}
+
+func (n *logicalTableFunctionNode) Filter() impls.Expression        { return nil }
+func (n *logicalTableFunctionNode) Ordering() impls.OrderExpression { return nil }
+func (n *logicalTableFunctionNode) SupportsMarkRestore() bool       { return false }
+
+// tableFunctionRows is the expected number of rows returned by a table function, whose
+// result cannot be estimated before it is invoked.
+const tableFunctionRows = 10
+
+func (n *logicalTableFunctionNode) Estimate() Estimate {
+	return Estimate{Rows: tableFunctionRows, Cost: tableFunctionRows * CPUOperatorCost * float64(len(n.fields))}
+}
+
+func (n *logicalTableFunctionNode) Build() nodes.Node {
+	return nodes.NewTableFunction(n.name, n.fields, n.function, n.args)
+}
@@ -33,6 +33,11 @@ func (n *logicalValuesNode) Filter() impls.Expression
 func (n *logicalValuesNode) Ordering() impls.OrderExpression                                     { return nil }
 func (n *logicalValuesNode) SupportsMarkRestore() bool                                           { return false }
 
+func (n *logicalValuesNode) Estimate() Estimate {
+	rows := float64(len(n.expressions))
+	return Estimate{Rows: rows, Cost: rows * CPUOperatorCost * float64(len(n.fields))}
+}
+
 func (n *logicalValuesNode) Build() nodes.Node {
 	return nodes.NewValues(n.fields, n.expressions)
 }
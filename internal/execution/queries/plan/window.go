@@ -0,0 +1,82 @@
+package plan
+
+import (
+	"slices"
+
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type logicalWindowNode struct {
+	LogicalNode
+	functions []nodes.WindowFunction
+}
+
+func NewWindow(node LogicalNode, functions []nodes.WindowFunction) LogicalNode {
+	return &logicalWindowNode{
+		LogicalNode: node,
+		functions:   functions,
+	}
+}
+
+func (n *logicalWindowNode) Fields() []fields.Field {
+	fields := slices.Clone(n.LogicalNode.Fields())
+	for _, function := range n.functions {
+		fields = append(fields, function.Field)
+	}
+
+	return fields
+}
+
+func (n *logicalWindowNode) AddFilter(ctx impls.OptimizationContext, filter impls.Expression) {
+	// Filtering the input would change the values of the window functions
+}
+
+func (n *logicalWindowNode) AddOrder(ctx impls.OptimizationContext, order impls.OrderExpression) {
+	for _, expr := range order.Expressions() {
+		for _, field := range expressions.Fields(expr.Expression) {
+			if slices.ContainsFunc(n.functions, func(function nodes.WindowFunction) bool { return function.Field == field }) {
+				return // window function values are not available below this node
+			}
+		}
+	}
+
+	// Rows are emitted in the order of the input
+	n.LogicalNode.AddOrder(ctx, order)
+}
+
+func (n *logicalWindowNode) Optimize(ctx impls.OptimizationContext) {
+	for i, function := range n.functions {
+		n.functions[i].Expression = function.Expression.Fold()
+	}
+
+	n.LogicalNode.Optimize(ctx)
+}
+
+func (n *logicalWindowNode) Estimate() Estimate {
+	estimate := n.LogicalNode.Estimate()
+
+	var windows []impls.Window
+	for _, function := range n.functions {
+		_, _, window, _ := expressions.UnwrapWindowFunction(function.Expression)
+		if !slices.ContainsFunc(windows, func(w impls.Window) bool { return expressions.WindowsEqual(w, window) }) {
+			windows = append(windows, window)
+		}
+	}
+
+	estimate.Cost += EstimateSortCost(estimate.Rows) * float64(len(windows))
+	estimate.Cost += estimate.Rows * CPUOperatorCost * float64(len(n.functions))
+	estimate.StartupCost = estimate.Cost
+	return estimate
+}
+
+func (n *logicalWindowNode) SupportsMarkRestore() bool {
+	// Window function values are materialized
+	return true
+}
+
+func (n *logicalWindowNode) Build() nodes.Node {
+	return nodes.NewWindow(n.LogicalNode.Build(), n.LogicalNode.Fields(), n.functions)
+}
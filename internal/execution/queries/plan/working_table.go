@@ -0,0 +1,56 @@
+package plan
+
+import (
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type logicalWorkingTableScanNode struct {
+	name   string
+	fields []fields.Field
+	table  *nodes.WorkingTable
+}
+
+// NewWorkingTableScan creates a node reading the rows of the given working table, which
+// are emitted by the previous iteration of a recursive query with the given fields.
+func NewWorkingTableScan(name string, fields []fields.Field, table *nodes.WorkingTable) LogicalNode {
+	return &logicalWorkingTableScanNode{
+		name:   name,
+		fields: fields,
+		table:  table,
+	}
+}
+
+func (n *logicalWorkingTableScanNode) Name() string {
+	return n.name
+}
+
+func (n *logicalWorkingTableScanNode) Fields() []fields.Field {
+	return n.fields
+}
+
+func (n *logicalWorkingTableScanNode) AddFilter(ctx impls.OptimizationContext, filter impls.Expression) {
+	// The rows of the working table are filtered above the scan
+}
+
+func (n *logicalWorkingTableScanNode) AddOrder(ctx impls.OptimizationContext, order impls.OrderExpression) {
+	// The rows of the working table are ordered above the scan
+}
+
+func (n *logicalWorkingTableScanNode) Optimize(ctx impls.OptimizationContext) {}
+func (n *logicalWorkingTableScanNode) Filter() impls.Expression               { return nil }
+func (n *logicalWorkingTableScanNode) Ordering() impls.OrderExpression        { return nil }
+func (n *logicalWorkingTableScanNode) SupportsMarkRestore() bool              { return false }
+
+// workingTableRows is the expected number of rows emitted by an iteration of a recursive
+// query, which cannot be estimated from its base term.
+const workingTableRows = 10
+
+func (n *logicalWorkingTableScanNode) Estimate() Estimate {
+	return Estimate{Rows: workingTableRows, Cost: workingTableRows * CPUOperatorCost}
+}
+
+func (n *logicalWorkingTableScanNode) Build() nodes.Node {
+	return nodes.NewWorkingTableScan(n.name, n.table)
+}
@@ -0,0 +1,49 @@
+package prepared
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type deallocate struct {
+	name string
+	all  bool
+}
+
+var _ queries.Query = &deallocate{}
+
+func NewDeallocate(name string) queries.Query {
+	return &deallocate{name: name}
+}
+
+func NewDeallocateAll() queries.Query {
+	return &deallocate{all: true}
+}
+
+func (q *deallocate) Command() string {
+	if q.all {
+		return "DEALLOCATE ALL"
+	}
+
+	return "DEALLOCATE"
+}
+
+func (q *deallocate) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	statements := ctx.PreparedStatements()
+	if statements == nil {
+		w.Error(fmt.Errorf("prepared statements are not supported outside of a session"))
+		return
+	}
+
+	if q.all {
+		statements.Clear()
+	} else if !statements.Delete(q.name) {
+		w.Error(fmt.Errorf("prepared statement %q does not exist", q.name))
+		return
+	}
+
+	w.Done()
+}
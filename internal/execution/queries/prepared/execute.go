@@ -0,0 +1,60 @@
+package prepared
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+)
+
+type execute struct {
+	name       string
+	parameters []impls.Expression
+}
+
+var _ queries.Query = &execute{}
+
+func NewExecute(name string, parameters []impls.Expression) queries.Query {
+	return &execute{
+		name:       name,
+		parameters: parameters,
+	}
+}
+
+func (q *execute) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	statement, ok := lookup(ctx, q.name)
+	if !ok {
+		w.Error(fmt.Errorf("prepared statement %q does not exist", q.name))
+		return
+	}
+
+	// String literals adopt the types of the parameters to which they are bound
+	for i, typ := range statement.ParameterTypes() {
+		if i < len(q.parameters) {
+			if err := expressions.InferType(q.parameters[i], typ); err != nil {
+				w.Error(err)
+				return
+			}
+		}
+	}
+
+	values, err := queries.EvaluateExpressions(ctx, q.parameters, rows.Row{})
+	if err != nil {
+		w.Error(err)
+		return
+	}
+
+	statement.Execute(ctx, w, values)
+}
+
+func lookup(ctx impls.ExecutionContext, name string) (impls.PreparedStatement, bool) {
+	statements := ctx.PreparedStatements()
+	if statements == nil {
+		return nil, false
+	}
+
+	return statements.Get(name)
+}
@@ -0,0 +1,43 @@
+package prepared
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type prepare struct {
+	name      string
+	statement impls.PreparedStatement
+}
+
+var _ queries.Query = &prepare{}
+
+func NewPrepare(name string, statement impls.PreparedStatement) queries.Query {
+	return &prepare{
+		name:      name,
+		statement: statement,
+	}
+}
+
+func (q *prepare) Command() string {
+	return "PREPARE"
+}
+
+func (q *prepare) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	statements := ctx.PreparedStatements()
+	if statements == nil {
+		w.Error(fmt.Errorf("prepared statements are not supported outside of a session"))
+		return
+	}
+
+	if _, ok := statements.Get(q.name); ok {
+		w.Error(fmt.Errorf("prepared statement %q already exists", q.name))
+		return
+	}
+
+	statements.Set(q.name, q.statement)
+	w.Done()
+}
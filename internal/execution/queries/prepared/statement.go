@@ -0,0 +1,121 @@
+package prepared
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type preparedStatement struct {
+	query          queries.Query
+	parameterTypes []types.Type
+	catalogVersion int64
+	plan           Planner
+}
+
+// Planner parses and plans the query of a prepared statement against the current
+// state of the catalog.
+type Planner func() (queries.Query, error)
+
+var _ impls.PreparedStatement = &preparedStatement{}
+
+// NewPreparedStatement creates a prepared statement from a query planned against the
+// given version of the catalog. If the catalog changes before the statement executes,
+// the query is planned again so that it does not refer to replaced tables or indexes.
+func NewPreparedStatement(query queries.Query, parameterTypes []types.Type, catalogVersion int64, plan Planner) impls.PreparedStatement {
+	return &preparedStatement{
+		query:          query,
+		parameterTypes: parameterTypes,
+		catalogVersion: catalogVersion,
+		plan:           plan,
+	}
+}
+
+func (s *preparedStatement) ParameterTypes() []types.Type {
+	return slices.Clone(s.parameterTypes)
+}
+
+func (s *preparedStatement) Fields() []fields.Field {
+	if describer, ok := s.query.(interface{ Fields() []fields.Field }); ok {
+		return describer.Fields()
+	}
+
+	return nil
+}
+
+func (s *preparedStatement) Command() string {
+	return queries.Command(s.query)
+}
+
+func (s *preparedStatement) AllowedInFailedTransaction() bool {
+	return queries.AllowedInFailedTransaction(s.query)
+}
+
+func (s *preparedStatement) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter, parameters []any) {
+	values, err := s.bind(parameters)
+	if err != nil {
+		w.Error(err)
+		return
+	}
+
+	if err := s.replan(ctx.Catalog()); err != nil {
+		w.Error(err)
+		return
+	}
+
+	// An EXECUTE statement cannot describe the statement it executes until it runs
+	protocol.Describe(w, s.Command(), s.Fields())
+	s.query.Execute(ctx.WithParameters(values), w)
+}
+
+// replan plans the statement's query again if the catalog changed since it was last
+// planned. Clients may have already been told the shape of the statement's results,
+// so the new plan must produce the same columns.
+func (s *preparedStatement) replan(catalog impls.CatalogSet) error {
+	version := catalog.Version()
+	if version == s.catalogVersion {
+		return nil
+	}
+
+	query, err := s.plan()
+	if err != nil {
+		return err
+	}
+
+	if !sameFieldTypes(s.Fields(), queries.Fields(query)) {
+		return fmt.Errorf("cached plan must not change result type")
+	}
+
+	s.query = query
+	s.catalogVersion = version
+	return nil
+}
+
+func sameFieldTypes(a, b []fields.Field) bool {
+	return slices.EqualFunc(a, b, func(a, b fields.Field) bool {
+		return a.Name() == b.Name() && a.Type() == b.Type()
+	})
+}
+
+func (s *preparedStatement) bind(parameters []any) ([]any, error) {
+	if len(parameters) != len(s.parameterTypes) {
+		return nil, fmt.Errorf("wrong number of parameters for prepared statement (expected %d, have %d)", len(s.parameterTypes), len(parameters))
+	}
+
+	values := make([]any, 0, len(parameters))
+	for i, value := range parameters {
+		_, refined, ok := s.parameterTypes[i].Refine(value)
+		if !ok {
+			return nil, fmt.Errorf("parameter $%d: cannot use %v as %s", i+1, value, s.parameterTypes[i])
+		}
+
+		values = append(values, refined)
+	}
+
+	return values, nil
+}
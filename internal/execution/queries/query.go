@@ -2,6 +2,7 @@ package queries
 
 import (
 	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/shared/fields"
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/shared/rows"
 )
@@ -10,6 +11,33 @@ type Query interface {
 	Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter)
 }
 
+// AllowedInFailedTransaction returns true if the given query may be executed within
+// a failed transaction block. Such queries end the transaction block or roll it back
+// to a savepoint; all other queries are rejected until then.
+func AllowedInFailedTransaction(query any) bool {
+	q, ok := query.(interface{ AllowedInFailedTransaction() bool })
+	return ok && q.AllowedInFailedTransaction()
+}
+
+// Command returns the name of the command executed by the given query (e.g., SELECT or
+// CREATE TABLE), which tags the completion of the statement.
+func Command(query any) string {
+	if q, ok := query.(interface{ Command() string }); ok {
+		return q.Command()
+	}
+
+	return ""
+}
+
+// Fields returns the fields of the rows returned by the given query.
+func Fields(query any) []fields.Field {
+	if q, ok := query.(interface{ Fields() []fields.Field }); ok {
+		return q.Fields()
+	}
+
+	return nil
+}
+
 func Evaluate(ctx impls.ExecutionContext, expr impls.Expression, row rows.Row) (any, error) {
 	return expr.ValueFrom(ctx, rows.CombineRows(row, ctx.OuterRow()))
 }
@@ -0,0 +1,95 @@
+package transaction
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type begin struct {
+	command string
+}
+
+var _ queries.Query = &begin{}
+
+func NewBegin() queries.Query {
+	return &begin{command: "BEGIN"}
+}
+
+func NewStartTransaction() queries.Query {
+	return &begin{command: "START TRANSACTION"}
+}
+
+func (q *begin) Command() string {
+	return q.command
+}
+
+func (q *begin) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	execute(ctx, w, func(transactions impls.TransactionManager) error {
+		return transactions.Begin()
+	})
+}
+
+type commit struct{}
+
+var _ queries.Query = &commit{}
+
+func NewCommit() queries.Query {
+	return &commit{}
+}
+
+func (q *commit) Command() string {
+	return "COMMIT"
+}
+
+func (q *commit) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	execute(ctx, w, func(transactions impls.TransactionManager) error {
+		return transactions.Commit()
+	})
+}
+
+func (q *commit) AllowedInFailedTransaction() bool {
+	return true
+}
+
+type rollback struct{}
+
+var _ queries.Query = &rollback{}
+
+func NewRollback() queries.Query {
+	return &rollback{}
+}
+
+func (q *rollback) Command() string {
+	return "ROLLBACK"
+}
+
+func (q *rollback) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	execute(ctx, w, func(transactions impls.TransactionManager) error {
+		return transactions.Rollback()
+	})
+}
+
+func (q *rollback) AllowedInFailedTransaction() bool {
+	return true
+}
+
+//
+//
+
+func execute(ctx impls.ExecutionContext, w protocol.ResponseWriter, f func(transactions impls.TransactionManager) error) {
+	transactions := ctx.Transactions()
+	if transactions == nil {
+		w.Error(fmt.Errorf("transactions are not supported outside of a session"))
+		return
+	}
+
+	if err := f(transactions); err != nil {
+		w.Error(err)
+		return
+	}
+
+	w.Done()
+}
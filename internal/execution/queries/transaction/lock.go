@@ -0,0 +1,40 @@
+package transaction
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type lock struct {
+	tableNames []string
+	mode       impls.LockMode
+}
+
+var _ queries.Query = &lock{}
+
+func NewLock(tableNames []string, mode impls.LockMode) queries.Query {
+	return &lock{
+		tableNames: tableNames,
+		mode:       mode,
+	}
+}
+
+func (q *lock) Command() string {
+	return "LOCK TABLE"
+}
+
+func (q *lock) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	for _, name := range q.tableNames {
+		if _, ok := ctx.Catalog().Tables.Get(name); !ok {
+			w.Error(fmt.Errorf("unknown table %q", name))
+			return
+		}
+	}
+
+	execute(ctx, w, func(transactions impls.TransactionManager) error {
+		return transactions.LockTables(q.tableNames, q.mode)
+	})
+}
@@ -0,0 +1,71 @@
+package transaction
+
+import (
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type savepoint struct {
+	name string
+}
+
+var _ queries.Query = &savepoint{}
+
+func NewSavepoint(name string) queries.Query {
+	return &savepoint{name: name}
+}
+
+func (q *savepoint) Command() string {
+	return "SAVEPOINT"
+}
+
+func (q *savepoint) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	execute(ctx, w, func(transactions impls.TransactionManager) error {
+		return transactions.Savepoint(q.name)
+	})
+}
+
+type rollbackToSavepoint struct {
+	name string
+}
+
+var _ queries.Query = &rollbackToSavepoint{}
+
+func NewRollbackToSavepoint(name string) queries.Query {
+	return &rollbackToSavepoint{name: name}
+}
+
+func (q *rollbackToSavepoint) Command() string {
+	return "ROLLBACK"
+}
+
+func (q *rollbackToSavepoint) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	execute(ctx, w, func(transactions impls.TransactionManager) error {
+		return transactions.RollbackToSavepoint(q.name)
+	})
+}
+
+func (q *rollbackToSavepoint) AllowedInFailedTransaction() bool {
+	return true
+}
+
+type releaseSavepoint struct {
+	name string
+}
+
+var _ queries.Query = &releaseSavepoint{}
+
+func NewReleaseSavepoint(name string) queries.Query {
+	return &releaseSavepoint{name: name}
+}
+
+func (q *releaseSavepoint) Command() string {
+	return "RELEASE"
+}
+
+func (q *releaseSavepoint) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	execute(ctx, w, func(transactions impls.TransactionManager) error {
+		return transactions.ReleaseSavepoint(q.name)
+	})
+}
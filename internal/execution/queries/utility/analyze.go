@@ -0,0 +1,69 @@
+package utility
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/catalog/table"
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type analyze struct {
+	tableName string
+}
+
+var _ queries.Query = &analyze{}
+
+// NewAnalyze creates a query that collects statistics of the table with the given name,
+// or of every table if the name is empty.
+func NewAnalyze(tableName string) queries.Query {
+	return &analyze{
+		tableName: tableName,
+	}
+}
+
+func (q *analyze) Command() string {
+	return "ANALYZE"
+}
+
+func (q *analyze) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	tables, err := resolveTables(ctx, q.tableName)
+	if err != nil {
+		w.Error(err)
+		return
+	}
+
+	for _, t := range tables {
+		statistics, err := table.Analyze(ctx, t)
+		if err != nil {
+			w.Error(err)
+			return
+		}
+
+		t.SetStatistics(ctx, statistics)
+	}
+
+	w.Done()
+}
+
+// resolveTables returns the table with the given name, or every table if the name is empty.
+func resolveTables(ctx impls.ExecutionContext, tableName string) ([]impls.Table, error) {
+	if tableName != "" {
+		t, ok := ctx.Catalog().Tables.Get(tableName)
+		if !ok {
+			return nil, fmt.Errorf("unknown table %q", tableName)
+		}
+
+		return []impls.Table{t}, nil
+	}
+
+	var tables []impls.Table
+	for _, name := range ctx.Catalog().Tables.Names() {
+		if t, ok := ctx.Catalog().Tables.Get(name); ok {
+			tables = append(tables, t)
+		}
+	}
+
+	return tables, nil
+}
@@ -0,0 +1,36 @@
+package utility
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type checkpoint struct{}
+
+var _ queries.Query = &checkpoint{}
+
+func NewCheckpoint() queries.Query {
+	return &checkpoint{}
+}
+
+func (q *checkpoint) Command() string {
+	return "CHECKPOINT"
+}
+
+func (q *checkpoint) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	checkpointer := ctx.Checkpointer()
+	if checkpointer == nil {
+		w.Error(fmt.Errorf("checkpoints are not supported outside of a session"))
+		return
+	}
+
+	if err := checkpointer.Checkpoint(); err != nil {
+		w.Error(err)
+		return
+	}
+
+	w.Done()
+}
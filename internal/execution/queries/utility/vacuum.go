@@ -0,0 +1,43 @@
+package utility
+
+import (
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+type vacuum struct {
+	tableName string
+}
+
+var _ queries.Query = &vacuum{}
+
+// NewVacuum creates a query that removes the row versions no longer visible to any
+// transaction from the table with the given name, or from every table if the name is
+// empty.
+func NewVacuum(tableName string) queries.Query {
+	return &vacuum{
+		tableName: tableName,
+	}
+}
+
+func (q *vacuum) Command() string {
+	return "VACUUM"
+}
+
+func (q *vacuum) Execute(ctx impls.ExecutionContext, w protocol.ResponseWriter) {
+	tables, err := resolveTables(ctx, q.tableName)
+	if err != nil {
+		w.Error(err)
+		return
+	}
+
+	for _, t := range tables {
+		if err := t.Vacuum(ctx); err != nil {
+			w.Error(err)
+			return
+		}
+	}
+
+	w.Done()
+}
@@ -0,0 +1,157 @@
+package transaction
+
+import (
+	"sync"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/storage/wal"
+)
+
+// Coordinator assigns identifiers to transactions and tracks which of them are
+// still in progress. It is shared by all sessions of an engine.
+//
+// Transaction identifiers increase monotonically. The identifier zero is reserved
+// for changes made outside of any transaction, which are visible to everyone.
+type Coordinator struct {
+	mu      sync.Mutex
+	barrier sync.RWMutex
+	nextID  int64
+	active  map[int64]int64 // active transactions and the xmin of their snapshots
+	locks   *lockManager
+	log     Log
+}
+
+// Log durably records the changes made by committed transactions.
+type Log interface {
+	Append(records []wal.Record) error
+}
+
+func NewCoordinator() *Coordinator {
+	return NewCoordinatorWithLog(nil)
+}
+
+// NewCoordinatorWithLog creates a coordinator that records the changes of each
+// transaction in the given log as it commits.
+func NewCoordinatorWithLog(log Log) *Coordinator {
+	return &Coordinator{
+		nextID: 1,
+		active: map[int64]int64{},
+		locks:  newLockManager(),
+		log:    log,
+	}
+}
+
+// begin assigns an identifier to a new transaction and takes a snapshot of the
+// transactions whose changes are visible to it.
+func (c *Coordinator) begin() (int64, snapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := c.nextID
+	c.nextID++
+
+	xmin := id
+	inProgress := make(map[int64]struct{}, len(c.active))
+	for activeID := range c.active {
+		inProgress[activeID] = struct{}{}
+		xmin = min(xmin, activeID)
+	}
+	c.active[id] = xmin
+
+	return id, snapshot{xmin: xmin, xmax: id, inProgress: inProgress}
+}
+
+// commit records the changes of the given committing transaction in the log and
+// marks it as no longer in progress. Changes are logged before the transaction ends
+// so that they are logged before the changes of any transaction that observed them.
+func (c *Coordinator) commit(id int64, changes []wal.Record) error {
+	// Logging and ending the transaction must appear atomic to consistent snapshots
+	c.barrier.RLock()
+	defer c.barrier.RUnlock()
+
+	if c.log != nil && len(changes) > 0 {
+		if err := c.log.Append(changes); err != nil {
+			return err
+		}
+	}
+
+	c.deactivate(id)
+	return nil
+}
+
+// end marks the given transaction as no longer in progress and releases its locks.
+// The changes of rolled back transactions are reverted before they end, so any change
+// made by an ended transaction that remains in the database is a committed change.
+func (c *Coordinator) end(id int64) {
+	c.deactivate(id)
+	c.locks.releaseAll(id)
+}
+
+func (c *Coordinator) deactivate(id int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.active, id)
+}
+
+// ReadConsistent invokes f with a read-only transaction that sees exactly the changes
+// of the transactions that have been committed to the log. The function at is invoked
+// when the transaction's snapshot is taken, while no transaction is committing, so that
+// it may observe state (e.g., the position of the log) consistent with the snapshot.
+func (c *Coordinator) ReadConsistent(at func(), f func(tx impls.Transaction) error) error {
+	c.barrier.Lock()
+	tx := newTransaction(c)
+	tx.commandID = 1
+	at()
+	c.barrier.Unlock()
+
+	defer c.end(tx.id)
+	return f(tx)
+}
+
+// horizon returns the identifier of the oldest transaction whose changes may not be
+// visible to some transaction in progress. Changes made by transactions with smaller
+// identifiers are visible to every transaction in progress and to those that begin
+// later.
+func (c *Coordinator) horizon() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	horizon := c.nextID
+	for _, xmin := range c.active {
+		horizon = min(horizon, xmin)
+	}
+
+	return horizon
+}
+
+func (c *Coordinator) isActive(id int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.active[id]
+	return ok
+}
+
+//
+//
+
+// snapshot determines which transactions' changes are visible to a transaction:
+// those that had committed before the snapshot was taken.
+type snapshot struct {
+	xmin       int64              // oldest transaction in progress (or xmax) when the snapshot was taken
+	xmax       int64              // first identifier not yet assigned when the snapshot was taken
+	inProgress map[int64]struct{} // transactions in progress when the snapshot was taken
+}
+
+func (s snapshot) includes(id int64) bool {
+	if id < s.xmin {
+		return true
+	}
+	if id >= s.xmax {
+		return false
+	}
+
+	_, ok := s.inProgress[id]
+	return !ok
+}
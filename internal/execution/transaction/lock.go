@@ -0,0 +1,182 @@
+package transaction
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+// lockTarget identifies a lockable object: a table, a single row of a table, or a
+// transaction. Each transaction holds an exclusive lock on itself until it ends so
+// that other transactions can wait for it to end by requesting a conflicting lock.
+type lockTarget struct {
+	table       string
+	tid         int64 // zero for table-level locks
+	transaction int64 // non-zero for transaction locks
+}
+
+// lockConflicts lists, for each lock mode, the modes that may not be held
+// concurrently by another transaction on the same target.
+var lockConflicts = map[impls.LockMode][]impls.LockMode{
+	impls.AccessShareLock:          {impls.AccessExclusiveLock},
+	impls.RowShareLock:             {impls.ExclusiveLock, impls.AccessExclusiveLock},
+	impls.RowExclusiveLock:         {impls.ShareLock, impls.ShareRowExclusiveLock, impls.ExclusiveLock, impls.AccessExclusiveLock},
+	impls.ShareUpdateExclusiveLock: {impls.ShareUpdateExclusiveLock, impls.ShareLock, impls.ShareRowExclusiveLock, impls.ExclusiveLock, impls.AccessExclusiveLock},
+	impls.ShareLock:                {impls.RowExclusiveLock, impls.ShareUpdateExclusiveLock, impls.ShareRowExclusiveLock, impls.ExclusiveLock, impls.AccessExclusiveLock},
+	impls.ShareRowExclusiveLock:    {impls.RowExclusiveLock, impls.ShareUpdateExclusiveLock, impls.ShareLock, impls.ShareRowExclusiveLock, impls.ExclusiveLock, impls.AccessExclusiveLock},
+	impls.ExclusiveLock:            {impls.RowShareLock, impls.RowExclusiveLock, impls.ShareUpdateExclusiveLock, impls.ShareLock, impls.ShareRowExclusiveLock, impls.ExclusiveLock, impls.AccessExclusiveLock},
+	impls.AccessExclusiveLock:      {impls.AccessShareLock, impls.RowShareLock, impls.RowExclusiveLock, impls.ShareUpdateExclusiveLock, impls.ShareLock, impls.ShareRowExclusiveLock, impls.ExclusiveLock, impls.AccessExclusiveLock},
+	impls.ForShareLock:             {impls.ForUpdateLock},
+	impls.ForUpdateLock:            {impls.ForShareLock, impls.ForUpdateLock},
+}
+
+func conflicts(a, b impls.LockMode) bool {
+	return slices.Contains(lockConflicts[a], b)
+}
+
+var errDeadlock = fmt.Errorf("deadlock detected")
+
+// lockManager grants locks to transactions. A transaction requesting a lock that
+// conflicts with a lock held by another transaction waits until the conflicting
+// lock is released. If waiting would complete a cycle of transactions waiting on
+// one another, the request fails instead.
+type lockManager struct {
+	mu       sync.Mutex
+	released *sync.Cond
+	holders  map[lockTarget]map[int64][]impls.LockMode // modes held on each target, by transaction
+	held     map[int64][]lockTarget                    // targets locked by each transaction
+	waitsFor map[int64]map[int64]struct{}              // transactions each waiting transaction is blocked by
+}
+
+func newLockManager() *lockManager {
+	m := &lockManager{
+		holders:  map[lockTarget]map[int64][]impls.LockMode{},
+		held:     map[int64][]lockTarget{},
+		waitsFor: map[int64]map[int64]struct{}{},
+	}
+	m.released = sync.NewCond(&m.mu)
+
+	return m
+}
+
+func (m *lockManager) acquire(id int64, target lockTarget, mode impls.LockMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for {
+		blockers := m.blockers(id, target, mode)
+		if len(blockers) == 0 {
+			break
+		}
+
+		m.waitsFor[id] = blockers
+		if m.waitsOn(blockers, id, map[int64]struct{}{}) {
+			delete(m.waitsFor, id)
+			return errDeadlock
+		}
+
+		m.released.Wait()
+	}
+	delete(m.waitsFor, id)
+
+	holders, ok := m.holders[target]
+	if !ok {
+		holders = map[int64][]impls.LockMode{}
+		m.holders[target] = holders
+	}
+
+	modes := holders[id]
+	if len(modes) == 0 {
+		m.held[id] = append(m.held[id], target)
+	}
+	if !slices.Contains(modes, mode) {
+		holders[id] = append(modes, mode)
+	}
+
+	return nil
+}
+
+// blockers returns the set of other transactions holding a lock on the given
+// target that conflicts with the given mode.
+func (m *lockManager) blockers(id int64, target lockTarget, mode impls.LockMode) map[int64]struct{} {
+	blockers := map[int64]struct{}{}
+	for holder, modes := range m.holders[target] {
+		if holder == id {
+			continue
+		}
+
+		if slices.ContainsFunc(modes, func(heldMode impls.LockMode) bool { return conflicts(mode, heldMode) }) {
+			blockers[holder] = struct{}{}
+		}
+	}
+
+	return blockers
+}
+
+// waitsOn returns true if any of the given transactions is (transitively)
+// waiting on the target transaction.
+func (m *lockManager) waitsOn(ids map[int64]struct{}, target int64, visited map[int64]struct{}) bool {
+	for id := range ids {
+		if id == target {
+			return true
+		}
+
+		if _, ok := visited[id]; ok {
+			continue
+		}
+		visited[id] = struct{}{}
+
+		if m.waitsOn(m.waitsFor[id], target, visited) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// release releases the locks held by the given transaction on the given target and
+// wakes any transactions waiting to acquire a lock.
+func (m *lockManager) release(id int64, target lockTarget) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.holders[target][id]; !ok {
+		return
+	}
+
+	delete(m.holders[target], id)
+	if len(m.holders[target]) == 0 {
+		delete(m.holders, target)
+	}
+
+	m.held[id] = slices.DeleteFunc(m.held[id], func(heldTarget lockTarget) bool { return heldTarget == target })
+	if len(m.held[id]) == 0 {
+		delete(m.held, id)
+	}
+
+	m.released.Broadcast()
+}
+
+// releaseAll releases every lock held by the given transaction and wakes any
+// transactions waiting to acquire a lock.
+func (m *lockManager) releaseAll(id int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	targets, ok := m.held[id]
+	if !ok {
+		return
+	}
+
+	for _, target := range targets {
+		delete(m.holders[target], id)
+		if len(m.holders[target]) == 0 {
+			delete(m.holders, target)
+		}
+	}
+
+	delete(m.held, id)
+	m.released.Broadcast()
+}
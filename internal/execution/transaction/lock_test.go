@@ -0,0 +1,79 @@
+package transaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockConflicts(t *testing.T) {
+	for _, testCase := range []struct {
+		a, b     impls.LockMode
+		conflict bool
+	}{
+		{impls.AccessShareLock, impls.AccessShareLock, false},
+		{impls.AccessShareLock, impls.ExclusiveLock, false},
+		{impls.AccessShareLock, impls.AccessExclusiveLock, true},
+		{impls.RowExclusiveLock, impls.RowExclusiveLock, false},
+		{impls.RowExclusiveLock, impls.ShareLock, true},
+		{impls.ShareLock, impls.ShareLock, false},
+		{impls.ShareRowExclusiveLock, impls.ShareRowExclusiveLock, true},
+		{impls.RowShareLock, impls.ExclusiveLock, true},
+		{impls.ForShareLock, impls.ForShareLock, false},
+		{impls.ForShareLock, impls.ForUpdateLock, true},
+		{impls.ForUpdateLock, impls.ForUpdateLock, true},
+	} {
+		t.Run(testCase.a.String()+" / "+testCase.b.String(), func(t *testing.T) {
+			assert.Equal(t, testCase.conflict, conflicts(testCase.a, testCase.b))
+			assert.Equal(t, testCase.conflict, conflicts(testCase.b, testCase.a))
+		})
+	}
+}
+
+func TestLockManager_Wait(t *testing.T) {
+	m := newLockManager()
+	target := lockTarget{table: "t"}
+
+	require.NoError(t, m.acquire(1, target, impls.AccessExclusiveLock))
+	require.NoError(t, m.acquire(1, target, impls.AccessShareLock)) // re-entrant
+
+	acquired := make(chan error)
+	go func() { acquired <- m.acquire(2, target, impls.AccessShareLock) }()
+
+	select {
+	case <-acquired:
+		t.Fatal("expected lock request to block")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	m.releaseAll(1)
+	require.NoError(t, <-acquired)
+}
+
+func TestLockManager_Deadlock(t *testing.T) {
+	m := newLockManager()
+	x := lockTarget{table: "t", tid: 1}
+	y := lockTarget{table: "t", tid: 2}
+
+	require.NoError(t, m.acquire(1, x, impls.ForUpdateLock))
+	require.NoError(t, m.acquire(2, y, impls.ForUpdateLock))
+
+	acquired := make(chan error)
+	go func() { acquired <- m.acquire(1, y, impls.ForUpdateLock) }()
+
+	// Wait for the first transaction to register as waiting
+	require.Eventually(t, func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		_, ok := m.waitsFor[1]
+		return ok
+	}, time.Second, time.Millisecond)
+
+	assert.ErrorIs(t, m.acquire(2, x, impls.ForUpdateLock), errDeadlock)
+
+	m.releaseAll(2)
+	require.NoError(t, <-acquired)
+}
@@ -0,0 +1,251 @@
+package transaction
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+// Status describes the transaction state of a session.
+type Status int
+
+const (
+	StatusIdle    Status = iota // not in a transaction block
+	StatusInBlock               // in a transaction block
+	StatusFailed                // in a failed transaction block
+)
+
+// Manager tracks the transaction of a single session. Statements executed outside
+// of a transaction block run in an implicit transaction that ends (committing or
+// rolling back) with the statement, or with the implicit block grouping several
+// statements. A transaction block is opened by BEGIN and ends with COMMIT or ROLLBACK.
+type Manager struct {
+	coordinator   *Coordinator
+	current       *Transaction
+	implicitBlock bool
+}
+
+var _ impls.TransactionManager = &Manager{}
+
+func NewManager(coordinator *Coordinator) *Manager {
+	return &Manager{
+		coordinator: coordinator,
+	}
+}
+
+func (m *Manager) Status() Status {
+	if m.current == nil || !m.current.explicit {
+		return StatusIdle
+	}
+
+	if m.current.failed {
+		return StatusFailed
+	}
+
+	return StatusInBlock
+}
+
+// StartStatement returns the transaction in which the next statement executes,
+// beginning an implicit transaction if no transaction block is open.
+func (m *Manager) StartStatement() *Transaction {
+	if m.current == nil {
+		m.current = newTransaction(m.coordinator)
+	}
+
+	m.current.commandID++
+	m.current.statementStart = len(m.current.undo)
+	return m.current
+}
+
+// EndStatement completes the execution of a statement that failed with the given
+// error (if non-nil). An implicit transaction is committed or rolled back along
+// with its statement, unless it is committed by the end of an implicit block. Within a
+// transaction block, the changes of a failed statement are reverted and the
+// transaction block is marked as failed. An error is returned if an implicit
+// transaction fails to commit.
+func (m *Manager) EndStatement(err error) error {
+	t := m.current
+	if t == nil {
+		// Statement ended the transaction block
+		return nil
+	}
+
+	if err != nil {
+		if !t.explicit {
+			t.rollbackTo(0)
+			m.end()
+			return nil
+		}
+
+		t.rollbackTo(t.statementStart)
+		t.failed = true
+		return nil
+	}
+
+	if !t.explicit && !m.implicitBlock {
+		return m.commit()
+	}
+
+	return nil
+}
+
+// BeginImplicitBlock groups the statements executed until EndImplicitBlock into a
+// single implicit transaction, such that a failing statement reverts the changes of
+// the statements before it. BEGIN turns the implicit block into a transaction block,
+// and COMMIT or ROLLBACK end the implicit transaction early.
+func (m *Manager) BeginImplicitBlock() {
+	m.implicitBlock = true
+}
+
+// EndImplicitBlock ends the implicit block, committing its implicit transaction (if
+// any) or rolling it back if the given error is non-nil. An error is returned if the
+// implicit transaction fails to commit.
+func (m *Manager) EndImplicitBlock(err error) error {
+	m.implicitBlock = false
+
+	if m.current == nil || m.current.explicit {
+		return nil
+	}
+
+	if err != nil {
+		m.current.rollbackTo(0)
+		m.end()
+		return nil
+	}
+
+	return m.commit()
+}
+
+func (m *Manager) Begin() error {
+	if m.current == nil {
+		return fmt.Errorf("no statement in progress")
+	}
+
+	// Beginning a transaction within a transaction block has no effect
+	m.current.explicit = true
+	return nil
+}
+
+func (m *Manager) Commit() error {
+	if m.current == nil || (!m.current.explicit && !m.implicitBlock) {
+		// Committing outside of a transaction block or implicit block has no effect
+		return nil
+	}
+
+	if m.current.failed {
+		// Committing a failed transaction block rolls it back
+		return m.Rollback()
+	}
+
+	return m.commit()
+}
+
+func (m *Manager) Rollback() error {
+	if m.current == nil || (!m.current.explicit && !m.implicitBlock) {
+		// Rolling back outside of a transaction block or implicit block has no effect
+		return nil
+	}
+
+	m.current.rollbackTo(0)
+	m.end()
+	return nil
+}
+
+// Close rolls back the open transaction (if any), releasing its locks. The manager
+// should not be used after it is closed.
+func (m *Manager) Close() {
+	if m.current == nil {
+		return
+	}
+
+	m.current.rollbackTo(0)
+	m.end()
+}
+
+func (m *Manager) Savepoint(name string) error {
+	t, err := m.block("SAVEPOINT")
+	if err != nil {
+		return err
+	}
+
+	t.savepoints = append(t.savepoints, savepoint{name: name, undoLength: len(t.undo)})
+	return nil
+}
+
+func (m *Manager) RollbackToSavepoint(name string) error {
+	t, err := m.block("ROLLBACK TO SAVEPOINT")
+	if err != nil {
+		return err
+	}
+
+	i := t.findSavepoint(name)
+	if i < 0 {
+		return fmt.Errorf("savepoint %q does not exist", name)
+	}
+
+	// The savepoint remains established and can be rolled back to again
+	t.rollbackTo(t.savepoints[i].undoLength)
+	t.savepoints = t.savepoints[:i+1]
+	t.failed = false
+	return nil
+}
+
+func (m *Manager) ReleaseSavepoint(name string) error {
+	t, err := m.block("RELEASE SAVEPOINT")
+	if err != nil {
+		return err
+	}
+
+	i := t.findSavepoint(name)
+	if i < 0 {
+		return fmt.Errorf("savepoint %q does not exist", name)
+	}
+
+	// Changes made after the savepoint become part of the enclosing transaction
+	t.savepoints = t.savepoints[:i]
+	return nil
+}
+
+// LockTables acquires locks on the given tables that are held until the end of
+// the transaction block.
+func (m *Manager) LockTables(names []string, mode impls.LockMode) error {
+	t, err := m.block("LOCK TABLE")
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := t.LockTable(name, mode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// commit ends the current transaction, keeping its changes. If the changes cannot
+// be logged, the transaction is rolled back instead.
+func (m *Manager) commit() error {
+	if err := m.coordinator.commit(m.current.id, m.current.changes); err != nil {
+		m.current.rollbackTo(0)
+		m.end()
+		return err
+	}
+
+	m.end()
+	return nil
+}
+
+func (m *Manager) end() {
+	m.coordinator.end(m.current.id)
+	m.current = nil
+}
+
+// block returns the current transaction if a transaction block is open.
+func (m *Manager) block(command string) (*Transaction, error) {
+	if m.current == nil || !m.current.explicit {
+		return nil, fmt.Errorf("%s can only be used in transaction blocks", command)
+	}
+
+	return m.current, nil
+}
@@ -0,0 +1,198 @@
+package transaction
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_ImplicitTransactions(t *testing.T) {
+	m := NewManager(NewCoordinator())
+	var log []string
+
+	tx := m.StartStatement()
+	tx.OnRollback(func() { log = append(log, "undo 1") })
+	m.EndStatement(nil)
+	assert.Equal(t, StatusIdle, m.Status())
+
+	tx = m.StartStatement()
+	tx.OnRollback(func() { log = append(log, "undo 2") })
+	tx.OnRollback(func() { log = append(log, "undo 3") })
+	m.EndStatement(fmt.Errorf("oops"))
+	assert.Equal(t, StatusIdle, m.Status())
+
+	assert.Equal(t, []string{"undo 3", "undo 2"}, log)
+}
+
+func TestManager_TransactionBlock(t *testing.T) {
+	m := NewManager(NewCoordinator())
+	var log []string
+
+	statement := func(name string, err error) {
+		tx := m.StartStatement()
+		tx.OnRollback(func() { log = append(log, name) })
+		m.EndStatement(err)
+	}
+
+	m.StartStatement()
+	require.NoError(t, m.Begin())
+	m.EndStatement(nil)
+	assert.Equal(t, StatusInBlock, m.Status())
+
+	statement("a", nil)
+	statement("b", fmt.Errorf("oops"))
+	assert.Equal(t, StatusFailed, m.Status())
+	assert.Equal(t, []string{"b"}, log)
+
+	m.StartStatement()
+	require.NoError(t, m.Commit())
+	m.EndStatement(nil)
+	assert.Equal(t, StatusIdle, m.Status())
+
+	// Committing a failed transaction block rolls it back
+	assert.Equal(t, []string{"b", "a"}, log)
+}
+
+func TestManager_ImplicitBlock(t *testing.T) {
+	m := NewManager(NewCoordinator())
+	var log []string
+
+	statement := func(name string, err error) {
+		tx := m.StartStatement()
+		tx.OnRollback(func() { log = append(log, name) })
+		m.EndStatement(err)
+	}
+
+	// A failing statement reverts the statements before it
+	m.BeginImplicitBlock()
+	statement("a", nil)
+	statement("b", nil)
+	assert.Equal(t, StatusIdle, m.Status())
+	statement("c", fmt.Errorf("oops"))
+	require.NoError(t, m.EndImplicitBlock(fmt.Errorf("oops")))
+	assert.Equal(t, []string{"c", "b", "a"}, log)
+
+	// COMMIT ends the implicit transaction early
+	log = nil
+	m.BeginImplicitBlock()
+	statement("a", nil)
+	m.StartStatement()
+	require.NoError(t, m.Commit())
+	m.EndStatement(nil)
+	statement("b", nil)
+	require.NoError(t, m.EndImplicitBlock(nil))
+	assert.Empty(t, log)
+
+	// BEGIN turns the implicit block into a transaction block
+	m.BeginImplicitBlock()
+	statement("a", nil)
+	m.StartStatement()
+	require.NoError(t, m.Begin())
+	m.EndStatement(nil)
+	statement("b", fmt.Errorf("oops"))
+	require.NoError(t, m.EndImplicitBlock(fmt.Errorf("oops")))
+	assert.Equal(t, StatusFailed, m.Status())
+	assert.Equal(t, []string{"b"}, log)
+}
+
+func TestManager_Savepoints(t *testing.T) {
+	m := NewManager(NewCoordinator())
+	var log []string
+
+	control := func(f func() error) error {
+		m.StartStatement()
+		err := f()
+		m.EndStatement(err)
+		return err
+	}
+	statement := func(name string) {
+		m.StartStatement().OnRollback(func() { log = append(log, name) })
+		m.EndStatement(nil)
+	}
+
+	require.ErrorContains(t, control(func() error { return m.Savepoint("s1") }), "can only be used in transaction blocks")
+	require.NoError(t, control(m.Begin))
+
+	statement("a")
+	require.NoError(t, control(func() error { return m.Savepoint("s1") }))
+	statement("b")
+	require.NoError(t, control(func() error { return m.Savepoint("s2") }))
+	statement("c")
+
+	require.NoError(t, control(func() error { return m.RollbackToSavepoint("s1") }))
+	assert.Equal(t, []string{"c", "b"}, log)
+
+	// Later savepoints are destroyed by the rollback
+	require.ErrorContains(t, control(func() error { return m.ReleaseSavepoint("s2") }), `savepoint "s2" does not exist`)
+	assert.Equal(t, StatusFailed, m.Status())
+
+	// The savepoint is retained after rolling back to it and clears the failure
+	require.NoError(t, control(func() error { return m.RollbackToSavepoint("s1") }))
+	assert.Equal(t, StatusInBlock, m.Status())
+
+	statement("d")
+	require.NoError(t, control(func() error { return m.ReleaseSavepoint("s1") }))
+	require.NoError(t, control(m.Rollback))
+	assert.Equal(t, []string{"c", "b", "d", "a"}, log)
+	assert.Equal(t, StatusIdle, m.Status())
+}
+
+func TestManager_Visibility(t *testing.T) {
+	coordinator := NewCoordinator()
+	a := NewManager(coordinator)
+	b := NewManager(coordinator)
+
+	a.StartStatement()
+	require.NoError(t, a.Begin())
+	a.EndStatement(nil)
+	txA := a.StartStatement()
+	a.EndStatement(nil)
+
+	b.StartStatement()
+	require.NoError(t, b.Begin())
+	b.EndStatement(nil)
+	txB := b.StartStatement()
+
+	// Changes made by the current command are not yet visible
+	assert.True(t, txB.Sees(0, 0))
+	assert.True(t, txB.Sees(txB.ID(), txB.CommandID()-1))
+	assert.False(t, txB.Sees(txB.ID(), txB.CommandID()))
+
+	// Transactions in progress at the start of the snapshot are never visible
+	assert.False(t, txB.Sees(txA.ID(), 0))
+	assert.False(t, txB.Committed(txA.ID()))
+	a.StartStatement()
+	require.NoError(t, a.Commit())
+	a.EndStatement(nil)
+	assert.False(t, txB.Sees(txA.ID(), 0))
+	assert.True(t, txB.Committed(txA.ID()))
+
+	// Transactions started after the snapshot are never visible
+	txC := a.StartStatement()
+	a.EndStatement(nil)
+	assert.False(t, txB.Sees(txC.ID(), 0))
+	b.EndStatement(nil)
+}
+
+func TestManager_Close(t *testing.T) {
+	coordinator := NewCoordinator()
+	m := NewManager(coordinator)
+	var log []string
+
+	tx := m.StartStatement()
+	require.NoError(t, m.Begin())
+	require.NoError(t, tx.LockTable("t", impls.AccessExclusiveLock))
+	tx.OnRollback(func() { log = append(log, "undo") })
+	require.NoError(t, m.EndStatement(nil))
+
+	m.Close()
+	assert.Equal(t, StatusIdle, m.Status())
+	assert.Equal(t, []string{"undo"}, log)
+
+	// Locks held by the closed transaction are released
+	other := NewManager(coordinator).StartStatement()
+	require.NoError(t, other.LockTable("t", impls.AccessExclusiveLock))
+}
@@ -0,0 +1,130 @@
+package transaction
+
+import (
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/storage/wal"
+)
+
+// Transaction is a unit of work whose changes are either all kept (on commit) or
+// all reverted (on rollback). Changes are reverted by invoking the undo functions
+// registered by the tables and catalogs they modified, in reverse order.
+//
+// Each transaction reads from the snapshot taken when it began: it sees the changes
+// of transactions that committed before that point along with its own changes made
+// by previous statements (commands).
+type Transaction struct {
+	coordinator    *Coordinator
+	id             int64
+	snapshot       snapshot
+	commandID      int
+	explicit       bool
+	failed         bool
+	undo           []func()
+	changes        []wal.Record
+	savepoints     []savepoint
+	statementStart int
+}
+
+type savepoint struct {
+	name       string
+	undoLength int
+}
+
+var _ impls.Transaction = &Transaction{}
+
+func newTransaction(coordinator *Coordinator) *Transaction {
+	id, snapshot := coordinator.begin()
+
+	// No other transaction can hold a lock on the new transaction yet
+	_ = coordinator.locks.acquire(id, lockTarget{transaction: id}, impls.ExclusiveLock)
+
+	return &Transaction{
+		coordinator: coordinator,
+		id:          id,
+		snapshot:    snapshot,
+	}
+}
+
+func (t *Transaction) ID() int64 {
+	return t.id
+}
+
+func (t *Transaction) CommandID() int {
+	return t.commandID
+}
+
+func (t *Transaction) Sees(id int64, commandID int) bool {
+	if id == 0 {
+		return true
+	}
+
+	if id == t.id {
+		// Changes made by the current command are not yet visible
+		return commandID < t.commandID
+	}
+
+	return t.snapshot.includes(id)
+}
+
+func (t *Transaction) Committed(id int64) bool {
+	return id == 0 || (id != t.id && !t.coordinator.isActive(id))
+}
+
+func (t *Transaction) Horizon() int64 {
+	return t.coordinator.horizon()
+}
+
+func (t *Transaction) LockTable(name string, mode impls.LockMode) error {
+	return t.coordinator.locks.acquire(t.id, lockTarget{table: name}, mode)
+}
+
+func (t *Transaction) LockRow(table string, tid int64, mode impls.LockMode) error {
+	return t.coordinator.locks.acquire(t.id, lockTarget{table: table, tid: tid}, mode)
+}
+
+// WaitFor blocks until the transaction with the given identifier has ended.
+func (t *Transaction) WaitFor(id int64) error {
+	target := lockTarget{transaction: id}
+	if err := t.coordinator.locks.acquire(t.id, target, impls.ShareLock); err != nil {
+		return err
+	}
+
+	t.coordinator.locks.release(t.id, target)
+	return nil
+}
+
+// Failed returns true if a statement within the transaction block has failed.
+// Only statements that end the transaction block (or roll back to a savepoint)
+// may execute within a failed transaction block.
+func (t *Transaction) Failed() bool {
+	return t.failed
+}
+
+func (t *Transaction) OnRollback(undo func()) {
+	t.undo = append(t.undo, undo)
+}
+
+func (t *Transaction) RecordChange(record wal.Record) {
+	n := len(t.changes)
+	t.changes = append(t.changes, record)
+	t.OnRollback(func() { t.changes = t.changes[:n] })
+}
+
+// rollbackTo reverts all changes registered after the first n.
+func (t *Transaction) rollbackTo(n int) {
+	for i := len(t.undo) - 1; i >= n; i-- {
+		t.undo[i]()
+	}
+
+	t.undo = t.undo[:min(n, len(t.undo))]
+}
+
+func (t *Transaction) findSavepoint(name string) int {
+	for i := len(t.savepoints) - 1; i >= 0; i-- {
+		if t.savepoints[i].name == name {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,183 @@
+package sample
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/efritz/gostgres/internal/execution/engine"
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentSessions(t *testing.T) {
+	const (
+		numInventory  = 10
+		numSessions   = 16
+		numIterations = 12
+	)
+
+	if _, err := readPagilaFile("data.sql"); errors.Is(err, fs.ErrNotExist) {
+		t.Skip("pagila sample data is not available")
+	}
+
+	e := engine.NewDefaultEngine()
+	require.NoError(t, LoadPagilaSampleSchemaAndData(e))
+
+	inventoryIDs := make([]int32, 0, numInventory)
+	for _, row := range queryRows(t, e, fmt.Sprintf("SELECT inventory_id FROM inventory ORDER BY inventory_id LIMIT %d", numInventory)) {
+		inventoryIDs = append(inventoryIDs, row[0].(int32))
+	}
+	require.Len(t, inventoryIDs, numInventory)
+
+	// Rentals are made by the customer and staff member of an existing rental, and are
+	// identified by (and dated after) those that already exist
+	renter := queryRows(t, e, "SELECT customer_id, staff_id FROM rental ORDER BY rental_id LIMIT 1")[0]
+	numRentals := queryRows(t, e, "SELECT count(*) FROM rental")[0][0].(int64)
+	var nextRentalID atomic.Int32
+	nextRentalID.Store(queryRows(t, e, "SELECT max(rental_id) FROM rental")[0][0].(int32))
+
+	// Return the items of the workload so that each may be rented
+	require.NoError(t, e.QueryError(protocol.Request{Query: fmt.Sprintf("UPDATE rental SET return_date = now() WHERE inventory_id <= %d AND return_date IS NULL", inventoryIDs[numInventory-1])}))
+
+	// rent rents the given item unless it is already rented. The row of the item is
+	// updated first so that concurrent rentals of the same item fail to serialize
+	// rather than both observing that the item is available.
+	rent := func(session *engine.Session, inventoryID int32) (rented bool, err error) {
+		rentalID := nextRentalID.Add(1)
+		rentalDate := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(rentalID) * time.Minute)
+
+		err = transaction(session, func(query func(string) ([][]any, error)) error {
+			if _, err := query(fmt.Sprintf("UPDATE inventory SET last_update = now() WHERE inventory_id = %d", inventoryID)); err != nil {
+				return err
+			}
+
+			open, err := query(fmt.Sprintf("SELECT rental_id FROM rental WHERE inventory_id = %d AND return_date IS NULL", inventoryID))
+			if err != nil || len(open) > 0 {
+				return err
+			}
+
+			if _, err := query(fmt.Sprintf(
+				"INSERT INTO rental (rental_id, rental_date, inventory_id, customer_id, staff_id) VALUES (%d, '%s', %d, %d, %d)",
+				rentalID, rentalDate.Format(time.RFC3339), inventoryID, renter[0], renter[1],
+			)); err != nil {
+				return err
+			}
+
+			rented = true
+			return nil
+		})
+
+		return rented && err == nil, err
+	}
+
+	// giveBack returns the open rental of the given item (if any)
+	giveBack := func(session *engine.Session, inventoryID int32) error {
+		return transaction(session, func(query func(string) ([][]any, error)) error {
+			if _, err := query(fmt.Sprintf("SELECT inventory_id FROM inventory WHERE inventory_id = %d FOR UPDATE", inventoryID)); err != nil {
+				return err
+			}
+
+			_, err := query(fmt.Sprintf("UPDATE rental SET return_date = now() WHERE inventory_id = %d AND return_date IS NULL", inventoryID))
+			return err
+		})
+	}
+
+	// openRentals counts the open rentals of each item of the workload
+	openRentals := fmt.Sprintf(`
+		SELECT i.inventory_id, count(*)
+		FROM film f
+		JOIN inventory i ON i.film_id = f.film_id
+		JOIN rental r ON r.inventory_id = i.inventory_id
+		WHERE i.inventory_id <= %d AND r.return_date IS NULL
+		GROUP BY i.inventory_id
+	`, inventoryIDs[numInventory-1])
+
+	var rentals atomic.Int64
+
+	var wg sync.WaitGroup
+	for i := 0; i < numSessions; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			session := e.NewSession()
+			defer session.Close()
+
+			for j := 0; j < numIterations; j++ {
+				inventoryID := inventoryIDs[(i+j)%numInventory]
+
+				switch j % 3 {
+				case 0:
+					// Concurrent rentals and returns of the same item fail to serialize (or
+					// deadlock) and are retried by the client
+					rented, err := rent(session, inventoryID)
+					if rented {
+						rentals.Add(1)
+					} else if err != nil && !retryable(err) {
+						t.Errorf("unexpected error: %s", err)
+					}
+
+				case 1:
+					if err := giveBack(session, inventoryID); err != nil && !retryable(err) {
+						t.Errorf("unexpected error: %s", err)
+					}
+
+				case 2:
+					// Read a consistent snapshot while other sessions write
+					rows, err := session.QueryRows(protocol.Request{Query: openRentals})
+					if err != nil {
+						t.Errorf("unexpected error: %s", err)
+					}
+					for _, row := range rows.Values {
+						assert.EqualValues(t, 1, row[1], "open rentals of inventory %d", row[0])
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, row := range queryRows(t, e, openRentals) {
+		assert.EqualValues(t, 1, row[1], "open rentals of inventory %d", row[0])
+	}
+
+	assert.Equal(t, numRentals+rentals.Load(), queryRows(t, e, "SELECT count(*) FROM rental")[0][0])
+}
+
+func queryRows(t *testing.T, e *engine.Engine, query string) [][]any {
+	rows, err := e.QueryRows(protocol.Request{Query: query})
+	require.NoError(t, err, query)
+	return rows.Values
+}
+
+// transaction invokes f within a transaction block, which is committed if f succeeds
+// and rolled back otherwise.
+func transaction(session *engine.Session, f func(query func(string) ([][]any, error)) error) error {
+	query := func(query string) ([][]any, error) {
+		rows, err := session.QueryRows(protocol.Request{Query: query})
+		return rows.Values, err
+	}
+
+	if _, err := query("BEGIN"); err != nil {
+		return err
+	}
+
+	if err := f(query); err != nil {
+		_, _ = query("ROLLBACK")
+		return err
+	}
+
+	_, err := query("COMMIT")
+	return err
+}
+
+func retryable(err error) bool {
+	return strings.Contains(err.Error(), "could not serialize access") || strings.Contains(err.Error(), "deadlock detected")
+}
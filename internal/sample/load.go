@@ -24,7 +24,8 @@ func LoadPagilaSampleData(engine *engine.Engine) error {
 		return err
 	}
 
-	return loadPagilaSample(engine, statements)
+	// Collect statistics so that plans over the sample reflect its data
+	return loadPagilaSample(engine, append(statements, "ANALYZE"))
 }
 
 func LoadPagilaSampleSchemaAndData(engine *engine.Engine) error {
@@ -39,17 +40,30 @@ func LoadPagilaSampleSchemaAndData(engine *engine.Engine) error {
 	return nil
 }
 
+// loadPagilaSample executes the given statements in a single transaction so that
+// a failure part way through the load leaves the database untouched.
 func loadPagilaSample(engine *engine.Engine, statements []string) error {
-	for _, statement := range statements {
-		if err := engine.QueryError(protocol.Request{
-			Query: statement,
+	session := engine.NewSession()
+	defer session.Close()
+	query := func(query string) error {
+		return session.QueryError(protocol.Request{
+			Query: query,
 			Debug: false,
-		}); err != nil {
+		})
+	}
+
+	if err := query("BEGIN"); err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		if err := query(statement); err != nil {
+			_ = query("ROLLBACK")
 			return err
 		}
 	}
 
-	return nil
+	return query("COMMIT")
 }
 
 //go:embed data/pagila
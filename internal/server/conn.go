@@ -0,0 +1,259 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"runtime/debug"
+
+	"github.com/efritz/gostgres/internal/execution/engine"
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/execution/transaction"
+	"github.com/efritz/gostgres/internal/syntax/parsing"
+)
+
+// errInternal is reported to the client in place of an unexpected failure, the
+// details of which are logged by the server.
+var errInternal = errors.New("internal error")
+
+type conn struct {
+	server     *Server
+	netConn    net.Conn
+	reader     *bufio.Reader
+	writer     *bufio.Writer
+	processID  int32
+	secretKey  int32
+	parameters map[string]string
+	session    *engine.Session
+
+	// State of the extended query protocol. Names of statements prepared from empty
+	// query strings via Parse messages are tracked as they have no prepared statement.
+	// After an error, messages are discarded until the next Sync.
+	emptyStatements map[string]struct{}
+	portals         map[string]*portal
+	ignoreUntilSync bool
+}
+
+func newConn(server *Server, netConn net.Conn, processID, secretKey int32) *conn {
+	return &conn{
+		server:          server,
+		netConn:         netConn,
+		reader:          bufio.NewReader(netConn),
+		writer:          bufio.NewWriter(netConn),
+		processID:       processID,
+		secretKey:       secretKey,
+		session:         server.engine.NewSession(),
+		emptyStatements: map[string]struct{}{},
+		portals:         map[string]*portal{},
+	}
+}
+
+func (c *conn) serve() (err error) {
+	defer c.netConn.Close()
+	defer c.session.Close()
+
+	// A panic while handling a message closes this connection rather than crashing
+	// the server along with every other session.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("connection panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	if ok, err := c.startup(); err != nil || !ok {
+		return err
+	}
+
+	for {
+		typ, message, err := readMessage(c.reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		if c.ignoreUntilSync && typ != messageTypeSync && typ != messageTypeTerminate {
+			continue
+		}
+
+		switch typ {
+		case messageTypeQuery:
+			query, err := message.cstring()
+			if err != nil {
+				return err
+			}
+
+			c.handleSimpleQuery(query)
+
+			if err := c.readyForQuery(); err != nil {
+				return err
+			}
+
+		case messageTypeParse:
+			c.handleExtended(c.handleParse, message)
+		case messageTypeBind:
+			c.handleExtended(c.handleBind, message)
+		case messageTypeDescribe:
+			c.handleExtended(c.handleDescribe, message)
+		case messageTypeExecute:
+			c.handleExtended(c.handleExecute, message)
+		case messageTypeClose:
+			c.handleExtended(c.handleClose, message)
+
+		case messageTypeSync:
+			c.sync()
+
+			if err := c.readyForQuery(); err != nil {
+				return err
+			}
+
+		case messageTypeFlush:
+			if err := c.writer.Flush(); err != nil {
+				return err
+			}
+
+		case messageTypeTerminate:
+			return nil
+
+		default:
+			if err := c.write(errorResponseMessage(fmt.Errorf("unsupported message type %q", typ))); err != nil {
+				return err
+			}
+
+			if err := c.readyForQuery(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// startup performs the initial handshake with the client. The returned boolean
+// is false when the connection should be closed without further processing.
+func (c *conn) startup() (bool, error) {
+	for {
+		message, err := readStartupMessage(c.reader)
+		if err != nil {
+			return false, err
+		}
+
+		switch message.code {
+		case protocolSSLCode, protocolGSSENCCode:
+			// Encryption is not supported; the client may continue in plaintext
+			if _, err := c.netConn.Write([]byte{'N'}); err != nil {
+				return false, err
+			}
+			continue
+
+		case protocolCancelCode:
+			// Queries run to completion; there is nothing to cancel
+			return false, nil
+
+		case protocolVersion3:
+			c.parameters = message.parameters
+
+		default:
+			err := fmt.Errorf("unsupported frontend protocol %d.%d", message.code>>16, message.code&0xFFFF)
+			_ = c.write(errorResponseMessage(err))
+			_ = c.writer.Flush()
+			return false, err
+		}
+
+		break
+	}
+
+	if err := c.write(newMessage(messageTypeAuthentication).int32(0).finish()); err != nil {
+		return false, err
+	}
+
+	for _, parameter := range [][2]string{
+		{"server_version", serverVersion},
+		{"server_encoding", "UTF8"},
+		{"client_encoding", "UTF8"},
+		{"DateStyle", "ISO, MDY"},
+		{"TimeZone", "UTC"},
+		{"integer_datetimes", "on"},
+		{"standard_conforming_strings", "on"},
+		{"application_name", c.parameters["application_name"]},
+	} {
+		if err := c.write(newMessage(messageTypeParameterStatus).cstring(parameter[0]).cstring(parameter[1]).finish()); err != nil {
+			return false, err
+		}
+	}
+
+	if err := c.write(newMessage(messageTypeBackendKeyData).int32(c.processID).int32(c.secretKey).finish()); err != nil {
+		return false, err
+	}
+
+	if err := c.readyForQuery(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// handleSimpleQuery executes each statement of a simple Query message in turn.
+// The statements run in a single implicit transaction unless they contain explicit
+// transaction control statements. Execution stops at the first statement that fails,
+// rolling back the implicit transaction.
+func (c *conn) handleSimpleQuery(query string) {
+	statements := parsing.SplitStatements(query)
+	if len(statements) == 0 {
+		_ = c.write(newMessage(messageTypeEmptyQuery).finish())
+		return
+	}
+
+	var err error
+	c.session.BeginImplicitBlock()
+	for _, statement := range statements {
+		w := newWireResponseWriter(c, nil, true)
+		c.execute(w, func() { c.session.Query(protocol.Request{Query: statement}, w) })
+
+		if err = w.err; err != nil {
+			break
+		}
+	}
+
+	if err := c.session.EndImplicitBlock(err); err != nil {
+		_ = c.write(errorResponseMessage(err))
+	}
+}
+
+// execute invokes the given function, reporting panics to the client through
+// the given response writer. The details of a panic are logged rather than
+// reported to the client.
+func (c *conn) execute(w *wireResponseWriter, f func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("connection from %s: query execution panic: %v\n%s", c.netConn.RemoteAddr(), r, debug.Stack())
+			w.Error(errInternal)
+		}
+	}()
+
+	f()
+}
+
+func (c *conn) readyForQuery() error {
+	status := transactionStatusIdle
+	switch c.session.TransactionStatus() {
+	case transaction.StatusInBlock:
+		status = transactionStatusInBlock
+	case transaction.StatusFailed:
+		status = transactionStatusFailed
+	}
+
+	if err := c.write(newMessage(messageTypeReadyForQuery).byte(status).finish()); err != nil {
+		return err
+	}
+
+	return c.writer.Flush()
+}
+
+func (c *conn) write(message []byte) error {
+	_, err := c.writer.Write(message)
+	return err
+}
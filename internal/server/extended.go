@@ -0,0 +1,306 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/efritz/gostgres/internal/syntax/parsing"
+)
+
+// portal is a prepared statement bound to a set of parameter values, ready to
+// be executed by an Execute message. A portal with a nil statement was created
+// from an empty query string.
+type portal struct {
+	statement     impls.PreparedStatement
+	parameters    []any
+	resultFormats []int16
+}
+
+// handleExtended invokes the given handler for a message of the extended query
+// protocol. If the handler fails, an error is sent to the client and subsequent
+// messages are discarded until the next Sync.
+func (c *conn) handleExtended(handler func(message *messageReader) error, message *messageReader) {
+	if err := handler(message); err != nil {
+		_ = c.write(errorResponseMessage(err))
+		c.ignoreUntilSync = true
+	}
+}
+
+// sync ends the current extended query protocol cycle. The unnamed statement
+// persists across cycles but portals, which only exist within a transaction,
+// do not.
+func (c *conn) sync() {
+	c.ignoreUntilSync = false
+	clear(c.portals)
+}
+
+// Parse: name, query, number of parameter types, and parameter type OIDs.
+func (c *conn) handleParse(message *messageReader) error {
+	name, err := message.cstring()
+	if err != nil {
+		return err
+	}
+	query, err := message.cstring()
+	if err != nil {
+		return err
+	}
+	numParameterTypes, err := message.count()
+	if err != nil {
+		return err
+	}
+
+	parameterTypes := make([]types.Type, 0, numParameterTypes)
+	for i := 0; i < numParameterTypes; i++ {
+		oid, err := message.int32()
+		if err != nil {
+			return err
+		}
+
+		parameterTypes = append(parameterTypes, typeFromOID(oid))
+	}
+
+	statements := parsing.SplitStatements(query)
+	if len(statements) > 1 {
+		return fmt.Errorf("cannot insert multiple commands into a prepared statement")
+	}
+
+	if _, ok := c.session.PreparedStatement(name); ok && name != "" {
+		return fmt.Errorf("prepared statement %q already exists", name)
+	}
+
+	if len(statements) == 0 {
+		c.session.Deallocate(name)
+		c.emptyStatements[name] = struct{}{}
+	} else {
+		if _, err := c.session.Prepare(name, statements[0], parameterTypes); err != nil {
+			return err
+		}
+
+		delete(c.emptyStatements, name)
+	}
+
+	return c.write(newMessage(messageTypeParseComplete).finish())
+}
+
+// Bind: portal name, statement name, parameter format codes, parameter values,
+// and result format codes.
+func (c *conn) handleBind(message *messageReader) error {
+	portalName, err := message.cstring()
+	if err != nil {
+		return err
+	}
+	statementName, err := message.cstring()
+	if err != nil {
+		return err
+	}
+	parameterFormats, err := readFormats(message)
+	if err != nil {
+		return err
+	}
+	numParameters, err := message.count()
+	if err != nil {
+		return err
+	}
+
+	statement, err := c.lookupStatement(statementName)
+	if err != nil {
+		return err
+	}
+
+	var parameterTypes []types.Type
+	if statement != nil {
+		parameterTypes = statement.ParameterTypes()
+	}
+	if numParameters != len(parameterTypes) {
+		return fmt.Errorf("bind message supplies %d parameters, but prepared statement %q requires %d", numParameters, statementName, len(parameterTypes))
+	}
+
+	parameters := make([]any, 0, numParameters)
+	for i := 0; i < numParameters; i++ {
+		length, err := message.int32()
+		if err != nil {
+			return err
+		}
+
+		var data []byte
+		if length >= 0 {
+			if data, err = message.bytes(int(length)); err != nil {
+				return err
+			}
+		}
+
+		value, err := decodeValue(data, parameterTypes[i], formatForColumn(parameterFormats, i))
+		if err != nil {
+			return fmt.Errorf("parameter $%d: %s", i+1, err)
+		}
+
+		parameters = append(parameters, value)
+	}
+
+	resultFormats, err := readFormats(message)
+	if err != nil {
+		return err
+	}
+
+	c.portals[portalName] = &portal{
+		statement:     statement,
+		parameters:    parameters,
+		resultFormats: resultFormats,
+	}
+
+	return c.write(newMessage(messageTypeBindComplete).finish())
+}
+
+// Describe: target type (statement or portal) and name.
+func (c *conn) handleDescribe(message *messageReader) error {
+	target, err := message.byte()
+	if err != nil {
+		return err
+	}
+	name, err := message.cstring()
+	if err != nil {
+		return err
+	}
+
+	switch target {
+	case targetStatement:
+		statement, err := c.lookupStatement(name)
+		if err != nil {
+			return err
+		}
+
+		var parameterTypes []types.Type
+		var fields []fields.Field
+		if statement != nil {
+			parameterTypes = statement.ParameterTypes()
+			fields = statement.Fields()
+		}
+
+		m := newMessage(messageTypeParameterDescription).int16(int16(len(parameterTypes)))
+		for _, typ := range parameterTypes {
+			m.int32(describeType(typ).oid)
+		}
+		if err := c.write(m.finish()); err != nil {
+			return err
+		}
+
+		return c.writeDescription(fields, nil)
+
+	case targetPortal:
+		p, ok := c.portals[name]
+		if !ok {
+			return fmt.Errorf("portal %q does not exist", name)
+		}
+
+		var fields []fields.Field
+		if p.statement != nil {
+			fields = p.statement.Fields()
+		}
+
+		return c.writeDescription(fields, p.resultFormats)
+	}
+
+	return fmt.Errorf("invalid describe target %q", target)
+}
+
+// Execute: portal name and maximum number of rows to return. Portals are always
+// run to completion; a row limit is not supported.
+func (c *conn) handleExecute(message *messageReader) error {
+	name, err := message.cstring()
+	if err != nil {
+		return err
+	}
+	if _, err := message.int32(); err != nil {
+		return err
+	}
+
+	p, ok := c.portals[name]
+	if !ok {
+		return fmt.Errorf("portal %q does not exist", name)
+	}
+
+	if p.statement == nil {
+		return c.write(newMessage(messageTypeEmptyQuery).finish())
+	}
+
+	w := newWireResponseWriter(c, p.resultFormats, false)
+	c.execute(w, func() { c.session.Execute(p.statement, p.parameters, w) })
+
+	if w.err != nil {
+		// The error has already been sent to the client
+		c.ignoreUntilSync = true
+	}
+
+	return nil
+}
+
+// Close: target type (statement or portal) and name.
+func (c *conn) handleClose(message *messageReader) error {
+	target, err := message.byte()
+	if err != nil {
+		return err
+	}
+	name, err := message.cstring()
+	if err != nil {
+		return err
+	}
+
+	switch target {
+	case targetStatement:
+		c.session.Deallocate(name)
+		delete(c.emptyStatements, name)
+	case targetPortal:
+		delete(c.portals, name)
+	default:
+		return fmt.Errorf("invalid close target %q", target)
+	}
+
+	return c.write(newMessage(messageTypeCloseComplete).finish())
+}
+
+// lookupStatement returns the prepared statement with the given name, which may
+// also have been prepared via a PREPARE statement. A nil statement is returned for
+// a statement created from an empty query string.
+func (c *conn) lookupStatement(name string) (impls.PreparedStatement, error) {
+	if statement, ok := c.session.PreparedStatement(name); ok {
+		return statement, nil
+	}
+
+	if _, ok := c.emptyStatements[name]; ok {
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("prepared statement %q does not exist", name)
+}
+
+func (c *conn) writeDescription(fields []fields.Field, formats []int16) error {
+	if len(fields) == 0 {
+		return c.write(newMessage(messageTypeNoData).finish())
+	}
+
+	return c.write(rowDescriptionMessage(fields, formats))
+}
+
+func readFormats(message *messageReader) ([]int16, error) {
+	n, err := message.count()
+	if err != nil {
+		return nil, err
+	}
+
+	formats := make([]int16, 0, n)
+	for i := 0; i < n; i++ {
+		format, err := message.int16()
+		if err != nil {
+			return nil, err
+		}
+		if format != formatText && format != formatBinary {
+			return nil, fmt.Errorf("unsupported format code %d", format)
+		}
+
+		formats = append(formats, format)
+	}
+
+	return formats, nil
+}
@@ -0,0 +1,256 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Frontend message types
+const (
+	messageTypeBind      byte = 'B'
+	messageTypeClose     byte = 'C'
+	messageTypeDescribe  byte = 'D'
+	messageTypeExecute   byte = 'E'
+	messageTypeFlush     byte = 'H'
+	messageTypeParse     byte = 'P'
+	messageTypeQuery     byte = 'Q'
+	messageTypeSync      byte = 'S'
+	messageTypeTerminate byte = 'X'
+)
+
+// Backend message types
+const (
+	messageTypeAuthentication       byte = 'R'
+	messageTypeBackendKeyData       byte = 'K'
+	messageTypeBindComplete         byte = '2'
+	messageTypeCloseComplete        byte = '3'
+	messageTypeCommandComplete      byte = 'C'
+	messageTypeDataRow              byte = 'D'
+	messageTypeEmptyQuery           byte = 'I'
+	messageTypeErrorResponse        byte = 'E'
+	messageTypeNoData               byte = 'n'
+	messageTypeParameterDescription byte = 't'
+	messageTypeParameterStatus      byte = 'S'
+	messageTypeParseComplete        byte = '1'
+	messageTypeReadyForQuery        byte = 'Z'
+	messageTypeRowDescription       byte = 'T'
+)
+
+// Targets of Describe and Close messages
+const (
+	targetStatement byte = 'S'
+	targetPortal    byte = 'P'
+)
+
+// Startup message codes
+const (
+	protocolVersion3     = 196608   // 3.0
+	protocolCancelCode   = 80877102 // 1234.5678
+	protocolSSLCode      = 80877103 // 1234.5679
+	protocolGSSENCCode   = 80877104 // 1234.5680
+	maxStartupMessageLen = 10000
+	maxMessageLen        = 1 << 30
+)
+
+// Transaction status indicators sent with ReadyForQuery
+const (
+	transactionStatusIdle    byte = 'I'
+	transactionStatusInBlock byte = 'T'
+	transactionStatusFailed  byte = 'E'
+)
+
+//
+//
+
+type startupMessage struct {
+	code       uint32
+	parameters map[string]string
+}
+
+func readStartupMessage(r io.Reader) (startupMessage, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return startupMessage{}, err
+	}
+
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length < 8 || length > maxStartupMessageLen {
+		return startupMessage{}, fmt.Errorf("invalid startup message length %d", length)
+	}
+
+	payload := make([]byte, length-8)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return startupMessage{}, err
+	}
+
+	message := startupMessage{
+		code:       binary.BigEndian.Uint32(header[4:8]),
+		parameters: map[string]string{},
+	}
+
+	if message.code != protocolVersion3 {
+		return message, nil
+	}
+
+	buf := newMessageReader(payload)
+	for {
+		key, err := buf.cstring()
+		if err != nil {
+			return startupMessage{}, err
+		}
+		if key == "" {
+			break
+		}
+
+		value, err := buf.cstring()
+		if err != nil {
+			return startupMessage{}, err
+		}
+
+		message.parameters[key] = value
+	}
+
+	return message, nil
+}
+
+func readMessage(r io.Reader) (byte, *messageReader, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	length := binary.BigEndian.Uint32(header[1:5])
+	if length < 4 || length > maxMessageLen {
+		return 0, nil, fmt.Errorf("invalid message length %d", length)
+	}
+
+	payload := make([]byte, length-4)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return header[0], newMessageReader(payload), nil
+}
+
+//
+//
+
+type messageReader struct {
+	payload []byte
+}
+
+func newMessageReader(payload []byte) *messageReader {
+	return &messageReader{payload: payload}
+}
+
+func (r *messageReader) byte() (byte, error) {
+	if len(r.payload) < 1 {
+		return 0, fmt.Errorf("malformed message: truncated byte")
+	}
+
+	v := r.payload[0]
+	r.payload = r.payload[1:]
+	return v, nil
+}
+
+func (r *messageReader) cstring() (string, error) {
+	i := bytes.IndexByte(r.payload, 0)
+	if i < 0 {
+		return "", fmt.Errorf("malformed message: unterminated string")
+	}
+
+	s := string(r.payload[:i])
+	r.payload = r.payload[i+1:]
+	return s, nil
+}
+
+func (r *messageReader) int16() (int16, error) {
+	if len(r.payload) < 2 {
+		return 0, fmt.Errorf("malformed message: truncated int16")
+	}
+
+	v := int16(binary.BigEndian.Uint16(r.payload))
+	r.payload = r.payload[2:]
+	return v, nil
+}
+
+// count reads an int16 that counts the items that follow it in the message.
+func (r *messageReader) count() (int, error) {
+	n, err := r.int16()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("malformed message: negative count %d", n)
+	}
+
+	return int(n), nil
+}
+
+func (r *messageReader) int32() (int32, error) {
+	if len(r.payload) < 4 {
+		return 0, fmt.Errorf("malformed message: truncated int32")
+	}
+
+	v := int32(binary.BigEndian.Uint32(r.payload))
+	r.payload = r.payload[4:]
+	return v, nil
+}
+
+func (r *messageReader) bytes(n int) ([]byte, error) {
+	if n < 0 || len(r.payload) < n {
+		return nil, fmt.Errorf("malformed message: truncated value")
+	}
+
+	v := r.payload[:n]
+	r.payload = r.payload[n:]
+	return v, nil
+}
+
+//
+//
+
+type messageBuilder struct {
+	buf bytes.Buffer
+}
+
+func newMessage(typ byte) *messageBuilder {
+	b := &messageBuilder{}
+	b.buf.WriteByte(typ)
+	b.buf.Write([]byte{0, 0, 0, 0}) // length placeholder
+	return b
+}
+
+func (b *messageBuilder) byte(v byte) *messageBuilder {
+	b.buf.WriteByte(v)
+	return b
+}
+
+func (b *messageBuilder) int16(v int16) *messageBuilder {
+	_ = binary.Write(&b.buf, binary.BigEndian, v)
+	return b
+}
+
+func (b *messageBuilder) int32(v int32) *messageBuilder {
+	_ = binary.Write(&b.buf, binary.BigEndian, v)
+	return b
+}
+
+func (b *messageBuilder) cstring(v string) *messageBuilder {
+	b.buf.WriteString(v)
+	b.buf.WriteByte(0)
+	return b
+}
+
+func (b *messageBuilder) bytes(v []byte) *messageBuilder {
+	b.buf.Write(v)
+	return b
+}
+
+func (b *messageBuilder) finish() []byte {
+	data := b.buf.Bytes()
+	binary.BigEndian.PutUint32(data[1:5], uint32(len(data)-1))
+	return data
+}
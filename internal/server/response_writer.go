@@ -0,0 +1,165 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/rows"
+)
+
+// wireResponseWriter adapts the engine's response writer to the frontend/backend
+// protocol. Unless the rows were already described (as is the case for portals in
+// the extended query protocol), a RowDescription is sent once the statement is
+// described, so that the fields of a result are known even when it has no rows.
+// Rows are streamed to the client as DataRow messages as they are produced. The
+// CommandComplete tag is built from the command of the described statement.
+type wireResponseWriter struct {
+	c          *conn
+	command    string
+	formats    []int16
+	described  bool
+	numRows    int
+	completed  bool
+	err        error
+	writeError error
+}
+
+var _ protocol.ResponseWriter = &wireResponseWriter{}
+var _ protocol.Describer = &wireResponseWriter{}
+
+func newWireResponseWriter(c *conn, formats []int16, describe bool) *wireResponseWriter {
+	return &wireResponseWriter{
+		c:         c,
+		formats:   formats,
+		described: !describe,
+	}
+}
+
+func (w *wireResponseWriter) Describe(command string, fields []fields.Field) {
+	w.command = command
+
+	if w.described || len(fields) == 0 {
+		return
+	}
+
+	w.described = true
+	w.send(rowDescriptionMessage(fields, w.formats))
+}
+
+func (w *wireResponseWriter) SendRow(row rows.Row) {
+	if w.completed {
+		return
+	}
+
+	w.numRows++
+
+	if len(row.Fields) == 0 {
+		// Mutations without a RETURNING clause emit rows without fields;
+		// these only contribute to the affected row count.
+		return
+	}
+
+	if !w.described {
+		// Rows of a statement that was not described are described by their fields
+		w.described = true
+		w.send(rowDescriptionMessage(row.Fields, w.formats))
+	}
+
+	message, err := dataRowMessage(row.Values, w.formats)
+	if err != nil {
+		w.Error(err)
+		return
+	}
+
+	w.send(message)
+}
+
+func (w *wireResponseWriter) Done() {
+	if w.completed {
+		return
+	}
+
+	w.completed = true
+	w.send(newMessage(messageTypeCommandComplete).cstring(commandTag(w.command, w.numRows)).finish())
+}
+
+func (w *wireResponseWriter) Error(err error) {
+	if w.completed {
+		return
+	}
+
+	w.completed = true
+	w.err = err
+	w.send(errorResponseMessage(err))
+}
+
+func (w *wireResponseWriter) send(message []byte) {
+	if w.writeError != nil {
+		return
+	}
+
+	w.writeError = w.c.write(message)
+}
+
+//
+//
+
+func rowDescriptionMessage(fields []fields.Field, formats []int16) []byte {
+	m := newMessage(messageTypeRowDescription).int16(int16(len(fields)))
+	for i, field := range fields {
+		typ := describeType(field.Type())
+
+		m.cstring(field.Name())
+		m.int32(0)                           // table OID
+		m.int16(0)                           // column attribute number
+		m.int32(typ.oid)                     // data type OID
+		m.int16(typ.size)                    // data type size
+		m.int32(-1)                          // type modifier
+		m.int16(formatForColumn(formats, i)) // format code
+	}
+
+	return m.finish()
+}
+
+func dataRowMessage(values []any, formats []int16) ([]byte, error) {
+	m := newMessage(messageTypeDataRow).int16(int16(len(values)))
+	for i, value := range values {
+		encoded, err := encodeValue(value, formatForColumn(formats, i))
+		if err != nil {
+			return nil, err
+		}
+		if encoded == nil {
+			m.int32(-1)
+			continue
+		}
+
+		m.int32(int32(len(encoded))).bytes(encoded)
+	}
+
+	return m.finish(), nil
+}
+
+func errorResponseMessage(err error) []byte {
+	return newMessage(messageTypeErrorResponse).
+		byte('S').cstring("ERROR").
+		byte('V').cstring("ERROR").
+		byte('C').cstring("XX000"). // internal_error
+		byte('M').cstring(err.Error()).
+		byte(0).
+		finish()
+}
+
+//
+//
+
+func commandTag(command string, numRows int) string {
+	switch command {
+	case "INSERT":
+		return fmt.Sprintf("INSERT 0 %d", numRows)
+	case "SELECT", "UPDATE", "DELETE":
+		return fmt.Sprintf("%s %d", command, numRows)
+	}
+
+	return command
+}
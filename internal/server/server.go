@@ -0,0 +1,53 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+	"net"
+	"sync/atomic"
+
+	"github.com/efritz/gostgres/internal/execution/engine"
+)
+
+// serverVersion is reported to clients on startup. Clients use this value to
+// determine which features they can rely on; report a modern version.
+const serverVersion = "16.0 (gostgres)"
+
+type Server struct {
+	engine        *engine.Engine
+	nextProcessID atomic.Int32
+}
+
+func NewServer(engine *engine.Engine) *Server {
+	return &Server{
+		engine: engine,
+	}
+}
+
+// Serve accepts connections on the given listener and serves each of them on a
+// new goroutine. Serve returns when the listener is closed.
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		netConn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			return err
+		}
+
+		go func() {
+			if err := s.ServeConn(netConn); err != nil {
+				log.Printf("connection from %s: %s", netConn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// ServeConn speaks the frontend/backend protocol over the given connection until
+// the client terminates the session or the connection is closed.
+func (s *Server) ServeConn(netConn net.Conn) error {
+	return newConn(s, netConn, s.nextProcessID.Add(1), rand.Int31()).serve()
+}
@@ -0,0 +1,601 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/execution/engine"
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartup(t *testing.T) {
+	client := newTestClient(t)
+	client.sendSSLRequest()
+	client.sendStartup()
+
+	messages := client.readUntilReady()
+	require.NotEmpty(t, messages)
+	assert.Equal(t, messageTypeAuthentication, messages[0].typ)
+	assert.Equal(t, []byte{0, 0, 0, 0}, messages[0].payload)
+	assert.Equal(t, messageTypeBackendKeyData, messages[len(messages)-2].typ)
+	assert.Equal(t, []byte{transactionStatusIdle}, messages[len(messages)-1].payload)
+
+	parameters := map[string]string{}
+	for _, message := range messages {
+		if message.typ == messageTypeParameterStatus {
+			parts := bytes.Split(message.payload, []byte{0})
+			parameters[string(parts[0])] = string(parts[1])
+		}
+	}
+	assert.Equal(t, serverVersion, parameters["server_version"])
+	assert.Equal(t, "UTF8", parameters["client_encoding"])
+}
+
+func TestSimpleQuery(t *testing.T) {
+	client := newTestClient(t)
+	client.sendStartup()
+	client.readUntilReady()
+
+	for _, testCase := range []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{
+			name:     "create table",
+			query:    "CREATE TABLE t (id integer, name text, score real);",
+			expected: []string{"C:CREATE TABLE", "Z"},
+		},
+		{
+			name:     "insert",
+			query:    "INSERT INTO t (id, name, score) VALUES (1, 'a', 1.5), (2, NULL, 2.5);",
+			expected: []string{"C:INSERT 0 2", "Z"},
+		},
+		{
+			name:  "select",
+			query: "SELECT id, name, score FROM t ORDER BY id;",
+			expected: []string{
+				"T:id/23,name/25,score/700",
+				"D:1|a|1.5",
+				"D:2|NULL|2.5",
+				"C:SELECT 2",
+				"Z",
+			},
+		},
+		{
+			name:     "select no rows",
+			query:    "SELECT id FROM t WHERE id > 10;",
+			expected: []string{"T:id/23", "C:SELECT 0", "Z"},
+		},
+		{
+			name:     "update returning",
+			query:    "UPDATE t SET name = 'b' WHERE id = 2 RETURNING id, name;",
+			expected: []string{"T:id/23,name/25", "D:2|b", "C:UPDATE 1", "Z"},
+		},
+		{
+			name:     "with select",
+			query:    "WITH s AS (SELECT id FROM t) SELECT id FROM s;",
+			expected: []string{"T:id/23", "D:1", "D:2", "C:SELECT 2", "Z"},
+		},
+		{
+			name:     "start transaction",
+			query:    "START TRANSACTION; COMMIT;",
+			expected: []string{"C:START TRANSACTION", "C:COMMIT", "Z"},
+		},
+		{
+			name:     "multiple statements",
+			query:    "DELETE FROM t WHERE id = 1; SELECT id FROM t;",
+			expected: []string{"C:DELETE 1", "T:id/23", "D:2", "C:SELECT 1", "Z"},
+		},
+		{
+			name:     "error stops execution",
+			query:    "SELECT * FROM missing; DELETE FROM t;",
+			expected: []string{"E", "Z"},
+		},
+		{
+			name:     "empty query",
+			query:    "  ",
+			expected: []string{"I", "Z"},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			client.sendQuery(testCase.query)
+
+			var summaries []string
+			for _, message := range client.readUntilReady() {
+				summaries = append(summaries, summarizeMessage(t, message))
+			}
+
+			assert.Equal(t, testCase.expected, summaries)
+		})
+	}
+}
+
+func TestExtendedQuery(t *testing.T) {
+	client := newTestClient(t)
+	client.sendStartup()
+	client.readUntilReady()
+	client.sendQuery("CREATE TABLE t (id integer, name text); INSERT INTO t (id, name) VALUES (1, 'a'), (2, 'b');")
+	client.readUntilReady()
+
+	for _, testCase := range []struct {
+		name     string
+		send     func()
+		expected []string
+	}{
+		{
+			name: "parse and describe statement",
+			send: func() {
+				client.sendParse("s1", "SELECT id, name FROM t WHERE id = $1")
+				client.sendDescribe(targetStatement, "s1")
+			},
+			expected: []string{"1", "t:23", "T:id/23,name/25", "Z"},
+		},
+		{
+			name: "bind and execute with text parameters",
+			send: func() {
+				client.sendBind("", "s1", nil, [][]byte{[]byte("1")}, nil)
+				client.sendExecute("")
+			},
+			expected: []string{"2", "D:1|a", "C:SELECT 1", "Z"},
+		},
+		{
+			name: "bind and execute with binary parameters and results",
+			send: func() {
+				client.sendBind("p", "s1", []int16{formatBinary}, [][]byte{{0, 0, 0, 2}}, []int16{formatBinary, formatText})
+				client.sendDescribe(targetPortal, "p")
+				client.sendExecute("p")
+			},
+			expected: []string{"2", "T:id/23,name/25", `D:\x00\x00\x00\x02|b`, "C:SELECT 1", "Z"},
+		},
+		{
+			name: "unnamed statement",
+			send: func() {
+				client.sendParse("", "INSERT INTO t (id, name) VALUES ($1, $2)")
+				client.sendDescribe(targetStatement, "")
+				client.sendBind("", "", nil, [][]byte{[]byte("3"), nil}, nil)
+				client.sendExecute("")
+			},
+			expected: []string{"1", "t:23,25", "n", "2", "C:INSERT 0 1", "Z"},
+		},
+		{
+			name: "declared parameter types",
+			send: func() {
+				client.sendParse("", "SELECT $1 || name FROM t WHERE id = $2", oidText, oidInt8)
+				client.sendDescribe(targetStatement, "")
+			},
+			expected: []string{"1", "t:25,20", "T:?column?/25", "Z"},
+		},
+		{
+			name: "empty query",
+			send: func() {
+				client.sendParse("", "")
+				client.sendBind("", "", nil, nil, nil)
+				client.sendExecute("")
+			},
+			expected: []string{"1", "2", "I", "Z"},
+		},
+		{
+			name: "wrong number of parameters",
+			send: func() {
+				client.sendBind("", "s1", nil, nil, nil)
+				client.sendExecute("")
+			},
+			expected: []string{"E", "Z"},
+		},
+		{
+			name: "invalid parameter value",
+			send: func() {
+				client.sendBind("", "s1", nil, [][]byte{[]byte("one")}, nil)
+				client.sendExecute("")
+			},
+			expected: []string{"E", "Z"},
+		},
+		{
+			name: "parse error skips until sync",
+			send: func() {
+				client.sendParse("", "SELECT FROM WHERE")
+				client.sendBind("", "", nil, nil, nil)
+				client.sendExecute("")
+			},
+			expected: []string{"E", "Z"},
+		},
+		{
+			name: "duplicate statement name",
+			send: func() {
+				client.sendParse("s1", "SELECT 1")
+			},
+			expected: []string{"E", "Z"},
+		},
+		{
+			name: "close statement",
+			send: func() {
+				client.sendClose(targetStatement, "s1")
+				client.sendBind("", "s1", nil, [][]byte{[]byte("1")}, nil)
+			},
+			expected: []string{"3", "E", "Z"},
+		},
+		{
+			name: "portals do not survive sync",
+			send: func() {
+				client.sendExecute("p")
+			},
+			expected: []string{"E", "Z"},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.send()
+			client.sendSync()
+
+			var summaries []string
+			for _, message := range client.readUntilReady() {
+				summaries = append(summaries, summarizeMessage(t, message))
+			}
+
+			assert.Equal(t, testCase.expected, summaries)
+		})
+	}
+}
+
+func TestMalformedMessages(t *testing.T) {
+	client := newTestClient(t)
+	client.sendStartup()
+	client.readUntilReady()
+
+	for _, testCase := range []struct {
+		name    string
+		message *messageBuilder
+	}{
+		{
+			name:    "negative parameter type count",
+			message: newMessage(messageTypeParse).cstring("").cstring("SELECT 1").int16(-1),
+		},
+		{
+			name:    "negative parameter format count",
+			message: newMessage(messageTypeBind).cstring("").cstring("").int16(-1),
+		},
+		{
+			name:    "negative parameter count",
+			message: newMessage(messageTypeBind).cstring("").cstring("").int16(0).int16(-1).int16(0),
+		},
+		{
+			name:    "negative result format count",
+			message: newMessage(messageTypeBind).cstring("").cstring("").int16(0).int16(0).int16(-1),
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			client.sendParse("", "SELECT 1")
+			client.write(testCase.message.finish())
+			client.sendSync()
+
+			var summaries []string
+			for _, message := range client.readUntilReady() {
+				summaries = append(summaries, summarizeMessage(t, message))
+			}
+
+			assert.Equal(t, []string{"1", "E", "Z"}, summaries)
+		})
+	}
+
+	// The connection remains usable
+	client.sendQuery("SELECT 1 AS one;")
+
+	var summaries []string
+	for _, message := range client.readUntilReady() {
+		summaries = append(summaries, summarizeMessage(t, message))
+	}
+	assert.Equal(t, []string{"T:one/23", "D:1", "C:SELECT 1", "Z"}, summaries)
+}
+
+func TestPreparedStatementSharing(t *testing.T) {
+	client := newTestClient(t)
+	client.sendStartup()
+	client.readUntilReady()
+	client.sendQuery("CREATE TABLE t (id integer, name text); INSERT INTO t (id, name) VALUES (1, 'a');")
+	client.readUntilReady()
+
+	// Statements prepared via SQL are visible to the extended protocol
+	client.sendQuery("PREPARE q (integer) AS SELECT name FROM t WHERE id = $1;")
+	client.readUntilReady()
+	client.sendBind("", "q", nil, [][]byte{[]byte("1")}, nil)
+	client.sendExecute("")
+	client.sendSync()
+
+	var summaries []string
+	for _, message := range client.readUntilReady() {
+		summaries = append(summaries, summarizeMessage(t, message))
+	}
+	assert.Equal(t, []string{"2", "D:a", "C:SELECT 1", "Z"}, summaries)
+
+	// Statements prepared via the extended protocol are visible to SQL
+	client.sendParse("s", "SELECT id FROM t WHERE name = $1")
+	client.sendSync()
+	client.readUntilReady()
+	client.sendQuery("EXECUTE s ('a');")
+
+	summaries = nil
+	for _, message := range client.readUntilReady() {
+		summaries = append(summaries, summarizeMessage(t, message))
+	}
+	assert.Equal(t, []string{"T:id/23", "D:1", "C:SELECT 1", "Z"}, summaries)
+}
+
+func TestTransactionStatus(t *testing.T) {
+	client := newTestClient(t)
+	client.sendStartup()
+	client.readUntilReady()
+	client.sendQuery("CREATE TABLE t (id integer PRIMARY KEY);")
+	client.readUntilReady()
+
+	for _, testCase := range []struct {
+		query    string
+		expected []string
+		status   byte
+	}{
+		{query: "BEGIN;", expected: []string{"C:BEGIN", "Z"}, status: transactionStatusInBlock},
+		{query: "INSERT INTO t (id) VALUES (1);", expected: []string{"C:INSERT 0 1", "Z"}, status: transactionStatusInBlock},
+		{query: "INSERT INTO t (id) VALUES (1);", expected: []string{"E", "Z"}, status: transactionStatusFailed},
+		{query: "SELECT id FROM t;", expected: []string{"E", "Z"}, status: transactionStatusFailed},
+		{query: "ROLLBACK;", expected: []string{"C:ROLLBACK", "Z"}, status: transactionStatusIdle},
+		{query: "SELECT id FROM t;", expected: []string{"T:id/23", "C:SELECT 0", "Z"}, status: transactionStatusIdle},
+
+		// Statements of a message run in an implicit transaction that fails with any of them
+		{query: "INSERT INTO t (id) VALUES (2); INSERT INTO t (id) VALUES (2);", expected: []string{"C:INSERT 0 1", "E", "Z"}, status: transactionStatusIdle},
+		{query: "SELECT id FROM t;", expected: []string{"T:id/23", "C:SELECT 0", "Z"}, status: transactionStatusIdle},
+		{query: "INSERT INTO t (id) VALUES (3); COMMIT; INSERT INTO t (id) VALUES (4); SELECT id FROM missing;", expected: []string{"C:INSERT 0 1", "C:COMMIT", "C:INSERT 0 1", "E", "Z"}, status: transactionStatusIdle},
+		{query: "SELECT id FROM t;", expected: []string{"T:id/23", "D:3", "C:SELECT 1", "Z"}, status: transactionStatusIdle},
+		{query: "INSERT INTO t (id) VALUES (5); BEGIN; INSERT INTO t (id) VALUES (6);", expected: []string{"C:INSERT 0 1", "C:BEGIN", "C:INSERT 0 1", "Z"}, status: transactionStatusInBlock},
+		{query: "ROLLBACK; SELECT id FROM t;", expected: []string{"C:ROLLBACK", "T:id/23", "D:3", "C:SELECT 1", "Z"}, status: transactionStatusIdle},
+	} {
+		client.sendQuery(testCase.query)
+
+		var summaries []string
+		messages := client.readUntilReady()
+		for _, message := range messages {
+			summaries = append(summaries, summarizeMessage(t, message))
+		}
+
+		assert.Equal(t, testCase.expected, summaries, testCase.query)
+		assert.Equal(t, []byte{testCase.status}, messages[len(messages)-1].payload, testCase.query)
+	}
+}
+
+func TestExecutionPanic(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	var written bytes.Buffer
+	serverConn, clientConn := net.Pipe()
+	t.Cleanup(func() { clientConn.Close() })
+	c := &conn{netConn: serverConn, writer: bufio.NewWriter(&written)}
+
+	c.execute(newWireResponseWriter(c, nil, true), func() { panic("boom") })
+	require.NoError(t, c.writer.Flush())
+
+	// The details of the panic are logged rather than sent to the client
+	typ, message, err := readMessage(&written)
+	require.NoError(t, err)
+	assert.Equal(t, messageTypeErrorResponse, typ)
+	assert.Contains(t, string(message.payload), "internal error")
+	assert.NotContains(t, string(message.payload), "boom")
+	assert.Contains(t, logged.String(), "boom")
+	assert.Contains(t, logged.String(), "goroutine")
+}
+
+func TestTerminate(t *testing.T) {
+	client := newTestClient(t)
+	client.sendStartup()
+	client.readUntilReady()
+	client.send(messageTypeTerminate, nil)
+
+	_, err := client.reader.ReadByte()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestDisconnectInTransaction(t *testing.T) {
+	e := engine.NewDefaultEngine()
+	require.NoError(t, e.QueryError(protocol.Request{Query: "CREATE TABLE t (id integer PRIMARY KEY)"}))
+
+	client := newTestClientForServer(t, NewServer(e))
+	client.sendStartup()
+	client.readUntilReady()
+	client.sendQuery("BEGIN; INSERT INTO t (id) VALUES (1); LOCK TABLE t IN ACCESS EXCLUSIVE MODE;")
+	client.readUntilReady()
+	require.NoError(t, client.conn.Close())
+
+	// The open transaction block is rolled back and its locks are released
+	rows, err := e.QueryRows(protocol.Request{Query: "SELECT id FROM t"})
+	require.NoError(t, err)
+	assert.Empty(t, rows.Values)
+}
+
+//
+//
+
+type testClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+type testMessage struct {
+	typ     byte
+	payload []byte
+}
+
+func newTestClient(t *testing.T) *testClient {
+	return newTestClientForServer(t, NewServer(engine.NewDefaultEngine()))
+}
+
+func newTestClientForServer(t *testing.T, s *Server) *testClient {
+	clientConn, serverConn := net.Pipe()
+	t.Cleanup(func() { clientConn.Close() })
+
+	go func() { _ = s.ServeConn(serverConn) }()
+
+	return &testClient{
+		t:      t,
+		conn:   clientConn,
+		reader: bufio.NewReader(clientConn),
+	}
+}
+
+func (c *testClient) sendSSLRequest() {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, int32(8))
+	_ = binary.Write(&buf, binary.BigEndian, int32(protocolSSLCode))
+	c.write(buf.Bytes())
+
+	response, err := c.reader.ReadByte()
+	require.NoError(c.t, err)
+	require.Equal(c.t, byte('N'), response)
+}
+
+func (c *testClient) sendStartup() {
+	var payload bytes.Buffer
+	_ = binary.Write(&payload, binary.BigEndian, int32(protocolVersion3))
+	payload.WriteString("user\x00test\x00database\x00test\x00\x00")
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, int32(payload.Len()+4))
+	buf.Write(payload.Bytes())
+	c.write(buf.Bytes())
+}
+
+func (c *testClient) sendQuery(query string) {
+	c.send(messageTypeQuery, append([]byte(query), 0))
+}
+
+func (c *testClient) sendParse(name, query string, parameterOIDs ...int32) {
+	m := newMessage(messageTypeParse).cstring(name).cstring(query).int16(int16(len(parameterOIDs)))
+	for _, oid := range parameterOIDs {
+		m.int32(oid)
+	}
+	c.write(m.finish())
+}
+
+func (c *testClient) sendBind(portal, statement string, parameterFormats []int16, parameters [][]byte, resultFormats []int16) {
+	m := newMessage(messageTypeBind).cstring(portal).cstring(statement)
+	m.int16(int16(len(parameterFormats)))
+	for _, format := range parameterFormats {
+		m.int16(format)
+	}
+	m.int16(int16(len(parameters)))
+	for _, parameter := range parameters {
+		if parameter == nil {
+			m.int32(-1)
+			continue
+		}
+		m.int32(int32(len(parameter))).bytes(parameter)
+	}
+	m.int16(int16(len(resultFormats)))
+	for _, format := range resultFormats {
+		m.int16(format)
+	}
+	c.write(m.finish())
+}
+
+func (c *testClient) sendDescribe(target byte, name string) {
+	c.write(newMessage(messageTypeDescribe).byte(target).cstring(name).finish())
+}
+
+func (c *testClient) sendExecute(portal string) {
+	c.write(newMessage(messageTypeExecute).cstring(portal).int32(0).finish())
+}
+
+func (c *testClient) sendClose(target byte, name string) {
+	c.write(newMessage(messageTypeClose).byte(target).cstring(name).finish())
+}
+
+func (c *testClient) sendSync() {
+	c.send(messageTypeSync, nil)
+}
+
+func (c *testClient) send(typ byte, payload []byte) {
+	c.write(newMessage(typ).bytes(payload).finish())
+}
+
+func (c *testClient) write(data []byte) {
+	_, err := c.conn.Write(data)
+	require.NoError(c.t, err)
+}
+
+func (c *testClient) readUntilReady() (messages []testMessage) {
+	for {
+		typ, message, err := readMessage(c.reader)
+		require.NoError(c.t, err)
+
+		messages = append(messages, testMessage{typ: typ, payload: message.payload})
+		if typ == messageTypeReadyForQuery {
+			return messages
+		}
+	}
+}
+
+// summarizeMessage returns a compact, comparable representation of a backend message.
+func summarizeMessage(t *testing.T, message testMessage) string {
+	r := newMessageReader(message.payload)
+	must := func(v int32, err error) int32 { require.NoError(t, err); return v }
+	must16 := func(v int16, err error) int16 { require.NoError(t, err); return v }
+
+	switch message.typ {
+	case messageTypeRowDescription:
+		var columns []string
+		n := must16(r.int16())
+		for i := 0; i < int(n); i++ {
+			name, err := r.cstring()
+			require.NoError(t, err)
+			_ = must(r.int32())
+			_ = must16(r.int16())
+			oid := must(r.int32())
+			_ = must16(r.int16())
+			_ = must(r.int32())
+			_ = must16(r.int16())
+			columns = append(columns, fmt.Sprintf("%s/%d", name, oid))
+		}
+		return "T:" + strings.Join(columns, ",")
+
+	case messageTypeDataRow:
+		var values []string
+		n := must16(r.int16())
+		for i := 0; i < int(n); i++ {
+			length := must(r.int32())
+			if length < 0 {
+				values = append(values, "NULL")
+				continue
+			}
+
+			value, err := r.bytes(int(length))
+			require.NoError(t, err)
+			values = append(values, strings.Trim(strconv.Quote(string(value)), `"`))
+		}
+		return "D:" + strings.Join(values, "|")
+
+	case messageTypeParameterDescription:
+		var oids []string
+		n := must16(r.int16())
+		for i := 0; i < int(n); i++ {
+			oids = append(oids, strconv.Itoa(int(must(r.int32()))))
+		}
+		return "t:" + strings.Join(oids, ",")
+
+	case messageTypeCommandComplete:
+		tag, err := r.cstring()
+		require.NoError(t, err)
+		return "C:" + tag
+	}
+
+	return string(message.typ)
+}
@@ -0,0 +1,588 @@
+package server
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+// See https://github.com/postgres/postgres/blob/master/src/include/catalog/pg_type.dat
+const (
+	oidBool        int32 = 16
+	oidBytea       int32 = 17
+	oidInt8        int32 = 20
+	oidInt2        int32 = 21
+	oidInt4        int32 = 23
+	oidText        int32 = 25
+	oidFloat4      int32 = 700
+	oidFloat8      int32 = 701
+	oidUnknown     int32 = 705
+	oidBPChar      int32 = 1042
+	oidVarchar     int32 = 1043
+	oidDate        int32 = 1082
+	oidTime        int32 = 1083
+	oidTimestamp   int32 = 1114
+	oidTimestampTz int32 = 1184
+	oidInterval    int32 = 1186
+	oidNumeric     int32 = 1700
+	oidUUID        int32 = 2950
+	oidJSONB       int32 = 3802
+)
+
+// arrayOIDs are the OIDs of the array types of each element type.
+var arrayOIDs = map[types.Type]int32{
+	types.TypeBool:            1000,
+	types.TypeBytea:           1001,
+	types.TypeSmallInteger:    1005,
+	types.TypeInteger:         1007,
+	types.TypeText:            1009,
+	types.TypeCharacter:       1014,
+	types.TypeBigInteger:      1016,
+	types.TypeReal:            1021,
+	types.TypeDoublePrecision: 1022,
+	types.TypeTimestamp:       1115,
+	types.TypeDate:            1182,
+	types.TypeTime:            1183,
+	types.TypeTimestampTz:     1185,
+	types.TypeInterval:        1187,
+	types.TypeNumeric:         1231,
+	types.TypeUUID:            2951,
+	types.TypeJSONB:           3807,
+}
+
+// Format codes used for parameter and result values
+const (
+	formatText   int16 = 0
+	formatBinary int16 = 1
+)
+
+type typeDescription struct {
+	oid  int32
+	size int16 // -1 for variable-width types
+}
+
+func describeType(typ types.Type) typeDescription {
+	switch typ {
+	case types.TypeText:
+		return typeDescription{oid: oidText, size: -1}
+	case types.TypeSmallInteger:
+		return typeDescription{oid: oidInt2, size: 2}
+	case types.TypeInteger:
+		return typeDescription{oid: oidInt4, size: 4}
+	case types.TypeBigInteger:
+		return typeDescription{oid: oidInt8, size: 8}
+	case types.TypeReal:
+		return typeDescription{oid: oidFloat4, size: 4}
+	case types.TypeDoublePrecision:
+		return typeDescription{oid: oidFloat8, size: 8}
+	case types.TypeNumeric:
+		return typeDescription{oid: oidNumeric, size: -1}
+	case types.TypeBool:
+		return typeDescription{oid: oidBool, size: 1}
+	case types.TypeTimestampTz:
+		return typeDescription{oid: oidTimestampTz, size: 8}
+	case types.TypeDate:
+		return typeDescription{oid: oidDate, size: 4}
+	case types.TypeTime:
+		return typeDescription{oid: oidTime, size: 8}
+	case types.TypeTimestamp:
+		return typeDescription{oid: oidTimestamp, size: 8}
+	case types.TypeInterval:
+		return typeDescription{oid: oidInterval, size: 16}
+	case types.TypeUUID:
+		return typeDescription{oid: oidUUID, size: 16}
+	case types.TypeBytea:
+		return typeDescription{oid: oidBytea, size: -1}
+	case types.TypeJSONB:
+		return typeDescription{oid: oidJSONB, size: -1}
+	case types.TypeCharacter:
+		return typeDescription{oid: oidBPChar, size: -1}
+	}
+
+	if oid, ok := arrayOIDs[typ.ElementType()]; ok && typ.IsArray() {
+		return typeDescription{oid: oid, size: -1}
+	}
+
+	// Text-encoded values of unknown type are interpreted by clients as strings
+	return typeDescription{oid: oidUnknown, size: -2}
+}
+
+// typeFromOID returns the type corresponding to the given OID. Unspecified (zero)
+// and unrecognized OIDs map to types.TypeUnknown so that the type of the parameter
+// is inferred from the statement.
+func typeFromOID(oid int32) types.Type {
+	switch oid {
+	case oidText, oidVarchar, oidBPChar:
+		return types.TypeText
+	case oidInt2:
+		return types.TypeSmallInteger
+	case oidInt4:
+		return types.TypeInteger
+	case oidInt8:
+		return types.TypeBigInteger
+	case oidFloat4:
+		return types.TypeReal
+	case oidFloat8:
+		return types.TypeDoublePrecision
+	case oidNumeric:
+		return types.TypeNumeric
+	case oidBool:
+		return types.TypeBool
+	case oidTimestampTz:
+		return types.TypeTimestampTz
+	case oidDate:
+		return types.TypeDate
+	case oidTime:
+		return types.TypeTime
+	case oidTimestamp:
+		return types.TypeTimestamp
+	case oidInterval:
+		return types.TypeInterval
+	case oidUUID:
+		return types.TypeUUID
+	case oidBytea:
+		return types.TypeBytea
+	case oidJSONB:
+		return types.TypeJSONB
+	}
+
+	for elementType, arrayOID := range arrayOIDs {
+		if oid == arrayOID {
+			return types.NewArrayType(elementType)
+		}
+	}
+
+	return types.TypeUnknown
+}
+
+// formatForColumn returns the format code that applies to the i-th of n values
+// given the format codes sent by the client (zero, one, or one per value).
+func formatForColumn(formats []int16, i int) int16 {
+	switch len(formats) {
+	case 0:
+		return formatText
+	case 1:
+		return formats[0]
+	}
+
+	if i < len(formats) {
+		return formats[i]
+	}
+
+	return formatText
+}
+
+//
+//
+
+// postgresEpoch is the reference point of binary-encoded timestamps and dates.
+var postgresEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	postgresEpochDate      = types.NewDate(postgresEpoch)
+	postgresEpochTimestamp = types.NewTimestamp(postgresEpoch)
+)
+
+// jsonbBinaryVersion prefixes the text of binary-encoded jsonb values.
+const jsonbBinaryVersion byte = 1
+
+// encodeValue converts a value into its representation in the given format.
+// A nil return value indicates a SQL NULL.
+func encodeValue(value any, format int16) ([]byte, error) {
+	if format == formatBinary {
+		return encodeBinary(value)
+	}
+
+	return encodeText(value), nil
+}
+
+func encodeText(value any) []byte {
+	if value == nil {
+		return nil
+	}
+
+	return []byte(types.FormatText(value))
+}
+
+func encodeBinary(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(v), nil
+	case bool:
+		if v {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case int16:
+		return binary.BigEndian.AppendUint16(nil, uint16(v)), nil
+	case int32:
+		return binary.BigEndian.AppendUint32(nil, uint32(v)), nil
+	case int64:
+		return binary.BigEndian.AppendUint64(nil, uint64(v)), nil
+	case float32:
+		return binary.BigEndian.AppendUint32(nil, math.Float32bits(v)), nil
+	case float64:
+		return binary.BigEndian.AppendUint64(nil, math.Float64bits(v)), nil
+	case *big.Float:
+		return encodeBinaryNumeric(v), nil
+	case time.Time:
+		return binary.BigEndian.AppendUint64(nil, uint64(v.Sub(postgresEpoch).Microseconds())), nil
+	case types.Date:
+		return binary.BigEndian.AppendUint32(nil, uint32(v-postgresEpochDate)), nil
+	case types.Time:
+		return binary.BigEndian.AppendUint64(nil, uint64(v)), nil
+	case types.Timestamp:
+		return binary.BigEndian.AppendUint64(nil, uint64(v-postgresEpochTimestamp)), nil
+	case types.Interval:
+		data := binary.BigEndian.AppendUint64(nil, uint64(v.Microseconds))
+		data = binary.BigEndian.AppendUint32(data, uint32(v.Days))
+		return binary.BigEndian.AppendUint32(data, uint32(v.Months)), nil
+	case types.UUID:
+		return v[:], nil
+	case types.Bytea:
+		return []byte(v), nil
+	case types.JSONB:
+		return append([]byte{jsonbBinaryVersion}, v...), nil
+	case types.Character:
+		return []byte(v), nil
+	case types.Array:
+		return encodeBinaryArray(v)
+	}
+
+	return nil, fmt.Errorf("unsupported binary encoding for %T", value)
+}
+
+// decodeValue converts a parameter value sent by the client in the given format
+// into a value of the given type. A nil input indicates a SQL NULL.
+func decodeValue(data []byte, typ types.Type, format int16) (any, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	if format == formatBinary {
+		return decodeBinary(data, typ)
+	}
+
+	return decodeText(string(data), typ)
+}
+
+func decodeText(text string, typ types.Type) (any, error) {
+	return types.ParseText(text, typ)
+}
+
+func decodeBinary(data []byte, typ types.Type) (any, error) {
+	expectLength := func(n int) error {
+		if len(data) != n {
+			return fmt.Errorf("invalid binary value for type %s (expected %d bytes, have %d)", typ, n, len(data))
+		}
+
+		return nil
+	}
+
+	switch typ {
+	case types.TypeSmallInteger:
+		if err := expectLength(2); err != nil {
+			return nil, err
+		}
+		return int16(binary.BigEndian.Uint16(data)), nil
+	case types.TypeInteger:
+		if err := expectLength(4); err != nil {
+			return nil, err
+		}
+		return int32(binary.BigEndian.Uint32(data)), nil
+	case types.TypeBigInteger:
+		if err := expectLength(8); err != nil {
+			return nil, err
+		}
+		return int64(binary.BigEndian.Uint64(data)), nil
+	case types.TypeReal:
+		if err := expectLength(4); err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(data)), nil
+	case types.TypeDoublePrecision:
+		if err := expectLength(8); err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
+	case types.TypeNumeric:
+		return decodeBinaryNumeric(data)
+	case types.TypeBool:
+		if err := expectLength(1); err != nil {
+			return nil, err
+		}
+		return data[0] != 0, nil
+	case types.TypeTimestampTz:
+		if err := expectLength(8); err != nil {
+			return nil, err
+		}
+		return postgresEpoch.Add(time.Duration(int64(binary.BigEndian.Uint64(data))) * time.Microsecond), nil
+	case types.TypeDate:
+		if err := expectLength(4); err != nil {
+			return nil, err
+		}
+		return postgresEpochDate + types.Date(int32(binary.BigEndian.Uint32(data))), nil
+	case types.TypeTime:
+		if err := expectLength(8); err != nil {
+			return nil, err
+		}
+		return types.Time(int64(binary.BigEndian.Uint64(data))), nil
+	case types.TypeTimestamp:
+		if err := expectLength(8); err != nil {
+			return nil, err
+		}
+		return postgresEpochTimestamp + types.Timestamp(int64(binary.BigEndian.Uint64(data))), nil
+	case types.TypeInterval:
+		if err := expectLength(16); err != nil {
+			return nil, err
+		}
+		return types.Interval{
+			Microseconds: int64(binary.BigEndian.Uint64(data[0:8])),
+			Days:         int32(binary.BigEndian.Uint32(data[8:12])),
+			Months:       int32(binary.BigEndian.Uint32(data[12:16])),
+		}, nil
+	case types.TypeUUID:
+		if err := expectLength(16); err != nil {
+			return nil, err
+		}
+		return types.UUID(data), nil
+	case types.TypeBytea:
+		return types.Bytea(data), nil
+	case types.TypeCharacter:
+		return types.Character(data), nil
+	case types.TypeJSONB:
+		if len(data) == 0 || data[0] != jsonbBinaryVersion {
+			return nil, fmt.Errorf("unsupported jsonb binary format")
+		}
+		return types.ParseText(string(data[1:]), typ)
+	}
+
+	if typ.IsArray() {
+		return decodeBinaryArray(data, typ.ElementType())
+	}
+
+	return string(data), nil
+}
+
+//
+//
+
+// Binary arrays begin with a header holding the number of dimensions, a flag set if
+// any element is NULL, and the OID of the element type, followed by the length and
+// lower bound of each dimension. Each element is then written as its length (or -1
+// for NULL) followed by its binary encoding.
+
+func encodeBinaryArray(v types.Array) ([]byte, error) {
+	elementOID := describeType(v.ElementType()).oid
+
+	dimensions, hasNulls := uint32(0), uint32(0)
+	if v.Len() > 0 {
+		dimensions = 1
+	}
+	for _, element := range v.Elements() {
+		if element == nil {
+			hasNulls = 1
+		}
+	}
+
+	data := binary.BigEndian.AppendUint32(nil, dimensions)
+	data = binary.BigEndian.AppendUint32(data, hasNulls)
+	data = binary.BigEndian.AppendUint32(data, uint32(elementOID))
+	if dimensions > 0 {
+		data = binary.BigEndian.AppendUint32(data, uint32(v.Len()))
+		data = binary.BigEndian.AppendUint32(data, 1)
+	}
+
+	for _, element := range v.Elements() {
+		if element == nil {
+			data = binary.BigEndian.AppendUint32(data, math.MaxUint32)
+			continue
+		}
+
+		encoded, err := encodeBinary(element)
+		if err != nil {
+			return nil, err
+		}
+
+		data = binary.BigEndian.AppendUint32(data, uint32(len(encoded)))
+		data = append(data, encoded...)
+	}
+
+	return data, nil
+}
+
+func decodeBinaryArray(data []byte, elementType types.Type) (any, error) {
+	invalid := fmt.Errorf("invalid binary value for type %s", types.NewArrayType(elementType))
+
+	readUint32 := func() (uint32, error) {
+		if len(data) < 4 {
+			return 0, invalid
+		}
+
+		v := binary.BigEndian.Uint32(data)
+		data = data[4:]
+		return v, nil
+	}
+
+	var header [3]uint32
+	for i := range header {
+		v, err := readUint32()
+		if err != nil {
+			return nil, err
+		}
+		header[i] = v
+	}
+
+	switch dimensions := header[0]; dimensions {
+	case 0:
+		return types.NewArray(elementType, nil), nil
+	case 1:
+	default:
+		return nil, fmt.Errorf("multidimensional arrays are not supported")
+	}
+
+	length, err := readUint32()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := readUint32(); err != nil {
+		return nil, err
+	}
+
+	elements := make([]any, 0, length)
+	for i := uint32(0); i < length; i++ {
+		elementLength, err := readUint32()
+		if err != nil {
+			return nil, err
+		}
+
+		if int32(elementLength) == -1 {
+			elements = append(elements, nil)
+			continue
+		}
+		if uint32(len(data)) < elementLength {
+			return nil, invalid
+		}
+
+		element, err := decodeBinary(data[:elementLength], elementType)
+		if err != nil {
+			return nil, err
+		}
+
+		elements = append(elements, element)
+		data = data[elementLength:]
+	}
+
+	return types.NewArray(elementType, elements), nil
+}
+
+//
+//
+
+// Binary numerics are encoded as a sequence of base-10000 digits. The header holds
+// the number of digits, the weight (power of 10000) of the first digit, the sign,
+// and the number of decimal digits after the decimal point.
+const (
+	numericPositive = 0x0000
+	numericNegative = 0x4000
+	numericNaN      = 0xC000
+)
+
+func encodeBinaryNumeric(v *big.Float) []byte {
+	text := v.Text('f', -1)
+
+	sign := uint16(numericPositive)
+	if strings.HasPrefix(text, "-") {
+		sign = numericNegative
+		text = text[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(text, ".")
+	intPart = strings.TrimLeft(intPart, "0")
+	dscale := len(fracPart)
+
+	// Pad both parts out to a multiple of four digits
+	intPart = strings.Repeat("0", (4-len(intPart)%4)%4) + intPart
+	fracPart = fracPart + strings.Repeat("0", (4-len(fracPart)%4)%4)
+
+	var digits []uint16
+	for i := 0; i < len(intPart); i += 4 {
+		d, _ := strconv.Atoi(intPart[i : i+4])
+		digits = append(digits, uint16(d))
+	}
+	weight := len(digits) - 1
+	for i := 0; i < len(fracPart); i += 4 {
+		d, _ := strconv.Atoi(fracPart[i : i+4])
+		digits = append(digits, uint16(d))
+	}
+
+	for len(digits) > 0 && digits[0] == 0 {
+		digits = digits[1:]
+		weight--
+	}
+	for len(digits) > 0 && digits[len(digits)-1] == 0 {
+		digits = digits[:len(digits)-1]
+	}
+	if len(digits) == 0 {
+		weight = 0
+	}
+
+	data := make([]byte, 0, 8+2*len(digits))
+	data = binary.BigEndian.AppendUint16(data, uint16(len(digits)))
+	data = binary.BigEndian.AppendUint16(data, uint16(int16(weight)))
+	data = binary.BigEndian.AppendUint16(data, sign)
+	data = binary.BigEndian.AppendUint16(data, uint16(dscale))
+	for _, d := range digits {
+		data = binary.BigEndian.AppendUint16(data, d)
+	}
+
+	return data
+}
+
+func decodeBinaryNumeric(data []byte) (any, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("invalid binary value for type numeric")
+	}
+
+	ndigits := int(binary.BigEndian.Uint16(data[0:2]))
+	weight := int(int16(binary.BigEndian.Uint16(data[2:4])))
+	sign := binary.BigEndian.Uint16(data[4:6])
+
+	if sign == numericNaN {
+		return nil, fmt.Errorf("numeric NaN is not supported")
+	}
+	if len(data) != 8+2*ndigits {
+		return nil, fmt.Errorf("invalid binary value for type numeric")
+	}
+
+	value := new(big.Float)
+	base := big.NewFloat(10000)
+	for i := 0; i < ndigits; i++ {
+		d := binary.BigEndian.Uint16(data[8+2*i:])
+		value.Mul(value, base)
+		value.Add(value, big.NewFloat(float64(d)))
+	}
+
+	// Scale the accumulated integer by 10000^(weight - ndigits + 1)
+	exponent := weight - ndigits + 1
+	for ; exponent > 0; exponent-- {
+		value.Mul(value, base)
+	}
+	for ; exponent < 0; exponent++ {
+		value.Quo(value, base)
+	}
+
+	if sign == numericNegative {
+		value.Neg(value)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	for _, testCase := range []struct {
+		name  string
+		typ   types.Type
+		value any
+	}{
+		{name: "smallint", typ: types.TypeSmallInteger, value: int16(-12)},
+		{name: "integer", typ: types.TypeInteger, value: int32(123456)},
+		{name: "bigint", typ: types.TypeBigInteger, value: int64(-1 << 40)},
+		{name: "real", typ: types.TypeReal, value: float32(1.5)},
+		{name: "double precision", typ: types.TypeDoublePrecision, value: float64(-2.25)},
+		{name: "bool", typ: types.TypeBool, value: true},
+		{name: "text", typ: types.TypeText, value: "hello"},
+		{name: "character", typ: types.TypeCharacter, value: types.Character("ab  ")},
+		{name: "timestamptz", typ: types.TypeTimestampTz, value: time.Date(2024, 3, 1, 12, 30, 0, 123000, time.UTC)},
+		{name: "timestamptz before epoch", typ: types.TypeTimestampTz, value: time.Date(1999, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{name: "date", typ: types.TypeDate, value: types.NewDate(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))},
+		{name: "date before epoch", typ: types.TypeDate, value: types.NewDate(time.Date(1969, 7, 20, 0, 0, 0, 0, time.UTC))},
+		{name: "time", typ: types.TypeTime, value: types.Time(45296789012)},
+		{name: "timestamp", typ: types.TypeTimestamp, value: types.NewTimestamp(time.Date(2024, 3, 1, 12, 30, 0, 123000, time.UTC))},
+		{name: "interval", typ: types.TypeInterval, value: types.Interval{Months: 14, Days: -3, Microseconds: 3723000000}},
+		{name: "uuid", typ: types.TypeUUID, value: types.UUID{0xa0, 0xee, 0xbc, 0x99, 0x9c, 0x0b, 0x4e, 0xf8, 0xbb, 0x6d, 0x6b, 0xb9, 0xbd, 0x38, 0x0a, 0x11}},
+		{name: "bytea", typ: types.TypeBytea, value: types.Bytea([]byte{0, 1, 0xfe, 0xff})},
+		{name: "jsonb", typ: types.TypeJSONB, value: types.JSONB(`{"a": [1, null]}`)},
+		{name: "integer array", typ: types.NewArrayType(types.TypeInteger), value: types.NewArray(types.TypeInteger, []any{int32(1), nil, int32(-3)})},
+		{name: "text array", typ: types.NewArrayType(types.TypeText), value: types.NewArray(types.TypeText, []any{"a", "b c"})},
+		{name: "empty array", typ: types.NewArrayType(types.TypeBigInteger), value: types.NewArray(types.TypeBigInteger, nil)},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			encoded, err := encodeBinary(testCase.value)
+			require.NoError(t, err)
+
+			decoded, err := decodeBinary(encoded, testCase.typ)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.value, decoded)
+		})
+	}
+}
+
+func TestBinaryNumeric(t *testing.T) {
+	for _, testCase := range []struct {
+		text    string
+		encoded []byte
+	}{
+		{text: "0", encoded: []byte{0, 0, 0, 0, 0, 0, 0, 0}},
+		{text: "1", encoded: []byte{0, 1, 0, 0, 0, 0, 0, 0, 0, 1}},
+		{text: "12345.678", encoded: []byte{0, 3, 0, 1, 0, 0, 0, 3, 0, 1, 0x09, 0x29, 0x1A, 0x7C}},
+		{text: "-0.5", encoded: []byte{0, 1, 0xFF, 0xFF, 0x40, 0, 0, 1, 0x13, 0x88}},
+		{text: "10000", encoded: []byte{0, 1, 0, 1, 0, 0, 0, 0, 0, 1}},
+	} {
+		t.Run(testCase.text, func(t *testing.T) {
+			value, ok := new(big.Float).SetString(testCase.text)
+			require.True(t, ok)
+			assert.Equal(t, testCase.encoded, encodeBinaryNumeric(value))
+
+			decoded, err := decodeBinaryNumeric(testCase.encoded)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.text, decoded.(*big.Float).Text('f', -1))
+		})
+	}
+}
+
+func TestDecodeText(t *testing.T) {
+	for _, testCase := range []struct {
+		text     string
+		typ      types.Type
+		expected any
+	}{
+		{text: "42", typ: types.TypeInteger, expected: int32(42)},
+		{text: "-7", typ: types.TypeBigInteger, expected: int64(-7)},
+		{text: "2.5", typ: types.TypeDoublePrecision, expected: float64(2.5)},
+		{text: "yes", typ: types.TypeBool, expected: true},
+		{text: "2024-03-01 12:30:00+00", typ: types.TypeTimestampTz, expected: time.Date(2024, 3, 1, 12, 30, 0, 0, time.FixedZone("", 0))},
+		{text: "abc", typ: types.TypeText, expected: "abc"},
+	} {
+		t.Run(testCase.text, func(t *testing.T) {
+			value, err := decodeText(testCase.text, testCase.typ)
+			require.NoError(t, err)
+
+			if expected, ok := testCase.expected.(time.Time); ok {
+				assert.True(t, expected.Equal(value.(time.Time)))
+				return
+			}
+
+			assert.Equal(t, testCase.expected, value)
+		})
+	}
+
+	_, err := decodeText("nope", types.TypeInteger)
+	assert.Error(t, err)
+}
@@ -17,29 +17,32 @@ type Field struct {
 	relationName      string
 	name              string
 	typ               types.Type
+	typeModifier      types.TypeModifier
 	internalFieldType InternalFieldType
 }
 
 var TIDField = NewField("", "tid", types.TypeBigInteger, InternalFieldTid)
 
 func NewField(relationName, name string, typ types.Type, internalFieldType InternalFieldType) Field {
-	return newField(relationName, name, typ, internalFieldType)
+	return newField(relationName, name, typ, types.TypeModifier{}, internalFieldType)
 }
 
-func newField(relationName, name string, typ types.Type, internalFieldType InternalFieldType) Field {
+func newField(relationName, name string, typ types.Type, typeModifier types.TypeModifier, internalFieldType InternalFieldType) Field {
 	return Field{
 		relationName:      relationName,
 		name:              name,
 		typ:               typ,
+		typeModifier:      typeModifier,
 		internalFieldType: internalFieldType,
 	}
 }
 
-func (f Field) RelationName() string { return f.relationName }
-func (f Field) Name() string         { return f.name }
-func (f Field) Type() types.Type     { return f.typ }
-func (f Field) Internal() bool       { return f.internalFieldType != NonInternalField }
-func (f Field) IsTID() bool          { return f.internalFieldType == InternalFieldTid }
+func (f Field) RelationName() string             { return f.relationName }
+func (f Field) Name() string                     { return f.name }
+func (f Field) Type() types.Type                 { return f.typ }
+func (f Field) TypeModifier() types.TypeModifier { return f.typeModifier }
+func (f Field) Internal() bool                   { return f.internalFieldType != NonInternalField }
+func (f Field) IsTID() bool                      { return f.internalFieldType == InternalFieldTid }
 
 func (f Field) String() string {
 	if f.relationName == "" {
@@ -50,15 +53,19 @@ func (f Field) String() string {
 }
 
 func (f Field) WithRelationName(relationName string) Field {
-	return newField(relationName, f.name, f.typ, f.internalFieldType)
+	return newField(relationName, f.name, f.typ, f.typeModifier, f.internalFieldType)
 }
 
 func (f Field) WithName(alias string) Field {
-	return newField(f.relationName, alias, f.typ, f.internalFieldType)
+	return newField(f.relationName, alias, f.typ, f.typeModifier, f.internalFieldType)
 }
 
 func (f Field) WithType(typ types.Type) Field {
-	return newField(f.relationName, f.name, typ, f.internalFieldType)
+	return newField(f.relationName, f.name, typ, f.typeModifier, f.internalFieldType)
+}
+
+func (f Field) WithTypeModifier(typeModifier types.TypeModifier) Field {
+	return newField(f.relationName, f.name, f.typ, typeModifier, f.internalFieldType)
 }
 
 func FindMatchingFieldIndex(needle Field, haystack []Field) (int, error) {
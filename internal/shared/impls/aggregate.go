@@ -4,4 +4,7 @@ type Aggregate interface {
 	Callable
 	Step(ctx ExecutionContext, state any, args []any) (any, error)
 	Done(ctx ExecutionContext, state any) (any, error)
+
+	// Strict returns true if the aggregate ignores rows with a NULL argument.
+	Strict() bool
 }
@@ -44,7 +44,7 @@ func (c callable) ValidateArgTypes(argTypes []types.Type) error {
 	}
 
 	for i, expectedType := range c.paramTypes {
-		if expectedType != types.TypeAny && argTypes[i] != expectedType && !(argTypes[i].IsNumber() && expectedType.IsNumber()) {
+		if !types.CanCast(argTypes[i], expectedType, types.CastContextImplicit) {
 			return fmt.Errorf("argument %d to %s expects type %s, got %s", i+1, c.name, expectedType, argTypes[i])
 		}
 	}
@@ -54,7 +54,13 @@ func (c callable) ValidateArgTypes(argTypes []types.Type) error {
 
 func (c callable) RefineArgValues(args []any) ([]any, error) {
 	var ts []types.Type
-	for _, arg := range args {
+	for i, arg := range args {
+		if arg == nil && i < len(c.paramTypes) {
+			// NULL is a value of every type
+			ts = append(ts, c.paramTypes[i])
+			continue
+		}
+
 		ts = append(ts, types.TypeKindFromValue(arg))
 	}
 
@@ -1,12 +1,27 @@
 package impls
 
-import "github.com/efritz/gostgres/internal/catalog"
+import (
+	"sync/atomic"
+
+	"github.com/efritz/gostgres/internal/catalog"
+)
 
 type CatalogSet struct {
-	Tables     *catalog.Catalog[Table]
-	Sequences  *catalog.Catalog[Sequence]
-	Functions  *catalog.Catalog[Function]
-	Aggregates *catalog.Catalog[Aggregate]
+	Tables             *catalog.Catalog[Table]
+	Sequences          *catalog.Catalog[Sequence]
+	Functions          *catalog.Catalog[Function]
+	Aggregates         *catalog.Catalog[Aggregate]
+	WindowFunctions    *catalog.Catalog[WindowFunction]
+	TableFunctions     *catalog.Catalog[TableFunction]
+	TableAccessMethods *catalog.Catalog[TableAccessMethod]
+
+	// DefaultTableAccessMethod names the access method of tables created without an
+	// explicit access method.
+	DefaultTableAccessMethod string
+
+	// version is incremented by every schema change so that plans built against an
+	// earlier version of the catalog can be detected and rebuilt
+	version *atomic.Int64
 }
 
 func NewCatalogEmptySet() CatalogSet {
@@ -15,6 +30,10 @@ func NewCatalogEmptySet() CatalogSet {
 		catalog.NewCatalog[Sequence](),
 		catalog.NewCatalog[Function](),
 		catalog.NewCatalog[Aggregate](),
+		catalog.NewCatalog[WindowFunction](),
+		catalog.NewCatalog[TableFunction](),
+		catalog.NewCatalog[TableAccessMethod](),
+		"",
 	)
 }
 
@@ -23,11 +42,37 @@ func NewCatalogSet(
 	sequences *catalog.Catalog[Sequence],
 	functions *catalog.Catalog[Function],
 	aggregates *catalog.Catalog[Aggregate],
+	windowFunctions *catalog.Catalog[WindowFunction],
+	tableFunctions *catalog.Catalog[TableFunction],
+	tableAccessMethods *catalog.Catalog[TableAccessMethod],
+	defaultTableAccessMethod string,
 ) CatalogSet {
 	return CatalogSet{
-		Tables:     tables,
-		Sequences:  sequences,
-		Functions:  functions,
-		Aggregates: aggregates,
+		Tables:                   tables,
+		Sequences:                sequences,
+		Functions:                functions,
+		Aggregates:               aggregates,
+		WindowFunctions:          windowFunctions,
+		TableFunctions:           tableFunctions,
+		TableAccessMethods:       tableAccessMethods,
+		DefaultTableAccessMethod: defaultTableAccessMethod,
+		version:                  &atomic.Int64{},
+	}
+}
+
+// Version returns a number that changes whenever the schema of the catalog changes.
+func (c CatalogSet) Version() int64 {
+	if c.version == nil {
+		return 0
+	}
+
+	return c.version.Load()
+}
+
+// InvalidatePlans marks plans built against the current version of the catalog as
+// out of date.
+func (c CatalogSet) InvalidatePlans() {
+	if c.version != nil {
+		c.version.Add(1)
 	}
 }
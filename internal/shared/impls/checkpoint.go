@@ -0,0 +1,7 @@
+package impls
+
+// Checkpointer writes the committed state of a database to durable storage so that
+// it can be recovered without replaying the changes that preceded it.
+type Checkpointer interface {
+	Checkpoint() error
+}
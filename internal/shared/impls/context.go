@@ -3,10 +3,14 @@ package impls
 import (
 	"fmt"
 	"runtime"
+	"slices"
 	"strings"
+	"sync"
 
+	"github.com/efritz/gostgres/internal/catalog"
 	"github.com/efritz/gostgres/internal/shared/fields"
 	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/storage/wal"
 )
 
 type Cataloger interface {
@@ -19,6 +23,9 @@ type Cataloger interface {
 type ExpressionResolutionContext struct {
 	catalog                 CatalogSet
 	allowAggregateFunctions bool
+	allowWindowFunctions    bool
+	windows                 map[string]Window
+	parameterTypes          *ParameterTypes
 }
 
 func NewExpressionResolutionContext(catalog CatalogSet, allowAggregateFunctions bool) ExpressionResolutionContext {
@@ -36,16 +43,70 @@ func (ctx ExpressionResolutionContext) AllowAggregateFunctions() bool {
 	return ctx.allowAggregateFunctions
 }
 
+func (ctx ExpressionResolutionContext) AllowWindowFunctions() bool {
+	return ctx.allowWindowFunctions
+}
+
+// Window returns the window of the given name defined in the WINDOW clause of the
+// enclosing select.
+func (ctx ExpressionResolutionContext) Window(name string) (Window, bool) {
+	window, ok := ctx.windows[name]
+	return window, ok
+}
+
+// WithWindowFunctions returns a context in which window functions may be invoked over
+// the given named windows.
+func (ctx ExpressionResolutionContext) WithWindowFunctions(windows map[string]Window) ExpressionResolutionContext {
+	ctx.allowWindowFunctions = true
+	ctx.windows = windows
+	return ctx
+}
+
+// WithoutWindowFunctions returns a context in which window functions may not be invoked
+// (e.g., within the arguments of another window function).
+func (ctx ExpressionResolutionContext) WithoutWindowFunctions() ExpressionResolutionContext {
+	ctx.allowWindowFunctions = false
+	ctx.windows = nil
+	return ctx
+}
+
+func (ctx ExpressionResolutionContext) ParameterTypes() *ParameterTypes {
+	return ctx.parameterTypes
+}
+
+func (ctx ExpressionResolutionContext) WithParameterTypes(parameterTypes *ParameterTypes) ExpressionResolutionContext {
+	ctx.parameterTypes = parameterTypes
+	return ctx
+}
+
 //
 //
 
 type NodeResolutionContext struct {
-	catalog CatalogSet
-	Scopes  []Scope
+	catalog        CatalogSet
+	parameterTypes *ParameterTypes
+	Scopes         []Scope
+
+	// windows is non-nil while resolving the expressions of a select in which window
+	// functions may be invoked
+	windows map[string]Window
 }
 
 type Scope struct {
 	fields []fields.Field
+
+	// subquery is true for the outermost scope of a subquery, which records the fields
+	// of enclosing scopes referenced within it
+	subquery   bool
+	references []fields.Field
+
+	commonTables []CommonTable
+}
+
+// CommonTable is a relation defined by a WITH clause, which may be referenced by name
+// within the statement (or query) to which the clause is attached.
+type CommonTable interface {
+	CommonTableName() string
 }
 
 func NewNodeResolutionContext(catalog CatalogSet) *NodeResolutionContext {
@@ -58,8 +119,37 @@ func (ctx *NodeResolutionContext) Catalog() CatalogSet {
 	return ctx.catalog
 }
 
+func (ctx *NodeResolutionContext) WithParameterTypes(parameterTypes *ParameterTypes) *NodeResolutionContext {
+	ctx.parameterTypes = parameterTypes
+	return ctx
+}
+
+func (ctx *NodeResolutionContext) ParameterTypes() *ParameterTypes {
+	return ctx.parameterTypes
+}
+
 func (ctx *NodeResolutionContext) ExpressionResolutionContext(allowAggregateFunctions bool) ExpressionResolutionContext {
-	return NewExpressionResolutionContext(ctx.catalog, allowAggregateFunctions)
+	exprCtx := NewExpressionResolutionContext(ctx.catalog, allowAggregateFunctions).WithParameterTypes(ctx.parameterTypes)
+	if ctx.windows != nil {
+		exprCtx = exprCtx.WithWindowFunctions(ctx.windows)
+	}
+
+	return exprCtx
+}
+
+// WithWindowFunctions invokes the given function with a context in which expressions
+// may invoke window functions over the given named windows. Window functions may not be
+// invoked within subqueries resolved by the given function unless re-enabled.
+func (ctx *NodeResolutionContext) WithWindowFunctions(windows map[string]Window, f func() error) error {
+	if windows == nil {
+		windows = map[string]Window{}
+	}
+
+	previous := ctx.windows
+	ctx.windows = windows
+	defer func() { ctx.windows = previous }()
+
+	return f()
 }
 
 func (ctx *NodeResolutionContext) WithScope(f func() error) error {
@@ -69,6 +159,24 @@ func (ctx *NodeResolutionContext) WithScope(f func() error) error {
 	return f()
 }
 
+// WithSubqueryScope invokes the given function within a new scope for a subquery and
+// returns the fields of enclosing scopes that are referenced within the subquery.
+func (ctx *NodeResolutionContext) WithSubqueryScope(f func() error) ([]fields.Field, error) {
+	ctx.Scopes = append(ctx.Scopes, Scope{subquery: true})
+	index := len(ctx.Scopes) - 1
+	defer ctx.PopScope()
+
+	windows := ctx.windows
+	ctx.windows = nil
+	defer func() { ctx.windows = windows }()
+
+	if err := f(); err != nil {
+		return nil, err
+	}
+
+	return ctx.Scopes[index].references, nil
+}
+
 func (ctx *NodeResolutionContext) PushScope() {
 	ctx.Scopes = append(ctx.Scopes, Scope{})
 }
@@ -90,6 +198,26 @@ func (ctx *NodeResolutionContext) Bind(fields []fields.Field) {
 	scope.fields = append(scope.fields, fields...)
 }
 
+// BindCommonTable makes the given common table visible within the current scope.
+func (ctx *NodeResolutionContext) BindCommonTable(table CommonTable) {
+	scope := ctx.CurrentScope()
+	scope.commonTables = append(scope.commonTables, table)
+}
+
+// LookupCommonTable returns the common table with the given name bound in the innermost
+// scope, if any.
+func (ctx *NodeResolutionContext) LookupCommonTable(name string) (CommonTable, bool) {
+	for i := len(ctx.Scopes) - 1; i >= 0; i-- {
+		for _, table := range ctx.Scopes[i].commonTables {
+			if table.CommonTableName() == name {
+				return table, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
 func (ctx *NodeResolutionContext) Lookup(relationName, name string) (fields.Field, error) {
 	qualifiedSearchName := fmt.Sprintf("%q", name)
 	if relationName != "" {
@@ -107,6 +235,7 @@ func (ctx *NodeResolutionContext) Lookup(relationName, name string) (fields.Fiel
 		}
 
 		if len(candidates) == 1 {
+			ctx.reference(i, candidates[0])
 			return candidates[0], nil
 		}
 
@@ -118,14 +247,29 @@ func (ctx *NodeResolutionContext) Lookup(relationName, name string) (fields.Fiel
 	return fields.Field{}, fmt.Errorf("unknown field %s", qualifiedSearchName)
 }
 
+// reference records the given field, bound in the scope with the given index, as a
+// reference of each subquery nested within that scope.
+func (ctx *NodeResolutionContext) reference(index int, field fields.Field) {
+	for i := index + 1; i < len(ctx.Scopes); i++ {
+		if scope := &ctx.Scopes[i]; scope.subquery && !slices.Contains(scope.references, field) {
+			scope.references = append(scope.references, field)
+		}
+	}
+}
+
 //
 //
 
 type OptimizationContext struct {
-	catalog     CatalogSet
-	outerFields []fields.Field
+	catalog         CatalogSet
+	outerFields     []fields.Field
+	joinSearchLimit int
 }
 
+// DefaultJoinSearchLimit is the number of relations above which joins are ordered
+// greedily rather than by an exhaustive search.
+const DefaultJoinSearchLimit = 10
+
 func NewOptimizationContext(catalog CatalogSet) OptimizationContext {
 	return OptimizationContext{
 		catalog: catalog,
@@ -140,6 +284,21 @@ func (c OptimizationContext) OuterFields() []fields.Field {
 	return c.outerFields
 }
 
+// JoinSearchLimit returns the number of relations above which joins are ordered
+// greedily rather than by an exhaustive search.
+func (c OptimizationContext) JoinSearchLimit() int {
+	if c.joinSearchLimit <= 0 {
+		return DefaultJoinSearchLimit
+	}
+
+	return c.joinSearchLimit
+}
+
+func (c OptimizationContext) WithJoinSearchLimit(limit int) OptimizationContext {
+	c.joinSearchLimit = limit
+	return c
+}
+
 func (c OptimizationContext) AddOuterFields(fields []fields.Field) OptimizationContext {
 	c.outerFields = append(c.outerFields, fields...)
 	return c
@@ -149,9 +308,18 @@ func (c OptimizationContext) AddOuterFields(fields []fields.Field) OptimizationC
 //
 
 type ExecutionContext struct {
-	catalog  CatalogSet
-	debug    bool
-	outerRow rows.Row
+	catalog            CatalogSet
+	preparedStatements *catalog.Catalog[PreparedStatement]
+	transaction        Transaction
+	transactions       TransactionManager
+	checkpointer       Checkpointer
+	parameters         []any
+	debug              bool
+	outerRow           rows.Row
+	joinSearchLimit    int
+
+	// memo holds values computed once for each execution of a statement
+	memo *sync.Map
 }
 
 var EmptyExecutionContext = NewExecutionContext(NewCatalogEmptySet())
@@ -163,7 +331,14 @@ func NewExecutionContext(catalog CatalogSet) ExecutionContext {
 }
 
 func (c ExecutionContext) OptimizationContext() OptimizationContext {
-	return NewOptimizationContext(c.catalog)
+	return NewOptimizationContext(c.catalog).WithJoinSearchLimit(c.joinSearchLimit)
+}
+
+// WithJoinSearchLimit sets the number of relations above which queries executed with
+// the context order their joins greedily. Zero selects DefaultJoinSearchLimit.
+func (c ExecutionContext) WithJoinSearchLimit(limit int) ExecutionContext {
+	c.joinSearchLimit = limit
+	return c
 }
 
 func (c ExecutionContext) Catalog() CatalogSet {
@@ -174,6 +349,130 @@ func (c ExecutionContext) OuterRow() rows.Row {
 	return c.outerRow
 }
 
+func (c ExecutionContext) PreparedStatements() *catalog.Catalog[PreparedStatement] {
+	return c.preparedStatements
+}
+
+func (c ExecutionContext) WithPreparedStatements(preparedStatements *catalog.Catalog[PreparedStatement]) ExecutionContext {
+	c.preparedStatements = preparedStatements
+	return c
+}
+
+func (c ExecutionContext) Transaction() Transaction {
+	return c.transaction
+}
+
+func (c ExecutionContext) WithTransaction(transaction Transaction) ExecutionContext {
+	c.transaction = transaction
+	return c
+}
+
+func (c ExecutionContext) Transactions() TransactionManager {
+	return c.transactions
+}
+
+func (c ExecutionContext) WithTransactions(transactions TransactionManager) ExecutionContext {
+	c.transactions = transactions
+	return c
+}
+
+func (c ExecutionContext) Checkpointer() Checkpointer {
+	return c.checkpointer
+}
+
+func (c ExecutionContext) WithCheckpointer(checkpointer Checkpointer) ExecutionContext {
+	c.checkpointer = checkpointer
+	return c
+}
+
+// OnRollback registers a function that reverts a change made within the current
+// transaction. Outside of a transaction, changes are permanent and the function
+// is discarded.
+func (c ExecutionContext) OnRollback(undo func()) {
+	if c.transaction != nil {
+		c.transaction.OnRollback(undo)
+	}
+}
+
+// RecordChange adds a record describing a change made within the current transaction
+// to the write-ahead log. Changes made outside of a transaction are not logged.
+func (c ExecutionContext) RecordChange(record wal.Record) {
+	if c.transaction != nil {
+		c.transaction.RecordChange(record)
+	}
+}
+
+// LockTable acquires a lock on the given table that is held until the end of the
+// current transaction. Outside of a transaction, no lock is taken.
+func (c ExecutionContext) LockTable(name string, mode LockMode) error {
+	if c.transaction == nil {
+		return nil
+	}
+
+	return c.transaction.LockTable(name, mode)
+}
+
+// LockCatalogTable acquires a lock on the given table, which was read from the catalog
+// when the statement was planned. A transaction that creates a table holds an exclusive
+// lock on it until it ends, so statements of other sessions wait here rather than read
+// a table that is not yet committed. An error is returned if the table is no longer the
+// catalog's entry once the lock is held (e.g., its creating transaction rolled back).
+// Outside of a transaction, no lock is taken and the table is not checked.
+func (c ExecutionContext) LockCatalogTable(table Table, mode LockMode) error {
+	if c.transaction == nil {
+		return nil
+	}
+
+	if err := c.transaction.LockTable(table.Name(), mode); err != nil {
+		return err
+	}
+
+	current, ok := c.catalog.Tables.Get(table.Name())
+	if !ok {
+		return fmt.Errorf("unknown table %q", table.Name())
+	}
+	if current != table {
+		return fmt.Errorf("table %q was replaced by a concurrent transaction", table.Name())
+	}
+
+	return nil
+}
+
+// LockRow acquires a lock on the row of the given table with the given TID that is
+// held until the end of the current transaction. Outside of a transaction, no lock
+// is taken.
+func (c ExecutionContext) LockRow(table string, tid int64, mode LockMode) error {
+	if c.transaction == nil {
+		return nil
+	}
+
+	return c.transaction.LockRow(table, tid, mode)
+}
+
+// WaitForTransaction blocks until the transaction with the given identifier has
+// ended. Outside of a transaction, it returns immediately.
+func (c ExecutionContext) WaitForTransaction(id int64) error {
+	if c.transaction == nil {
+		return nil
+	}
+
+	return c.transaction.WaitFor(id)
+}
+
+// Parameter returns the value bound to the parameter with the given (one-based) index.
+func (c ExecutionContext) Parameter(index int) (any, error) {
+	if index < 1 || index > len(c.parameters) {
+		return nil, fmt.Errorf("there is no parameter $%d", index)
+	}
+
+	return c.parameters[index-1], nil
+}
+
+func (c ExecutionContext) WithParameters(parameters []any) ExecutionContext {
+	c.parameters = parameters
+	return c
+}
+
 func (c ExecutionContext) WithDebug() ExecutionContext {
 	c.debug = true
 	return c
@@ -184,6 +483,34 @@ func (c ExecutionContext) AddOuterRow(row rows.Row) ExecutionContext {
 	return c
 }
 
+// WithMemo returns a context for a single execution of a statement, within which values
+// computed by Memoize are retained.
+func (c ExecutionContext) WithMemo() ExecutionContext {
+	c.memo = &sync.Map{}
+	return c
+}
+
+// Memoize returns the value computed by the given function for the given key, which is
+// invoked at most once within the current execution of a statement. Outside of such an
+// execution, the function is invoked on each call.
+func (c ExecutionContext) Memoize(key any, f func() (any, error)) (any, error) {
+	if c.memo == nil {
+		return f()
+	}
+
+	if value, ok := c.memo.Load(key); ok {
+		return value, nil
+	}
+
+	value, err := f()
+	if err != nil {
+		return nil, err
+	}
+
+	value, _ = c.memo.LoadOrStore(key, value)
+	return value, nil
+}
+
 func (c ExecutionContext) Log(format string, args ...interface{}) {
 	if !c.debug {
 		return
@@ -1,6 +1,26 @@
 package impls
 
+import "github.com/efritz/gostgres/internal/shared/types"
+
 type Function interface {
 	Callable
 	Invoke(ctx ExecutionContext, args []any) (any, error)
 }
+
+// TableFunction is a set-returning function invoked in the FROM clause of a query
+// (e.g., unnest).
+type TableFunction interface {
+	Name() string
+	ParamTypes() []types.Type
+
+	// ResolveColumns validates the types of the function's arguments and returns the
+	// columns of the rows it returns.
+	ResolveColumns(argTypes []types.Type) ([]TableFunctionColumn, error)
+
+	Invoke(ctx ExecutionContext, args []any) ([][]any, error)
+}
+
+type TableFunctionColumn struct {
+	Name string
+	Type types.Type
+}
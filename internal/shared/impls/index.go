@@ -11,7 +11,11 @@ type BaseIndex interface {
 	Unwrap() BaseIndex
 	UniqueOn() []fields.Field
 	Filter() Expression
-	Insert(row rows.Row) error
+	// Insert adds the given row to the index. Unique indexes reject rows that
+	// conflict with an existing entry unless isLive (if non-nil) reports that
+	// the row version referenced by that entry has been deleted. Errors returned
+	// by isLive are returned unchanged.
+	Insert(row rows.Row, isLive func(tid int64) (bool, error)) error
 	Delete(row rows.Row) error
 }
 
@@ -0,0 +1,48 @@
+package impls
+
+// LockMode is the strength of a lock held on a table or on a row of a table.
+// Locks are held until the end of the transaction that acquired them.
+type LockMode int
+
+const (
+	// Table-level lock modes, in order of increasing strength
+	AccessShareLock LockMode = iota + 1
+	RowShareLock
+	RowExclusiveLock
+	ShareUpdateExclusiveLock
+	ShareLock
+	ShareRowExclusiveLock
+	ExclusiveLock
+	AccessExclusiveLock
+
+	// Row-level lock modes
+	ForShareLock
+	ForUpdateLock
+)
+
+func (m LockMode) String() string {
+	switch m {
+	case AccessShareLock:
+		return "ACCESS SHARE"
+	case RowShareLock:
+		return "ROW SHARE"
+	case RowExclusiveLock:
+		return "ROW EXCLUSIVE"
+	case ShareUpdateExclusiveLock:
+		return "SHARE UPDATE EXCLUSIVE"
+	case ShareLock:
+		return "SHARE"
+	case ShareRowExclusiveLock:
+		return "SHARE ROW EXCLUSIVE"
+	case ExclusiveLock:
+		return "EXCLUSIVE"
+	case AccessExclusiveLock:
+		return "ACCESS EXCLUSIVE"
+	case ForShareLock:
+		return "FOR SHARE"
+	case ForUpdateLock:
+		return "FOR UPDATE"
+	}
+
+	return "UNKNOWN"
+}
@@ -0,0 +1,69 @@
+package impls
+
+import (
+	"slices"
+
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+// ParameterTypes tracks the types of positional parameters ($1, $2, ...) of a
+// statement. Types may be declared up front (e.g., by PREPARE or by the client)
+// or inferred from the context in which a parameter is used during resolution.
+type ParameterTypes struct {
+	types []types.Type
+}
+
+func NewParameterTypes(declared []types.Type) *ParameterTypes {
+	return &ParameterTypes{
+		types: slices.Clone(declared),
+	}
+}
+
+// Type returns the type of the parameter with the given (one-based) index.
+func (p *ParameterTypes) Type(index int) types.Type {
+	if p == nil || index < 1 || index > len(p.types) {
+		return types.TypeUnknown
+	}
+
+	return p.types[index-1]
+}
+
+// Use registers a reference to the parameter with the given (one-based) index.
+func (p *ParameterTypes) Use(index int) {
+	if p == nil {
+		return
+	}
+
+	for len(p.types) < index {
+		p.types = append(p.types, types.TypeUnknown)
+	}
+}
+
+// Infer sets the type of the parameter with the given (one-based) index if
+// it has not yet been determined.
+func (p *ParameterTypes) Infer(index int, typ types.Type) {
+	if p == nil || typ == types.TypeUnknown || typ == types.TypeAny {
+		return
+	}
+
+	p.Use(index)
+	if p.types[index-1] == types.TypeUnknown {
+		p.types[index-1] = typ
+	}
+}
+
+// Finalize resolves parameters whose types could not be determined to text
+// and returns the types of all parameters.
+func (p *ParameterTypes) Finalize() []types.Type {
+	if p == nil {
+		return nil
+	}
+
+	for i, typ := range p.types {
+		if typ == types.TypeUnknown {
+			p.types[i] = types.TypeText
+		}
+	}
+
+	return slices.Clone(p.types)
+}
@@ -0,0 +1,13 @@
+package impls
+
+import (
+	"github.com/efritz/gostgres/internal/execution/protocol"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+type PreparedStatement interface {
+	ParameterTypes() []types.Type
+	Fields() []fields.Field
+	Execute(ctx ExecutionContext, w protocol.ResponseWriter, parameters []any)
+}
@@ -2,7 +2,7 @@ package impls
 
 type Sequence interface {
 	Name() string
-	Next() (int64, error)
-	Set(value int64) error
+	Next(ctx ExecutionContext) (int64, error)
+	Set(ctx ExecutionContext, value int64) error
 	Value() int64
 }
@@ -0,0 +1,31 @@
+package impls
+
+// TableStatistics describes the contents of a table as of the last time it was
+// analyzed. Statistics are estimates computed from a sample of the table's rows.
+type TableStatistics struct {
+	RowCount int
+
+	// Columns maps the name of each (non-internal) field of the table to the
+	// statistics of its values.
+	Columns map[string]ColumnStatistics
+}
+
+// ColumnStatistics describes the distribution of the values of a single column.
+type ColumnStatistics struct {
+	// NullFraction is the fraction of rows whose value is NULL.
+	NullFraction float64
+
+	// DistinctCount is the estimated number of distinct non-NULL values.
+	DistinctCount float64
+
+	// MostCommonValues lists the values occurring most frequently, in descending
+	// order of frequency. The corresponding element of MostCommonFrequencies is the
+	// fraction of all rows having that value.
+	MostCommonValues      []any
+	MostCommonFrequencies []float64
+
+	// HistogramBounds divides the non-NULL values that are not among the most common
+	// values into buckets holding approximately the same number of rows. Bounds are
+	// sorted in ascending order and include the minimum and maximum values.
+	HistogramBounds []any
+}
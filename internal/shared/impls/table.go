@@ -6,12 +6,45 @@ type Table interface {
 	Name() string
 	Indexes() []BaseIndex
 	Fields() []TableField
+	// Size returns the number of live (non-deleted) rows stored in the table.
 	Size() int
-	TIDs() []int64
-	Row(tid int64) (rows.Row, bool)
-	SetPrimaryKey(index BaseIndex) error
-	AddIndex(index BaseIndex) error
+	TIDs(ctx ExecutionContext) ([]int64, error)
+	Row(ctx ExecutionContext, tid int64) (rows.Row, bool, error)
+	SetPrimaryKey(ctx ExecutionContext, index BaseIndex) error
+	AddIndex(ctx ExecutionContext, index BaseIndex) error
 	AddConstraint(ctx ExecutionContext, constraint Constraint) error
 	Insert(ctx ExecutionContext, row rows.Row) (_ rows.Row, err error)
-	Delete(row rows.Row) (rows.Row, bool, error)
+	Delete(ctx ExecutionContext, row rows.Row) (rows.Row, bool, error)
+
+	// Restore adds a row previously stored in the table with the given TID, such as
+	// one recovered from the write-ahead log. A new TID is assigned if the given TID
+	// is zero. Constraints are not re-checked.
+	Restore(ctx ExecutionContext, tid int64, values []any) error
+
+	// LockRow locks the given row (identified by its TID) in the given mode until
+	// the end of the current transaction. An error is returned if the row has been
+	// modified by a transaction that committed after the current snapshot was taken.
+	LockRow(ctx ExecutionContext, row rows.Row, mode LockMode) error
+
+	// Vacuum removes the row versions that were deleted by transactions whose changes
+	// are visible to every transaction in progress (and to those that begin later).
+	Vacuum(ctx ExecutionContext) error
+
+	// Statistics returns the statistics collected by the most recent analysis of
+	// the table, or nil if the table has not been analyzed.
+	Statistics() *TableStatistics
+
+	// SetStatistics replaces the statistics of the table. The previous statistics
+	// are restored if the given context's transaction rolls back.
+	SetStatistics(ctx ExecutionContext, statistics *TableStatistics)
+}
+
+// TableAccessMethod creates tables that store their rows in a particular way (e.g.,
+// in memory or in pages of a file).
+type TableAccessMethod interface {
+	Name() string
+
+	// NewTable creates an empty table. Storage allocated for the table is released if
+	// the given context's transaction rolls back.
+	NewTable(ctx ExecutionContext, name string, fields []TableField) (Table, error)
 }
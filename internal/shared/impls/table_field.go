@@ -48,6 +48,10 @@ func (f TableField) WithType(typ types.Type) TableField {
 	return newTableField(f.Field.WithType(typ), f.nullable, f.defaultExpression)
 }
 
+func (f TableField) WithTypeModifier(typeModifier types.TypeModifier) TableField {
+	return newTableField(f.Field.WithTypeModifier(typeModifier), f.nullable, f.defaultExpression)
+}
+
 // TODO - make actual constraint?
 func (f TableField) WithNonNullable() TableField {
 	return newTableField(f.Field, false, f.defaultExpression)
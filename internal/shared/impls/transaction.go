@@ -0,0 +1,59 @@
+package impls
+
+import "github.com/efritz/gostgres/internal/storage/wal"
+
+// Transaction records the changes made by the statements of a transaction so
+// that they can be reverted if the transaction (or a part of it) rolls back.
+//
+// Row versions are stamped with the identifier of the transaction that created
+// (or deleted) them and the identifier of the command (statement) within that
+// transaction that made the change. A transaction uses these to determine which
+// row versions are visible to it.
+type Transaction interface {
+	ID() int64
+	CommandID() int
+
+	// Sees returns true if a change made by the given transaction and command
+	// is visible to the current command of this transaction.
+	Sees(id int64, commandID int) bool
+
+	// Committed returns true if the given transaction has committed. This
+	// differs from Sees for transactions that committed after this one began.
+	Committed(id int64) bool
+
+	// Horizon returns the identifier of the oldest transaction whose changes may
+	// not be visible to some transaction in progress. Row versions deleted by
+	// transactions with smaller identifiers are visible to no current or future
+	// transaction and may be removed.
+	Horizon() int64
+
+	// OnRollback registers a function that reverts a change made within the
+	// transaction. Registered functions are invoked in reverse order.
+	OnRollback(undo func())
+
+	// RecordChange adds a record describing a change made within the transaction
+	// to the write-ahead log once the transaction commits. Records of changes that
+	// are reverted are discarded.
+	RecordChange(record wal.Record)
+
+	// LockTable and LockRow acquire a lock on a table or on a single row of a
+	// table, blocking while another transaction holds a conflicting lock. An
+	// error is returned if waiting for the lock would deadlock.
+	LockTable(name string, mode LockMode) error
+	LockRow(table string, tid int64, mode LockMode) error
+
+	// WaitFor blocks until the transaction with the given identifier has ended.
+	// An error is returned if waiting would deadlock.
+	WaitFor(id int64) error
+}
+
+// TransactionManager controls the transaction block of a session.
+type TransactionManager interface {
+	Begin() error
+	Commit() error
+	Rollback() error
+	Savepoint(name string) error
+	RollbackToSavepoint(name string) error
+	ReleaseSavepoint(name string) error
+	LockTables(names []string, mode LockMode) error
+}
@@ -0,0 +1,146 @@
+package impls
+
+import "github.com/efritz/gostgres/internal/shared/types"
+
+// Window describes the partitioning, ordering, and framing of the rows over which a
+// window function is computed.
+type Window struct {
+	// Name refers to a window defined in a WINDOW clause, from which the partitioning,
+	// ordering, and framing of this window are copied. Empty for windows specified in
+	// full at the point of use.
+	Name       string
+	Partitions []Expression
+	Order      OrderExpression
+	Frame      *WindowFrame // nil for the default frame
+}
+
+type WindowFrameMode int
+
+const (
+	WindowFrameModeRange WindowFrameMode = iota
+	WindowFrameModeRows
+	WindowFrameModeGroups
+)
+
+func (m WindowFrameMode) String() string {
+	switch m {
+	case WindowFrameModeRows:
+		return "rows"
+	case WindowFrameModeGroups:
+		return "groups"
+	}
+
+	return "range"
+}
+
+// WindowFrame describes the subset of a window partition visible to a window function
+// computed for a particular row.
+type WindowFrame struct {
+	Mode      WindowFrameMode
+	Start     WindowFrameBound
+	End       WindowFrameBound
+	Exclusion WindowFrameExclusion
+}
+
+// WindowFrameExclusion describes the rows around the current row which are removed
+// from the frame.
+type WindowFrameExclusion int
+
+const (
+	WindowFrameExcludeNoOthers WindowFrameExclusion = iota
+	WindowFrameExcludeCurrentRow
+	WindowFrameExcludeGroup
+	WindowFrameExcludeTies
+)
+
+func (e WindowFrameExclusion) String() string {
+	switch e {
+	case WindowFrameExcludeCurrentRow:
+		return "current row"
+	case WindowFrameExcludeGroup:
+		return "group"
+	case WindowFrameExcludeTies:
+		return "ties"
+	}
+
+	return "no others"
+}
+
+type WindowFrameBoundType int
+
+const (
+	WindowFrameBoundUnboundedPreceding WindowFrameBoundType = iota
+	WindowFrameBoundPreceding
+	WindowFrameBoundCurrentRow
+	WindowFrameBoundFollowing
+	WindowFrameBoundUnboundedFollowing
+)
+
+type WindowFrameBound struct {
+	Type   WindowFrameBoundType
+	Offset Expression // set for (bounded) preceding and following bounds
+}
+
+func (b WindowFrameBound) String() string {
+	switch b.Type {
+	case WindowFrameBoundUnboundedPreceding:
+		return "unbounded preceding"
+	case WindowFrameBoundPreceding:
+		return b.Offset.String() + " preceding"
+	case WindowFrameBoundFollowing:
+		return b.Offset.String() + " following"
+	case WindowFrameBoundUnboundedFollowing:
+		return "unbounded following"
+	}
+
+	return "current row"
+}
+
+//
+//
+
+type WindowFunction interface {
+	Name() string
+
+	// ResolveReturnType validates the types of the function's arguments and returns the
+	// type of its result.
+	ResolveReturnType(argTypes []types.Type) (types.Type, error)
+
+	Invoke(ctx ExecutionContext, row WindowRow) (any, error)
+}
+
+// WindowRow describes the position of a row within its window partition. Rows of the
+// partition are identified by their index in window order. Ranges of rows are given
+// as half-open intervals.
+type WindowRow struct {
+	// Args holds the values of the window function's arguments for each row of the
+	// partition.
+	Args       [][]any
+	Index      int
+	PeerGroup  int // number of peer groups preceding the row's peer group
+	PeersStart int
+	PeersEnd   int
+	FrameStart int
+	FrameEnd   int
+
+	// Excluded holds ranges of rows removed from the frame by its exclusion clause.
+	Excluded [][2]int
+}
+
+// Frame returns the indexes of the rows in the frame of the row, in window order.
+func (r WindowRow) Frame() []int {
+	frame := make([]int, 0, r.FrameEnd-r.FrameStart)
+
+outer:
+	for i := r.FrameStart; i < r.FrameEnd; i++ {
+		for _, excluded := range r.Excluded {
+			if i >= excluded[0] && i < excluded[1] {
+				continue outer
+			}
+		}
+
+		frame = append(frame, i)
+	}
+
+	return frame
+}
@@ -1,7 +1,10 @@
 package ordering
 
 import (
+	"bytes"
 	"math/big"
+	"strings"
+	"time"
 
 	"github.com/efritz/gostgres/internal/shared/types"
 	"golang.org/x/exp/constraints"
@@ -40,15 +43,76 @@ func CompareValues(left, right any) OrderType {
 
 	if lVal, ok := left.(string); ok {
 		if rVal, ok := right.(string); ok {
-			if lVal == rVal {
-				return OrderTypeEqual
-			}
+			return orderTypeFromComparison(strings.Compare(lVal, rVal))
+		}
 
-			if lVal < rVal {
-				return OrderTypeBefore
-			}
+		if rVal, ok := right.(types.Character); ok {
+			return orderTypeFromComparison(strings.Compare(lVal, rVal.Text()))
+		}
+	}
+
+	switch lVal := left.(type) {
+	case types.Time:
+		if rVal, ok := right.(types.Time); ok {
+			return compareNumbers(lVal, rVal)
+		}
+
+	case types.Interval:
+		if rVal, ok := right.(types.Interval); ok {
+			return orderTypeFromComparison(lVal.Compare(rVal))
+		}
+
+	case types.UUID:
+		if rVal, ok := right.(types.UUID); ok {
+			return orderTypeFromComparison(bytes.Compare(lVal[:], rVal[:]))
+		}
+
+	case types.Bytea:
+		if rVal, ok := right.(types.Bytea); ok {
+			return orderTypeFromComparison(strings.Compare(string(lVal), string(rVal)))
+		}
 
-			return OrderTypeAfter
+	case types.JSONB:
+		if rVal, ok := right.(types.JSONB); ok {
+			return orderTypeFromComparison(lVal.Compare(rVal))
+		}
+
+	case types.Array:
+		if rVal, ok := right.(types.Array); ok {
+			return compareArrays(lVal, rVal)
+		}
+
+	case types.Character:
+		// Trailing spaces of blank-padded values are insignificant
+		switch rVal := right.(type) {
+		case types.Character:
+			return orderTypeFromComparison(strings.Compare(lVal.Text(), rVal.Text()))
+		case string:
+			return orderTypeFromComparison(strings.Compare(lVal.Text(), rVal))
+		}
+
+	case types.Date:
+		if rVal, ok := right.(types.Date); ok {
+			return compareNumbers(lVal, rVal)
+		}
+		if isTemporal(right) {
+			return compareTemporals(left, right)
+		}
+
+	case types.Timestamp:
+		if rVal, ok := right.(types.Timestamp); ok {
+			return compareNumbers(lVal, rVal)
+		}
+		if isTemporal(right) {
+			return compareTemporals(left, right)
+		}
+
+	case time.Time:
+		if rVal, ok := right.(time.Time); ok {
+			return orderTypeFromComparison(lVal.Compare(rVal))
+		}
+		if isTemporal(right) {
+			return compareTemporals(left, right)
 		}
 	}
 
@@ -81,6 +145,62 @@ func CompareValues(left, right any) OrderType {
 	return OrderTypeIncomparable
 }
 
+func isTemporal(value any) bool {
+	switch value.(type) {
+	case types.Date, types.Timestamp, time.Time:
+		return true
+	}
+
+	return false
+}
+
+// compareTemporals orders points in time of different types by promoting both to the
+// type of greater precedence. Both values must be dates or timestamps.
+func compareTemporals(left, right any) OrderType {
+	a, b, err := types.PromoteToCommonTemporalValues(left, right)
+	if err != nil {
+		return OrderTypeIncomparable
+	}
+
+	switch v := a.(type) {
+	case types.Date:
+		return compareNumbers(v, b.(types.Date))
+	case types.Timestamp:
+		return compareNumbers(v, b.(types.Timestamp))
+	case time.Time:
+		return orderTypeFromComparison(v.Compare(b.(time.Time)))
+	}
+
+	return OrderTypeIncomparable
+}
+
+// compareArrays orders arrays by their first unequal element, and then by length. NULL
+// elements are equal to each other and ordered after all other elements.
+func compareArrays(left, right types.Array) OrderType {
+	rElements := right.Elements()
+	for i, lElement := range left.Elements()[:min(left.Len(), right.Len())] {
+		switch cmp := CompareValues(lElement, rElements[i]); cmp {
+		case OrderTypeEqual, OrderTypeNulls:
+		default:
+			return cmp
+		}
+	}
+
+	return compareNumbers(left.Len(), right.Len())
+}
+
+func orderTypeFromComparison(cmp int) OrderType {
+	if cmp < 0 {
+		return OrderTypeBefore
+	}
+
+	if cmp > 0 {
+		return OrderTypeAfter
+	}
+
+	return OrderTypeEqual
+}
+
 func compareNumbers[T constraints.Integer | constraints.Float](a, b T) OrderType {
 	if a < b {
 		return OrderTypeBefore
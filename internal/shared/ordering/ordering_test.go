@@ -3,7 +3,9 @@ package ordering
 import (
 	"math/big"
 	"testing"
+	"time"
 
+	"github.com/efritz/gostgres/internal/shared/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -69,6 +71,54 @@ func TestCompareValues(t *testing.T) {
 			right:    "bar",
 			expected: OrderTypeAfter,
 		},
+		{
+			name:     "padded characters =",
+			left:     types.Character("foo  "),
+			right:    types.Character("foo"),
+			expected: OrderTypeEqual,
+		},
+		{
+			name:     "padded characters and text =",
+			left:     "foo",
+			right:    types.Character("foo  "),
+			expected: OrderTypeEqual,
+		},
+		{
+			name:     "padded characters and text <",
+			left:     types.Character("foo "),
+			right:    "foo ",
+			expected: OrderTypeBefore,
+		},
+		{
+			name:     "timestamps with time zone =",
+			left:     time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			right:    time.Date(2024, 1, 1, 14, 0, 0, 0, time.FixedZone("", 2*60*60)),
+			expected: OrderTypeEqual,
+		},
+		{
+			name:     "promotable temporals =",
+			left:     types.Date(19723),
+			right:    types.Timestamp(19723 * 24 * 60 * 60 * 1000000),
+			expected: OrderTypeEqual,
+		},
+		{
+			name:     "promotable temporals <",
+			left:     types.Date(19723),
+			right:    time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC),
+			expected: OrderTypeBefore,
+		},
+		{
+			name:     "promotable temporals >",
+			left:     time.Date(2024, 1, 1, 0, 0, 1, 0, time.UTC),
+			right:    types.Timestamp(19723 * 24 * 60 * 60 * 1000000),
+			expected: OrderTypeAfter,
+		},
+		{
+			name:     "temporal and number",
+			left:     types.Date(19723),
+			right:    int32(19723),
+			expected: OrderTypeIncomparable,
+		},
 		{
 			name:     "incomparable",
 			left:     "foo",
@@ -82,6 +132,18 @@ func TestCompareValues(t *testing.T) {
 	}
 }
 
+func TestCompareValuesDoesNotAllocate(t *testing.T) {
+	for _, values := range [][2]any{
+		{int64(100), int64(200)},
+		{types.Date(19723), types.Date(19724)},
+		{types.Timestamp(100), types.Timestamp(200)},
+		{time.Unix(100, 0), time.Unix(200, 0)},
+	} {
+		allocs := testing.AllocsPerRun(100, func() { CompareValues(values[0], values[1]) })
+		assert.Zero(t, allocs, "comparing %T values", values[0])
+	}
+}
+
 func TestCompareValueSlices(t *testing.T) {
 	for _, testCase := range []struct {
 		name     string
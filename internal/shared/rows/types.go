@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/types"
 )
 
 func refineTypes(refinableFields []fields.Field, values []any) ([]fields.Field, []any, error) {
@@ -20,6 +21,11 @@ func refineTypes(refinableFields []fields.Field, values []any) ([]fields.Field,
 			return nil, nil, fmt.Errorf("type error (%v is not %s)", values[i], field.Type())
 		}
 
+		refinedValue, err := field.TypeModifier().Apply(refinedValue, types.CastContextAssignment)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		refinedFields = append(refinedFields, field.WithType(refinedType))
 		refinedValues = append(refinedValues, refinedValue)
 	}
@@ -1,6 +1,32 @@
 package scan
 
+// MarkRestorer is implemented by scanners that can return to an earlier position,
+// as required by the inner relation of a merge join.
 type MarkRestorer interface {
+	// Mark records the position of the row most recently returned by Scan.
 	Mark()
+
+	// Restore returns to the marked position, so that the next call to Scan returns
+	// the marked row again.
 	Restore()
 }
+
+type markRestoreRowScanner struct {
+	RowScanner
+	MarkRestorer
+}
+
+// WithMarkRestore returns the given row scanner, forwarding marks and restores to the
+// given source scanner if it supports them. This is suitable only for row scanners that
+// return a row as soon as it is produced by the source (e.g., filters or projections),
+// so that the positions of the two scanners coincide.
+func WithMarkRestore(scanner RowScanner, source any) RowScanner {
+	if markRestorer, ok := source.(MarkRestorer); ok {
+		return markRestoreRowScanner{
+			RowScanner:   scanner,
+			MarkRestorer: markRestorer,
+		}
+	}
+
+	return scanner
+}
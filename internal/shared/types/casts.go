@@ -0,0 +1,153 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// CastContext determines where a cast may be applied without being requested by an
+// explicit CAST(expression AS type) or expression::type. These match the contexts of
+// Postgres's pg_cast catalog.
+type CastContext int
+
+const (
+	// CastContextExplicit casts are applied only when requested explicitly.
+	CastContextExplicit CastContext = iota
+
+	// CastContextAssignment casts are also applied when storing a value into a column
+	// or binding a value to a parameter.
+	CastContextAssignment
+
+	// CastContextImplicit casts are also applied to function arguments.
+	CastContextImplicit
+)
+
+type castFunc func(value any) (any, error)
+
+type castDefinition struct {
+	context CastContext
+	cast    castFunc
+}
+
+var casts = map[Type]map[Type]castDefinition{}
+
+func registerCast(source, target Type, context CastContext, cast castFunc) {
+	if casts[source] == nil {
+		casts[source] = map[Type]castDefinition{}
+	}
+
+	casts[source][target] = castDefinition{context: context, cast: cast}
+}
+
+func init() {
+	const (
+		e = CastContextExplicit
+		a = CastContextAssignment
+		i = CastContextImplicit
+	)
+
+	numericTypes := []Type{TypeSmallInteger, TypeInteger, TypeBigInteger, TypeReal, TypeDoublePrecision, TypeNumeric}
+	numericContexts := map[Type][]CastContext{
+		//                   smallint, integer, bigint, real, double, numeric
+		TypeSmallInteger:    {i, i, i, i, i, i},
+		TypeInteger:         {a, i, i, i, i, i},
+		TypeBigInteger:      {a, a, i, i, i, i},
+		TypeReal:            {a, a, a, i, i, a},
+		TypeDoublePrecision: {a, a, a, a, i, a},
+		TypeNumeric:         {a, a, a, i, i, i},
+	}
+
+	for _, source := range numericTypes {
+		for j, target := range numericTypes {
+			if source != target {
+				registerCast(source, target, numericContexts[source][j], converters[numericTypeIndex(source)][j])
+			}
+		}
+	}
+
+	registerCast(TypeInteger, TypeBool, e, func(value any) (any, error) { return value.(int32) != 0, nil })
+	registerCast(TypeBool, TypeInteger, e, func(value any) (any, error) {
+		if value.(bool) {
+			return int32(1), nil
+		}
+
+		return int32(0), nil
+	})
+
+	// Timestamps with a time zone are converted to and from local time in UTC
+	registerCast(TypeDate, TypeTimestamp, i, func(value any) (any, error) { return Timestamp(int64(value.(Date)) * microsecondsPerDay), nil })
+	registerCast(TypeDate, TypeTimestampTz, i, func(value any) (any, error) { return value.(Date).Time(), nil })
+	registerCast(TypeTimestamp, TypeTimestampTz, i, func(value any) (any, error) { return value.(Timestamp).Time(), nil })
+	registerCast(TypeTimestamp, TypeDate, a, func(value any) (any, error) { return NewDate(value.(Timestamp).Time()), nil })
+	registerCast(TypeTimestamp, TypeTime, a, func(value any) (any, error) { return NewTime(value.(Timestamp).Time()), nil })
+	registerCast(TypeTimestampTz, TypeTimestamp, a, func(value any) (any, error) { return NewTimestamp(value.(time.Time).UTC()), nil })
+	registerCast(TypeTimestampTz, TypeDate, a, func(value any) (any, error) { return NewDate(value.(time.Time).UTC()), nil })
+	registerCast(TypeTimestampTz, TypeTime, a, func(value any) (any, error) { return NewTime(value.(time.Time).UTC()), nil })
+	registerCast(TypeTime, TypeInterval, i, func(value any) (any, error) { return Interval{Microseconds: int64(value.(Time))}, nil })
+	registerCast(TypeInterval, TypeTime, a, func(value any) (any, error) {
+		return Time(floorMod(value.(Interval).Microseconds, microsecondsPerDay)), nil
+	})
+
+	// JSON numbers and booleans can be extracted from jsonb values
+	for _, typ := range append(numericTypes, TypeBool) {
+		typ := typ
+		registerCast(TypeJSONB, typ, e, func(value any) (any, error) { return castJSONB(value.(JSONB), typ) })
+	}
+
+	// Trailing spaces of blank-padded values are removed when converted to text
+	registerCast(TypeCharacter, TypeText, i, func(value any) (any, error) { return value.(Character).Text(), nil })
+	registerCast(TypeText, TypeCharacter, a, func(value any) (any, error) { return Character(value.(string)), nil })
+
+	// Every type can be converted to and from its text representation. Text input is
+	// explicit for numbers and booleans. Text values can be assigned to columns of the
+	// remaining types (string literals adopt the type of their context instead).
+	for _, typ := range append(numericTypes, TypeBool, TypeTimestampTz, TypeDate, TypeTime, TypeTimestamp, TypeInterval, TypeUUID, TypeBytea, TypeJSONB) {
+		typ := typ
+		context := a
+		if typ.IsNumber() || typ == TypeBool {
+			context = e
+		}
+
+		registerCast(TypeText, typ, context, func(value any) (any, error) { return ParseText(value.(string), typ) })
+		registerCast(typ, TypeText, a, func(value any) (any, error) { return FormatText(value), nil })
+	}
+}
+
+// CanCast returns true if a value of the source type can be converted to the target
+// type in the given context.
+func CanCast(source, target Type, context CastContext) bool {
+	if source == target || target == TypeAny || target == NewArrayType(TypeAny) && source.IsArray() {
+		return true
+	}
+
+	if source.IsArray() || target.IsArray() {
+		return canCastArray(source, target, context)
+	}
+
+	definition, ok := casts[source][target]
+	return ok && definition.context >= context
+}
+
+// Cast converts the given value to the target type in the given context. NULL values are
+// returned unchanged.
+func Cast(value any, target Type, context CastContext) (any, error) {
+	if value == nil || target == TypeAny {
+		return value, nil
+	}
+
+	source := TypeKindFromValue(value)
+	if source == target || target == NewArrayType(TypeAny) && source.IsArray() {
+		return value, nil
+	}
+
+	if source.IsArray() || target.IsArray() {
+		return castArray(value, source, target, context)
+	}
+
+	definition, ok := casts[source][target]
+	if !ok || definition.context < context {
+		return nil, fmt.Errorf("cannot cast type %s to %s", source, target)
+	}
+
+	return definition.cast(value)
+}
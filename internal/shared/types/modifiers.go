@@ -0,0 +1,171 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// TypeModifier holds the parameters of a parameterized column type, such as the maximum
+// length of a varchar(n) column or the precision and scale of a numeric(p, s) column.
+// Like the typmod of a Postgres column, a modifier constrains the values stored in the
+// column but is not part of the type of an expression: a varchar(n) column has type text.
+// The zero value does not constrain values.
+type TypeModifier struct {
+	kind      typeModifierKind
+	length    int
+	precision int
+	scale     int
+}
+
+type typeModifierKind int
+
+const (
+	typeModifierKindNone typeModifierKind = iota
+	typeModifierKindVarchar
+	typeModifierKindChar
+	typeModifierKindNumeric
+)
+
+const maxNumericPrecision = 1000
+
+// NewVarcharModifier creates a modifier for text values of at most the given number of
+// characters (i.e., varchar(n)).
+func NewVarcharModifier(length int) (TypeModifier, error) {
+	if length < 1 {
+		return TypeModifier{}, fmt.Errorf("length for type varchar must be at least 1")
+	}
+
+	return TypeModifier{kind: typeModifierKindVarchar, length: length}, nil
+}
+
+// NewCharModifier creates a modifier for text values of exactly the given number of
+// characters (i.e., char(n)). Shorter values are padded with trailing spaces.
+func NewCharModifier(length int) (TypeModifier, error) {
+	if length < 1 {
+		return TypeModifier{}, fmt.Errorf("length for type char must be at least 1")
+	}
+
+	return TypeModifier{kind: typeModifierKindChar, length: length}, nil
+}
+
+// NewNumericModifier creates a modifier for numeric values with the given number of
+// significant digits, of which the given number follow the decimal point.
+func NewNumericModifier(precision, scale int) (TypeModifier, error) {
+	if precision < 1 || precision > maxNumericPrecision {
+		return TypeModifier{}, fmt.Errorf("NUMERIC precision %d must be between 1 and %d", precision, maxNumericPrecision)
+	}
+
+	if scale < 0 || scale > precision {
+		return TypeModifier{}, fmt.Errorf("NUMERIC scale %d must be between 0 and precision %d", scale, precision)
+	}
+
+	return TypeModifier{kind: typeModifierKindNumeric, precision: precision, scale: scale}, nil
+}
+
+// TypeName returns the name of the given type as constrained by this modifier.
+func (m TypeModifier) TypeName(typ Type) string {
+	name := typ.String()
+	switch m.kind {
+	case typeModifierKindVarchar:
+		name = fmt.Sprintf("character varying(%d)", m.length)
+	case typeModifierKindChar:
+		name = fmt.Sprintf("character(%d)", m.length)
+	case typeModifierKindNumeric:
+		name = fmt.Sprintf("numeric(%d,%d)", m.precision, m.scale)
+	default:
+		return name
+	}
+
+	if typ.IsArray() {
+		name += "[]"
+	}
+
+	return name
+}
+
+// Apply conforms a value of the modified type to this modifier. Values too long for a
+// varchar(n) or char(n) modifier are truncated by explicit casts and are otherwise
+// rejected, unless only trailing spaces would be removed. Numeric values are rounded to
+// the scale of a numeric(p, s) modifier and are rejected if they exceed its precision.
+func (m TypeModifier) Apply(value any, context CastContext) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	if array, ok := value.(Array); ok && m.kind != typeModifierKindNone {
+		// Modifiers of array types constrain each element
+		elements := make([]any, 0, array.Len())
+		for _, element := range array.Elements() {
+			applied, err := m.Apply(element, context)
+			if err != nil {
+				return nil, err
+			}
+
+			elements = append(elements, applied)
+		}
+
+		return NewArray(array.ElementType(), elements), nil
+	}
+
+	switch m.kind {
+	case typeModifierKindVarchar:
+		if text, ok := value.(string); ok {
+			return m.applyLength(text, context)
+		}
+
+	case typeModifierKindChar:
+		if text, ok := value.(Character); ok {
+			padded, err := m.applyLength(string(text), context)
+			return Character(padded), err
+		}
+
+	case typeModifierKindNumeric:
+		if number, ok := value.(*big.Float); ok {
+			return m.applyPrecision(number)
+		}
+	}
+
+	return value, nil
+}
+
+func (m TypeModifier) applyLength(text string, context CastContext) (string, error) {
+	length := utf8.RuneCountInString(text)
+	if length > m.length {
+		runes := []rune(text)
+		if context != CastContextExplicit && strings.TrimRight(string(runes[m.length:]), " ") != "" {
+			return "", fmt.Errorf("value too long for type %s", m.TypeName(TypeText))
+		}
+
+		text, length = string(runes[:m.length]), m.length
+	}
+
+	if m.kind == typeModifierKindChar {
+		text += strings.Repeat(" ", m.length-length)
+	}
+
+	return text, nil
+}
+
+func (m TypeModifier) applyPrecision(number *big.Float) (*big.Float, error) {
+	prec := max(number.Prec(), 64)
+	factor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(m.scale)), nil))
+
+	// Round half away from zero to the scale of the modifier
+	scaled := new(big.Float).SetPrec(prec+64).Mul(number, factor)
+	if scaled.Sign() < 0 {
+		scaled.Sub(scaled, big.NewFloat(0.5))
+	} else {
+		scaled.Add(scaled, big.NewFloat(0.5))
+	}
+	digits, _ := scaled.Int(nil)
+
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(m.precision)), nil)
+	if new(big.Int).Abs(digits).Cmp(limit) >= 0 {
+		return nil, fmt.Errorf("numeric field overflow (a field with precision %d, scale %d must round to an absolute value less than 10^%d)", m.precision, m.scale, m.precision-m.scale)
+	}
+
+	rounded := new(big.Float).SetPrec(prec).SetInt(digits)
+	return rounded.Quo(rounded, factor), nil
+}
@@ -19,6 +19,14 @@ const (
 	TypeNumeric
 	TypeBool
 	TypeTimestampTz
+	TypeDate
+	TypeTime
+	TypeTimestamp
+	TypeInterval
+	TypeUUID
+	TypeBytea
+	TypeJSONB
+	TypeCharacter
 	TypeAny
 )
 
@@ -42,10 +50,30 @@ func (typ Type) String() string {
 		return "bool"
 	case TypeTimestampTz:
 		return "timestamp with time zone"
+	case TypeDate:
+		return "date"
+	case TypeTime:
+		return "time without time zone"
+	case TypeTimestamp:
+		return "timestamp without time zone"
+	case TypeInterval:
+		return "interval"
+	case TypeUUID:
+		return "uuid"
+	case TypeBytea:
+		return "bytea"
+	case TypeJSONB:
+		return "jsonb"
+	case TypeCharacter:
+		return "character"
 	case TypeAny:
 		return "any"
 	}
 
+	if typ.IsArray() {
+		return typ.ElementType().String() + "[]"
+	}
+
 	return "unknown"
 }
 
@@ -68,23 +96,15 @@ func (typ Type) IsNumber() bool {
 	return false
 }
 
+// Refine converts the given value to this type in an assignment context (e.g., when
+// storing a value into a column of this type).
 func (typ Type) Refine(value any) (Type, any, bool) {
-	if value == nil || typ == TypeAny {
-		return typ, value, true
+	refined, err := Cast(value, typ, CastContextAssignment)
+	if err != nil {
+		return TypeUnknown, nil, false
 	}
 
-	switch v := value.(type) {
-	case string:
-		return refineStringValue(v, typ)
-	case int16, int32, int64, float32, float64, *big.Float:
-		return refineNumericValue(v, typ)
-	case bool:
-		return typ, v, typ == TypeAny || typ == TypeBool
-	case time.Time:
-		return typ, v, typ == TypeAny || typ == TypeTimestampTz
-	}
-
-	return TypeUnknown, nil, false
+	return typ, refined, true
 }
 
 func (typ Type) PromoteToCommonType(other Type) Type {
@@ -96,6 +116,21 @@ func (typ Type) PromoteToCommonType(other Type) Type {
 		return typ
 	}
 
+	if typ.IsArray() {
+		// Arrays are promoted to arrays of the common type of their elements
+		if other == TypeAny {
+			return typ
+		}
+
+		if other.IsArray() {
+			if common := typ.ElementType().PromoteToCommonType(other.ElementType()); common != TypeUnknown {
+				return NewArrayType(common)
+			}
+		}
+
+		return TypeUnknown
+	}
+
 	switch typ {
 	case TypeAny:
 		return other
@@ -105,6 +140,17 @@ func (typ Type) PromoteToCommonType(other Type) Type {
 			return common
 		}
 
+	case TypeTimestampTz, TypeDate, TypeTimestamp:
+		if common, err := PromoteToCommonTemporalTypes(typ, other); err == nil {
+			return common
+		}
+
+	case TypeCharacter:
+		// Blank-padded values are compared with text values as text
+		if other == TypeText {
+			return TypeText
+		}
+
 	default:
 		// Not equal, not promotable
 	}
@@ -113,7 +159,7 @@ func (typ Type) PromoteToCommonType(other Type) Type {
 }
 
 func TypeKindFromValue(value any) Type {
-	switch value.(type) {
+	switch v := value.(type) {
 	case string:
 		return TypeText
 	case int16, int32, int64, float32, float64, *big.Float:
@@ -122,6 +168,24 @@ func TypeKindFromValue(value any) Type {
 		return TypeBool
 	case time.Time:
 		return TypeTimestampTz
+	case Date:
+		return TypeDate
+	case Time:
+		return TypeTime
+	case Timestamp:
+		return TypeTimestamp
+	case Interval:
+		return TypeInterval
+	case UUID:
+		return TypeUUID
+	case Bytea:
+		return TypeBytea
+	case JSONB:
+		return TypeJSONB
+	case Character:
+		return TypeCharacter
+	case Array:
+		return NewArrayType(v.elementType)
 	}
 
 	return TypeUnknown
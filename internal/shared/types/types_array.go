@@ -0,0 +1,231 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// arrayTypeFlag is set in the types of arrays. The remaining bits identify the type
+// of the array's elements.
+const arrayTypeFlag Type = 1 << 8
+
+// NewArrayType returns the type of one-dimensional arrays of the given element type.
+// Arrays of TypeAny accept arrays of any element type (i.e., Postgres's anyarray).
+func NewArrayType(elementType Type) Type {
+	return elementType | arrayTypeFlag
+}
+
+// IsArray returns true if the type is an array type with a valid element type.
+func (typ Type) IsArray() bool {
+	if typ <= 0 || typ&arrayTypeFlag == 0 {
+		return false
+	}
+
+	elementType := typ.ElementType()
+	return elementType > TypeUnknown && elementType <= TypeAny
+}
+
+// ElementType returns the type of the elements of an array type.
+func (typ Type) ElementType() Type {
+	return typ &^ arrayTypeFlag
+}
+
+// Array is the value of a one-dimensional array, whose elements (any of which may be
+// NULL) have the given type. Arrays are immutable; operations on arrays return new
+// values.
+type Array struct {
+	elementType Type
+	elements    []any
+}
+
+func NewArray(elementType Type, elements []any) Array {
+	if len(elements) == 0 {
+		elements = nil
+	}
+
+	return Array{
+		elementType: elementType,
+		elements:    elements,
+	}
+}
+
+func (a Array) ElementType() Type {
+	return a.elementType
+}
+
+// Elements returns the elements of the array, which must not be modified.
+func (a Array) Elements() []any {
+	return a.elements
+}
+
+func (a Array) Len() int {
+	return len(a.elements)
+}
+
+// String formats the array in Postgres's text format, e.g. `{1,2,NULL}` or
+// `{"a b",c}`.
+func (a Array) String() string {
+	elements := make([]string, 0, len(a.elements))
+	for _, element := range a.elements {
+		if element == nil {
+			elements = append(elements, "NULL")
+		} else {
+			elements = append(elements, quoteArrayElement(FormatText(element)))
+		}
+	}
+
+	return "{" + strings.Join(elements, ",") + "}"
+}
+
+// quoteArrayElement quotes the text of an array element if it would otherwise be read
+// as a NULL or as the delimiter of an element.
+func quoteArrayElement(text string) string {
+	if text != "" && !strings.EqualFold(text, "null") && !strings.ContainsAny(text, "{},\"\\ \t\n") {
+		return text
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text) + `"`
+}
+
+// parseArray parses an array written in Postgres's text format. Elements may be quoted,
+// in which case backslashes escape the following character. Unquoted elements have
+// surrounding whitespace removed, and the unquoted element NULL is a NULL element.
+func parseArray(text string, elementType Type) (Array, error) {
+	malformed := fmt.Errorf("malformed array literal: %q", text)
+
+	if !strings.HasPrefix(text, "{") || !strings.HasSuffix(text, "}") {
+		return Array{}, malformed
+	}
+	body := text[1 : len(text)-1]
+
+	if strings.TrimSpace(body) == "" {
+		return NewArray(elementType, nil), nil
+	}
+
+	var elements []any
+	for i := 0; ; {
+		for i < len(body) && isArraySpace(body[i]) {
+			i++
+		}
+
+		var element strings.Builder
+		quoted := false
+
+		if i < len(body) && body[i] == '"' {
+			quoted = true
+			i++
+
+			for ; i < len(body) && body[i] != '"'; i++ {
+				if body[i] == '\\' {
+					i++
+				}
+				if i < len(body) {
+					element.WriteByte(body[i])
+				}
+			}
+			if i >= len(body) {
+				return Array{}, malformed
+			}
+			i++
+
+			for i < len(body) && isArraySpace(body[i]) {
+				i++
+			}
+		} else {
+			for ; i < len(body) && body[i] != ','; i++ {
+				switch body[i] {
+				case '{':
+					return Array{}, fmt.Errorf("multidimensional arrays are not supported")
+				case '"', '}':
+					return Array{}, malformed
+				case '\\':
+					i++
+				}
+				if i < len(body) {
+					element.WriteByte(body[i])
+				}
+			}
+		}
+
+		value := element.String()
+		if !quoted {
+			value = strings.TrimRight(value, " \t\n")
+			if value == "" {
+				return Array{}, malformed
+			}
+		}
+
+		if !quoted && strings.EqualFold(value, "null") {
+			elements = append(elements, nil)
+		} else {
+			parsed, err := ParseText(value, elementType)
+			if err != nil {
+				return Array{}, err
+			}
+
+			elements = append(elements, parsed)
+		}
+
+		if i >= len(body) {
+			break
+		}
+		if body[i] != ',' {
+			return Array{}, malformed
+		}
+		i++
+	}
+
+	return NewArray(elementType, elements), nil
+}
+
+func isArraySpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+// canCastArray returns true if arrays of the source type can be converted to arrays
+// of the target type in the given context, which is the case when their elements can
+// be converted.
+func canCastArray(source, target Type, context CastContext) bool {
+	if source.IsArray() && target.IsArray() {
+		return source.ElementType() == TypeAny || CanCast(source.ElementType(), target.ElementType(), context)
+	}
+
+	// Arrays are converted to and from their text representation like other types
+	if source == TypeText && target.IsArray() || source.IsArray() && target == TypeText {
+		return context <= CastContextAssignment
+	}
+
+	return false
+}
+
+// castArray converts an array value to the target type (or a text value to the target
+// array type) in the given context.
+func castArray(value any, source, target Type, context CastContext) (any, error) {
+	if !canCastArray(source, target, context) {
+		return nil, fmt.Errorf("cannot cast type %s to %s", source, target)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return ParseText(v, target)
+
+	case Array:
+		if target == TypeText {
+			return v.String(), nil
+		}
+
+		elements := make([]any, 0, len(v.elements))
+		for _, element := range v.elements {
+			converted, err := Cast(element, target.ElementType(), context)
+			if err != nil {
+				return nil, err
+			}
+
+			elements = append(elements, converted)
+		}
+
+		return NewArray(target.ElementType(), elements), nil
+	}
+
+	return nil, fmt.Errorf("cannot cast type %s to %s", source, target)
+}
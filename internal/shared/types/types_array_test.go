@@ -0,0 +1,75 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArray(t *testing.T) {
+	for _, testCase := range []struct {
+		text        string
+		elementType Type
+		expected    Array
+	}{
+		{text: "{}", elementType: TypeInteger, expected: NewArray(TypeInteger, nil)},
+		{text: "{1, 2 ,NULL}", elementType: TypeInteger, expected: NewArray(TypeInteger, []any{int32(1), int32(2), nil})},
+		{text: `{a,"b c","NULL","",null}`, elementType: TypeText, expected: NewArray(TypeText, []any{"a", "b c", "NULL", "", nil})},
+		{text: `{"a\"b","c\\d",e\,f}`, elementType: TypeText, expected: NewArray(TypeText, []any{`a"b`, `c\d`, "e,f"})},
+	} {
+		t.Run(testCase.text, func(t *testing.T) {
+			array, err := parseArray(testCase.text, testCase.elementType)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, array)
+		})
+	}
+
+	for _, text := range []string{"1,2", "{1,,2}", `{"a}`, "{a}b}", "{1,2"} {
+		_, err := parseArray(text, TypeText)
+		assert.Error(t, err, text)
+	}
+
+	_, err := parseArray("{{1,2},{3,4}}", TypeInteger)
+	assert.EqualError(t, err, "multidimensional arrays are not supported")
+}
+
+func TestFormatArray(t *testing.T) {
+	array := NewArray(TypeText, []any{"a", "b c", "NULL", "", nil, `x"y\z`, "{}"})
+	assert.Equal(t, `{a,"b c","NULL","",NULL,"x\"y\\z","{}"}`, array.String())
+
+	// Formatted arrays parse to the original array
+	parsed, err := parseArray(array.String(), TypeText)
+	require.NoError(t, err)
+	assert.Equal(t, array, parsed)
+}
+
+func TestArrayTypes(t *testing.T) {
+	typ := NewArrayType(TypeInteger)
+	assert.True(t, typ.IsArray())
+	assert.False(t, TypeInteger.IsArray())
+	assert.Equal(t, TypeInteger, typ.ElementType())
+	assert.Equal(t, "integer[]", typ.String())
+
+	assert.Equal(t, NewArrayType(TypeBigInteger), typ.PromoteToCommonType(NewArrayType(TypeBigInteger)))
+	assert.Equal(t, TypeUnknown, typ.PromoteToCommonType(TypeInteger))
+
+	assert.True(t, CanCast(typ, NewArrayType(TypeBigInteger), CastContextImplicit))
+	assert.True(t, CanCast(TypeText, typ, CastContextAssignment))
+	assert.False(t, CanCast(TypeText, typ, CastContextImplicit))
+	assert.False(t, CanCast(NewArrayType(TypeUUID), typ, CastContextExplicit))
+}
+
+func TestInvalidTypesAreNotArrays(t *testing.T) {
+	for _, typ := range []Type{Type(-1), TypeUnknown, arrayTypeFlag, NewArrayType(TypeAny + 1)} {
+		assert.False(t, typ.IsArray(), int(typ))
+		assert.Equal(t, "unknown", typ.String(), int(typ))
+		assert.False(t, CanCast(typ, NewArrayType(TypeText), CastContextExplicit), int(typ))
+	}
+
+	// Values of non-numeric types are reported without an array type
+	_, _, err := PromoteToCommonNumericValues("a", int32(1))
+	assert.EqualError(t, err, "unexpected type (wanted numeric type, have unknown)")
+	assert.False(t, IsNumeric("a"))
+	assert.True(t, IsNumeric(int16(1)))
+}
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// UUID is the value of a uuid.
+type UUID [16]byte
+
+func (u UUID) String() string {
+	text := hex.EncodeToString(u[:])
+	return strings.Join([]string{text[0:8], text[8:12], text[12:16], text[16:20], text[20:32]}, "-")
+}
+
+// parseUUID parses a uuid written as 32 hexadecimal digits, optionally separated into
+// groups by hyphens and optionally surrounded by braces.
+func parseUUID(text string) (UUID, bool) {
+	if strings.HasPrefix(text, "{") && strings.HasSuffix(text, "}") {
+		text = text[1 : len(text)-1]
+	}
+
+	var u UUID
+	digits := strings.ReplaceAll(text, "-", "")
+	if len(digits) != 32 || strings.HasPrefix(text, "-") || strings.HasSuffix(text, "-") || strings.Contains(text, "--") {
+		return u, false
+	}
+
+	if _, err := hex.Decode(u[:], []byte(digits)); err != nil {
+		return u, false
+	}
+
+	return u, true
+}
+
+// Bytea is the value of a bytea. The bytes are held in a string so that values are
+// immutable and comparable.
+type Bytea string
+
+// String formats the value in Postgres's hex format, e.g. `\x0aff`.
+func (b Bytea) String() string {
+	return `\x` + hex.EncodeToString([]byte(b))
+}
+
+// parseBytea parses a bytea written in Postgres's hex format (`\x` followed by pairs of
+// hexadecimal digits) or escape format (in which a backslash is written as `\\` and an
+// arbitrary byte as a backslash followed by three octal digits).
+func parseBytea(text string) (Bytea, bool) {
+	if strings.HasPrefix(text, `\x`) || strings.HasPrefix(text, `\X`) {
+		data, err := hex.DecodeString(strings.Join(strings.Fields(text[2:]), ""))
+		return Bytea(data), err == nil
+	}
+
+	var data []byte
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' {
+			data = append(data, text[i])
+			continue
+		}
+
+		if strings.HasPrefix(text[i:], `\\`) {
+			data = append(data, '\\')
+			i++
+			continue
+		}
+
+		if i+4 > len(text) {
+			return "", false
+		}
+
+		value, err := strconv.ParseUint(text[i+1:i+4], 8, 8)
+		if err != nil {
+			return "", false
+		}
+
+		data = append(data, byte(value))
+		i += 3
+	}
+
+	return Bytea(data), true
+}
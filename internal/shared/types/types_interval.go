@@ -0,0 +1,262 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interval is the value of an interval. Months and days are held separately from the
+// time component as their lengths vary: one month after January 31 is the last day of
+// February, and one day after a daylight saving transition may be 23 or 25 hours later.
+type Interval struct {
+	Months       int32
+	Days         int32
+	Microseconds int64
+}
+
+const daysPerMonth = 30
+
+// NewIntervalBetween returns the interval a - b, expressed as a number of days and a
+// time component.
+func NewIntervalBetween(a, b time.Time) Interval {
+	microseconds := a.UnixMicro() - b.UnixMicro()
+
+	return Interval{
+		Days:         int32(microseconds / microsecondsPerDay),
+		Microseconds: microseconds % microsecondsPerDay,
+	}
+}
+
+// AddTo returns the given time advanced by the interval. Months are added first, clamping
+// the day of the month to the length of the resulting month, followed by days and then
+// the time component.
+func (i Interval) AddTo(t time.Time) time.Time {
+	if i.Months != 0 {
+		year, month, day := t.Date()
+		hour, minute, second := t.Clock()
+
+		months := int64(month) - 1 + int64(i.Months)
+		year += int((months - floorMod(months, 12)) / 12)
+		month = time.Month(floorMod(months, 12) + 1)
+		day = min(day, time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day())
+
+		t = time.Date(year, month, day, hour, minute, second, t.Nanosecond(), t.Location())
+	}
+
+	return t.AddDate(0, 0, int(i.Days)).Add(time.Duration(i.Microseconds) * time.Microsecond)
+}
+
+func (i Interval) Add(other Interval) Interval {
+	return Interval{
+		Months:       i.Months + other.Months,
+		Days:         i.Days + other.Days,
+		Microseconds: i.Microseconds + other.Microseconds,
+	}
+}
+
+func (i Interval) Negate() Interval {
+	return Interval{
+		Months:       -i.Months,
+		Days:         -i.Days,
+		Microseconds: -i.Microseconds,
+	}
+}
+
+// Scale multiplies the interval by the given factor. Fractional months and days cascade
+// into the smaller units, treating a month as 30 days.
+func (i Interval) Scale(factor float64) Interval {
+	months := float64(i.Months) * factor
+	wholeMonths := math.Trunc(months)
+	days := float64(i.Days)*factor + (months-wholeMonths)*daysPerMonth
+	wholeDays := math.Trunc(days)
+	microseconds := float64(i.Microseconds)*factor + (days-wholeDays)*float64(microsecondsPerDay)
+
+	return Interval{
+		Months:       int32(wholeMonths),
+		Days:         int32(wholeDays),
+		Microseconds: int64(math.Round(microseconds)),
+	}
+}
+
+// Compare returns -1, 0, or 1 if the interval is shorter than, equal to, or longer than
+// the given interval. Like Postgres, a month is treated as 30 days and a day as 24 hours
+// for the purposes of comparison, so that '1 month' = '30 days'.
+func (i Interval) Compare(other Interval) int {
+	a, b := i.approximateMicroseconds(), other.approximateMicroseconds()
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+
+	return 0
+}
+
+func (i Interval) approximateMicroseconds() int64 {
+	return (int64(i.Months)*daysPerMonth+int64(i.Days))*microsecondsPerDay + i.Microseconds
+}
+
+// String formats the interval in the style of Postgres's default (postgres) interval
+// output, e.g. "1 year 2 mons 3 days 04:05:06".
+func (i Interval) String() string {
+	var parts []string
+	for _, unit := range []struct {
+		value int32
+		name  string
+	}{
+		{i.Months / 12, "year"},
+		{i.Months % 12, "mon"},
+		{i.Days, "day"},
+	} {
+		if unit.value == 1 {
+			parts = append(parts, fmt.Sprintf("1 %s", unit.name))
+		} else if unit.value != 0 {
+			parts = append(parts, fmt.Sprintf("%d %ss", unit.value, unit.name))
+		}
+	}
+
+	if i.Microseconds != 0 || len(parts) == 0 {
+		sign := ""
+		microseconds := i.Microseconds
+		if microseconds < 0 {
+			sign, microseconds = "-", -microseconds
+		}
+
+		seconds := microseconds / microsecondsPerSecond
+		clock := fmt.Sprintf("%s%02d:%02d:%02d", sign, seconds/3600, seconds/60%60, seconds%60)
+		if fraction := microseconds % microsecondsPerSecond; fraction != 0 {
+			clock += strings.TrimRight(fmt.Sprintf(".%06d", fraction), "0")
+		}
+
+		parts = append(parts, clock)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+//
+//
+
+type intervalUnit struct {
+	months       float64
+	days         float64
+	microseconds float64
+}
+
+var intervalUnits = map[string]intervalUnit{}
+
+func init() {
+	for _, unit := range []struct {
+		names []string
+		unit  intervalUnit
+	}{
+		{[]string{"microsecond", "us", "usec"}, intervalUnit{microseconds: 1}},
+		{[]string{"millisecond", "ms", "msec"}, intervalUnit{microseconds: 1e3}},
+		{[]string{"second", "s", "sec"}, intervalUnit{microseconds: 1e6}},
+		{[]string{"minute", "m", "min"}, intervalUnit{microseconds: 60e6}},
+		{[]string{"hour", "h", "hr"}, intervalUnit{microseconds: 3600e6}},
+		{[]string{"day", "d"}, intervalUnit{days: 1}},
+		{[]string{"week", "w"}, intervalUnit{days: 7}},
+		{[]string{"month", "mon"}, intervalUnit{months: 1}},
+		{[]string{"year", "y", "yr"}, intervalUnit{months: 12}},
+		{[]string{"decade"}, intervalUnit{months: 120}},
+		{[]string{"century", "centuries"}, intervalUnit{months: 1200}},
+		{[]string{"millennium", "millennia"}, intervalUnit{months: 12000}},
+	} {
+		for _, name := range unit.names {
+			intervalUnits[name] = unit.unit
+			intervalUnits[name+"s"] = unit.unit
+		}
+	}
+}
+
+// parseInterval parses an interval written as a sequence of quantities and units, such
+// as "1 year 2 months" or "-1.5 hours", optionally including a time component such as
+// "3 days 04:05:06" and optionally followed by "ago". A trailing unitless quantity is a
+// number of seconds.
+func parseInterval(text string) (Interval, bool) {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) == 0 {
+		return Interval{}, false
+	}
+
+	ago := false
+	if words[len(words)-1] == "ago" {
+		ago = true
+		words = words[:len(words)-1]
+	}
+
+	var months, days, microseconds float64
+	for j := 0; j < len(words); j++ {
+		if strings.Contains(words[j], ":") {
+			clock, ok := parseIntervalClock(words[j])
+			if !ok {
+				return Interval{}, false
+			}
+
+			microseconds += clock
+			continue
+		}
+
+		quantity, err := strconv.ParseFloat(words[j], 64)
+		if err != nil || math.IsInf(quantity, 0) || math.IsNaN(quantity) {
+			return Interval{}, false
+		}
+
+		unit := intervalUnit{microseconds: 1e6}
+		if j+1 < len(words) {
+			j++
+
+			var ok bool
+			if unit, ok = intervalUnits[words[j]]; !ok {
+				return Interval{}, false
+			}
+		}
+
+		months += quantity * unit.months
+		days += quantity * unit.days
+		microseconds += quantity * unit.microseconds
+	}
+
+	// Fractional months and days cascade into the smaller units
+	interval := Interval{Months: 1}.Scale(months).Add(Interval{Days: 1}.Scale(days))
+	interval.Microseconds += int64(math.Round(microseconds))
+
+	if ago {
+		interval = interval.Negate()
+	}
+
+	return interval, true
+}
+
+// parseIntervalClock parses a signed time component of the form hh:mm[:ss[.ffffff]] into
+// a number of microseconds.
+func parseIntervalClock(text string) (float64, bool) {
+	sign := 1.0
+	if strings.HasPrefix(text, "-") {
+		sign, text = -1, text[1:]
+	} else {
+		text = strings.TrimPrefix(text, "+")
+	}
+
+	parts := strings.Split(text, ":")
+	if len(parts) > 3 {
+		return 0, false
+	}
+
+	var microseconds float64
+	for j, part := range parts {
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil || value < 0 || (j < 2 && strings.Contains(part, ".")) {
+			return 0, false
+		}
+
+		microseconds += value * []float64{3600e6, 60e6, 1e6}[j]
+	}
+
+	return sign * microseconds, true
+}
@@ -0,0 +1,464 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JSONB is the value of a jsonb. The value is held in its normalized text representation
+// so that values are immutable and comparable. As in Postgres, normalized objects have no
+// duplicate keys (the last value for a key is kept) and order their keys by length and
+// then bytewise, and whitespace is normalized.
+type JSONB string
+
+func (j JSONB) String() string {
+	return string(j)
+}
+
+// Value returns the decoded value: nil (for a JSON null), a bool, a string, a json.Number,
+// a []any, or a map[string]any.
+func (j JSONB) Value() any {
+	value, err := decodeJSON(string(j))
+	if err != nil {
+		panic(fmt.Sprintf("malformed jsonb value %q: %s", string(j), err))
+	}
+
+	return value
+}
+
+// NewJSONB returns the jsonb value holding the given decoded value (as returned by
+// JSONB.Value).
+func NewJSONB(value any) JSONB {
+	var buf strings.Builder
+	writeJSON(&buf, value)
+	return JSONB(buf.String())
+}
+
+// ToJSONB converts the given SQL value to jsonb (as Postgres's to_jsonb). Numbers and
+// booleans convert to their JSON equivalents, arrays to JSON arrays, and values of other
+// types to JSON strings holding their text representation.
+func ToJSONB(value any) JSONB {
+	return NewJSONB(toJSONValue(value))
+}
+
+func toJSONValue(value any) any {
+	switch v := value.(type) {
+	case nil, bool, string:
+		return v
+	case JSONB:
+		return v.Value()
+	case float32:
+		return jsonFloat(float64(v), 32)
+	case float64:
+		return jsonFloat(v, 64)
+	case int16, int32, int64, *big.Float:
+		return json.Number(FormatText(v))
+	case Array:
+		elements := make([]any, 0, v.Len())
+		for _, element := range v.Elements() {
+			elements = append(elements, toJSONValue(element))
+		}
+
+		return elements
+	}
+
+	return FormatText(value)
+}
+
+func jsonFloat(v float64, bitSize int) any {
+	// JSON has no representation for infinities and NaN
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return FormatText(v)
+	}
+
+	return json.Number(strconv.FormatFloat(v, 'f', -1, bitSize))
+}
+
+// parseJSONB parses and normalizes the given JSON text.
+func parseJSONB(text string) (JSONB, bool) {
+	value, err := decodeJSON(text)
+	if err != nil {
+		return "", false
+	}
+
+	return NewJSONB(value), true
+}
+
+func decodeJSON(text string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+
+	return value, nil
+}
+
+// writeJSON writes the given decoded value in the normalized text representation.
+func writeJSON(buf *strings.Builder, value any) {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+
+	case json.Number:
+		buf.WriteString(normalizeJSONNumber(v))
+
+	case string:
+		writeJSONString(buf, v)
+
+	case []any:
+		buf.WriteByte('[')
+		for i, element := range v {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeJSON(buf, element)
+		}
+		buf.WriteByte(']')
+
+	case map[string]any:
+		buf.WriteByte('{')
+		for i, key := range JSONKeys(v) {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeJSONString(buf, key)
+			buf.WriteString(": ")
+			writeJSON(buf, v[key])
+		}
+		buf.WriteByte('}')
+
+	default:
+		panic(fmt.Sprintf("unexpected JSON value of type %T", value))
+	}
+}
+
+func writeJSONString(buf *strings.Builder, s string) {
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+
+	buf.Write(bytes.TrimSuffix(encoded.Bytes(), []byte{'\n'}))
+}
+
+// normalizeJSONNumber rewrites numbers written with an exponent in positional notation.
+// Other numbers are kept as written (e.g., 1.50 retains its trailing zero).
+func normalizeJSONNumber(n json.Number) string {
+	if !strings.ContainsAny(string(n), "eE") {
+		return string(n)
+	}
+
+	if f, ok := new(big.Float).SetPrec(256).SetString(string(n)); ok {
+		return f.Text('f', -1)
+	}
+
+	return string(n)
+}
+
+// JSONKeys returns the keys of the given decoded object in normalized order: by length
+// and then bytewise.
+func JSONKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, compareJSONKeys)
+	return keys
+}
+
+func compareJSONKeys(a, b string) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+
+	return strings.Compare(a, b)
+}
+
+// castJSONB converts a JSON number or boolean to the given numeric or boolean type. A JSON
+// null converts to NULL.
+func castJSONB(j JSONB, typ Type) (any, error) {
+	value := j.Value()
+
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+
+	case json.Number:
+		if typ.IsNumber() {
+			number, err := ParseText(string(v), TypeNumeric)
+			if err != nil {
+				return nil, err
+			}
+
+			return Cast(number, typ, CastContextExplicit)
+		}
+
+	case bool:
+		if typ == TypeBool {
+			return v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot cast jsonb %s to type %s", jsonKindNames[jsonKind(value)], typ)
+}
+
+//
+//
+
+// Field returns the value of the given key of a JSON object.
+func (j JSONB) Field(key string) (JSONB, bool) {
+	if object, ok := j.Value().(map[string]any); ok {
+		if value, ok := object[key]; ok {
+			return NewJSONB(value), true
+		}
+	}
+
+	return "", false
+}
+
+// Element returns the element of a JSON array at the given (zero-based) index. Negative
+// indexes count from the end of the array.
+func (j JSONB) Element(index int) (JSONB, bool) {
+	if array, ok := j.Value().([]any); ok {
+		if index < 0 {
+			index += len(array)
+		}
+
+		if index >= 0 && index < len(array) {
+			return NewJSONB(array[index]), true
+		}
+	}
+
+	return "", false
+}
+
+// Path returns the value at the given path. Each element of the path is either the key of
+// an object or the (zero-based) index of an array element.
+func (j JSONB) Path(path []string) (JSONB, bool) {
+	value := j
+	for _, step := range path {
+		var ok bool
+		if _, isArray := value.Value().([]any); isArray {
+			index, err := strconv.Atoi(step)
+			if err != nil {
+				return "", false
+			}
+
+			value, ok = value.Element(index)
+		} else {
+			value, ok = value.Field(step)
+		}
+
+		if !ok {
+			return "", false
+		}
+	}
+
+	return value, true
+}
+
+// Text returns the text representation of the value as a SQL value: strings are unquoted,
+// and a JSON null is a SQL NULL.
+func (j JSONB) Text() any {
+	switch v := j.Value().(type) {
+	case nil:
+		return nil
+	case string:
+		return v
+	}
+
+	return string(j)
+}
+
+// HasKey returns true if the given string is a key of a JSON object, an element of a JSON
+// array, or equal to a JSON string.
+func (j JSONB) HasKey(key string) bool {
+	switch v := j.Value().(type) {
+	case map[string]any:
+		_, ok := v[key]
+		return ok
+
+	case []any:
+		for _, element := range v {
+			if s, ok := element.(string); ok && s == key {
+				return true
+			}
+		}
+
+	case string:
+		return v == key
+	}
+
+	return false
+}
+
+// Contains returns true if the given value is contained within this value (i.e.,
+// j @> other). An object contains another if it has each of its keys and each of its
+// values contains the corresponding value. An array contains another if each of its
+// elements is contained by some element. Scalars contain only equal scalars, except that
+// a top-level array contains each of its scalar elements.
+func (j JSONB) Contains(other JSONB) bool {
+	left, right := j.Value(), other.Value()
+
+	if array, ok := left.([]any); ok && jsonKind(right) < jsonKindArray {
+		return jsonContains(array, []any{right})
+	}
+
+	return jsonContains(left, right)
+}
+
+func jsonContains(left, right any) bool {
+	switch r := right.(type) {
+	case map[string]any:
+		l, ok := left.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		for key, rValue := range r {
+			lValue, ok := l[key]
+			if !ok || !jsonContains(lValue, rValue) {
+				return false
+			}
+		}
+
+		return true
+
+	case []any:
+		l, ok := left.([]any)
+		if !ok {
+			return false
+		}
+
+	outer:
+		for _, rElement := range r {
+			for _, lElement := range l {
+				if jsonContains(lElement, rElement) {
+					continue outer
+				}
+			}
+
+			return false
+		}
+
+		return true
+	}
+
+	return jsonKind(left) < jsonKindArray && compareJSONValues(left, right) == 0
+}
+
+// Compare orders jsonb values as Postgres does: values of different kinds are ordered
+// object > array > boolean > number > string > null; objects and arrays are ordered by
+// their number of pairs or elements, and then pairwise.
+func (j JSONB) Compare(other JSONB) int {
+	return compareJSONValues(j.Value(), other.Value())
+}
+
+const (
+	jsonKindNull = iota
+	jsonKindString
+	jsonKindNumber
+	jsonKindBool
+	jsonKindArray
+	jsonKindObject
+)
+
+var jsonKindNames = map[int]string{
+	jsonKindNull:   "null",
+	jsonKindString: "string",
+	jsonKindNumber: "numeric",
+	jsonKindBool:   "boolean",
+	jsonKindArray:  "array",
+	jsonKindObject: "object",
+}
+
+func jsonKind(value any) int {
+	switch value.(type) {
+	case string:
+		return jsonKindString
+	case json.Number:
+		return jsonKindNumber
+	case bool:
+		return jsonKindBool
+	case []any:
+		return jsonKindArray
+	case map[string]any:
+		return jsonKindObject
+	}
+
+	return jsonKindNull
+}
+
+func compareJSONValues(left, right any) int {
+	if lKind, rKind := jsonKind(left), jsonKind(right); lKind != rKind {
+		return lKind - rKind
+	}
+
+	switch l := left.(type) {
+	case string:
+		return strings.Compare(l, right.(string))
+
+	case json.Number:
+		lVal, _ := new(big.Float).SetString(string(l))
+		rVal, _ := new(big.Float).SetString(string(right.(json.Number)))
+		return lVal.Cmp(rVal)
+
+	case bool:
+		if r := right.(bool); l != r {
+			if r {
+				return -1
+			}
+
+			return 1
+		}
+
+	case []any:
+		r := right.([]any)
+		if len(l) != len(r) {
+			return len(l) - len(r)
+		}
+
+		for i, element := range l {
+			if cmp := compareJSONValues(element, r[i]); cmp != 0 {
+				return cmp
+			}
+		}
+
+	case map[string]any:
+		r := right.(map[string]any)
+		if len(l) != len(r) {
+			return len(l) - len(r)
+		}
+
+		lKeys, rKeys := JSONKeys(l), JSONKeys(r)
+		for i, key := range lKeys {
+			if cmp := compareJSONKeys(key, rKeys[i]); cmp != 0 {
+				return cmp
+			}
+
+			if cmp := compareJSONValues(l[key], r[key]); cmp != 0 {
+				return cmp
+			}
+		}
+	}
+
+	return 0
+}
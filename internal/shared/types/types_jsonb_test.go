@@ -0,0 +1,114 @@
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONB(t *testing.T) {
+	for _, testCase := range []struct {
+		text     string
+		expected JSONB
+	}{
+		{text: `null`, expected: `null`},
+		{text: ` "ab<>" `, expected: `"ab<>"`},
+		{text: `[1,2.50 , 1e2,-0.5E-1]`, expected: `[1, 2.50, 100, -0.05]`},
+		{text: `{"bb":1,"a":{"y":true,"x":false},"c":[]}`, expected: `{"a": {"x": false, "y": true}, "c": [], "bb": 1}`},
+		{text: `{"a": 1, "a": 2}`, expected: `{"a": 2}`},
+		{text: `{}`, expected: `{}`},
+	} {
+		t.Run(testCase.text, func(t *testing.T) {
+			value, ok := parseJSONB(testCase.text)
+			require.True(t, ok)
+			assert.Equal(t, testCase.expected, value)
+		})
+	}
+
+	for _, text := range []string{"", "{", `{"a"}`, "[1,]", "1 2", "'a'", "nul"} {
+		_, ok := parseJSONB(text)
+		assert.False(t, ok, text)
+	}
+}
+
+func TestToJSONB(t *testing.T) {
+	assert.Equal(t, JSONB(`[1, null, "a", true, 1.5]`), ToJSONB(NewArray(TypeAny, []any{int32(1), nil, "a", true, 1.5})))
+	assert.Equal(t, JSONB(`"+Inf"`), ToJSONB(math.Inf(1)))
+	assert.Equal(t, JSONB(`{"a": 1}`), ToJSONB(JSONB(`{"a": 1}`)))
+}
+
+func TestJSONBAccessors(t *testing.T) {
+	value := JSONB(`{"a": {"b": [10, "x", null]}, "s": "t"}`)
+
+	field, ok := value.Field("a")
+	require.True(t, ok)
+	assert.Equal(t, JSONB(`{"b": [10, "x", null]}`), field)
+
+	_, ok = value.Field("missing")
+	assert.False(t, ok)
+
+	element, ok := JSONB(`[1, 2, 3]`).Element(-1)
+	require.True(t, ok)
+	assert.Equal(t, JSONB(`3`), element)
+
+	_, ok = JSONB(`[1, 2, 3]`).Element(3)
+	assert.False(t, ok)
+
+	path, ok := value.Path([]string{"a", "b", "1"})
+	require.True(t, ok)
+	assert.Equal(t, "x", path.Text())
+
+	path, ok = value.Path([]string{"a", "b", "2"})
+	require.True(t, ok)
+	assert.Nil(t, path.Text())
+
+	_, ok = value.Path([]string{"a", "b", "c"})
+	assert.False(t, ok)
+
+	assert.True(t, value.HasKey("s"))
+	assert.False(t, value.HasKey("t"))
+	assert.True(t, JSONB(`["a", 1]`).HasKey("a"))
+	assert.False(t, JSONB(`["a", 1]`).HasKey("1"))
+}
+
+func TestJSONBContains(t *testing.T) {
+	for _, testCase := range []struct {
+		left     JSONB
+		right    JSONB
+		expected bool
+	}{
+		{left: `{"a": 1, "b": {"c": [1, 2]}}`, right: `{"b": {"c": [2]}}`, expected: true},
+		{left: `{"a": 1, "b": {"c": [1, 2]}}`, right: `{"b": {"c": 2}}`, expected: false},
+		{left: `{"a": 1}`, right: `{"a": 1.0}`, expected: true},
+		{left: `{"a": 1}`, right: `{}`, expected: true},
+		{left: `[1, [2, 3]]`, right: `[[3], 1, 1]`, expected: true},
+		{left: `[1, [2, 3]]`, right: `[2]`, expected: false},
+		{left: `["a", "b"]`, right: `"a"`, expected: true},
+		{left: `{"a": ["b"]}`, right: `{"a": "b"}`, expected: false},
+		{left: `"a"`, right: `["a"]`, expected: false},
+		{left: `null`, right: `null`, expected: true},
+	} {
+		assert.Equal(t, testCase.expected, testCase.left.Contains(testCase.right), "%s @> %s", testCase.left, testCase.right)
+	}
+}
+
+func TestJSONBCompare(t *testing.T) {
+	// Ordered as null < string < number < boolean < array < object, and then by size
+	ordered := []JSONB{`null`, `""`, `"b"`, `-1`, `2.5`, `false`, `true`, `[]`, `[3]`, `[1, 2]`, `{}`, `{"b": 1}`, `{"b": 2}`, `{"aa": 1}`}
+	for i, left := range ordered {
+		for j, right := range ordered {
+			switch cmp := left.Compare(right); {
+			case i < j:
+				assert.Negative(t, cmp, "%s < %s", left, right)
+			case i > j:
+				assert.Positive(t, cmp, "%s > %s", left, right)
+			default:
+				assert.Zero(t, cmp, "%s = %s", left, right)
+			}
+		}
+	}
+
+	assert.Zero(t, JSONB(`1`).Compare(`1.00`))
+}
@@ -8,26 +8,6 @@ import (
 	"golang.org/x/exp/constraints"
 )
 
-func refineNumericValue(value any, typ Type) (Type, any, bool) {
-	if typ == TypeAny {
-		return typ, value, true
-	}
-
-	lIndex := numericTypeIndex(numericTypeKindFromValue(value))
-	rIndex := numericTypeIndex(typ)
-
-	if lIndex >= 0 && rIndex >= 0 {
-		if vx, err := converters[lIndex][rIndex](value); err == nil {
-			return typ, vx, true
-		}
-	}
-
-	return TypeUnknown, nil, false
-}
-
-//
-//
-
 func PromoteToCommonNumericTypes(left, right Type) (Type, error) {
 	lIndex := numericTypeIndex(left)
 	if lIndex < 0 {
@@ -114,10 +94,15 @@ func convertFromBigFloat() []func(value any) (any, error) {
 		}
 	}
 
+	// Unlike floating point values, numeric values round ties away from zero
+	roundingFromBigFloat := func(f func(value float64) (any, error)) func(value any) (any, error) {
+		return fromBigFloat(func(value float64) (any, error) { return f(math.Round(value)) })
+	}
+
 	return []func(value any) (any, error){
-		fromBigFloat(toSmallInteger),
-		fromBigFloat(toInteger),
-		fromBigFloat(toBigInteger),
+		roundingFromBigFloat(toSmallInteger),
+		roundingFromBigFloat(toInteger),
+		roundingFromBigFloat(toBigInteger),
 		fromBigFloat(toReal),
 		fromBigFloat(toDoublePrecision),
 		func(value any) (any, error) { return value, nil },
@@ -145,15 +130,28 @@ func toDoublePrecision[T constraints.Integer | constraints.Float](value T) (any,
 }
 
 func convertInteger[T constraints.Integer, R constraints.Integer | constraints.Float](value R, name string, maxValue T) (any, error) {
-	if int64(value) > int64(maxValue) {
+	// NaN compares false with the bounds below, so values that are not finite are rejected first
+	if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+		return nil, fmt.Errorf("%s out of range", name)
+	}
+
+	// Fractional values are rounded (to the nearest even integer on ties)
+	rounded := math.RoundToEven(float64(value))
+
+	// The minimum value of a signed integer type is -(maxValue + 1)
+	if rounded > float64(maxValue) || rounded < -float64(maxValue)-1 {
 		return nil, fmt.Errorf("value %v is out of range for %s", value, name)
 	}
 
+	if float64(value) != rounded {
+		return T(rounded), nil
+	}
+
 	return T(value), nil
 }
 
 func convertFloat[T constraints.Float, R constraints.Integer | constraints.Float](value R, name string, maxValue T) (any, error) {
-	if float64(value) > float64(maxValue) {
+	if math.Abs(float64(value)) > float64(maxValue) {
 		return nil, fmt.Errorf("value %v is out of range for %s", value, name)
 	}
 
@@ -164,7 +162,7 @@ func convertFloat[T constraints.Float, R constraints.Integer | constraints.Float
 //
 
 func IsNumeric(value any) bool {
-	return numericTypeKindFromValue(value) >= 0
+	return numericTypeKindFromValue(value) != TypeUnknown
 }
 
 func numericTypeIndex(value Type) int {
@@ -187,5 +185,5 @@ func numericTypeKindFromValue(value any) Type {
 		return TypeNumeric
 	}
 
-	return -1
+	return TypeUnknown
 }
@@ -82,6 +82,12 @@ func TestRefineNumericType(t *testing.T) {
 		failing("(large) numeric -> bigint", TypeBigInteger, huge),
 		failing("(large) numeric -> real", TypeReal, big.NewFloat(floatAfterMax32)),
 		failing("(large) numeric -> double precision", TypeDoublePrecision, huge),
+		failing("(NaN) real -> integer", TypeInteger, float32(math.NaN())),
+		failing("(NaN) double precision -> smallint", TypeSmallInteger, math.NaN()),
+		failing("(NaN) double precision -> integer", TypeInteger, math.NaN()),
+		failing("(NaN) double precision -> bigint", TypeBigInteger, math.NaN()),
+		failing("(infinite) double precision -> integer", TypeInteger, math.Inf(1)),
+		failing("(infinite) double precision -> bigint", TypeBigInteger, math.Inf(-1)),
 	} {
 		t.Run(testCase.name, func(t *testing.T) {
 			if _, value, ok := testCase.typ.Refine(testCase.input); testCase.ok {
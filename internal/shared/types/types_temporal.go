@@ -0,0 +1,188 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Date is the value of a date: the number of days since 1970-01-01.
+type Date int32
+
+// Time is the value of a time of day (without time zone): the number of microseconds
+// since midnight.
+type Time int64
+
+// Timestamp is the value of a timestamp without time zone: the number of microseconds
+// since 1970-01-01 00:00:00. Timestamps with a time zone are held as time.Time values.
+type Timestamp int64
+
+const (
+	secondsPerDay         = 24 * 60 * 60
+	microsecondsPerSecond = int64(time.Second / time.Microsecond)
+	microsecondsPerDay    = secondsPerDay * microsecondsPerSecond
+)
+
+// NewDate returns the date of the given time in its own location.
+func NewDate(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / secondsPerDay)
+}
+
+// Time returns midnight (UTC) of the date.
+func (d Date) Time() time.Time {
+	return time.Unix(int64(d)*secondsPerDay, 0).UTC()
+}
+
+func (d Date) AddDays(days int64) Date {
+	return Date(int64(d) + days)
+}
+
+func (d Date) String() string {
+	return d.Time().Format(dateFormat)
+}
+
+// NewTime returns the time of day of the given time in its own location.
+func NewTime(t time.Time) Time {
+	hour, minute, second := t.Clock()
+	seconds := int64(hour)*60*60 + int64(minute)*60 + int64(second)
+	return Time(seconds*microsecondsPerSecond + int64(t.Nanosecond())/1000)
+}
+
+// AddInterval returns the time of day reached by adding the time component of the given
+// interval, wrapping around midnight. Months and days of the interval are ignored.
+func (t Time) AddInterval(interval Interval) Time {
+	return Time(floorMod(int64(t)+interval.Microseconds, microsecondsPerDay))
+}
+
+func (t Time) String() string {
+	return time.UnixMicro(int64(t)).UTC().Format(timeFormat)
+}
+
+// NewTimestamp returns the timestamp matching the wall clock of the given time in its
+// own location.
+func NewTimestamp(t time.Time) Timestamp {
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	return Timestamp(time.Date(year, month, day, hour, minute, second, t.Nanosecond(), time.UTC).UnixMicro())
+}
+
+// Time returns the timestamp as a time in UTC.
+func (ts Timestamp) Time() time.Time {
+	return time.UnixMicro(int64(ts)).UTC()
+}
+
+func (ts Timestamp) String() string {
+	return ts.Time().Format(timestampWithoutTimeZoneFormat)
+}
+
+//
+//
+
+// temporalTypeRanks orders the types of points in time by precedence. Dates are
+// promoted to timestamps, and timestamps without a time zone are interpreted as UTC.
+var temporalTypeRanks = map[Type]int{
+	TypeDate:        1,
+	TypeTimestamp:   2,
+	TypeTimestampTz: 3,
+}
+
+func PromoteToCommonTemporalTypes(left, right Type) (Type, error) {
+	lRank, ok := temporalTypeRanks[left]
+	if !ok {
+		return TypeUnknown, fmt.Errorf("unexpected type (wanted date or timestamp type, have %v)", left)
+	}
+
+	rRank, ok := temporalTypeRanks[right]
+	if !ok {
+		return TypeUnknown, fmt.Errorf("unexpected type (wanted date or timestamp type, have %v)", right)
+	}
+
+	if lRank < rRank {
+		return right, nil
+	}
+
+	return left, nil
+}
+
+func PromoteToCommonTemporalValues(left, right any) (any, any, error) {
+	typ, err := PromoteToCommonTemporalTypes(TypeKindFromValue(left), TypeKindFromValue(right))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if left, err = Cast(left, typ, CastContextImplicit); err != nil {
+		return nil, nil, err
+	}
+
+	if right, err = Cast(right, typ, CastContextImplicit); err != nil {
+		return nil, nil, err
+	}
+
+	return left, right, nil
+}
+
+//
+//
+
+const (
+	dateFormat                     = "2006-01-02"
+	timeFormat                     = "15:04:05.999999"
+	timestampFormat                = "2006-01-02 15:04:05.999999-07"
+	timestampWithoutTimeZoneFormat = "2006-01-02 15:04:05.999999"
+)
+
+var timestampInputFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00:00",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	dateFormat,
+}
+
+var timeInputFormats = []string{
+	"15:04:05.999999999",
+	"15:04",
+}
+
+func parseTimestampTz(text string) (time.Time, bool) {
+	for _, layout := range timestampInputFormats {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// parseTimestamp parses a timestamp without time zone. Like Postgres, an explicit time
+// zone in the input is ignored.
+func parseTimestamp(text string) (Timestamp, bool) {
+	t, ok := parseTimestampTz(text)
+	return NewTimestamp(t), ok
+}
+
+// parseDate parses a date, ignoring any time of day in the input.
+func parseDate(text string) (Date, bool) {
+	t, ok := parseTimestampTz(text)
+	return NewDate(t), ok
+}
+
+func parseTime(text string) (Time, bool) {
+	for _, layout := range timeInputFormats {
+		if t, err := time.Parse(layout, strings.ToUpper(text)); err == nil {
+			return NewTime(t), true
+		}
+	}
+
+	return 0, false
+}
+
+// floorMod returns the remainder of a divided by b with the sign of b.
+func floorMod(a, b int64) int64 {
+	return ((a % b) + b) % b
+}
@@ -0,0 +1,134 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Character is the value of a char(n), padded with trailing spaces to the length of its
+// type. Trailing spaces are insignificant: they are ignored by comparisons and removed
+// when the value is converted to text.
+type Character string
+
+// Text returns the value without its trailing spaces.
+func (c Character) Text() string {
+	return strings.TrimRight(string(c), " ")
+}
+
+// FormatText returns the text representation of the given (non-NULL) value.
+func FormatText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case Character:
+		return string(v)
+	case bool:
+		if v {
+			return "t"
+		}
+		return "f"
+	case int16:
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case *big.Float:
+		return v.Text('f', -1)
+	case time.Time:
+		return v.Format(timestampFormat)
+	case fmt.Stringer:
+		// Date, Time, Timestamp, Interval, UUID, Bytea, JSONB, and Array
+		return v.String()
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// ParseText converts the text representation of a value into a value of the given type.
+func ParseText(text string, typ Type) (any, error) {
+	trimmed := strings.TrimSpace(text)
+
+	if typ.IsArray() {
+		return parseArray(trimmed, typ.ElementType())
+	}
+
+	switch typ {
+	case TypeSmallInteger:
+		if v, err := strconv.ParseInt(trimmed, 10, 16); err == nil {
+			return int16(v), nil
+		}
+	case TypeInteger:
+		if v, err := strconv.ParseInt(trimmed, 10, 32); err == nil {
+			return int32(v), nil
+		}
+	case TypeBigInteger:
+		if v, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return v, nil
+		}
+	case TypeReal:
+		if v, err := strconv.ParseFloat(trimmed, 32); err == nil {
+			return float32(v), nil
+		}
+	case TypeDoublePrecision:
+		if v, err := strconv.ParseFloat(trimmed, 64); err == nil {
+			return v, nil
+		}
+	case TypeNumeric:
+		if v, ok := new(big.Float).SetString(trimmed); ok {
+			return v, nil
+		}
+	case TypeBool:
+		switch strings.ToLower(trimmed) {
+		case "t", "true", "y", "yes", "on", "1":
+			return true, nil
+		case "f", "false", "n", "no", "off", "0":
+			return false, nil
+		}
+	case TypeTimestampTz:
+		if v, ok := parseTimestampTz(trimmed); ok {
+			return v, nil
+		}
+	case TypeDate:
+		if v, ok := parseDate(trimmed); ok {
+			return v, nil
+		}
+	case TypeTime:
+		if v, ok := parseTime(trimmed); ok {
+			return v, nil
+		}
+	case TypeTimestamp:
+		if v, ok := parseTimestamp(trimmed); ok {
+			return v, nil
+		}
+	case TypeInterval:
+		if v, ok := parseInterval(trimmed); ok {
+			return v, nil
+		}
+	case TypeUUID:
+		if v, ok := parseUUID(trimmed); ok {
+			return v, nil
+		}
+	case TypeBytea:
+		if v, ok := parseBytea(text); ok {
+			return v, nil
+		}
+	case TypeJSONB:
+		if v, ok := parseJSONB(trimmed); ok {
+			return v, nil
+		}
+	case TypeCharacter:
+		return Character(text), nil
+	default:
+		return text, nil
+	}
+
+	return nil, fmt.Errorf("invalid input syntax for type %s: %q", typ, text)
+}
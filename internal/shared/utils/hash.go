@@ -4,19 +4,56 @@ import (
 	"fmt"
 	"hash/fnv"
 	"strings"
+	"time"
+
+	"github.com/efritz/gostgres/internal/shared/types"
 )
 
 func Hash(value any) uint64 {
 	h := fnv.New64()
-	_, _ = h.Write([]byte(fmt.Sprintf("%v", value)))
+	_, _ = h.Write([]byte(hashText(value)))
 	return h.Sum64()
 }
 
 func HashSlice(values []any) string {
 	strValues := make([]string, 0, len(values))
 	for _, value := range values {
-		strValues = append(strValues, fmt.Sprintf("%v", value))
+		strValues = append(strValues, hashText(value))
 	}
 
 	return strings.Join(strValues, ":")
 }
+
+// hashText returns the text from which the hash of the given value is computed. Values
+// that compare equal hash alike: blank padded values hash as their text, points in time
+// hash as their UTC representation regardless of their time zone, and negative zero
+// hashes as zero.
+func hashText(value any) string {
+	switch v := value.(type) {
+	case types.Character:
+		return v.Text()
+
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+
+	case float32:
+		if v == 0 {
+			return "0"
+		}
+
+	case float64:
+		if v == 0 {
+			return "0"
+		}
+
+	case []any:
+		strValues := make([]string, 0, len(v))
+		for _, value := range v {
+			strValues = append(strValues, hashText(value))
+		}
+
+		return "[" + strings.Join(strValues, " ") + "]"
+	}
+
+	return fmt.Sprintf("%v", value)
+}
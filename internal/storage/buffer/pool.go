@@ -0,0 +1,294 @@
+package buffer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// PageSize is the size in bytes of every page read from or written to a file.
+const PageSize = 8192
+
+// Pool caches pages of files in a fixed number of in-memory frames.
+//
+// Pages are pinned while in use and cannot be evicted until every user has released
+// them. When a page not in the pool is requested and no frame is free, a victim is
+// chosen among the unpinned frames with the clock algorithm: a hand sweeps over the
+// frames, clearing the reference bit of each recently used frame and evicting the
+// first frame whose bit is already clear. Dirty victims are written back to their
+// file before the frame is reused. If every frame is pinned, the request waits until
+// a page is released.
+//
+// The pool synchronizes its own bookkeeping only. Users of a page must coordinate
+// concurrent access to its contents themselves.
+type Pool struct {
+	mu       sync.Mutex
+	released *sync.Cond
+	frames   []*frame
+	pages    map[pageKey]*frame
+	hand     int
+	nextID   int
+}
+
+type frame struct {
+	key   pageKey
+	data  []byte
+	valid bool
+	pins  int
+	ref   bool
+	dirty bool
+	file  *File
+}
+
+type pageKey struct {
+	fileID int
+	pageID int64
+}
+
+// NewPool creates a pool holding at most the given number of pages in memory.
+func NewPool(capacity int) *Pool {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	p := &Pool{
+		frames: make([]*frame, 0, capacity),
+		pages:  map[pageKey]*frame{},
+	}
+	p.released = sync.NewCond(&p.mu)
+
+	for i := 0; i < capacity; i++ {
+		p.frames = append(p.frames, &frame{data: make([]byte, PageSize)})
+	}
+
+	return p
+}
+
+// Capacity returns the number of pages the pool can hold in memory.
+func (p *Pool) Capacity() int {
+	return len(p.frames)
+}
+
+// File is a file whose pages are read and written through a pool.
+type File struct {
+	pool     *Pool
+	id       int
+	file     *os.File
+	numPages int64
+}
+
+// Open opens the file at the given path, creating it if it does not exist. The file
+// must consist of whole pages.
+func (p *Pool) Open(path string) (*File, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if info.Size()%PageSize != 0 {
+		_ = file.Close()
+		return nil, fmt.Errorf("%s is not a whole number of pages", path)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextID++
+	return &File{
+		pool:     p,
+		id:       p.nextID,
+		file:     file,
+		numPages: info.Size() / PageSize,
+	}, nil
+}
+
+// NumPages returns the number of pages in the file, including pages allocated but
+// not yet written back to disk.
+func (f *File) NumPages() int64 {
+	f.pool.mu.Lock()
+	defer f.pool.mu.Unlock()
+
+	return f.numPages
+}
+
+// Fetch pins and returns the page with the given identifier. The page must be released
+// once it is no longer used.
+func (f *File) Fetch(pageID int64) (*Page, error) {
+	f.pool.mu.Lock()
+	defer f.pool.mu.Unlock()
+
+	if pageID < 0 || pageID >= f.numPages {
+		return nil, fmt.Errorf("page %d out of range for %s", pageID, f.file.Name())
+	}
+
+	key := pageKey{fileID: f.id, pageID: pageID}
+	if fr, ok := f.pool.pages[key]; ok {
+		fr.pins++
+		fr.ref = true
+		return &Page{pool: f.pool, frame: fr}, nil
+	}
+
+	fr, err := f.pool.victim()
+	if err != nil {
+		return nil, err
+	}
+
+	if existing, ok := f.pool.pages[key]; ok {
+		// Read by another user while waiting for a free frame
+		existing.pins++
+		existing.ref = true
+		return &Page{pool: f.pool, frame: existing}, nil
+	}
+
+	if _, err := f.file.ReadAt(fr.data, pageID*PageSize); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	f.pool.install(fr, f, key)
+	return &Page{pool: f.pool, frame: fr}, nil
+}
+
+// Allocate pins and returns a new zeroed page at the end of the file.
+func (f *File) Allocate() (*Page, error) {
+	f.pool.mu.Lock()
+	defer f.pool.mu.Unlock()
+
+	fr, err := f.pool.victim()
+	if err != nil {
+		return nil, err
+	}
+
+	clear(fr.data)
+	key := pageKey{fileID: f.id, pageID: f.numPages}
+	f.numPages++
+
+	f.pool.install(fr, f, key)
+	fr.dirty = true
+	return &Page{pool: f.pool, frame: fr}, nil
+}
+
+// Flush writes every dirty page of the file back to disk.
+func (f *File) Flush() error {
+	f.pool.mu.Lock()
+	defer f.pool.mu.Unlock()
+
+	for _, fr := range f.pool.frames {
+		if fr.valid && fr.file == f && fr.dirty {
+			if err := fr.writeBack(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close discards the pages of the file held by the pool without writing them back
+// and closes the file. No page of the file may be pinned.
+func (f *File) Close() error {
+	f.pool.mu.Lock()
+	defer f.pool.mu.Unlock()
+
+	for _, fr := range f.pool.frames {
+		if fr.valid && fr.file == f {
+			delete(f.pool.pages, fr.key)
+			fr.valid, fr.file, fr.dirty, fr.ref = false, nil, false, false
+		}
+	}
+
+	return f.file.Close()
+}
+
+// victim returns an unpinned frame, writing back its current page if dirty. The pool
+// mutex must be held.
+func (p *Pool) victim() (*frame, error) {
+	for {
+		// Two sweeps clear every reference bit, after which any unpinned frame is chosen
+		for i := 0; i < 2*len(p.frames); i++ {
+			fr := p.frames[p.hand]
+			p.hand = (p.hand + 1) % len(p.frames)
+
+			if fr.pins > 0 {
+				continue
+			}
+			if fr.valid && fr.ref {
+				fr.ref = false
+				continue
+			}
+
+			if fr.valid {
+				if fr.dirty {
+					if err := fr.writeBack(); err != nil {
+						return nil, err
+					}
+				}
+
+				delete(p.pages, fr.key)
+				fr.valid, fr.file = false, nil
+			}
+
+			return fr, nil
+		}
+
+		p.released.Wait()
+	}
+}
+
+func (p *Pool) install(fr *frame, file *File, key pageKey) {
+	fr.key, fr.file = key, file
+	fr.valid, fr.pins, fr.ref, fr.dirty = true, 1, true, false
+	p.pages[key] = fr
+}
+
+func (fr *frame) writeBack() error {
+	if _, err := fr.file.file.WriteAt(fr.data, fr.key.pageID*PageSize); err != nil {
+		return fmt.Errorf("failed to write page %d of %s: %s", fr.key.pageID, fr.file.file.Name(), err)
+	}
+
+	fr.dirty = false
+	return nil
+}
+
+//
+//
+
+// Page is a pinned page of a file.
+type Page struct {
+	pool  *Pool
+	frame *frame
+}
+
+func (p *Page) ID() int64 {
+	return p.frame.key.pageID
+}
+
+// Data returns the contents of the page. The contents may be modified in place, after
+// which the page must be marked dirty. The slice must not be used after the page is
+// released.
+func (p *Page) Data() []byte {
+	return p.frame.data
+}
+
+func (p *Page) MarkDirty() {
+	p.pool.mu.Lock()
+	defer p.pool.mu.Unlock()
+
+	p.frame.dirty = true
+}
+
+// Release unpins the page.
+func (p *Page) Release() {
+	p.pool.mu.Lock()
+	defer p.pool.mu.Unlock()
+
+	p.frame.pins--
+	if p.frame.pins == 0 {
+		p.pool.released.Broadcast()
+	}
+}
@@ -0,0 +1,123 @@
+package buffer
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	pool := NewPool(2)
+	file, err := pool.Open(filepath.Join(t.TempDir(), "file"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	// Allocating more pages than fit in the pool evicts (and writes back) earlier pages
+	for i := 0; i < 5; i++ {
+		page, err := file.Allocate()
+		require.NoError(t, err)
+		assert.Equal(t, int64(i), page.ID())
+		assert.Equal(t, make([]byte, PageSize), page.Data())
+
+		page.Data()[0] = byte(i + 1)
+		page.MarkDirty()
+		page.Release()
+	}
+	assert.Equal(t, int64(5), file.NumPages())
+
+	for _, pageID := range []int64{3, 0, 4, 1, 2, 0} {
+		page, err := file.Fetch(pageID)
+		require.NoError(t, err)
+		assert.Equal(t, byte(pageID+1), page.Data()[0])
+
+		page.Data()[1] = byte(pageID + 1)
+		page.MarkDirty()
+		page.Release()
+	}
+
+	// Unflushed pages of a closed file are discarded
+	require.NoError(t, file.Flush())
+	require.NoError(t, file.Close())
+	file, err = pool.Open(file.file.Name())
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), file.NumPages())
+
+	for pageID := int64(0); pageID < 5; pageID++ {
+		page, err := file.Fetch(pageID)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{byte(pageID + 1), byte(pageID + 1)}, page.Data()[:2])
+		page.Release()
+	}
+
+	_, err = file.Fetch(5)
+	assert.ErrorContains(t, err, "out of range")
+}
+
+func TestPoolClockEviction(t *testing.T) {
+	pool := NewPool(3)
+	file, err := pool.Open(filepath.Join(t.TempDir(), "file"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	for i := 0; i < 4; i++ {
+		page, err := file.Allocate()
+		require.NoError(t, err)
+		page.Release()
+	}
+
+	resident := func() (pageIDs []int64) {
+		for _, fr := range pool.frames {
+			if fr.valid {
+				pageIDs = append(pageIDs, fr.key.pageID)
+			}
+		}
+		return pageIDs
+	}
+	assert.ElementsMatch(t, []int64{1, 2, 3}, resident())
+
+	// Page 1 was referenced again after the hand passed it; page 2 was not
+	page, err := file.Fetch(1)
+	require.NoError(t, err)
+	page.Release()
+	page, err = file.Fetch(0)
+	require.NoError(t, err)
+	page.Release()
+	assert.ElementsMatch(t, []int64{0, 1, 3}, resident())
+}
+
+func TestPoolPinned(t *testing.T) {
+	pool := NewPool(1)
+	file, err := pool.Open(filepath.Join(t.TempDir(), "file"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	first, err := file.Allocate()
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	fetched := make(chan struct{})
+	go func() {
+		defer wg.Done()
+
+		// Waits for the only frame to be released
+		second, err := file.Allocate()
+		assert.NoError(t, err)
+		close(fetched)
+		second.Release()
+	}()
+
+	select {
+	case <-fetched:
+		t.Fatal("pinned page was evicted")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	first.Release()
+	wg.Wait()
+	assert.Equal(t, int64(2), file.NumPages())
+}
@@ -0,0 +1,290 @@
+package encoding
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+// Value tags identify the Go type of an encoded value.
+const (
+	valueTagNull byte = iota
+	valueTagText
+	valueTagInt16
+	valueTagInt32
+	valueTagInt64
+	valueTagFloat32
+	valueTagFloat64
+	valueTagNumeric
+	valueTagBool
+	valueTagTimestamp
+	valueTagDate
+	valueTagTime
+	valueTagTimestampWithoutTimeZone
+	valueTagInterval
+	valueTagUUID
+	valueTagBytea
+	valueTagArray
+	valueTagJSONB
+	valueTagCharacter
+)
+
+func AppendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// AppendValues appends the given values to the buffer. Each value is tagged with
+// its type so that it decodes to a value of the same Go type.
+func AppendValues(buf []byte, values []any) ([]byte, error) {
+	buf = binary.AppendUvarint(buf, uint64(len(values)))
+	for _, value := range values {
+		var err error
+		if buf, err = AppendValue(buf, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf, nil
+}
+
+func AppendValue(buf []byte, value any) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, valueTagNull), nil
+	case string:
+		return AppendString(append(buf, valueTagText), v), nil
+	case int16:
+		return binary.AppendVarint(append(buf, valueTagInt16), int64(v)), nil
+	case int32:
+		return binary.AppendVarint(append(buf, valueTagInt32), int64(v)), nil
+	case int64:
+		return binary.AppendVarint(append(buf, valueTagInt64), v), nil
+	case float32:
+		return binary.BigEndian.AppendUint32(append(buf, valueTagFloat32), math.Float32bits(v)), nil
+	case float64:
+		return binary.BigEndian.AppendUint64(append(buf, valueTagFloat64), math.Float64bits(v)), nil
+	case bool:
+		if v {
+			return append(buf, valueTagBool, 1), nil
+		}
+		return append(buf, valueTagBool, 0), nil
+
+	case *big.Float:
+		data, err := v.GobEncode()
+		if err != nil {
+			return nil, err
+		}
+		return AppendString(append(buf, valueTagNumeric), string(data)), nil
+
+	case time.Time:
+		data, err := v.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return AppendString(append(buf, valueTagTimestamp), string(data)), nil
+
+	case types.Date:
+		return binary.AppendVarint(append(buf, valueTagDate), int64(v)), nil
+	case types.Time:
+		return binary.AppendVarint(append(buf, valueTagTime), int64(v)), nil
+	case types.Timestamp:
+		return binary.AppendVarint(append(buf, valueTagTimestampWithoutTimeZone), int64(v)), nil
+	case types.Interval:
+		buf = binary.AppendVarint(append(buf, valueTagInterval), int64(v.Months))
+		buf = binary.AppendVarint(buf, int64(v.Days))
+		return binary.AppendVarint(buf, v.Microseconds), nil
+	case types.UUID:
+		return append(append(buf, valueTagUUID), v[:]...), nil
+	case types.Bytea:
+		return AppendString(append(buf, valueTagBytea), string(v)), nil
+	case types.JSONB:
+		return AppendString(append(buf, valueTagJSONB), string(v)), nil
+	case types.Character:
+		return AppendString(append(buf, valueTagCharacter), string(v)), nil
+	case types.Array:
+		buf = binary.AppendVarint(append(buf, valueTagArray), int64(v.ElementType()))
+		return AppendValues(buf, v.Elements())
+	}
+
+	return nil, fmt.Errorf("cannot encode value of type %T", value)
+}
+
+//
+//
+
+// Decoder reads values from an encoded buffer. The first error encountered is
+// retained and reported by Err; subsequent reads return zero values.
+type Decoder struct {
+	buf []byte
+	err error
+}
+
+func NewDecoder(buf []byte) *Decoder {
+	return &Decoder{buf: buf}
+}
+
+// Err returns the first error encountered while decoding.
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// Remaining returns the number of bytes not yet decoded.
+func (d *Decoder) Remaining() int {
+	return len(d.buf)
+}
+
+func (d *Decoder) Values() []any {
+	n := d.Uvarint()
+
+	var values []any
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		values = append(values, d.Value())
+	}
+
+	return values
+}
+
+func (d *Decoder) Value() any {
+	switch tag := d.Byte(); tag {
+	case valueTagNull:
+		return nil
+	case valueTagText:
+		return d.String()
+	case valueTagInt16:
+		return int16(d.Varint())
+	case valueTagInt32:
+		return int32(d.Varint())
+	case valueTagInt64:
+		return d.Varint()
+	case valueTagFloat32:
+		return math.Float32frombits(d.Uint32())
+	case valueTagFloat64:
+		return math.Float64frombits(d.Uint64())
+	case valueTagBool:
+		return d.Byte() != 0
+
+	case valueTagNumeric:
+		v := new(big.Float)
+		if err := v.GobDecode([]byte(d.String())); err != nil {
+			d.Fail(err)
+		}
+		return v
+
+	case valueTagTimestamp:
+		var v time.Time
+		if err := v.UnmarshalBinary([]byte(d.String())); err != nil {
+			d.Fail(err)
+		}
+		return v
+
+	case valueTagDate:
+		return types.Date(d.Varint())
+	case valueTagTime:
+		return types.Time(d.Varint())
+	case valueTagTimestampWithoutTimeZone:
+		return types.Timestamp(d.Varint())
+	case valueTagInterval:
+		return types.Interval{Months: int32(d.Varint()), Days: int32(d.Varint()), Microseconds: d.Varint()}
+	case valueTagUUID:
+		var v types.UUID
+		copy(v[:], d.bytes(len(v)))
+		return v
+	case valueTagBytea:
+		return types.Bytea(d.String())
+	case valueTagJSONB:
+		return types.JSONB(d.String())
+	case valueTagCharacter:
+		return types.Character(d.String())
+	case valueTagArray:
+		elementType := types.Type(d.Varint())
+		return types.NewArray(elementType, d.Values())
+
+	default:
+		d.Fail(fmt.Errorf("unknown value tag %d", tag))
+		return nil
+	}
+}
+
+func (d *Decoder) Byte() byte {
+	if b := d.bytes(1); b != nil {
+		return b[0]
+	}
+
+	return 0
+}
+
+func (d *Decoder) String() string {
+	return string(d.bytes(int(d.Uvarint())))
+}
+
+func (d *Decoder) Uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.Fail(fmt.Errorf("malformed varint"))
+		return 0
+	}
+
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *Decoder) Varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.Fail(fmt.Errorf("malformed varint"))
+		return 0
+	}
+
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *Decoder) Uint32() uint32 {
+	if b := d.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+
+	return 0
+}
+
+func (d *Decoder) Uint64() uint64 {
+	if b := d.bytes(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+
+	return 0
+}
+
+// bytes returns the next n bytes of the buffer, or nil if they cannot be read.
+func (d *Decoder) bytes(n int) []byte {
+	if d.err == nil && (n < 0 || n > len(d.buf)) {
+		d.Fail(fmt.Errorf("unexpected end of data"))
+	}
+	if d.err != nil {
+		return nil
+	}
+
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+// Fail records the given error if no error has yet been encountered.
+func (d *Decoder) Fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
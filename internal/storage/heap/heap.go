@@ -0,0 +1,281 @@
+package heap
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/efritz/gostgres/internal/storage/buffer"
+)
+
+// Heap is an unordered collection of tuples stored in slotted pages of a file whose
+// pages are cached by a buffer pool.
+//
+// Each tuple is identified by a TID encoding its location: the page number in the
+// upper bits and the (1-based) slot number in the lower 16 bits. TIDs are therefore
+// never zero, and iterating over pages and slots in order visits tuples in TID order.
+// New tuples are appended to the last page of the file; slots of removed tuples are
+// not reused, so TIDs are not reassigned to other tuples.
+type Heap struct {
+	mu   sync.RWMutex
+	file *buffer.File
+}
+
+// Open opens the heap stored in the file at the given path, creating it if it does
+// not exist.
+func Open(pool *buffer.Pool, path string) (*Heap, error) {
+	file, err := pool.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Heap{file: file}, nil
+}
+
+func (h *Heap) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.file.Close()
+}
+
+func newTID(pageID int64, slot int) int64 {
+	return pageID<<16 | int64(slot)
+}
+
+func splitTID(tid int64) (int64, int, error) {
+	pageID, slot := tid>>16, int(tid&0xffff)
+	if tid < 0 || slot < 1 || slot > maxSlots {
+		return 0, 0, fmt.Errorf("invalid heap TID %d", tid)
+	}
+
+	return pageID, slot, nil
+}
+
+// checkSize returns an error if the given tuple cannot be stored in a page. Empty
+// tuples cannot be distinguished from unused slots and are also rejected.
+func checkSize(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("empty tuples cannot be stored")
+	}
+	if len(data) > MaxTupleSize {
+		return fmt.Errorf("row of %d bytes exceeds maximum size of %d bytes", len(data), MaxTupleSize)
+	}
+
+	return nil
+}
+
+// Insert stores a new tuple and returns its TID.
+func (h *Heap) Insert(data []byte) (int64, error) {
+	if err := checkSize(data); err != nil {
+		return 0, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if numPages := h.file.NumPages(); numPages > 0 {
+		if tid, ok, err := h.append(numPages-1, data); err != nil || ok {
+			return tid, err
+		}
+	}
+
+	pg, err := h.file.Allocate()
+	if err != nil {
+		return 0, err
+	}
+	defer pg.Release()
+
+	page(pg.Data()).place(1, data)
+	return newTID(pg.ID(), 1), nil
+}
+
+// append stores the given tuple in a new slot of the given page, or returns false if
+// it does not fit.
+func (h *Heap) append(pageID int64, data []byte) (int64, bool, error) {
+	pg, err := h.file.Fetch(pageID)
+	if err != nil {
+		return 0, false, err
+	}
+	defer pg.Release()
+
+	p := page(pg.Data())
+	if p.numSlots() >= maxSlots || len(data)+slotSize > p.available() {
+		return 0, false, nil
+	}
+
+	slot := p.numSlots() + 1
+	p.place(slot, data)
+	pg.MarkDirty()
+	return newTID(pageID, slot), true, nil
+}
+
+// InsertAt stores a tuple with the given TID, which must not be in use. Pages and
+// slots preceding the TID are added (empty) if they do not yet exist.
+func (h *Heap) InsertAt(tid int64, data []byte) error {
+	pageID, slot, err := splitTID(tid)
+	if err != nil {
+		return err
+	}
+	if err := checkSize(data); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for h.file.NumPages() <= pageID {
+		pg, err := h.file.Allocate()
+		if err != nil {
+			return err
+		}
+		pg.Release()
+	}
+
+	pg, err := h.file.Fetch(pageID)
+	if err != nil {
+		return err
+	}
+	defer pg.Release()
+
+	p := page(pg.Data())
+	if p.tuple(slot) != nil {
+		return fmt.Errorf("duplicate heap TID %d", tid)
+	}
+
+	required := len(data)
+	if slot > p.numSlots() {
+		required += (slot - p.numSlots()) * slotSize
+	}
+	if required > p.available() {
+		return fmt.Errorf("no space for heap TID %d", tid)
+	}
+
+	p.place(slot, data)
+	pg.MarkDirty()
+	return nil
+}
+
+// Read returns a copy of the tuple with the given TID.
+func (h *Heap) Read(tid int64) ([]byte, bool, error) {
+	var data []byte
+	ok, err := h.access(tid, false, func(tuple []byte) {
+		data = append([]byte(nil), tuple...)
+	})
+
+	return data, ok, err
+}
+
+// Update modifies the tuple with the given TID in place. The size of the tuple cannot
+// be changed.
+func (h *Heap) Update(tid int64, f func(data []byte)) (bool, error) {
+	return h.access(tid, true, f)
+}
+
+func (h *Heap) access(tid int64, write bool, f func(data []byte)) (bool, error) {
+	pageID, slot, err := splitTID(tid)
+	if err != nil {
+		return false, nil
+	}
+
+	if write {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+	} else {
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+	}
+
+	if pageID >= h.file.NumPages() {
+		return false, nil
+	}
+
+	pg, err := h.file.Fetch(pageID)
+	if err != nil {
+		return false, err
+	}
+	defer pg.Release()
+
+	tuple := page(pg.Data()).tuple(slot)
+	if tuple == nil {
+		return false, nil
+	}
+
+	f(tuple)
+	if write {
+		pg.MarkDirty()
+	}
+
+	return true, nil
+}
+
+// Delete removes the tuple with the given TID.
+func (h *Heap) Delete(tid int64) error {
+	pageID, slot, err := splitTID(tid)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	pg, err := h.file.Fetch(pageID)
+	if err != nil {
+		return err
+	}
+	defer pg.Release()
+
+	p := page(pg.Data())
+	if p.tuple(slot) == nil {
+		return fmt.Errorf("unknown heap TID %d", tid)
+	}
+
+	p.setSlot(slot, 0, 0)
+	pg.MarkDirty()
+	return nil
+}
+
+// Scan invokes f with each tuple of the heap in TID order. The data passed to f is
+// a copy that may be retained. Iteration stops at the first error returned by f.
+//
+// No page is pinned while f is invoked, so f may access this or other heaps.
+func (h *Heap) Scan(f func(tid int64, data []byte) error) error {
+	for pageID := int64(0); ; pageID++ {
+		tids, tuples, ok, err := h.readPage(pageID)
+		if err != nil || !ok {
+			return err
+		}
+
+		for i, tid := range tids {
+			if err := f(tid, tuples[i]); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readPage returns copies of the tuples of the given page, or false if the page does
+// not exist.
+func (h *Heap) readPage(pageID int64) (tids []int64, tuples [][]byte, _ bool, _ error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if pageID >= h.file.NumPages() {
+		return nil, nil, false, nil
+	}
+
+	pg, err := h.file.Fetch(pageID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer pg.Release()
+
+	p := page(pg.Data())
+	for slot := 1; slot <= p.numSlots(); slot++ {
+		if tuple := p.tuple(slot); tuple != nil {
+			tids = append(tids, newTID(pageID, slot))
+			tuples = append(tuples, append([]byte(nil), tuple...))
+		}
+	}
+
+	return tids, tuples, true, nil
+}
@@ -0,0 +1,106 @@
+package heap
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/efritz/gostgres/internal/storage/buffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "heap")
+	heap, err := Open(buffer.NewPool(2), path)
+	require.NoError(t, err)
+
+	tuple := func(i int) []byte {
+		return bytes.Repeat([]byte(fmt.Sprintf("%04d", i)), 100)
+	}
+
+	// Enough tuples to span many pages (and evict most of them)
+	var tids []int64
+	for i := 0; i < 100; i++ {
+		tid, err := heap.Insert(tuple(i))
+		require.NoError(t, err)
+		assert.NotZero(t, tid)
+		tids = append(tids, tid)
+	}
+	assert.Greater(t, tids[len(tids)-1]>>16, int64(1))
+
+	for i, tid := range tids {
+		data, ok, err := heap.Read(tid)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, tuple(i), data)
+	}
+
+	ok, err := heap.Update(tids[5], func(data []byte) { copy(data, "xxxx") })
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	for i := 0; i < len(tids); i += 2 {
+		require.NoError(t, heap.Delete(tids[i]))
+	}
+	assert.Error(t, heap.Delete(tids[0]))
+
+	_, ok, err = heap.Read(tids[0])
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	var scanned []int64
+	require.NoError(t, heap.Scan(func(tid int64, data []byte) error {
+		scanned = append(scanned, tid)
+		return nil
+	}))
+
+	var expected []int64
+	for i := 1; i < len(tids); i += 2 {
+		expected = append(expected, tids[i])
+	}
+	assert.Equal(t, expected, scanned)
+
+	// Removed tuples are restored at their original location
+	require.NoError(t, heap.InsertAt(tids[4], tuple(4)))
+	assert.ErrorContains(t, heap.InsertAt(tids[4], tuple(4)), "duplicate heap TID")
+
+	data, ok, err := heap.Read(tids[5])
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, append([]byte("xxxx"), tuple(5)[4:]...), data)
+
+	_, err = heap.Insert(make([]byte, MaxTupleSize+1))
+	assert.ErrorContains(t, err, "exceeds maximum size")
+	require.NoError(t, heap.Close())
+}
+
+func TestHeapInsertAt(t *testing.T) {
+	heap, err := Open(buffer.NewPool(1), filepath.Join(t.TempDir(), "heap"))
+	require.NoError(t, err)
+	defer heap.Close()
+
+	for _, tid := range []int64{newTID(3, 10), newTID(0, 2), newTID(3, 1)} {
+		require.NoError(t, heap.InsertAt(tid, []byte(fmt.Sprintf("tuple %d", tid))))
+	}
+	assert.Error(t, heap.InsertAt(newTID(1, 0), []byte("tuple")))
+
+	var scanned []string
+	require.NoError(t, heap.Scan(func(tid int64, data []byte) error {
+		scanned = append(scanned, string(data))
+		return nil
+	}))
+	assert.Equal(t, []string{
+		fmt.Sprintf("tuple %d", newTID(0, 2)),
+		fmt.Sprintf("tuple %d", newTID(3, 1)),
+		fmt.Sprintf("tuple %d", newTID(3, 10)),
+	}, scanned)
+
+	// A page is compacted to make room for new tuples
+	full := newTID(4, 1)
+	require.NoError(t, heap.InsertAt(full, make([]byte, MaxTupleSize)))
+	require.NoError(t, heap.Delete(full))
+	require.NoError(t, heap.InsertAt(newTID(4, 2), make([]byte, MaxTupleSize-slotSize)))
+	assert.ErrorContains(t, heap.InsertAt(full, []byte("tuple")), "no space")
+}
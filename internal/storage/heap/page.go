@@ -0,0 +1,135 @@
+package heap
+
+import (
+	"encoding/binary"
+
+	"github.com/efritz/gostgres/internal/storage/buffer"
+)
+
+// A slotted page begins with a header holding the number of slots and the offset at
+// which tuple data begins, followed by an array of slots. Each slot holds the offset
+// and length of a tuple. Tuple data is placed at the end of the page and grows toward
+// the slot array. Slots of removed tuples have zero length; the space of their data is
+// reclaimed by compacting the page when a new tuple would otherwise not fit.
+//
+//	+--------+--------+-----+--------+---- free ----+---------+-----+---------+
+//	| header | slot 1 | ... | slot n |              | tuple n | ... | tuple 1 |
+//	+--------+--------+-----+--------+--------------+---------+-----+---------+
+const (
+	headerSize = 4
+	slotSize   = 4
+
+	// maxSlots is the number of slots that fit in a page holding only empty tuples.
+	maxSlots = (buffer.PageSize - headerSize) / slotSize
+)
+
+// MaxTupleSize is the size of the largest tuple that can be stored in a heap.
+const MaxTupleSize = buffer.PageSize - headerSize - slotSize
+
+type page []byte
+
+func (p page) numSlots() int {
+	return int(binary.BigEndian.Uint16(p))
+}
+
+func (p page) setNumSlots(n int) {
+	binary.BigEndian.PutUint16(p, uint16(n))
+}
+
+// dataStart returns the offset of the first byte of tuple data.
+func (p page) dataStart() int {
+	if start := int(binary.BigEndian.Uint16(p[2:])); start != 0 {
+		return start
+	}
+
+	// Zeroed (newly allocated) page
+	return buffer.PageSize
+}
+
+func (p page) setDataStart(start int) {
+	binary.BigEndian.PutUint16(p[2:], uint16(start))
+}
+
+// slot returns the offset and length of the tuple in the given (1-based) slot.
+func (p page) slot(n int) (offset, length int) {
+	s := p[headerSize+(n-1)*slotSize:]
+	return int(binary.BigEndian.Uint16(s)), int(binary.BigEndian.Uint16(s[2:]))
+}
+
+func (p page) setSlot(n, offset, length int) {
+	s := p[headerSize+(n-1)*slotSize:]
+	binary.BigEndian.PutUint16(s, uint16(offset))
+	binary.BigEndian.PutUint16(s[2:], uint16(length))
+}
+
+// tuple returns the data of the tuple in the given slot, or nil if the slot is unused.
+func (p page) tuple(n int) []byte {
+	if n < 1 || n > p.numSlots() {
+		return nil
+	}
+
+	offset, length := p.slot(n)
+	if length == 0 {
+		return nil
+	}
+
+	return p[offset : offset+length]
+}
+
+// available returns the number of bytes that can be used for new slots and tuple data
+// once the page is compacted.
+func (p page) available() int {
+	used := headerSize + p.numSlots()*slotSize
+	for n := 1; n <= p.numSlots(); n++ {
+		_, length := p.slot(n)
+		used += length
+	}
+
+	return buffer.PageSize - used
+}
+
+// place stores the given data in the given slot, which must be unused. Slots between
+// the current last slot and the given slot are added as unused slots. The caller must
+// ensure that the data fits.
+func (p page) place(n int, data []byte) {
+	if numSlots := p.numSlots(); n > numSlots {
+		extra := (n - numSlots) * slotSize
+		if headerSize+numSlots*slotSize+extra > p.dataStart() {
+			p.compact()
+		}
+
+		clear(p[headerSize+numSlots*slotSize : headerSize+n*slotSize])
+		p.setNumSlots(n)
+	}
+
+	if headerSize+p.numSlots()*slotSize+len(data) > p.dataStart() {
+		p.compact()
+	}
+
+	offset := p.dataStart() - len(data)
+	copy(p[offset:], data)
+	p.setDataStart(offset)
+	p.setSlot(n, offset, len(data))
+}
+
+// compact moves the data of all tuples to the end of the page so that the free space
+// between the slot array and the tuple data is contiguous.
+func (p page) compact() {
+	var tuples [][]byte
+	for n := 1; n <= p.numSlots(); n++ {
+		tuples = append(tuples, append([]byte(nil), p.tuple(n)...))
+	}
+
+	offset := buffer.PageSize
+	for i, data := range tuples {
+		if len(data) == 0 {
+			continue
+		}
+
+		offset -= len(data)
+		copy(p[offset:], data)
+		p.setSlot(i+1, offset, len(data))
+	}
+
+	p.setDataStart(offset)
+}
@@ -0,0 +1,87 @@
+package wal
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/storage/encoding"
+)
+
+func encodeRecords(records []Record) ([]byte, error) {
+	buf := binary.AppendUvarint(nil, uint64(len(records)))
+	for _, record := range records {
+		var err error
+		if buf, err = appendRecord(buf, record); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf, nil
+}
+
+func appendRecord(buf []byte, record Record) ([]byte, error) {
+	buf = append(buf, byte(record.Type))
+
+	switch record.Type {
+	case RecordTypeInsert:
+		buf = encoding.AppendString(buf, record.Name)
+		buf = binary.AppendVarint(buf, record.TID)
+		return encoding.AppendValues(buf, record.Values)
+
+	case RecordTypeDelete:
+		buf = encoding.AppendString(buf, record.Name)
+		return binary.AppendVarint(buf, record.TID), nil
+
+	case RecordTypeSequenceAdvance, RecordTypeSequenceSet:
+		buf = encoding.AppendString(buf, record.Name)
+		return binary.AppendVarint(buf, record.Value), nil
+
+	case RecordTypeDDL:
+		return encoding.AppendString(buf, record.Statement), nil
+	}
+
+	return nil, fmt.Errorf("unknown record type %d", record.Type)
+}
+
+func decodeRecords(buf []byte) ([]Record, error) {
+	d := encoding.NewDecoder(buf)
+
+	n := d.Uvarint()
+	records := make([]Record, 0, min(n, uint64(len(buf))))
+	for i := uint64(0); i < n && d.Err() == nil; i++ {
+		records = append(records, decodeRecord(d))
+	}
+
+	if d.Err() == nil && d.Remaining() > 0 {
+		d.Fail(fmt.Errorf("unexpected trailing data"))
+	}
+
+	return records, d.Err()
+}
+
+func decodeRecord(d *encoding.Decoder) Record {
+	record := Record{Type: RecordType(d.Byte())}
+
+	switch record.Type {
+	case RecordTypeInsert:
+		record.Name = d.String()
+		record.TID = d.Varint()
+		record.Values = d.Values()
+
+	case RecordTypeDelete:
+		record.Name = d.String()
+		record.TID = d.Varint()
+
+	case RecordTypeSequenceAdvance, RecordTypeSequenceSet:
+		record.Name = d.String()
+		record.Value = d.Varint()
+
+	case RecordTypeDDL:
+		record.Statement = d.String()
+
+	default:
+		d.Fail(fmt.Errorf("unknown record type %d", record.Type))
+	}
+
+	return record
+}
@@ -0,0 +1,334 @@
+package wal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Log is an append-only file recording the changes of committed transactions.
+//
+// The file begins with a header identifying the format, followed by one entry for
+// each committed transaction. Each entry holds the length and checksum of its payload
+// followed by the payload itself: the encoded records of the transaction, in the order
+// in which the changes were made. A transaction is durable once its entry has been
+// written and synced in full. Entries that are incomplete or fail their checksum are
+// the remnants of a crash during a write and, along with anything following them,
+// are discarded when the log is opened.
+//
+// Entries are numbered consecutively by log sequence number (LSN). The header holds
+// the LSN of the first entry in the file, which is non-zero once entries preceding a
+// checkpoint have been discarded.
+type Log struct {
+	mu   sync.Mutex
+	file *os.File
+	base int64
+	next int64
+	err  error
+}
+
+var (
+	magic      = []byte("GOSTGWAL")
+	version    = uint32(2)
+	headerSize = len(magic) + 4 + 8 // magic, version, and LSN of the first entry
+	crcTable   = crc32.MakeTable(crc32.Castagnoli)
+	entryBytes = 8 // length and checksum of an entry
+)
+
+// Open opens the log at the given path, creating it if it does not exist. The records
+// of each committed transaction in the log are returned in commit order; the first
+// transaction has the LSN returned by Base.
+func Open(path string) (*Log, [][]Record, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+
+	base, entries, end, err := scan(file.Name(), data)
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+
+	var transactions [][]Record
+	for _, entry := range entries {
+		records, err := decodeRecords(entry.payload)
+		if err != nil {
+			_ = file.Close()
+			return nil, nil, fmt.Errorf("malformed write-ahead log entry at offset %d: %s", entry.offset, err)
+		}
+
+		transactions = append(transactions, records)
+	}
+
+	// Discard any partially written entry so that new entries directly follow the
+	// last complete one
+	if err := truncate(file, end); err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+
+	return &Log{
+		file: file,
+		base: base,
+		next: base + int64(len(transactions)),
+	}, transactions, nil
+}
+
+type entry struct {
+	offset  int
+	payload []byte
+}
+
+// scan returns the LSN of the first entry of the given log data, its complete entries,
+// and the offset at which the last complete entry ends. A log whose header is not
+// present in full is empty.
+func scan(name string, data []byte) (base int64, entries []entry, end int, _ error) {
+	if n := min(len(data), len(magic)); !bytes.Equal(data[:n], magic[:n]) {
+		return 0, nil, 0, fmt.Errorf("%s is not a write-ahead log", name)
+	}
+	if len(data) < headerSize {
+		// Empty, or a crash occurred while the header was written
+		return 0, nil, 0, nil
+	}
+	if v := binary.BigEndian.Uint32(data[len(magic):]); v != version {
+		return 0, nil, 0, fmt.Errorf("%s has unsupported write-ahead log version %d", name, v)
+	}
+
+	base = int64(binary.BigEndian.Uint64(data[len(magic)+4:]))
+	offset := headerSize
+	for {
+		payload, n := readEntry(data[offset:])
+		if n == 0 {
+			break
+		}
+
+		entries = append(entries, entry{offset: offset, payload: payload})
+		offset += n
+	}
+
+	return base, entries, offset, nil
+}
+
+// readEntry returns the payload of the entry at the start of the given data along with
+// the size of the entry, or zero if the data does not begin with a complete entry.
+func readEntry(data []byte) ([]byte, int) {
+	if len(data) < entryBytes {
+		return nil, 0
+	}
+
+	length := int(binary.BigEndian.Uint32(data))
+	checksum := binary.BigEndian.Uint32(data[4:])
+	if length > len(data)-entryBytes {
+		return nil, 0
+	}
+
+	payload := data[entryBytes : entryBytes+length]
+	if crc32.Checksum(payload, crcTable) != checksum {
+		return nil, 0
+	}
+
+	return payload, entryBytes + length
+}
+
+func appendEntry(buf, payload []byte) []byte {
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(payload)))
+	buf = binary.BigEndian.AppendUint32(buf, crc32.Checksum(payload, crcTable))
+	return append(buf, payload...)
+}
+
+func appendHeader(buf []byte, base int64) []byte {
+	buf = append(buf, magic...)
+	buf = binary.BigEndian.AppendUint32(buf, version)
+	return binary.BigEndian.AppendUint64(buf, uint64(base))
+}
+
+func truncate(file *os.File, end int) error {
+	if end == 0 {
+		if err := file.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := file.WriteAt(appendHeader(nil, 0), 0); err != nil {
+			return err
+		}
+
+		end = headerSize
+	} else if err := file.Truncate(int64(end)); err != nil {
+		return err
+	}
+
+	if _, err := file.Seek(int64(end), io.SeekStart); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+// Base returns the LSN of the first entry in the log.
+func (l *Log) Base() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.base
+}
+
+// NextLSN returns the LSN that will be assigned to the next appended entry.
+func (l *Log) NextLSN() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.next
+}
+
+// Append durably records the changes of a committed transaction. If the log cannot
+// be written, the log is left in an unknown state and all further appends fail.
+func (l *Log) Append(records []Record) error {
+	payload, err := encodeRecords(records)
+	if err != nil {
+		return err
+	}
+
+	entry := appendEntry(make([]byte, 0, entryBytes+len(payload)), payload)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err != nil {
+		return l.err
+	}
+
+	if _, err := l.file.Write(entry); err != nil {
+		l.err = fmt.Errorf("failed to write to write-ahead log: %s", err)
+		return l.err
+	}
+	if err := l.file.Sync(); err != nil {
+		l.err = fmt.Errorf("failed to sync write-ahead log: %s", err)
+		return l.err
+	}
+
+	l.next++
+	return nil
+}
+
+// Discard removes the entries preceding the given LSN from the log, such as those
+// whose changes have been written to a checkpoint. The log is replaced atomically, so
+// a crash leaves either the old or the new log in place.
+func (l *Log) Discard(lsn int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err != nil {
+		return l.err
+	}
+	if lsn <= l.base {
+		return nil
+	}
+	if lsn > l.next {
+		return fmt.Errorf("cannot discard write-ahead log entries up to LSN %d beyond the end of the log (%d)", lsn, l.next)
+	}
+
+	path := l.file.Name()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	_, entries, end, err := scan(path, data)
+	if err != nil {
+		return err
+	}
+
+	start := end
+	if i := int(lsn - l.base); i < len(entries) {
+		start = entries[i].offset
+	}
+
+	file, err := replace(path, append(appendHeader(nil, lsn), data[start:end]...))
+	if err != nil {
+		// The open file may no longer be the file at the log's path
+		l.err = fmt.Errorf("failed to replace write-ahead log: %s", err)
+		return l.err
+	}
+
+	_ = l.file.Close()
+	l.file = file
+	l.base = lsn
+	return nil
+}
+
+// replace atomically replaces the file at the given path with the given contents and
+// returns the new file, opened for appending.
+func replace(path string, data []byte) (*os.File, error) {
+	temp := path + ".tmp"
+	if err := WriteFileAtomic(temp, path, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// WriteFileAtomic writes a file at the given path with the contents written by f. The
+// contents are first written and synced to the given temporary path, which is then
+// renamed, so that the file at the given path is never observed partially written.
+func WriteFileAtomic(temp, path string, f func(w io.Writer) error) error {
+	file, err := os.Create(temp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp)
+
+	if err := f(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(temp, path); err != nil {
+		return err
+	}
+
+	// Make the rename itself durable
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
+
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file.Close()
+}
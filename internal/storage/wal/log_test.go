@@ -0,0 +1,154 @@
+package wal
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+	transactions := [][]Record{
+		{
+			NewDDLRecord("CREATE TABLE t (id integer)"),
+			NewInsertRecord("t", 1, []any{nil, "text", int16(1), int32(2), int64(3), float32(4.5), float64(5.5), big.NewFloat(6.5), true}),
+			NewSequenceAdvanceRecord("s", 10),
+		},
+		{
+			NewInsertRecord("t", 2, []any{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), types.Date(19724), types.Time(3600e6), types.Timestamp(-1), types.Interval{Months: 1, Days: -2, Microseconds: 3}, types.UUID{1, 2, 3}, types.Bytea("\x00\xff"), types.NewArray(types.TypeText, []any{"a", nil})}),
+			NewDeleteRecord("t", 1),
+			NewSequenceSetRecord("s", 3),
+		},
+	}
+
+	log, recovered, err := Open(path)
+	require.NoError(t, err)
+	assert.Empty(t, recovered)
+	for _, records := range transactions {
+		require.NoError(t, log.Append(records))
+	}
+	require.NoError(t, log.Close())
+
+	log, recovered, err = Open(path)
+	require.NoError(t, err)
+	require.NoError(t, log.Close())
+	require.Len(t, recovered, len(transactions))
+
+	for i, records := range recovered {
+		require.Len(t, records, len(transactions[i]))
+
+		for j, record := range records {
+			expected := transactions[i][j]
+			assert.Equal(t, expected.Type, record.Type)
+			assert.Equal(t, expected.Name, record.Name)
+			assert.Equal(t, expected.TID, record.TID)
+			assert.Equal(t, expected.Value, record.Value)
+			assert.Equal(t, expected.Statement, record.Statement)
+			require.Len(t, record.Values, len(expected.Values))
+
+			for k, value := range record.Values {
+				switch v := value.(type) {
+				case *big.Float:
+					assert.Zero(t, v.Cmp(expected.Values[k].(*big.Float)))
+				case time.Time:
+					assert.True(t, v.Equal(expected.Values[k].(time.Time)))
+				default:
+					assert.Equal(t, expected.Values[k], value)
+				}
+			}
+		}
+	}
+}
+
+func TestLogTornWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+
+	log, _, err := Open(path)
+	require.NoError(t, err)
+
+	var ends []int64
+	for i := int64(1); i <= 3; i++ {
+		require.NoError(t, log.Append([]Record{NewInsertRecord("t", i, []any{"value"}), NewDeleteRecord("t", i-1)}))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		ends = append(ends, info.Size())
+	}
+	require.NoError(t, log.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	for size := 0; size <= len(data); size++ {
+		require.NoError(t, os.WriteFile(path, data[:size], 0o644))
+
+		expected := 0
+		for _, end := range ends {
+			if int64(size) >= end {
+				expected++
+			}
+		}
+
+		log, recovered, err := Open(path)
+		require.NoError(t, err)
+		assert.Len(t, recovered, expected, "size %d", size)
+
+		// Appends following a torn write must be readable
+		require.NoError(t, log.Append([]Record{NewDeleteRecord("t", 4)}))
+		require.NoError(t, log.Close())
+
+		log, recovered, err = Open(path)
+		require.NoError(t, err)
+		require.NoError(t, log.Close())
+		assert.Len(t, recovered, expected+1, "size %d", size)
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte("not a log at all"), 0o644))
+	_, _, err = Open(path)
+	assert.ErrorContains(t, err, "is not a write-ahead log")
+}
+
+func TestLogDiscard(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+
+	log, _, err := Open(path)
+	require.NoError(t, err)
+	for i := int64(1); i <= 5; i++ {
+		require.NoError(t, log.Append([]Record{NewDeleteRecord("t", i)}))
+	}
+	assert.Equal(t, int64(0), log.Base())
+	assert.Equal(t, int64(5), log.NextLSN())
+
+	require.NoError(t, log.Discard(3))
+	require.NoError(t, log.Discard(2)) // already discarded
+	assert.Error(t, log.Discard(7))
+	require.NoError(t, log.Append([]Record{NewDeleteRecord("t", 6)}))
+	require.NoError(t, log.Close())
+
+	log, recovered, err := Open(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), log.Base())
+	assert.Equal(t, int64(6), log.NextLSN())
+
+	var tids []int64
+	for _, records := range recovered {
+		tids = append(tids, records[0].TID)
+	}
+	assert.Equal(t, []int64{4, 5, 6}, tids)
+
+	// Discarding every entry leaves an empty log
+	require.NoError(t, log.Discard(6))
+	require.NoError(t, log.Close())
+
+	log, recovered, err = Open(path)
+	require.NoError(t, err)
+	require.NoError(t, log.Close())
+	assert.Empty(t, recovered)
+	assert.Equal(t, int64(6), log.Base())
+}
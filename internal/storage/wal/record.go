@@ -0,0 +1,49 @@
+package wal
+
+// RecordType identifies the kind of change described by a log record.
+type RecordType byte
+
+const (
+	RecordTypeInsert          RecordType = iota + 1 // a row was inserted into a table
+	RecordTypeDelete                                // a row was deleted from a table
+	RecordTypeSequenceAdvance                       // a sequence produced a value
+	RecordTypeSequenceSet                           // a sequence was set to a value
+	RecordTypeDDL                                   // a DDL statement was executed
+)
+
+// Record describes a single change made by a transaction. The fields that are
+// populated depend on the type of the record.
+type Record struct {
+	Type      RecordType
+	Name      string // the table or sequence that was changed
+	TID       int64  // the TID of the inserted or deleted row
+	Values    []any  // the values of the inserted row (excluding its TID)
+	Value     int64  // the value of the sequence
+	Statement string // the text of the DDL statement
+}
+
+func NewInsertRecord(table string, tid int64, values []any) Record {
+	return Record{Type: RecordTypeInsert, Name: table, TID: tid, Values: values}
+}
+
+func NewDeleteRecord(table string, tid int64) Record {
+	return Record{Type: RecordTypeDelete, Name: table, TID: tid}
+}
+
+// NewSequenceAdvanceRecord records that a sequence produced the given value. Sequences
+// produce values outside of transaction control, so concurrent transactions may commit
+// the values they obtained out of order; replaying an advance never moves a sequence
+// backwards.
+func NewSequenceAdvanceRecord(sequence string, value int64) Record {
+	return Record{Type: RecordTypeSequenceAdvance, Name: sequence, Value: value}
+}
+
+func NewSequenceSetRecord(sequence string, value int64) Record {
+	return Record{Type: RecordTypeSequenceSet, Name: sequence, Value: value}
+}
+
+// NewDDLRecord records the source text of a DDL statement. Schema changes are replayed
+// by re-executing the statement against the catalog as it existed when it was logged.
+func NewDDLRecord(statement string) Record {
+	return Record{Type: RecordTypeDDL, Statement: statement}
+}
@@ -0,0 +1,136 @@
+package wal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// A snapshot holds the records describing the complete state of a database. It begins
+// with a header identifying the format and the LSN of the first log entry whose changes
+// are not reflected in the snapshot. Records follow in entries framed and checksummed
+// as in the log, each holding a batch of records. An empty entry marks the end of the
+// snapshot so that a truncated snapshot is detected.
+var (
+	snapshotMagic      = []byte("GOSTGSNP")
+	snapshotVersion    = uint32(1)
+	snapshotHeaderSize = len(snapshotMagic) + 4 + 8 // magic, version, and LSN
+	snapshotBatchBytes = 64 * 1024
+)
+
+// maxSnapshotEntryBytes bounds the size of an entry so that a corrupt length does not
+// cause an arbitrarily large allocation.
+const maxSnapshotEntryBytes = 1 << 30
+
+// SnapshotWriter writes a snapshot to an underlying writer.
+type SnapshotWriter struct {
+	w   io.Writer
+	buf []byte
+	n   int
+}
+
+// NewSnapshotWriter writes the header of a snapshot reflecting the changes of log entries
+// preceding the given LSN.
+func NewSnapshotWriter(w io.Writer, lsn int64) (*SnapshotWriter, error) {
+	header := append([]byte(nil), snapshotMagic...)
+	header = binary.BigEndian.AppendUint32(header, snapshotVersion)
+	header = binary.BigEndian.AppendUint64(header, uint64(lsn))
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &SnapshotWriter{w: w}, nil
+}
+
+func (s *SnapshotWriter) Write(record Record) error {
+	var err error
+	if s.buf, err = appendRecord(s.buf, record); err != nil {
+		return err
+	}
+
+	s.n++
+	if len(s.buf) >= snapshotBatchBytes {
+		return s.flush()
+	}
+
+	return nil
+}
+
+func (s *SnapshotWriter) flush() error {
+	if s.n == 0 {
+		return nil
+	}
+
+	payload := append(binary.AppendUvarint(nil, uint64(s.n)), s.buf...)
+	s.buf, s.n = s.buf[:0], 0
+
+	_, err := s.w.Write(appendEntry(nil, payload))
+	return err
+}
+
+// Close writes any buffered records and marks the end of the snapshot. It does not
+// close the underlying writer.
+func (s *SnapshotWriter) Close() error {
+	if err := s.flush(); err != nil {
+		return err
+	}
+
+	_, err := s.w.Write(appendEntry(nil, nil))
+	return err
+}
+
+// ReadSnapshot invokes f with each record of the snapshot read from the given reader,
+// in the order in which they were written, and returns the LSN recorded in its header.
+func ReadSnapshot(r io.Reader, f func(record Record) error) (int64, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, snapshotHeaderSize)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return 0, fmt.Errorf("failed to read snapshot header: %s", err)
+	}
+	if !bytes.Equal(header[:len(snapshotMagic)], snapshotMagic) {
+		return 0, fmt.Errorf("not a snapshot")
+	}
+	if v := binary.BigEndian.Uint32(header[len(snapshotMagic):]); v != snapshotVersion {
+		return 0, fmt.Errorf("unsupported snapshot version %d", v)
+	}
+	lsn := int64(binary.BigEndian.Uint64(header[len(snapshotMagic)+4:]))
+
+	for {
+		frame := make([]byte, entryBytes)
+		if _, err := io.ReadFull(br, frame); err != nil {
+			return 0, fmt.Errorf("snapshot is incomplete: %s", err)
+		}
+
+		length := binary.BigEndian.Uint32(frame)
+		if length > maxSnapshotEntryBytes {
+			return 0, fmt.Errorf("snapshot is corrupt")
+		}
+
+		frame = append(frame, make([]byte, length)...)
+		if _, err := io.ReadFull(br, frame[entryBytes:]); err != nil {
+			return 0, fmt.Errorf("snapshot is incomplete: %s", err)
+		}
+
+		payload, n := readEntry(frame)
+		if n == 0 {
+			return 0, fmt.Errorf("snapshot is corrupt")
+		}
+		if len(payload) == 0 {
+			return lsn, nil
+		}
+
+		records, err := decodeRecords(payload)
+		if err != nil {
+			return 0, fmt.Errorf("snapshot is corrupt: %s", err)
+		}
+
+		for _, record := range records {
+			if err := f(record); err != nil {
+				return 0, err
+			}
+		}
+	}
+}
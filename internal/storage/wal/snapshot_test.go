@@ -0,0 +1,51 @@
+package wal
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	var records []Record
+	records = append(records, NewDDLRecord("CREATE TABLE t (id integer, name text)"), NewSequenceSetRecord("s", 42))
+	for i := int64(1); i <= 5000; i++ {
+		// Enough records to span several entries
+		records = append(records, NewInsertRecord("t", i, []any{int32(i), strings.Repeat("x", 20)}))
+	}
+
+	var buf bytes.Buffer
+	w, err := NewSnapshotWriter(&buf, 17)
+	require.NoError(t, err)
+	for _, record := range records {
+		require.NoError(t, w.Write(record))
+	}
+	require.NoError(t, w.Close())
+	data := buf.Bytes()
+
+	var read []Record
+	lsn, err := ReadSnapshot(bytes.NewReader(data), func(record Record) error {
+		read = append(read, record)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(17), lsn)
+	assert.Equal(t, records, read)
+
+	for _, size := range []int{0, 10, snapshotHeaderSize, len(data) / 2, len(data) - 1} {
+		_, err := ReadSnapshot(bytes.NewReader(data[:size]), func(Record) error { return nil })
+		assert.Error(t, err, "size %d", size)
+	}
+
+	corrupt := bytes.Clone(data)
+	corrupt[len(corrupt)/2] ^= 0xff
+	_, err = ReadSnapshot(bytes.NewReader(corrupt), func(Record) error { return nil })
+	assert.ErrorContains(t, err, "corrupt")
+
+	_, err = ReadSnapshot(bytes.NewReader(data), func(Record) error { return fmt.Errorf("oops") })
+	assert.ErrorContains(t, err, "oops")
+}
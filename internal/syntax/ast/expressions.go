@@ -18,6 +18,14 @@ func ResolveExpression(
 	}
 
 	mappedExpr, err := expr.Map(func(expr impls.Expression) (impls.Expression, error) {
+		if subquery, ok := expr.(*subqueryExpression); ok {
+			if err := subquery.resolveQuery(ctx); err != nil {
+				return nil, err
+			}
+
+			return subquery, nil
+		}
+
 		if named, ok := expr.(expressions.NamedExpression); ok {
 			field, err := ctx.Lookup(named.Field().RelationName(), named.Field().Name())
 			if err != nil {
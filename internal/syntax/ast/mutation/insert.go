@@ -3,10 +3,12 @@ package mutation
 import (
 	"fmt"
 
+	"github.com/efritz/gostgres/internal/execution/expressions"
 	"github.com/efritz/gostgres/internal/execution/projection"
 	"github.com/efritz/gostgres/internal/execution/queries/plan"
 	"github.com/efritz/gostgres/internal/execution/queries/plan/mutation"
 	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
 	"github.com/efritz/gostgres/internal/syntax/ast"
 )
 
@@ -29,10 +31,20 @@ func (b *InsertBuilder) Resolve(ctx *impls.NodeResolutionContext) error {
 
 	// TODO - resolve column names
 
+	if values, ok := b.Source.(*ast.ValuesBuilder); ok {
+		values.Untyped = true
+	}
+
 	if err := b.Source.Resolve(ctx); err != nil {
 		return err
 	}
 
+	if values, ok := b.Source.(*ast.ValuesBuilder); ok {
+		if err := b.inferParameterTypes(values); err != nil {
+			return err
+		}
+	}
+
 	returning, err := resolveReturning(ctx, b.table, b.Target.AliasName, b.Returning)
 	if err != nil {
 		return err
@@ -42,6 +54,43 @@ func (b *InsertBuilder) Resolve(ctx *impls.NodeResolutionContext) error {
 	return nil
 }
 
+// inferParameterTypes assigns the types of the target columns to untyped parameters
+// that are inserted into those columns directly. An error is returned if a string
+// literal inserted into a column is not valid input for its type.
+func (b *InsertBuilder) inferParameterTypes(values *ast.ValuesBuilder) error {
+	var columnTypes []types.Type
+	if b.ColumnNames == nil {
+		for _, field := range b.table.Fields() {
+			if !field.Internal() {
+				columnTypes = append(columnTypes, field.Type())
+			}
+		}
+	} else {
+		for _, name := range b.ColumnNames {
+			typ := types.TypeUnknown
+			for _, field := range b.table.Fields() {
+				if field.Name() == name {
+					typ = field.Type()
+				}
+			}
+
+			columnTypes = append(columnTypes, typ)
+		}
+	}
+
+	for _, rowExpressions := range values.Expressions {
+		for i, expr := range rowExpressions {
+			if i < len(columnTypes) {
+				if err := expressions.InferType(expr, columnTypes[i]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 func (b *InsertBuilder) Build() (plan.LogicalNode, error) {
 	node, err := b.Source.Build()
 	if err != nil {
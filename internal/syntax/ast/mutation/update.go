@@ -3,6 +3,7 @@ package mutation
 import (
 	"fmt"
 
+	"github.com/efritz/gostgres/internal/execution/expressions"
 	"github.com/efritz/gostgres/internal/execution/projection"
 	mutationNodes "github.com/efritz/gostgres/internal/execution/queries/nodes/mutation"
 	"github.com/efritz/gostgres/internal/execution/queries/plan"
@@ -65,6 +66,14 @@ func (b *UpdateBuilder) Resolve(ctx *impls.NodeResolutionContext) error {
 			return err
 		}
 
+		for _, field := range table.Fields() {
+			if field.Name() == setExpression.Name {
+				if err := expressions.InferType(resolved, field.Type()); err != nil {
+					return err
+				}
+			}
+		}
+
 		b.Updates[i].Expression = resolved
 	}
 
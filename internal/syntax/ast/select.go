@@ -6,6 +6,7 @@ import (
 
 	"github.com/efritz/gostgres/internal/execution/expressions"
 	"github.com/efritz/gostgres/internal/execution/projection"
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
 	"github.com/efritz/gostgres/internal/execution/queries/plan"
 	"github.com/efritz/gostgres/internal/execution/queries/plan/combination"
 	"github.com/efritz/gostgres/internal/shared/fields"
@@ -14,17 +15,30 @@ import (
 )
 
 type SelectBuilder struct {
+	Distinct          bool
+	DistinctOn        []impls.Expression
 	SelectExpressions []projection.ProjectionExpression
 	From              *TableExpression
 	Where             impls.Expression
 	Groupings         []impls.Expression
+	Having            impls.Expression
+	Windows           []NamedWindow
 	Combinations      []*CombinationDescription
 	Order             impls.OrderExpression
 	Limit             *int
 	Offset            *int
+	Locking           *LockingClause
 
-	fields     []fields.Field
-	projection *projection.Projection
+	fields            []fields.Field
+	projection        *projection.Projection
+	groupedProjection *projection.Projection
+	windowFunctions   []nodes.WindowFunction
+	locking           *plan.RowLocking
+}
+
+type LockingClause struct {
+	Mode          impls.LockMode
+	RelationNames []string // empty to lock rows of all tables in the from clause
 }
 
 type CombinationDescription struct {
@@ -42,6 +56,10 @@ func (b *SelectBuilder) Resolve(ctx *impls.NodeResolutionContext) error {
 		return err
 	}
 
+	if err := b.resolveLocking(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -64,13 +82,28 @@ func (b *SelectBuilder) resolvePrimarySelect(ctx *impls.NodeResolutionContext) e
 	}
 	b.Where = resolved
 
-	projection, err := ResolveProjection(ctx, "", fromFields, b.SelectExpressions, nil)
+	windows, err := b.resolveWindows(ctx)
 	if err != nil {
 		return err
 	}
+
+	var projection *projection.Projection
+	if err := ctx.WithWindowFunctions(windows, func() (err error) {
+		projection, err = ResolveProjection(ctx, "", fromFields, b.SelectExpressions, nil)
+		return err
+	}); err != nil {
+		return err
+	}
 	b.projection = projection
 	b.fields = projection.Fields()
 
+	// The having condition is evaluated over the input rows of each group
+	having, err := ResolveExpression(ctx, b.Having, nil, true)
+	if err != nil {
+		return err
+	}
+	b.Having = having
+
 	ctx.PushScope()
 	defer ctx.PopScope()
 	ctx.Bind(b.fields)
@@ -88,6 +121,9 @@ func (b *SelectBuilder) resolvePrimarySelect(ctx *impls.NodeResolutionContext) e
 	for _, selectExpression := range projection.Aliases() {
 		rawProjectedExpressions = append(rawProjectedExpressions, selectExpression.Expression)
 	}
+	if b.Having != nil {
+		rawProjectedExpressions = append(rawProjectedExpressions, b.Having)
+	}
 	_, nonAggregatedFields, containsAggregate, err := expressions.PartitionAggregatedFieldReferences(
 		ctx.ExpressionResolutionContext(true),
 		rawProjectedExpressions,
@@ -97,7 +133,8 @@ func (b *SelectBuilder) resolvePrimarySelect(ctx *impls.NodeResolutionContext) e
 		return err
 	}
 
-	if len(b.Groupings) == 0 && containsAggregate {
+	// A select with a having clause but no group by clause forms a single group
+	if len(b.Groupings) == 0 && (containsAggregate || b.Having != nil) {
 		b.Groupings = []impls.Expression{expressions.NewConstant(nil)}
 	}
 
@@ -115,23 +152,110 @@ func (b *SelectBuilder) resolvePrimarySelect(ctx *impls.NodeResolutionContext) e
 	}
 
 	if b.Order != nil {
-		resolved, err := b.Order.Map(func(expr impls.Expression) (impls.Expression, error) {
-			if len(b.Groupings) > 0 {
-				return ResolveExpression(ctx, expr, nil, false)
+		resolveOrder := func() error {
+			resolved, err := b.Order.Map(func(expr impls.Expression) (impls.Expression, error) {
+				if len(b.Groupings) > 0 {
+					return ResolveExpression(ctx, expr, nil, false)
+				}
+
+				return ResolveExpression(ctx, expr, projection, false)
+			})
+			if err != nil {
+				return err
 			}
 
-			return ResolveExpression(ctx, expr, projection, false)
-		})
+			b.Order = resolved
+			return nil
+		}
+
+		if len(b.Groupings) == 0 && len(b.Combinations) == 0 {
+			// Window functions may be invoked directly by the order of a simple select
+			if err := ctx.WithWindowFunctions(windows, resolveOrder); err != nil {
+				return err
+			}
+		} else if err := resolveOrder(); err != nil {
+			return err
+		}
+	}
+
+	if err := b.extractWindowFunctions(ctx); err != nil {
+		return err
+	}
+
+	if len(b.Groupings) > 0 && len(b.windowFunctions) > 0 {
+		if err := b.extractGroupedExpressions(ctx); err != nil {
+			return err
+		}
+	}
+
+	return b.resolveDistinct(ctx)
+}
+
+func (b *SelectBuilder) resolveDistinct(ctx *impls.NodeResolutionContext) error {
+	for i, expr := range b.DistinctOn {
+		var resolved impls.Expression
+		var err error
+		if len(b.Groupings) > 0 {
+			resolved, err = ResolveExpression(ctx, expr, nil, false)
+		} else {
+			resolved, err = ResolveExpression(ctx, expr, b.projection, false)
+		}
 		if err != nil {
 			return err
 		}
 
-		b.Order = resolved
+		b.DistinctOn[i] = resolved
+	}
+
+	if !b.Distinct || b.Order == nil || len(b.Combinations) > 0 {
+		return nil
+	}
+
+	orderExpressions := b.Order.Expressions()
+
+	if len(b.DistinctOn) > 0 {
+		for _, expr := range orderExpressions[:min(len(orderExpressions), len(b.DistinctOn))] {
+			if !slices.ContainsFunc(b.DistinctOn, expr.Expression.Equal) {
+				return fmt.Errorf("SELECT DISTINCT ON expressions must match initial ORDER BY expressions")
+			}
+		}
+
+		return nil
+	}
+
+	// Rows would otherwise be ordered by values discarded with their duplicates
+	distinctExpressions := b.distinctExpressions()
+	for _, expr := range orderExpressions {
+		if !slices.ContainsFunc(distinctExpressions, expr.Expression.Equal) {
+			return fmt.Errorf("for SELECT DISTINCT, ORDER BY expressions must appear in select list")
+		}
 	}
 
 	return nil
 }
 
+// distinctExpressions returns the expressions whose values distinguish the rows of a
+// SELECT DISTINCT query. These expressions are evaluated before the projection of a
+// select without groupings, and after the projection of a select with groupings.
+func (b *SelectBuilder) distinctExpressions() []impls.Expression {
+	if len(b.DistinctOn) > 0 {
+		return b.DistinctOn
+	}
+
+	var distinctExpressions []impls.Expression
+	if len(b.Groupings) > 0 {
+		for _, field := range b.fields {
+			distinctExpressions = append(distinctExpressions, expressions.NewNamed(field))
+		}
+	} else {
+		for _, alias := range b.projection.Aliases() {
+			distinctExpressions = append(distinctExpressions, alias.Expression)
+		}
+	}
+
+	return distinctExpressions
+}
+
 func (b *SelectBuilder) resolveCombinations(ctx *impls.NodeResolutionContext) error {
 	for _, c := range b.Combinations {
 		if err := ctx.WithScope(func() error {
@@ -149,6 +273,57 @@ func (b *SelectBuilder) resolveCombinations(ctx *impls.NodeResolutionContext) er
 	return nil
 }
 
+func (b *SelectBuilder) resolveLocking() error {
+	if b.Locking == nil {
+		return nil
+	}
+
+	clause := b.Locking.Mode.String()
+	if len(b.Combinations) > 0 {
+		return fmt.Errorf("%s is not allowed with UNION/INTERSECT/EXCEPT", clause)
+	}
+	if len(b.Groupings) > 0 {
+		return fmt.Errorf("%s is not allowed with GROUP BY clause or aggregate functions", clause)
+	}
+	if len(b.windowFunctions) > 0 {
+		return fmt.Errorf("%s is not allowed with window functions", clause)
+	}
+	if b.Distinct {
+		return fmt.Errorf("%s is not allowed with DISTINCT clause", clause)
+	}
+
+	relations := b.From.lockableRelations(false)
+	if len(b.Locking.RelationNames) > 0 {
+		var selected []lockableRelation
+		for _, name := range b.Locking.RelationNames {
+			i := slices.IndexFunc(relations, func(relation lockableRelation) bool { return relation.Name == name })
+			if i < 0 {
+				return fmt.Errorf("relation %q in %s clause not found in from clause", name, clause)
+			}
+
+			selected = append(selected, relations[i])
+		}
+
+		relations = selected
+	}
+
+	lockedRelations := make([]nodes.LockedRelation, 0, len(relations))
+	for _, relation := range relations {
+		if relation.nullable {
+			return fmt.Errorf("%s cannot be applied to the nullable side of an outer join", clause)
+		}
+
+		lockedRelations = append(lockedRelations, relation.LockedRelation)
+	}
+
+	b.locking = &plan.RowLocking{
+		Mode:      b.Locking.Mode,
+		Relations: lockedRelations,
+	}
+
+	return nil
+}
+
 func (b *SelectBuilder) TableFields() []fields.Field {
 	return slices.Clone(b.fields)
 }
@@ -159,60 +334,145 @@ func (b *SelectBuilder) Build() (plan.LogicalNode, error) {
 		return nil, err
 	}
 
-	if len(b.Combinations) > 0 {
-		node = plan.NewSelect(
-			node,
-			b.projection,
-			b.Groupings,
-			b.Where,
-			nil,
-			nil,
-			nil,
-		)
+	node, where, err := decorrelate(node, b.From.TableFields(), b.Where)
+	if err != nil {
+		return nil, err
+	}
 
-		for _, c := range b.Combinations {
-			var factory func(left, right plan.LogicalNode, distinct bool) (plan.LogicalNode, error)
-			switch c.Type {
-			case tokens.TokenTypeUnion:
-				factory = combination.NewUnion
-			case tokens.TokenTypeIntersect:
-				factory = combination.NewIntersect
-			case tokens.TokenTypeExcept:
-				factory = combination.NewExcept
-			}
+	if len(b.windowFunctions) > 0 {
+		if len(b.Groupings) > 0 {
+			return b.buildGroupedWindow(node, where)
+		}
 
-			right, err := c.Select.Build()
-			if err != nil {
-				return nil, err
-			}
+		// Window functions are computed over the filtered rows
+		node = plan.NewWindow(plan.NewSelect(node, nil, nil, nil, where, nil, nil, nil, nil), b.windowFunctions)
+		where = nil
+	}
 
-			newNode, err := factory(node, right, c.Distinct)
-			if err != nil {
-				return nil, err
-			}
-			node = newNode
+	if len(b.Combinations) == 0 {
+		if b.Distinct {
+			return plan.NewSelect(
+				b.buildDistinct(node, where, b.Order),
+				nil,
+				nil,
+				nil,
+				nil,
+				nil,
+				b.Limit,
+				b.Offset,
+				nil,
+			), nil
 		}
 
 		return plan.NewSelect(
 			node,
-			nil,
-			nil,
-			nil,
+			b.projection,
+			b.Groupings,
+			b.Having,
+			where,
 			b.Order,
 			b.Limit,
 			b.Offset,
+			b.locking,
 		), nil
+	}
+
+	if b.Distinct {
+		node = b.buildDistinct(node, where, nil)
 	} else {
 		node = plan.NewSelect(
 			node,
 			b.projection,
 			b.Groupings,
-			b.Where,
-			b.Order,
-			b.Limit,
-			b.Offset,
+			b.Having,
+			where,
+			nil,
+			nil,
+			nil,
+			nil,
 		)
+	}
 
-		return node, nil
+	return b.buildCombinations(node)
+}
+
+// buildGroupedWindow returns a node emitting the rows of a select with groupings and
+// window functions. The window functions are computed over the groups remaining after
+// the having condition is applied, and the projection of the select is applied to the
+// groups and the values of the window functions.
+func (b *SelectBuilder) buildGroupedWindow(node plan.LogicalNode, where impls.Expression) (plan.LogicalNode, error) {
+	node = plan.NewSelect(node, b.groupedProjection, b.Groupings, b.Having, where, nil, nil, nil, nil)
+	node = plan.NewWindow(node, b.windowFunctions)
+	node = plan.NewSelect(node, b.projection, nil, nil, nil, nil, nil, nil, nil)
+
+	order := b.Order
+	if len(b.Combinations) > 0 {
+		order = nil
 	}
+
+	if b.Distinct {
+		node = plan.NewSelect(node, nil, nil, nil, nil, order, nil, nil, nil)
+		node = plan.NewDistinct(node, b.distinctExpressions(), len(b.DistinctOn) > 0)
+		order = nil
+	}
+
+	if len(b.Combinations) == 0 {
+		return plan.NewSelect(node, nil, nil, nil, nil, order, b.Limit, b.Offset, nil), nil
+	}
+
+	return b.buildCombinations(node)
+}
+
+// buildCombinations returns a node combining the given node with the selects of each
+// combination in turn, emitting the combined rows in the order of the select.
+func (b *SelectBuilder) buildCombinations(node plan.LogicalNode) (plan.LogicalNode, error) {
+	for _, c := range b.Combinations {
+		var factory func(left, right plan.LogicalNode, distinct bool) (plan.LogicalNode, error)
+		switch c.Type {
+		case tokens.TokenTypeUnion:
+			factory = combination.NewUnion
+		case tokens.TokenTypeIntersect:
+			factory = combination.NewIntersect
+		case tokens.TokenTypeExcept:
+			factory = combination.NewExcept
+		}
+
+		right, err := c.Select.Build()
+		if err != nil {
+			return nil, err
+		}
+
+		newNode, err := factory(node, right, c.Distinct)
+		if err != nil {
+			return nil, err
+		}
+		node = newNode
+	}
+
+	return plan.NewSelect(
+		node,
+		nil,
+		nil,
+		nil,
+		nil,
+		b.Order,
+		b.Limit,
+		b.Offset,
+		nil,
+	), nil
+}
+
+// buildDistinct returns a node emitting the distinct projected rows of the select in
+// the given order. Rows of a select without groupings are made distinct before they
+// are projected, as DISTINCT ON and ORDER BY expressions may refer to columns that
+// are not projected.
+func (b *SelectBuilder) buildDistinct(node plan.LogicalNode, where impls.Expression, order impls.OrderExpression) plan.LogicalNode {
+	if len(b.Groupings) > 0 {
+		node = plan.NewSelect(node, b.projection, b.Groupings, b.Having, where, order, nil, nil, nil)
+		return plan.NewDistinct(node, b.distinctExpressions(), len(b.DistinctOn) > 0)
+	}
+
+	node = plan.NewSelect(node, nil, nil, nil, where, order, nil, nil, nil)
+	node = plan.NewDistinct(node, b.distinctExpressions(), len(b.DistinctOn) > 0)
+	return plan.NewSelect(node, b.projection, nil, nil, nil, nil, nil, nil, nil)
 }
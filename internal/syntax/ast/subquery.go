@@ -0,0 +1,550 @@
+package ast
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	concreteJoin "github.com/efritz/gostgres/internal/execution/queries/nodes/join"
+	"github.com/efritz/gostgres/internal/execution/queries/plan"
+	logicalJoin "github.com/efritz/gostgres/internal/execution/queries/plan/join"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/rows"
+	"github.com/efritz/gostgres/internal/shared/scan"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+// ComparisonOperator compares a value with each row of a subquery (e.g., the = of
+// x = ANY (SELECT ...)).
+type ComparisonOperator struct {
+	Symbol  string
+	Factory func(left, right impls.Expression) impls.Expression
+
+	// Negated is true if the operator is the negation of the factory's comparison
+	// (e.g., <> as the negation of =)
+	Negated bool
+}
+
+// Compare returns the comparison of the given expressions.
+func (o ComparisonOperator) Compare(left, right impls.Expression) impls.Expression {
+	if o.Negated {
+		return expressions.NewNot(o.Factory(left, right))
+	}
+
+	return o.Factory(left, right)
+}
+
+// Matches returns an expression that is true when the comparison of the given
+// expressions has the given value (and is false otherwise, including when the
+// comparison is NULL).
+func (o ComparisonOperator) Matches(left, right impls.Expression, value bool) impls.Expression {
+	comparison := o.Factory(left, right)
+
+	if value != o.Negated {
+		return expressions.NewIsTrue(comparison)
+	}
+
+	return expressions.NewIsFalse(comparison)
+}
+
+//
+//
+
+type subqueryKind int
+
+const (
+	subqueryKindExists subqueryKind = iota
+	subqueryKindAny
+	subqueryKindAll
+	subqueryKindScalar
+)
+
+type subqueryExpression struct {
+	kind     subqueryKind
+	left     impls.Expression
+	operator ComparisonOperator
+	query    TableReferenceOrExpression
+
+	// outerFields are the fields of enclosing queries on which the subquery depends.
+	// Their values are computed by the expressions of outer (initially references to
+	// the fields) and supplied to the subquery as an outer row.
+	outerFields []fields.Field
+	outer       []impls.Expression
+
+	// plan is shared by the copies of the expression made while optimizing the query
+	plan *subqueryPlan
+}
+
+var _ expressions.SubqueryExpression = &subqueryExpression{}
+
+// NewExists creates an expression that is true if the given query returns any rows.
+func NewExists(query TableReferenceOrExpression) impls.Expression {
+	return newSubquery(subqueryKindExists, nil, ComparisonOperator{}, query)
+}
+
+// NewAny creates an expression comparing the given value with the value of each row of
+// the given single-column query. It is true if any comparison is true, false if every
+// comparison (of which there may be none) is false, and NULL otherwise.
+func NewAny(left impls.Expression, operator ComparisonOperator, query TableReferenceOrExpression) impls.Expression {
+	return newSubquery(subqueryKindAny, left, operator, query)
+}
+
+// NewAll creates an expression comparing the given value with the value of each row of
+// the given single-column query. It is true if every comparison (of which there may be
+// none) is true, false if any comparison is false, and NULL otherwise.
+func NewAll(left impls.Expression, operator ComparisonOperator, query TableReferenceOrExpression) impls.Expression {
+	return newSubquery(subqueryKindAll, left, operator, query)
+}
+
+// NewScalar creates an expression whose value is that of the single column of the row
+// returned by the given query, or NULL if no row is returned. It is an error for the
+// query to return more than one row.
+func NewScalar(query TableReferenceOrExpression) impls.Expression {
+	return newSubquery(subqueryKindScalar, nil, ComparisonOperator{}, query)
+}
+
+func newSubquery(kind subqueryKind, left impls.Expression, operator ComparisonOperator, query TableReferenceOrExpression) *subqueryExpression {
+	return &subqueryExpression{
+		kind:     kind,
+		left:     left,
+		operator: operator,
+		query:    query,
+		plan:     &subqueryPlan{},
+	}
+}
+
+func (e subqueryExpression) Subquery() {}
+
+func (e subqueryExpression) String() string {
+	switch e.kind {
+	case subqueryKindAny:
+		return fmt.Sprintf("%s %s any(subquery)", e.left, e.operator.Symbol)
+	case subqueryKindAll:
+		return fmt.Sprintf("%s %s all(subquery)", e.left, e.operator.Symbol)
+	case subqueryKindScalar:
+		return "(subquery)"
+	}
+
+	return "exists(subquery)"
+}
+
+// Name returns the name of the column of a scalar subquery used as a select expression,
+// which is that of the single select expression of the subquery.
+func (e subqueryExpression) Name() string {
+	if query, ok := e.query.(*SelectBuilder); ok && e.kind == subqueryKindScalar && len(query.SelectExpressions) == 1 {
+		if aliased, ok := query.SelectExpressions[0].(interface{ Alias() string }); ok {
+			return aliased.Alias()
+		}
+	}
+
+	return "?column?"
+}
+
+// resolveQuery resolves the subquery within the given context, in which the fields of
+// enclosing queries are bound.
+func (e *subqueryExpression) resolveQuery(ctx *impls.NodeResolutionContext) error {
+	references, err := ctx.WithSubqueryScope(func() error {
+		return e.query.Resolve(ctx)
+	})
+	if err != nil {
+		return err
+	}
+
+	e.outerFields = references
+	e.outer = nil
+	for _, field := range references {
+		e.outer = append(e.outer, expressions.NewNamed(field))
+	}
+
+	return nil
+}
+
+func (e *subqueryExpression) Resolve(ctx impls.ExpressionResolutionContext) error {
+	for _, expr := range e.Children() {
+		if err := expr.Resolve(ctx); err != nil {
+			return err
+		}
+	}
+
+	if e.kind == subqueryKindExists {
+		return nil
+	}
+
+	queryFields := e.query.TableFields()
+	if e.kind == subqueryKindScalar {
+		if len(queryFields) != 1 {
+			return fmt.Errorf("subquery must return only one column")
+		}
+
+		return nil
+	}
+
+	if len(queryFields) != 1 {
+		return fmt.Errorf("subquery has too many columns")
+	}
+
+	// Ensure the values of each side can be compared
+	return e.operator.Compare(e.left, expressions.NewNamed(queryFields[0])).Resolve(ctx)
+}
+
+func (e subqueryExpression) Type() types.Type {
+	if e.kind == subqueryKindScalar {
+		if queryFields := e.query.TableFields(); len(queryFields) == 1 {
+			return queryFields[0].Type()
+		}
+
+		return types.TypeAny
+	}
+
+	return types.TypeBool
+}
+
+func (e subqueryExpression) Equal(other impls.Expression) bool {
+	o, ok := other.(*subqueryExpression)
+	if !ok || e.kind != o.kind || e.query != o.query || e.operator.Symbol != o.operator.Symbol {
+		return false
+	}
+
+	if (e.left == nil) != (o.left == nil) || (e.left != nil && !e.left.Equal(o.left)) {
+		return false
+	}
+
+	if len(e.outer) != len(o.outer) {
+		return false
+	}
+	for i, expr := range e.outer {
+		if !expr.Equal(o.outer[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (e subqueryExpression) Children() []impls.Expression {
+	var children []impls.Expression
+	if e.left != nil {
+		children = append(children, e.left)
+	}
+
+	return append(children, e.outer...)
+}
+
+func (e subqueryExpression) Fold() impls.Expression {
+	if e.left != nil {
+		e.left = e.left.Fold()
+	}
+
+	outer := make([]impls.Expression, 0, len(e.outer))
+	for _, expr := range e.outer {
+		outer = append(outer, expr.Fold())
+	}
+	e.outer = outer
+
+	return &e
+}
+
+func (e subqueryExpression) Map(f func(impls.Expression) (impls.Expression, error)) (impls.Expression, error) {
+	if e.left != nil {
+		left, err := e.left.Map(f)
+		if err != nil {
+			return nil, err
+		}
+		e.left = left
+	}
+
+	outer := make([]impls.Expression, 0, len(e.outer))
+	for _, expr := range e.outer {
+		mapped, err := expr.Map(f)
+		if err != nil {
+			return nil, err
+		}
+
+		outer = append(outer, mapped)
+	}
+	e.outer = outer
+
+	return f(&e)
+}
+
+func (e subqueryExpression) ValueFrom(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	var left any
+	if e.left != nil {
+		value, err := e.left.ValueFrom(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		left = value
+	}
+
+	switch e.kind {
+	case subqueryKindExists:
+		return e.memoize(ctx, func() (any, error) { return e.exists(ctx, row) })
+	case subqueryKindScalar:
+		return e.memoize(ctx, func() (any, error) { return e.scalar(ctx, row) })
+	}
+
+	scanner, err := e.scanner(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	// ANY is decided by a true comparison, and ALL by a false one
+	decisive := e.kind == subqueryKindAny
+
+	// Without a decisive comparison, the result is NULL if any comparison is NULL
+	var result any = !decisive
+	if err := scan.VisitRows(scanner, func(queryRow rows.Row) (bool, error) {
+		comparison := e.operator.Compare(expressions.NewConstant(left), expressions.NewConstant(queryRow.Values[0]))
+
+		value, err := types.ValueAs[bool](comparison.ValueFrom(ctx, rows.Row{}))
+		if err != nil {
+			return false, err
+		}
+
+		if value == nil {
+			result = nil
+			return true, nil
+		}
+
+		if *value == decisive {
+			result = decisive
+			return false, nil
+		}
+
+		return true, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// memoize returns the value computed by the given function. The value of a subquery not
+// depending on fields of enclosing queries is computed once per statement.
+func (e subqueryExpression) memoize(ctx impls.ExecutionContext, f func() (any, error)) (any, error) {
+	if len(e.outerFields) > 0 {
+		return f()
+	}
+
+	return ctx.Memoize(e.plan, f)
+}
+
+func (e subqueryExpression) exists(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	scanner, err := e.scanner(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := scanner.Scan(); err != nil {
+		if err == scan.ErrNoRows {
+			return false, nil
+		}
+
+		return nil, err
+	}
+
+	return true, nil
+}
+
+func (e subqueryExpression) scalar(ctx impls.ExecutionContext, row rows.Row) (any, error) {
+	scanner, err := e.scanner(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	queryRow, err := scanner.Scan()
+	if err != nil {
+		if err == scan.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if _, err := scanner.Scan(); err != scan.ErrNoRows {
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("more than one row returned by a subquery used as an expression")
+	}
+
+	return queryRow.Values[0], nil
+}
+
+// scanner returns a scanner over the rows of the subquery for the given row of the
+// enclosing query.
+func (e subqueryExpression) scanner(ctx impls.ExecutionContext, row rows.Row) (scan.RowScanner, error) {
+	outerValues := make([]any, 0, len(e.outer))
+	for _, expr := range e.outer {
+		value, err := expr.ValueFrom(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		outerValues = append(outerValues, value)
+	}
+
+	node, err := e.plan.build(ctx, e.query, e.outerFields)
+	if err != nil {
+		return nil, err
+	}
+
+	outerRow, err := rows.NewRow(e.outerFields, outerValues)
+	if err != nil {
+		return nil, err
+	}
+
+	return node.Scanner(ctx.AddOuterRow(outerRow))
+}
+
+//
+//
+
+// subqueryPlan is the physical plan of a subquery, which is planned when first executed.
+type subqueryPlan struct {
+	once sync.Once
+	node nodes.Node
+	err  error
+}
+
+func (p *subqueryPlan) build(ctx impls.ExecutionContext, query TableReferenceOrExpression, outerFields []fields.Field) (nodes.Node, error) {
+	p.once.Do(func() {
+		node, err := query.Build()
+		if err != nil {
+			p.err = err
+			return
+		}
+
+		node.Optimize(ctx.OptimizationContext().AddOuterFields(outerFields))
+		p.node = node.Build()
+	})
+
+	return p.node, p.err
+}
+
+//
+//
+
+// decorrelate replaces each conjunction of the given filter of the given node that is a
+// (possibly negated) subquery predicate with a semi or anti join of the node and the
+// relations of the subquery. The subquery is then evaluated once, rather than once for
+// each row of the node. Subqueries that cannot be joined in this way (e.g., those with
+// aggregates or limits, or depending on fields of other queries) are left in the filter.
+// The node and remaining filter are returned.
+func decorrelate(node plan.LogicalNode, nodeFields []fields.Field, filter impls.Expression) (plan.LogicalNode, impls.Expression, error) {
+	var remaining []impls.Expression
+	for _, expr := range expressions.Conjunctions(filter) {
+		right, operator, ok, err := subqueryJoin(expr, nodeFields)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			remaining = append(remaining, expr)
+			continue
+		}
+
+		left, ok := node.(logicalJoin.JoinNode)
+		if !ok {
+			left = logicalJoin.NewJoinLeafNode(node)
+		}
+
+		node = logicalJoin.NewJoinInternalNode(left, logicalJoin.NewJoinLeafNode(right), operator)
+	}
+
+	return node, expressions.UnionFilters(remaining...), nil
+}
+
+// subqueryJoin returns the relation and operator of the semi or anti join that filters
+// rows with the given fields as the given predicate does. False is returned if the
+// predicate cannot be evaluated as a join.
+func subqueryJoin(expr impls.Expression, nodeFields []fields.Field) (plan.LogicalNode, logicalJoin.JoinOperator, bool, error) {
+	negated := false
+	if inner, ok := expressions.IsNegation(expr); ok {
+		expr = inner
+		negated = true
+	}
+
+	subquery, ok := expr.(*subqueryExpression)
+	if !ok || !subquery.joinable(nodeFields) {
+		return nil, logicalJoin.JoinOperator{}, false, nil
+	}
+	query := subquery.query.(*SelectBuilder)
+
+	right, err := query.From.Build()
+	if err != nil {
+		return nil, logicalJoin.JoinOperator{}, false, err
+	}
+
+	right, condition, err := decorrelate(right, query.From.TableFields(), query.Where)
+	if err != nil {
+		return nil, logicalJoin.JoinOperator{}, false, err
+	}
+
+	// EXISTS and ANY are true when some row matches; NOT EXISTS and ALL are true when no
+	// row fails to match (NOT ANY and NOT ALL are the reverse)
+	semi := (subquery.kind != subqueryKindAll) != negated
+
+	if subquery.kind != subqueryKindExists {
+		// Rows of a semi join match when the comparison has the value deciding the
+		// predicate (true for ANY, false for ALL); rows of an anti join match when
+		// the comparison may not have the value required by the predicate
+		value := (subquery.kind == subqueryKindAny) == semi
+		left := subquery.left
+		right := query.projection.Aliases()[0].Expression
+
+		var comparison impls.Expression
+		if !semi {
+			comparison = expressions.NewNot(subquery.operator.Matches(left, right, value))
+		} else if value != subquery.operator.Negated {
+			// Filters are satisfied only by true values
+			comparison = subquery.operator.Factory(left, right)
+		} else {
+			comparison = subquery.operator.Matches(left, right, value)
+		}
+
+		condition = expressions.UnionFilters(condition, comparison)
+	}
+
+	joinType := concreteJoin.JoinTypeAnti
+	if semi {
+		joinType = concreteJoin.JoinTypeSemi
+	}
+
+	return right, logicalJoin.JoinOperator{JoinType: joinType, Condition: condition}, true, nil
+}
+
+// joinable returns true if the subquery can be evaluated as a join with a relation with
+// the given fields.
+func (e *subqueryExpression) joinable(nodeFields []fields.Field) bool {
+	query, ok := e.query.(*SelectBuilder)
+	if e.kind == subqueryKindScalar || !ok || len(query.Groupings) > 0 || len(query.windowFunctions) > 0 || len(query.DistinctOn) > 0 || len(query.Combinations) > 0 || query.Limit != nil || query.Offset != nil || query.locking != nil {
+		return false
+	}
+
+	referencedFields := slices.Clone(e.outerFields)
+	if e.left != nil {
+		referencedFields = append(referencedFields, expressions.Fields(e.left)...)
+	}
+
+	for _, field := range referencedFields {
+		if _, err := fields.FindMatchingFieldIndex(field, nodeFields); err != nil {
+			return false
+		}
+	}
+
+	// Fields of the joined relations must remain distinguishable
+	for _, field := range query.From.TableFields() {
+		for _, nodeField := range nodeFields {
+			if field.RelationName() == nodeField.RelationName() {
+				return false
+			}
+		}
+	}
+
+	return true
+}
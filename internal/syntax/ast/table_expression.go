@@ -6,6 +6,7 @@ import (
 
 	"github.com/efritz/gostgres/internal/execution/expressions"
 	"github.com/efritz/gostgres/internal/execution/projection"
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
 	concreteJoin "github.com/efritz/gostgres/internal/execution/queries/nodes/join"
 	"github.com/efritz/gostgres/internal/execution/queries/plan"
 	logicalJoin "github.com/efritz/gostgres/internal/execution/queries/plan/join"
@@ -38,7 +39,13 @@ type TableAlias struct {
 
 type Join struct {
 	Table     *TableExpression
+	JoinType  concreteJoin.JoinType
 	Condition impls.Expression
+	Using     []string
+	Natural   bool
+
+	// projection merges the columns named in the USING clause of a full join
+	projection *projection.Projection
 }
 
 func (e *TableExpression) Resolve(ctx *impls.NodeResolutionContext) error {
@@ -55,28 +62,174 @@ func (e *TableExpression) Resolve(ctx *impls.NodeResolutionContext) error {
 	}
 	e.projection = aliasProjection
 
-	ctx.Bind(baseFields)
-
-	for _, j := range e.Joins {
-		if err := j.Table.Resolve(ctx); err != nil {
+	for i := range e.Joins {
+		if err := e.Joins[i].Table.Resolve(ctx); err != nil {
 			return err
 		}
 
-		joinFields := j.Table.TableFields()
-		ctx.Bind(joinFields)
-		baseFields = append(baseFields, joinFields...)
-
-		resolved, err := ResolveExpression(ctx, j.Condition, nil, false)
+		joinedFields, err := e.Joins[i].resolve(ctx, baseFields)
 		if err != nil {
 			return err
 		}
-		j.Condition = resolved
+		baseFields = joinedFields
 	}
 
 	e.fields = baseFields
 	return nil
 }
 
+// resolve resolves the condition of the join given the fields visible on its left, and
+// returns the fields visible after the join.
+func (j *Join) resolve(ctx *impls.NodeResolutionContext, leftFields []fields.Field) ([]fields.Field, error) {
+	rightFields := j.Table.TableFields()
+	joinedFields := append(slices.Clone(leftFields), rightFields...)
+
+	using := j.Using
+	if j.Natural {
+		using = commonColumnNames(leftFields, rightFields)
+	}
+
+	if len(using) == 0 {
+		if err := ctx.WithScope(func() error {
+			ctx.Bind(joinedFields)
+
+			resolved, err := ResolveExpression(ctx, j.Condition, nil, false)
+			if err != nil {
+				return err
+			}
+
+			j.Condition = resolved
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		return joinedFields, nil
+	}
+
+	return j.resolveUsing(ctx, using, leftFields, rightFields)
+}
+
+// resolveUsing constructs the condition equating the given columns of each side of the
+// join. Each pair of columns is merged into a single visible column, which precedes the
+// remaining columns of the left and then the right side of the join.
+func (j *Join) resolveUsing(ctx *impls.NodeResolutionContext, using []string, leftFields, rightFields []fields.Field) ([]fields.Field, error) {
+	var (
+		conditions         []impls.Expression
+		mergedFields       []fields.Field
+		mergedExpressions  []projection.ProjectedExpression
+		leftUsing          []fields.Field
+		rightUsing         []fields.Field
+		expressionResolver = ctx.ExpressionResolutionContext(false)
+	)
+
+	for _, name := range using {
+		leftField, err := findUsingColumn(leftFields, name, "left")
+		if err != nil {
+			return nil, err
+		}
+
+		rightField, err := findUsingColumn(rightFields, name, "right")
+		if err != nil {
+			return nil, err
+		}
+
+		leftUsing = append(leftUsing, leftField)
+		rightUsing = append(rightUsing, rightField)
+		conditions = append(conditions, expressions.NewEquals(expressions.NewNamed(leftField), expressions.NewNamed(rightField)))
+
+		switch j.JoinType {
+		case concreteJoin.JoinTypeRightOuter:
+			mergedFields = append(mergedFields, rightField)
+
+		case concreteJoin.JoinTypeFullOuter:
+			// Either side may be NULL-extended; the merged column is the non-NULL one
+			merged := expressions.NewCoalesce([]impls.Expression{expressions.NewNamed(leftField), expressions.NewNamed(rightField)})
+			if err := merged.Resolve(expressionResolver); err != nil {
+				return nil, err
+			}
+
+			mergedExpressions = append(mergedExpressions, projection.NewProjectedExpression(merged, name, false))
+			mergedFields = append(mergedFields, fields.NewField("", name, merged.Type(), fields.NonInternalField))
+
+		default:
+			mergedFields = append(mergedFields, leftField)
+		}
+	}
+
+	condition := expressions.UnionFilters(conditions...)
+	if err := condition.Resolve(expressionResolver); err != nil {
+		return nil, err
+	}
+	j.Condition = condition
+
+	var remainingFields []fields.Field
+	for _, field := range leftFields {
+		if !slices.Contains(leftUsing, field) {
+			remainingFields = append(remainingFields, field)
+		}
+	}
+	for _, field := range rightFields {
+		if !slices.Contains(rightUsing, field) {
+			remainingFields = append(remainingFields, field)
+		}
+	}
+
+	if len(mergedExpressions) > 0 {
+		for _, field := range remainingFields {
+			mergedExpressions = append(mergedExpressions, projection.NewProjectedExpressionFromField(field))
+		}
+
+		p, err := projection.NewProjection("", mergedExpressions)
+		if err != nil {
+			return nil, err
+		}
+		j.projection = p
+	}
+
+	return append(mergedFields, remainingFields...), nil
+}
+
+// commonColumnNames returns the names of the non-internal columns present on both sides
+// of a natural join, in the order they appear on the left side.
+func commonColumnNames(leftFields, rightFields []fields.Field) []string {
+	var names []string
+	for _, leftField := range leftFields {
+		if leftField.Internal() || slices.Contains(names, leftField.Name()) {
+			continue
+		}
+
+		for _, rightField := range rightFields {
+			if !rightField.Internal() && rightField.Name() == leftField.Name() {
+				names = append(names, leftField.Name())
+				break
+			}
+		}
+	}
+
+	return names
+}
+
+// findUsingColumn returns the single non-internal field with the given name on the given
+// side of a join.
+func findUsingColumn(sideFields []fields.Field, name, side string) (fields.Field, error) {
+	var matches []fields.Field
+	for _, field := range sideFields {
+		if !field.Internal() && field.Name() == name {
+			matches = append(matches, field)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return fields.Field{}, fmt.Errorf("column %q specified in USING clause does not exist in %s table", name, side)
+	case 1:
+		return matches[0], nil
+	}
+
+	return fields.Field{}, fmt.Errorf("common column name %q appears more than once in %s table", name, side)
+}
+
 func (e *TableExpression) resolveTableAlias(ctx *impls.NodeResolutionContext) ([]fields.Field, *projection.Projection, error) {
 	if e.Base.Alias == nil {
 		// No alias, return base fields without modification
@@ -97,12 +250,17 @@ func (e *TableExpression) resolveTableAlias(ctx *impls.NodeResolutionContext) ([
 		}
 	}
 
-	for _, field := range nonInternalFields[len(columnAliases):] {
-		columnAliases = append(columnAliases, field.Name())
+	if _, ok := e.Base.BaseTableExpression.(*TableFunctionReference); ok && len(columnAliases) == 0 && len(nonInternalFields) == 1 {
+		// The alias of a function returning a single column also names the column
+		columnAliases = []string{tableAlias}
 	}
+
 	if len(columnAliases) > len(nonInternalFields) {
 		return nil, nil, fmt.Errorf("has %d columns available but %d columns specified", len(nonInternalFields), len(columnAliases))
 	}
+	for _, field := range nonInternalFields[len(columnAliases):] {
+		columnAliases = append(columnAliases, field.Name())
+	}
 
 	var projectionExpressions []projection.ProjectionExpression
 	for i, field := range nonInternalFields {
@@ -140,6 +298,56 @@ func (e *TableExpression) resolveTableAlias(ctx *impls.NodeResolutionContext) ([
 	return fields, p, nil
 }
 
+// lockableRelation is a table referenced directly by a table expression.
+type lockableRelation struct {
+	nodes.LockedRelation
+
+	// nullable is true if rows of the table may be extended with NULL values by an
+	// outer join
+	nullable bool
+}
+
+// lockableRelations returns the tables referenced directly by this table expression
+// (and not through a subquery), named as they are referenced in the query.
+func (e *TableExpression) lockableRelations(nullable bool) []lockableRelation {
+	var relations []lockableRelation
+	switch base := e.Base.BaseTableExpression.(type) {
+	case *TableReference:
+		if base.commonTable != nil {
+			break
+		}
+
+		name := base.Name
+		if e.Base.Alias != nil {
+			name = e.Base.Alias.TableAlias
+		}
+
+		relations = append(relations, lockableRelation{
+			LockedRelation: nodes.LockedRelation{Name: name, Table: base.table},
+			nullable:       nullable,
+		})
+
+	case *TableExpression:
+		if e.Base.Alias == nil {
+			relations = append(relations, base.lockableRelations(nullable)...)
+		}
+	}
+
+	for _, j := range e.Joins {
+		if j.JoinType == concreteJoin.JoinTypeRightOuter || j.JoinType == concreteJoin.JoinTypeFullOuter {
+			// Rows of every table to the left of the join may be NULL-extended
+			for i := range relations {
+				relations[i].nullable = true
+			}
+		}
+
+		rightNullable := nullable || j.JoinType == concreteJoin.JoinTypeLeftOuter || j.JoinType == concreteJoin.JoinTypeFullOuter
+		relations = append(relations, j.Table.lockableRelations(rightNullable)...)
+	}
+
+	return relations
+}
+
 func (e *TableExpression) TableFields() []fields.Field {
 	return slices.Clone(e.fields)
 }
@@ -167,10 +375,15 @@ func (e *TableExpression) Build() (plan.LogicalNode, error) {
 		}
 
 		joinNode = logicalJoin.NewJoinInternalNode(joinNode, logicalJoin.NewJoinLeafNode(right), logicalJoin.JoinOperator{
-			JoinType:  concreteJoin.JoinTypeInner,
+			JoinType:  j.JoinType,
 			Condition: j.Condition,
 		})
+
+		if j.projection != nil {
+			// Columns merged by a full join are computed above the join
+			joinNode = logicalJoin.NewJoinLeafNode(plan.NewProjection(joinNode.ConvertRightJoinsToLeftJoins(), j.projection))
+		}
 	}
 
-	return joinNode, nil
+	return joinNode.ConvertRightJoinsToLeftJoins(), nil
 }
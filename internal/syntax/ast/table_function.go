@@ -0,0 +1,69 @@
+package ast
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/execution/queries/plan"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
+)
+
+// TableFunctionReference is the invocation of a set-returning function in the FROM
+// clause of a query (e.g., unnest(array)). The arguments may not refer to the columns
+// of other tables in the FROM clause.
+type TableFunctionReference struct {
+	Name string
+	Args []impls.Expression
+
+	function impls.TableFunction
+	fields   []fields.Field
+}
+
+func (r *TableFunctionReference) Resolve(ctx *impls.NodeResolutionContext) error {
+	function, ok := ctx.Catalog().TableFunctions.Get(r.Name)
+	if !ok {
+		return fmt.Errorf("unknown table function %q", r.Name)
+	}
+	r.function = function
+
+	paramTypes := function.ParamTypes()
+
+	var argTypes []types.Type
+	for i, arg := range r.Args {
+		resolved, err := ResolveExpression(ctx, arg, nil, false)
+		if err != nil {
+			return err
+		}
+
+		if i < len(paramTypes) {
+			if err := expressions.InferType(resolved, paramTypes[i]); err != nil {
+				return err
+			}
+		}
+
+		r.Args[i] = resolved
+		argTypes = append(argTypes, resolved.Type())
+	}
+
+	columns, err := function.ResolveColumns(argTypes)
+	if err != nil {
+		return err
+	}
+
+	r.fields = nil
+	for _, column := range columns {
+		r.fields = append(r.fields, fields.NewField(r.Name, column.Name, column.Type, fields.NonInternalField))
+	}
+
+	return nil
+}
+
+func (r *TableFunctionReference) TableFields() []fields.Field {
+	return r.fields
+}
+
+func (r *TableFunctionReference) Build() (plan.LogicalNode, error) {
+	return plan.NewTableFunction(r.Name, r.fields, r.function, r.Args), nil
+}
@@ -12,9 +12,20 @@ type TableReference struct {
 	Name string
 
 	table impls.Table
+
+	// commonTable is the common table to which the name refers, if any; working is true
+	// if the reference reads the working table of a recursive common table
+	commonTable *CommonTableExpression
+	working     bool
 }
 
 func (r *TableReference) Resolve(ctx *impls.NodeResolutionContext) error {
+	if commonTable, ok := ctx.LookupCommonTable(r.Name); ok {
+		r.commonTable = commonTable.(*CommonTableExpression)
+		r.working = r.commonTable.resolveReference()
+		return nil
+	}
+
 	table, ok := ctx.Catalog().Tables.Get(r.Name)
 	if !ok {
 		return fmt.Errorf("unknown table %q", r.Name)
@@ -25,6 +36,10 @@ func (r *TableReference) Resolve(ctx *impls.NodeResolutionContext) error {
 }
 
 func (r *TableReference) TableFields() []fields.Field {
+	if r.commonTable != nil {
+		return r.commonTable.TableFields()
+	}
+
 	var fields []fields.Field
 	for _, f := range r.table.Fields() {
 		fields = append(fields, f.Field)
@@ -34,5 +49,13 @@ func (r *TableReference) TableFields() []fields.Field {
 }
 
 func (r *TableReference) Build() (plan.LogicalNode, error) {
+	if r.working {
+		return plan.NewWorkingTableScan(r.Name, r.commonTable.TableFields(), r.commonTable.working), nil
+	}
+
+	if r.commonTable != nil {
+		return r.commonTable.reference()
+	}
+
 	return plan.NewAccess(r.table), nil
 }
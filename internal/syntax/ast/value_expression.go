@@ -1,19 +1,78 @@
 package ast
 
 import (
+	"fmt"
 	"slices"
 
+	"github.com/efritz/gostgres/internal/execution/expressions"
 	"github.com/efritz/gostgres/internal/execution/queries/plan"
 	"github.com/efritz/gostgres/internal/shared/fields"
 	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
 )
 
 type ValuesBuilder struct {
 	Fields      []fields.Field
 	Expressions [][]impls.Expression
+
+	// Untyped is set when the values are converted to the types of the columns of
+	// an INSERT target individually, rather than to a common type per column.
+	Untyped bool
 }
 
 func (b *ValuesBuilder) Resolve(ctx *impls.NodeResolutionContext) error {
+	for _, rowExpressions := range b.Expressions {
+		if len(rowExpressions) != len(b.Fields) {
+			return fmt.Errorf("VALUES lists must all be the same length")
+		}
+
+		for i, expr := range rowExpressions {
+			resolved, err := ResolveExpression(ctx, expr, nil, false)
+			if err != nil {
+				return err
+			}
+
+			rowExpressions[i] = resolved
+		}
+	}
+
+	if b.Untyped {
+		return nil
+	}
+
+	for i, field := range b.Fields {
+		typ := types.TypeAny
+		hasLiterals := false
+		for _, rowExpressions := range b.Expressions {
+			if expressions.IsUntypedLiteral(rowExpressions[i]) {
+				hasLiterals = true
+				continue
+			}
+
+			if exprType := rowExpressions[i].Type(); exprType != types.TypeAny && exprType != types.TypeUnknown {
+				common := typ.PromoteToCommonType(exprType)
+				if common == types.TypeUnknown {
+					return fmt.Errorf("VALUES types %s and %s cannot be matched", typ, exprType)
+				}
+
+				typ = common
+			}
+		}
+
+		if typ == types.TypeAny && hasLiterals {
+			// String literals adopt the type of the other values, and are text otherwise
+			typ = types.TypeText
+		}
+
+		for _, rowExpressions := range b.Expressions {
+			if err := expressions.InferType(rowExpressions[i], typ); err != nil {
+				return err
+			}
+		}
+
+		b.Fields[i] = field.WithType(typ)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,228 @@
+package ast
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/execution/projection"
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+)
+
+// NamedWindow is a window defined in the WINDOW clause of a select.
+type NamedWindow struct {
+	Name   string
+	Window impls.Window
+}
+
+// resolveWindows resolves the windows of the WINDOW clause. Each window may refer to the
+// windows defined before it.
+func (b *SelectBuilder) resolveWindows(ctx *impls.NodeResolutionContext) (map[string]impls.Window, error) {
+	windows := map[string]impls.Window{}
+	for _, namedWindow := range b.Windows {
+		if _, ok := windows[namedWindow.Name]; ok {
+			return nil, fmt.Errorf("window %q is already defined", namedWindow.Name)
+		}
+
+		window, err := expressions.MapWindow(namedWindow.Window, func(expr impls.Expression) (impls.Expression, error) {
+			return ResolveExpression(ctx, expr, nil, false)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if window.Name != "" {
+			base, ok := windows[window.Name]
+			if !ok {
+				return nil, fmt.Errorf("window %q does not exist", window.Name)
+			}
+
+			window, err = expressions.InheritWindow(window.Name, base, window)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if err := expressions.ResolveWindow(ctx.ExpressionResolutionContext(false), window); err != nil {
+			return nil, err
+		}
+
+		windows[namedWindow.Name] = window
+	}
+
+	return windows, nil
+}
+
+// extractWindowFunctions replaces each window function invoked by the projection and
+// order of the select with a reference to a field computed by a window node.
+func (b *SelectBuilder) extractWindowFunctions(ctx *impls.NodeResolutionContext) error {
+	extract := func(expr impls.Expression) (impls.Expression, error) {
+		if !expressions.ContainsWindowFunction(expr) {
+			return expr, nil
+		}
+
+		mappedExpr, err := expr.Map(func(expr impls.Expression) (impls.Expression, error) {
+			if _, _, _, ok := expressions.UnwrapWindowFunction(expr); !ok {
+				return expr, nil
+			}
+
+			for _, function := range b.windowFunctions {
+				if function.Expression.Equal(expr) {
+					return expressions.NewNamed(function.Field), nil
+				}
+			}
+
+			field := fields.NewField("", expr.String(), expr.Type(), fields.NonInternalField)
+			b.windowFunctions = append(b.windowFunctions, nodes.WindowFunction{Field: field, Expression: expr})
+			return expressions.NewNamed(field), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Mapped expressions must be resolved again to determine their type
+		if err := mappedExpr.Resolve(ctx.ExpressionResolutionContext(false)); err != nil {
+			return nil, err
+		}
+
+		return mappedExpr, nil
+	}
+
+	aliases := b.projection.Aliases()
+	projectedExpressions := make([]projection.ProjectedExpression, 0, len(aliases))
+	for _, alias := range aliases {
+		expr, err := extract(alias.Expression)
+		if err != nil {
+			return err
+		}
+
+		alias.Expression = expr
+		projectedExpressions = append(projectedExpressions, alias)
+	}
+
+	if b.Order != nil {
+		order, err := b.Order.Map(extract)
+		if err != nil {
+			return err
+		}
+
+		b.Order = order
+	}
+
+	if len(b.windowFunctions) == 0 {
+		return nil
+	}
+
+	projection, err := projection.NewProjection("", projectedExpressions)
+	if err != nil {
+		return err
+	}
+
+	b.projection = projection
+	return nil
+}
+
+// extractGroupedExpressions prepares the window functions of a select with groupings to
+// be computed over its groups. The groups are projected into their grouping columns and
+// the values of the aggregates and grouping expressions referenced by the window functions
+// and projection of the select, which are rewritten to refer to those values instead.
+func (b *SelectBuilder) extractGroupedExpressions(ctx *impls.NodeResolutionContext) error {
+	var groupedExpressions []projection.ProjectedExpression
+	var groupingExpressions []impls.Expression
+	for _, grouping := range b.Groupings {
+		if named, ok := grouping.(expressions.NamedExpression); ok {
+			// Grouping columns are passed through so that references to them are unchanged
+			groupedExpressions = append(groupedExpressions, projection.NewProjectedExpression(grouping, named.Field().Name(), false))
+		} else if len(expressions.Fields(grouping)) > 0 {
+			groupingExpressions = append(groupingExpressions, grouping)
+		}
+	}
+
+	exprCtx := ctx.ExpressionResolutionContext(true)
+	extract := func(expr impls.Expression) (impls.Expression, error) {
+		// Mapped expressions must be resolved again to determine their type
+		if err := expr.Resolve(exprCtx); err != nil {
+			return nil, err
+		}
+
+		for _, grouped := range groupedExpressions {
+			if grouped.Expression.Equal(expr) {
+				return expressions.NewNamed(fields.NewField("", grouped.Alias, expr.Type(), fields.NonInternalField)), nil
+			}
+		}
+
+		groupedExpressions = append(groupedExpressions, projection.NewProjectedExpression(expr, expr.String(), false))
+		return expressions.NewNamed(fields.NewField("", expr.String(), expr.Type(), fields.NonInternalField)), nil
+	}
+
+	rewrite := func(expr impls.Expression) (impls.Expression, error) {
+		// Aggregates are extracted first so that grouping expressions within their
+		// arguments, which are evaluated over the rows of each group, are left intact
+		expr, err := expr.Map(func(expr impls.Expression) (impls.Expression, error) {
+			if _, _, ok := expressions.UnwrapAggregate(exprCtx, expr); ok {
+				return extract(expr)
+			}
+
+			return expr, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return expr.Map(func(expr impls.Expression) (impls.Expression, error) {
+			if slices.ContainsFunc(groupingExpressions, expr.Equal) {
+				return extract(expr)
+			}
+
+			return expr, nil
+		})
+	}
+
+	for i, function := range b.windowFunctions {
+		expr, err := rewrite(function.Expression)
+		if err != nil {
+			return err
+		}
+
+		// The window function itself is resolved, but its mapped arguments and window are not
+		for _, child := range expr.Children() {
+			if err := child.Resolve(ctx.ExpressionResolutionContext(false)); err != nil {
+				return err
+			}
+		}
+
+		b.windowFunctions[i].Expression = expr
+	}
+
+	aliases := b.projection.Aliases()
+	projectedExpressions := make([]projection.ProjectedExpression, 0, len(aliases))
+	for _, alias := range aliases {
+		expr, err := rewrite(alias.Expression)
+		if err != nil {
+			return err
+		}
+
+		if err := expr.Resolve(ctx.ExpressionResolutionContext(false)); err != nil {
+			return err
+		}
+
+		alias.Expression = expr
+		projectedExpressions = append(projectedExpressions, alias)
+	}
+
+	groupedProjection, err := projection.NewProjection("", groupedExpressions)
+	if err != nil {
+		return err
+	}
+
+	projection, err := projection.NewProjection("", projectedExpressions)
+	if err != nil {
+		return err
+	}
+
+	b.groupedProjection = groupedProjection
+	b.projection = projection
+	return nil
+}
@@ -0,0 +1,201 @@
+package ast
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/execution/queries/nodes"
+	"github.com/efritz/gostgres/internal/execution/queries/plan"
+	"github.com/efritz/gostgres/internal/execution/queries/plan/combination"
+	"github.com/efritz/gostgres/internal/shared/fields"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/syntax/tokens"
+)
+
+// WithBuilder is a statement preceded by a WITH clause, whose common tables may be
+// referenced by name within the statement.
+type WithBuilder struct {
+	CommonTables []*CommonTableExpression
+	Recursive    bool
+	Statement    BuilderResolver
+}
+
+func (b *WithBuilder) Resolve(ctx *impls.NodeResolutionContext) error {
+	ctx.PushScope()
+	defer ctx.PopScope()
+
+	for i, c := range b.CommonTables {
+		for _, other := range b.CommonTables[:i] {
+			if other.Name == c.Name {
+				return fmt.Errorf("WITH query name %q specified more than once", c.Name)
+			}
+		}
+
+		if err := c.resolve(ctx, b.Recursive); err != nil {
+			return err
+		}
+
+		ctx.BindCommonTable(c)
+	}
+
+	return b.Statement.Resolve(ctx)
+}
+
+func (b *WithBuilder) Build() (plan.LogicalNode, error) {
+	return b.Statement.Build()
+}
+
+//
+//
+
+type CommonTableExpression struct {
+	Name        string
+	ColumnNames []string
+	Query       TableReferenceOrExpression
+
+	// Materialized is nil if the query is materialized only when referenced more than
+	// once, or otherwise determines whether the query is materialized
+	Materialized *bool
+
+	// table is the query with its fields renamed after the common table
+	table *TableExpression
+
+	// recursive is the recursive term of a query of a WITH RECURSIVE clause, which is
+	// combined with the rows of the preceding (non-recursive) term; working is non-nil
+	// if the recursive term references the common table
+	recursive *CombinationDescription
+	working   *nodes.WorkingTable
+
+	resolvingRecursiveTerm bool
+	references             int
+	materialized           *plan.MaterializedRelation
+}
+
+var _ impls.CommonTable = &CommonTableExpression{}
+
+func (c *CommonTableExpression) CommonTableName() string {
+	return c.Name
+}
+
+func (c *CommonTableExpression) resolve(ctx *impls.NodeResolutionContext, recursive bool) error {
+	query := c.Query
+	if builder, ok := query.(*SelectBuilder); ok && recursive && isRecursiveQuery(builder) {
+		c.recursive = builder.Combinations[0]
+		builder.Combinations = nil
+	}
+
+	c.table = &TableExpression{
+		Base: AliasedTableReferenceOrExpression{
+			BaseTableExpression: query,
+			Alias: &TableAlias{
+				TableAlias:    c.Name,
+				ColumnAliases: c.ColumnNames,
+			},
+		},
+	}
+
+	if err := ctx.WithScope(func() error { return c.table.Resolve(ctx) }); err != nil {
+		return err
+	}
+
+	if c.recursive == nil {
+		return nil
+	}
+
+	// The recursive term may reference the rows of the common table produced by the
+	// previous iteration
+	if err := ctx.WithScope(func() error {
+		ctx.BindCommonTable(c)
+		c.resolvingRecursiveTerm = true
+		defer func() { c.resolvingRecursiveTerm = false }()
+
+		return c.recursive.Select.Resolve(ctx)
+	}); err != nil {
+		return err
+	}
+
+	if len(c.recursive.Select.TableFields()) != len(c.TableFields()) {
+		return fmt.Errorf("each UNION query must have the same number of columns")
+	}
+
+	return nil
+}
+
+// isRecursiveQuery returns true if the given query has the form of a recursive query,
+// in which a non-recursive term is combined with a recursive term by UNION [ALL].
+func isRecursiveQuery(builder *SelectBuilder) bool {
+	return len(builder.Combinations) == 1 &&
+		builder.Combinations[0].Type == tokens.TokenTypeUnion &&
+		builder.Order == nil &&
+		builder.Limit == nil &&
+		builder.Offset == nil
+}
+
+// resolveReference records a reference to the common table and returns true if the
+// reference reads the working table of a recursive query.
+func (c *CommonTableExpression) resolveReference() bool {
+	if c.resolvingRecursiveTerm {
+		if c.working == nil {
+			c.working = nodes.NewWorkingTable()
+		}
+
+		return true
+	}
+
+	c.references++
+	return false
+}
+
+func (c *CommonTableExpression) TableFields() []fields.Field {
+	return c.table.TableFields()
+}
+
+// reference returns a node producing the rows of the common table for one of its
+// references.
+func (c *CommonTableExpression) reference() (plan.LogicalNode, error) {
+	// Recursive queries are evaluated once; other queries are materialized when
+	// referenced more than once unless instructed otherwise
+	materialize := c.references > 1
+	if c.working != nil {
+		materialize = true
+	} else if c.Materialized != nil {
+		materialize = *c.Materialized
+	}
+
+	if !materialize {
+		return c.build()
+	}
+
+	if c.materialized == nil {
+		node, err := c.build()
+		if err != nil {
+			return nil, err
+		}
+
+		c.materialized = plan.NewMaterializedRelation(c.Name, node)
+	}
+
+	return plan.NewMaterializedScan(c.materialized), nil
+}
+
+func (c *CommonTableExpression) build() (plan.LogicalNode, error) {
+	node, err := c.table.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.recursive == nil {
+		return node, nil
+	}
+
+	recursive, err := c.recursive.Select.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.working == nil {
+		// The recursive term does not reference the common table
+		return combination.NewUnion(node, recursive, c.recursive.Distinct)
+	}
+
+	return combination.NewRecursiveUnion(node, recursive, c.working, c.TableFields(), c.recursive.Distinct), nil
+}
@@ -14,61 +14,104 @@ func newLexer(text string) *lexer {
 }
 
 var keywordSet = map[string]tokens.TokenType{
-	"add":        tokens.TokenTypeAdd,
-	"all":        tokens.TokenTypeAll,
-	"alter":      tokens.TokenTypeAlter,
-	"and":        tokens.TokenTypeAnd,
-	"as":         tokens.TokenTypeAs,
-	"asc":        tokens.TokenTypeAscending,
-	"between":    tokens.TokenTypeBetween,
-	"by":         tokens.TokenTypeBy,
-	"check":      tokens.TokenTypeCheck,
-	"constraint": tokens.TokenTypeConstraint,
-	"create":     tokens.TokenTypeCreate,
-	"default":    tokens.TokenTypeDefault,
-	"delete":     tokens.TokenTypeDelete,
-	"desc":       tokens.TokenTypeDescending,
-	"distinct":   tokens.TokenTypeDistinct,
-	"except":     tokens.TokenTypeExcept,
-	"explain":    tokens.TokenTypeExplain,
-	"false":      tokens.TokenTypeFalse,
-	"foreign":    tokens.TokenTypeForeign,
-	"from":       tokens.TokenTypeFrom,
-	"group":      tokens.TokenTypeGroup,
-	"ilike":      tokens.TokenTypeILike,
-	"index":      tokens.TokenTypeIndex,
-	"insert":     tokens.TokenTypeInsert,
-	"intersect":  tokens.TokenTypeIntersect,
-	"into":       tokens.TokenTypeInto,
-	"is":         tokens.TokenTypeIs,
-	"isnull":     tokens.TokenTypeIsNull,
-	"join":       tokens.TokenTypeJoin,
-	"key":        tokens.TokenTypeKey,
-	"like":       tokens.TokenTypeLike,
-	"limit":      tokens.TokenTypeLimit,
-	"not":        tokens.TokenTypeNot,
-	"notnull":    tokens.TokenTypeIsNotNull,
-	"null":       tokens.TokenTypeNull,
-	"offset":     tokens.TokenTypeOffset,
-	"on":         tokens.TokenTypeOn,
-	"or":         tokens.TokenTypeOr,
-	"order":      tokens.TokenTypeOrder,
-	"primary":    tokens.TokenTypePrimary,
-	"references": tokens.TokenTypeReferences,
-	"returning":  tokens.TokenTypeReturning,
-	"select":     tokens.TokenTypeSelect,
-	"sequence":   tokens.TokenTypeSequence,
-	"set":        tokens.TokenTypeSet,
-	"symmetric":  tokens.TokenTypeSymmetric,
-	"table":      tokens.TokenTypeTable,
-	"true":       tokens.TokenTypeTrue,
-	"union":      tokens.TokenTypeUnion,
-	"unique":     tokens.TokenTypeUnique,
-	"unknown":    tokens.TokenTypeKwUnknown,
-	"update":     tokens.TokenTypeUpdate,
-	"using":      tokens.TokenTypeUsing,
-	"values":     tokens.TokenTypeValues,
-	"where":      tokens.TokenTypeWhere,
+	"abort":       tokens.TokenTypeAbort,
+	"access":      tokens.TokenTypeAccess,
+	"add":         tokens.TokenTypeAdd,
+	"all":         tokens.TokenTypeAll,
+	"alter":       tokens.TokenTypeAlter,
+	"and":         tokens.TokenTypeAnd,
+	"any":         tokens.TokenTypeAny,
+	"array":       tokens.TokenTypeArray,
+	"as":          tokens.TokenTypeAs,
+	"asc":         tokens.TokenTypeAscending,
+	"begin":       tokens.TokenTypeBegin,
+	"between":     tokens.TokenTypeBetween,
+	"by":          tokens.TokenTypeBy,
+	"case":        tokens.TokenTypeCase,
+	"cast":        tokens.TokenTypeCast,
+	"check":       tokens.TokenTypeCheck,
+	"commit":      tokens.TokenTypeCommit,
+	"constraint":  tokens.TokenTypeConstraint,
+	"create":      tokens.TokenTypeCreate,
+	"cross":       tokens.TokenTypeCross,
+	"deallocate":  tokens.TokenTypeDeallocate,
+	"default":     tokens.TokenTypeDefault,
+	"delete":      tokens.TokenTypeDelete,
+	"desc":        tokens.TokenTypeDescending,
+	"distinct":    tokens.TokenTypeDistinct,
+	"else":        tokens.TokenTypeElse,
+	"end":         tokens.TokenTypeEnd,
+	"except":      tokens.TokenTypeExcept,
+	"exclusive":   tokens.TokenTypeExclusive,
+	"execute":     tokens.TokenTypeExecute,
+	"exists":      tokens.TokenTypeExists,
+	"explain":     tokens.TokenTypeExplain,
+	"false":       tokens.TokenTypeFalse,
+	"for":         tokens.TokenTypeFor,
+	"foreign":     tokens.TokenTypeForeign,
+	"from":        tokens.TokenTypeFrom,
+	"full":        tokens.TokenTypeFull,
+	"group":       tokens.TokenTypeGroup,
+	"having":      tokens.TokenTypeHaving,
+	"ilike":       tokens.TokenTypeILike,
+	"in":          tokens.TokenTypeIn,
+	"index":       tokens.TokenTypeIndex,
+	"inner":       tokens.TokenTypeInner,
+	"insert":      tokens.TokenTypeInsert,
+	"intersect":   tokens.TokenTypeIntersect,
+	"into":        tokens.TokenTypeInto,
+	"is":          tokens.TokenTypeIs,
+	"isnull":      tokens.TokenTypeIsNull,
+	"join":        tokens.TokenTypeJoin,
+	"key":         tokens.TokenTypeKey,
+	"left":        tokens.TokenTypeLeft,
+	"like":        tokens.TokenTypeLike,
+	"limit":       tokens.TokenTypeLimit,
+	"lock":        tokens.TokenTypeLock,
+	"mode":        tokens.TokenTypeMode,
+	"natural":     tokens.TokenTypeNatural,
+	"not":         tokens.TokenTypeNot,
+	"notnull":     tokens.TokenTypeIsNotNull,
+	"null":        tokens.TokenTypeNull,
+	"of":          tokens.TokenTypeOf,
+	"offset":      tokens.TokenTypeOffset,
+	"on":          tokens.TokenTypeOn,
+	"or":          tokens.TokenTypeOr,
+	"order":       tokens.TokenTypeOrder,
+	"outer":       tokens.TokenTypeOuter,
+	"prepare":     tokens.TokenTypePrepare,
+	"primary":     tokens.TokenTypePrimary,
+	"references":  tokens.TokenTypeReferences,
+	"release":     tokens.TokenTypeRelease,
+	"returning":   tokens.TokenTypeReturning,
+	"rollback":    tokens.TokenTypeRollback,
+	"right":       tokens.TokenTypeRight,
+	"row":         tokens.TokenTypeRow,
+	"savepoint":   tokens.TokenTypeSavepoint,
+	"select":      tokens.TokenTypeSelect,
+	"sequence":    tokens.TokenTypeSequence,
+	"set":         tokens.TokenTypeSet,
+	"share":       tokens.TokenTypeShare,
+	"similar":     tokens.TokenTypeSimilar,
+	"some":        tokens.TokenTypeSome,
+	"start":       tokens.TokenTypeStart,
+	"symmetric":   tokens.TokenTypeSymmetric,
+	"table":       tokens.TokenTypeTable,
+	"then":        tokens.TokenTypeThen,
+	"to":          tokens.TokenTypeTo,
+	"transaction": tokens.TokenTypeTransaction,
+	"true":        tokens.TokenTypeTrue,
+	"union":       tokens.TokenTypeUnion,
+	"unique":      tokens.TokenTypeUnique,
+	"unknown":     tokens.TokenTypeKwUnknown,
+	"update":      tokens.TokenTypeUpdate,
+	"using":       tokens.TokenTypeUsing,
+	"values":      tokens.TokenTypeValues,
+	"when":        tokens.TokenTypeWhen,
+	"where":       tokens.TokenTypeWhere,
+	"window":      tokens.TokenTypeWindow,
+	"with":        tokens.TokenTypeWith,
+	"work":        tokens.TokenTypeWork,
 }
 
 var punctuationMap = map[rune]tokens.TokenType{
@@ -85,13 +128,25 @@ var punctuationMap = map[rune]tokens.TokenType{
 	'<': tokens.TokenTypeLessThan,
 	'=': tokens.TokenTypeEquals,
 	'>': tokens.TokenTypeGreaterThan,
+	'~': tokens.TokenTypeTilde,
+	'[': tokens.TokenTypeLeftBracket,
+	']': tokens.TokenTypeRightBracket,
+	':': tokens.TokenTypeColon,
+	'?': tokens.TokenTypeQuestion,
 }
 
 var multipleCharacterPunctuationMap = map[rune]map[string]tokens.TokenType{
-	'!': {"=": tokens.TokenTypeNotEquals},
-	'<': {"=": tokens.TokenTypeLessThanOrEqual, ">": tokens.TokenTypeNotEquals},
+	'!': {"=": tokens.TokenTypeNotEquals, "~": tokens.TokenTypeNotTilde, "~*": tokens.TokenTypeNotTildeAsterisk},
+	'<': {"=": tokens.TokenTypeLessThanOrEqual, ">": tokens.TokenTypeNotEquals, "@": tokens.TokenTypeContainedBy},
 	'>': {"=": tokens.TokenTypeGreaterThanOrEqual},
 	'|': {"|": tokens.TokenTypeConcat},
+	':': {":": tokens.TokenTypeTypeCast},
+	'~': {"*": tokens.TokenTypeTildeAsterisk},
+	'@': {">": tokens.TokenTypeContains},
+	'&': {"&": tokens.TokenTypeOverlaps},
+	'-': {">": tokens.TokenTypeArrow, ">>": tokens.TokenTypeDoubleArrow},
+	'#': {">": tokens.TokenTypeHashArrow, ">>": tokens.TokenTypeHashDoubleArrow},
+	'?': {"|": tokens.TokenTypeQuestionPipe, "&": tokens.TokenTypeQuestionAmpersand},
 }
 
 func (l *lexer) next() tokens.Token {
@@ -140,17 +195,24 @@ func (l *lexer) scan(filter scanner) (string, bool) {
 }
 
 func (l *lexer) scanMultipleCharacterPunctuation(r rune) (tokens.TokenType, string, bool) {
-	suffixMap, ok := multipleCharacterPunctuationMap[r]
-	if ok {
-		for suffix, tokenType := range suffixMap {
-			if l.peekSubstring(len(suffix)) == suffix {
-				l.cursor += len(suffix)
-				return tokenType, string(r) + suffix, true
-			}
+	var (
+		matchedSuffix    string
+		matchedTokenType tokens.TokenType
+	)
+
+	// Prefer the longest suffix (e.g., `!~*` over `!~`)
+	for suffix, tokenType := range multipleCharacterPunctuationMap[r] {
+		if len(suffix) > len(matchedSuffix) && l.peekSubstring(len(suffix)) == suffix {
+			matchedSuffix, matchedTokenType = suffix, tokenType
 		}
 	}
 
-	return tokens.TokenTypeInvalid, "", false
+	if matchedSuffix == "" {
+		return tokens.TokenTypeInvalid, "", false
+	}
+
+	l.cursor += len(matchedSuffix)
+	return matchedTokenType, string(r) + matchedSuffix, true
 }
 
 func (l *lexer) scanPunctuation(r rune) (tokens.TokenType, string, bool) {
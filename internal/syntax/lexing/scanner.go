@@ -13,10 +13,12 @@ var scanners = map[tokens.TokenType]scanner{
 	tokens.TokenTypeString:     {isQuote, isNotQuote, false},
 	tokens.TokenTypeIdent:      {isIdent, isIdentOrDigit, true},
 	tokens.TokenTypeNumber:     {isDigit, isDigit, true},
+	tokens.TokenTypeParameter:  {isDollar, isDigit, true},
 }
 
 func isSpace(r rune) bool        { return r == ' ' || r == '\t' || r == '\n' }
 func isQuote(r rune) bool        { return r == '\'' }
+func isDollar(r rune) bool       { return r == '$' }
 func isNotQuote(r rune) bool     { return r != '\'' }
 func isIdent(r rune) bool        { return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || r == '_' }
 func isDigit(r rune) bool        { return ('0' <= r && r <= '9') }
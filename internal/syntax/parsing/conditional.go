@@ -0,0 +1,104 @@
+package parsing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/efritz/gostgres/internal/execution/expressions"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/syntax/tokens"
+)
+
+// caseTail := [ expression ] `WHEN` expression `THEN` expression [ ... ] [ `ELSE` expression ] `END`
+func (p *parser) parseCaseExpression(token tokens.Token) (impls.Expression, error) {
+	var operand impls.Expression
+	if p.current().Type != tokens.TokenTypeWhen {
+		expression, err := p.parseRootExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		operand = expression
+	}
+
+	var whens []expressions.CaseWhen
+	for p.advanceIf(isType(tokens.TokenTypeWhen)) {
+		condition, err := p.parseRootExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := p.mustAdvance(isType(tokens.TokenTypeThen)); err != nil {
+			return nil, err
+		}
+
+		result, err := p.parseRootExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		whens = append(whens, expressions.CaseWhen{Condition: condition, Result: result})
+	}
+
+	if len(whens) == 0 {
+		return nil, fmt.Errorf("expected WHEN (near %s)", p.current().Text)
+	}
+
+	var elseResult impls.Expression
+	if p.advanceIf(isType(tokens.TokenTypeElse)) {
+		expression, err := p.parseRootExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		elseResult = expression
+	}
+
+	if _, err := p.mustAdvance(isType(tokens.TokenTypeEnd)); err != nil {
+		return nil, err
+	}
+
+	return expressions.NewCase(operand, whens, elseResult), nil
+}
+
+type conditionalExpressionFactory struct {
+	minArgs int
+	maxArgs int // zero for no limit
+	factory func(args []impls.Expression) impls.Expression
+}
+
+// conditionalExpressionFactories are invoked like functions, but are evaluated lazily
+// and have result types determined by their arguments.
+var conditionalExpressionFactories = map[string]conditionalExpressionFactory{
+	"coalesce": {minArgs: 1, factory: expressions.NewCoalesce},
+	"greatest": {minArgs: 1, factory: expressions.NewGreatest},
+	"least":    {minArgs: 1, factory: expressions.NewLeast},
+	"nullif": {minArgs: 2, maxArgs: 2, factory: func(args []impls.Expression) impls.Expression {
+		return expressions.NewNullIf(args[0], args[1])
+	}},
+}
+
+// conditionalExpressionTail := `(` expression [, ...] `)`
+func (p *parser) parseConditionalExpressionTail(token tokens.Token, factory conditionalExpressionFactory) (impls.Expression, error) {
+	args, err := parseParenthesized(p, func() ([]impls.Expression, error) {
+		if p.current().Type == tokens.TokenTypeRightParen {
+			return nil, nil
+		}
+
+		return parseCommaSeparatedList(p, p.parseRootExpression)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.ToUpper(token.Text)
+	if len(args) < factory.minArgs || (factory.maxArgs != 0 && len(args) > factory.maxArgs) {
+		if factory.minArgs == factory.maxArgs {
+			return nil, fmt.Errorf("%s expects %d arguments, got %d", name, factory.minArgs, len(args))
+		}
+
+		return nil, fmt.Errorf("%s expects at least %d argument, got %d", name, factory.minArgs, len(args))
+	}
+
+	return factory.factory(args), nil
+}
@@ -44,7 +44,7 @@ func (p *parser) parseCreateIndex(unique bool) (Query, error) {
 	return ddl.NewCreateIndex(name, tableName, method, unique, columnExpressions, where), nil
 }
 
-// createIndexUsing := `USING` ( `btree` | `hash` )
+// createIndexUsing := `USING` ( `btree` | `hash` | `gin` )
 func (p *parser) parseCreateIndexUsing() (string, error) {
 	if p.advanceIf(isType(tokens.TokenTypeUsing)) {
 		return p.parseIdent()
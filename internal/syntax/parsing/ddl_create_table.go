@@ -12,7 +12,7 @@ import (
 	"github.com/efritz/gostgres/internal/syntax/tokens"
 )
 
-// createTableTail := ident `(` [ columnDescription [, ...] ] `)`
+// createTableTail := ident `(` [ columnDescription [, ...] ] `)` createTableUsing
 func (p *parser) parseCreateTable() (Query, error) {
 	name, err := p.parseIdent()
 	if err != nil {
@@ -26,6 +26,11 @@ func (p *parser) parseCreateTable() (Query, error) {
 		return nil, err
 	}
 
+	method, err := p.parseCreateTableUsing()
+	if err != nil {
+		return nil, err
+	}
+
 	var queries []ddl.DDLQuery
 	for _, column := range columns {
 		// Sequences must be created before tables that reference them
@@ -36,7 +41,7 @@ func (p *parser) parseCreateTable() (Query, error) {
 	for _, column := range columns {
 		fields = append(fields, column.field)
 	}
-	queries = append(queries, ddl.NewCreateTable(name, fields))
+	queries = append(queries, ddl.NewCreateTable(name, fields, method))
 
 	for _, column := range columns {
 		// Constraints must be added after the table they reference
@@ -45,7 +50,16 @@ func (p *parser) parseCreateTable() (Query, error) {
 
 	// TODO - if not exists
 	// TODO - table constraints
-	return ddl.NewSet(queries), nil
+	return ddl.NewSet("CREATE TABLE", queries), nil
+}
+
+// createTableUsing := [ `USING` ident ]
+func (p *parser) parseCreateTableUsing() (string, error) {
+	if p.advanceIf(isType(tokens.TokenTypeUsing)) {
+		return p.parseIdent()
+	}
+
+	return "", nil
 }
 
 type columnDescription struct {
@@ -110,12 +124,12 @@ func (p *parser) parseColumnType(name string, tableName string, description *col
 		nextValue := expressions.NewFunction("nextval", []impls.Expression{expressions.NewConstant(sequenceName)})
 		description.field = description.field.WithDefault(nextValue)
 	} else {
-		typ, err := p.parseBasicType()
+		typ, typeModifier, err := p.parseModifiedType()
 		if err != nil {
 			return err
 		}
 
-		description.field = description.field.WithType(typ)
+		description.field = description.field.WithType(typ).WithTypeModifier(typeModifier)
 	}
 
 	return nil
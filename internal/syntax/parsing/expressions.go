@@ -9,6 +9,7 @@ import (
 	"github.com/efritz/gostgres/internal/shared/fields"
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/efritz/gostgres/internal/syntax/ast"
 	"github.com/efritz/gostgres/internal/syntax/tokens"
 )
 
@@ -49,9 +50,14 @@ func (p *parser) parseExpression(precedence Precedence) (impls.Expression, error
 func (p *parser) initExpressionPrefixParsers() {
 	p.prefixParsers = prefixParsers{
 		tokens.TokenTypeIdent:     p.parseNamedExpression,
+		tokens.TokenTypeArray:     p.parseArrayExpression,
+		tokens.TokenTypeCase:      p.parseCaseExpression,
+		tokens.TokenTypeCast:      p.parseCastExpression,
 		tokens.TokenTypeNumber:    p.parseNumericLiteralExpression,
 		tokens.TokenTypeString:    p.parseStringLiteralExpression,
+		tokens.TokenTypeParameter: p.parseParameterExpression,
 		tokens.TokenTypeFalse:     p.parseBooleanLiteralExpression,
+		tokens.TokenTypeExists:    p.parseExistsExpression,
 		tokens.TokenTypeNot:       p.parseUnary(expressions.NewNot),
 		tokens.TokenTypeNull:      p.parseNullLiteralExpression,
 		tokens.TokenTypeTrue:      p.parseBooleanLiteralExpression,
@@ -79,12 +85,12 @@ func (p *parser) initExpressionInfixParsers() {
 		tokens.TokenTypeAsterisk:            p.parseBinary(PrecedenceMultiplicative, expressions.NewMultiplication),
 		tokens.TokenTypeSlash:               p.parseBinary(PrecedenceMultiplicative, expressions.NewDivision),
 		tokens.TokenTypePlus:                p.parseBinary(PrecedenceAdditive, expressions.NewAddition),
-		tokens.TokenTypeLessThan:            p.parseBinary(PrecedenceComparison, expressions.NewLessThan),
-		tokens.TokenTypeEquals:              p.parseBinary(PrecedenceEquality, expressions.NewEquals),
-		tokens.TokenTypeGreaterThan:         p.parseBinary(PrecedenceComparison, expressions.NewGreaterThan),
-		tokens.TokenTypeLessThanOrEqual:     p.parseBinary(PrecedenceComparison, expressions.NewLessThanEquals),
-		tokens.TokenTypeNotEquals:           negate(p.parseBinary(PrecedenceEquality, expressions.NewEquals)),
-		tokens.TokenTypeGreaterThanOrEqual:  p.parseBinary(PrecedenceComparison, expressions.NewGreaterThanEquals),
+		tokens.TokenTypeLessThan:            p.parseComparison(PrecedenceComparison, ast.ComparisonOperator{Symbol: "<", Factory: expressions.NewLessThan}),
+		tokens.TokenTypeEquals:              p.parseComparison(PrecedenceEquality, ast.ComparisonOperator{Symbol: "=", Factory: expressions.NewEquals}),
+		tokens.TokenTypeGreaterThan:         p.parseComparison(PrecedenceComparison, ast.ComparisonOperator{Symbol: ">", Factory: expressions.NewGreaterThan}),
+		tokens.TokenTypeLessThanOrEqual:     p.parseComparison(PrecedenceComparison, ast.ComparisonOperator{Symbol: "<=", Factory: expressions.NewLessThanEquals}),
+		tokens.TokenTypeNotEquals:           p.parseComparison(PrecedenceEquality, ast.ComparisonOperator{Symbol: "<>", Factory: expressions.NewEquals, Negated: true}),
+		tokens.TokenTypeGreaterThanOrEqual:  p.parseComparison(PrecedenceComparison, ast.ComparisonOperator{Symbol: ">=", Factory: expressions.NewGreaterThanEquals}),
 		tokens.TokenTypeIsTrue:              p.parsePostfix(PrecedencePostfix, expressions.NewIsTrue),
 		tokens.TokenTypeIsNotTrue:           negate(p.parsePostfix(PrecedencePostfix, expressions.NewIsTrue)),
 		tokens.TokenTypeIsFalse:             p.parsePostfix(PrecedencePostfix, expressions.NewIsFalse),
@@ -96,14 +102,34 @@ func (p *parser) initExpressionInfixParsers() {
 		tokens.TokenTypeConcat:              p.parseBinary(PrecedenceGenericOperator, expressions.NewConcat),
 		tokens.TokenTypeIsDistinctFrom:      p.parseBinary(PrecedenceIs, expressions.NewIsDistinctFrom),
 		tokens.TokenTypeIsNotDistinctFrom:   negate(p.parseBinary(PrecedenceIs, expressions.NewIsDistinctFrom)),
-		tokens.TokenTypeLike:                p.parseBinary(PrecedenceLike, expressions.NewLike),
-		tokens.TokenTypeNotLike:             negate(p.parseBinary(PrecedenceLike, expressions.NewLike)),
-		tokens.TokenTypeILike:               p.parseBinary(PrecedenceLike, expressions.NewILike),
-		tokens.TokenTypeNotILike:            negate(p.parseBinary(PrecedenceLike, expressions.NewILike)),
+		tokens.TokenTypeLike:                p.parsePattern(expressions.NewLike),
+		tokens.TokenTypeNotLike:             negate(p.parsePattern(expressions.NewLike)),
+		tokens.TokenTypeILike:               p.parsePattern(expressions.NewILike),
+		tokens.TokenTypeNotILike:            negate(p.parsePattern(expressions.NewILike)),
+		tokens.TokenTypeSimilarTo:           p.parsePattern(expressions.NewSimilarTo),
+		tokens.TokenTypeNotSimilarTo:        negate(p.parsePattern(expressions.NewSimilarTo)),
+		tokens.TokenTypeTilde:               p.parseBinary(PrecedenceGenericOperator, expressions.NewRegexMatch),
+		tokens.TokenTypeTildeAsterisk:       p.parseBinary(PrecedenceGenericOperator, expressions.NewIRegexMatch),
+		tokens.TokenTypeNotTilde:            negate(p.parseBinary(PrecedenceGenericOperator, expressions.NewRegexMatch)),
+		tokens.TokenTypeNotTildeAsterisk:    negate(p.parseBinary(PrecedenceGenericOperator, expressions.NewIRegexMatch)),
 		tokens.TokenTypeBetween:             p.parseBetween(expressions.NewBetween),
 		tokens.TokenTypeNotBetween:          negate(p.parseBetween(expressions.NewBetween)),
 		tokens.TokenTypeBetweenSymmetric:    p.parseBetween(expressions.NewBetweenSymmetric),
 		tokens.TokenTypeNotBetweenSymmetric: negate(p.parseBetween(expressions.NewBetweenSymmetric)),
+		tokens.TokenTypeIn:                  p.parseIn,
+		tokens.TokenTypeNotIn:               negate(p.parseIn),
+		tokens.TokenTypeTypeCast:            p.parseTypeCast,
+		tokens.TokenTypeLeftBracket:         p.parseSubscript,
+		tokens.TokenTypeContains:            p.parseBinary(PrecedenceGenericOperator, expressions.NewContains),
+		tokens.TokenTypeContainedBy:         p.parseBinary(PrecedenceGenericOperator, expressions.NewContainedBy),
+		tokens.TokenTypeOverlaps:            p.parseBinary(PrecedenceGenericOperator, expressions.NewArrayOverlaps),
+		tokens.TokenTypeArrow:               p.parseBinary(PrecedenceGenericOperator, expressions.NewJSONBField),
+		tokens.TokenTypeDoubleArrow:         p.parseBinary(PrecedenceGenericOperator, expressions.NewJSONBFieldText),
+		tokens.TokenTypeHashArrow:           p.parseBinary(PrecedenceGenericOperator, expressions.NewJSONBPath),
+		tokens.TokenTypeHashDoubleArrow:     p.parseBinary(PrecedenceGenericOperator, expressions.NewJSONBPathText),
+		tokens.TokenTypeQuestion:            p.parseBinary(PrecedenceGenericOperator, expressions.NewJSONBHasKey),
+		tokens.TokenTypeQuestionPipe:        p.parseBinary(PrecedenceGenericOperator, expressions.NewJSONBHasAnyKey),
+		tokens.TokenTypeQuestionAmpersand:   p.parseBinary(PrecedenceGenericOperator, expressions.NewJSONBHasAllKeys),
 	}
 }
 
@@ -129,7 +155,16 @@ func (p *parser) parseExpressionSuffix(expression impls.Expression, precedence P
 }
 
 func (p *parser) parseStringLiteralExpression(token tokens.Token) (impls.Expression, error) {
-	return expressions.NewConstant(token.Text), nil
+	return expressions.NewLiteral(token.Text), nil
+}
+
+func (p *parser) parseParameterExpression(token tokens.Token) (impls.Expression, error) {
+	index, err := strconv.Atoi(token.Text[1:])
+	if err != nil || index < 1 {
+		return nil, fmt.Errorf("invalid parameter reference (near %s)", token.Text)
+	}
+
+	return expressions.NewParameter(index), nil
 }
 
 func (p *parser) parseBooleanLiteralExpression(token tokens.Token) (impls.Expression, error) {
@@ -163,8 +198,42 @@ func (p *parser) parseNumericLiteralExpression(token tokens.Token) (impls.Expres
 	return expressions.NewConstant(int32(value)), nil
 }
 
-// parenthesizedExpressionTail := expression `)`
+// castTail := `(` expression `AS` modifiedType `)`
+func (p *parser) parseCastExpression(token tokens.Token) (impls.Expression, error) {
+	return parseParenthesized(p, func() (impls.Expression, error) {
+		expression, err := p.parseRootExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := p.mustAdvance(isType(tokens.TokenTypeAs)); err != nil {
+			return nil, err
+		}
+
+		typ, typeModifier, err := p.parseModifiedType()
+		if err != nil {
+			return nil, err
+		}
+
+		return expressions.NewCast(expression, typ, typeModifier), nil
+	})
+}
+
+// parenthesizedExpressionTail := ( `SELECT` selectTail `)` ) | ( expression `)` )
 func (p *parser) parseParenthesizedExpression(token tokens.Token) (impls.Expression, error) {
+	if p.advanceIf(isType(tokens.TokenTypeSelect)) {
+		query, err := p.parseSelect()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := p.mustAdvance(isType(tokens.TokenTypeRightParen)); err != nil {
+			return nil, err
+		}
+
+		return ast.NewScalar(query), nil
+	}
+
 	inner, err := p.parseRootExpression()
 	if err != nil {
 		return nil, err
@@ -177,8 +246,68 @@ func (p *parser) parseParenthesizedExpression(token tokens.Token) (impls.Express
 	return inner, nil
 }
 
-// namedExpressionTail := ( `.` ident ) | ( functionInvocationTail ) | <empty>
+// arrayExpressionTail := `[` [ expression [, ...] ] `]`
+func (p *parser) parseArrayExpression(token tokens.Token) (impls.Expression, error) {
+	if _, err := p.mustAdvance(isType(tokens.TokenTypeLeftBracket)); err != nil {
+		return nil, err
+	}
+
+	var elements []impls.Expression
+	if p.current().Type != tokens.TokenTypeRightBracket {
+		var err error
+		elements, err = parseCommaSeparatedList(p, p.parseRootExpression)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := p.mustAdvance(isType(tokens.TokenTypeRightBracket)); err != nil {
+		return nil, err
+	}
+
+	return expressions.NewArrayConstructor(elements), nil
+}
+
+// existsExpressionTail := subquery
+func (p *parser) parseExistsExpression(token tokens.Token) (impls.Expression, error) {
+	query, err := p.parseSubquery()
+	if err != nil {
+		return nil, err
+	}
+
+	return ast.NewExists(query), nil
+}
+
+// subquery := `(` `SELECT` selectTail `)`
+func (p *parser) parseSubquery() (ast.TableReferenceOrExpression, error) {
+	return parseParenthesized(p, func() (ast.TableReferenceOrExpression, error) {
+		if _, err := p.mustAdvance(isType(tokens.TokenTypeSelect)); err != nil {
+			return nil, err
+		}
+
+		return p.parseSelect()
+	})
+}
+
+// typedLiteralTypes are the types whose values may be written as a type name followed
+// by a string literal, such as `DATE '2024-01-01'` or `INTERVAL '1 day'`.
+var typedLiteralTypes = map[string]types.Type{
+	"date":        types.TypeDate,
+	"time":        types.TypeTime,
+	"timestamp":   types.TypeTimestamp,
+	"timestamptz": types.TypeTimestampTz,
+	"interval":    types.TypeInterval,
+	"uuid":        types.TypeUUID,
+	"bytea":       types.TypeBytea,
+	"jsonb":       types.TypeJSONB,
+}
+
+// namedExpressionTail := ( `.` ident ) | ( functionInvocationTail ) | string | <empty>
 func (p *parser) parseNamedExpression(token tokens.Token) (impls.Expression, error) {
+	if typ, ok := typedLiteralTypes[strings.ToLower(token.Text)]; ok && p.current().Type == tokens.TokenTypeString {
+		return expressions.NewCast(expressions.NewConstant(p.advance().Text), typ, types.TypeModifier{}), nil
+	}
+
 	if p.advanceIf(isType(tokens.TokenTypeDot)) {
 		qualifiedNameToken, err := p.parseIdent()
 		if err != nil {
@@ -189,27 +318,61 @@ func (p *parser) parseNamedExpression(token tokens.Token) (impls.Expression, err
 	}
 
 	if p.peek(0).Type == tokens.TokenTypeLeftParen {
+		if factory, ok := conditionalExpressionFactories[strings.ToLower(token.Text)]; ok {
+			return p.parseConditionalExpressionTail(token, factory)
+		}
+
 		return p.parseFunctionInvocationTail(token)
 	}
 
 	return expressions.NewNamed(fields.NewField("", token.Text, types.TypeAny, fields.NonInternalField)), nil
 }
 
-// functionInvocationTail := `(` [ expression [, ...] ] `)`
+// functionInvocationTail := `(` [ [ `ALL` | `DISTINCT` ] expression [, ...] ] `)` over
 func (p *parser) parseFunctionInvocationTail(token tokens.Token) (impls.Expression, error) {
 	if next := p.peek(0); next.Type != tokens.TokenTypeLeftParen {
 		return nil, fmt.Errorf("expected left paren (near %s)", token.Text)
 	}
 
+	var args []impls.Expression
+	distinct := false
+
 	// Handle special case for COUNT(*) -> COUNT(1)
 	if strings.ToLower(token.Text) == "count" && p.advanceIf(isType(tokens.TokenTypeLeftParen), isType(tokens.TokenTypeAsterisk), isType(tokens.TokenTypeRightParen)) {
-		return expressions.NewFunction(token.Text, []impls.Expression{expressions.NewConstant(1)}), nil
+		args = []impls.Expression{expressions.NewConstant(1)}
+	} else {
+		var err error
+		args, err = parseParenthesized(p, func() ([]impls.Expression, error) {
+			if p.advanceIf(isType(tokens.TokenTypeDistinct)) {
+				distinct = true
+			} else if p.advanceIf(isType(tokens.TokenTypeAll)) {
+				// token is explicitly supplying the default
+			} else if p.current().Type == tokens.TokenTypeRightParen {
+				return nil, nil
+			}
+
+			return parseCommaSeparatedList(p, p.parseRootExpression)
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	args, err := parseParenthesizedCommaSeparatedList(p, false, true, p.parseRootExpression)
+	window, ok, err := p.parseOver()
 	if err != nil {
 		return nil, err
 	}
+	if ok {
+		if distinct {
+			return nil, fmt.Errorf("DISTINCT is not implemented for window functions")
+		}
+
+		return expressions.NewWindowFunction(token.Text, args, window), nil
+	}
+
+	if distinct {
+		return expressions.NewDistinctFunction(token.Text, args), nil
+	}
 
 	return expressions.NewFunction(token.Text, args), nil
 }
@@ -231,11 +394,34 @@ func (p *parser) parseUnary(factory unaryExpressionParserFunc) prefixParserFunc
 
 func (p *parser) parseBinary(precedence Precedence, factory binaryExpressionParserFunc) infixParserFunc {
 	return func(left impls.Expression, token tokens.Token) (impls.Expression, error) {
-		right, err := p.parseExpression(precedence)
+		// Operators are left-associative: a following operator of the same precedence
+		// applies to this expression (e.g., a - b - c is (a - b) - c)
+		right, err := p.parseExpression(precedence + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		return factory(left, right), nil
+	}
+}
+
+// patternTail := expression [ `ESCAPE` expression ]
+func (p *parser) parsePattern(factory binaryExpressionParserFunc) infixParserFunc {
+	return func(left impls.Expression, token tokens.Token) (impls.Expression, error) {
+		right, err := p.parseExpression(PrecedenceLike)
 		if err != nil {
 			return nil, err
 		}
 
+		if p.advanceIf(isIdent("escape")) {
+			escape, err := p.parseExpression(PrecedenceLike)
+			if err != nil {
+				return nil, err
+			}
+
+			right = expressions.NewPatternEscape(right, escape)
+		}
+
 		return factory(left, right), nil
 	}
 }
@@ -266,6 +452,116 @@ func (p *parser) parseBetween(factory ternaryExpressionParserFunc) infixParserFu
 	}
 }
 
+// typeCastTail := modifiedType
+func (p *parser) parseTypeCast(left impls.Expression, token tokens.Token) (impls.Expression, error) {
+	typ, typeModifier, err := p.parseModifiedType()
+	if err != nil {
+		return nil, err
+	}
+
+	return expressions.NewCast(left, typ, typeModifier), nil
+}
+
+// subscriptTail := ( expression | [ expression ] `:` [ expression ] ) `]`
+func (p *parser) parseSubscript(left impls.Expression, token tokens.Token) (impls.Expression, error) {
+	var lower, upper impls.Expression
+	if p.current().Type != tokens.TokenTypeColon {
+		expression, err := p.parseRootExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		lower = expression
+	}
+
+	if !p.advanceIf(isType(tokens.TokenTypeColon)) {
+		if _, err := p.mustAdvance(isType(tokens.TokenTypeRightBracket)); err != nil {
+			return nil, err
+		}
+
+		return expressions.NewSubscript(left, lower), nil
+	}
+
+	if p.current().Type != tokens.TokenTypeRightBracket {
+		expression, err := p.parseRootExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		upper = expression
+	}
+
+	if _, err := p.mustAdvance(isType(tokens.TokenTypeRightBracket)); err != nil {
+		return nil, err
+	}
+
+	return expressions.NewSlice(left, lower, upper), nil
+}
+
+// comparisonTail := ( ( `ANY` | `SOME` | `ALL` ) ( subquery | ( `(` expression `)` ) ) ) | expression
+func (p *parser) parseComparison(precedence Precedence, operator ast.ComparisonOperator) infixParserFunc {
+	return func(left impls.Expression, token tokens.Token) (impls.Expression, error) {
+		if p.current().Type == tokens.TokenTypeAny || p.current().Type == tokens.TokenTypeSome || p.current().Type == tokens.TokenTypeAll {
+			quantifier := p.advance()
+
+			if p.peek(1).Type != tokens.TokenTypeSelect {
+				array, err := parseParenthesized(p, p.parseRootExpression)
+				if err != nil {
+					return nil, err
+				}
+
+				return expressions.NewArrayComparison(left, array, operator.Symbol, operator.Compare, quantifier.Type == tokens.TokenTypeAll), nil
+			}
+
+			query, err := p.parseSubquery()
+			if err != nil {
+				return nil, err
+			}
+
+			if quantifier.Type == tokens.TokenTypeAll {
+				return ast.NewAll(left, operator, query), nil
+			}
+
+			return ast.NewAny(left, operator, query), nil
+		}
+
+		right, err := p.parseExpression(precedence)
+		if err != nil {
+			return nil, err
+		}
+
+		return operator.Compare(left, right), nil
+	}
+}
+
+// inTail := subquery | ( `(` expression [, ...] `)` )
+func (p *parser) parseIn(left impls.Expression, token tokens.Token) (impls.Expression, error) {
+	if p.current().Type == tokens.TokenTypeLeftParen && p.peek(1).Type == tokens.TokenTypeSelect {
+		query, err := p.parseSubquery()
+		if err != nil {
+			return nil, err
+		}
+
+		return ast.NewAny(left, ast.ComparisonOperator{Symbol: "=", Factory: expressions.NewEquals}, query), nil
+	}
+
+	values, err := parseParenthesizedCommaSeparatedList(p, false, false, p.parseRootExpression)
+	if err != nil {
+		return nil, err
+	}
+
+	var expression impls.Expression
+	for _, value := range values {
+		if expression == nil {
+			expression = expressions.NewEquals(left, value)
+		} else {
+			expression = expressions.NewOr(expression, expressions.NewEquals(left, value))
+		}
+	}
+
+	return expression, nil
+}
+
 func negate(parserFunc infixParserFunc) infixParserFunc {
 	return func(left impls.Expression, token tokens.Token) (impls.Expression, error) {
 		expression, err := parserFunc(left, token)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/efritz/gostgres/internal/execution/protocol"
 	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/shared/types"
 	"github.com/efritz/gostgres/internal/syntax/tokens"
 )
 
@@ -15,18 +16,26 @@ type Query interface {
 }
 
 func Parse(catalog impls.CatalogSet, tokenStream []tokens.Token) (Query, error) {
-	parser := newParser(tokenStream)
+	statement, _, err := ParseWithParameterTypes(catalog, tokenStream, nil)
+	return statement, err
+}
+
+// ParseWithParameterTypes parses a statement that may reference positional parameters.
+// Parameters without a declared type have their type inferred from their use in the
+// statement. The types of all parameters referenced by the statement are returned.
+func ParseWithParameterTypes(catalog impls.CatalogSet, tokenStream []tokens.Token, parameterTypes []types.Type) (Query, []types.Type, error) {
+	parser := newParser(tokenStream, impls.NewParameterTypes(parameterTypes))
 	statement, err := parser.parseStatement(catalog)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	_ = parser.advanceIf(isType(tokens.TokenTypeSemicolon))
 	if parser.cursor < len(parser.tokens) {
-		return nil, fmt.Errorf("unexpected tokens at end of statement (near %s)", parser.tokens[parser.cursor].Text)
+		return nil, nil, fmt.Errorf("unexpected tokens at end of statement (near %s)", parser.tokens[parser.cursor].Text)
 	}
 
-	return statement, nil
+	return statement, parser.parameterTypes.Finalize(), nil
 }
 
 func SplitStatements(input string) []string {
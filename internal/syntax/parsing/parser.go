@@ -2,6 +2,7 @@ package parsing
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/syntax/ast"
@@ -17,8 +18,11 @@ type parser struct {
 	addConstraintParsers    addConstraintParsers
 	columnConstraintParsers columnConstraintParsers
 	explainableParsers      explainableParsers
+	preparedParsers         preparedParsers
+	transactionParsers      transactionParsers
 	prefixParsers           prefixParsers
 	infixParsers            infixParsers
+	parameterTypes          *impls.ParameterTypes
 }
 
 type tokenFilterFunc func(token tokens.Token) bool
@@ -31,12 +35,15 @@ type alterParsers map[tokens.TokenType]func() (Query, error)
 type addConstraintParsers map[tokens.TokenType]func(name, tableName string) (Query, error)
 type columnConstraintParsers map[tokens.TokenType]func(columnName, tableName string, description *columnDescription) error
 type explainableParsers map[tokens.TokenType]func(token tokens.Token) (ast.BuilderResolver, error)
+type preparedParsers map[tokens.TokenType]func(catalog impls.CatalogSet) (Query, error)
+type transactionParsers map[tokens.TokenType]func() (Query, error)
 type prefixParsers map[tokens.TokenType]prefixParserFunc
 type infixParsers map[tokens.TokenType]infixParserFunc
 
-func newParser(tokenStream []tokens.Token) *parser {
+func newParser(tokenStream []tokens.Token, parameterTypes *impls.ParameterTypes) *parser {
 	p := &parser{
-		tokens: tokenStream,
+		tokens:         tokenStream,
+		parameterTypes: parameterTypes,
 	}
 
 	p.initAlterParsers()
@@ -46,7 +53,9 @@ func newParser(tokenStream []tokens.Token) *parser {
 	p.initDDLParsers()
 	p.initExpressionInfixParsers()
 	p.initExpressionPrefixParsers()
+	p.initPreparedParsers()
 	p.initStatementParsers()
+	p.initTransactionParsers()
 	return p
 }
 
@@ -97,8 +106,10 @@ func isType(tokenType tokens.TokenType) tokenFilterFunc {
 	}
 }
 
+// isIdent matches an identifier with the given (lowercase) text, case-insensitively,
+// such as a non-reserved keyword.
 func isIdent(text string) tokenFilterFunc {
 	return func(t tokens.Token) bool {
-		return t.Type == tokens.TokenTypeIdent && t.Text == text
+		return t.Type == tokens.TokenTypeIdent && strings.ToLower(t.Text) == text
 	}
 }
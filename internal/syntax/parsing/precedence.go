@@ -12,7 +12,7 @@ const (
 	PrecedenceComparison
 	PrecedenceLike
 	PrecedenceBetween
-	// PrecedenceIn
+	PrecedenceIn
 	PrecedenceGenericOperator
 	PrecedenceIsNotNull
 	PrecedenceIsNull
@@ -21,6 +21,8 @@ const (
 	PrecedenceMultiplicative
 	PrecedenceUnary
 	PrecedencePostfix
+	PrecedenceTypeCast
+	PrecedenceSubscript
 	PrecedenceAny
 )
 
@@ -52,8 +54,28 @@ var precedenceMap = map[tokens.TokenType]Precedence{
 	tokens.TokenTypeNotLike:             PrecedenceLike,
 	tokens.TokenTypeILike:               PrecedenceLike,
 	tokens.TokenTypeNotILike:            PrecedenceLike,
+	tokens.TokenTypeSimilarTo:           PrecedenceLike,
+	tokens.TokenTypeNotSimilarTo:        PrecedenceLike,
+	tokens.TokenTypeTilde:               PrecedenceGenericOperator,
+	tokens.TokenTypeTildeAsterisk:       PrecedenceGenericOperator,
+	tokens.TokenTypeNotTilde:            PrecedenceGenericOperator,
+	tokens.TokenTypeNotTildeAsterisk:    PrecedenceGenericOperator,
 	tokens.TokenTypeBetween:             PrecedenceBetween,
 	tokens.TokenTypeNotBetween:          PrecedenceBetween,
 	tokens.TokenTypeBetweenSymmetric:    PrecedenceBetween,
 	tokens.TokenTypeNotBetweenSymmetric: PrecedenceBetween,
+	tokens.TokenTypeIn:                  PrecedenceIn,
+	tokens.TokenTypeNotIn:               PrecedenceIn,
+	tokens.TokenTypeTypeCast:            PrecedenceTypeCast,
+	tokens.TokenTypeLeftBracket:         PrecedenceSubscript,
+	tokens.TokenTypeContains:            PrecedenceGenericOperator,
+	tokens.TokenTypeContainedBy:         PrecedenceGenericOperator,
+	tokens.TokenTypeOverlaps:            PrecedenceGenericOperator,
+	tokens.TokenTypeArrow:               PrecedenceGenericOperator,
+	tokens.TokenTypeDoubleArrow:         PrecedenceGenericOperator,
+	tokens.TokenTypeHashArrow:           PrecedenceGenericOperator,
+	tokens.TokenTypeHashDoubleArrow:     PrecedenceGenericOperator,
+	tokens.TokenTypeQuestion:            PrecedenceGenericOperator,
+	tokens.TokenTypeQuestionPipe:        PrecedenceGenericOperator,
+	tokens.TokenTypeQuestionAmpersand:   PrecedenceGenericOperator,
 }
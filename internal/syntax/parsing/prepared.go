@@ -0,0 +1,101 @@
+package parsing
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/execution/queries"
+	"github.com/efritz/gostgres/internal/execution/queries/prepared"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/syntax/tokens"
+)
+
+func (p *parser) initPreparedParsers() {
+	p.preparedParsers = preparedParsers{
+		tokens.TokenTypePrepare:    p.parsePrepare,
+		tokens.TokenTypeExecute:    p.parseExecute,
+		tokens.TokenTypeDeallocate: p.parseDeallocate,
+	}
+}
+
+// prepareTail := ident [ `(` basicType [, ...] `)` ] `AS` ( [ `EXPLAIN` ] explainableStatement )
+func (p *parser) parsePrepare(catalog impls.CatalogSet) (Query, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	parameterTypes, err := parseParenthesizedCommaSeparatedList(p, true, false, p.parseBasicType)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.mustAdvance(isType(tokens.TokenTypeAs)); err != nil {
+		return nil, err
+	}
+
+	if _, ok := p.explainableParsers[p.current().Type]; !ok && p.current().Type != tokens.TokenTypeExplain {
+		return nil, fmt.Errorf("expected preparable statement (near %s)", p.current().Text)
+	}
+
+	// Parameters of the prepared statement are distinct from any parameters
+	// of the enclosing statement
+	outerParameterTypes := p.parameterTypes
+	p.parameterTypes = impls.NewParameterTypes(parameterTypes)
+	defer func() { p.parameterTypes = outerParameterTypes }()
+
+	catalogVersion := catalog.Version()
+	start := p.cursor
+	statement, err := p.parseStatement(catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	// The statement is parsed again from its tokens if the catalog changes before
+	// it is executed
+	statementTokens := p.tokens[start:p.cursor]
+	finalizedTypes := p.parameterTypes.Finalize()
+	plan := func() (queries.Query, error) {
+		statement, _, err := ParseWithParameterTypes(catalog, statementTokens, finalizedTypes)
+		return statement, err
+	}
+
+	return prepared.NewPrepare(name, prepared.NewPreparedStatement(statement, finalizedTypes, catalogVersion, plan)), nil
+}
+
+// executeTail := ident [ `(` expression [, ...] `)` ]
+func (p *parser) parseExecute(catalog impls.CatalogSet) (Query, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	parameters, err := parseParenthesizedCommaSeparatedList(p, true, false, p.parseRootExpression)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := impls.NewExpressionResolutionContext(catalog, false).WithParameterTypes(p.parameterTypes)
+	for _, parameter := range parameters {
+		if err := parameter.Resolve(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return prepared.NewExecute(name, parameters), nil
+}
+
+// deallocateTail := [ `PREPARE` ] ( ident | `ALL` )
+func (p *parser) parseDeallocate(catalog impls.CatalogSet) (Query, error) {
+	_ = p.advanceIf(isType(tokens.TokenTypePrepare))
+
+	if p.advanceIf(isType(tokens.TokenTypeAll)) {
+		return prepared.NewDeallocateAll(), nil
+	}
+
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	return prepared.NewDeallocate(name), nil
+}
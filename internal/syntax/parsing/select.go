@@ -1,6 +1,7 @@
 package parsing
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/efritz/gostgres/internal/execution/expressions"
@@ -14,7 +15,7 @@ func (p *parser) parseSelectBuilder(_ tokens.Token) (ast.BuilderResolver, error)
 	return p.parseSelect()
 }
 
-// selectTail := simpleSelect orderBy limitOffset
+// selectTail := simpleSelect orderBy limitOffset locking
 func (p *parser) parseSelect() (ast.TableReferenceOrExpression, error) {
 	simpleSelect, err := p.parseSimpleSelect()
 	if err != nil {
@@ -31,26 +32,45 @@ func (p *parser) parseSelect() (ast.TableReferenceOrExpression, error) {
 		return nil, err
 	}
 
+	locking, err := p.parseLocking()
+	if err != nil {
+		return nil, err
+	}
+
 	simpleSelect.Order = orderExpression
 	simpleSelect.Limit = limit
 	simpleSelect.Offset = offset
+	simpleSelect.Locking = locking
 
 	return simpleSelect, nil
 }
 
-// simpleSelect := selectExpressions from where groupBy combinedQuery
+// simpleSelect := distinct selectExpressions [ from ] where groupBy having windowClause combinedQuery
 func (p *parser) parseSimpleSelect() (*ast.SelectBuilder, error) {
-	selectExpressions, err := p.parseSelectExpressions()
+	distinct, distinctOn, err := p.parseDistinct()
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO - make from optional
-	node, err := p.parseFrom()
+	selectExpressions, err := p.parseSelectExpressions()
 	if err != nil {
 		return nil, err
 	}
 
+	// A select without a FROM clause selects from a single row with no columns
+	node := &ast.TableExpression{
+		Base: ast.AliasedTableReferenceOrExpression{
+			BaseTableExpression: &ast.ValuesBuilder{Expressions: [][]impls.Expression{nil}},
+		},
+	}
+
+	if p.current().Type == tokens.TokenTypeFrom {
+		node, err = p.parseFrom()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	whereExpression, _, err := p.parseWhere()
 	if err != nil {
 		return nil, err
@@ -61,22 +81,59 @@ func (p *parser) parseSimpleSelect() (*ast.SelectBuilder, error) {
 		return nil, err
 	}
 
+	having, _, err := p.parseHaving()
+	if err != nil {
+		return nil, err
+	}
+
+	windows, err := p.parseWindowClause()
+	if err != nil {
+		return nil, err
+	}
+
 	combinations, err := p.parseCombinedQuery()
 	if err != nil {
 		return nil, err
 	}
 
 	builder := &ast.SelectBuilder{
+		Distinct:          distinct,
+		DistinctOn:        distinctOn,
 		SelectExpressions: selectExpressions,
 		From:              node,
 		Where:             whereExpression,
 		Groupings:         groupings,
+		Having:            having,
+		Windows:           windows,
 		Combinations:      combinations,
 	}
 
 	return builder, nil
 }
 
+// distinct := [ `ALL` | ( `DISTINCT` [ `ON` `(` expression [, ...] `)` ] ) ]
+func (p *parser) parseDistinct() (bool, []impls.Expression, error) {
+	if p.advanceIf(isType(tokens.TokenTypeAll)) {
+		// token is explicitly supplying the default
+		return false, nil, nil
+	}
+
+	if !p.advanceIf(isType(tokens.TokenTypeDistinct)) {
+		return false, nil, nil
+	}
+
+	if !p.advanceIf(isType(tokens.TokenTypeOn)) {
+		return true, nil, nil
+	}
+
+	distinctOn, err := parseParenthesizedCommaSeparatedList(p, false, false, p.parseRootExpression)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return true, distinctOn, nil
+}
+
 // selectExpressions := selectExpression [, ...]
 func (p *parser) parseSelectExpressions() (aliasedExpressions []projection.ProjectionExpression, _ error) {
 	return parseCommaSeparatedList(p, p.parseSelectExpression)
@@ -130,6 +187,20 @@ func (p *parser) parseGroupBy() ([]impls.Expression, bool, error) {
 	return groupingExpressions, true, nil
 }
 
+// having := [ `HAVING` expression ]
+func (p *parser) parseHaving() (impls.Expression, bool, error) {
+	if !p.advanceIf(isType(tokens.TokenTypeHaving)) {
+		return nil, false, nil
+	}
+
+	havingExpression, err := p.parseRootExpression()
+	if err != nil {
+		return nil, false, err
+	}
+
+	return havingExpression, true, nil
+}
+
 // combinedQuery := [ ( ( `UNION` | `INTERSECT` | `EXCEPT` ) [ ( `ALL` | `DISTINCT` ) ] combinationTarget ) [, ...] ]
 func (p *parser) parseCombinedQuery() ([]*ast.CombinationDescription, error) {
 	var combinations []*ast.CombinationDescription
@@ -269,3 +340,34 @@ func (p *parser) parseOffset() (int, bool, error) {
 	limitValue, err := strconv.Atoi(limitToken.Text)
 	return limitValue, true, err
 }
+
+// locking := [ `FOR` ( `UPDATE` | `SHARE` ) [ `OF` ident [, ...] ] ]
+func (p *parser) parseLocking() (*ast.LockingClause, error) {
+	if !p.advanceIf(isType(tokens.TokenTypeFor)) {
+		return nil, nil
+	}
+
+	var mode impls.LockMode
+	if p.advanceIf(isType(tokens.TokenTypeUpdate)) {
+		mode = impls.ForUpdateLock
+	} else if p.advanceIf(isType(tokens.TokenTypeShare)) {
+		mode = impls.ForShareLock
+	} else {
+		return nil, fmt.Errorf("expected UPDATE or SHARE (near %s)", p.current().Text)
+	}
+
+	var relationNames []string
+	if p.advanceIf(isType(tokens.TokenTypeOf)) {
+		names, err := parseCommaSeparatedList(p, p.parseIdent)
+		if err != nil {
+			return nil, err
+		}
+
+		relationNames = names
+	}
+
+	return &ast.LockingClause{
+		Mode:          mode,
+		RelationNames: relationNames,
+	}, nil
+}
@@ -21,13 +21,40 @@ func (p *parser) initStatementParsers() {
 		tokens.TokenTypeInsert: p.parseInsert,
 		tokens.TokenTypeUpdate: p.parseUpdate,
 		tokens.TokenTypeDelete: p.parseDelete,
+		tokens.TokenTypeWith:   p.parseWith,
 	}
 }
 
-// statement := ddlStatement | ( [ `EXPLAIN` ] explainableStatement )
+// statement := transactionStatement | preparedStatement | utilityStatement | ddlStatement | ( [ `EXPLAIN` ] explainableStatement )
+// transactionStatement := ( `BEGIN` beginTail ) | ( `START` startTransactionTail ) | ( ( `COMMIT` | `END` ) commitTail ) | ( `ROLLBACK` rollbackTail ) | ( `ABORT` abortTail ) | ( `SAVEPOINT` savepointTail ) | ( `RELEASE` releaseTail ) | ( `LOCK` lockTail )
+// preparedStatement := ( `PREPARE` prepareTail ) | ( `EXECUTE` executeTail ) | ( `DEALLOCATE` deallocateTail )
+// utilityStatement := ( `CHECKPOINT` checkpointTail ) | ( `ANALYZE` analyzeTail ) | ( `VACUUM` vacuumTail )
 // ddlStatement := ( `CREATE` createTail ) | ( `ALTER` alterTail )
-// explainableStatement := ( `SELECT` selectTail ) | ( `INSERT` insertTail ) | ( `UPDATE` updateTail ) | ( `DELETE` deleteTail )
+// explainableStatement := ( `SELECT` selectTail ) | ( `INSERT` insertTail ) | ( `UPDATE` updateTail ) | ( `DELETE` deleteTail ) | ( `WITH` withTail )
 func (p *parser) parseStatement(catalog impls.CatalogSet) (Query, error) {
+	for tokenType, parser := range p.transactionParsers {
+		if p.advanceIf(isType(tokenType)) {
+			return parser()
+		}
+	}
+
+	for tokenType, parser := range p.preparedParsers {
+		if p.advanceIf(isType(tokenType)) {
+			return parser(catalog)
+		}
+	}
+
+	// CHECKPOINT, ANALYZE, and VACUUM are not reserved words
+	if p.advanceIf(isIdent("checkpoint")) {
+		return p.parseCheckpoint()
+	}
+	if p.advanceIf(isIdent("analyze")) {
+		return p.parseAnalyze()
+	}
+	if p.advanceIf(isIdent("vacuum")) {
+		return p.parseVacuum()
+	}
+
 	for tokenType, parser := range p.ddlParsers {
 		token := p.current()
 		if p.advanceIf(isType(tokenType)) {
@@ -48,7 +75,7 @@ func (p *parser) parseStatement(catalog impls.CatalogSet) (Query, error) {
 				return nil, err
 			}
 
-			if err := builder.Resolve(impls.NewNodeResolutionContext(catalog)); err != nil {
+			if err := builder.Resolve(impls.NewNodeResolutionContext(catalog).WithParameterTypes(p.parameterTypes)); err != nil {
 				return nil, err
 			}
 
@@ -3,6 +3,7 @@ package parsing
 import (
 	"fmt"
 
+	"github.com/efritz/gostgres/internal/execution/queries/nodes/join"
 	"github.com/efritz/gostgres/internal/shared/fields"
 	"github.com/efritz/gostgres/internal/shared/impls"
 	"github.com/efritz/gostgres/internal/shared/types"
@@ -48,7 +49,7 @@ func (p *parser) parseTableExpressions() ([]*ast.TableExpression, error) {
 	return parseCommaSeparatedList(p, p.parseTableExpression)
 }
 
-// tableExpression := aliasedBaseTableExpression [ `JOIN` joinTail [...] ]
+// tableExpression := aliasedBaseTableExpression [ join [...] ]
 func (p *parser) parseTableExpression() (*ast.TableExpression, error) {
 	node, err := p.parseAliasedBaseTableExpression()
 	if err != nil {
@@ -56,11 +57,14 @@ func (p *parser) parseTableExpression() (*ast.TableExpression, error) {
 	}
 
 	var joins []ast.Join
-	for p.advanceIf(isType(tokens.TokenTypeJoin)) {
-		join, err := p.parseJoin()
+	for {
+		join, ok, err := p.parseJoin()
 		if err != nil {
 			return nil, err
 		}
+		if !ok {
+			break
+		}
 
 		joins = append(joins, join)
 	}
@@ -120,13 +124,25 @@ func (p *parser) parseBaseTableExpression() (ast.TableReferenceOrExpression, err
 	return p.parseTableReference()
 }
 
-// tableReference := ident
+// tableReference := ident [ `(` [ expression [, ...] ] `)` ]
 func (p *parser) parseTableReference() (ast.TableReferenceOrExpression, error) {
 	nameToken, err := p.parseIdent()
 	if err != nil {
 		return &ast.TableReference{}, err
 	}
 
+	if p.current().Type == tokens.TokenTypeLeftParen {
+		args, err := parseParenthesizedCommaSeparatedList(p, false, true, p.parseRootExpression)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.TableFunctionReference{
+			Name: nameToken,
+			Args: args,
+		}, nil
+	}
+
 	return &ast.TableReference{
 		Name: nameToken,
 	}, nil
@@ -208,14 +224,89 @@ func (p *parser) parseAlias() (string, bool, error) {
 	return "", false, nil
 }
 
-// joinTail := tableExpression [`ON` expression]
-func (p *parser) parseJoin() (ast.Join, error) {
+// join := ( `CROSS` `JOIN` aliasedBaseTableExpression ) | ( `NATURAL` joinType `JOIN` aliasedBaseTableExpression ) | ( joinType `JOIN` joinTail )
+func (p *parser) parseJoin() (ast.Join, bool, error) {
+	if p.advanceIf(isType(tokens.TokenTypeCross), isType(tokens.TokenTypeJoin)) {
+		table, err := p.parseAliasedBaseTableExpression()
+		if err != nil {
+			return ast.Join{}, false, err
+		}
+
+		return ast.Join{
+			Table:    &ast.TableExpression{Base: table},
+			JoinType: join.JoinTypeCross,
+		}, true, nil
+	}
+
+	natural := p.advanceIf(isType(tokens.TokenTypeNatural))
+
+	joinType, ok := p.parseJoinType()
+	if !ok {
+		if natural {
+			return ast.Join{}, false, fmt.Errorf("expected join type (near %s)", p.current().Text)
+		}
+
+		return ast.Join{}, false, nil
+	}
+
+	if _, err := p.mustAdvance(isType(tokens.TokenTypeJoin)); err != nil {
+		return ast.Join{}, false, err
+	}
+
+	if natural {
+		table, err := p.parseAliasedBaseTableExpression()
+		if err != nil {
+			return ast.Join{}, false, err
+		}
+
+		return ast.Join{
+			Table:    &ast.TableExpression{Base: table},
+			JoinType: joinType,
+			Natural:  true,
+		}, true, nil
+	}
+
+	j, err := p.parseJoinTail(joinType)
+	if err != nil {
+		return ast.Join{}, false, err
+	}
+
+	return j, true, nil
+}
+
+// joinType := [ `INNER` | ( ( `LEFT` | `RIGHT` | `FULL` ) [ `OUTER` ] ) ]
+func (p *parser) parseJoinType() (join.JoinType, bool) {
+	if p.advanceIf(isType(tokens.TokenTypeInner)) {
+		return join.JoinTypeInner, true
+	}
+
+	var joinType join.JoinType
+	switch p.current().Type {
+	case tokens.TokenTypeLeft:
+		joinType = join.JoinTypeLeftOuter
+	case tokens.TokenTypeRight:
+		joinType = join.JoinTypeRightOuter
+	case tokens.TokenTypeFull:
+		joinType = join.JoinTypeFullOuter
+	default:
+		return join.JoinTypeInner, p.current().Type == tokens.TokenTypeJoin
+	}
+
+	p.advance()
+	p.advanceIf(isType(tokens.TokenTypeOuter))
+	return joinType, true
+}
+
+// joinTail := tableExpression [ ( `ON` expression ) | ( `USING` `(` ident [, ...] `)` ) ]
+func (p *parser) parseJoinTail(joinType join.JoinType) (ast.Join, error) {
 	table, err := p.parseTableExpression()
 	if err != nil {
 		return ast.Join{}, err
 	}
 
 	var condition impls.Expression
+	var using []string
+
 	if p.advanceIf(isType(tokens.TokenTypeOn)) {
 		rawCondition, err := p.parseRootExpression()
 		if err != nil {
@@ -223,13 +314,22 @@ func (p *parser) parseJoin() (ast.Join, error) {
 		}
 
 		condition = rawCondition
+	} else if p.advanceIf(isType(tokens.TokenTypeUsing)) {
+		columnNames, err := parseParenthesizedCommaSeparatedList(p, false, false, p.parseIdent)
+		if err != nil {
+			return ast.Join{}, err
+		}
+
+		using = columnNames
+	} else if joinType != join.JoinTypeInner {
+		return ast.Join{}, fmt.Errorf("expected ON or USING (near %s)", p.current().Text)
 	}
 
-	// TODO - support USING
-	// TODO - support multiple join types: (natural, left, outer, full, cross, and combinations)
 	return ast.Join{
 		Table:     table,
+		JoinType:  joinType,
 		Condition: condition,
+		Using:     using,
 	}, nil
 }
 
@@ -247,6 +347,7 @@ func joinNodes(expressions []*ast.TableExpression) *ast.TableExpression {
 	for _, right := range expressions[1:] {
 		joins = append(joins, ast.Join{
 			Table:     right,
+			JoinType:  join.JoinTypeInner,
 			Condition: nil,
 		})
 	}
@@ -0,0 +1,151 @@
+package parsing
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/execution/queries/transaction"
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/syntax/tokens"
+)
+
+func (p *parser) initTransactionParsers() {
+	p.transactionParsers = transactionParsers{
+		tokens.TokenTypeBegin:     p.parseBegin,
+		tokens.TokenTypeStart:     p.parseStartTransaction,
+		tokens.TokenTypeCommit:    p.parseCommit,
+		tokens.TokenTypeEnd:       p.parseCommit,
+		tokens.TokenTypeRollback:  p.parseRollback,
+		tokens.TokenTypeAbort:     p.parseAbort,
+		tokens.TokenTypeSavepoint: p.parseSavepoint,
+		tokens.TokenTypeRelease:   p.parseRelease,
+		tokens.TokenTypeLock:      p.parseLock,
+	}
+}
+
+// beginTail := [ `WORK` | `TRANSACTION` ]
+func (p *parser) parseBegin() (Query, error) {
+	p.parseTransactionNoiseWord()
+	return transaction.NewBegin(), nil
+}
+
+// startTransactionTail := `TRANSACTION`
+func (p *parser) parseStartTransaction() (Query, error) {
+	if _, err := p.mustAdvance(isType(tokens.TokenTypeTransaction)); err != nil {
+		return nil, err
+	}
+
+	return transaction.NewStartTransaction(), nil
+}
+
+// commitTail := [ `WORK` | `TRANSACTION` ]
+func (p *parser) parseCommit() (Query, error) {
+	p.parseTransactionNoiseWord()
+	return transaction.NewCommit(), nil
+}
+
+// rollbackTail := [ `WORK` | `TRANSACTION` ] [ `TO` [ `SAVEPOINT` ] ident ]
+func (p *parser) parseRollback() (Query, error) {
+	p.parseTransactionNoiseWord()
+
+	if !p.advanceIf(isType(tokens.TokenTypeTo)) {
+		return transaction.NewRollback(), nil
+	}
+
+	_ = p.advanceIf(isType(tokens.TokenTypeSavepoint))
+
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction.NewRollbackToSavepoint(name), nil
+}
+
+// abortTail := [ `WORK` | `TRANSACTION` ]
+func (p *parser) parseAbort() (Query, error) {
+	p.parseTransactionNoiseWord()
+	return transaction.NewRollback(), nil
+}
+
+// savepointTail := ident
+func (p *parser) parseSavepoint() (Query, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction.NewSavepoint(name), nil
+}
+
+// releaseTail := [ `SAVEPOINT` ] ident
+func (p *parser) parseRelease() (Query, error) {
+	_ = p.advanceIf(isType(tokens.TokenTypeSavepoint))
+
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction.NewReleaseSavepoint(name), nil
+}
+
+func (p *parser) parseTransactionNoiseWord() {
+	if !p.advanceIf(isType(tokens.TokenTypeWork)) {
+		_ = p.advanceIf(isType(tokens.TokenTypeTransaction))
+	}
+}
+
+// lockTail := [ `TABLE` ] ident [, ...] [ `IN` lockMode `MODE` ]
+func (p *parser) parseLock() (Query, error) {
+	_ = p.advanceIf(isType(tokens.TokenTypeTable))
+
+	tableNames, err := parseCommaSeparatedList(p, p.parseIdent)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := impls.AccessExclusiveLock
+	if p.advanceIf(isType(tokens.TokenTypeIn)) {
+		mode, err = p.parseLockMode()
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := p.mustAdvance(isType(tokens.TokenTypeMode)); err != nil {
+			return nil, err
+		}
+	}
+
+	return transaction.NewLock(tableNames, mode), nil
+}
+
+// lockModes is ordered so that no sequence is a prefix of a sequence that follows it
+var lockModes = []struct {
+	tokenTypes []tokens.TokenType
+	mode       impls.LockMode
+}{
+	{[]tokens.TokenType{tokens.TokenTypeAccess, tokens.TokenTypeShare}, impls.AccessShareLock},
+	{[]tokens.TokenType{tokens.TokenTypeAccess, tokens.TokenTypeExclusive}, impls.AccessExclusiveLock},
+	{[]tokens.TokenType{tokens.TokenTypeRow, tokens.TokenTypeShare}, impls.RowShareLock},
+	{[]tokens.TokenType{tokens.TokenTypeRow, tokens.TokenTypeExclusive}, impls.RowExclusiveLock},
+	{[]tokens.TokenType{tokens.TokenTypeShare, tokens.TokenTypeUpdate, tokens.TokenTypeExclusive}, impls.ShareUpdateExclusiveLock},
+	{[]tokens.TokenType{tokens.TokenTypeShare, tokens.TokenTypeRow, tokens.TokenTypeExclusive}, impls.ShareRowExclusiveLock},
+	{[]tokens.TokenType{tokens.TokenTypeShare}, impls.ShareLock},
+	{[]tokens.TokenType{tokens.TokenTypeExclusive}, impls.ExclusiveLock},
+}
+
+// lockMode := `ACCESS SHARE` | `ROW SHARE` | `ROW EXCLUSIVE` | `SHARE UPDATE EXCLUSIVE` | `SHARE` | `SHARE ROW EXCLUSIVE` | `EXCLUSIVE` | `ACCESS EXCLUSIVE`
+func (p *parser) parseLockMode() (impls.LockMode, error) {
+	for _, lockMode := range lockModes {
+		var filters []tokenFilterFunc
+		for _, tokenType := range lockMode.tokenTypes {
+			filters = append(filters, isType(tokenType))
+		}
+
+		if p.advanceIf(filters...) {
+			return lockMode.mode, nil
+		}
+	}
+
+	return 0, fmt.Errorf("expected lock mode (near %s)", p.current().Text)
+}
@@ -2,49 +2,181 @@ package parsing
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/efritz/gostgres/internal/shared/types"
+	"github.com/efritz/gostgres/internal/syntax/tokens"
 )
 
-// basicType := ident
+// basicType := modifiedType
 func (p *parser) parseBasicType() (types.Type, error) {
+	typ, _, err := p.parseModifiedType()
+	return typ, err
+}
+
+// modifiedType := elementType [ `[` [ number ] `]` ... ]
+func (p *parser) parseModifiedType() (types.Type, types.TypeModifier, error) {
+	typ, typeModifier, err := p.parseElementType()
+	if err != nil {
+		return types.TypeUnknown, types.TypeModifier{}, err
+	}
+
+	// Like Postgres, the declared number and size of dimensions are not enforced
+	for p.advanceIf(isType(tokens.TokenTypeLeftBracket)) {
+		p.advanceIf(isType(tokens.TokenTypeNumber))
+
+		if _, err := p.mustAdvance(isType(tokens.TokenTypeRightBracket)); err != nil {
+			return types.TypeUnknown, types.TypeModifier{}, err
+		}
+
+		typ = types.NewArrayType(typ.ElementType())
+	}
+
+	return typ, typeModifier, nil
+}
+
+// elementType := ident [ ident ... ] [ `(` number [, ...] `)` ]
+func (p *parser) parseElementType() (types.Type, types.TypeModifier, error) {
 	dataType, err := p.parseIdent()
 	if err != nil {
-		return types.TypeUnknown, err
+		return types.TypeUnknown, types.TypeModifier{}, err
 	}
 
 	var typ types.Type
+	var modifierFactory func(args []int) (types.TypeModifier, error)
+
 	switch strings.ToLower(dataType) {
 	case "text":
 		typ = types.TypeText
-	case "smallint":
+	case "smallint", "int2":
 		typ = types.TypeSmallInteger
-	case "integer":
+	case "integer", "int", "int4":
 		typ = types.TypeInteger
-	case "bigint":
+	case "bigint", "int8":
 		typ = types.TypeBigInteger
-	case "real":
+	case "real", "float4":
 		typ = types.TypeReal
+	case "float8":
+		typ = types.TypeDoublePrecision
 		// TODO - use multi-phrase keyword
 	case "double":
 		if !p.advanceIf(isIdent("precision")) {
-			return types.TypeUnknown, fmt.Errorf("unknown type %q", "double")
+			return types.TypeUnknown, types.TypeModifier{}, fmt.Errorf("unknown type %q", "double")
 		}
 		typ = types.TypeDoublePrecision
-	case "numeric":
+	case "numeric", "decimal":
 		typ = types.TypeNumeric
-	case "boolean":
+		modifierFactory = newNumericModifier
+	case "boolean", "bool":
 		typ = types.TypeBool
+	case "varchar":
+		typ = types.TypeText
+		modifierFactory = newLengthModifier(types.NewVarcharModifier, false)
+	case "char":
+		typ = types.TypeCharacter
+		modifierFactory = newLengthModifier(types.NewCharModifier, true)
+		// TODO - use multi-phrase keyword
+	case "character":
+		typ = types.TypeText
+		if p.advanceIf(isIdent("varying")) {
+			modifierFactory = newLengthModifier(types.NewVarcharModifier, false)
+		} else {
+			typ = types.TypeCharacter
+			modifierFactory = newLengthModifier(types.NewCharModifier, true)
+		}
 		// TODO - use multi-phrase keyword(s)
 	case "timestamp":
-		if !p.advanceIf(isIdent("with"), isIdent("time"), isIdent("zone")) {
-			return types.TypeUnknown, fmt.Errorf("unknown type %q", "timestamp")
+		typ = types.TypeTimestamp
+		if p.advanceIf(isType(tokens.TokenTypeWith), isIdent("time"), isIdent("zone")) {
+			typ = types.TypeTimestampTz
+		} else {
+			p.advanceIf(isIdent("without"), isIdent("time"), isIdent("zone"))
 		}
+	case "timestamptz":
 		typ = types.TypeTimestampTz
+	case "time":
+		if p.advanceIf(isType(tokens.TokenTypeWith), isIdent("time"), isIdent("zone")) {
+			return types.TypeUnknown, types.TypeModifier{}, fmt.Errorf("unknown type %q", "time with time zone")
+		}
+		p.advanceIf(isIdent("without"), isIdent("time"), isIdent("zone"))
+		typ = types.TypeTime
+	case "date":
+		typ = types.TypeDate
+	case "interval":
+		typ = types.TypeInterval
+	case "uuid":
+		typ = types.TypeUUID
+	case "bytea":
+		typ = types.TypeBytea
+	case "jsonb":
+		typ = types.TypeJSONB
 	default:
-		return types.TypeUnknown, fmt.Errorf("unknown type %q", dataType)
+		return types.TypeUnknown, types.TypeModifier{}, fmt.Errorf("unknown type %q", dataType)
+	}
+
+	if modifierFactory == nil {
+		return typ, types.TypeModifier{}, nil
+	}
+
+	args, err := parseParenthesizedCommaSeparatedList(p, true, false, p.parseTypeModifierArgument)
+	if err != nil {
+		return types.TypeUnknown, types.TypeModifier{}, err
+	}
+
+	modifier, err := modifierFactory(args)
+	if err != nil {
+		return types.TypeUnknown, types.TypeModifier{}, err
+	}
+
+	return typ, modifier, nil
+}
+
+func (p *parser) parseTypeModifierArgument() (int, error) {
+	token, err := p.mustAdvance(isType(tokens.TokenTypeNumber))
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.Atoi(token.Text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid type modifier %q", token.Text)
+	}
+
+	return value, nil
+}
+
+// newLengthModifier returns a factory for modifiers with a single length argument. Types
+// without an explicit length are unbounded unless a default length of one is requested.
+func newLengthModifier(factory func(length int) (types.TypeModifier, error), defaultLength bool) func(args []int) (types.TypeModifier, error) {
+	return func(args []int) (types.TypeModifier, error) {
+		switch len(args) {
+		case 0:
+			if defaultLength {
+				return factory(1)
+			}
+
+			return types.TypeModifier{}, nil
+
+		case 1:
+			return factory(args[0])
+		}
+
+		return types.TypeModifier{}, fmt.Errorf("invalid type modifier")
+	}
+}
+
+// newNumericModifier creates a modifier from numeric's optional precision and scale
+// arguments. The scale defaults to zero.
+func newNumericModifier(args []int) (types.TypeModifier, error) {
+	switch len(args) {
+	case 0:
+		return types.TypeModifier{}, nil
+	case 1:
+		return types.NewNumericModifier(args[0], 0)
+	case 2:
+		return types.NewNumericModifier(args[0], args[1])
 	}
 
-	return typ, nil
+	return types.TypeModifier{}, fmt.Errorf("invalid NUMERIC type modifier")
 }
@@ -0,0 +1,31 @@
+package parsing
+
+import (
+	"github.com/efritz/gostgres/internal/execution/queries/utility"
+	"github.com/efritz/gostgres/internal/syntax/tokens"
+)
+
+// checkpointTail := ε
+func (p *parser) parseCheckpoint() (Query, error) {
+	return utility.NewCheckpoint(), nil
+}
+
+// analyzeTail := [ ident ]
+func (p *parser) parseAnalyze() (Query, error) {
+	tableName := ""
+	if p.current().Type == tokens.TokenTypeIdent {
+		tableName = p.advance().Text
+	}
+
+	return utility.NewAnalyze(tableName), nil
+}
+
+// vacuumTail := [ ident ]
+func (p *parser) parseVacuum() (Query, error) {
+	tableName := ""
+	if p.current().Type == tokens.TokenTypeIdent {
+		tableName = p.advance().Text
+	}
+
+	return utility.NewVacuum(tableName), nil
+}
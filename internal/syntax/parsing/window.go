@@ -0,0 +1,208 @@
+package parsing
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/shared/impls"
+	"github.com/efritz/gostgres/internal/syntax/ast"
+	"github.com/efritz/gostgres/internal/syntax/tokens"
+)
+
+// windowClause := [ `WINDOW` ident `AS` `(` windowSpecification `)` [, ...] ]
+func (p *parser) parseWindowClause() ([]ast.NamedWindow, error) {
+	if !p.advanceIf(isType(tokens.TokenTypeWindow)) {
+		return nil, nil
+	}
+
+	return parseCommaSeparatedList(p, func() (ast.NamedWindow, error) {
+		name, err := p.parseIdent()
+		if err != nil {
+			return ast.NamedWindow{}, err
+		}
+
+		if _, err := p.mustAdvance(isType(tokens.TokenTypeAs)); err != nil {
+			return ast.NamedWindow{}, err
+		}
+
+		window, err := parseParenthesized(p, p.parseWindowSpecification)
+		if err != nil {
+			return ast.NamedWindow{}, err
+		}
+
+		return ast.NamedWindow{Name: name, Window: window}, nil
+	})
+}
+
+// over := [ `OVER` ( ident | ( `(` windowSpecification `)` ) ) ]
+func (p *parser) parseOver() (impls.Window, bool, error) {
+	// OVER is not a reserved word
+	if !p.advanceIf(isIdent("over")) {
+		return impls.Window{}, false, nil
+	}
+
+	if p.current().Type == tokens.TokenTypeIdent {
+		name, err := p.parseIdent()
+		if err != nil {
+			return impls.Window{}, false, err
+		}
+
+		return impls.Window{Name: name}, true, nil
+	}
+
+	window, err := parseParenthesized(p, p.parseWindowSpecification)
+	if err != nil {
+		return impls.Window{}, false, err
+	}
+
+	return window, true, nil
+}
+
+// windowSpecification := [ ident ] [ `PARTITION BY` expression [, ...] ] orderBy [ frame ]
+func (p *parser) parseWindowSpecification() (impls.Window, error) {
+	var window impls.Window
+
+	// PARTITION, RANGE, ROWS, and GROUPS are not reserved words
+	if current := p.current(); current.Type == tokens.TokenTypeIdent && !isIdent("partition")(current) && !isFrameMode(current) {
+		window.Name = p.advance().Text
+	}
+
+	if p.advanceIf(isIdent("partition"), isType(tokens.TokenTypeBy)) {
+		partitions, err := parseCommaSeparatedList(p, p.parseRootExpression)
+		if err != nil {
+			return impls.Window{}, err
+		}
+
+		window.Partitions = partitions
+	}
+
+	order, _, err := p.parseOrderBy()
+	if err != nil {
+		return impls.Window{}, err
+	}
+	window.Order = order
+
+	if isFrameMode(p.current()) {
+		frame, err := p.parseFrame()
+		if err != nil {
+			return impls.Window{}, err
+		}
+
+		window.Frame = frame
+	}
+
+	return window, nil
+}
+
+func isFrameMode(token tokens.Token) bool {
+	return isIdent("range")(token) || isIdent("rows")(token) || isIdent("groups")(token)
+}
+
+// frame := ( `RANGE` | `ROWS` | `GROUPS` ) ( frameBound | ( `BETWEEN` frameBound `AND` frameBound ) ) [ frameExclusion ]
+func (p *parser) parseFrame() (*impls.WindowFrame, error) {
+	var mode impls.WindowFrameMode
+	switch {
+	case p.advanceIf(isIdent("range")):
+		mode = impls.WindowFrameModeRange
+	case p.advanceIf(isIdent("rows")):
+		mode = impls.WindowFrameModeRows
+	case p.advanceIf(isIdent("groups")):
+		mode = impls.WindowFrameModeGroups
+	default:
+		return nil, fmt.Errorf("expected RANGE, ROWS, or GROUPS (near %s)", p.current().Text)
+	}
+
+	if !p.advanceIf(isType(tokens.TokenTypeBetween)) {
+		start, err := p.parseFrameBound()
+		if err != nil {
+			return nil, err
+		}
+
+		exclusion, err := p.parseFrameExclusion()
+		if err != nil {
+			return nil, err
+		}
+
+		// A frame with only a start bound ends with the current row
+		return &impls.WindowFrame{
+			Mode:      mode,
+			Start:     start,
+			End:       impls.WindowFrameBound{Type: impls.WindowFrameBoundCurrentRow},
+			Exclusion: exclusion,
+		}, nil
+	}
+
+	start, err := p.parseFrameBound()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.mustAdvance(isType(tokens.TokenTypeAnd)); err != nil {
+		return nil, err
+	}
+
+	end, err := p.parseFrameBound()
+	if err != nil {
+		return nil, err
+	}
+
+	exclusion, err := p.parseFrameExclusion()
+	if err != nil {
+		return nil, err
+	}
+
+	return &impls.WindowFrame{
+		Mode:      mode,
+		Start:     start,
+		End:       end,
+		Exclusion: exclusion,
+	}, nil
+}
+
+// frameBound := ( `UNBOUNDED` ( `PRECEDING` | `FOLLOWING` ) ) | ( `CURRENT` `ROW` ) | ( expression ( `PRECEDING` | `FOLLOWING` ) )
+func (p *parser) parseFrameBound() (impls.WindowFrameBound, error) {
+	// UNBOUNDED, CURRENT, PRECEDING, and FOLLOWING are not reserved words
+	if p.advanceIf(isIdent("unbounded"), isIdent("preceding")) {
+		return impls.WindowFrameBound{Type: impls.WindowFrameBoundUnboundedPreceding}, nil
+	}
+	if p.advanceIf(isIdent("unbounded"), isIdent("following")) {
+		return impls.WindowFrameBound{Type: impls.WindowFrameBoundUnboundedFollowing}, nil
+	}
+	if p.advanceIf(isIdent("current"), isType(tokens.TokenTypeRow)) {
+		return impls.WindowFrameBound{Type: impls.WindowFrameBoundCurrentRow}, nil
+	}
+
+	offset, err := p.parseRootExpression()
+	if err != nil {
+		return impls.WindowFrameBound{}, err
+	}
+
+	if p.advanceIf(isIdent("preceding")) {
+		return impls.WindowFrameBound{Type: impls.WindowFrameBoundPreceding, Offset: offset}, nil
+	}
+	if p.advanceIf(isIdent("following")) {
+		return impls.WindowFrameBound{Type: impls.WindowFrameBoundFollowing, Offset: offset}, nil
+	}
+
+	return impls.WindowFrameBound{}, fmt.Errorf("expected PRECEDING or FOLLOWING (near %s)", p.current().Text)
+}
+
+// frameExclusion := `EXCLUDE` ( ( `CURRENT` `ROW` ) | `GROUP` | `TIES` | ( `NO` `OTHERS` ) )
+func (p *parser) parseFrameExclusion() (impls.WindowFrameExclusion, error) {
+	// EXCLUDE, TIES, NO, and OTHERS are not reserved words
+	if !p.advanceIf(isIdent("exclude")) {
+		return impls.WindowFrameExcludeNoOthers, nil
+	}
+
+	switch {
+	case p.advanceIf(isIdent("current"), isType(tokens.TokenTypeRow)):
+		return impls.WindowFrameExcludeCurrentRow, nil
+	case p.advanceIf(isType(tokens.TokenTypeGroup)):
+		return impls.WindowFrameExcludeGroup, nil
+	case p.advanceIf(isIdent("ties")):
+		return impls.WindowFrameExcludeTies, nil
+	case p.advanceIf(isIdent("no"), isIdent("others")):
+		return impls.WindowFrameExcludeNoOthers, nil
+	}
+
+	return 0, fmt.Errorf("expected CURRENT ROW, GROUP, TIES, or NO OTHERS (near %s)", p.current().Text)
+}
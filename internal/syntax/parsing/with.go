@@ -0,0 +1,80 @@
+package parsing
+
+import (
+	"fmt"
+
+	"github.com/efritz/gostgres/internal/syntax/ast"
+	"github.com/efritz/gostgres/internal/syntax/tokens"
+)
+
+// withTail := [ `RECURSIVE` ] commonTableExpression [, ...] explainableStatement
+func (p *parser) parseWith(_ tokens.Token) (ast.BuilderResolver, error) {
+	// RECURSIVE is not a reserved word
+	recursive := p.advanceIf(isIdent("recursive"))
+
+	commonTables, err := parseCommaSeparatedList(p, p.parseCommonTableExpression)
+	if err != nil {
+		return nil, err
+	}
+
+	for tokenType, parser := range p.explainableParsers {
+		if tokenType == tokens.TokenTypeWith {
+			continue
+		}
+
+		token := p.current()
+		if p.advanceIf(isType(tokenType)) {
+			statement, err := parser(token)
+			if err != nil {
+				return nil, err
+			}
+
+			return &ast.WithBuilder{
+				CommonTables: commonTables,
+				Recursive:    recursive,
+				Statement:    statement,
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("expected SELECT, INSERT, UPDATE, or DELETE (near %s)", p.current().Text)
+}
+
+// commonTableExpression := ident [ `(` ident [, ...] `)` ] `AS` [ [ `NOT` ] `MATERIALIZED` ] `(` selectOrValues `)`
+func (p *parser) parseCommonTableExpression() (*ast.CommonTableExpression, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	columnNames, err := parseParenthesizedCommaSeparatedList(p, true, false, p.parseIdent)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.mustAdvance(isType(tokens.TokenTypeAs)); err != nil {
+		return nil, err
+	}
+
+	// MATERIALIZED is not a reserved word
+	var materialized *bool
+	if p.advanceIf(isIdent("materialized")) {
+		value := true
+		materialized = &value
+	} else if p.advanceIf(isType(tokens.TokenTypeNot), isIdent("materialized")) {
+		value := false
+		materialized = &value
+	}
+
+	query, err := parseParenthesized(p, p.parseSelectOrValues)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.CommonTableExpression{
+		Name:         name,
+		ColumnNames:  columnNames,
+		Query:        query,
+		Materialized: materialized,
+	}, nil
+}
@@ -10,33 +10,54 @@ const (
 	TokenTypeIdent
 	TokenTypeNumber
 	TokenTypeString
+	TokenTypeParameter
 
 	//
 	// Keywords
 
+	TokenTypeAbort
+	TokenTypeAccess
 	TokenTypeAdd
 	TokenTypeAll
 	TokenTypeAlter
 	TokenTypeAnd
+	TokenTypeAny
+	TokenTypeArray
 	TokenTypeAs
 	TokenTypeAscending
+	TokenTypeBegin
 	TokenTypeBetween
 	TokenTypeBy
+	TokenTypeCase
+	TokenTypeCast
 	TokenTypeCheck
+	TokenTypeCommit
 	TokenTypeConstraint
 	TokenTypeCreate
+	TokenTypeCross
+	TokenTypeDeallocate
 	TokenTypeDefault
 	TokenTypeDelete
 	TokenTypeDescending
 	TokenTypeDistinct
+	TokenTypeElse
+	TokenTypeEnd
 	TokenTypeExcept
+	TokenTypeExclusive
+	TokenTypeExecute
+	TokenTypeExists
 	TokenTypeExplain
 	TokenTypeFalse
+	TokenTypeFor
 	TokenTypeForeign
 	TokenTypeFrom
+	TokenTypeFull
 	TokenTypeGroup
+	TokenTypeHaving
 	TokenTypeILike
+	TokenTypeIn
 	TokenTypeIndex
+	TokenTypeInner
 	TokenTypeInsert
 	TokenTypeIntersect
 	TokenTypeInto
@@ -44,29 +65,52 @@ const (
 	TokenTypeJoin
 	TokenTypeKey
 	TokenTypeKwUnknown
+	TokenTypeLeft
 	TokenTypeLike
 	TokenTypeLimit
+	TokenTypeLock
+	TokenTypeMode
+	TokenTypeNatural
 	TokenTypeNot
 	TokenTypeNull
+	TokenTypeOf
 	TokenTypeOffset
 	TokenTypeOn
 	TokenTypeOr
 	TokenTypeOrder
+	TokenTypeOuter
+	TokenTypePrepare
 	TokenTypePrimary
 	TokenTypeReferences
+	TokenTypeRelease
 	TokenTypeReturning
+	TokenTypeRollback
+	TokenTypeRight
+	TokenTypeRow
+	TokenTypeSavepoint
 	TokenTypeSelect
 	TokenTypeSequence
 	TokenTypeSet
+	TokenTypeShare
+	TokenTypeSimilar
+	TokenTypeSome
+	TokenTypeStart
 	TokenTypeSymmetric
 	TokenTypeTable
+	TokenTypeThen
+	TokenTypeTo
+	TokenTypeTransaction
 	TokenTypeTrue
 	TokenTypeUnion
 	TokenTypeUnique
 	TokenTypeUpdate
 	TokenTypeUsing
 	TokenTypeValues
+	TokenTypeWhen
 	TokenTypeWhere
+	TokenTypeWindow
+	TokenTypeWith
+	TokenTypeWork
 
 	//
 	// Single-character operators
@@ -83,6 +127,11 @@ const (
 	TokenTypeLessThan
 	TokenTypeEquals
 	TokenTypeGreaterThan
+	TokenTypeTilde
+	TokenTypeLeftBracket
+	TokenTypeRightBracket
+	TokenTypeColon
+	TokenTypeQuestion
 
 	//
 	// Multiple-character operators
@@ -91,6 +140,19 @@ const (
 	TokenTypeNotEquals
 	TokenTypeGreaterThanOrEqual
 	TokenTypeConcat
+	TokenTypeTildeAsterisk
+	TokenTypeNotTilde
+	TokenTypeNotTildeAsterisk
+	TokenTypeTypeCast
+	TokenTypeContains
+	TokenTypeContainedBy
+	TokenTypeOverlaps
+	TokenTypeArrow
+	TokenTypeDoubleArrow
+	TokenTypeHashArrow
+	TokenTypeHashDoubleArrow
+	TokenTypeQuestionPipe
+	TokenTypeQuestionAmpersand
 
 	//
 	// Multiple-keyword operators
@@ -110,9 +172,12 @@ const (
 	TokenTypeNotBetween
 	TokenTypeNotBetweenSymmetric
 	TokenTypeNotILike
+	TokenTypeNotIn
 	TokenTypeNotLike
 	TokenTypeNotNull
+	TokenTypeNotSimilarTo
 	TokenTypePrimaryKey
+	TokenTypeSimilarTo
 
 	TokenTypeUnknown
 )
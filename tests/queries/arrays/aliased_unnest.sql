@@ -0,0 +1,4 @@
+SELECT t, t || '!'
+FROM unnest('{a,NULL,b}'::text[]) AS t
+WHERE t IS NOT NULL
+ORDER BY t DESC;
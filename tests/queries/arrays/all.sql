@@ -0,0 +1,12 @@
+WITH posts (id, tags, scores, codes) AS (
+    VALUES
+        (1, '{go,sql}'::text[], ARRAY[3, 1, 2], '{ab}'::varchar(2)[]),
+        (2, ARRAY['sql', NULL], '{}'::integer[], NULL),
+        (3, NULL, NULL, ARRAY['x', 'y']::varchar(2)[])
+)
+SELECT
+    id,
+    0 < ALL(scores),
+    'sql' <> ALL(tags)
+FROM posts
+ORDER BY id;
@@ -0,0 +1,10 @@
+WITH posts (id, tags, scores, codes) AS (
+    VALUES
+        (1, '{go,sql}'::text[], ARRAY[3, 1, 2], '{ab}'::varchar(2)[]),
+        (2, ARRAY['sql', NULL], '{}'::integer[], NULL),
+        (3, NULL, NULL, ARRAY['x', 'y']::varchar(2)[])
+)
+SELECT
+    array_agg(id)::text,
+    array_agg(tags[1])::text
+FROM posts;
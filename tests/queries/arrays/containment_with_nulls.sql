@@ -0,0 +1,4 @@
+SELECT
+    ARRAY[1, NULL] @> ARRAY[NULL]::integer[],
+    ARRAY[1, NULL] && ARRAY[NULL, 1],
+    ARRAY[]::integer[] <@ ARRAY[1];
@@ -0,0 +1,5 @@
+SELECT
+    ARRAY[1, 2] = ARRAY[1, 2],
+    ARRAY[1, 2] < ARRAY[1, 3],
+    ARRAY[1] < ARRAY[1, 0],
+    '{a}'::text[] = ARRAY['a'];
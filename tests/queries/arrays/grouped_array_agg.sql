@@ -0,0 +1,16 @@
+WITH posts (id, tags, scores, codes) AS (
+    VALUES
+        (1, '{go,sql}'::text[], ARRAY[3, 1, 2], '{ab}'::varchar(2)[]),
+        (2, ARRAY['sql', NULL], '{}'::integer[], NULL),
+        (3, NULL, NULL, ARRAY['x', 'y']::varchar(2)[])
+)
+SELECT
+    tag,
+    array_agg(id)::text
+FROM (
+    SELECT id, tags[1] AS tag
+    FROM posts
+) AS p
+WHERE tag IS NOT NULL
+GROUP BY tag
+ORDER BY tag;
@@ -0,0 +1,6 @@
+SELECT
+    ARRAY[1, 2.5]::text,
+    ARRAY['a b', 'c"d', '']::text,
+    '{1,2}'::bigint[]::text,
+    ARRAY[]::integer[]::text,
+    ARRAY[NULL, 1]::text;
@@ -0,0 +1,14 @@
+WITH posts (id, tags, scores, codes) AS (
+    VALUES
+        (1, '{go,sql}'::text[], ARRAY[3, 1, 2], '{ab}'::varchar(2)[]),
+        (2, ARRAY['sql', NULL], '{}'::integer[], NULL),
+        (3, NULL, NULL, ARRAY['x', 'y']::varchar(2)[])
+)
+SELECT
+    scores[2:3]::text,
+    scores[:2]::text,
+    scores[2:]::text,
+    scores[0:9]::text,
+    scores[3:2]::text
+FROM posts
+WHERE id = 1;
@@ -0,0 +1,15 @@
+WITH posts (id, tags, scores, codes) AS (
+    VALUES
+        (1, '{go,sql}'::text[], ARRAY[3, 1, 2], '{ab}'::varchar(2)[]),
+        (2, ARRAY['sql', NULL], '{}'::integer[], NULL),
+        (3, NULL, NULL, ARRAY['x', 'y']::varchar(2)[])
+)
+SELECT
+    tags[1],
+    tags[2],
+    scores[3],
+    scores[0],
+    scores[4],
+    scores[id]
+FROM posts
+ORDER BY id;
@@ -0,0 +1,3 @@
+SELECT unnest
+FROM unnest(ARRAY[3, 1, 2])
+ORDER BY unnest;
@@ -0,0 +1,3 @@
+SELECT u.n
+FROM unnest(ARRAY[1, 2]) AS u (n)
+ORDER BY u.n;
@@ -0,0 +1,6 @@
+SELECT
+    'yes'::boolean,
+    'off'::bool,
+    0::boolean,
+    true::integer,
+    false::text;
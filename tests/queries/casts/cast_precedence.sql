@@ -0,0 +1,3 @@
+SELECT
+    1 + '2'::integer * 3,
+    (1 + 2)::text || '!';
@@ -0,0 +1,8 @@
+WITH events (id, label, at) AS (
+    VALUES
+        (1, 'first', '2024-01-01'::timestamptz),
+        (2, 'second', '2024-02-15 12:30:00+00'::timestamptz)
+)
+SELECT id::text || ':' || label
+FROM events
+WHERE id::text = '2';
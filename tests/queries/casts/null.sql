@@ -0,0 +1,3 @@
+SELECT
+    NULL::integer,
+    CAST(NULL AS text);
@@ -0,0 +1,4 @@
+SELECT
+    42::text,
+    CAST(2.5 AS text),
+    '1.25'::numeric::text;
@@ -0,0 +1,6 @@
+SELECT
+    2.5::integer,
+    3.5::int,
+    2.5::numeric::integer,
+    7::real,
+    7::numeric;
@@ -0,0 +1,5 @@
+SELECT
+    10 - 2 - 3,
+    12 / 2 / 3,
+    2 * 3 / 2,
+    'a' || 'b' || 'c';
@@ -0,0 +1,5 @@
+SELECT
+    '42'::integer,
+    '42'::int8,
+    CAST(' 2.5 ' AS double precision),
+    '7'::smallint;
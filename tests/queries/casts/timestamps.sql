@@ -0,0 +1,3 @@
+SELECT
+    '2024-02-01'::timestamptz::text,
+    CAST('2024-02-01 10:00:00+00' AS timestamp with time zone)::text;
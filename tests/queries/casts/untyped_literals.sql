@@ -0,0 +1,12 @@
+WITH events (id, at) AS (
+    VALUES
+        (1, '2024-01-01 00:00:00+00'::timestamptz),
+        (2, '2024-02-15 12:30:00+00'::timestamptz)
+)
+SELECT
+    id,
+    at::date = '2024-01-01' AS first_day,
+    (at + '1 day')::text AS next_day
+FROM events
+WHERE at > '2023-06-01'
+ORDER BY id;
@@ -0,0 +1,11 @@
+WITH scores (id, name, score, bonus) AS (
+    VALUES
+        (1, 'alice', 90::smallint, 5::bigint),
+        (2, 'bob', 60::smallint, NULL),
+        (3, NULL, NULL, 7::bigint),
+        (4, 'dave', 0::smallint, 0::bigint)
+)
+SELECT
+    CASE WHEN count(*) > 3 THEN 'many' ELSE 'few' END,
+    sum(CASE WHEN score > 50 THEN 1 ELSE 0 END)
+FROM scores;
@@ -0,0 +1,12 @@
+WITH scores (id, name, score, bonus) AS (
+    VALUES
+        (1, 'alice', 90::smallint, 5::bigint),
+        (2, 'bob', 60::smallint, NULL),
+        (3, NULL, NULL, 7::bigint),
+        (4, 'dave', 0::smallint, 0::bigint)
+)
+SELECT
+    id,
+    CASE WHEN id < 3 THEN score ELSE bonus END
+FROM scores
+ORDER BY id;
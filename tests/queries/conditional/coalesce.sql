@@ -0,0 +1,13 @@
+WITH scores (id, name, score, bonus) AS (
+    VALUES
+        (1, 'alice', 90::smallint, 5::bigint),
+        (2, 'bob', 60::smallint, NULL),
+        (3, NULL, NULL, 7::bigint),
+        (4, 'dave', 0::smallint, 0::bigint)
+)
+SELECT
+    id,
+    coalesce(name, 'unknown'),
+    COALESCE(bonus, score, 0)
+FROM scores
+ORDER BY id;
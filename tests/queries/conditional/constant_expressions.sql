@@ -0,0 +1,5 @@
+SELECT
+    CASE WHEN 1 > 2 THEN 'a' WHEN 2 > 1 THEN 'b' END,
+    CASE 2 WHEN 1 THEN 'a' ELSE 'c' END,
+    coalesce(NULL, 2),
+    nullif(1, 1);
@@ -0,0 +1,18 @@
+WITH scores (id, name, score, bonus) AS (
+    VALUES
+        (1, 'alice', 90::smallint, 5::bigint),
+        (2, 'bob', 60::smallint, NULL),
+        (3, NULL, NULL, 7::bigint),
+        (4, 'dave', 0::smallint, 0::bigint)
+)
+SELECT
+    CASE
+        WHEN false THEN 0
+        WHEN id > 1 THEN 1
+        WHEN true THEN 2
+        ELSE 3
+    END,
+    CASE WHEN 1 = 1 THEN name ELSE 'x' END,
+    coalesce(NULL, name, 'x', name)
+FROM scores
+ORDER BY id;
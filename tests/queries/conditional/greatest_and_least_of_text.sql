@@ -0,0 +1,4 @@
+SELECT
+    greatest('pear', 'apple', NULL),
+    least('pear', 'apple', NULL),
+    greatest(NULL, NULL);
@@ -0,0 +1,16 @@
+WITH scores (id, name, score, bonus) AS (
+    VALUES
+        (1, 'alice', 90::smallint, 5::bigint),
+        (2, 'bob', 60::smallint, NULL),
+        (3, NULL, NULL, 7::bigint),
+        (4, 'dave', 0::smallint, 0::bigint)
+)
+SELECT
+    id,
+    CASE
+        WHEN score >= 80 THEN 'high'
+        WHEN score >= 50 THEN 'medium'
+        ELSE 'low'
+    END
+FROM scores
+ORDER BY id;
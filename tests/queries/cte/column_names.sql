@@ -0,0 +1,17 @@
+WITH employees (id, name, manager_id) AS (
+    VALUES
+        (1, 'ada', NULL),
+        (2, 'bob', 1),
+        (3, 'cat', 1),
+        (4, 'dan', 2),
+        (5, 'eve', 4)
+),
+pairs (employee, manager) AS (
+    SELECT e.name, m.name
+    FROM employees e
+    JOIN employees m ON m.id = e.manager_id
+)
+SELECT employee
+FROM pairs
+WHERE manager = 'ada'
+ORDER BY employee;
@@ -0,0 +1,11 @@
+WITH employees (id, name, manager_id) AS (
+    VALUES
+        (1, 'ada', NULL),
+        (2, 'bob', 1),
+        (3, 'cat', 1),
+        (4, 'dan', 2),
+        (5, 'eve', 4)
+),
+m AS MATERIALIZED (SELECT id FROM employees)
+SELECT count(*)
+FROM m;
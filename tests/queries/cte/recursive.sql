@@ -0,0 +1,7 @@
+WITH RECURSIVE t (n) AS (
+    SELECT 1
+    UNION ALL
+    SELECT n + 1 FROM t WHERE n < 5
+)
+SELECT sum(n)
+FROM t;
@@ -0,0 +1,20 @@
+WITH RECURSIVE employees (id, name, manager_id) AS (
+    VALUES
+        (1, 'ada', NULL),
+        (2, 'bob', 1),
+        (3, 'cat', 1),
+        (4, 'dan', 2),
+        (5, 'eve', 4)
+),
+reports (id, name, depth) AS (
+    SELECT id, name, 0
+    FROM employees
+    WHERE manager_id IS NULL
+    UNION ALL
+    SELECT e.id, e.name, r.depth + 1
+    FROM employees e
+    JOIN reports r ON e.manager_id = r.id
+)
+SELECT name, depth
+FROM reports
+ORDER BY depth, name;
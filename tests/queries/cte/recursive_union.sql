@@ -0,0 +1,8 @@
+WITH RECURSIVE t (n) AS (
+    SELECT 0
+    UNION
+    SELECT (n + 1) / 4 FROM t
+)
+SELECT n
+FROM t
+ORDER BY n;
@@ -0,0 +1,7 @@
+WITH RECURSIVE t (n) AS (
+    SELECT 1
+    UNION
+    SELECT 1
+)
+SELECT n
+FROM t;
@@ -0,0 +1,13 @@
+WITH employees (id, name, manager_id) AS (
+    VALUES
+        (1, 'ada', NULL),
+        (2, 'bob', 1),
+        (3, 'cat', 1),
+        (4, 'dan', 2),
+        (5, 'eve', 4)
+),
+bosses AS (SELECT id FROM employees WHERE manager_id IS NULL)
+SELECT name
+FROM employees
+WHERE manager_id IN (SELECT id FROM bosses)
+ORDER BY name;
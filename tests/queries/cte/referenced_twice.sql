@@ -0,0 +1,14 @@
+WITH employees (id, name, manager_id) AS (
+    VALUES
+        (1, 'ada', NULL),
+        (2, 'bob', 1),
+        (3, 'cat', 1),
+        (4, 'dan', 2),
+        (5, 'eve', 4)
+),
+e AS (SELECT id, manager_id FROM employees)
+SELECT e1.id, e2.id
+FROM e e1
+JOIN e e2 ON e2.manager_id = e1.id
+WHERE e1.id > 1
+ORDER BY e1.id;
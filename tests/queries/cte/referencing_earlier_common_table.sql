@@ -0,0 +1,13 @@
+WITH employees (id, name, manager_id) AS (
+    VALUES
+        (1, 'ada', NULL),
+        (2, 'bob', 1),
+        (3, 'cat', 1),
+        (4, 'dan', 2),
+        (5, 'eve', 4)
+),
+a AS (SELECT id FROM employees WHERE id > 2),
+b AS (SELECT id FROM a WHERE id < 5)
+SELECT id
+FROM b
+ORDER BY id;
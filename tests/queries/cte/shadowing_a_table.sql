@@ -0,0 +1,3 @@
+WITH film AS (SELECT 1 AS id)
+SELECT id
+FROM film;
@@ -0,0 +1,17 @@
+WITH employees (id, name, manager_id) AS (
+    VALUES
+        (1, 'ada', NULL),
+        (2, 'bob', 1),
+        (3, 'cat', 1),
+        (4, 'dan', 2),
+        (5, 'eve', 4)
+),
+managers AS (
+    SELECT manager_id
+    FROM employees
+    GROUP BY manager_id
+)
+SELECT name
+FROM employees e
+JOIN managers m ON m.manager_id = e.id
+ORDER BY name;
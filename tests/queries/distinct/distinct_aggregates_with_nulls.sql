@@ -0,0 +1,14 @@
+WITH employees (id, dept, salary) AS (
+    VALUES
+        (1, 'eng', 100),
+        (2, 'eng', NULL),
+        (3, 'eng', 200),
+        (4, 'ops', NULL),
+        (5, 'ops', 100),
+        (6, 'hr', NULL)
+)
+SELECT
+    count(DISTINCT salary),
+    sum(DISTINCT salary),
+    min(DISTINCT salary)
+FROM employees;
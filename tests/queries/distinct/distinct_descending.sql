@@ -0,0 +1,12 @@
+WITH employees (id, dept, salary) AS (
+    VALUES
+        (1, 'eng', 100),
+        (2, 'eng', 200),
+        (3, 'eng', 200),
+        (4, 'ops', 50),
+        (5, 'ops', 80),
+        (6, 'hr', 70)
+)
+SELECT DISTINCT salary
+FROM employees
+ORDER BY salary DESC;
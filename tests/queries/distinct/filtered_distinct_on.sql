@@ -0,0 +1,17 @@
+WITH employees (id, dept, salary) AS (
+    VALUES
+        (1, 'eng', 100),
+        (2, 'eng', 200),
+        (3, 'eng', 200),
+        (4, 'ops', 50),
+        (5, 'ops', 80),
+        (6, 'hr', 70)
+)
+SELECT dept, salary
+FROM (
+    SELECT DISTINCT ON (dept) dept, salary
+    FROM employees
+    ORDER BY dept, salary
+) s
+WHERE salary > 60
+ORDER BY dept;
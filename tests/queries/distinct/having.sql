@@ -0,0 +1,14 @@
+WITH employees (id, dept, salary) AS (
+    VALUES
+        (1, 'eng', 100),
+        (2, 'eng', 200),
+        (3, 'eng', 200),
+        (4, 'ops', 50),
+        (5, 'ops', 80),
+        (6, 'hr', 70)
+)
+SELECT dept, count(*)
+FROM employees
+GROUP BY dept
+HAVING count(*) > 1
+ORDER BY dept;
@@ -0,0 +1,3 @@
+SELECT count(*)
+FROM film f
+JOIN inventory i ON i.film_id = f.film_id;
@@ -0,0 +1,4 @@
+SELECT count(*)
+FROM film f
+JOIN inventory i ON i.film_id = f.film_id
+JOIN rental r ON r.inventory_id = i.inventory_id;
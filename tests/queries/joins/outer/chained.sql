@@ -0,0 +1,18 @@
+WITH
+    l (id, k, x) AS (
+        VALUES
+            (1, 1, 'l1'),
+            (2, 2, 'l2'),
+            (3, NULL, 'l3')
+    ),
+    r (id, k, y) AS (
+        VALUES
+            (1, 2, 'r2'),
+            (2, 3, 'r3'),
+            (3, NULL, 'r4')
+    )
+SELECT l.x, r.y, r2.y
+FROM l
+LEFT JOIN r ON l.k = r.k
+LEFT JOIN r r2 ON r2.k = r.k + 1
+ORDER BY l.x;
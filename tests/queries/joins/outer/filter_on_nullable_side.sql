@@ -0,0 +1,18 @@
+WITH
+    l (id, k, x) AS (
+        VALUES
+            (1, 1, 'l1'),
+            (2, 2, 'l2'),
+            (3, NULL, 'l3')
+    ),
+    r (id, k, y) AS (
+        VALUES
+            (1, 2, 'r2'),
+            (2, 3, 'r3'),
+            (3, NULL, 'r4')
+    )
+SELECT x, y
+FROM l
+LEFT JOIN r ON l.k = r.k
+WHERE r.y IS NULL
+ORDER BY x;
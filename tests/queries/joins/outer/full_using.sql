@@ -0,0 +1,17 @@
+WITH
+    l (id, k, x) AS (
+        VALUES
+            (1, 1, 'l1'),
+            (2, 2, 'l2'),
+            (3, NULL, 'l3')
+    ),
+    r (id, k, y) AS (
+        VALUES
+            (1, 2, 'r2'),
+            (2, 3, 'r3'),
+            (3, NULL, 'r4')
+    )
+SELECT k, x, y
+FROM l
+FULL JOIN r USING (k)
+ORDER BY x, y;
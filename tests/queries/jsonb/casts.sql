@@ -0,0 +1,14 @@
+WITH events (id, payload) AS (
+    VALUES
+        (1, '{"type": "click", "user": {"id": 7, "name": "ada"}, "tags": ["a", "b"], "type": "view"}'::jsonb),
+        (2, '[1, "two", null, {"k": 3}]'::jsonb),
+        (3, NULL),
+        (4, '"a"'::jsonb)
+)
+SELECT
+    (payload #> '{user,id}'::text[])::integer + 1,
+    ('{"n": 2.5}'::jsonb->'n')::double precision,
+    ('true'::jsonb)::bool,
+    'null'::jsonb::integer
+FROM events
+WHERE id = 1;
@@ -0,0 +1,15 @@
+WITH events (id, payload) AS (
+    VALUES
+        (1, '{"type": "click", "user": {"id": 7, "name": "ada"}, "tags": ["a", "b"], "type": "view"}'::jsonb),
+        (2, '[1, "two", null, {"k": 3}]'::jsonb),
+        (3, NULL),
+        (4, '"a"'::jsonb)
+)
+SELECT
+    id,
+    payload @> '{"user": {"id": 7}}'::jsonb,
+    payload @> '{"tags": ["b"]}'::jsonb,
+    payload @> '[{"k": 3.0}]'::jsonb,
+    '{"tags": []}'::jsonb <@ payload
+FROM events
+ORDER BY id;
@@ -0,0 +1,12 @@
+WITH events (id, payload) AS (
+    VALUES
+        (1, '{"type": "click", "user": {"id": 7, "name": "ada"}, "tags": ["a", "b"], "type": "view"}'::jsonb),
+        (2, '[1, "two", null, {"k": 3}]'::jsonb),
+        (3, NULL),
+        (4, '"a"'::jsonb)
+)
+SELECT
+    id,
+    (SELECT count(*) FROM jsonb_array_elements(events.payload->'tags') AS t)
+FROM events
+WHERE id = 1;
@@ -0,0 +1,4 @@
+SELECT
+    '{"a": 1, "b": 2}'::jsonb = '{"b":2,"a":1}'::jsonb,
+    '[1, 2]'::jsonb = '[2, 1]'::jsonb,
+    '1.0'::jsonb = '1'::jsonb;
@@ -0,0 +1,16 @@
+WITH events (id, payload) AS (
+    VALUES
+        (1, '{"type": "click", "user": {"id": 7, "name": "ada"}, "tags": ["a", "b"], "type": "view"}'::jsonb),
+        (2, '[1, "two", null, {"k": 3}]'::jsonb),
+        (3, NULL),
+        (4, '"a"'::jsonb)
+)
+SELECT
+    payload->'user',
+    payload->'user'->>'name',
+    payload->>'type',
+    payload->1,
+    payload->>1,
+    payload->'missing'
+FROM events
+ORDER BY id;
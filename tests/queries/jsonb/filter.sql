@@ -0,0 +1,11 @@
+WITH events (id, payload) AS (
+    VALUES
+        (1, '{"type": "click", "user": {"id": 7, "name": "ada"}, "tags": ["a", "b"], "type": "view"}'::jsonb),
+        (2, '[1, "two", null, {"k": 3}]'::jsonb),
+        (3, NULL),
+        (4, '"a"'::jsonb)
+)
+SELECT id
+FROM events
+WHERE payload->'user'->>'name' = 'ada' OR payload ? 'a'
+ORDER BY id;
@@ -0,0 +1,14 @@
+WITH events (id, payload) AS (
+    VALUES
+        (1, '{"type": "click", "user": {"id": 7, "name": "ada"}, "tags": ["a", "b"], "type": "view"}'::jsonb),
+        (2, '[1, "two", null, {"k": 3}]'::jsonb),
+        (3, NULL),
+        (4, '"a"'::jsonb)
+)
+SELECT kind, jsonb_agg(id)
+FROM (
+    SELECT id, payload->>'type' AS kind
+    FROM events
+) AS e
+WHERE kind IS NOT NULL
+GROUP BY kind;
@@ -0,0 +1,2 @@
+SELECT e, e->>'k'
+FROM jsonb_array_elements('[1, "two", {"k": 3}]'::jsonb) AS e;
@@ -0,0 +1,10 @@
+WITH events (id, payload) AS (
+    VALUES
+        (1, '{"type": "click", "user": {"id": 7, "name": "ada"}, "tags": ["a", "b"], "type": "view"}'::jsonb),
+        (2, '[1, "two", null, {"k": 3}]'::jsonb),
+        (3, NULL),
+        (4, '"a"'::jsonb)
+)
+SELECT jsonb_build_object('id', id, 'tags', payload->'tags', 'none', NULL, 'list', ARRAY[1, 2], 1, 'one')
+FROM events
+WHERE id = 1;
@@ -0,0 +1,10 @@
+WITH events (id, payload) AS (
+    VALUES
+        (1, '{"type": "click", "user": {"id": 7, "name": "ada"}, "tags": ["a", "b"], "type": "view"}'::jsonb),
+        (2, '[1, "two", null, {"k": 3}]'::jsonb),
+        (3, NULL),
+        (4, '"a"'::jsonb)
+)
+SELECT k, v, v->>0
+FROM jsonb_each((SELECT payload FROM events WHERE id = 1)) AS e (k, v)
+ORDER BY k;
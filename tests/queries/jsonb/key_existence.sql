@@ -0,0 +1,17 @@
+WITH events (id, payload) AS (
+    VALUES
+        (1, '{"type": "click", "user": {"id": 7, "name": "ada"}, "tags": ["a", "b"], "type": "view"}'::jsonb),
+        (2, '[1, "two", null, {"k": 3}]'::jsonb),
+        (3, NULL),
+        (4, '"a"'::jsonb)
+)
+SELECT
+    id,
+    payload ? 'tags',
+    payload ? 'two',
+    payload ? 'a',
+    payload ?| ARRAY['x', 'user'],
+    payload ?& ARRAY['tags', 'user'],
+    payload ?& ARRAY['tags', 'x']
+FROM events
+ORDER BY id;
@@ -0,0 +1,15 @@
+WITH events (id, payload) AS (
+    VALUES
+        (1, '{"type": "click", "user": {"id": 7, "name": "ada"}, "tags": ["a", "b"], "type": "view"}'::jsonb),
+        (2, '[1, "two", null, {"k": 3}]'::jsonb),
+        (3, NULL),
+        (4, '"a"'::jsonb)
+)
+SELECT
+    payload #> '{user,id}'::text[],
+    payload #>> ARRAY['tags', '1'],
+    payload #>> ARRAY['3', 'k'],
+    payload #>> ARRAY['2']
+FROM events
+WHERE id < 3
+ORDER BY id;
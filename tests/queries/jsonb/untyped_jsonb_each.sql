@@ -0,0 +1,2 @@
+SELECT *
+FROM jsonb_each('{"b": 2, "a": null}');
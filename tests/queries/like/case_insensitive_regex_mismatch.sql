@@ -0,0 +1,14 @@
+WITH words (id, word) AS (
+    VALUES
+        (1, 'apple'),
+        (2, 'Apricot'),
+        (3, 'banana'),
+        (4, '50%'),
+        (5, 'a_b'),
+        (6, 'abc'),
+        (7, NULL)
+)
+SELECT id
+FROM words
+WHERE word !~* '^a'
+ORDER BY id;
@@ -0,0 +1,4 @@
+SELECT
+    'a\b' LIKE 'a\b' ESCAPE '',
+    'a%' LIKE 'a#%' ESCAPE '#',
+    'ab' LIKE 'a#%' ESCAPE '#';
@@ -0,0 +1,7 @@
+SELECT
+    'abc' LIKE 'abc',
+    'x.y' SIMILAR TO 'x.y',
+    'xzy' SIMILAR TO 'x.y',
+    'a
+b' LIKE 'a_b',
+    'ab' LIKE 'a' || '%';
@@ -0,0 +1,9 @@
+SELECT
+    NULL LIKE 'a',
+    'a' LIKE NULL,
+    NULL ILIKE 'a',
+    NULL NOT LIKE 'a',
+    NULL SIMILAR TO 'a',
+    NULL ~ 'a',
+    NULL ~* 'a',
+    'a' LIKE 'a' ESCAPE NULL;
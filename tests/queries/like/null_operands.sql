@@ -0,0 +1,17 @@
+WITH words (id, word) AS (
+    VALUES
+        (1, 'apple'),
+        (2, 'Apricot'),
+        (3, 'banana'),
+        (4, '50%'),
+        (5, 'a_b'),
+        (6, 'abc'),
+        (7, NULL)
+)
+SELECT
+    word LIKE 'a%',
+    word SIMILAR TO 'a%',
+    word ~ 'a',
+    'abc' LIKE word
+FROM words
+WHERE id = 7;
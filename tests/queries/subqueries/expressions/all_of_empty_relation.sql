@@ -0,0 +1,19 @@
+WITH
+    l (id, k, x) AS (
+        VALUES
+            (1, 1, 'l1'),
+            (2, 2, 'l2'),
+            (3, NULL, 'l3'),
+            (4, 3, 'l4')
+    ),
+    r (id, k, y) AS (
+        VALUES
+            (1, 2, 'r2'),
+            (2, 3, 'r3'),
+            (3, NULL, 'r4'),
+            (4, 3, 'r5')
+    ),
+    e AS (SELECT id, k FROM r WHERE id < 0)
+SELECT count(*)
+FROM l
+WHERE k > ALL (SELECT k FROM e);
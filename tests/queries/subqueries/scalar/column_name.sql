@@ -0,0 +1,9 @@
+WITH t (x) AS (
+    VALUES (1), (2), (3)
+)
+SELECT
+    (SELECT max(x) FROM t),
+    (SELECT x FROM t ORDER BY x LIMIT 1),
+    (SELECT x AS y FROM t WHERE x = 2),
+    (SELECT 1),
+    (SELECT max(x) FROM t) AS m;
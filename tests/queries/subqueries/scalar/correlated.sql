@@ -0,0 +1,18 @@
+WITH
+    customers (id, name) AS (
+        VALUES
+            (1, 'c1'),
+            (2, 'c2'),
+            (3, 'c3')
+    ),
+    payments (id, customer_id, amount) AS (
+        VALUES
+            (1, 1, 10),
+            (2, 1, 30),
+            (3, 2, 20)
+    )
+SELECT
+    name,
+    (SELECT max(amount) FROM payments p WHERE p.customer_id = c.id)
+FROM customers c
+ORDER BY name;
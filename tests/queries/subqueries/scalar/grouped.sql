@@ -0,0 +1,19 @@
+WITH
+    customers (id, name) AS (
+        VALUES
+            (1, 'c1'),
+            (2, 'c2'),
+            (3, 'c3')
+    ),
+    payments (id, customer_id, amount) AS (
+        VALUES
+            (1, 1, 10),
+            (2, 1, 30),
+            (3, 2, 20)
+    )
+SELECT
+    customer_id,
+    (SELECT name FROM customers c WHERE c.id = p.customer_id)
+FROM payments p
+GROUP BY customer_id
+ORDER BY customer_id;
@@ -0,0 +1,16 @@
+WITH
+    customers (id, name) AS (
+        VALUES
+            (1, 'c1'),
+            (2, 'c2'),
+            (3, 'c3')
+    ),
+    payments (id, customer_id, amount) AS (
+        VALUES
+            (1, 1, 10),
+            (2, 1, 30),
+            (3, 2, 20)
+    )
+SELECT name
+FROM customers c
+WHERE (SELECT count(*) FROM payments p WHERE p.customer_id = c.id) + 1 > 2;
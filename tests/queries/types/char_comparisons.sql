@@ -0,0 +1,5 @@
+SELECT
+    'ab'::char(3) = 'ab',
+    'ab'::char(3) = 'ab'::char(5),
+    'ab'::char(3) < 'ab!',
+    'ab '::text = 'ab'::char(3);
@@ -0,0 +1,5 @@
+SELECT
+    'ab'::char(3) || 'x',
+    'ab'::char(3)::text,
+    length('ab'::char(3)),
+    'ab'::char(3) LIKE 'ab';
@@ -0,0 +1,36 @@
+WITH things (id, code, name, price, born, opens, seen, seen_at, lasts, token, data) AS (
+    VALUES
+        (
+            1,
+            'ab'::char(4),
+            'first'::varchar(8),
+            12.345::numeric(6, 2),
+            '2024-01-31'::date,
+            '09:30'::time,
+            '2024-01-31 12:00:00'::timestamp,
+            '2024-01-31 12:00:00+00'::timestamptz,
+            '1 day 02:00:00'::interval,
+            'A0EEBC99-9C0B-4EF8-BB6D-6BB9BD380A11'::uuid,
+            '\x00ff'::bytea
+        ),
+        (
+            2,
+            'cd'::char(4),
+            'second'::varchar(8),
+            3::numeric(6, 2),
+            '2023-12-25'::date,
+            '17:45:30.5'::time,
+            '2023-12-25 08:15:00'::timestamp,
+            '2023-12-25 08:15:00+02'::timestamptz,
+            '3 hours'::interval,
+            '{a0eebc999c0b4ef8bb6d6bb9bd380a12}'::uuid,
+            'abc'::bytea
+        ),
+        (3, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL)
+)
+SELECT id
+FROM things
+WHERE
+    born >= date '2024-01-01' AND
+    born < timestamp '2024-02-01 00:00:00'
+ORDER BY id;
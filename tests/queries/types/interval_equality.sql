@@ -0,0 +1,4 @@
+SELECT
+    interval '1 month' = interval '30 days',
+    interval '1 day' = interval '24 hours',
+    interval '1 hour' < interval '59 minutes';
@@ -0,0 +1,6 @@
+SELECT
+    interval '1 year 2 mons 3 days 04:05:06.5'::text,
+    interval '1.5 days'::text,
+    interval '2 weeks ago'::text,
+    interval '90 minutes'::text,
+    interval '0'::text;
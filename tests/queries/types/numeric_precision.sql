@@ -0,0 +1,5 @@
+SELECT
+    2.5::numeric(1, 0)::text,
+    (0 - 2.5)::numeric(2)::text,
+    1.125::numeric(4, 2)::text,
+    '123.4567'::numeric(7, 3)::text;
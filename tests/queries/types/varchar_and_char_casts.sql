@@ -0,0 +1,6 @@
+SELECT
+    'abcdef'::varchar(3),
+    'ab'::char(4) || '|',
+    'ab'::character varying,
+    'abc'::character,
+    CAST('xyz' AS char(2));
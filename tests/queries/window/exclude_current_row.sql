@@ -0,0 +1,16 @@
+WITH employees (id, dept, salary) AS (
+    VALUES
+        (1, 'eng', 100),
+        (2, 'eng', 200),
+        (3, 'eng', 200),
+        (4, 'ops', 50),
+        (5, 'ops', 80),
+        (6, 'hr', 70)
+)
+SELECT
+    id,
+    sum(salary) OVER w,
+    first_value(id) OVER w
+FROM employees
+WINDOW w AS (ORDER BY salary, dept ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING EXCLUDE CURRENT ROW)
+ORDER BY id;
@@ -0,0 +1,18 @@
+WITH employees (id, dept, salary) AS (
+    VALUES
+        (1, 'eng', 100),
+        (2, 'eng', 200),
+        (3, 'eng', 200),
+        (4, 'ops', 50),
+        (5, 'ops', 80),
+        (6, 'hr', 70)
+)
+SELECT id
+FROM (
+    SELECT
+        id,
+        rank() OVER (PARTITION BY dept ORDER BY salary DESC) AS r
+    FROM employees
+) s
+WHERE r = 1
+ORDER BY id;
@@ -0,0 +1,18 @@
+WITH employees (id, dept, salary) AS (
+    VALUES
+        (1, 'eng', 100),
+        (2, 'eng', 200),
+        (3, 'eng', 200),
+        (4, 'ops', 50),
+        (5, 'ops', 80),
+        (6, 'hr', 70)
+)
+SELECT
+    dept,
+    sum(salary) AS total,
+    rank() OVER (ORDER BY sum(salary) DESC),
+    sum(sum(salary)) OVER ()
+FROM employees
+GROUP BY dept
+HAVING count(*) > 1
+ORDER BY dept;
@@ -0,0 +1,15 @@
+WITH employees (id, dept, salary) AS (
+    VALUES
+        (1, 'eng', 100),
+        (2, 'eng', 200),
+        (3, 'eng', 200),
+        (4, 'ops', 50),
+        (5, 'ops', 80),
+        (6, 'hr', 70)
+)
+SELECT
+    id,
+    lag(id) OVER (ORDER BY id),
+    lead(id, 2, 0) OVER (ORDER BY id)
+FROM employees
+ORDER BY id;
@@ -0,0 +1,15 @@
+WITH employees (id, dept, salary) AS (
+    VALUES
+        (1, 'eng', NULL),
+        (2, 'eng', 200),
+        (3, 'eng', NULL),
+        (4, 'ops', 50),
+        (5, 'ops', NULL),
+        (6, 'hr', NULL)
+)
+SELECT
+    id,
+    sum(salary) OVER (ORDER BY id),
+    sum(salary) OVER (PARTITION BY dept)
+FROM employees
+ORDER BY id;
@@ -0,0 +1,16 @@
+WITH employees (id, dept, salary) AS (
+    VALUES
+        (1, 'eng', 100),
+        (2, 'eng', 200),
+        (3, 'eng', 200),
+        (4, 'ops', 50),
+        (5, 'ops', 80),
+        (6, 'hr', 70)
+)
+SELECT
+    id,
+    rank() OVER w,
+    dense_rank() OVER w
+FROM employees
+WINDOW w AS (PARTITION BY dept ORDER BY salary DESC)
+ORDER BY id;